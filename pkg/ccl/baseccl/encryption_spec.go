@@ -11,12 +11,14 @@ package baseccl
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/cliccl/cliflagsccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
 	"github.com/spf13/pflag"
@@ -35,6 +37,10 @@ type StoreEncryptionSpec struct {
 	KeyPath        string
 	OldKeyPath     string
 	RotationPeriod time.Duration
+	// KMSURI and OldKMSURI are optional. If set, the key file at KeyPath
+	// (respectively OldKeyPath) is wrapped by the KMS key referenced by the URI.
+	KMSURI    string
+	OldKMSURI string
 }
 
 // ToEncryptionOptions convert to a serialized EncryptionOptions protobuf.
@@ -42,8 +48,10 @@ func (es StoreEncryptionSpec) ToEncryptionOptions() ([]byte, error) {
 	opts := EncryptionOptions{
 		KeySource: EncryptionKeySource_KeyFiles,
 		KeyFiles: &EncryptionKeyFiles{
-			CurrentKey: es.KeyPath,
-			OldKey:     es.OldKeyPath,
+			CurrentKey:       es.KeyPath,
+			OldKey:           es.OldKeyPath,
+			CurrentKeyKmsUri: es.KMSURI,
+			OldKeyKmsUri:     es.OldKMSURI,
 		},
 		DataKeyRotationPeriod: int64(es.RotationPeriod / time.Second),
 	}
@@ -51,11 +59,30 @@ func (es StoreEncryptionSpec) ToEncryptionOptions() ([]byte, error) {
 	return protoutil.Marshal(&opts)
 }
 
-// String returns a fully parsable version of the encryption spec.
+// String returns a fully parsable version of the encryption spec. The KMS
+// URIs are redacted, since they may contain credentials and the result is
+// logged.
 func (es StoreEncryptionSpec) String() string {
-	// All fields are set.
-	return fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
+	// All required fields are set.
+	s := fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
 		es.Path, es.KeyPath, es.OldKeyPath, es.RotationPeriod)
+	if es.KMSURI != "" {
+		s += ",kms=" + redactKMSURI(es.KMSURI)
+	}
+	if es.OldKMSURI != "" {
+		s += ",old-kms=" + redactKMSURI(es.OldKMSURI)
+	}
+	return s
+}
+
+// redactKMSURI redacts the key identifier and the credentials in the given KMS
+// URI. If the URI cannot be parsed, it is redacted entirely.
+func redactKMSURI(uri string) string {
+	redacted, err := cloud.RedactKMSURI(uri)
+	if err != nil {
+		return "redacted"
+	}
+	return redacted
 }
 
 // NewStoreEncryptionSpec parses the string passed in and returns a new
@@ -116,6 +143,15 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 					return StoreEncryptionSpec{}, err
 				}
 			}
+		case "kms", "old-kms":
+			if _, err := url.ParseRequestURI(value); err != nil {
+				return StoreEncryptionSpec{}, errors.Wrapf(err, "could not parse %s URI", field)
+			}
+			if field == "kms" {
+				es.KMSURI = value
+			} else {
+				es.OldKMSURI = value
+			}
 		case "rotation-period":
 			var err error
 			es.RotationPeriod, err = time.ParseDuration(value)
@@ -137,6 +173,12 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 	if es.OldKeyPath == "" {
 		return StoreEncryptionSpec{}, fmt.Errorf("no old-key specified")
 	}
+	if es.KMSURI != "" && es.KeyPath == plaintextFieldValue {
+		return StoreEncryptionSpec{}, fmt.Errorf("kms cannot be specified when key is %s", plaintextFieldValue)
+	}
+	if es.OldKMSURI != "" && es.OldKeyPath == plaintextFieldValue {
+		return StoreEncryptionSpec{}, fmt.Errorf("old-kms cannot be specified when old-key is %s", plaintextFieldValue)
+	}
 
 	return es, nil
 }
@@ -11,6 +11,7 @@ package baseccl
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,11 +46,19 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 		{"path=data,key=new.key,old-key=old.key,rotation-period=1", `could not parse rotation-duration value: 1: time: missing unit in duration "1"`, StoreEncryptionSpec{}},
 		{"path=data,key=new.key,old-key=old.key,rotation-period=1d", `could not parse rotation-duration value: 1d: time: unknown unit "d" in duration "1d"`, StoreEncryptionSpec{}},
 
+		// KMS.
+		{"path=data,key=new.key,old-key=old.key,kms=", "no value specified for kms", StoreEncryptionSpec{}},
+		{"path=data,key=new.key,old-key=old.key,kms=foo", `could not parse kms URI: parse "foo": invalid URI for request`, StoreEncryptionSpec{}},
+		{"path=data,key=plain,old-key=old.key,kms=aws-kms:///key", "kms cannot be specified when key is plain", StoreEncryptionSpec{}},
+		{"path=data,key=new.key,old-key=plain,old-kms=aws-kms:///key", "old-kms cannot be specified when old-key is plain", StoreEncryptionSpec{}},
+
 		// Good values.
 		{"path=/data,key=/new.key,old-key=/old.key", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod}},
 		{"path=/data,key=/new.key,old-key=/old.key,rotation-period=1h", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: time.Hour}},
 		{"path=/data,key=plain,old-key=/old.key,rotation-period=1h", "", StoreEncryptionSpec{Path: "/data", KeyPath: "plain", OldKeyPath: "/old.key", RotationPeriod: time.Hour}},
 		{"path=/data,key=/new.key,old-key=plain,rotation-period=1h", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "plain", RotationPeriod: time.Hour}},
+		{"path=/data,key=/new.key,old-key=plain,kms=aws-kms:///key?AUTH=implicit&REGION=us-east-1", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "plain", RotationPeriod: DefaultRotationPeriod, KMSURI: "aws-kms:///key?AUTH=implicit&REGION=us-east-1"}},
+		{"path=/data,key=/new.key,old-key=/old.key,kms=gs:///new,old-kms=gs:///old", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod, KMSURI: "gs:///new", OldKMSURI: "gs:///old"}},
 	}
 
 	for i, testCase := range testCases {
@@ -87,3 +96,27 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 		}
 	}
 }
+
+// TestStoreEncryptionSpecStringRedactsKMSURI verifies that the KMS URIs are
+// redacted in the string representation of a StoreEncryptionSpec.
+func TestStoreEncryptionSpecStringRedactsKMSURI(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	es := StoreEncryptionSpec{
+		Path:           "/data",
+		KeyPath:        "/new.key",
+		OldKeyPath:     "/old.key",
+		RotationPeriod: DefaultRotationPeriod,
+		KMSURI:         "aws-kms://user:secret@/new-key-id?REGION=us-east-1",
+		OldKMSURI:      "gs:///old-key-id",
+	}
+	s := es.String()
+	for _, sensitive := range []string{"secret", "new-key-id", "old-key-id"} {
+		if strings.Contains(s, sensitive) {
+			t.Errorf("expected %q to be redacted in %q", sensitive, s)
+		}
+	}
+	if _, err := NewStoreEncryptionSpec(s); err != nil {
+		t.Errorf("error parsing String() result: %s", err)
+	}
+}
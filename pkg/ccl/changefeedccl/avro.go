@@ -348,6 +348,21 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 				return tree.NewDRefCursor(x.(string)), nil
 			},
 		)
+	case types.RangeFamily, types.MultirangeFamily:
+		setNullable(
+			avroSchemaString,
+			func(d tree.Datum, _ interface{}) (interface{}, error) {
+				return tree.AsStringWithFlags(d, tree.FmtPgwireText), nil
+			},
+			func(x interface{}) (tree.Datum, error) {
+				if typ.Family() == types.RangeFamily {
+					r, _, err := tree.ParseDRangeFromString(nil /* ctx */, x.(string), typ)
+					return r, err
+				}
+				r, _, err := tree.ParseDMultirangeFromString(nil /* ctx */, x.(string), typ)
+				return r, err
+			},
+		)
 	case types.Box2DFamily:
 		setNullable(
 			avroSchemaString,
@@ -613,6 +628,16 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 				return tree.ParseDTSQuery(x.(string))
 			},
 		)
+	case types.JsonpathFamily:
+		setNullable(
+			avroSchemaString,
+			func(d tree.Datum, _ interface{}) (interface{}, error) {
+				return d.(*tree.DJsonpath).Path.String(), nil
+			},
+			func(x interface{}) (tree.Datum, error) {
+				return tree.ParseDJsonpath(x.(string))
+			},
+		)
 	case types.TSVectorFamily:
 		setNullable(
 			avroSchemaString,
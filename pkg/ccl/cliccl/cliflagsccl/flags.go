@@ -34,8 +34,15 @@ Valid fields:
 * key     (required): path to the current key file, or "plain"
 * old-key (required): path to the previous key file, or "plain"
 * rotation-period   : amount of time after which data keys should be rotated
+* kms               : URI of the KMS key wrapping the current key file
+* old-kms           : URI of the KMS key wrapping the previous key file
 
 </PRE>
+When kms (or old-kms) is specified, the corresponding key file must contain a
+key wrapped by that KMS key, as generated by 'cockroach gen encryption-key
+--kms'. The key is unwrapped using the KMS when the store is opened. KMS URIs
+should use implicit authentication, since the flag value may be logged.
+
 example:
 <PRE>
   --enterprise-encryption=path=cockroach-data,key=/keys/aes-128.key,old-key=plain</PRE>
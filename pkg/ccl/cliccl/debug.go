@@ -105,6 +105,20 @@ with their env type and encryption settings (if applicable).
 		RunE: clierrorplus.MaybeDecorateError(runList),
 	}
 
+	encryptionVerify := &cobra.Command{
+		Use:   "encryption-verify <directory>",
+		Short: "verify the integrity of the files of an encrypted store",
+		Long: `Reads every file of an Encryption At Rest store which uses authenticated
+encryption (AES-GCM), and reports the files which have been tampered with or
+corrupted. The store must not be in use by a running node.
+
+Files encrypted with a store key that does not use authenticated encryption
+cannot be verified and are skipped.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: clierrorplus.MaybeDecorateError(runVerify),
+	}
+
 	checkFipsCmd := &cobra.Command{
 		Use:   "enterprise-check-fips",
 		Short: "print diagnostics for FIPS-ready configuration",
@@ -122,6 +136,7 @@ mode in the current environment.
 	cli.DebugCmd.AddCommand(encryptionActiveKeyCmd)
 	cli.DebugCmd.AddCommand(encryptionDecryptCmd)
 	cli.DebugCmd.AddCommand(encryptionRegistryList)
+	cli.DebugCmd.AddCommand(encryptionVerify)
 	cli.DebugCmd.AddCommand(checkFipsCmd)
 
 	// Add the encryption flag to commands that need it.
@@ -137,6 +152,9 @@ mode in the current environment.
 	// For the encryption-registry-list command.
 	f = encryptionRegistryList.Flags()
 	cliflagcfg.VarFlag(f, &storeEncryptionSpecs, cliflagsccl.EnterpriseEncryption)
+	// For the encryption-verify command.
+	f = encryptionVerify.Flags()
+	cliflagcfg.VarFlag(f, &storeEncryptionSpecs, cliflagsccl.EnterpriseEncryption)
 
 	// Add encryption flag to all OSS debug commands that want it.
 	for _, cmd := range cli.DebugCommandsRequiringEncryption {
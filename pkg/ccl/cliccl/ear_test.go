@@ -13,20 +13,21 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
-	// The following import is required for the hook that populates
-	// NewEncryptedEnvFunc in `pkg/storage`.
-	_ "github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl"
 	"github.com/cockroachdb/cockroach/pkg/cli"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudtestutils"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/storageutils"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -166,6 +167,138 @@ func TestList(t *testing.T) {
 	})
 }
 
+func TestVerify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Generate a new encryption key which uses authenticated encryption.
+	keyPath := filepath.Join(dir, "aes.key")
+	require.NoError(t, genEncryptionKey(keyPath, 128, false /* overwrite */, 3 /* keyVersion */, "" /* kmsURI */))
+
+	// Spin up a new encrypted store, and write a key and flush, to create a
+	// table in the store.
+	encSpecStr := fmt.Sprintf("path=%s,key=%s,old-key=plain", dir, keyPath)
+	encSpec, err := baseccl.NewStoreEncryptionSpec(encSpecStr)
+	require.NoError(t, err)
+	encOpts, err := encSpec.ToEncryptionOptions()
+	require.NoError(t, err)
+	env, err := fs.InitEnv(ctx, vfs.Default, dir, fs.EnvConfig{EncryptionOptions: encOpts})
+	require.NoError(t, err)
+	p, err := storage.Open(ctx, env, cluster.MakeClusterSettings())
+	require.NoError(t, err)
+	require.NoError(t, p.PutUnversioned([]byte("foo"), []byte("bar")))
+	require.NoError(t, p.Flush())
+	files, err := p.List(dir)
+	require.NoError(t, err)
+	p.Close()
+
+	cmd := getTool(cli.DebugCmd, []string{"debug", "encryption-verify"})
+	require.NotNil(t, cmd)
+	require.NoError(t, cmd.Flags().Set("enterprise-encryption", encSpecStr))
+	verify := func() (string, error) {
+		var b bytes.Buffer
+		cmd.SetOut(&b)
+		cmd.SetErr(&b)
+		err := runVerify(cmd, []string{dir})
+		return b.String(), err
+	}
+
+	out, err := verify()
+	require.NoError(t, err)
+	require.Contains(t, out, " files verified, 0 files corrupted")
+
+	// Flip a bit in the middle of the sstable on disk.
+	var sstPath string
+	for _, basename := range files {
+		if strings.HasSuffix(basename, ".sst") {
+			sstPath = filepath.Join(dir, basename)
+			break
+		}
+	}
+	require.NotEmpty(t, sstPath)
+	b, err := os.ReadFile(sstPath)
+	require.NoError(t, err)
+	b[len(b)/2] ^= 0x01
+	require.NoError(t, os.WriteFile(sstPath, b, 0644))
+
+	out, err = verify()
+	require.ErrorContains(t, err, "found 1 corrupted files")
+	require.Contains(t, out, filepath.Base(sstPath)+": ")
+	require.Contains(t, out, "failed authentication")
+	require.Contains(t, out, "1 files corrupted")
+
+	// So is tampering with the end record of the sstable, which authenticates
+	// its size.
+	b[len(b)/2] ^= 0x01
+	b[len(b)-1] ^= 0x01
+	require.NoError(t, os.WriteFile(sstPath, b, 0644))
+	out, err = verify()
+	require.ErrorContains(t, err, "found 1 corrupted files")
+	require.Contains(t, out, filepath.Base(sstPath)+": ")
+	b[len(b)-1] ^= 0x01
+
+	// Dropping the end record of the sstable cannot be told apart from a crash
+	// while it was written, so it is only reported.
+	require.NoError(t, os.WriteFile(sstPath, b[:len(b)-32], 0644))
+	out, err = verify()
+	require.NoError(t, err)
+	require.Contains(t, out, filepath.Base(sstPath)+": not closed after being written")
+	require.Contains(t, out, "0 files corrupted, 1 files not closed")
+}
+
+func TestKMSWrappedStoreKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	kmsURI := cloudtestutils.MakeFileKMSURI(t, dir, "master.key")
+
+	// Generate a new encryption key wrapped by the KMS key. It cannot be used
+	// without unwrapping it first.
+	keyPath := filepath.Join(dir, "aes.key")
+	require.NoError(t, genEncryptionKey(keyPath, 256, false /* overwrite */, 1 /* keyVersion */, kmsURI))
+	_, err := engineccl.LoadKeyFromFile(vfs.Default, keyPath)
+	require.ErrorContains(t, err, "could not parse store key")
+
+	openStore := func(encSpecStr string) (*storage.Pebble, error) {
+		encSpec, err := baseccl.NewStoreEncryptionSpec(encSpecStr)
+		require.NoError(t, err)
+		encOpts, err := encSpec.ToEncryptionOptions()
+		require.NoError(t, err)
+		env, err := fs.InitEnv(ctx, vfs.Default, dir, fs.EnvConfig{EncryptionOptions: encOpts})
+		if err != nil {
+			return nil, err
+		}
+		return storage.Open(ctx, env, cluster.MakeClusterSettings())
+	}
+
+	// Spin up a new encrypted store using the wrapped key, and write a key.
+	encSpecStr := fmt.Sprintf("path=%s,key=%s,old-key=plain,kms=%s", dir, keyPath, kmsURI)
+	p, err := openStore(encSpecStr)
+	require.NoError(t, err)
+	require.NoError(t, p.PutUnversioned([]byte("foo"), []byte("bar")))
+	require.NoError(t, p.Flush())
+	p.Close()
+
+	// The store cannot be opened without the KMS.
+	_, err = openStore(fmt.Sprintf("path=%s,key=%s,old-key=plain", dir, keyPath))
+	require.ErrorContains(t, err, "could not parse store key")
+
+	// Rotate to a new store key wrapped by a new KMS key.
+	newKMSURI := cloudtestutils.MakeFileKMSURI(t, dir, "new-master.key")
+	newKeyPath := filepath.Join(dir, "aes-new.key")
+	require.NoError(t, genEncryptionKey(newKeyPath, 256, false /* overwrite */, 1 /* keyVersion */, newKMSURI))
+	p, err = openStore(fmt.Sprintf("path=%s,key=%s,old-key=%s,kms=%s,old-kms=%s",
+		dir, newKeyPath, keyPath, newKMSURI, kmsURI))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), storageutils.MVCCGetRaw(t, p, storageutils.PointKey("foo", 0)))
+	p.Close()
+}
+
 // getTool traverses the given cobra.Command recursively, searching for a tool
 // matching the given command.
 func getTool(cmd *cobra.Command, want []string) *cobra.Command {
@@ -9,12 +9,14 @@
 package cliccl
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
 	"github.com/cockroachdb/cockroach/pkg/cli"
 	"github.com/cockroachdb/errors"
@@ -25,9 +27,10 @@ import (
 var aesSizeFlag int
 var overwriteKeyFlag bool
 var keyVersionFlag int
+var kmsURIFlag string
 
 func genEncryptionKey(
-	encryptionKeyPath string, aesSize int, overwriteKey bool, keyVersion int,
+	encryptionKeyPath string, aesSize int, overwriteKey bool, keyVersion int, kmsURI string,
 ) error {
 	// Check encryptionKeySize is suitable for the encryption algorithm.
 	if aesSize != 128 && aesSize != 192 && aesSize != 256 {
@@ -48,7 +51,7 @@ func genEncryptionKey(
 	case 1:
 		b = append(b, keyID...)
 		b = append(b, key...)
-	case 2:
+	case 2, 3:
 		var et enginepbccl.EncryptionType
 		switch aesSize {
 		case 128:
@@ -61,6 +64,17 @@ func genEncryptionKey(
 			// Redundant since we checked this at the start of the function too.
 			return fmt.Errorf("store key size should be 128, 192, or 256 bits, got %d", aesSize)
 		}
+		if keyVersion == 3 {
+			// Version 3 keys use authenticated encryption.
+			switch aesSize {
+			case 128:
+				et = enginepbccl.EncryptionType_AES_128_GCM
+			case 192:
+				et = enginepbccl.EncryptionType_AES_192_GCM
+			case 256:
+				et = enginepbccl.EncryptionType_AES_256_GCM
+			}
+		}
 
 		symKey := jwk.NewSymmetricKey()
 		if err := symKey.FromRaw(key); err != nil {
@@ -88,6 +102,13 @@ func genEncryptionKey(
 		return fmt.Errorf("unsupported version %d", keyVersion)
 	}
 
+	if kmsURI != "" {
+		var err error
+		if b, err = engineccl.WrapStoreKey(context.Background(), kmsURI, b); err != nil {
+			return err
+		}
+	}
+
 	// Write key to the file with owner read/write permission.
 	openMode := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
 	if !overwriteKey {
@@ -113,13 +134,20 @@ var genEncryptionKeyCmd = &cobra.Command{
 	Long: `Generate store key for encryption at rest.
 
 Generates a key suitable for use as a store key for Encryption At Rest.
-The resulting key file will be 32 bytes (random key ID) + key_size in bytes.
+With --version=1, the resulting key file will be 32 bytes (random key ID) +
+key_size in bytes. With --version=2 or --version=3, the key is written in JWK
+format. Version 3 keys use AES-GCM, which detects tampering with or corruption
+of the encrypted files in addition to keeping them confidential.
+
+If --kms is specified, the key is wrapped by the KMS key referenced by the URI
+before being written to the key file. The same URI must then be passed in the
+kms (or old-kms) field of the --enterprise-encryption flag.
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		encryptionKeyPath := args[0]
 
-		err := genEncryptionKey(encryptionKeyPath, aesSizeFlag, overwriteKeyFlag, keyVersionFlag)
+		err := genEncryptionKey(encryptionKeyPath, aesSizeFlag, overwriteKeyFlag, keyVersionFlag, kmsURIFlag)
 
 		if err != nil {
 			return err
@@ -138,5 +166,7 @@ func init() {
 	genEncryptionKeyCmd.PersistentFlags().BoolVar(&overwriteKeyFlag, "overwrite", false,
 		"Overwrite key if it exists")
 	genEncryptionKeyCmd.PersistentFlags().IntVar(&keyVersionFlag, "version", 1,
-		"Encryption format version (1 or 2)")
+		"Encryption format version (1, 2, or 3)")
+	genEncryptionKeyCmd.PersistentFlags().StringVar(&kmsURIFlag, "kms", "",
+		"URI of a KMS key with which to wrap the generated key")
 }
@@ -28,13 +28,13 @@ func TestGenEncryptionKey(t *testing.T) {
 
 	dir := t.TempDir()
 
-	for _, keyVersion := range []int{1, 2} {
+	for _, keyVersion := range []int{1, 2, 3} {
 		for _, keySize := range []int{128, 192, 256} {
 			t.Run(fmt.Sprintf("version=%d/size=%d", keyVersion, keySize), func(t *testing.T) {
 				keyName := fmt.Sprintf("aes-%d-v%d.key", keySize, keyVersion)
 				keyPath := filepath.Join(dir, keyName)
 
-				err := genEncryptionKey(keyPath, keySize, false, keyVersion)
+				err := genEncryptionKey(keyPath, keySize, false, keyVersion, "" /* kmsURI */)
 				require.NoError(t, err)
 
 				if keyVersion == 1 {
@@ -50,10 +50,10 @@ func TestGenEncryptionKey(t *testing.T) {
 				// Key ID is hex encoded on load so it's 64 bytes here but 32 in the file size.
 				assert.EqualValues(t, 64, len(key.Info.KeyId))
 
-				err = genEncryptionKey(keyPath, keySize, false, keyVersion)
+				err = genEncryptionKey(keyPath, keySize, false, keyVersion, "" /* kmsURI */)
 				require.ErrorContains(t, err, fmt.Sprintf("%s: file exists", keyName))
 
-				err = genEncryptionKey(keyPath, keySize, true /* overwrite */, keyVersion)
+				err = genEncryptionKey(keyPath, keySize, true /* overwrite */, keyVersion, "" /* kmsURI */)
 				require.NoError(t, err)
 			})
 		}
@@ -0,0 +1,228 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logicalreplicationccl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// rowEvent is a change of a row of a published table, as emitted by a
+// changefeed with the wrapped envelope, the diff option and the
+// mvcc_timestamp option.
+type rowEvent struct {
+	// before is the JSON encoding of the row before the change, or nil if the
+	// row was inserted.
+	before []byte
+	// after is the JSON encoding of the row after the change, or nil if the row
+	// was deleted.
+	after []byte
+	// mvccTimestamp is the commit timestamp of the change on the publisher.
+	mvccTimestamp hlc.Timestamp
+}
+
+// changefeedMessage is the value of a message of a changefeed with the
+// wrapped envelope. Resolved messages only have the resolved field set.
+type changefeedMessage struct {
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	MVCCTimestamp string          `json:"mvcc_timestamp"`
+	Resolved      string          `json:"resolved"`
+}
+
+// parseChangefeedMessage parses the value of a changefeed message. It returns
+// either a row event or, for resolved messages, a resolved timestamp.
+func parseChangefeedMessage(value []byte) (_ rowEvent, resolved hlc.Timestamp, _ error) {
+	var msg changefeedMessage
+	if err := json.Unmarshal(value, &msg); err != nil {
+		return rowEvent{}, hlc.Timestamp{}, errors.Wrap(err, "decoding changefeed message")
+	}
+	if msg.Resolved != "" {
+		resolved, err := hlc.ParseHLC(msg.Resolved)
+		return rowEvent{}, resolved, errors.Wrap(err, "decoding resolved timestamp")
+	}
+	ts, err := hlc.ParseHLC(msg.MVCCTimestamp)
+	if err != nil {
+		return rowEvent{}, hlc.Timestamp{}, errors.Wrap(err, "decoding MVCC timestamp")
+	}
+	ev := rowEvent{mvccTimestamp: ts}
+	if !isJSONNull(msg.Before) {
+		ev.before = msg.Before
+	}
+	if !isJSONNull(msg.After) {
+		ev.after = msg.After
+	}
+	if ev.before == nil && ev.after == nil {
+		return rowEvent{}, hlc.Timestamp{}, errors.New("changefeed message has neither before nor after")
+	}
+	return ev, hlc.Timestamp{}, nil
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+// localRow describes the local version of the row of a row event.
+type localRow struct {
+	exists bool
+	// mvccTimestamp is the timestamp at which the local row was last written.
+	mvccTimestamp hlc.Timestamp
+	// matchesBefore and matchesAfter are set if the local row is equal to the
+	// row before, respectively after, the change on the publisher.
+	matchesBefore, matchesAfter bool
+}
+
+// shouldApply returns whether a row event must be applied given the local
+// version of the row.
+//
+// Changes which are no-ops locally are never applied. This is what stops
+// changes from bouncing forever between two clusters which subscribe to each
+// other, since applying a change is itself a change that is published.
+//
+// A change conflicts with local writes if the local row is not the row which
+// was changed on the publisher. With the last-write-wins policy, conflicts are
+// resolved by keeping the version with the highest MVCC timestamp. Rows which
+// were deleted locally have no timestamp left to compare with, so in that case
+// the change of the publisher wins. With the overwrite policy, the change of
+// the publisher always wins.
+func shouldApply(
+	policy jobspb.LogicalReplicationDetails_ConflictResolution, ev rowEvent, local localRow,
+) bool {
+	if ev.after == nil && !local.exists {
+		return false
+	}
+	if ev.after != nil && local.exists && local.matchesAfter {
+		return false
+	}
+	if policy == jobspb.LogicalReplicationDetails_OVERWRITE {
+		return true
+	}
+	noConflict := (ev.before == nil && !local.exists) ||
+		(ev.before != nil && local.exists && local.matchesBefore)
+	if noConflict || !local.exists {
+		return true
+	}
+	return local.mvccTimestamp.Less(ev.mvccTimestamp)
+}
+
+// tableApplier applies the row events of a published table to the local table
+// with the same name.
+type tableApplier struct {
+	policy    jobspb.LogicalReplicationDetails_ConflictResolution
+	dbName    string
+	lookupSQL string
+	upsertSQL string
+	deleteSQL string
+}
+
+func makeTableApplier(
+	policy jobspb.LogicalReplicationDetails_ConflictResolution,
+	dbName string,
+	tn *tree.TableName,
+	table catalog.TableDescriptor,
+) tableApplier {
+	var cols, pkCols []string
+	for _, col := range table.PublicColumns() {
+		if col.IsComputed() || col.IsHidden() {
+			continue
+		}
+		cols = append(cols, tree.NameString(col.GetName()))
+	}
+	for i := 0; i < table.GetPrimaryIndex().NumKeyColumns(); i++ {
+		pkCols = append(pkCols, tree.NameString(table.GetPrimaryIndex().GetKeyColumnName(i)))
+	}
+	qualify := func(alias string, names []string) string {
+		qualified := make([]string, len(names))
+		for i, name := range names {
+			qualified[i] = alias + "." + name
+		}
+		return strings.Join(qualified, ", ")
+	}
+	tableName := tn.FQString()
+	record := func(placeholder string) string {
+		return fmt.Sprintf("json_populate_record(NULL::%s, %s) AS r", tableName, placeholder)
+	}
+	pkPredicate := fmt.Sprintf("ROW(%s) = (SELECT ROW(%s) FROM %s)",
+		qualify("t", pkCols), qualify("r", pkCols), record("$1"))
+	return tableApplier{
+		policy: policy,
+		dbName: dbName,
+		// $1 is the changed row, used to find the local row, $2 is the row
+		// before the change and $3 the row after the change.
+		lookupSQL: fmt.Sprintf(
+			"SELECT t.crdb_internal_mvcc_timestamp, "+
+				"ROW(%[1]s) IS NOT DISTINCT FROM (SELECT ROW(%[2]s) FROM %[3]s), "+
+				"ROW(%[1]s) IS NOT DISTINCT FROM (SELECT ROW(%[2]s) FROM %[4]s) "+
+				"FROM %[5]s AS t WHERE %[6]s",
+			qualify("t", cols), qualify("r", cols), record("$2"), record("$3"), tableName, pkPredicate,
+		),
+		upsertSQL: fmt.Sprintf("UPSERT INTO %s (%s) SELECT %s FROM %s",
+			tableName, strings.Join(cols, ", "), qualify("r", cols), record("$1")),
+		deleteSQL: fmt.Sprintf("DELETE FROM %s AS t WHERE %s", tableName, pkPredicate),
+	}
+}
+
+// apply applies the row event in the transaction if it wins over the local
+// version of the row. It returns whether the event was applied.
+func (a *tableApplier) apply(ctx context.Context, txn isql.Txn, ev rowEvent) (bool, error) {
+	override := sessiondata.InternalExecutorOverride{
+		User:     username.NodeUserName(),
+		Database: a.dbName,
+	}
+	row := ev.after
+	if row == nil {
+		row = ev.before
+	}
+	args := make([]interface{}, 3)
+	for i, raw := range [][]byte{row, ev.before, ev.after} {
+		if raw == nil {
+			raw = []byte("{}")
+		}
+		d, err := tree.ParseDJSON(string(raw))
+		if err != nil {
+			return false, err
+		}
+		args[i] = d
+	}
+
+	var local localRow
+	datums, err := txn.QueryRowEx(ctx, "logical-replication-lookup", txn.KV(), override, a.lookupSQL, args...)
+	if err != nil {
+		return false, err
+	}
+	if datums != nil {
+		local.exists = true
+		local.mvccTimestamp, err = hlc.DecimalToHLC(&tree.MustBeDDecimal(datums[0]).Decimal)
+		if err != nil {
+			return false, err
+		}
+		local.matchesBefore = ev.before != nil && tree.MustBeDBool(datums[1]) == tree.DBoolTrue
+		local.matchesAfter = ev.after != nil && tree.MustBeDBool(datums[2]) == tree.DBoolTrue
+	}
+	if !shouldApply(a.policy, ev, local) {
+		return false, nil
+	}
+	if ev.after != nil {
+		_, err = txn.ExecEx(ctx, "logical-replication-upsert", txn.KV(), override, a.upsertSQL, args[0])
+	} else {
+		_, err = txn.ExecEx(ctx, "logical-replication-delete", txn.KV(), override, a.deleteSQL, args[0])
+	}
+	return err == nil, err
+}
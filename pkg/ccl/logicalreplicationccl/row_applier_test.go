@@ -0,0 +1,86 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logicalreplicationccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangefeedMessage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ev, resolved, err := parseChangefeedMessage([]byte(
+		`{"after": {"k": 1, "v": "b"}, "before": {"k": 1, "v": "a"}, ` +
+			`"mvcc_timestamp": "1700000000000000000.0000000002", "updated": "1700000000000000000.0000000002"}`,
+	))
+	require.NoError(t, err)
+	require.True(t, resolved.IsEmpty())
+	require.Equal(t, hlc.Timestamp{WallTime: 1700000000000000000, Logical: 2}, ev.mvccTimestamp)
+	require.JSONEq(t, `{"k": 1, "v": "a"}`, string(ev.before))
+	require.JSONEq(t, `{"k": 1, "v": "b"}`, string(ev.after))
+
+	ev, _, err = parseChangefeedMessage([]byte(
+		`{"after": null, "before": {"k": 1, "v": "b"}, "mvcc_timestamp": "1700000000000000001.0000000000"}`,
+	))
+	require.NoError(t, err)
+	require.Nil(t, ev.after)
+	require.NotNil(t, ev.before)
+
+	_, resolved, err = parseChangefeedMessage([]byte(`{"resolved": "1700000000000000005.0000000000"}`))
+	require.NoError(t, err)
+	require.Equal(t, hlc.Timestamp{WallTime: 1700000000000000005}, resolved)
+
+	_, _, err = parseChangefeedMessage([]byte(`{"mvcc_timestamp": "1700000000000000001.0000000000"}`))
+	require.Error(t, err)
+}
+
+func TestShouldApply(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	row := []byte(`{}`)
+	older := hlc.Timestamp{WallTime: 1}
+	newer := hlc.Timestamp{WallTime: 2}
+	insert := rowEvent{after: row, mvccTimestamp: newer}
+	update := rowEvent{before: row, after: row, mvccTimestamp: newer}
+	del := rowEvent{before: row, mvccTimestamp: newer}
+
+	const lww = jobspb.LogicalReplicationDetails_LAST_WRITE_WINS
+	const overwrite = jobspb.LogicalReplicationDetails_OVERWRITE
+	for _, tc := range []struct {
+		name     string
+		policy   jobspb.LogicalReplicationDetails_ConflictResolution
+		ev       rowEvent
+		local    localRow
+		expected bool
+	}{
+		{"insert", lww, insert, localRow{}, true},
+		{"update", lww, update, localRow{exists: true, mvccTimestamp: newer, matchesBefore: true}, true},
+		{"delete", lww, del, localRow{exists: true, mvccTimestamp: newer, matchesBefore: true}, true},
+		{"no-op upsert", lww, update, localRow{exists: true, mvccTimestamp: older, matchesAfter: true}, false},
+		{"no-op delete", lww, del, localRow{}, false},
+		{"conflict older local", lww, update, localRow{exists: true, mvccTimestamp: older}, true},
+		{"conflict newer local", lww, update, localRow{exists: true, mvccTimestamp: hlc.Timestamp{WallTime: 3}}, false},
+		{"conflict insert newer local", lww, insert, localRow{exists: true, mvccTimestamp: hlc.Timestamp{WallTime: 3}}, false},
+		{"conflict deleted locally", lww, update, localRow{}, true},
+		{"overwrite newer local", overwrite, update, localRow{exists: true, mvccTimestamp: hlc.Timestamp{WallTime: 3}}, true},
+		{"overwrite no-op", overwrite, update, localRow{exists: true, matchesAfter: true}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, shouldApply(tc.policy, tc.ev, tc.local))
+		})
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logicalreplicationccl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v4"
+)
+
+// resolvedInterval is the interval at which the publisher emits resolved
+// timestamps, which the subscription checkpoints as its high-water mark.
+var resolvedInterval = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"logical_replication.subscription.resolved_interval",
+	"the interval at which subscriptions checkpoint the progress of the replication",
+	10*time.Second,
+	settings.PositiveDuration,
+)
+
+// publishedTable is a table of the publisher which is published by one of the
+// publications of a subscription.
+type publishedTable struct {
+	schema, table string
+}
+
+func connectToPublisher(ctx context.Context, connectionURI string) (*pgx.Conn, error) {
+	config, err := pgx.ParseConfig(connectionURI)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := config.RuntimeParams["application_name"]; !ok {
+		config.RuntimeParams["application_name"] = "$ internal logical replication"
+	}
+	return pgx.ConnectConfig(ctx, config)
+}
+
+// fetchPublishedTables returns the tables published by the publications on the
+// publisher. It returns an error if one of the publications does not exist.
+func fetchPublishedTables(
+	ctx context.Context, conn *pgx.Conn, publications []string,
+) ([]publishedTable, error) {
+	existing := make(map[string]bool)
+	rows, err := conn.Query(ctx,
+		`SELECT pubname FROM pg_catalog.pg_publication WHERE pubname = ANY($1)`, publications)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, name := range publications {
+		if !existing[name] {
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"publication %q does not exist on the publisher", name)
+		}
+	}
+
+	var tables []publishedTable
+	rows, err = conn.Query(ctx,
+		`SELECT DISTINCT schemaname, tablename FROM pg_catalog.pg_publication_tables
+WHERE pubname = ANY($1) ORDER BY 1, 2`, publications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t publishedTable
+		if err := rows.Scan(&t.schema, &t.table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// subscriptionResumer implements the jobs.Resumer interface for the jobs of
+// subscriptions. The job runs a changefeed on the publisher over the published
+// tables, which is backed by rangefeeds, and applies the changes to the tables
+// with the same names in the database of the subscription.
+type subscriptionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*subscriptionResumer)(nil)
+
+// Resume implements the jobs.Resumer interface.
+func (r *subscriptionResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	jobExecCtx := execCtx.(sql.JobExecContext)
+	execCfg := jobExecCtx.ExecCfg()
+	details := r.job.Details().(jobspb.LogicalReplicationDetails)
+
+	conn, err := connectToPublisher(ctx, details.ConnectionURI)
+	if err != nil {
+		return jobs.MarkAsRetryJobError(errors.Wrap(err, "connecting to the publisher"))
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	published, err := fetchPublishedTables(ctx, conn, details.Publications)
+	if err != nil {
+		return err
+	}
+	if len(published) == 0 {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"publications %s of subscription %q do not publish any table",
+			strings.Join(details.Publications, ", "), details.SubscriptionName)
+	}
+	var remoteDB string
+	if err := conn.QueryRow(ctx, `SELECT current_database()`).Scan(&remoteDB); err != nil {
+		return jobs.MarkAsRetryJobError(err)
+	}
+
+	appliers, dbName, err := r.makeAppliers(ctx, execCfg.InternalDB, details, published, remoteDB)
+	if err != nil {
+		return err
+	}
+
+	cursor := r.job.Progress().GetHighWater()
+	initialScan := cursor == nil && details.CopyData
+	if cursor == nil && !details.CopyData {
+		// Without copy_data, only the changes which are committed after the
+		// subscription started are replicated.
+		var ts string
+		if err := conn.QueryRow(ctx,
+			`SELECT cluster_logical_timestamp()::STRING`).Scan(&ts); err != nil {
+			return jobs.MarkAsRetryJobError(err)
+		}
+		parsed, err := hlc.ParseHLC(ts)
+		if err != nil {
+			return err
+		}
+		cursor = &parsed
+	}
+
+	targets := make([]string, 0, len(published))
+	for _, t := range published {
+		tn := tree.MakeTableNameWithSchema(tree.Name(remoteDB), tree.Name(t.schema), tree.Name(t.table))
+		targets = append(targets, tn.FQString())
+	}
+	changefeedSQL := fmt.Sprintf(
+		"EXPERIMENTAL CHANGEFEED FOR %s WITH updated, diff, mvcc_timestamp, full_table_name, "+
+			"envelope = 'wrapped', format = 'json', resolved = %s",
+		strings.Join(targets, ", "),
+		lexbase.EscapeSQLString(resolvedInterval.Get(&execCfg.Settings.SV).String()),
+	)
+	if cursor != nil {
+		changefeedSQL += fmt.Sprintf(", cursor = %s", lexbase.EscapeSQLString(cursor.AsOfSystemTime()))
+	} else if !initialScan {
+		changefeedSQL += ", no_initial_scan"
+	}
+
+	log.Infof(ctx, "starting subscription %q from %d tables", details.SubscriptionName, len(published))
+	// The job spends its life waiting for changes, which is safe to interrupt.
+	r.job.MarkIdle(true)
+	rows, err := conn.Query(ctx, changefeedSQL)
+	if err != nil {
+		return jobs.MarkAsRetryJobError(errors.Wrap(err, "starting the changefeed on the publisher"))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var topic *string
+		var key, value []byte
+		if err := rows.Scan(&topic, &key, &value); err != nil {
+			return err
+		}
+		ev, resolved, err := parseChangefeedMessage(value)
+		if err != nil {
+			return err
+		}
+		if !resolved.IsEmpty() {
+			if err := r.checkpoint(ctx, resolved); err != nil {
+				return err
+			}
+			continue
+		}
+		if topic == nil {
+			return errors.AssertionFailedf("row event without a table")
+		}
+		a, ok := appliers[*topic]
+		if !ok {
+			return errors.AssertionFailedf("row event for unexpected table %s", *topic)
+		}
+		if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			_, err := a.apply(ctx, txn, ev)
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "applying change of %s in database %s", *topic, dbName)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return jobs.MarkAsRetryJobError(err)
+	}
+	// Changefeeds without a sink only end when they fail, so the publisher went
+	// away; retry from the last checkpoint.
+	return jobs.MarkAsRetryJobError(errors.New("the changefeed on the publisher ended"))
+}
+
+// makeAppliers returns the table appliers of the published tables, keyed by
+// the names of the tables in the changefeed messages of the publisher.
+func (r *subscriptionResumer) makeAppliers(
+	ctx context.Context,
+	db descs.DB,
+	details jobspb.LogicalReplicationDetails,
+	published []publishedTable,
+	remoteDB string,
+) (_ map[string]*tableApplier, dbName string, _ error) {
+	appliers := make(map[string]*tableApplier, len(published))
+	err := db.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		dbDesc, err := txn.Descriptors().ByID(txn.KV()).Get().Database(ctx, details.DatabaseID)
+		if err != nil {
+			return err
+		}
+		dbName = dbDesc.GetName()
+		for _, t := range published {
+			sc, err := txn.Descriptors().ByName(txn.KV()).Get().Schema(ctx, dbDesc, t.schema)
+			if err != nil {
+				return err
+			}
+			table, err := txn.Descriptors().ByName(txn.KV()).Get().Table(ctx, dbDesc, sc, t.table)
+			if err != nil {
+				return err
+			}
+			if err := checkSubscribedTable(table); err != nil {
+				return err
+			}
+			localName := tree.MakeTableNameWithSchema(tree.Name(dbName), tree.Name(t.schema), tree.Name(t.table))
+			a := makeTableApplier(details.ConflictResolution, dbName, &localName, table)
+			appliers[fmt.Sprintf("%s.%s.%s", remoteDB, t.schema, t.table)] = &a
+		}
+		return nil
+	})
+	return appliers, dbName, err
+}
+
+// checkSubscribedTable returns an error if the changes of the publisher cannot
+// be applied to the table.
+func checkSubscribedTable(table catalog.TableDescriptor) error {
+	if !table.IsTable() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"cannot use relation %q as logical replication target", table.GetName())
+	}
+	for _, col := range table.PublicColumns() {
+		if col.IsHidden() && table.GetPrimaryIndex().CollectKeyColumnIDs().Contains(col.GetID()) {
+			// Hidden key columns, like rowid, are not published and get
+			// different values on each cluster.
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"logical replication target relation %q must have an explicit primary key",
+				table.GetName())
+		}
+	}
+	return nil
+}
+
+// checkpoint records the resolved timestamp of the publisher as the high-water
+// mark of the job, from which the replication resumes after restarts.
+func (r *subscriptionResumer) checkpoint(ctx context.Context, resolved hlc.Timestamp) error {
+	return r.job.NoTxn().Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		if err := md.CheckRunningOrReverting(); err != nil {
+			return err
+		}
+		if hw := md.Progress.GetHighWater(); hw != nil && resolved.LessEq(*hw) {
+			return nil
+		}
+		md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &resolved}
+		md.Progress.RunningStatus = fmt.Sprintf("replicated until %s",
+			timeutil.Unix(0, resolved.WallTime).Format(time.RFC3339))
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface. Nothing needs to be
+// cleaned up: the rows which were applied stay in the tables and the
+// changefeed on the publisher ends with the connection.
+func (r *subscriptionResumer) OnFailOrCancel(context.Context, interface{}, error) error {
+	return nil
+}
+
+// CollectProfile implements the jobs.Resumer interface.
+func (r *subscriptionResumer) CollectProfile(context.Context, interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeLogicalReplication,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &subscriptionResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
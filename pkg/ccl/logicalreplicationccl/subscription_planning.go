@@ -0,0 +1,296 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logicalreplicationccl
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	optConflictResolution = "conflict_resolution"
+	optCopyData           = "copy_data"
+)
+
+var subscriptionOptionValidations = exprutil.KVOptionValidationMap{
+	optConflictResolution: exprutil.KVStringOptRequireValue,
+	optCopyData:           exprutil.KVStringOptAny,
+}
+
+// conflictResolutionFromString maps the values of the conflict_resolution
+// option to the conflict resolution policies.
+var conflictResolutionFromString = map[string]jobspb.LogicalReplicationDetails_ConflictResolution{
+	"last_write_wins": jobspb.LogicalReplicationDetails_LAST_WRITE_WINS,
+	"overwrite":       jobspb.LogicalReplicationDetails_OVERWRITE,
+}
+
+func createSubscriptionTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, _ colinfo.ResultColumns, _ error) {
+	subStmt, ok := stmt.(*tree.CreateSubscription)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(ctx, "CREATE SUBSCRIPTION", p.SemaCtx(),
+		exprutil.Strings{subStmt.ConnectionURI},
+		exprutil.KVOptions{
+			KVOptions:  subStmt.Options,
+			Validation: subscriptionOptionValidations,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, nil, nil
+}
+
+func createSubscriptionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	subStmt, ok := stmt.(*tree.CreateSubscription)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	exprEval := p.ExprEvaluator("CREATE SUBSCRIPTION")
+	connectionURI, err := exprEval.String(ctx, subStmt.ConnectionURI)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	rawOpts, err := exprEval.KVOptions(ctx, subStmt.Options, subscriptionOptionValidations)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	details := jobspb.LogicalReplicationDetails{
+		SubscriptionName: string(subStmt.Name),
+		ConnectionURI:    connectionURI,
+		CopyData:         true,
+	}
+	for _, pub := range subStmt.Publications {
+		details.Publications = append(details.Publications, string(pub))
+	}
+	if v, ok := rawOpts[optConflictResolution]; ok {
+		policy, ok := conflictResolutionFromString[strings.ToLower(v)]
+		if !ok {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized %s value: %q", optConflictResolution, v)
+		}
+		details.ConflictResolution = policy
+	}
+	if v, ok := rawOpts[optCopyData]; ok && v != "" {
+		copyData, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s requires a Boolean value", optCopyData)
+		}
+		details.CopyData = copyData
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, _ chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, "CREATE SUBSCRIPTION",
+		); err != nil {
+			return err
+		}
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_Publications) {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"subscriptions are not supported until the cluster version is upgraded")
+		}
+		if err := requireAdmin(ctx, p, "create subscriptions"); err != nil {
+			return err
+		}
+		if p.CurrentDatabase() == "" {
+			return sqlerrors.ErrNoDatabase
+		}
+		dbDesc, err := p.InternalSQLTxn().Descriptors().ByName(p.Txn()).Get().Database(ctx, p.CurrentDatabase())
+		if err != nil {
+			return err
+		}
+		details.DatabaseID = dbDesc.GetID()
+
+		_, found, err := findSubscriptionJob(ctx, p, details.DatabaseID, details.SubscriptionName)
+		if err != nil {
+			return err
+		}
+		if found {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"subscription %q already exists", details.SubscriptionName)
+		}
+
+		// Like PostgreSQL, verify that the publications exist on the remote
+		// cluster so that typos are reported when the subscription is created
+		// rather than when the job runs.
+		conn, err := connectToPublisher(ctx, connectionURI)
+		if err != nil {
+			return pgerror.Wrap(err, pgcode.ConnectionFailure,
+				"could not connect to the publisher")
+		}
+		defer func() { _ = conn.Close(ctx) }()
+		if _, err := fetchPublishedTables(ctx, conn, details.Publications); err != nil {
+			return err
+		}
+
+		description, err := subscriptionJobDescription(p, connectionURI, subStmt)
+		if err != nil {
+			return err
+		}
+		jr := jobs.Record{
+			Description: description,
+			Username:    p.User(),
+			Details:     details,
+			Progress:    jobspb.LogicalReplicationProgress{},
+		}
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		_, err = p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(ctx, jr, jobID, p.InternalSQLTxn())
+		return err
+	}
+	return fn, nil, nil, false, nil
+}
+
+func dropSubscriptionTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, _ colinfo.ResultColumns, _ error) {
+	if _, ok := stmt.(*tree.DropSubscription); !ok {
+		return false, nil, nil
+	}
+	return true, nil, nil
+}
+
+func dropSubscriptionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	subStmt, ok := stmt.(*tree.DropSubscription)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, _ chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := requireAdmin(ctx, p, "drop subscriptions"); err != nil {
+			return err
+		}
+		if p.CurrentDatabase() == "" {
+			return sqlerrors.ErrNoDatabase
+		}
+		dbDesc, err := p.InternalSQLTxn().Descriptors().ByName(p.Txn()).Get().Database(ctx, p.CurrentDatabase())
+		if err != nil {
+			return err
+		}
+		jobID, found, err := findSubscriptionJob(ctx, p, dbDesc.GetID(), string(subStmt.Name))
+		if err != nil {
+			return err
+		}
+		if !found {
+			if subStmt.IfExists {
+				p.BufferClientNotice(ctx,
+					pgnotice.Newf("subscription %q does not exist, skipping", subStmt.Name))
+				return nil
+			}
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"subscription %q does not exist", subStmt.Name)
+		}
+		// Canceling the job stops the replication. The rows which were already
+		// applied are kept, like in PostgreSQL.
+		return p.ExecCfg().JobRegistry.UpdateJobWithTxn(ctx, jobID, p.InternalSQLTxn(),
+			func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+				return ju.CancelRequested(ctx, md)
+			})
+	}
+	return fn, nil, nil, false, nil
+}
+
+func requireAdmin(ctx context.Context, p sql.PlanHookState, op string) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		return pgerror.Newf(pgcode.InsufficientPrivilege, "only users with the admin role are allowed to %s", op)
+	}
+	return nil
+}
+
+// findSubscriptionJob returns the ID of the logical replication job of the
+// named subscription of the database, if the job is not in a terminal state.
+func findSubscriptionJob(
+	ctx context.Context, p sql.PlanHookState, dbID descpb.ID, name string,
+) (_ jobspb.JobID, found bool, _ error) {
+	rows, err := p.InternalSQLTxn().QueryBufferedEx(ctx, "find-subscription-job", p.Txn(),
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT id FROM system.jobs WHERE job_type = $1 AND status IN ($2, $3, $4, $5)`,
+		jobspb.TypeLogicalReplication.String(),
+		string(jobs.StatusPending), string(jobs.StatusRunning),
+		string(jobs.StatusPauseRequested), string(jobs.StatusPaused),
+	)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, row := range rows {
+		jobID := jobspb.JobID(tree.MustBeDInt(row[0]))
+		job, err := p.ExecCfg().JobRegistry.LoadJobWithTxn(ctx, jobID, p.InternalSQLTxn())
+		if err != nil {
+			if jobs.HasJobNotFoundError(err) {
+				continue
+			}
+			return 0, false, err
+		}
+		details, ok := job.Details().(jobspb.LogicalReplicationDetails)
+		if !ok {
+			return 0, false, errors.AssertionFailedf(
+				"unexpected details %T for job %d", job.Details(), jobID)
+		}
+		if details.DatabaseID == dbID && details.SubscriptionName == name {
+			return jobID, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// subscriptionJobDescription returns the statement with the password and the
+// parameters of the connection string redacted.
+func subscriptionJobDescription(
+	p sql.PlanHookState, connectionURI string, stmt *tree.CreateSubscription,
+) (string, error) {
+	redactedURI, err := streamclient.RedactSourceURI(connectionURI)
+	if err != nil {
+		return "", err
+	}
+	redactedStmt := *stmt
+	redactedStmt.ConnectionURI = tree.NewDString(redactedURI)
+	return tree.AsStringWithFQNames(&redactedStmt, p.ExtendedEvalContext().Annotations), nil
+}
+
+func init() {
+	sql.AddPlanHook("create subscription", createSubscriptionPlanHook, createSubscriptionTypeCheck)
+	sql.AddPlanHook("drop subscription", dropSubscriptionPlanHook, dropSubscriptionTypeCheck)
+}
@@ -169,6 +169,13 @@ func TestCCLLogic_partitioning_all_by_nothing(
 	runCCLLogicTest(t, "partitioning_all_by_nothing")
 }
 
+func TestCCLLogic_partitioning_attach(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runCCLLogicTest(t, "partitioning_attach")
+}
+
 func TestCCLLogic_partitioning_constrained_scans(
 	t *testing.T,
 ) {
@@ -330,6 +337,13 @@ func TestCCLLogic_tenant_usage(
 	runCCLLogicTest(t, "tenant_usage")
 }
 
+func TestCCLLogic_triggers(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runCCLLogicTest(t, "triggers")
+}
+
 func TestCCLLogic_udf_params(
 	t *testing.T,
 ) {
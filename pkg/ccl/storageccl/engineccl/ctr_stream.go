@@ -97,6 +97,9 @@ func createFileCipherStream(
 			return nil, err
 		}
 		return fcs, nil
+
+	case enginepbccl.EncryptionType_AES_128_GCM, enginepbccl.EncryptionType_AES_192_GCM, enginepbccl.EncryptionType_AES_256_GCM:
+		return newFileGCMStream(key.Key, settings.Nonce)
 	}
 	return nil, fmt.Errorf("unknown encryption type %s", settings.EncryptionType)
 }
@@ -122,6 +125,9 @@ func (c *FileCipherStreamCreator) CreateExisting(
 // a ctrBlockCipherStream. The ctrBlockCipherStream does AES in counter mode (CTR). CTR
 // allows us to encrypt/decrypt at arbitrary byte offsets in a file (including partial
 // blocks) without caring about what preceded the bytes.
+//
+// Files encrypted with AES-GCM use a fileGCMStream, which does not support encrypting
+// in place; see gcmFile.
 type FileStream interface {
 	// Encrypt encrypts the data to be written at fileOffset.
 	Encrypt(fileOffset int64, data []byte)
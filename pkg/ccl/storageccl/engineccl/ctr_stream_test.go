@@ -38,11 +38,11 @@ func generateKey(encType enginepbccl.EncryptionType) (*enginepbccl.SecretKey, er
 	key.Info.EncryptionType = encType
 	var keyLength int
 	switch encType {
-	case enginepbccl.EncryptionType_AES128_CTR:
+	case enginepbccl.EncryptionType_AES128_CTR, enginepbccl.EncryptionType_AES_128_GCM:
 		keyLength = 16
-	case enginepbccl.EncryptionType_AES192_CTR:
+	case enginepbccl.EncryptionType_AES192_CTR, enginepbccl.EncryptionType_AES_192_GCM:
 		keyLength = 24
-	case enginepbccl.EncryptionType_AES256_CTR:
+	case enginepbccl.EncryptionType_AES256_CTR, enginepbccl.EncryptionType_AES_256_GCM:
 		keyLength = 32
 	}
 	key.Key = make([]byte, keyLength)
@@ -11,6 +11,7 @@ package engineccl
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
@@ -143,7 +144,16 @@ func (fs *encryptedFS) Create(name string) (vfs.File, error) {
 			return nil, err
 		}
 	}
-	return &encryptedFile{File: f, stream: stream}, nil
+	return wrapEncryptedFile(f, name, stream, true /* created */), nil
+}
+
+// wrapEncryptedFile returns a vfs.File which encrypts and decrypts the
+// contents of f using the given stream.
+func wrapEncryptedFile(f vfs.File, name string, stream FileStream, created bool) vfs.File {
+	if gcmStream, ok := stream.(*fileGCMStream); ok {
+		return newGCMFile(f, name, gcmStream, created)
+	}
+	return &encryptedFile{File: f, stream: stream}
 }
 
 // Link implements vfs.FS.Link.
@@ -179,7 +189,34 @@ func (fs *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, erro
 		f.Close()
 		return nil, err
 	}
-	return &encryptedFile{File: f, stream: stream}, nil
+	return wrapEncryptedFile(f, name, stream, false /* created */), nil
+}
+
+// Stat implements vfs.FS.Stat. Files encrypted with AES-GCM are larger on
+// disk than their plaintext, whose size is only known once their records are
+// indexed, so they are opened to be stat'ed.
+func (fs *encryptedFS) Stat(name string) (os.FileInfo, error) {
+	info, err := fs.FS.Stat(name)
+	if err != nil || info.IsDir() {
+		return info, err
+	}
+	fileEntry := fs.fileRegistry.GetFileEntry(name)
+	if fileEntry == nil {
+		return info, nil
+	}
+	settings := &enginepbccl.EncryptionSettings{}
+	if err := protoutil.Unmarshal(fileEntry.EncryptionSettings, settings); err != nil {
+		return nil, err
+	}
+	if !settings.EncryptionType.IsAuthenticated() {
+		return info, nil
+	}
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
 }
 
 // Remove implements vfs.FS.Remove.
@@ -315,6 +352,8 @@ func newEncryptedEnv(
 		fs:                unencryptedFS,
 		activeKeyFilename: options.KeyFiles.CurrentKey,
 		oldKeyFilename:    options.KeyFiles.OldKey,
+		activeKeyKMSURI:   options.KeyFiles.CurrentKeyKmsUri,
+		oldKeyKMSURI:      options.KeyFiles.OldKeyKmsUri,
 	}
 	if err := storeKeyManager.Load(context.TODO()); err != nil {
 		return nil, err
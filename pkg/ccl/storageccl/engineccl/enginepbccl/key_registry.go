@@ -26,6 +26,13 @@ func (e EncryptionType) JWKAlgorithm() (string, error) {
 		return "cockroach-aes-192-ctr-v2", nil
 	case EncryptionType_AES_256_CTR_V2:
 		return "cockroach-aes-192-ctr-v2", nil
+
+	case EncryptionType_AES_128_GCM:
+		return "cockroach-aes-128-gcm-v1", nil
+	case EncryptionType_AES_192_GCM:
+		return "cockroach-aes-192-gcm-v1", nil
+	case EncryptionType_AES_256_GCM:
+		return "cockroach-aes-256-gcm-v1", nil
 	}
 	return "", fmt.Errorf("unknown EncryptionType %d", e)
 }
@@ -46,6 +53,23 @@ func EncryptionTypeFromJWKAlgorithm(s string) (EncryptionType, error) {
 		return EncryptionType_AES_192_CTR_V2, nil
 	case "cockroach-aes-256-ctr-v2":
 		return EncryptionType_AES_256_CTR_V2, nil
+
+	case "cockroach-aes-128-gcm-v1":
+		return EncryptionType_AES_128_GCM, nil
+	case "cockroach-aes-192-gcm-v1":
+		return EncryptionType_AES_192_GCM, nil
+	case "cockroach-aes-256-gcm-v1":
+		return EncryptionType_AES_256_GCM, nil
 	}
 	return 0, fmt.Errorf("unknown JWK algorithm name %s", s)
 }
+
+// IsAuthenticated returns true if this EncryptionType detects tampering with
+// or corruption of the encrypted data.
+func (e EncryptionType) IsAuthenticated() bool {
+	switch e {
+	case EncryptionType_AES_128_GCM, EncryptionType_AES_192_GCM, EncryptionType_AES_256_GCM:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,606 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+// Files encrypted with AES-GCM are written as a sequence of records, each of
+// which seals up to gcmChunkSize bytes of plaintext and is stored on disk as:
+//
+//	[header (4 bytes)][nonce (12 bytes)][ciphertext][tag (16 bytes)]
+//
+// The header holds the size of the plaintext, and gcmEndFlag for the end
+// record, which holds no plaintext and is appended when the file is closed
+// after being written. Records are only ever appended to a file and never
+// rewritten, so a write torn by a crash can only affect the last record of the
+// file. Data is sealed into full records as it is written, and the data that
+// does not fill a record is only sealed into a shorter one when the file is
+// synced or closed; the data written afterwards starts a new record.
+//
+// The offset of the plaintext of a record in the file and its header are
+// authenticated as additional data, so records cannot be reordered, dropped
+// or resized within a file, and each file is sealed with its own key derived
+// from the data key and the random nonce in its EncryptionSettings, so
+// records cannot be moved between files either. The end record authenticates
+// the size of the plaintext of a file which was closed, so such a file cannot
+// be truncated either.
+//
+// A record which fails authentication or has an invalid header is reported
+// as a pebble.ErrCorruption error. The only damage which is tolerated is a
+// last record which is physically incomplete in a file without an end record:
+// the record was being written when the process crashed, and was not synced
+// since a synced record is written completely. It is ignored as if the file
+// ended before it, so that Pebble handles it like the torn tail of an
+// unencrypted file. Truncation of a file which was not closed at a record
+// boundary is not detected here; Pebble detects it through the footer of
+// sstables, and tolerates it at the end of WAL and MANIFEST files.
+//
+// Since records have variable sizes, the records of an existing file are
+// indexed by reading their headers when the file is first accessed.
+const (
+	gcmChunkSize      = 4096
+	gcmHeaderSize     = 4
+	gcmNonceSize      = 12
+	gcmTagSize        = 16
+	gcmRecordOverhead = gcmHeaderSize + gcmNonceSize + gcmTagSize
+	gcmMaxRecordSize  = gcmChunkSize + gcmRecordOverhead
+	gcmEndFlag        = 1 << 31
+)
+
+// gcmFileKeyInfo is mixed into the derivation of the per-file keys.
+const gcmFileKeyInfo = "cockroach-aes-gcm-file-key"
+
+var gcmRecordPool = sync.Pool{
+	New: func() interface{} {
+		return new([gcmMaxRecordSize]byte)
+	},
+}
+
+// fileGCMStream holds the AEAD used to seal the records of a file encrypted
+// with AES-GCM. Unlike the CTR streams, it cannot encrypt or decrypt data in
+// place since every record carries a nonce and an authentication tag; the
+// encryptedFS wraps such files in a gcmFile instead.
+type fileGCMStream struct {
+	aead cipher.AEAD
+}
+
+var _ FileStream = &fileGCMStream{}
+
+func newFileGCMStream(key, nonce []byte) (*fileGCMStream, error) {
+	// Derive a key for this file so that the number of records sealed with any
+	// one key, and hence the probability of a random nonce collision, stays
+	// small no matter how much data is written with the same data key.
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(gcmFileKeyInfo))
+	mac.Write(nonce)
+	fileKey := mac.Sum(nil)[:len(key)]
+	aesBlock, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCMWithNonceSize(aesBlock, gcmNonceSize)
+	if err != nil {
+		return nil, err
+	}
+	if aead.Overhead() != gcmTagSize {
+		return nil, errors.AssertionFailedf("unexpected GCM tag size: %d", aead.Overhead())
+	}
+	return &fileGCMStream{aead: aead}, nil
+}
+
+// Encrypt implements the FileStream interface. It must not be called.
+func (s *fileGCMStream) Encrypt(fileOffset int64, data []byte) {
+	panic(errors.AssertionFailedf("AES-GCM files cannot be encrypted in place"))
+}
+
+// Decrypt implements the FileStream interface. It must not be called.
+func (s *fileGCMStream) Decrypt(fileOffset int64, data []byte) {
+	panic(errors.AssertionFailedf("AES-GCM files cannot be decrypted in place"))
+}
+
+// gcmPhysicalSize returns the size on disk of the records holding the given
+// amount of plaintext, when all of them but the last one are full.
+func gcmPhysicalSize(logicalSize int64) int64 {
+	size := (logicalSize / gcmChunkSize) * gcmMaxRecordSize
+	if rem := logicalSize % gcmChunkSize; rem > 0 {
+		size += rem + gcmRecordOverhead
+	}
+	return size
+}
+
+// gcmRecord locates a record of a file encrypted with AES-GCM.
+type gcmRecord struct {
+	// logicalOffset and physicalOffset are the offsets of the record in the
+	// plaintext of the file and on disk.
+	logicalOffset, physicalOffset int64
+	// size is the size of the plaintext of the record.
+	size int
+	// end is set for the end record of the file.
+	end bool
+}
+
+// header returns the header of the record.
+func (r gcmRecord) header() uint32 {
+	if r.end {
+		return gcmEndFlag
+	}
+	return uint32(r.size)
+}
+
+// additionalData returns the additional data authenticated with the record.
+func (r gcmRecord) additionalData() []byte {
+	var ad [12]byte
+	binary.BigEndian.PutUint64(ad[:8], uint64(r.logicalOffset))
+	binary.BigEndian.PutUint32(ad[8:], r.header())
+	return ad[:]
+}
+
+// gcmRun is a run of consecutive records of a file encrypted with AES-GCM,
+// all of which are full except possibly the last one. Runs allow indexing
+// the records of a file compactly, since records are full unless the file was
+// synced before they were.
+type gcmRun struct {
+	// logicalOffset and physicalOffset are the offsets of the first record of
+	// the run in the plaintext of the file and on disk.
+	logicalOffset, physicalOffset int64
+	// size is the size of the plaintext of the records in the run.
+	size int64
+}
+
+// record returns the i-th record of the run.
+func (r gcmRun) record(i int64) gcmRecord {
+	return gcmRecord{
+		logicalOffset:  r.logicalOffset + i*gcmChunkSize,
+		physicalOffset: r.physicalOffset + i*gcmMaxRecordSize,
+		size:           int(min(gcmChunkSize, r.size-i*gcmChunkSize)),
+	}
+}
+
+// gcmIndex indexes the records of a file encrypted with AES-GCM, except for
+// its end record.
+type gcmIndex struct {
+	runs []gcmRun
+}
+
+// end returns the size of the plaintext of the indexed records, and their
+// size on disk.
+func (x *gcmIndex) end() (logicalSize, physicalSize int64) {
+	if len(x.runs) == 0 {
+		return 0, 0
+	}
+	r := x.runs[len(x.runs)-1]
+	return r.logicalOffset + r.size, r.physicalOffset + gcmPhysicalSize(r.size)
+}
+
+// add indexes a record holding the given amount of plaintext after the
+// indexed records.
+func (x *gcmIndex) add(size int) {
+	if n := len(x.runs); n > 0 && x.runs[n-1].size%gcmChunkSize == 0 {
+		x.runs[n-1].size += int64(size)
+		return
+	}
+	logicalSize, physicalSize := x.end()
+	x.runs = append(x.runs, gcmRun{
+		logicalOffset:  logicalSize,
+		physicalOffset: physicalSize,
+		size:           int64(size),
+	})
+}
+
+// records appends to buf the indexed records which overlap with the given
+// range of the plaintext of the file, in order.
+func (x *gcmIndex) records(off, length int64, buf []gcmRecord) []gcmRecord {
+	end := off + length
+	i := sort.Search(len(x.runs), func(i int) bool {
+		return x.runs[i].logicalOffset+x.runs[i].size > off
+	})
+	for ; i < len(x.runs) && off < end; i++ {
+		r := x.runs[i]
+		for j := (off - r.logicalOffset) / gcmChunkSize; j*gcmChunkSize < r.size && off < end; j++ {
+			rec := r.record(j)
+			buf = append(buf, rec)
+			off = rec.logicalOffset + int64(rec.size)
+		}
+	}
+	return buf
+}
+
+// gcmFileInfo reports the size of the plaintext of a file encrypted with
+// AES-GCM.
+type gcmFileInfo struct {
+	os.FileInfo
+	size int64
+}
+
+// Size implements os.FileInfo.
+func (i gcmFileInfo) Size() int64 {
+	return i.size
+}
+
+// gcmFile implements vfs.File for files encrypted with AES-GCM. Writes must be
+// sequential, while reads can be at arbitrary offsets, including concurrently
+// with writes. A record which fails authentication is reported as a
+// pebble.ErrCorruption error.
+type gcmFile struct {
+	vfs.File
+	name   string
+	stream *fileGCMStream
+	mu     struct {
+		syncutil.Mutex
+		rOffset int64
+		// loaded is set once the records already in the file when it was
+		// opened have been indexed.
+		loaded bool
+		index  gcmIndex
+		// created is set if the file was created through f, in which case an
+		// end record is appended when it is closed. synced is set once it has
+		// been synced, in which case the end record is synced as well.
+		created, synced bool
+		// ended is set if the file has an end record.
+		ended bool
+		// tail is the plaintext written after the last record, which is sealed
+		// into a record once it is full or the file is synced or closed.
+		tail []byte
+		// scratch is used to seal records.
+		scratch []byte
+	}
+}
+
+func newGCMFile(f vfs.File, name string, stream *fileGCMStream, created bool) *gcmFile {
+	gf := &gcmFile{File: f, name: name, stream: stream}
+	// A file which was just created is empty, so there is nothing to index.
+	gf.mu.loaded, gf.mu.created = created, created
+	return gf
+}
+
+// loadLocked indexes the records already in the file, unless it was done
+// already. A physically incomplete last record is ignored if the file has no
+// end record; any other invalid record, as well as an end record which fails
+// authentication, is reported as a corruption error.
+func (f *gcmFile) loadLocked() error {
+	if f.mu.loaded {
+		return nil
+	}
+	info, err := f.File.Stat()
+	if err != nil {
+		return err
+	}
+	fileSize := info.Size()
+	var header [gcmHeaderSize]byte
+	for physicalOffset := int64(0); physicalOffset+gcmHeaderSize <= fileSize; {
+		if _, err := f.File.ReadAt(header[:], physicalOffset); err != nil {
+			return err
+		}
+		h := binary.BigEndian.Uint32(header[:])
+		if h == gcmEndFlag {
+			if physicalOffset+gcmRecordOverhead > fileSize {
+				// The end record was torn while it was written.
+				break
+			}
+			if err := f.checkEndRecord(physicalOffset, fileSize); err != nil {
+				return err
+			}
+			f.mu.ended = true
+			break
+		}
+		size := int64(h)
+		if size == 0 || size > gcmChunkSize {
+			return errors.Mark(errors.Newf(
+				"%s: record at offset %d has invalid header %x", f.name, physicalOffset, h,
+			), pebble.ErrCorruption)
+		}
+		physicalOffset += gcmRecordOverhead + size
+		if physicalOffset > fileSize {
+			// The last record was torn while it was written.
+			break
+		}
+		f.mu.index.add(int(size))
+	}
+	f.mu.loaded = true
+	return nil
+}
+
+// checkEndRecord authenticates the end record of the file, found at the given
+// offset, and checks that it is the last record of the file.
+func (f *gcmFile) checkEndRecord(physicalOffset, fileSize int64) error {
+	if physicalOffset+gcmRecordOverhead != fileSize {
+		return errors.Mark(errors.Newf(
+			"%s: end record at offset %d is followed by %d bytes",
+			f.name, physicalOffset, fileSize-physicalOffset-gcmRecordOverhead,
+		), pebble.ErrCorruption)
+	}
+	logicalSize, _ := f.mu.index.end()
+	var buf [gcmRecordOverhead]byte
+	_, err := f.readRecord(gcmRecord{
+		logicalOffset:  logicalSize,
+		physicalOffset: physicalOffset,
+		end:            true,
+	}, buf[:])
+	return err
+}
+
+// Write implements io.Writer.
+func (f *gcmFile) Write(p []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.loadLocked(); err != nil {
+		return 0, err
+	}
+	for len(p) > 0 {
+		c := min(gcmChunkSize-len(f.mu.tail), len(p))
+		f.mu.tail = append(f.mu.tail, p[:c]...)
+		if len(f.mu.tail) == gcmChunkSize {
+			if err := f.sealTailLocked(); err != nil {
+				f.mu.tail = f.mu.tail[:len(f.mu.tail)-c]
+				return n, err
+			}
+		}
+		n += c
+		p = p[c:]
+	}
+	return n, nil
+}
+
+// sealTailLocked seals the plaintext written after the last record, if any,
+// and appends it to the file as a new record.
+func (f *gcmFile) sealTailLocked() error {
+	if len(f.mu.tail) == 0 {
+		return nil
+	}
+	logicalOffset, _ := f.mu.index.end()
+	if err := f.appendRecordLocked(gcmRecord{logicalOffset: logicalOffset, size: len(f.mu.tail)}, f.mu.tail); err != nil {
+		return err
+	}
+	f.mu.index.add(len(f.mu.tail))
+	f.mu.tail = f.mu.tail[:0]
+	return nil
+}
+
+// appendRecordLocked seals the given plaintext as the given record, and
+// appends it to the file.
+func (f *gcmFile) appendRecordLocked(r gcmRecord, plaintext []byte) error {
+	if cap(f.mu.scratch) < gcmMaxRecordSize {
+		f.mu.scratch = make([]byte, 0, gcmMaxRecordSize)
+	}
+	buf := f.mu.scratch[:gcmHeaderSize+gcmNonceSize]
+	binary.BigEndian.PutUint32(buf, r.header())
+	nonce := buf[gcmHeaderSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	buf = f.stream.aead.Seal(buf, nonce, plaintext, r.additionalData())
+	f.mu.scratch = buf
+	_, err := f.File.Write(buf)
+	return err
+}
+
+// WriteAt implements io.WriterAt. Files encrypted with AES-GCM can only be
+// written sequentially.
+func (f *gcmFile) WriteAt(p []byte, off int64) (n int, err error) {
+	return 0, errors.AssertionFailedf("%s: WriteAt is not supported for AES-GCM encrypted files", f.name)
+}
+
+// Read implements io.Reader.
+func (f *gcmFile) Read(p []byte) (n int, err error) {
+	f.mu.Lock()
+	off := f.mu.rOffset
+	f.mu.Unlock()
+	n, err = f.ReadAt(p, off)
+	f.mu.Lock()
+	f.mu.rOffset += int64(n)
+	f.mu.Unlock()
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt. The records to read are located while
+// holding the lock, but they are read and authenticated after releasing it,
+// since records are never modified once written.
+func (f *gcmFile) ReadAt(p []byte, off int64) (n int, err error) {
+	var recordsBuf [4]gcmRecord
+	f.mu.Lock()
+	if err := f.loadLocked(); err != nil {
+		f.mu.Unlock()
+		return 0, err
+	}
+	records := f.mu.index.records(off, int64(len(p)), recordsBuf[:0])
+	// Copy the part of the plaintext that is read from the tail, which can be
+	// modified as soon as the lock is released.
+	var tail []byte
+	if sealedSize, _ := f.mu.index.end(); off+int64(len(p)) > sealedSize {
+		start := max(off-sealedSize, 0)
+		end := min(off+int64(len(p))-sealedSize, int64(len(f.mu.tail)))
+		if start < end {
+			tail = append(tail, f.mu.tail[start:end]...)
+		}
+	}
+	f.mu.Unlock()
+
+	if len(records) > 0 {
+		buf := gcmRecordPool.Get().(*[gcmMaxRecordSize]byte)
+		defer gcmRecordPool.Put(buf)
+		for _, r := range records {
+			plaintext, err := f.readRecord(r, buf[:])
+			if err != nil {
+				return n, err
+			}
+			n += copy(p[n:], plaintext[off+int64(n)-r.logicalOffset:])
+		}
+	}
+	n += copy(p[n:], tail)
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readRecord reads and authenticates the given record into buf, returning
+// its plaintext.
+func (f *gcmFile) readRecord(r gcmRecord, buf []byte) ([]byte, error) {
+	buf = buf[:gcmRecordOverhead+r.size]
+	if n, err := f.File.ReadAt(buf, r.physicalOffset); n < len(buf) {
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil, errors.Mark(errors.Newf(
+				"%s: record at offset %d is truncated to %d bytes", f.name, r.physicalOffset, n,
+			), pebble.ErrCorruption)
+		}
+		return nil, err
+	}
+	nonce, sealed := buf[gcmHeaderSize:gcmHeaderSize+gcmNonceSize], buf[gcmHeaderSize+gcmNonceSize:]
+	// The header read from disk is authenticated indirectly, as the header
+	// expected for the record is authenticated as additional data.
+	plaintext, err := f.stream.aead.Open(sealed[:0], nonce, sealed, r.additionalData())
+	if err != nil {
+		return nil, errors.Mark(errors.Wrapf(
+			err, "%s: record at offset %d failed authentication", f.name, r.physicalOffset,
+		), pebble.ErrCorruption)
+	}
+	return plaintext, nil
+}
+
+// Stat implements vfs.File.
+func (f *gcmFile) Stat() (os.FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.loadLocked(); err != nil {
+		return nil, err
+	}
+	size, _ := f.mu.index.end()
+	return gcmFileInfo{FileInfo: info, size: size + int64(len(f.mu.tail))}, nil
+}
+
+// sealTail seals the plaintext written after the last record, and returns
+// the size on disk of the records written through f.
+func (f *gcmFile) sealTail() (physicalSize int64, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// The tail is empty unless the file was written, which loads it.
+	if f.mu.loaded {
+		if err := f.sealTailLocked(); err != nil {
+			return 0, err
+		}
+	}
+	f.mu.synced = true
+	_, physicalSize = f.mu.index.end()
+	return physicalSize, nil
+}
+
+// Sync implements vfs.File.
+func (f *gcmFile) Sync() error {
+	if _, err := f.sealTail(); err != nil {
+		return err
+	}
+	return f.File.Sync()
+}
+
+// SyncData implements vfs.File.
+func (f *gcmFile) SyncData() error {
+	if _, err := f.sealTail(); err != nil {
+		return err
+	}
+	return f.File.SyncData()
+}
+
+// SyncTo implements vfs.File. All of the records written so far are synced.
+func (f *gcmFile) SyncTo(length int64) (fullSync bool, err error) {
+	physicalSize, err := f.sealTail()
+	if err != nil {
+		return false, err
+	}
+	return f.File.SyncTo(physicalSize)
+}
+
+// Close implements io.Closer. If the file was created through f, its end
+// record is appended.
+func (f *gcmFile) Close() error {
+	return errors.CombineErrors(f.appendEndRecord(), f.File.Close())
+}
+
+// appendEndRecord seals the plaintext written after the last record, and
+// appends the end record if the file was created through f. If the file was
+// synced, the end record is synced as well, so that a file which was durably
+// closed cannot lose it.
+func (f *gcmFile) appendEndRecord() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.mu.created {
+		return nil
+	}
+	if err := f.sealTailLocked(); err != nil {
+		return err
+	}
+	logicalSize, _ := f.mu.index.end()
+	if err := f.appendRecordLocked(gcmRecord{logicalOffset: logicalSize, end: true}, nil); err != nil {
+		return err
+	}
+	f.mu.ended = true
+	if f.mu.synced {
+		return f.File.Sync()
+	}
+	return nil
+}
+
+// Preallocate implements vfs.File. The size on disk of the preallocated range
+// is estimated assuming that it is written in full records.
+func (f *gcmFile) Preallocate(offset, length int64) error {
+	physicalOffset := gcmPhysicalSize(offset)
+	return f.File.Preallocate(physicalOffset, gcmPhysicalSize(offset+length)-physicalOffset)
+}
+
+// Prefetch implements vfs.File.
+func (f *gcmFile) Prefetch(offset int64, length int64) error {
+	f.mu.Lock()
+	if err := f.loadLocked(); err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	records := f.mu.index.records(offset, length, nil)
+	f.mu.Unlock()
+	if len(records) == 0 {
+		return nil
+	}
+	first, last := records[0], records[len(records)-1]
+	end := last.physicalOffset + gcmRecordOverhead + int64(last.size)
+	return f.File.Prefetch(first.physicalOffset, end-first.physicalOffset)
+}
+
+// IsUnclosedGCMFile returns true if the given file, opened through an
+// encrypted FS, is encrypted with AES-GCM and has no end record: either it
+// was still being written when the process stopped, or it was truncated.
+func IsUnclosedGCMFile(f vfs.File) (bool, error) {
+	gf, ok := f.(*gcmFile)
+	if !ok {
+		return false, nil
+	}
+	gf.mu.Lock()
+	defer gf.mu.Unlock()
+	if err := gf.loadLocked(); err != nil {
+		return false, err
+	}
+	return !gf.mu.ended, nil
+}
@@ -0,0 +1,449 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCMSizes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		logical, physical int64
+	}{
+		{0, 0},
+		{1, 1 + gcmRecordOverhead},
+		{gcmChunkSize - 1, gcmMaxRecordSize - 1},
+		{gcmChunkSize, gcmMaxRecordSize},
+		{gcmChunkSize + 1, gcmMaxRecordSize + 1 + gcmRecordOverhead},
+		{3 * gcmChunkSize, 3 * gcmMaxRecordSize},
+	} {
+		t.Run(fmt.Sprint(tc.logical), func(t *testing.T) {
+			require.Equal(t, tc.physical, gcmPhysicalSize(tc.logical))
+		})
+	}
+}
+
+// newTestGCMFS returns an encryptedFS that encrypts files with a random key of
+// the given AES-GCM encryption type, along with the FS it wraps.
+func newTestGCMFS(t *testing.T, encType enginepbccl.EncryptionType) (*encryptedFS, vfs.FS) {
+	memFS := vfs.NewMem()
+	require.NoError(t, memFS.MkdirAll("/foo", os.ModePerm))
+	fileRegistry := &fs.FileRegistry{FS: memFS, DBDir: "/foo"}
+	require.NoError(t, fileRegistry.Load(context.Background()))
+
+	key, err := generateKey(encType)
+	require.NoError(t, err)
+	key.Info.KeyId = "foo"
+	km := &testKeyManager{activeID: "foo", keys: map[string]*enginepbccl.SecretKey{"foo": key}}
+	streamCreator := &FileCipherStreamCreator{keyManager: km, envType: enginepb.EnvType_Data}
+	return &encryptedFS{FS: memFS, fileRegistry: fileRegistry, streamCreator: streamCreator}, memFS
+}
+
+func TestGCMFile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewTestRand()
+	for _, encType := range []enginepbccl.EncryptionType{
+		enginepbccl.EncryptionType_AES_128_GCM,
+		enginepbccl.EncryptionType_AES_192_GCM,
+		enginepbccl.EncryptionType_AES_256_GCM,
+	} {
+		t.Run(encType.String(), func(t *testing.T) {
+			efs, memFS := newTestGCMFS(t, encType)
+			const name = "/foo/file"
+
+			// Write the data in pieces of random sizes. Since the file is not synced
+			// until it is closed, all of its records are full but the last one.
+			data := randutil.RandBytes(rng, 3*gcmChunkSize+1+rng.Intn(gcmChunkSize-1))
+			f, err := efs.Create(name)
+			require.NoError(t, err)
+			for remaining := data; len(remaining) > 0; {
+				n := 1 + rng.Intn(gcmChunkSize/2)
+				if n > len(remaining) {
+					n = len(remaining)
+				}
+				written, err := f.Write(remaining[:n])
+				require.NoError(t, err)
+				require.Equal(t, n, written)
+				remaining = remaining[n:]
+			}
+			info, err := f.Stat()
+			require.NoError(t, err)
+			require.Equal(t, int64(len(data)), info.Size())
+			require.NoError(t, f.Close())
+
+			// The file is larger on disk, including its end record, but the
+			// encryptedFS reports the size of the plaintext.
+			info, err = memFS.Stat(name)
+			require.NoError(t, err)
+			require.Equal(t, gcmPhysicalSize(int64(len(data)))+gcmRecordOverhead, info.Size())
+			info, err = efs.Stat(name)
+			require.NoError(t, err)
+			require.Equal(t, int64(len(data)), info.Size())
+
+			// Read the file back at random offsets.
+			f, err = efs.Open(name)
+			require.NoError(t, err)
+			b, err := io.ReadAll(f)
+			require.NoError(t, err)
+			require.Equal(t, data, b)
+			for i := 0; i < 100; i++ {
+				off := rng.Intn(len(data))
+				b := make([]byte, rng.Intn(2*gcmChunkSize))
+				n, err := f.ReadAt(b, int64(off))
+				if off+len(b) > len(data) {
+					require.ErrorIs(t, err, io.EOF)
+				} else {
+					require.NoError(t, err)
+				}
+				require.Equal(t, data[off:off+n], b[:n])
+			}
+			require.NoError(t, f.Close())
+
+			// Flip a bit in the second record of the file on disk. Reading the first
+			// record still succeeds, but reading the second one returns a
+			// corruption error.
+			raw := readRawFile(t, memFS, name)
+			tampered := append([]byte(nil), raw...)
+			tampered[gcmMaxRecordSize+gcmHeaderSize+gcmNonceSize+rng.Intn(gcmChunkSize)] ^= 0x01
+			writeRawFile(t, memFS, name, tampered)
+			f, err = efs.Open(name)
+			require.NoError(t, err)
+			b = make([]byte, gcmChunkSize)
+			_, err = f.ReadAt(b, 0)
+			require.NoError(t, err)
+			require.Equal(t, data[:gcmChunkSize], b)
+			_, err = f.ReadAt(b, gcmChunkSize)
+			require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+			require.NoError(t, f.Close())
+
+			// Swapping two intact records is detected as well.
+			tampered = append(tampered[:0], raw...)
+			copy(tampered[gcmMaxRecordSize:], raw[2*gcmMaxRecordSize:3*gcmMaxRecordSize])
+			copy(tampered[2*gcmMaxRecordSize:], raw[gcmMaxRecordSize:2*gcmMaxRecordSize])
+			writeRawFile(t, memFS, name, tampered)
+			f, err = efs.Open(name)
+			require.NoError(t, err)
+			_, err = io.ReadAll(f)
+			require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+			require.NoError(t, f.Close())
+		})
+	}
+}
+
+func TestGCMFileTornTail(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewTestRand()
+	efs, memFS := newTestGCMFS(t, enginepbccl.EncryptionType_AES_128_GCM)
+	const name = "/foo/file"
+
+	// Write three pieces of data, syncing after each of them, so that the last
+	// record of each piece is shorter than a chunk.
+	pieces := [][]byte{
+		randutil.RandBytes(rng, gcmChunkSize+100),
+		randutil.RandBytes(rng, 200),
+		randutil.RandBytes(rng, 300),
+	}
+	f, err := efs.Create(name)
+	require.NoError(t, err)
+	var data []byte
+	var ends []int
+	for _, p := range pieces {
+		_, err := f.Write(p)
+		require.NoError(t, err)
+		require.NoError(t, f.Sync())
+		data = append(data, p...)
+		ends = append(ends, len(data))
+	}
+	require.NoError(t, f.Close())
+	raw := readRawFile(t, memFS, name)
+	require.Len(t, raw, gcmMaxRecordSize+100+200+300+4*gcmRecordOverhead)
+	endRecord := len(raw) - gcmRecordOverhead
+	lastRecord := endRecord - 300 - gcmRecordOverhead
+	secondToLastRecord := lastRecord - 200 - gcmRecordOverhead
+
+	for _, tc := range []struct {
+		name   string
+		tamper func(b []byte) []byte
+		// size is the size of the data which can still be read, or -1 if the
+		// file is corrupted.
+		size int
+		// closed is set if the file still has its end record.
+		closed bool
+	}{
+		{
+			name:   "intact",
+			tamper: func(b []byte) []byte { return b },
+			size:   ends[2],
+			closed: true,
+		},
+		{
+			name:   "torn end record",
+			tamper: func(b []byte) []byte { return b[:len(b)-1-rng.Intn(gcmRecordOverhead-1)] },
+			size:   ends[2],
+		},
+		{
+			name:   "dropped end record",
+			tamper: func(b []byte) []byte { return b[:endRecord] },
+			size:   ends[2],
+		},
+		{
+			name:   "torn last record",
+			tamper: func(b []byte) []byte { return b[:lastRecord+1+rng.Intn(300+gcmRecordOverhead-1)] },
+			size:   ends[1],
+		},
+		{
+			name: "corrupted end record",
+			tamper: func(b []byte) []byte {
+				b[endRecord+gcmHeaderSize+rng.Intn(gcmNonceSize+gcmTagSize)] ^= 0x01
+				return b
+			},
+			size: -1,
+		},
+		{
+			name:   "data after end record",
+			tamper: func(b []byte) []byte { return append(b, 0) },
+			size:   -1,
+		},
+		{
+			name: "moved end record",
+			tamper: func(b []byte) []byte {
+				return append(b[:lastRecord:lastRecord], b[endRecord:]...)
+			},
+			size: -1,
+		},
+		{
+			name: "corrupted last record",
+			tamper: func(b []byte) []byte {
+				b[lastRecord+gcmHeaderSize+rng.Intn(300+gcmNonceSize+gcmTagSize)] ^= 0x01
+				return b
+			},
+			size: -1,
+		},
+		{
+			name: "zeroed last records",
+			tamper: func(b []byte) []byte {
+				clear(b[secondToLastRecord:])
+				return b
+			},
+			size: -1,
+		},
+		{
+			name: "corrupted second to last record",
+			tamper: func(b []byte) []byte {
+				b[secondToLastRecord+gcmHeaderSize+rng.Intn(200+gcmNonceSize+gcmTagSize)] ^= 0x01
+				return b
+			},
+			size: -1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			writeRawFile(t, memFS, name, tc.tamper(append([]byte(nil), raw...)))
+			f, err := efs.Open(name)
+			require.NoError(t, err)
+			defer f.Close()
+			b, err := io.ReadAll(f)
+			if tc.size < 0 {
+				require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, data[:tc.size], b)
+			info, err := efs.Stat(name)
+			require.NoError(t, err)
+			require.Equal(t, int64(tc.size), info.Size())
+			unclosed, err := IsUnclosedGCMFile(f)
+			require.NoError(t, err)
+			require.Equal(t, !tc.closed, unclosed)
+		})
+	}
+}
+
+func TestGCMFileConcurrentReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewTestRand()
+	efs, _ := newTestGCMFS(t, enginepbccl.EncryptionType_AES_256_GCM)
+	f, err := efs.Create("/foo/file")
+	require.NoError(t, err)
+	defer f.Close()
+
+	// Read the file while it is being written, syncing it from time to time.
+	// Everything written so far can be read back.
+	data := randutil.RandBytes(rng, 16*gcmChunkSize)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- func() error {
+			for remaining := data; len(remaining) > 0; {
+				n := min(1+rng.Intn(gcmChunkSize), len(remaining))
+				if _, err := f.Write(remaining[:n]); err != nil {
+					return err
+				}
+				if rng.Intn(2) == 0 {
+					if err := f.Sync(); err != nil {
+						return err
+					}
+				}
+				remaining = remaining[n:]
+			}
+			return nil
+		}()
+	}()
+	for done := false; !done; {
+		select {
+		case err := <-errCh:
+			require.NoError(t, err)
+			done = true
+		default:
+		}
+		info, err := f.Stat()
+		require.NoError(t, err)
+		b := make([]byte, info.Size())
+		n, err := f.ReadAt(b, 0)
+		require.NoError(t, err)
+		require.Equal(t, data[:n], b[:n])
+	}
+}
+
+// TestGCMPebble runs Pebble on an encryptedFS using AES-GCM. A write torn at
+// the end of the WAL loses the last writes, while a corrupted WAL or sstable
+// is reported as such.
+func TestGCMPebble(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	efs, memFS := newTestGCMFS(t, enginepbccl.EncryptionType_AES_256_GCM)
+	const dir = "/foo"
+	key := func(i int) []byte {
+		return []byte(fmt.Sprintf("key-%04d", i))
+	}
+
+	// Write keys to an sstable, and then more keys to the WAL only, syncing it
+	// after each of them.
+	const flushed, logged = 1000, 100
+	db, err := pebble.Open(dir, &pebble.Options{FS: efs})
+	require.NoError(t, err)
+	for i := 0; i < flushed; i++ {
+		require.NoError(t, db.Set(key(i), key(i), pebble.NoSync))
+	}
+	require.NoError(t, db.Flush())
+	for i := flushed; i < flushed+logged; i++ {
+		require.NoError(t, db.Set(key(i), key(i), pebble.Sync))
+	}
+	require.NoError(t, db.Close())
+
+	// Tear the second to last record of the WAL, and drop the last one.
+	wals := listFiles(t, efs, dir, ".log")
+	require.NotEmpty(t, wals)
+	wal := wals[len(wals)-1]
+	f, err := efs.Open(wal)
+	require.NoError(t, err)
+	info, err := f.Stat()
+	require.NoError(t, err)
+	gf := f.(*gcmFile)
+	gf.mu.Lock()
+	records := gf.mu.index.records(0, info.Size(), nil)
+	gf.mu.Unlock()
+	require.NoError(t, f.Close())
+	require.Greater(t, len(records), 2)
+	raw := readRawFile(t, memFS, wal)
+
+	// Flipping a bit in the last record of the WAL prevents Pebble from
+	// replaying it.
+	last := records[len(records)-1]
+	tampered := append([]byte(nil), raw...)
+	tampered[last.physicalOffset+gcmHeaderSize+gcmNonceSize] ^= 0x01
+	writeRawFile(t, memFS, wal, tampered)
+	_, err = pebble.Open(dir, &pebble.Options{FS: efs})
+	require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+
+	torn := records[len(records)-2]
+	writeRawFile(t, memFS, wal, raw[:torn.physicalOffset+gcmRecordOverhead+int64(torn.size)/2])
+
+	// Only the last writes are lost.
+	db, err = pebble.Open(dir, &pebble.Options{FS: efs})
+	require.NoError(t, err)
+	for i := 0; i < flushed+logged; i++ {
+		v, closer, err := db.Get(key(i))
+		if i >= flushed+logged-2 && errors.Is(err, pebble.ErrNotFound) {
+			continue
+		}
+		require.NoError(t, err, "key %d", i)
+		require.Equal(t, key(i), v)
+		require.NoError(t, closer.Close())
+	}
+	_, _, err = db.Get(key(flushed + logged - 1))
+	require.ErrorIs(t, err, pebble.ErrNotFound)
+	require.NoError(t, db.Close())
+
+	// Flip a bit in the first record of the first sstable, which holds its
+	// first data block.
+	ssts := listFiles(t, efs, dir, ".sst")
+	require.NotEmpty(t, ssts)
+	raw = readRawFile(t, memFS, ssts[0])
+	raw[gcmHeaderSize+gcmNonceSize] ^= 0x01
+	writeRawFile(t, memFS, ssts[0], raw)
+	db, err = pebble.Open(dir, &pebble.Options{FS: efs})
+	require.NoError(t, err)
+	_, _, err = db.Get(key(0))
+	require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+	require.NoError(t, db.Close())
+}
+
+// listFiles returns the paths of the files in the given directory whose names
+// end with the given suffix, in order.
+func listFiles(t *testing.T, fs vfs.FS, dir, suffix string) []string {
+	names, err := fs.List(dir)
+	require.NoError(t, err)
+	var paths []string
+	for _, name := range names {
+		if strings.HasSuffix(name, suffix) {
+			paths = append(paths, fs.PathJoin(dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// readRawFile returns the contents of the named file in the given FS.
+func readRawFile(t *testing.T, fs vfs.FS, name string) []byte {
+	f, err := fs.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return b
+}
+
+// writeRawFile overwrites the named file in the given FS with data.
+func writeRawFile(t *testing.T, fs vfs.FS, name string, data []byte) {
+	f, err := fs.Create(name)
+	require.NoError(t, err)
+	_, err = f.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/errors"
+)
+
+// storeKMSEnv is the cloud.KMSEnv used to wrap and unwrap store keys. Store
+// keys are loaded when a store is opened, before the node can serve SQL, so
+// the environment uses the default cluster settings and has no database
+// handle. In particular, KMS URIs referencing external connections cannot be
+// used for store keys.
+type storeKMSEnv struct {
+	settings *cluster.Settings
+	conf     base.ExternalIODirConfig
+}
+
+var _ cloud.KMSEnv = &storeKMSEnv{}
+
+func makeStoreKMSEnv() *storeKMSEnv {
+	return &storeKMSEnv{settings: cluster.MakeClusterSettings()}
+}
+
+// ClusterSettings implements the cloud.KMSEnv interface.
+func (e *storeKMSEnv) ClusterSettings() *cluster.Settings {
+	return e.settings
+}
+
+// KMSConfig implements the cloud.KMSEnv interface.
+func (e *storeKMSEnv) KMSConfig() *base.ExternalIODirConfig {
+	return &e.conf
+}
+
+// DBHandle implements the cloud.KMSEnv interface.
+func (e *storeKMSEnv) DBHandle() isql.DB {
+	return nil
+}
+
+// User implements the cloud.KMSEnv interface.
+func (e *storeKMSEnv) User() username.SQLUsername {
+	return username.NodeUserName()
+}
+
+// WrapStoreKey encrypts the contents of a store key file with the KMS key
+// referenced by kmsURI. The result can be written to a key file and used with
+// the kms field of the --enterprise-encryption flag.
+func WrapStoreKey(ctx context.Context, kmsURI string, b []byte) ([]byte, error) {
+	kms, err := cloud.KMSFromURI(ctx, kmsURI, makeStoreKMSEnv())
+	if err != nil {
+		return nil, err
+	}
+	defer kms.Close()
+	wrapped, err := kms.Encrypt(ctx, b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not wrap store key with KMS key %s", kms.MasterKeyID())
+	}
+	return wrapped, nil
+}
+
+// unwrapStoreKey decrypts the contents of a store key file which was wrapped
+// by the KMS key referenced by kmsURI. It also returns the ID of the KMS key.
+func unwrapStoreKey(ctx context.Context, kmsURI string, b []byte) ([]byte, string, error) {
+	kms, err := cloud.KMSFromURI(ctx, kmsURI, makeStoreKMSEnv())
+	if err != nil {
+		return nil, "", err
+	}
+	defer kms.Close()
+	unwrapped, err := kms.Decrypt(ctx, b)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "could not unwrap store key with KMS key %s", kms.MasterKeyID())
+	}
+	return unwrapped, kms.MasterKeyID(), nil
+}
@@ -79,6 +79,10 @@ type StoreKeyManager struct {
 	fs                vfs.FS
 	activeKeyFilename string
 	oldKeyFilename    string
+	// If set, the corresponding key file holds a store key wrapped by the KMS
+	// key referenced by the URI.
+	activeKeyKMSURI string
+	oldKeyKMSURI    string
 
 	// Implementation. Both are not nil after a successful call to Load().
 	activeKey *enginepbccl.SecretKey
@@ -88,11 +92,11 @@ type StoreKeyManager struct {
 // Load must be called before calling other functions.
 func (m *StoreKeyManager) Load(ctx context.Context) error {
 	var err error
-	m.activeKey, err = LoadKeyFromFile(m.fs, m.activeKeyFilename)
+	m.activeKey, err = loadKeyFromFile(ctx, m.fs, m.activeKeyFilename, m.activeKeyKMSURI)
 	if err != nil {
 		return err
 	}
-	m.oldKey, err = LoadKeyFromFile(m.fs, m.oldKeyFilename)
+	m.oldKey, err = loadKeyFromFile(ctx, m.fs, m.oldKeyFilename, m.oldKeyKMSURI)
 	if err != nil {
 		return err
 	}
@@ -119,6 +123,15 @@ func (m *StoreKeyManager) GetKey(id string) (*enginepbccl.SecretKey, error) {
 
 // LoadKeyFromFile reads a secret key from the given file.
 func LoadKeyFromFile(fs vfs.FS, filename string) (*enginepbccl.SecretKey, error) {
+	return loadKeyFromFile(context.Background(), fs, filename, "" /* kmsURI */)
+}
+
+// loadKeyFromFile reads a secret key from the given file. If kmsURI is set,
+// the file holds a key wrapped by the referenced KMS key, which is unwrapped
+// before being parsed.
+func loadKeyFromFile(
+	ctx context.Context, fs vfs.FS, filename string, kmsURI string,
+) (*enginepbccl.SecretKey, error) {
 	now := kmTimeNow().Unix()
 	key := &enginepbccl.SecretKey{}
 	key.Info = &enginepbccl.KeyInfo{}
@@ -139,6 +152,15 @@ func LoadKeyFromFile(fs vfs.FS, filename string) (*enginepbccl.SecretKey, error)
 	if err != nil {
 		return nil, err
 	}
+	source := filename
+	if kmsURI != "" {
+		var masterKeyID string
+		b, masterKeyID, err = unwrapStoreKey(ctx, kmsURI, b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not load store key %s", filename)
+		}
+		source = fmt.Sprintf("%s (wrapped by KMS key %s)", filename, masterKeyID)
+	}
 
 	// We support two file formats:
 	// - Old-style keys are just raw random data with no delimiters; the only
@@ -188,7 +210,7 @@ func LoadKeyFromFile(fs vfs.FS, filename string) (*enginepbccl.SecretKey, error)
 		key.Info.KeyId = hex.EncodeToString(b[:keyIDLength])
 	}
 	key.Info.CreationTime = now
-	key.Info.Source = filename
+	key.Info.Source = source
 
 	return key, nil
 }
@@ -412,11 +434,11 @@ func generateAndSetNewDataKey(
 	} else {
 		var keyLength int
 		switch activeStoreKey.EncryptionType {
-		case enginepbccl.EncryptionType_AES128_CTR:
+		case enginepbccl.EncryptionType_AES128_CTR, enginepbccl.EncryptionType_AES_128_GCM:
 			keyLength = 16
-		case enginepbccl.EncryptionType_AES192_CTR:
+		case enginepbccl.EncryptionType_AES192_CTR, enginepbccl.EncryptionType_AES_192_GCM:
 			keyLength = 24
-		case enginepbccl.EncryptionType_AES256_CTR:
+		case enginepbccl.EncryptionType_AES256_CTR, enginepbccl.EncryptionType_AES_256_GCM:
 			keyLength = 32
 		default:
 			return nil, fmt.Errorf("unknown encryption type %d for key ID %s",
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudtestutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -198,6 +199,83 @@ func TestStoreKeyManager(t *testing.T) {
 	}
 }
 
+func TestStoreKeyManagerKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	kmsURI := cloudtestutils.MakeFileKMSURI(t, dir, "master.key")
+	newKMSURI := cloudtestutils.MakeFileKMSURI(t, dir, "new-master.key")
+
+	memFS := vfs.NewMem()
+	writeToFile(t, memFS, "16.key", []byte(keyFile128))
+	wrapped, err := WrapStoreKey(ctx, kmsURI, []byte(keyFile128))
+	require.NoError(t, err)
+	require.NotContains(t, string(wrapped), key128)
+	writeToFile(t, memFS, "16.key.wrapped", wrapped)
+	wrapped, err = WrapStoreKey(ctx, newKMSURI, []byte(keyFile256))
+	require.NoError(t, err)
+	writeToFile(t, memFS, "32.key.wrapped", wrapped)
+
+	checkKeys := func(t *testing.T, skm *StoreKeyManager) {
+		key, err := skm.ActiveKey(ctx)
+		require.NoError(t, err)
+		require.Equal(t, enginepbccl.EncryptionType_AES256_CTR, key.Info.EncryptionType)
+		require.Equal(t, keyID256, key.Info.KeyId)
+		require.Equal(t, []byte(key256), key.Key)
+		require.Contains(t, key.Info.Source, "32.key.wrapped (wrapped by KMS key ")
+		key, err = skm.GetKey(keyID128)
+		require.NoError(t, err)
+		require.Equal(t, []byte(key128), key.Key)
+	}
+
+	t.Run("migrate from key file", func(t *testing.T) {
+		skm := &StoreKeyManager{
+			fs:                memFS,
+			activeKeyFilename: "32.key.wrapped",
+			activeKeyKMSURI:   newKMSURI,
+			oldKeyFilename:    "16.key",
+		}
+		require.NoError(t, skm.Load(ctx))
+		checkKeys(t, skm)
+	})
+
+	t.Run("rotate KMS key", func(t *testing.T) {
+		skm := &StoreKeyManager{
+			fs:                memFS,
+			activeKeyFilename: "32.key.wrapped",
+			activeKeyKMSURI:   newKMSURI,
+			oldKeyFilename:    "16.key.wrapped",
+			oldKeyKMSURI:      kmsURI,
+		}
+		require.NoError(t, skm.Load(ctx))
+		checkKeys(t, skm)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			filename, kmsURI, expectedErr string
+		}{
+			// The key was wrapped by a different KMS key.
+			{"32.key.wrapped", kmsURI, "could not unwrap store key"},
+			// The key file is not wrapped.
+			{"16.key", kmsURI, "could not unwrap store key"},
+			// The KMS key does not exist.
+			{"16.key.wrapped", cloudtestutils.FileKMSScheme + "://" + dir + "/missing.key", "could not read master key"},
+			// The key is wrapped, but no KMS key was specified.
+			{"16.key.wrapped", "", "could not parse store key"},
+		} {
+			skm := &StoreKeyManager{
+				fs:                memFS,
+				activeKeyFilename: tc.filename,
+				activeKeyKMSURI:   tc.kmsURI,
+				oldKeyFilename:    "plain",
+			}
+			require.ErrorContains(t, skm.Load(ctx), tc.expectedErr)
+		}
+	})
+}
+
 func setActiveStoreKeyInProto(dkr *enginepbccl.DataKeysRegistry, id string) {
 	dkr.StoreKeys[id] = &enginepbccl.KeyInfo{
 		EncryptionType: enginepbccl.EncryptionType_AES128_CTR,
@@ -1375,6 +1375,7 @@ var debugCmds = []*cobra.Command{
 	debugResetQuorumCmd,
 	debugSendKVBatchCmd,
 	debugRecoverCmd,
+	debugSimulateCmd,
 }
 
 // DebugCmd is the root of all debug commands. Exported to allow modification by CCL code.
@@ -1549,6 +1550,12 @@ func init() {
 	f.StringSliceVar(&debugMergeLogsOpts.tenantIDsFilter, "tenant-ids", nil,
 		"tenant IDs to filter logs by")
 
+	f = debugSimulateCmd.Flags()
+	f.StringVar(&debugSimulateOpts.format, "format", debugSimulateOpts.format,
+		"output format of the time series, one of: csv, json")
+	f.StringVarP(&debugSimulateOpts.output, "output", "o", "",
+		"file to write the time series to, defaults to stdout")
+
 	f = debugDecodeKeyCmd.Flags()
 	f.Var(&decodeKeyOptions.encoding, "encoding", "key argument encoding")
 	f.BoolVar(&decodeKeyOptions.userKey, "user-key", false, "key type")
@@ -0,0 +1,97 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/scenario"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var debugSimulateCmd = &cobra.Command{
+	Use:   "simulate <scenario file>",
+	Short: "simulate allocation decisions for a declarative cluster scenario",
+	Long: `
+Runs the allocation simulator against the scenario described in the given YAML
+file and emits, for every store and metrics interval, a time series of the
+store's load, replica and lease counts and the lease transfers and replica
+rebalances it authored.
+
+The scenario declares the cluster topology and localities, the initial ranges,
+the workload, zone configs and a list of events scheduled during the
+simulation, such as adding nodes, marking nodes dead or changing their
+locality. For example:
+
+  duration: 30m
+  seed: 42
+  regions:
+  - name: us-east
+    zones:
+    - {name: us-east-1, nodes: 3}
+    - {name: us-east-2, nodes: 3}
+  ranges:
+    count: 500
+  workload:
+  - {rate: 5000, read_ratio: 0.95}
+  zone_configs:
+  - start_key: 0
+    end_key: 10000
+    config: {num_replicas: 3}
+  events:
+  - {at: 10m, type: add_node, locality: "region=us-east,zone=us-east-1"}
+  - {at: 20m, type: set_liveness, node: 2, liveness: dead}
+
+The simulation does not communicate with a running cluster.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDebugSimulate,
+}
+
+var debugSimulateOpts = struct {
+	format string
+	output string
+}{
+	format: "csv",
+}
+
+func runDebugSimulate(cmd *cobra.Command, args []string) (resErr error) {
+	ctx := context.Background()
+	format, err := scenario.ParseOutputFormat(debugSimulateOpts.format)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return errors.Wrap(err, "reading scenario")
+	}
+	s, err := scenario.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if debugSimulateOpts.output != "" {
+		f, err := os.Create(debugSimulateOpts.output)
+		if err != nil {
+			return errors.Wrap(err, "creating output file")
+		}
+		defer func() { resErr = errors.CombineErrors(resErr, f.Close()) }()
+		out = f
+	}
+
+	sim := s.Simulator()
+	sim.RunSim(ctx)
+	return scenario.WriteHistory(out, sim.History(), format)
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloudtestutils
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// FileKMSScheme is the URI scheme of a fake KMS whose master key is stored in
+// a local file, e.g. file-kms:///path/to/master.key. It is meant for tests
+// which need a KMS without access to a cloud provider.
+const FileKMSScheme = "file-kms"
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeFileKMS, FileKMSScheme)
+}
+
+type fileKMS struct {
+	path string
+	aead cipher.AEAD
+}
+
+var _ cloud.KMS = &fileKMS{}
+
+// MakeFileKMS returns a fake KMS which encrypts and decrypts data with AES-GCM
+// using the 256-bit master key stored in the file referenced by the URI.
+func MakeFileKMS(_ context.Context, uri string, _ cloud.KMSEnv) (cloud.KMS, error) {
+	kmsURL, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	masterKey, err := os.ReadFile(kmsURL.Path)
+	if err != nil {
+		return nil, cloud.KMSInaccessible(errors.Wrap(err, "could not read master key"))
+	}
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fileKMS{path: kmsURL.Path, aead: aead}, nil
+}
+
+// MasterKeyID implements the cloud.KMS interface.
+func (k *fileKMS) MasterKeyID() string {
+	return k.path
+}
+
+// Encrypt implements the cloud.KMS interface.
+func (k *fileKMS) Encrypt(_ context.Context, data []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(data)+k.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return k.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// Decrypt implements the cloud.KMS interface.
+func (k *fileKMS) Decrypt(_ context.Context, data []byte) ([]byte, error) {
+	if len(data) < k.aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := data[:k.aead.NonceSize()], data[k.aead.NonceSize():]
+	return k.aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// Close implements the cloud.KMS interface.
+func (k *fileKMS) Close() error {
+	return nil
+}
+
+// MakeFileKMSURI writes a new random master key to a file with the given name
+// in dir, and returns the URI of a fake KMS which uses it.
+func MakeFileKMSURI(t testing.TB, dir, name string) string {
+	masterKey := make([]byte, 32)
+	_, err := rand.Read(masterKey)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, masterKey, 0600))
+	return fmt.Sprintf("%s://%s", FileKMSScheme, path)
+}
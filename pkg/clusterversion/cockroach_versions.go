@@ -301,6 +301,53 @@ const (
 	// used for allocator decisions before then.
 	V24_1_GossipMaximumIOOverload
 
+	// V24_1_Triggers is the version at which table descriptors may contain
+	// triggers.
+	V24_1_Triggers
+
+	// V24_1_ReplicationSlotsTable is the version at which the
+	// system.replication_slots table is created.
+	V24_1_ReplicationSlotsTable
+
+	// V24_1_Publications is the version at which database descriptors may
+	// contain publications and subscription jobs may be created.
+	V24_1_Publications
+
+	// V24_1_NotificationsTable is the version at which the
+	// system.notifications table is created.
+	V24_1_NotificationsTable
+
+	// V24_1_RangeTypes is the version at which columns may have range and
+	// multirange types.
+	V24_1_RangeTypes
+
+	// V24_1_Jsonpath is the version at which columns may have the jsonpath
+	// type.
+	V24_1_Jsonpath
+
+	// V24_1_DeferrableConstraints is the version at which foreign key and
+	// unique constraints may be DEFERRABLE.
+	V24_1_DeferrableConstraints
+
+	// V24_1_Domains is the version at which DOMAIN types may be created.
+	V24_1_Domains
+
+	// V24_1_IncrementalMaterializedViews is the version at which materialized
+	// views may be refreshed incrementally by a job.
+	V24_1_IncrementalMaterializedViews
+
+	// V24_1_RoutineSecurityDefiner is the version at which routines may be
+	// defined with SECURITY DEFINER or with SET clauses.
+	V24_1_RoutineSecurityDefiner
+
+	// V24_1_VariadicAndPolymorphicRoutines is the version at which routines
+	// may declare VARIADIC parameters and parameters of polymorphic types.
+	V24_1_VariadicAndPolymorphicRoutines
+
+	// V24_1_ExclusionConstraints is the version at which tables may have
+	// EXCLUDE constraints.
+	V24_1_ExclusionConstraints
+
 	numKeys
 )
 
@@ -367,6 +414,18 @@ var versionTable = [numKeys]roachpb.Version{
 	V24_1_PebbleFormatSyntheticPrefixSuffix:    {Major: 23, Minor: 2, Internal: 16},
 	V24_1_SystemDatabaseSurvivability:          {Major: 23, Minor: 2, Internal: 18},
 	V24_1_GossipMaximumIOOverload:              {Major: 23, Minor: 2, Internal: 20},
+	V24_1_Triggers:                             {Major: 23, Minor: 2, Internal: 22},
+	V24_1_ReplicationSlotsTable:                {Major: 23, Minor: 2, Internal: 24},
+	V24_1_Publications:                         {Major: 23, Minor: 2, Internal: 26},
+	V24_1_NotificationsTable:                   {Major: 23, Minor: 2, Internal: 28},
+	V24_1_RangeTypes:                           {Major: 23, Minor: 2, Internal: 30},
+	V24_1_Jsonpath:                             {Major: 23, Minor: 2, Internal: 32},
+	V24_1_DeferrableConstraints:                {Major: 23, Minor: 2, Internal: 34},
+	V24_1_Domains:                              {Major: 23, Minor: 2, Internal: 36},
+	V24_1_IncrementalMaterializedViews:         {Major: 23, Minor: 2, Internal: 38},
+	V24_1_RoutineSecurityDefiner:               {Major: 23, Minor: 2, Internal: 40},
+	V24_1_VariadicAndPolymorphicRoutines:       {Major: 23, Minor: 2, Internal: 42},
+	V24_1_ExclusionConstraints:                 {Major: 23, Minor: 2, Internal: 44},
 }
 
 // Latest is always the highest version key. This is the maximum logical cluster
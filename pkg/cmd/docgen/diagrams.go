@@ -420,6 +420,11 @@ var specs = []stmtSpec{
 		inline:  []string{"opt_for_roles", "role_or_group_or_user", "name_list", "opt_in_schemas", "schema_name_list", "abbreviated_grant_stmt", "opt_with_grant_option", "target_object_type", "abbreviated_revoke_stmt", "opt_drop_behavior"},
 		nosplit: true,
 	},
+	{
+		name:    "alter_domain",
+		stmt:    "alter_domain_stmt",
+		replace: map[string]string{"opt_drop_behavior": ""},
+	},
 	{
 		name:    "alter_index",
 		stmt:    "alter_index_stmt",
@@ -573,7 +578,7 @@ var specs = []stmtSpec{
 	{
 		name:    "alter_view",
 		stmt:    "alter_view_stmt",
-		inline:  []string{"alter_rename_view_stmt", "alter_view_set_schema_stmt", "alter_view_owner_stmt", "opt_transaction"},
+		inline:  []string{"alter_rename_view_stmt", "alter_view_set_schema_stmt", "alter_view_owner_stmt", "alter_view_storage_params_stmt", "opt_transaction"},
 		replace: map[string]string{"relation_expr": "view_name", "'RENAME' 'TO' view_name": "'RENAME' 'TO' view_new_name"},
 		unlink:  []string{"view_name", "view_new_name"},
 	},
@@ -864,6 +869,10 @@ var specs = []stmtSpec{
 		replace: map[string]string{" name": "column_name"},
 		unlink:  []string{"column_name"},
 	},
+	{
+		name: "create_domain",
+		stmt: "create_domain_stmt",
+	},
 	{
 		name: "create_type",
 		stmt: "create_type_stmt",
@@ -995,6 +1004,11 @@ var specs = []stmtSpec{
 		inline: []string{"opt_drop_behavior"},
 		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' 'TABLE'")},
 	},
+	{
+		name:    "drop_domain",
+		stmt:    "drop_domain_stmt",
+		replace: map[string]string{"opt_drop_behavior": ""},
+	},
 	{
 		name:    "drop_type",
 		stmt:    "drop_type_stmt",
@@ -46,6 +46,8 @@ var (
 	_ Details = AutoConfigTaskDetails{}
 	_ Details = AutoUpdateSQLActivityDetails{}
 	_ Details = MVCCStatisticsJobDetails{}
+	_ Details = LogicalReplicationDetails{}
+	_ Details = MaterializedViewRefreshDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -71,6 +73,8 @@ var (
 	_ ProgressDetails = AutoConfigTaskProgress{}
 	_ ProgressDetails = AutoUpdateSQLActivityProgress{}
 	_ ProgressDetails = MVCCStatisticsJobProgress{}
+	_ ProgressDetails = LogicalReplicationProgress{}
+	_ ProgressDetails = MaterializedViewRefreshProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -213,6 +217,10 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeAutoUpdateSQLActivity, nil
 	case *Payload_MvccStatisticsDetails:
 		return TypeMVCCStatisticsUpdate, nil
+	case *Payload_LogicalReplication:
+		return TypeLogicalReplication, nil
+	case *Payload_MaterializedViewRefresh:
+		return TypeMaterializedViewRefresh, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -258,6 +266,8 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeAutoConfigTask:               AutoConfigTaskDetails{},
 	TypeAutoUpdateSQLActivity:        AutoUpdateSQLActivityDetails{},
 	TypeMVCCStatisticsUpdate:         MVCCStatisticsJobDetails{},
+	TypeLogicalReplication:           LogicalReplicationDetails{},
+	TypeMaterializedViewRefresh:      MaterializedViewRefreshDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -315,6 +325,10 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_UpdateSqlActivity{UpdateSqlActivity: &d}
 	case MVCCStatisticsJobProgress:
 		return &Progress_MvccStatisticsProgress{MvccStatisticsProgress: &d}
+	case LogicalReplicationProgress:
+		return &Progress_LogicalReplication{LogicalReplication: &d}
+	case MaterializedViewRefreshProgress:
+		return &Progress_MaterializedViewRefresh{MaterializedViewRefresh: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -370,6 +384,10 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.AutoUpdateSqlActivities
 	case *Payload_MvccStatisticsDetails:
 		return *d.MvccStatisticsDetails
+	case *Payload_LogicalReplication:
+		return *d.LogicalReplication
+	case *Payload_MaterializedViewRefresh:
+		return *d.MaterializedViewRefresh
 	default:
 		return nil
 	}
@@ -425,6 +443,10 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.UpdateSqlActivity
 	case *Progress_MvccStatisticsProgress:
 		return *d.MvccStatisticsProgress
+	case *Progress_LogicalReplication:
+		return *d.LogicalReplication
+	case *Progress_MaterializedViewRefresh:
+		return *d.MaterializedViewRefresh
 	default:
 		return nil
 	}
@@ -504,6 +526,10 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_AutoUpdateSqlActivities{AutoUpdateSqlActivities: &d}
 	case MVCCStatisticsJobDetails:
 		return &Payload_MvccStatisticsDetails{MvccStatisticsDetails: &d}
+	case LogicalReplicationDetails:
+		return &Payload_LogicalReplication{LogicalReplication: &d}
+	case MaterializedViewRefreshDetails:
+		return &Payload_MaterializedViewRefresh{MaterializedViewRefresh: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -539,7 +565,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 25
+const NumJobTypes = 27
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
@@ -10,13 +10,19 @@
 
 package allocator2
 
-import "github.com/cockroachdb/cockroach/pkg/roachpb"
+import (
+	"time"
 
-// ChangeOptions is passed to ComputeChanges and AdminScatterOne.
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// ChangeOptions is passed to ComputeChanges.
 type ChangeOptions struct {
 	// DryRun tells the allocator not to update its internal state with the
 	// proposed pending changes.
 	DryRun bool
+	// LeaseTransfersOnly restricts the proposed changes to lease transfers.
+	LeaseTransfersOnly bool
 }
 
 // Allocator is the interface for a distributed allocator. We expect that the
@@ -30,7 +36,8 @@ type ChangeOptions struct {
 //     be less different than integration with the old allocator.
 type Allocator interface {
 	// Methods to update the state of the external world. The allocator starts
-	// with no knowledge.
+	// with no knowledge. The allocator does not read the clock, and the methods
+	// that need the current time are passed it as now.
 
 	// SetStore informs the allocator about a new store, or when something about
 	// the store descriptor has changed. The allocator's knowledge about the
@@ -73,7 +80,7 @@ type Allocator interface {
 	//   will not give us the follower non-raft CPU, so we will need to assume
 	//   that the CPU for a follower is only the raftCPU, and that it has the
 	//   same value as the raftCPU at the leaseholder.
-	ProcessNodeLoadResponse(resp *nodeLoadResponse) error
+	ProcessNodeLoadResponse(resp *nodeLoadResponse, now time.Time) error
 
 	// TODO(sumeer): only a subset of the fields in pendingReplicaChange are
 	// relevant to the caller. Hide the remaining.
@@ -90,7 +97,8 @@ type Allocator interface {
 	// Calls to AdjustPendingChangesDisposition must be correctly sequenced with
 	// full state updates from the local node provided in
 	// ProcessNodeLoadResponse.
-	AdjustPendingChangesDisposition(changes []pendingReplicaChange, success bool) error
+	AdjustPendingChangesDisposition(
+		changes []pendingReplicaChange, success bool, now time.Time) error
 
 	// ComputeChanges is called periodically and frequently, say every 10s.
 	//
@@ -104,68 +112,9 @@ type Allocator interface {
 	// Unless ChangeOptions.DryRun is true, changes returned are remembered by
 	// the allocator, to avoid re-proposing the same change and to make
 	// adjustments to the load.
-	ComputeChanges(opts ChangeOptions) []*pendingReplicaChange
-
-	// AdminRelocateOne is a helper for AdminRelocateRange.
-	//
-	// The allocator must know the stores listed in the targets. The
-	// implementation ignores the allocator's state of the range's replicas and
-	// leaseholder, and utilizes the information provided in desc, conf, and
-	// leaseholderStore (leaseholderStore is -1 if there is no leaseholder) --
-	// for this reason it does not remember the proposed changes. The
-	// implementation ignores the current load on the targets, though it returns
-	// an error if any of the targets has a failure detector state != fdOK.
-	// Additionally, it checks that the voterTargets and nonVoterTargets satisfy
-	// all the range constraints. Leaseholder preferences are ignored if
-	// transferLeaseToFirstVoter is true, else the leaseholder is picked
-	// randomly from the best set of voters (best defined by leaseholder
-	// preferences. Load is not considered). Diversity scores are ignored since
-	// the set of voters and non-voters have been explicitly specified.
-	//
-	// If the returned slice is empty and error is nil, there are no more
-	// changes to make.
-	//
-	// Note for future integration work: the intention here is to subsume some
-	// of the code in replica_command.go, and this method will be called from
-	// Replica.relocateReplicas (RelocateOne code is no longer needed).
-	//
-	// NB: leaseholderStore is needed because this function may not be called on
-	// the leaseholder. Also, even if it is being called at the leaseholder
-	// node, the node may have multiple stores, and we need to know which store
-	// is the leaseholder. This also implies that the leaseholder transfer does
-	// not need to be the last step. For the same reason, the rangeID may not be
-	// known to the allocator, which is why the range's SpanConfig is provided.
-	AdminRelocateOne(desc *roachpb.RangeDescriptor, conf *roachpb.SpanConfig,
-		leaseholderStore roachpb.StoreID,
-		voterTargets, nonVoterTargets []roachpb.ReplicationTarget,
-		transferLeaseToFirstVoter bool) ([]pendingReplicaChange, error)
+	ComputeChanges(opts ChangeOptions, now time.Time) []*pendingReplicaChange
 
-	// AdminScatterOne is a helper for AdminScatterRequest. Consider it a more
-	// aggressive rebalance, with more randomization. The difference wrt
-	// rebalancing is that (a) the candidate range for rebalancing is being
-	// specified by the caller, instead of having to be figured out by the
-	// allocator. Like rebalancing, the candidate store to shed from and the
-	// store to add to is computed by the callee, and the result is provided in
-	// []pendingReplicaChange. Exactly one such pair of remove-add will be
-	// specified in the pending changes. Unless DryRun is true, these pending
-	// changes are remembered by the callee and the caller should not call again
-	// for the same range until it has enacted the change and informed the
-	// callee about the change success or failure via
-	// AdjustPendingChangesDisposition.
-	//
-	// If the caller will call AdminScatterOne in a loop to potentially scatter
-	// all the replicas, only the last call should specify
-	// canTransferLease=true, since transferring the lease will make it no
-	// longer possible to call AdminScatterOne on this node.
-	//
-	// TODO(sumeer):
-	// - understand what is really happening as a consequence of
-	//   ScorerOptionsForScatter.
-	AdminScatterOne(
-		rangeID roachpb.RangeID, canTransferLease bool, opts ChangeOptions,
-	) ([]pendingReplicaChange, error)
+	// TODO(sumeer): add helpers for AdminRelocateRange and AdminScatterRequest,
+	// so that relocation and scatter can also be planned by this allocator.
+	// Until then, both are handled by the old allocator.
 }
-
-// Avoid unused lint errors.
-
-var _ = ChangeOptions{}.DryRun
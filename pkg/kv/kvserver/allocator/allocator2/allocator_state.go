@@ -11,7 +11,9 @@
 package allocator2
 
 import (
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 )
@@ -43,31 +45,414 @@ func newAllocatorState() *allocatorState {
 	}
 }
 
+var _ Allocator = &allocatorState{}
+
+// SetStore implements the Allocator interface.
+func (a *allocatorState) SetStore(store roachpb.StoreDescriptor) error {
+	if _, ok := a.cs.stores[store.StoreID]; ok {
+		a.cs.changeStore(store)
+	} else {
+		a.cs.addStore(store)
+	}
+	return nil
+}
+
+// RemoveNodeAndStores implements the Allocator interface.
+func (a *allocatorState) RemoveNodeAndStores(nodeID roachpb.NodeID) error {
+	a.cs.removeNodeAndStores(nodeID)
+	return nil
+}
+
+// UpdateFailureDetectionSummary implements the Allocator interface.
+func (a *allocatorState) UpdateFailureDetectionSummary(
+	nodeID roachpb.NodeID, fd failureDetectionSummary,
+) error {
+	a.cs.updateFailureDetectionSummary(nodeID, fd)
+	return nil
+}
+
+// ProcessNodeLoadResponse implements the Allocator interface.
+func (a *allocatorState) ProcessNodeLoadResponse(resp *nodeLoadResponse, now time.Time) error {
+	a.cs.processNodeLoadResponse(resp, now)
+	return nil
+}
+
+// AdjustPendingChangesDisposition implements the Allocator interface.
+func (a *allocatorState) AdjustPendingChangesDisposition(
+	changes []pendingReplicaChange, success bool, now time.Time,
+) error {
+	if !success {
+		a.cs.pendingChangesRejected(changes)
+		return nil
+	}
+	for i := range changes {
+		if change := a.cs.pendingChanges[changes[i].changeID]; change != nil {
+			a.cs.pendingChangeEnacted(change, now)
+		}
+	}
+	return nil
+}
+
+// ComputeChanges implements the Allocator interface.
+func (a *allocatorState) ComputeChanges(
+	opts ChangeOptions, now time.Time,
+) []*pendingReplicaChange {
+	return a.computeChanges(opts, now)
+}
+
 // Called periodically, say every 10s.
-func (a *allocatorState) computeChanges() []*pendingReplicaChange {
-	// TODO(sumeer): rebalancing. To select which stores are overloaded, we will
-	// use a notion of overload that is based on cluster means (and of course
-	// individual store/node capacities). We do not want to loop through all
-	// ranges in the cluster, and for each range and its constraints expression
-	// decide whether any of the replica stores is overloaded, since
-	// O(num-ranges) work during each allocator pass is not scalable.
-	//
-	// If cluster mean is too low, more will be considered overloaded. This is
-	// ok, since then when we look at ranges we will have a different mean for
-	// the constraint satisfying candidates and if that mean is higher we may
-	// not do anything. There is wasted work, but we can bound it by typically
-	// only looking at a random K ranges for each store.
-	//
-	// If the cluster mean is too high, we will not rebalance across subsets
-	// that have a low mean. Seems fine, if we accept that rebalancing is not
-	// responsible for equalizing load across two nodes that have 30% and 50%
-	// cpu utilization while the cluster mean is 70% utilization (as an
-	// example).
+//
+// To select which stores are overloaded, we use a notion of overload that is
+// based on cluster means (and of course individual store/node capacities).
+// We do not want to loop through all ranges in the cluster, and for each
+// range and its constraints expression decide whether any of the replica
+// stores is overloaded, since O(num-ranges) work during each allocator pass
+// is not scalable.
+//
+// If cluster mean is too low, more will be considered overloaded. This is
+// ok, since then when we look at ranges we will have a different mean for the
+// constraint satisfying candidates and if that mean is higher we may not do
+// anything. There is wasted work, but we can bound it by only looking at the
+// top-k ranges for each store.
+//
+// If the cluster mean is too high, we will not rebalance across subsets that
+// have a low mean. Seems fine, if we accept that rebalancing is not
+// responsible for equalizing load across two nodes that have 30% and 50% cpu
+// utilization while the cluster mean is 70% utilization (as an example).
+//
+// Only ranges for which a local store is the leaseholder are considered, so
+// the shedding stores are the local leaseholder stores.
+func (a *allocatorState) computeChanges(
+	opts ChangeOptions, now time.Time,
+) []*pendingReplicaChange {
+	a.cs.gcPendingChanges(now)
+	a.meansMemo.clear()
+	clusterMeans := a.meansMemo.getMeans(constraintsDisj{nil})
+	var leaseholderStores []roachpb.StoreID
+	for _, rs := range a.cs.ranges {
+		for _, r := range rs.replicas {
+			if r.isLeaseholder {
+				leaseholderStores = append(leaseholderStores, r.StoreID)
+			}
+		}
+	}
+	leaseholderStores = makeStoreIDPostingList(leaseholderStores)
+	var changes []*pendingReplicaChange
+	for i, storeID := range leaseholderStores {
+		if i > 0 && leaseholderStores[i-1] == storeID {
+			continue
+		}
+		changes = a.rebalanceStore(storeID, clusterMeans, opts, now, changes)
+	}
+	if opts.DryRun {
+		for i := len(changes) - 1; i >= 0; i-- {
+			if change := a.cs.pendingChanges[changes[i].changeID]; change != nil {
+				a.cs.undoPendingChange(change)
+			}
+		}
+	}
+	return changes
+}
+
+// rangeIDAndLoad is a top-k range of a store being considered for shedding.
+type rangeIDAndLoad struct {
+	roachpb.RangeID
+	rangeLoad
+}
+
+// rebalanceStore tries to shed load from the store, if it is overloaded, by
+// moving leases and replicas of the ranges for which it is the leaseholder.
+// The proposed changes are added as pending changes and appended to changes.
+func (a *allocatorState) rebalanceStore(
+	storeID roachpb.StoreID,
+	clusterMeans *meansForStoreSet,
+	opts ChangeOptions,
+	now time.Time,
+	changes []*pendingReplicaChange,
+) []*pendingReplicaChange {
+	ss := a.cs.stores[storeID]
+	if ss == nil || ss.storeInitState != fullyInit {
+		return changes
+	}
+	dim, overloaded := a.overloadedDimension(ss, clusterMeans)
+	if !overloaded {
+		return changes
+	}
+	// The store decides which ranges are worth shedding, via the top-k ranges.
+	var ranges []rangeIDAndLoad
+	for rangeID, rl := range ss.topKRanges {
+		rs := a.cs.ranges[rangeID]
+		if rs == nil || len(rs.pendingChanges) > 0 || rl.load[dim] <= 0 {
+			continue
+		}
+		if state, ok := ss.adjusted.replicas[rangeID]; !ok || !state.isLeaseholder {
+			continue
+		}
+		ranges = append(ranges, rangeIDAndLoad{RangeID: rangeID, rangeLoad: rl})
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].load[dim] != ranges[j].load[dim] {
+			return ranges[i].load[dim] > ranges[j].load[dim]
+		}
+		return ranges[i].RangeID < ranges[j].RangeID
+	})
+	for _, r := range ranges {
+		if _, overloaded := a.overloadedDimension(ss, clusterMeans); !overloaded {
+			break
+		}
+		rs := a.cs.ranges[r.RangeID]
+		// Moving the lease only sheds the cpu that is not raft related, so it is
+		// only useful when cpu is the overloaded dimension. It is cheaper than
+		// moving a replica, so is tried first.
+		if dim == cpu {
+			if c := a.tryMovingLease(ss, r.RangeID, rs, r.rangeLoad, clusterMeans, now); c != nil {
+				changes = append(changes, c...)
+				continue
+			}
+		}
+		if opts.LeaseTransfersOnly {
+			continue
+		}
+		if c := a.tryMovingReplica(ss, r.RangeID, rs, r.rangeLoad, now); c != nil {
+			changes = append(changes, c...)
+		}
+	}
+	return changes
+}
+
+// overloadedDimension returns the load dimension in which the store (or, for
+// cpu, its node) is most overloaded relative to the means, and whether it is
+// overloaded at all.
+func (a *allocatorState) overloadedDimension(
+	ss *storeState, means *meansForStoreSet,
+) (loadDimension, bool) {
+	ns := a.cs.nodes[ss.NodeID]
+	msl := &means.storeLoad
+	mnl := &means.nodeLoad
+	worst := loadNoChange
+	dim := cpu
+	overloaded := false
+	for i := range ss.adjusted.load {
+		ls := loadSummaryForDimension(ss.adjusted.load[i], ss.capacity[i], msl.load[i], msl.util[i])
+		if loadDimension(i) == cpu {
+			nls := loadSummaryForDimension(ns.adjustedCPU, ns.capacityCPU, mnl.loadCPU, mnl.utilCPU)
+			if nls < ls {
+				ls = nls
+			}
+		}
+		if ls < worst {
+			worst = ls
+			dim = loadDimension(i)
+			overloaded = true
+		}
+	}
+	return dim, overloaded
+}
+
+// tryMovingLease tries to transfer the lease of the range from ss to another
+// voter. It returns the pending changes on success.
+func (a *allocatorState) tryMovingLease(
+	ss *storeState,
+	rangeID roachpb.RangeID,
+	rs *rangeState,
+	rl rangeLoad,
+	means *meansForStoreSet,
+	now time.Time,
+) []*pendingReplicaChange {
+	var delta loadVector
+	delta[cpu] = rl.load[cpu] - rl.raftCPU
+	if delta[cpu] <= 0 {
+		return nil
+	}
+	// Only consider the candidates with the best lease preference, and only if
+	// that is not worse than the current leaseholder's.
+	bestPref := a.leasePreferenceIndex(rs.conf, ss.StoreID)
+	var candidates []*storeState
+	var candidatePrefs []int
+	for _, r := range rs.replicas {
+		if r.StoreID == ss.StoreID || r.replicaType.replicaType != roachpb.VOTER_FULL ||
+			r.voterIsLagging {
+			continue
+		}
+		cand := a.cs.stores[r.StoreID]
+		if cand == nil || cand.storeInitState != fullyInit ||
+			a.cs.nodes[cand.NodeID].fdSummary != fdOK {
+			continue
+		}
+		pref := a.leasePreferenceIndex(rs.conf, r.StoreID)
+		if pref < bestPref {
+			bestPref = pref
+		}
+		candidates = append(candidates, cand)
+		candidatePrefs = append(candidatePrefs, pref)
+	}
+	var target *storeState
+	for i, cand := range candidates {
+		if candidatePrefs[i] != bestPref || !a.cs.canAddLoad(cand, delta, means) {
+			continue
+		}
+		if target == nil {
+			target = cand
+			continue
+		}
+		candCPU := a.cs.nodes[cand.NodeID].adjustedCPU
+		targetCPU := a.cs.nodes[target.NodeID].adjustedCPU
+		if candCPU < targetCPU || (candCPU == targetCPU && cand.StoreID < target.StoreID) {
+			target = cand
+		}
+	}
+	if target == nil {
+		return nil
+	}
+	var negDelta loadVector
+	negDelta.subtract(delta)
+	srcState := ss.adjusted.replicas[rangeID]
+	srcNext := srcState.replicaIDAndType
+	srcNext.isLeaseholder = false
+	targetState := target.adjusted.replicas[rangeID]
+	targetNext := targetState.replicaIDAndType
+	targetNext.isLeaseholder = true
+	changes := []*pendingReplicaChange{
+		{
+			loadDelta: negDelta,
+			storeID:   ss.StoreID,
+			rangeID:   rangeID,
+			prev:      srcState,
+			next:      srcNext,
+		},
+		{
+			loadDelta: delta,
+			storeID:   target.StoreID,
+			rangeID:   rangeID,
+			prev:      targetState,
+			next:      targetNext,
+		},
+	}
+	a.cs.addPendingChanges(rangeID, changes, now)
+	return changes
+}
+
+// epsilonDiversityScore is used to ignore floating point noise when checking
+// whether a rebalance reduces diversity.
+const epsilonDiversityScore = 1e-10
+
+// tryMovingReplica tries to move the replica of the range at ss, which is
+// also the leaseholder, to a store that does not have a replica of the range.
+// The lease moves with the replica. It returns the pending changes on
+// success.
+func (a *allocatorState) tryMovingReplica(
+	ss *storeState, rangeID roachpb.RangeID, rs *rangeState, rl rangeLoad, now time.Time,
+) []*pendingReplicaChange {
+	if rs.constraints == nil {
+		rac := rangeAnalyzedConstraintsPool.Get().(*rangeAnalyzedConstraints)
+		buf := rac.stateForInit()
+		for _, r := range rs.replicas {
+			buf.tryAddingStore(r.StoreID, r.replicaType.replicaType, a.cs.stores[r.StoreID].localityTiers)
+		}
+		rac.finishInit(rs.conf, a.cs.constraintMatcher)
+		rs.constraints = rac
+	}
+	conj, err := rs.constraints.candidatesToReplaceVoterForRebalance(ss.StoreID)
+	if err != nil {
+		// The range needs some other kind of change first, which is not the
+		// responsibility of load-based rebalancing.
+		return nil
+	}
+	// Exclude the nodes that already have a replica.
+	var storesToExclude storeIDPostingList
+	var voterLocalities []localityTiers
+	for _, r := range rs.replicas {
+		rss := a.cs.stores[r.StoreID]
+		for _, storeID := range a.cs.nodes[rss.NodeID].stores {
+			storesToExclude.insert(storeID)
+		}
+		if r.replicaType.replicaType == roachpb.VOTER_FULL {
+			voterLocalities = append(voterLocalities, rss.localityTiers)
+		}
+	}
+	cset := a.computeCandidatesForRange(constraintsDisj{conj}, storesToExclude, ss.StoreID)
+	if len(cset.candidates) == 0 {
+		return nil
+	}
+	erl := a.diversityScoringMemo.getExistingReplicaLocalities(voterLocalities)
+	srcPref := a.leasePreferenceIndex(rs.conf, ss.StoreID)
+	candidates := cset.candidates[:0]
+	for _, c := range cset.candidates {
+		c.diversityScore = erl.getScoreChangeForRebalance(
+			ss.localityTiers, a.cs.stores[c.StoreID].localityTiers)
+		if c.diversityScore < -epsilonDiversityScore ||
+			a.leasePreferenceIndex(rs.conf, c.StoreID) > srcPref {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	// Prefer the least loaded candidates, and then higher diversity.
+	sort.Slice(candidates, func(i, j int) bool {
+		li, lj := candidates[i].sls, candidates[j].sls
+		if candidates[i].nls < li {
+			li = candidates[i].nls
+		}
+		if candidates[j].nls < lj {
+			lj = candidates[j].nls
+		}
+		if li != lj {
+			return li > lj
+		}
+		if candidates[i].diversityScore != candidates[j].diversityScore {
+			return candidates[i].diversityScore > candidates[j].diversityScore
+		}
+		return candidates[i].StoreID < candidates[j].StoreID
+	})
+	for _, c := range candidates {
+		target := a.cs.stores[c.StoreID]
+		if !a.cs.canAddLoad(target, rl.load, cset.means) {
+			continue
+		}
+		var negDelta loadVector
+		negDelta.subtract(rl.load)
+		changes := []*pendingReplicaChange{
+			{
+				loadDelta: negDelta,
+				storeID:   ss.StoreID,
+				rangeID:   rangeID,
+				prev:      ss.adjusted.replicas[rangeID],
+				next:      replicaIDAndType{ReplicaID: noReplicaID},
+			},
+			{
+				loadDelta: rl.load,
+				storeID:   target.StoreID,
+				rangeID:   rangeID,
+				prev: replicaState{
+					replicaIDAndType: replicaIDAndType{ReplicaID: noReplicaID},
+				},
+				next: replicaIDAndType{
+					ReplicaID: unknownReplicaID,
+					replicaType: replicaType{
+						replicaType:   roachpb.VOTER_FULL,
+						isLeaseholder: true,
+					},
+				},
+			},
+		}
+		a.cs.addPendingChanges(rangeID, changes, now)
+		return changes
+	}
 	return nil
 }
 
-// TODO(sumeer): look at support methods for allocatorState.tryMovingRange in
-// the allocator kernel draft PR.
+// leasePreferenceIndex returns the index of the first lease preference
+// satisfied by the store, or len(leasePreferences) if none is satisfied.
+// Lower is better.
+func (a *allocatorState) leasePreferenceIndex(
+	conf *normalizedSpanConfig, storeID roachpb.StoreID,
+) int {
+	for i := range conf.leasePreferences {
+		if a.cs.constraintMatcher.storeMatches(storeID, conf.leasePreferences[i].constraints) {
+			return i
+		}
+	}
+	return len(conf.leasePreferences)
+}
 
 type candidateInfo struct {
 	roachpb.StoreID
@@ -152,10 +537,10 @@ func (a *allocatorState) computeCandidatesForRange(
 	if loadSheddingStore > 0 {
 		sheddingSS := a.cs.stores[loadSheddingStore]
 		sheddingSLS := a.meansMemo.getStoreLoadSummary(means, loadSheddingStore, sheddingSS.loadSeqNum)
-		if sheddingSLS.sls > sheddingThreshold {
-			sheddingThreshold = sheddingSLS.sls
-		}
-		if sheddingSLS.nls > sheddingThreshold {
+		// The shedding store is as overloaded as the worse of its store and node
+		// summaries, so candidates need only be better than that.
+		sheddingThreshold = sheddingSLS.sls
+		if sheddingSLS.nls < sheddingThreshold {
 			sheddingThreshold = sheddingSLS.nls
 		}
 		if sheddingSLS.sls >= loadNoChange && sheddingSLS.nls >= loadNoChange {
@@ -224,19 +609,40 @@ type replicasLocalityTiers struct {
 	replicas []localityTiers
 }
 
+// makeReplicasLocalityTiers sorts replicas in place and returns it as a
+// replicasLocalityTiers.
 func makeReplicasLocalityTiers(replicas []localityTiers) replicasLocalityTiers {
-	// TODO(sumeer): remember to sort.
-	return replicasLocalityTiers{}
+	sort.Slice(replicas, func(i, j int) bool {
+		return replicas[i].str < replicas[j].str
+	})
+	return replicasLocalityTiers{replicas: replicas}
 }
 
+// FNV-1a hash algorithm.
 func (rlt replicasLocalityTiers) hash() uint64 {
-	// TODO(sumeer):
-	return 0
+	h := uint64(offset64)
+	for i := range rlt.replicas {
+		for _, code := range rlt.replicas[i].tiers {
+			h ^= uint64(code)
+			h *= prime64
+		}
+		// Separator between replicas.
+		h *= prime64
+	}
+	return h
 }
 
 func (rlt replicasLocalityTiers) isEqual(b mapKey) bool {
-	// TODO(sumeer):
-	return false
+	other := b.(replicasLocalityTiers)
+	if len(rlt.replicas) != len(other.replicas) {
+		return false
+	}
+	for i := range rlt.replicas {
+		if rlt.replicas[i].str != other.replicas[i].str {
+			return false
+		}
+	}
+	return true
 }
 
 var _ mapKey = replicasLocalityTiers{}
@@ -289,8 +695,11 @@ func (dsm *diversityScoringMemo) getExistingReplicaLocalities(
 	if ok {
 		return erl
 	}
-	erl.replicasLocalityTiers = lt
-	erl.scoreSums = map[string]float64{}
+	// The map entry may be reused, and must not alias the caller's slice.
+	erl.replicas = append(erl.replicas[:0], lt.replicas...)
+	if erl.scoreSums == nil {
+		erl.scoreSums = map[string]float64{}
+	}
 	return erl
 }
 
@@ -11,6 +11,7 @@
 package allocator2
 
 import (
+	"math"
 	"sort"
 	"time"
 
@@ -82,11 +83,13 @@ type pendingReplicaChange struct {
 	// Only following cases can happen:
 	//
 	// - prev.replicaID >= 0 && next.replicaID == noReplicaID: outgoing replica.
-	//   prev.isLeaseholder is false, since we shed a lease first.
+	//   prev.isLeaseholder is false, unless the lease moves to the incoming
+	//   replica that this change is paired with.
 	//
 	// - prev.replicaID == noReplicaID && next.replicaID == unknownReplicaID:
 	//   incoming replica, next.replicaType must be VOTER_FULL or NON_VOTER.
-	//   Both isLeaseholder fields must be false.
+	//   next.isLeaseholder is false, unless the lease moves from the outgoing
+	//   replica that this change is paired with.
 	//
 	// - prev.replicaID >= 0 && next.replicaID >= 0: can be a change to
 	//   isLeaseholder, or replicaType. next.replicaType must be VOTER_FULL or
@@ -105,6 +108,17 @@ type pendingReplicaChange struct {
 	enactedAtTime time.Time
 }
 
+// secondaryLoadDelta returns the secondary load this change adds to a store.
+func (prc *pendingReplicaChange) secondaryLoadDelta() secondaryLoadVector {
+	var delta secondaryLoadVector
+	if prc.prev.isLeaseholder && !prc.next.isLeaseholder {
+		delta[leaseCount] = -1
+	} else if !prc.prev.isLeaseholder && prc.next.isLeaseholder {
+		delta[leaseCount] = 1
+	}
+	return delta
+}
+
 type pendingChangesOldestFirst []*pendingReplicaChange
 
 func (p *pendingChangesOldestFirst) removeChangeAtIndex(index int) {
@@ -465,6 +479,8 @@ type clusterState struct {
 	// time-based GC. There is no explicit acceptance by enacting module since
 	// the single source of truth of a rangeState is the leaseholder.
 	pendingChanges map[changeID]*pendingReplicaChange
+	// changeSeqGen is used to assign a unique changeID to each pending change.
+	changeSeqGen changeID
 
 	*constraintMatcher
 	*localityTierInterner
@@ -485,24 +501,342 @@ func newClusterState(interner *stringInterner) *clusterState {
 // clusterState mutators
 //======================================================================
 
-func (cs *clusterState) processNodeLoadResponse(resp *nodeLoadResponse) {
-	// TODO(sumeer):
+func (cs *clusterState) processNodeLoadResponse(resp *nodeLoadResponse, now time.Time) {
+	ns := cs.nodes[resp.nodeID]
+	if ns == nil {
+		// The node is not known to the allocator, e.g. it was concurrently
+		// removed. Ignore the response.
+		return
+	}
+	ns.nodeLoad = resp.nodeLoad
+	if len(resp.leaseholderStores) > 0 {
+		// The leaseholderStores are authoritative and complete for this node, so
+		// ranges that are no longer mentioned are no longer led by this node.
+		// Ranges with pending changes are retained, since the lease may be
+		// moving as part of one of those changes, and the changes will be
+		// garbage collected.
+		seen := map[roachpb.RangeID]struct{}{}
+		for i := range resp.leaseholderStores {
+			for j := range resp.leaseholderStores[i].ranges {
+				rm := &resp.leaseholderStores[i].ranges[j]
+				seen[rm.RangeID] = struct{}{}
+				cs.processRangeMsg(rm, now)
+			}
+		}
+		var toRemove []roachpb.RangeID
+		for rangeID, rs := range cs.ranges {
+			if _, ok := seen[rangeID]; !ok && len(rs.pendingChanges) == 0 {
+				toRemove = append(toRemove, rangeID)
+			}
+		}
+		for _, rangeID := range toRemove {
+			cs.removeRange(rangeID)
+		}
+	}
+	for i := range resp.stores {
+		cs.processStoreLoadMsg(&resp.stores[i], now)
+	}
+	cs.updateNodeAdjustedCPU(ns)
+	cs.gcRemovedStores()
+}
+
+func (cs *clusterState) processStoreLoadMsg(msg *storeLoadMsg, now time.Time) {
+	ss := cs.stores[msg.StoreID]
+	if ss == nil || ss.storeInitState != fullyInit {
+		return
+	}
+	ss.reportedLoad = msg.load
+	ss.capacity = msg.capacity
+	ss.reportedSecondaryLoad = msg.secondaryLoad
+	ss.topKRanges = map[roachpb.RangeID]rangeLoad{}
+	for _, r := range msg.topKRanges {
+		ss.topKRanges[r.RangeID] = r.rangeLoad
+	}
+	ss.meanNonTopKRangeLoad = msg.meanNonTopKRangeLoad
+	for _, change := range ss.computePendingChangesReflectedInLatestLoad(now) {
+		delete(ss.adjusted.loadPendingChanges, change.changeID)
+	}
+	for rangeID := range ss.adjusted.loadReplicas {
+		delete(ss.adjusted.loadReplicas, rangeID)
+	}
+	for _, r := range msg.storeRanges {
+		ss.adjusted.loadReplicas[r.RangeID] = r.replicaType
+	}
+	for _, change := range ss.adjusted.loadPendingChanges {
+		applyChangeToLoadReplicas(ss, change.rangeID, change.next)
+	}
+	cs.updateStoreAdjustedLoad(ss)
+}
+
+func (cs *clusterState) processRangeMsg(rm *rangeMsg, now time.Time) {
+	if rm.isDeletedRange() {
+		cs.removeRange(rm.RangeID)
+		return
+	}
+	for _, r := range rm.replicas {
+		if ss := cs.stores[r.StoreID]; ss == nil || ss.storeInitState != fullyInit {
+			// The allocator does not (yet) know enough about this store to make
+			// decisions involving this range. The range will be added back when
+			// the leaseholder next reports it.
+			cs.removeRange(rm.RangeID)
+			return
+		}
+	}
+	// A non-nil conf with an error is the result of a best-effort structural
+	// normalization, which is good enough to use.
+	conf, _ := makeNormalizedSpanConfig(&rm.conf, cs.constraintMatcher.interner)
+	if conf == nil {
+		cs.removeRange(rm.RangeID)
+		return
+	}
+	rs := cs.ranges[rm.RangeID]
+	if rs == nil {
+		rs = &rangeState{}
+		cs.ranges[rm.RangeID] = rs
+	}
+	if len(rs.pendingChanges) > 0 {
+		allReflected := true
+		for _, change := range rs.pendingChanges {
+			if !changeReflectedInReplicas(change, rm.replicas) {
+				allReflected = false
+				break
+			}
+		}
+		if allReflected {
+			changes := append([]*pendingReplicaChange(nil), rs.pendingChanges...)
+			for _, change := range changes {
+				cs.pendingChangeEnacted(change, now)
+			}
+		}
+	}
+	// Replace the replicas with the authoritative state, and reapply the
+	// remaining pending changes.
+	for _, r := range rs.replicas {
+		if ss := cs.stores[r.StoreID]; ss != nil {
+			delete(ss.adjusted.replicas, rm.RangeID)
+		}
+	}
+	rs.replicas = rs.replicas[:0]
+	for _, r := range rm.replicas {
+		rs.replicas = append(rs.replicas, r)
+		cs.stores[r.StoreID].adjusted.replicas[rm.RangeID] = r.replicaState
+	}
+	for _, change := range rs.pendingChanges {
+		cs.setReplicaState(rm.RangeID, rs, change.storeID, replicaState{replicaIDAndType: change.next})
+	}
+	rs.conf = conf
+	cs.clearRangeConstraints(rs)
+	rs.lastHeardTime = now
+}
+
+// changeReflectedInReplicas returns true if the authoritative replicas of
+// the range reflect the change.
+func changeReflectedInReplicas(
+	change *pendingReplicaChange, replicas []storeIDAndReplicaState,
+) bool {
+	for _, r := range replicas {
+		if r.StoreID == change.storeID {
+			return change.next.ReplicaID != noReplicaID &&
+				r.replicaType == change.next.replicaType
+		}
+	}
+	return change.next.ReplicaID == noReplicaID
+}
+
+// setReplicaState sets the adjusted state of the replica of the range at
+// storeID. A state with noReplicaID removes the replica. A state with
+// unknownReplicaID retains the ReplicaID of an existing replica.
+func (cs *clusterState) setReplicaState(
+	rangeID roachpb.RangeID, rs *rangeState, storeID roachpb.StoreID, state replicaState,
+) {
+	ss := cs.stores[storeID]
+	idx := -1
+	for i := range rs.replicas {
+		if rs.replicas[i].StoreID == storeID {
+			idx = i
+			break
+		}
+	}
+	if state.ReplicaID == noReplicaID {
+		if idx >= 0 {
+			rs.replicas = append(rs.replicas[:idx], rs.replicas[idx+1:]...)
+		}
+		if ss != nil {
+			delete(ss.adjusted.replicas, rangeID)
+		}
+		return
+	}
+	if idx >= 0 {
+		if state.ReplicaID == unknownReplicaID {
+			state.ReplicaID = rs.replicas[idx].ReplicaID
+		}
+		rs.replicas[idx].replicaState = state
+	} else {
+		rs.replicas = append(rs.replicas, storeIDAndReplicaState{StoreID: storeID, replicaState: state})
+	}
+	if ss != nil {
+		ss.adjusted.replicas[rangeID] = state
+	}
+}
+
+func applyChangeToLoadReplicas(ss *storeState, rangeID roachpb.RangeID, next replicaIDAndType) {
+	if next.ReplicaID == noReplicaID {
+		delete(ss.adjusted.loadReplicas, rangeID)
+	} else {
+		ss.adjusted.loadReplicas[rangeID] = next.replicaType
+	}
+}
+
+// updateStoreAdjustedLoad recomputes the adjusted load of the store, and of
+// its node, from the reported load and the load pending changes.
+func (cs *clusterState) updateStoreAdjustedLoad(ss *storeState) {
+	ss.adjusted.load = ss.reportedLoad
+	ss.adjusted.secondaryLoad = ss.reportedSecondaryLoad
+	for _, change := range ss.adjusted.loadPendingChanges {
+		ss.adjusted.load.add(change.loadDelta)
+		ss.adjusted.secondaryLoad.add(change.secondaryLoadDelta())
+	}
+	ss.maxFractionPending = 0
+	for i := range ss.adjusted.load {
+		if ss.reportedLoad[i] == 0 {
+			continue
+		}
+		f := math.Abs(1 - float64(ss.adjusted.load[i])/float64(ss.reportedLoad[i]))
+		if f > ss.maxFractionPending {
+			ss.maxFractionPending = f
+		}
+	}
+	ss.loadSeqNum++
+	if ns := cs.nodes[ss.NodeID]; ns != nil {
+		cs.updateNodeAdjustedCPU(ns)
+	}
+}
+
+// updateNodeAdjustedCPU recomputes the adjusted cpu of the node from the
+// reported cpu and the load pending changes of its stores. The stores'
+// loadSeqNum is incremented, since their storeLoadSummary incorporates the
+// node's load.
+func (cs *clusterState) updateNodeAdjustedCPU(ns *nodeState) {
+	ns.adjustedCPU = ns.reportedCPU
+	for _, storeID := range ns.stores {
+		ss := cs.stores[storeID]
+		for _, change := range ss.adjusted.loadPendingChanges {
+			ns.adjustedCPU += change.loadDelta[cpu]
+		}
+		ss.loadSeqNum++
+	}
+}
+
+// removeRange forgets the range, including its pending changes.
+func (cs *clusterState) removeRange(rangeID roachpb.RangeID) {
+	rs := cs.ranges[rangeID]
+	if rs == nil {
+		return
+	}
+	for len(rs.pendingChanges) > 0 {
+		cs.undoPendingChange(rs.pendingChanges[0])
+	}
+	for _, r := range rs.replicas {
+		if ss := cs.stores[r.StoreID]; ss != nil {
+			delete(ss.adjusted.replicas, rangeID)
+		}
+	}
+	cs.clearRangeConstraints(rs)
+	delete(cs.ranges, rangeID)
+}
+
+func (cs *clusterState) clearRangeConstraints(rs *rangeState) {
+	if rs.constraints != nil {
+		releaseRangeAnalyzedConstraints(rs.constraints)
+		rs.constraints = nil
+	}
 }
 
 func (cs *clusterState) addNodeID(nodeID roachpb.NodeID) {
-	// TODO(sumeer):
+	if _, ok := cs.nodes[nodeID]; ok {
+		return
+	}
+	cs.nodes[nodeID] = &nodeState{
+		nodeLoad: nodeLoad{
+			nodeID:      nodeID,
+			capacityCPU: unknownCapacity,
+		},
+	}
 }
 
 func (cs *clusterState) addStore(store roachpb.StoreDescriptor) {
-	// TODO(sumeer):
+	cs.addNodeID(store.Node.NodeID)
+	ss := &storeState{storeInitState: fullyInit}
+	ss.storeLoad.StoreID = store.StoreID
+	ss.storeLoad.StoreDescriptor = store
+	ss.storeLoad.NodeID = store.Node.NodeID
+	ss.capacity = loadVector{parentCapacity, unknownCapacity, unknownCapacity}
+	ss.topKRanges = map[roachpb.RangeID]rangeLoad{}
+	ss.adjusted.loadReplicas = map[roachpb.RangeID]replicaType{}
+	ss.adjusted.loadPendingChanges = map[changeID]*pendingReplicaChange{}
+	ss.adjusted.replicas = map[roachpb.RangeID]replicaState{}
+	ss.localityTiers = cs.localityTierInterner.intern(store.Locality())
+	cs.stores[store.StoreID] = ss
+	cs.constraintMatcher.setStore(store)
+	ns := cs.nodes[store.Node.NodeID]
+	ns.stores = append(ns.stores, store.StoreID)
 }
 
 func (cs *clusterState) changeStore(store roachpb.StoreDescriptor) {
-	// TODO(sumeer):
+	ss := cs.stores[store.StoreID]
+	if ss.storeInitState == removed {
+		// The store was removed, but is being added back before it was garbage
+		// collected.
+		ss.storeInitState = fullyInit
+		if cs.nodes[ss.NodeID] == nil {
+			cs.addNodeID(ss.NodeID)
+			ns := cs.nodes[ss.NodeID]
+			ns.stores = append(ns.stores, store.StoreID)
+		}
+	}
+	ss.storeLoad.StoreDescriptor = store
+	ss.localityTiers = cs.localityTierInterner.intern(store.Locality())
+	cs.constraintMatcher.setStore(store)
+	ss.loadSeqNum++
 }
 
 func (cs *clusterState) removeNodeAndStores(nodeID roachpb.NodeID) {
-	// TODO(sumeer):
+	ns := cs.nodes[nodeID]
+	if ns == nil {
+		return
+	}
+	for _, storeID := range ns.stores {
+		ss := cs.stores[storeID]
+		ss.storeInitState = removed
+		ss.loadSeqNum++
+		cs.constraintMatcher.removeStore(storeID)
+	}
+	ns.fdSummary = fdDead
+	cs.gcRemovedStores()
+}
+
+// gcRemovedStores forgets removed stores that are no longer referenced by
+// any range, and nodes that no longer have any stores.
+func (cs *clusterState) gcRemovedStores() {
+	for storeID, ss := range cs.stores {
+		if ss.storeInitState != removed || len(ss.adjusted.replicas) > 0 {
+			continue
+		}
+		delete(cs.stores, storeID)
+		ns := cs.nodes[ss.NodeID]
+		if ns == nil {
+			continue
+		}
+		for i := range ns.stores {
+			if ns.stores[i] == storeID {
+				ns.stores = append(ns.stores[:i], ns.stores[i+1:]...)
+				break
+			}
+		}
+		if len(ns.stores) == 0 {
+			delete(cs.nodes, ss.NodeID)
+		}
+	}
 }
 
 // If the pending change does not happen within this GC duration, we
@@ -510,25 +844,115 @@ func (cs *clusterState) removeNodeAndStores(nodeID roachpb.NodeID) {
 const pendingChangeGCDuration = 5 * time.Minute
 
 // Called periodically by allocator.
-func (cs *clusterState) gcPendingChanges() {
-	// TODO(sumeer):
+func (cs *clusterState) gcPendingChanges(now time.Time) {
+	var expired []*pendingReplicaChange
+	for _, change := range cs.pendingChanges {
+		if now.Sub(change.startTime) > pendingChangeGCDuration {
+			expired = append(expired, change)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].changeID < expired[j].changeID
+	})
+	for _, change := range expired {
+		cs.undoPendingChange(change)
+	}
+	for _, ss := range cs.stores {
+		changed := false
+		for id, change := range ss.adjusted.loadPendingChanges {
+			if !change.enactedAtTime.IsZero() &&
+				now.Sub(change.enactedAtTime) > pendingChangeGCDuration {
+				delete(ss.adjusted.loadPendingChanges, id)
+				changed = true
+			}
+		}
+		if changed {
+			cs.updateStoreAdjustedLoad(ss)
+		}
+	}
 }
 
 // Called by enacting module.
-func (cs *clusterState) pendingChangesRejected(
-	rangeID roachpb.RangeID, changes []pendingReplicaChange,
-) {
-	// TODO(sumeer):
+func (cs *clusterState) pendingChangesRejected(changes []pendingReplicaChange) {
+	for i := range changes {
+		if change := cs.pendingChanges[changes[i].changeID]; change != nil {
+			cs.undoPendingChange(change)
+		}
+	}
+}
+
+// undoPendingChange removes a pending change that was not enacted, and
+// reverts its effect on the adjusted replicas and load.
+func (cs *clusterState) undoPendingChange(change *pendingReplicaChange) {
+	delete(cs.pendingChanges, change.changeID)
+	if rs := cs.ranges[change.rangeID]; rs != nil {
+		for i := range rs.pendingChanges {
+			if rs.pendingChanges[i] == change {
+				rs.pendingChanges = append(rs.pendingChanges[:i], rs.pendingChanges[i+1:]...)
+				break
+			}
+		}
+		cs.setReplicaState(change.rangeID, rs, change.storeID, change.prev)
+		cs.clearRangeConstraints(rs)
+	}
+	if ss := cs.stores[change.storeID]; ss != nil {
+		delete(ss.adjusted.loadPendingChanges, change.changeID)
+		applyChangeToLoadReplicas(ss, change.rangeID, change.prev.replicaIDAndType)
+		cs.updateStoreAdjustedLoad(ss)
+	}
+}
+
+// pendingChangeEnacted is called when the change is known to have been
+// enacted. The change is retained in the store's loadPendingChanges until
+// the store's reported load reflects it.
+func (cs *clusterState) pendingChangeEnacted(change *pendingReplicaChange, now time.Time) {
+	change.enactedAtTime = now
+	delete(cs.pendingChanges, change.changeID)
+	if rs := cs.ranges[change.rangeID]; rs != nil {
+		for i := range rs.pendingChanges {
+			if rs.pendingChanges[i] == change {
+				rs.pendingChanges = append(rs.pendingChanges[:i], rs.pendingChanges[i+1:]...)
+				break
+			}
+		}
+	}
+	if ss := cs.stores[change.storeID]; ss != nil {
+		ss.adjusted.enactedHistory.addEnactedChange(change)
+	}
 }
 
-func (cs *clusterState) addPendingChanges(rangeID roachpb.RangeID, changes []pendingReplicaChange) {
-	// TODO(sumeer):
+// addPendingChanges assigns a changeID to each of the changes, and adjusts
+// the replicas and load for them. The range must be known.
+func (cs *clusterState) addPendingChanges(
+	rangeID roachpb.RangeID, changes []*pendingReplicaChange, now time.Time,
+) {
+	rs := cs.ranges[rangeID]
+	for _, change := range changes {
+		cs.changeSeqGen++
+		change.changeID = cs.changeSeqGen
+		change.startTime = now
+		cs.pendingChanges[change.changeID] = change
+		rs.pendingChanges = append(rs.pendingChanges, change)
+		cs.setReplicaState(rangeID, rs, change.storeID, replicaState{replicaIDAndType: change.next})
+		ss := cs.stores[change.storeID]
+		ss.adjusted.loadPendingChanges[change.changeID] = change
+		applyChangeToLoadReplicas(ss, rangeID, change.next)
+		cs.updateStoreAdjustedLoad(ss)
+	}
+	cs.clearRangeConstraints(rs)
 }
 
 func (cs *clusterState) updateFailureDetectionSummary(
 	nodeID roachpb.NodeID, fd failureDetectionSummary,
 ) {
-	// TODO(sumeer):
+	ns := cs.nodes[nodeID]
+	if ns == nil {
+		return
+	}
+	ns.fdSummary = fd
+	for _, storeID := range ns.stores {
+		cs.stores[storeID].loadSeqNum++
+	}
 }
 
 //======================================================================
@@ -540,13 +964,17 @@ func (cs *clusterState) updateFailureDetectionSummary(
 // For meansMemo.
 var _ loadInfoProvider = &clusterState{}
 
-func (cs *clusterState) getStoreReportedLoad(roachpb.StoreID) *storeLoad {
-	// TODO(sumeer):
+func (cs *clusterState) getStoreReportedLoad(storeID roachpb.StoreID) *storeLoad {
+	if ss := cs.stores[storeID]; ss != nil {
+		return &ss.storeLoad
+	}
 	return nil
 }
 
-func (cs *clusterState) getNodeReportedLoad(roachpb.NodeID) *nodeLoad {
-	// TODO(sumeer):
+func (cs *clusterState) getNodeReportedLoad(nodeID roachpb.NodeID) *nodeLoad {
+	if ns := cs.nodes[nodeID]; ns != nil {
+		return &ns.nodeLoad
+	}
 	return nil
 }
 
@@ -554,8 +982,17 @@ func (cs *clusterState) getNodeReportedLoad(roachpb.NodeID) *nodeLoad {
 // causing it to be overloaded (or the node to be overloaded). It does not
 // change any state between the call and return.
 func (cs *clusterState) canAddLoad(ss *storeState, delta loadVector, means *meansForStoreSet) bool {
-	// TODO(sumeer):
-	return false
+	msl := &means.storeLoad
+	for i := range delta {
+		l := ss.adjusted.load[i] + delta[i]
+		if loadSummaryForDimension(l, ss.capacity[i], msl.load[i], msl.util[i]) < loadNormal {
+			return false
+		}
+	}
+	ns := cs.nodes[ss.NodeID]
+	mnl := &means.nodeLoad
+	return loadSummaryForDimension(
+		ns.adjustedCPU+delta[cpu], ns.capacityCPU, mnl.loadCPU, mnl.utilCPU) >= loadNormal
 }
 
 func (cs *clusterState) computeLoadSummary(
@@ -163,13 +163,21 @@ func (cm *constraintMatcher) getMatchedSetForConstraint(c internedConstraint) *m
 }
 
 // constrainStoresForConjunction populates storeSet with the stores matching
-// the given conjunction of constraints.
+// the given conjunction of constraints. An empty conjunction is satisfied by
+// all stores, consistent with storeMatches.
 //
 // TODO(sumeer): make storeIDPostingList a struct and use a sync.Pool.
 func (cm *constraintMatcher) constrainStoresForConjunction(
 	constraints []internedConstraint, storeSet *storeIDPostingList,
 ) {
 	*storeSet = (*storeSet)[:0]
+	if len(constraints) == 0 {
+		for storeID := range cm.stores {
+			*storeSet = append(*storeSet, storeID)
+		}
+		*storeSet = makeStoreIDPostingList(*storeSet)
+		return
+	}
 	for i := range constraints {
 		matchedSet := cm.getMatchedSetForConstraint(constraints[i])
 		if len(matchedSet.storeIDPostingList) == 0 {
@@ -173,7 +173,8 @@ type meanStoreLoad struct {
 	secondaryLoad secondaryLoadVector
 }
 
-// The mean node load for a set of nodeLoad.
+// The mean node load for a set of nodeLoad. If the capacity of some node is
+// unknownCapacity, capacityCPU is unknownCapacity and utilCPU is 0.
 type meanNodeLoad struct {
 	loadCPU     loadValue
 	capacityCPU loadValue
@@ -336,6 +337,10 @@ func (mm *meansMemo) getMeans(expr constraintsDisj) *meansForStoreSet {
 			mm.scratchNodes[sload.NodeID] = mm.loadInfoProvider.getNodeReportedLoad(nodeID)
 		}
 	}
+	if n == 0 {
+		// No store satisfies the expression, so there is no meaningful mean.
+		return means
+	}
 	for i := range means.storeLoad.load {
 		if means.storeLoad.capacity[i] != parentCapacity {
 			means.storeLoad.util[i] =
@@ -353,12 +358,20 @@ func (mm *meansMemo) getMeans(expr constraintsDisj) *meansForStoreSet {
 	n = len(mm.scratchNodes)
 	for _, nl := range mm.scratchNodes {
 		means.nodeLoad.loadCPU += nl.reportedCPU
-		means.nodeLoad.capacityCPU += nl.capacityCPU
+		// If the capacity of any node is unknown, the mean capacity and
+		// utilization are unknown too.
+		if nl.capacityCPU == unknownCapacity {
+			means.nodeLoad.capacityCPU = unknownCapacity
+		} else if means.nodeLoad.capacityCPU != unknownCapacity {
+			means.nodeLoad.capacityCPU += nl.capacityCPU
+		}
+	}
+	if means.nodeLoad.capacityCPU != unknownCapacity {
+		means.nodeLoad.utilCPU =
+			float64(means.nodeLoad.loadCPU) / float64(means.nodeLoad.capacityCPU)
+		means.nodeLoad.capacityCPU /= loadValue(n)
 	}
-	means.nodeLoad.utilCPU =
-		float64(means.nodeLoad.loadCPU) / float64(means.nodeLoad.capacityCPU)
 	means.nodeLoad.loadCPU /= loadValue(n)
-	means.nodeLoad.capacityCPU /= loadValue(n)
 
 	return means
 }
@@ -109,6 +109,9 @@ func TestMeansMemo(t *testing.T) {
 					reportedCPU: loadValue(cpuLoad),
 					capacityCPU: loadValue(cpuCapacity),
 				}
+				if cpuCapacity < 0 {
+					nLoad.capacityCPU = unknownCapacity
+				}
 				loadProvider.nloads[nLoad.nodeID] = nLoad
 				return ""
 
@@ -147,8 +150,12 @@ func TestMeansMemo(t *testing.T) {
 						capStr, mss.storeLoad.util[i])
 				}
 				fmt.Fprintf(&b, "\n   secondary-load: %d\n", mss.storeLoad.secondaryLoad)
-				fmt.Fprintf(&b, "node-mean cpu (load,cap,util): (%d, %d, %.2f)\n", mss.nodeLoad.loadCPU,
-					mss.nodeLoad.capacityCPU, mss.nodeLoad.utilCPU)
+				nodeCapStr := fmt.Sprintf("%d", mss.nodeLoad.capacityCPU)
+				if mss.nodeLoad.capacityCPU == unknownCapacity {
+					nodeCapStr = "unknown"
+				}
+				fmt.Fprintf(&b, "node-mean cpu (load,cap,util): (%d, %s, %.2f)\n", mss.nodeLoad.loadCPU,
+					nodeCapStr, mss.nodeLoad.utilCPU)
 				return b.String()
 
 			case "get-store-summary":
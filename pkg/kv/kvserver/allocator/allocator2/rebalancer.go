@@ -0,0 +1,298 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package allocator2
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// Rebalancer is an adapter layer that allows a store to use the allocator
+// for multi-metric load-based rebalancing of the ranges for which it is the
+// leaseholder, without constructing the messages consumed by the Allocator
+// interface. Each call to ComputeChanges provides the full state known to the
+// caller, which is typically derived from the store pool and the local
+// replicas. The allocator state persists across calls, so that pending
+// changes are remembered and the load is adjusted for them.
+//
+// A Rebalancer is not thread-safe.
+type Rebalancer struct {
+	localStoreID roachpb.StoreID
+	a            *allocatorState
+	// nodes are the nodes known to the allocator.
+	nodes map[roachpb.NodeID]struct{}
+}
+
+// StoreInfo is the information about a store provided to the Rebalancer.
+type StoreInfo struct {
+	Desc roachpb.StoreDescriptor
+	Load StoreLoad
+	// Suspect is set if the store should not receive leases or replicas, e.g.
+	// because its node is suspect or draining.
+	Suspect bool
+}
+
+// StoreLoad is the load of a store.
+type StoreLoad struct {
+	// CPU is the cpu used by the store, in nanos per second. The node cpu is
+	// the sum of the cpu of its stores.
+	CPU            int64
+	WriteBandwidth int64
+	ByteSize       int64
+	// ByteCapacity is the capacity of the store in bytes, or 0 if unknown.
+	ByteCapacity int64
+	LeaseCount   int64
+}
+
+// RangeInfo is the information about a range for which the local store is
+// the leaseholder.
+type RangeInfo struct {
+	Desc *roachpb.RangeDescriptor
+	Conf roachpb.SpanConfig
+	Load RangeLoad
+	// LaggingReplicas are the voters that have fallen behind, and should not
+	// receive the lease.
+	LaggingReplicas []roachpb.ReplicaID
+}
+
+// RangeLoad is the load of the leaseholder replica of a range.
+type RangeLoad struct {
+	CPU int64
+	// RaftCPU is the part of CPU that is also incurred by followers, so it
+	// does not move with the lease. RaftCPU <= CPU.
+	RaftCPU        int64
+	WriteBandwidth int64
+	ByteSize       int64
+}
+
+// RebalanceChange is a change proposed by the Rebalancer. It is either a
+// lease transfer to LeaseTarget, or a relocation of the range to
+// VoterTargets and NonVoterTargets, where the lease is transferred to the
+// first voter.
+type RebalanceChange struct {
+	RangeID         roachpb.RangeID
+	LeaseTarget     roachpb.ReplicaDescriptor
+	VoterTargets    []roachpb.ReplicationTarget
+	NonVoterTargets []roachpb.ReplicationTarget
+
+	changes []pendingReplicaChange
+}
+
+// IsLeaseTransfer returns true if the change is a lease transfer.
+func (rc *RebalanceChange) IsLeaseTransfer() bool {
+	return len(rc.VoterTargets) == 0
+}
+
+// NewRebalancer returns a Rebalancer for the local store.
+func NewRebalancer(localStoreID roachpb.StoreID) *Rebalancer {
+	return &Rebalancer{
+		localStoreID: localStoreID,
+		a:            newAllocatorState(),
+		nodes:        map[roachpb.NodeID]struct{}{},
+	}
+}
+
+// ComputeChanges updates the allocator state with the provided stores and
+// ranges, and returns the changes that should be made to reduce the load on
+// the local store. Unless opts.DryRun is set, the returned changes are
+// remembered as pending, and the caller should report their outcome via
+// AdjustChangeDisposition.
+func (r *Rebalancer) ComputeChanges(
+	now time.Time, stores []StoreInfo, ranges []RangeInfo, opts ChangeOptions,
+) []RebalanceChange {
+	nodeStores := map[roachpb.NodeID][]*StoreInfo{}
+	for i := range stores {
+		_ = r.a.SetStore(stores[i].Desc)
+		nodeID := stores[i].Desc.Node.NodeID
+		nodeStores[nodeID] = append(nodeStores[nodeID], &stores[i])
+	}
+	for nodeID := range r.nodes {
+		if _, ok := nodeStores[nodeID]; !ok {
+			_ = r.a.RemoveNodeAndStores(nodeID)
+			delete(r.nodes, nodeID)
+		}
+	}
+	nodeIDs := make([]roachpb.NodeID, 0, len(nodeStores))
+	for nodeID, ss := range nodeStores {
+		r.nodes[nodeID] = struct{}{}
+		nodeIDs = append(nodeIDs, nodeID)
+		fd := fdOK
+		for _, s := range ss {
+			if s.Suspect {
+				fd = fdSuspect
+			}
+		}
+		_ = r.a.UpdateFailureDetectionSummary(nodeID, fd)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return nodeIDs[i] < nodeIDs[j]
+	})
+
+	descs := map[roachpb.RangeID]*roachpb.RangeDescriptor{}
+	leaseholderMsg := storeLeaseholderMsg{StoreID: r.localStoreID}
+	var topKRanges []struct {
+		roachpb.RangeID
+		rangeLoad
+	}
+	for i := range ranges {
+		rm, ok := r.makeRangeMsg(&ranges[i])
+		if !ok {
+			continue
+		}
+		descs[rm.RangeID] = ranges[i].Desc
+		leaseholderMsg.ranges = append(leaseholderMsg.ranges, rm)
+		l := ranges[i].Load
+		topKRanges = append(topKRanges, struct {
+			roachpb.RangeID
+			rangeLoad
+		}{
+			RangeID: rm.RangeID,
+			rangeLoad: rangeLoad{
+				load:    loadVector{loadValue(l.CPU), loadValue(l.WriteBandwidth), loadValue(l.ByteSize)},
+				raftCPU: loadValue(l.RaftCPU),
+			},
+		})
+	}
+
+	for _, nodeID := range nodeIDs {
+		resp := nodeLoadResponse{
+			lastLoadSeqNum: -1,
+			nodeLoad: nodeLoad{
+				nodeID:      nodeID,
+				capacityCPU: unknownCapacity,
+			},
+		}
+		for _, s := range nodeStores[nodeID] {
+			resp.reportedCPU += loadValue(s.Load.CPU)
+			msg := storeLoadMsg{
+				StoreID: s.Desc.StoreID,
+				load: loadVector{
+					loadValue(s.Load.CPU), loadValue(s.Load.WriteBandwidth), loadValue(s.Load.ByteSize)},
+				capacity:      loadVector{parentCapacity, unknownCapacity, unknownCapacity},
+				secondaryLoad: secondaryLoadVector{loadValue(s.Load.LeaseCount)},
+			}
+			if s.Load.ByteCapacity > 0 {
+				msg.capacity[byteSize] = loadValue(s.Load.ByteCapacity)
+			}
+			if s.Desc.StoreID == r.localStoreID {
+				msg.topKRanges = topKRanges
+				resp.leaseholderStores = []storeLeaseholderMsg{leaseholderMsg}
+			}
+			resp.stores = append(resp.stores, msg)
+		}
+		_ = r.a.ProcessNodeLoadResponse(&resp, now)
+	}
+
+	pendingChanges := r.a.ComputeChanges(opts, now)
+	var rangeIDs []roachpb.RangeID
+	rangeChanges := map[roachpb.RangeID][]pendingReplicaChange{}
+	for _, c := range pendingChanges {
+		if _, ok := rangeChanges[c.rangeID]; !ok {
+			rangeIDs = append(rangeIDs, c.rangeID)
+		}
+		rangeChanges[c.rangeID] = append(rangeChanges[c.rangeID], *c)
+	}
+	var changes []RebalanceChange
+	for _, rangeID := range rangeIDs {
+		changes = append(changes, r.makeRebalanceChange(descs[rangeID], rangeChanges[rangeID]))
+	}
+	return changes
+}
+
+// AdjustChangeDisposition informs the Rebalancer whether a change returned
+// by ComputeChanges was successfully enacted.
+func (r *Rebalancer) AdjustChangeDisposition(
+	now time.Time, change RebalanceChange, success bool,
+) {
+	_ = r.a.AdjustPendingChangesDisposition(change.changes, success, now)
+}
+
+// makeRangeMsg returns the rangeMsg for a range for which the local store is
+// the leaseholder. Ranges that are undergoing a change, i.e., with learners
+// or in a joint configuration, are not considered for rebalancing, and false
+// is returned.
+func (r *Rebalancer) makeRangeMsg(ri *RangeInfo) (rangeMsg, bool) {
+	desc := ri.Desc
+	rm := rangeMsg{
+		RangeID: desc.RangeID,
+		start:   desc.StartKey.AsRawKey(),
+		end:     desc.EndKey.AsRawKey(),
+		conf:    ri.Conf,
+	}
+	for _, rd := range desc.Replicas().Descriptors() {
+		if rd.Type != roachpb.VOTER_FULL && rd.Type != roachpb.NON_VOTER {
+			return rangeMsg{}, false
+		}
+		state := replicaState{
+			replicaIDAndType: replicaIDAndType{
+				ReplicaID: rd.ReplicaID,
+				replicaType: replicaType{
+					replicaType:   rd.Type,
+					isLeaseholder: rd.StoreID == r.localStoreID,
+				},
+			},
+		}
+		for _, id := range ri.LaggingReplicas {
+			if id == rd.ReplicaID {
+				state.voterIsLagging = true
+			}
+		}
+		rm.replicas = append(rm.replicas, storeIDAndReplicaState{
+			StoreID:      rd.StoreID,
+			replicaState: state,
+		})
+	}
+	return rm, true
+}
+
+func (r *Rebalancer) makeRebalanceChange(
+	desc *roachpb.RangeDescriptor, changes []pendingReplicaChange,
+) RebalanceChange {
+	rc := RebalanceChange{
+		RangeID: desc.RangeID,
+		changes: changes,
+	}
+	var addStoreID, removeStoreID roachpb.StoreID
+	for _, c := range changes {
+		switch c.next.ReplicaID {
+		case unknownReplicaID:
+			addStoreID = c.storeID
+		case noReplicaID:
+			removeStoreID = c.storeID
+		default:
+			if c.next.isLeaseholder {
+				rc.LeaseTarget, _ = desc.GetReplicaDescriptor(c.storeID)
+			}
+		}
+	}
+	if addStoreID == 0 {
+		return rc
+	}
+	// The added voter is first, so that it receives the lease.
+	rc.VoterTargets = append(rc.VoterTargets, roachpb.ReplicationTarget{
+		NodeID:  r.a.cs.stores[addStoreID].NodeID,
+		StoreID: addStoreID,
+	})
+	for _, rd := range desc.Replicas().Descriptors() {
+		if rd.StoreID == removeStoreID {
+			continue
+		}
+		target := roachpb.ReplicationTarget{NodeID: rd.NodeID, StoreID: rd.StoreID}
+		if rd.Type == roachpb.NON_VOTER {
+			rc.NonVoterTargets = append(rc.NonVoterTargets, target)
+		} else {
+			rc.VoterTargets = append(rc.VoterTargets, target)
+		}
+	}
+	return rc
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package allocator2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/datadriven"
+	"github.com/stretchr/testify/require"
+)
+
+func parseStoreIDs(t *testing.T, val string) []roachpb.StoreID {
+	var storeIDs []roachpb.StoreID
+	for _, v := range strings.Split(val, ",") {
+		storeID, err := strconv.Atoi(strings.TrimSpace(v))
+		require.NoError(t, err)
+		storeIDs = append(storeIDs, roachpb.StoreID(storeID))
+	}
+	return storeIDs
+}
+
+func printTargets(b *strings.Builder, targets []roachpb.ReplicationTarget) {
+	fmt.Fprintf(b, "[")
+	for i, target := range targets {
+		if i > 0 {
+			fmt.Fprintf(b, " ")
+		}
+		fmt.Fprintf(b, "s%d", target.StoreID)
+	}
+	fmt.Fprintf(b, "]")
+}
+
+func TestRebalancer(t *testing.T) {
+	var r *Rebalancer
+	var stores map[roachpb.StoreID]*StoreInfo
+	var ranges map[roachpb.RangeID]*RangeInfo
+	// proposed is the latest change proposed for each range.
+	var proposed map[roachpb.RangeID]RebalanceChange
+	now := time.Unix(0, 0)
+	reset := func() {
+		r = nil
+		stores = map[roachpb.StoreID]*StoreInfo{}
+		ranges = map[roachpb.RangeID]*RangeInfo{}
+		proposed = map[roachpb.RangeID]RebalanceChange{}
+	}
+	reset()
+	datadriven.RunTest(t, "testdata/rebalancer",
+		func(t *testing.T, d *datadriven.TestData) string {
+			switch d.Cmd {
+			case "reset":
+				reset()
+				return ""
+
+			case "store":
+				desc := parseStoreDescriptor(t, d)
+				stores[desc.StoreID] = &StoreInfo{Desc: desc}
+				return ""
+
+			case "store-load":
+				var storeID int
+				d.ScanArgs(t, "store-id", &storeID)
+				s, ok := stores[roachpb.StoreID(storeID)]
+				require.True(t, ok)
+				d.ScanArgs(t, "cpu", &s.Load.CPU)
+				d.ScanArgs(t, "write-bw", &s.Load.WriteBandwidth)
+				d.ScanArgs(t, "bytes", &s.Load.ByteSize)
+				d.ScanArgs(t, "leases", &s.Load.LeaseCount)
+				if d.HasArg("byte-capacity") {
+					d.ScanArgs(t, "byte-capacity", &s.Load.ByteCapacity)
+				}
+				s.Suspect = false
+				if d.HasArg("suspect") {
+					d.ScanArgs(t, "suspect", &s.Suspect)
+				}
+				return ""
+
+			case "range":
+				var rangeID int
+				d.ScanArgs(t, "range-id", &rangeID)
+				var voters string
+				d.ScanArgs(t, "voters", &voters)
+				desc := &roachpb.RangeDescriptor{RangeID: roachpb.RangeID(rangeID)}
+				addReplicas := func(val string, typ roachpb.ReplicaType) {
+					for _, storeID := range parseStoreIDs(t, val) {
+						s, ok := stores[storeID]
+						require.True(t, ok)
+						desc.InternalReplicas = append(desc.InternalReplicas, roachpb.ReplicaDescriptor{
+							NodeID:    s.Desc.Node.NodeID,
+							StoreID:   storeID,
+							ReplicaID: roachpb.ReplicaID(len(desc.InternalReplicas) + 1),
+							Type:      typ,
+						})
+					}
+				}
+				addReplicas(voters, roachpb.VOTER_FULL)
+				if d.HasArg("non-voters") {
+					var nonVoters string
+					d.ScanArgs(t, "non-voters", &nonVoters)
+					addReplicas(nonVoters, roachpb.NON_VOTER)
+				}
+				ri := &RangeInfo{
+					Desc: desc,
+					Conf: parseSpanConfig(t, d),
+				}
+				d.ScanArgs(t, "cpu", &ri.Load.CPU)
+				d.ScanArgs(t, "raft-cpu", &ri.Load.RaftCPU)
+				d.ScanArgs(t, "write-bw", &ri.Load.WriteBandwidth)
+				d.ScanArgs(t, "bytes", &ri.Load.ByteSize)
+				if d.HasArg("lagging") {
+					var lagging string
+					d.ScanArgs(t, "lagging", &lagging)
+					for _, storeID := range parseStoreIDs(t, lagging) {
+						rd, ok := desc.GetReplicaDescriptor(storeID)
+						require.True(t, ok)
+						ri.LaggingReplicas = append(ri.LaggingReplicas, rd.ReplicaID)
+					}
+				}
+				ranges[desc.RangeID] = ri
+				return ""
+
+			case "compute-changes":
+				var localStoreID int
+				d.ScanArgs(t, "local-store-id", &localStoreID)
+				if r == nil {
+					r = NewRebalancer(roachpb.StoreID(localStoreID))
+				}
+				require.Equal(t, roachpb.StoreID(localStoreID), r.localStoreID)
+				var opts ChangeOptions
+				if d.HasArg("dry-run") {
+					d.ScanArgs(t, "dry-run", &opts.DryRun)
+				}
+				if d.HasArg("lease-transfers-only") {
+					d.ScanArgs(t, "lease-transfers-only", &opts.LeaseTransfersOnly)
+				}
+				var storeInfos []StoreInfo
+				for _, s := range stores {
+					storeInfos = append(storeInfos, *s)
+				}
+				sort.Slice(storeInfos, func(i, j int) bool {
+					return storeInfos[i].Desc.StoreID < storeInfos[j].Desc.StoreID
+				})
+				var rangeInfos []RangeInfo
+				for _, ri := range ranges {
+					rangeInfos = append(rangeInfos, *ri)
+				}
+				sort.Slice(rangeInfos, func(i, j int) bool {
+					return rangeInfos[i].Desc.RangeID < rangeInfos[j].Desc.RangeID
+				})
+				changes := r.ComputeChanges(now, storeInfos, rangeInfos, opts)
+				var b strings.Builder
+				for i := range changes {
+					c := &changes[i]
+					proposed[c.RangeID] = *c
+					if c.IsLeaseTransfer() {
+						fmt.Fprintf(&b, "r%d: transfer lease to s%d\n", c.RangeID, c.LeaseTarget.StoreID)
+						continue
+					}
+					fmt.Fprintf(&b, "r%d: relocate voters=", c.RangeID)
+					printTargets(&b, c.VoterTargets)
+					fmt.Fprintf(&b, " non-voters=")
+					printTargets(&b, c.NonVoterTargets)
+					fmt.Fprintf(&b, "\n")
+				}
+				return b.String()
+
+			case "adjust-disposition":
+				var rangeID int
+				d.ScanArgs(t, "range-id", &rangeID)
+				var success bool
+				d.ScanArgs(t, "success", &success)
+				c, ok := proposed[roachpb.RangeID(rangeID)]
+				if !ok {
+					return fmt.Sprintf("no change for r%d", rangeID)
+				}
+				r.AdjustChangeDisposition(now, c, success)
+				delete(proposed, c.RangeID)
+				return ""
+
+			case "advance-time":
+				var dur string
+				d.ScanArgs(t, "duration", &dur)
+				delta, err := time.ParseDuration(dur)
+				require.NoError(t, err)
+				now = now.Add(delta)
+				return ""
+
+			default:
+				return fmt.Sprintf("unknown command: %s", d.Cmd)
+			}
+		})
+}
@@ -83,6 +83,16 @@ type PlannerOptions struct {
 	Scatter bool
 	// CanTransferLease indicates whether the lease can be transferred.
 	CanTransferLease bool
+	// DisableRebalancing indicates that replica rebalancing is the
+	// responsibility of another component, so only repair actions should be
+	// planned. It is ignored when scattering.
+	DisableRebalancing bool
+}
+
+// rebalancingDisabled returns whether replica rebalancing should not be
+// planned with these options.
+func (opts PlannerOptions) rebalancingDisabled() bool {
+	return opts.DisableRebalancing && !opts.Scatter
 }
 
 // LeaseCheckReplica contains methods that may be used to check a replica's
@@ -167,7 +177,7 @@ func (rp ReplicaPlanner) ShouldPlanChange(
 
 	voterReplicas := desc.Replicas().VoterDescriptors()
 	nonVoterReplicas := desc.Replicas().NonVoterDescriptors()
-	if !rp.knobs.DisableReplicaRebalancing {
+	if !rp.knobs.DisableReplicaRebalancing && !opts.rebalancingDisabled() {
 		scorerOptions := rp.allocator.ScorerOptions(ctx)
 		rangeUsageInfo := repl.RangeUsageInfo()
 		_, _, _, ok := rp.allocator.RebalanceVoter(
@@ -323,7 +333,7 @@ func (rp ReplicaPlanner) PlanOneChange(
 			voterReplicas,
 			nonVoterReplicas,
 			allocatorPrio,
-			opts.Scatter,
+			opts,
 		)
 	case allocatorimpl.AllocatorFinalizeAtomicReplicationChange, allocatorimpl.AllocatorRemoveLearner:
 		op = AllocationFinalizeAtomicReplicationOp{}
@@ -778,17 +788,17 @@ func (rp ReplicaPlanner) considerRebalance(
 	conf *roachpb.SpanConfig,
 	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
 	allocatorPrio float64,
-	scatter bool,
+	opts PlannerOptions,
 ) (op AllocationOp, stats ReplicateStats, _ error) {
 	// When replica rebalancing is not enabled return early.
-	if rp.knobs.DisableReplicaRebalancing {
+	if rp.knobs.DisableReplicaRebalancing || opts.rebalancingDisabled() {
 		return nil, stats, nil
 	}
 
 	rebalanceTargetType := allocatorimpl.VoterTarget
 
 	scorerOpts := allocatorimpl.ScorerOptions(rp.allocator.ScorerOptions(ctx))
-	if scatter {
+	if opts.Scatter {
 		scorerOpts = rp.allocator.ScorerOptionsForScatter(ctx)
 	}
 	rangeUsageInfo := repl.RangeUsageInfo()
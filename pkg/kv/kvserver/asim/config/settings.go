@@ -115,6 +115,10 @@ type SimulationSettings struct {
 	// rebalancer would care to reconcile (via lease or replica rebalancing) between
 	// any two stores.
 	LBMinRequiredQPSDiff float64
+	// MultiMetricRebalancing controls whether the store rebalancer uses the
+	// multi-metric allocator, in which case the replicate queue does not
+	// rebalance replicas. It maps to kvserver.MultiMetricRebalancingEnabled.
+	MultiMetricRebalancing bool
 }
 
 // DefaultSimulationSettings returns a set of default settings for simulation.
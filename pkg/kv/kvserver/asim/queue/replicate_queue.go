@@ -80,7 +80,7 @@ func (rq *replicateQueue) MaybeAdd(ctx context.Context, replica state.Replica, s
 		repl,
 		desc,
 		conf,
-		plan.PlannerOptions{},
+		plan.PlannerOptions{DisableRebalancing: rq.settings.MultiMetricRebalancing},
 	)
 
 	if !shouldPlanChange {
@@ -138,7 +138,8 @@ func (rq *replicateQueue) Tick(ctx context.Context, tick time.Time, s state.Stat
 		if !repl.OwnsValidLease(ctx, hlc.ClockTimestamp{}) {
 			continue
 		}
-		change, err := rq.planner.PlanOneChange(ctx, repl, desc, conf, plan.PlannerOptions{})
+		change, err := rq.planner.PlanOneChange(ctx, repl, desc, conf,
+			plan.PlannerOptions{DisableRebalancing: rq.settings.MultiMetricRebalancing})
 		if err != nil {
 			log.Errorf(ctx, "error planning change %s", err.Error())
 			continue
@@ -0,0 +1,156 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scenario
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/history"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/errors"
+)
+
+// OutputFormat is the format the time series of a simulation run are written
+// in.
+type OutputFormat int
+
+const (
+	// OutputCSV writes a header row followed by one row per store and metrics
+	// tick.
+	OutputCSV OutputFormat = iota
+	// OutputJSON writes one JSON object per store and metrics tick, separated
+	// by newlines.
+	OutputJSON
+)
+
+// ParseOutputFormat returns the output format with the name given.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch s {
+	case "csv":
+		return OutputCSV, nil
+	case "json":
+		return OutputJSON, nil
+	default:
+		return 0, errors.Newf("unknown output format %q, expected one of: csv, json", s)
+	}
+}
+
+// Sample is a single point of the time series emitted for a store.
+type Sample struct {
+	// ElapsedSeconds is the simulated time since the start of the run.
+	ElapsedSeconds     float64 `json:"elapsed_seconds"`
+	StoreID            int64   `json:"store_id"`
+	QPS                int64   `json:"qps"`
+	WriteKeys          int64   `json:"write_keys"`
+	WriteBytes         int64   `json:"write_bytes"`
+	ReadKeys           int64   `json:"read_keys"`
+	ReadBytes          int64   `json:"read_bytes"`
+	Replicas           int64   `json:"replicas"`
+	Leases             int64   `json:"leases"`
+	LeaseTransfers     int64   `json:"lease_transfers"`
+	Rebalances         int64   `json:"rebalances"`
+	RebalanceSentBytes int64   `json:"rebalance_sent_bytes"`
+	RebalanceRcvdBytes int64   `json:"rebalance_rcvd_bytes"`
+	RangeSplits        int64   `json:"range_splits"`
+	DiskFractionUsed   float64 `json:"disk_fraction_used"`
+}
+
+var csvHeader = []string{
+	"elapsed_seconds", "store_id", "qps", "write_keys", "write_bytes",
+	"read_keys", "read_bytes", "replicas", "leases", "lease_transfers",
+	"rebalances", "rebalance_sent_bytes", "rebalance_rcvd_bytes",
+	"range_splits", "disk_fraction_used",
+}
+
+func (s Sample) csvRecord() []string {
+	i := func(v int64) string { return strconv.FormatInt(v, 10) }
+	return []string{
+		strconv.FormatFloat(s.ElapsedSeconds, 'f', -1, 64),
+		i(s.StoreID), i(s.QPS), i(s.WriteKeys), i(s.WriteBytes),
+		i(s.ReadKeys), i(s.ReadBytes), i(s.Replicas), i(s.Leases),
+		i(s.LeaseTransfers), i(s.Rebalances), i(s.RebalanceSentBytes),
+		i(s.RebalanceRcvdBytes), i(s.RangeSplits),
+		strconv.FormatFloat(s.DiskFractionUsed, 'f', 4, 64),
+	}
+}
+
+// Samples flattens the store metrics recorded in the history into samples,
+// ordered by tick and then by store.
+func Samples(h history.History) []Sample {
+	if len(h.Recorded) == 0 {
+		return nil
+	}
+	var start time.Time
+	if len(h.Recorded[0]) > 0 {
+		start = h.Recorded[0][0].Tick
+	}
+	var samples []Sample
+	for _, tick := range h.Recorded {
+		for _, sm := range tick {
+			samples = append(samples, makeSample(start, sm))
+		}
+	}
+	return samples
+}
+
+func makeSample(start time.Time, sm metrics.StoreMetrics) Sample {
+	return Sample{
+		ElapsedSeconds:     sm.Tick.Sub(start).Seconds(),
+		StoreID:            sm.StoreID,
+		QPS:                sm.QPS,
+		WriteKeys:          sm.WriteKeys,
+		WriteBytes:         sm.WriteBytes,
+		ReadKeys:           sm.ReadKeys,
+		ReadBytes:          sm.ReadBytes,
+		Replicas:           sm.Replicas,
+		Leases:             sm.Leases,
+		LeaseTransfers:     sm.LeaseTransfers,
+		Rebalances:         sm.Rebalances,
+		RebalanceSentBytes: sm.RebalanceSentBytes,
+		RebalanceRcvdBytes: sm.RebalanceRcvdBytes,
+		RangeSplits:        sm.RangeSplits,
+		DiskFractionUsed:   sm.DiskFractionUsed,
+	}
+}
+
+// WriteHistory writes the per-store time series recorded in the history to w
+// in the format given.
+func WriteHistory(w io.Writer, h history.History, format OutputFormat) error {
+	samples := Samples(h)
+	switch format {
+	case OutputCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, s := range samples {
+			if err := cw.Write(s.csvRecord()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		for _, s := range samples {
+			if err := enc.Encode(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.AssertionFailedf("unknown output format %d", format)
+	}
+}
@@ -0,0 +1,500 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package scenario provides a declarative, file based description of an
+// allocation simulation. A scenario describes the cluster topology, the
+// initial ranges, the workload and a list of events (e.g. adding nodes,
+// failing nodes or changing zone configs) which are scheduled during the
+// simulation. Scenarios are written in YAML, e.g.
+//
+//	duration: 30m
+//	seed: 42
+//	regions:
+//	- name: us-east
+//	  zones:
+//	  - {name: us-east-1, nodes: 3}
+//	  - {name: us-east-2, nodes: 3}
+//	ranges:
+//	  count: 500
+//	  replication_factor: 3
+//	workload:
+//	- rate: 5000
+//	  read_ratio: 0.95
+//	events:
+//	- at: 10m
+//	  type: add_node
+//	  locality: region=us-east,zone=us-east-1
+//	- at: 20m
+//	  type: set_liveness
+//	  node: 2
+//	  liveness: dead
+//
+// A scenario is turned into a runnable simulation via Scenario.Simulator.
+package scenario
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/event"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/gen"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultDuration          = 30 * time.Minute
+	defaultDiskCapacityGB    = 1024
+	defaultKeySpace          = 10000
+	defaultReplicationFactor = 3
+	defaultMinBlockSize      = 1
+	defaultMaxBlockSize      = 1
+)
+
+// Scenario is the declarative description of a simulation.
+type Scenario struct {
+	// Duration is the simulated duration of the run.
+	Duration time.Duration `yaml:"duration"`
+	// Seed seeds the random number generators used by the simulation.
+	Seed int64 `yaml:"seed"`
+	// DiskCapacityGB is the disk capacity of every store in the initial
+	// cluster.
+	DiskCapacityGB int `yaml:"disk_capacity_gb"`
+	// Settings overrides the default simulation settings.
+	Settings Settings `yaml:"settings"`
+	// Regions describes the initial topology of the cluster. Every node is
+	// given the locality region=<region>,zone=<zone>.
+	Regions []Region `yaml:"regions"`
+	// Ranges describes the initial ranges and their placement.
+	Ranges Ranges `yaml:"ranges"`
+	// Workload is the list of workloads which run concurrently for the whole
+	// simulation.
+	Workload []Workload `yaml:"workload"`
+	// ZoneConfigs are applied to their span at the start of the simulation,
+	// or at the time given.
+	ZoneConfigs []ZoneConfig `yaml:"zone_configs"`
+	// Events are mutations applied to the cluster at the time given.
+	Events []Event `yaml:"events"`
+}
+
+// Settings contains the subset of the simulation settings which may be
+// overridden by a scenario. Unset fields retain their default value.
+type Settings struct {
+	TickInterval           time.Duration `yaml:"tick_interval"`
+	MetricsInterval        time.Duration `yaml:"metrics_interval"`
+	RebalancingMode        string        `yaml:"rebalancing_mode"`
+	MultiMetricRebalancing bool          `yaml:"multi_metric_rebalancing"`
+	SplitQPSThreshold      float64       `yaml:"split_qps_threshold"`
+}
+
+// Region is a named region containing one or more zones.
+type Region struct {
+	Name  string `yaml:"name"`
+	Zones []Zone `yaml:"zones"`
+}
+
+// Zone is a named availability zone containing nodes.
+type Zone struct {
+	Name          string `yaml:"name"`
+	Nodes         int    `yaml:"nodes"`
+	StoresPerNode int    `yaml:"stores_per_node"`
+}
+
+// Ranges describes the initial range layout.
+type Ranges struct {
+	Count             int    `yaml:"count"`
+	KeySpace          int    `yaml:"key_space"`
+	ReplicationFactor int    `yaml:"replication_factor"`
+	Placement         string `yaml:"placement"`
+	Bytes             int64  `yaml:"bytes"`
+}
+
+// Workload describes a single key-value workload.
+type Workload struct {
+	Rate         float64 `yaml:"rate"`
+	ReadRatio    float64 `yaml:"read_ratio"`
+	SkewedAccess bool    `yaml:"skewed_access"`
+	MinBlockSize int     `yaml:"min_block_size"`
+	MaxBlockSize int     `yaml:"max_block_size"`
+	// MinKey and MaxKey bound the keys accessed by the workload. When unset,
+	// the workload accesses the whole range key space.
+	MinKey int64 `yaml:"min_key"`
+	MaxKey int64 `yaml:"max_key"`
+}
+
+// ZoneConfig applies a zone config, in the same YAML format accepted by
+// CONFIGURE ZONE, to the key span [StartKey, EndKey).
+type ZoneConfig struct {
+	At       time.Duration     `yaml:"at"`
+	StartKey int64             `yaml:"start_key"`
+	EndKey   int64             `yaml:"end_key"`
+	Config   zonepb.ZoneConfig `yaml:"config"`
+}
+
+// Event is a cluster mutation scheduled during the simulation. The fields
+// which apply depend on the event type:
+//
+//   - add_node: stores, locality
+//   - set_liveness: node, liveness
+//   - set_locality: node, locality
+//   - set_capacity: store, capacity, available
+type Event struct {
+	At        time.Duration `yaml:"at"`
+	Type      string        `yaml:"type"`
+	Node      int           `yaml:"node"`
+	Store     int           `yaml:"store"`
+	Stores    int           `yaml:"stores"`
+	Locality  string        `yaml:"locality"`
+	Liveness  string        `yaml:"liveness"`
+	Capacity  *int64        `yaml:"capacity"`
+	Available *int64        `yaml:"available"`
+}
+
+// Parse parses and validates a YAML scenario. Unset fields are populated with
+// their default values.
+func Parse(data []byte) (*Scenario, error) {
+	s := &Scenario{}
+	if err := yaml.UnmarshalStrict(data, s); err != nil {
+		return nil, errors.Wrap(err, "parsing scenario")
+	}
+	s.setDefaults()
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Scenario) setDefaults() {
+	if s.Duration == 0 {
+		s.Duration = defaultDuration
+	}
+	if s.DiskCapacityGB == 0 {
+		s.DiskCapacityGB = defaultDiskCapacityGB
+	}
+	for i := range s.Regions {
+		for j := range s.Regions[i].Zones {
+			if s.Regions[i].Zones[j].StoresPerNode == 0 {
+				s.Regions[i].Zones[j].StoresPerNode = 1
+			}
+		}
+	}
+	if s.Ranges.KeySpace == 0 {
+		s.Ranges.KeySpace = defaultKeySpace
+	}
+	if s.Ranges.ReplicationFactor == 0 {
+		s.Ranges.ReplicationFactor = defaultReplicationFactor
+	}
+	if s.Ranges.Placement == "" {
+		s.Ranges.Placement = gen.Even.String()
+	}
+	for i := range s.Workload {
+		w := &s.Workload[i]
+		if w.MinBlockSize == 0 {
+			w.MinBlockSize = defaultMinBlockSize
+		}
+		if w.MaxBlockSize == 0 {
+			w.MaxBlockSize = defaultMaxBlockSize
+		}
+		if w.MaxKey == 0 {
+			w.MaxKey = int64(s.Ranges.KeySpace)
+		}
+	}
+	// Zone configs only declare the fields they override, the remaining fields
+	// are inherited from the default zone config.
+	defaultZone := zonepb.DefaultZoneConfig()
+	for i := range s.ZoneConfigs {
+		s.ZoneConfigs[i].Config.InheritFromParent(&defaultZone)
+	}
+	for i := range s.Events {
+		if s.Events[i].Type == "add_node" && s.Events[i].Stores == 0 {
+			s.Events[i].Stores = 1
+		}
+	}
+}
+
+func (s *Scenario) validate() error {
+	if s.Duration < 0 {
+		return errors.Newf("duration must be positive, found %s", s.Duration)
+	}
+	if len(s.Regions) == 0 {
+		return errors.New("scenario must declare at least one region")
+	}
+	nodes := 0
+	for _, r := range s.Regions {
+		if r.Name == "" {
+			return errors.New("region name must be set")
+		}
+		if len(r.Zones) == 0 {
+			return errors.Newf("region %s must declare at least one zone", r.Name)
+		}
+		for _, z := range r.Zones {
+			if z.Name == "" {
+				return errors.Newf("zone name must be set in region %s", r.Name)
+			}
+			if z.Nodes < 0 || z.StoresPerNode < 0 {
+				return errors.Newf("zone %s must have a non-negative node and store count", z.Name)
+			}
+			nodes += z.Nodes
+		}
+	}
+	if nodes == 0 {
+		return errors.New("scenario must declare at least one node")
+	}
+	switch s.Ranges.Placement {
+	case gen.Even.String(), gen.Skewed.String():
+	default:
+		return errors.Newf("unsupported range placement %q, expected one of: even, skewed",
+			s.Ranges.Placement)
+	}
+	if _, err := s.Settings.rebalancingMode(); err != nil {
+		return err
+	}
+	for i, w := range s.Workload {
+		if w.ReadRatio < 0 || w.ReadRatio > 1 {
+			return errors.Newf("workload %d: read_ratio must be in [0, 1], found %v", i, w.ReadRatio)
+		}
+		if w.MinBlockSize > w.MaxBlockSize {
+			return errors.Newf("workload %d: min_block_size exceeds max_block_size", i)
+		}
+		if w.MinKey >= w.MaxKey {
+			return errors.Newf("workload %d: min_key must be less than max_key", i)
+		}
+	}
+	for i, zc := range s.ZoneConfigs {
+		if zc.StartKey >= zc.EndKey {
+			return errors.Newf("zone config %d: start_key must be less than end_key", i)
+		}
+		if err := zc.Config.Validate(); err != nil {
+			return errors.Wrapf(err, "zone config %d", i)
+		}
+		if err := zc.Config.EnsureFullyHydrated(); err != nil {
+			return errors.Wrapf(err, "zone config %d", i)
+		}
+	}
+	for i, e := range s.Events {
+		if _, err := e.event(); err != nil {
+			return errors.Wrapf(err, "event %d", i)
+		}
+	}
+	return nil
+}
+
+// rebalancingMode returns the load based rebalancing mode declared in the
+// settings, or the default mode when none is declared.
+func (s Settings) rebalancingMode() (kvserver.LBRebalancingMode, error) {
+	switch s.RebalancingMode {
+	case "":
+		return kvserver.LBRebalancingMode(config.DefaultSimulationSettings().LBRebalancingMode), nil
+	case "off":
+		return kvserver.LBRebalancingOff, nil
+	case "leases":
+		return kvserver.LBRebalancingLeasesOnly, nil
+	case "leases_and_replicas":
+		return kvserver.LBRebalancingLeasesAndReplicas, nil
+	default:
+		return 0, errors.Newf(
+			"unknown rebalancing mode %q, expected one of: off, leases, leases_and_replicas",
+			s.RebalancingMode)
+	}
+}
+
+// span returns the simulator key span the zone config applies to.
+func (zc ZoneConfig) span() roachpb.Span {
+	return roachpb.Span{
+		Key:    state.Key(zc.StartKey).ToRKey().AsRawKey(),
+		EndKey: state.Key(zc.EndKey).ToRKey().AsRawKey(),
+	}
+}
+
+// event returns the simulator event corresponding to the scenario event.
+func (e Event) event() (event.Event, error) {
+	switch e.Type {
+	case "add_node":
+		if e.Locality != "" {
+			if err := (&roachpb.Locality{}).Set(e.Locality); err != nil {
+				return nil, err
+			}
+		}
+		return event.AddNodeEvent{NumStores: e.Stores, LocalityString: e.Locality}, nil
+	case "set_liveness":
+		status, ok := livenesspb.NodeLivenessStatus_value[strings.ToUpper(e.Liveness)]
+		if !ok {
+			return nil, errors.Newf("unknown liveness status %q", e.Liveness)
+		}
+		if e.Node <= 0 {
+			return nil, errors.New("set_liveness requires a node")
+		}
+		return event.SetNodeLivenessEvent{
+			NodeId:         state.NodeID(e.Node),
+			LivenessStatus: livenesspb.NodeLivenessStatus(status),
+		}, nil
+	case "set_locality":
+		if e.Node <= 0 {
+			return nil, errors.New("set_locality requires a node")
+		}
+		if err := (&roachpb.Locality{}).Set(e.Locality); err != nil {
+			return nil, err
+		}
+		return event.SetNodeLocalityEvent{
+			NodeID:         state.NodeID(e.Node),
+			LocalityString: e.Locality,
+		}, nil
+	case "set_capacity":
+		if e.Store <= 0 {
+			return nil, errors.New("set_capacity requires a store")
+		}
+		override := state.NewCapacityOverride()
+		if e.Capacity != nil {
+			override.Capacity = *e.Capacity
+		}
+		if e.Available != nil {
+			override.Available = *e.Available
+		}
+		return event.SetCapacityOverrideEvent{
+			StoreID:          state.StoreID(e.Store),
+			CapacityOverride: override,
+		}, nil
+	default:
+		return nil, errors.Newf("unknown event type %q", e.Type)
+	}
+}
+
+// SettingsGen returns a settings generator for the scenario.
+func (s *Scenario) SettingsGen() gen.StaticSettings {
+	settings := config.DefaultSimulationSettings()
+	settings.Seed = s.Seed
+	if s.Settings.TickInterval != 0 {
+		settings.TickInterval = s.Settings.TickInterval
+	}
+	if s.Settings.MetricsInterval != 0 {
+		settings.MetricsInterval = s.Settings.MetricsInterval
+	}
+	if s.Settings.SplitQPSThreshold != 0 {
+		settings.SplitQPSThreshold = s.Settings.SplitQPSThreshold
+	}
+	// The mode has already been validated when parsing the scenario.
+	mode, _ := s.Settings.rebalancingMode()
+	settings.LBRebalancingMode = int64(mode)
+	settings.MultiMetricRebalancing = s.Settings.MultiMetricRebalancing
+	return gen.StaticSettings{Settings: settings}
+}
+
+// ClusterGen returns a cluster generator for the scenario topology.
+func (s *Scenario) ClusterGen() gen.LoadedCluster {
+	info := state.ClusterInfo{DiskCapacityGB: s.DiskCapacityGB}
+	for _, r := range s.Regions {
+		region := state.Region{Name: r.Name}
+		for _, z := range r.Zones {
+			region.Zones = append(region.Zones, state.NewZone(z.Name, z.Nodes, z.StoresPerNode))
+		}
+		info.Regions = append(info.Regions, region)
+	}
+	return gen.LoadedCluster{Info: info}
+}
+
+// RangeGen returns a range generator for the scenario's initial ranges.
+func (s *Scenario) RangeGen() gen.BasicRanges {
+	return gen.BasicRanges{
+		BaseRanges: gen.BaseRanges{
+			Ranges:            s.Ranges.Count,
+			KeySpace:          s.Ranges.KeySpace,
+			ReplicationFactor: s.Ranges.ReplicationFactor,
+			Bytes:             s.Ranges.Bytes,
+		},
+		PlacementType: gen.GetRangePlacementType(s.Ranges.Placement),
+	}
+}
+
+// LoadGen returns a load generator for the scenario's workloads.
+func (s *Scenario) LoadGen() gen.LoadGen {
+	loads := make(multiLoad, len(s.Workload))
+	for i, w := range s.Workload {
+		loads[i] = gen.BasicLoad{
+			RWRatio:      w.ReadRatio,
+			Rate:         w.Rate,
+			SkewedAccess: w.SkewedAccess,
+			MinBlockSize: w.MinBlockSize,
+			MaxBlockSize: w.MaxBlockSize,
+			MinKey:       w.MinKey,
+			MaxKey:       w.MaxKey,
+		}
+	}
+	return loads
+}
+
+// EventGen returns an event generator for the scenario's zone configs and
+// events, scheduled relative to the start time of the settings given.
+func (s *Scenario) EventGen(settings *config.SimulationSettings) gen.StaticEvents {
+	eventGen := gen.NewStaticEventsWithNoEvents()
+	for _, zc := range s.ZoneConfigs {
+		eventGen.ScheduleEvent(settings.StartTime, zc.At, event.SetSpanConfigEvent{
+			Span:   zc.span(),
+			Config: zc.Config.AsSpanConfig(),
+		})
+	}
+	for _, e := range s.Events {
+		// The event has already been validated when parsing the scenario.
+		ev, _ := e.event()
+		eventGen.ScheduleEvent(settings.StartTime, e.At, ev)
+	}
+	return eventGen
+}
+
+// Simulator returns a new simulator for the scenario.
+func (s *Scenario) Simulator() *asim.Simulator {
+	settingsGen := s.SettingsGen()
+	return gen.GenerateSimulation(
+		s.Duration,
+		s.ClusterGen(),
+		s.RangeGen(),
+		s.LoadGen(),
+		settingsGen,
+		s.EventGen(settingsGen.Settings),
+		s.Seed,
+	)
+}
+
+// multiLoad implements the gen.LoadGen interface, generating the workload
+// generators of every load it contains. The generators are seeded
+// differently so that concurrent workloads do not access identical keys.
+type multiLoad []gen.BasicLoad
+
+var _ gen.LoadGen = multiLoad{}
+
+// Generate implements the gen.LoadGen interface.
+func (ml multiLoad) Generate(
+	seed int64, settings *config.SimulationSettings,
+) []workload.Generator {
+	var generators []workload.Generator
+	for i, l := range ml {
+		generators = append(generators, l.Generate(seed+int64(i), settings)...)
+	}
+	return generators
+}
+
+func (ml multiLoad) String() string {
+	var buf strings.Builder
+	for i, l := range ml {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "%v", l)
+	}
+	return buf.String()
+}
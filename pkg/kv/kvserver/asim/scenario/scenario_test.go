@@ -0,0 +1,181 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	data, err := os.ReadFile(datapathutils.TestDataPath(t, "add_nodes.yaml"))
+	require.NoError(t, err)
+	s, err := Parse(data)
+	require.NoError(t, err)
+
+	require.Equal(t, 10*time.Minute, s.Duration)
+	require.Equal(t, int64(42), s.Seed)
+	require.Equal(t, defaultDiskCapacityGB, s.DiskCapacityGB)
+
+	info := s.ClusterGen().Info
+	require.Len(t, info.Regions, 2)
+	require.Len(t, info.Regions[0].Zones, 2)
+	require.Equal(t, 1, info.Regions[1].Zones[0].StoresPerNode)
+
+	settings := s.SettingsGen().Settings
+	require.Equal(t, int64(kvserver.LBRebalancingLeasesAndReplicas), settings.LBRebalancingMode)
+	require.Equal(t, int64(42), settings.Seed)
+
+	require.Len(t, s.Workload, 2)
+	require.Equal(t, int64(defaultKeySpace), s.Workload[1].MaxKey)
+	require.Equal(t, defaultMinBlockSize, s.Workload[1].MinBlockSize)
+
+	require.Len(t, s.ZoneConfigs, 1)
+	conf := s.ZoneConfigs[0].Config.AsSpanConfig()
+	require.Equal(t, int32(3), conf.NumReplicas)
+	require.Len(t, conf.Constraints, 1)
+	require.Equal(t, int32(2), conf.Constraints[0].NumReplicas)
+
+	require.Len(t, s.Events, 3)
+	require.Equal(t, 1, s.Events[0].Stores)
+}
+
+func TestParseErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const regions = `
+regions:
+- name: us-east
+  zones:
+  - {name: us-east-1, nodes: 3}
+`
+	testCases := []struct {
+		name     string
+		scenario string
+		expected string
+	}{
+		{
+			name:     "unknown field",
+			scenario: regions + "nodes: 3\n",
+			expected: "field nodes not found",
+		},
+		{
+			name:     "no regions",
+			scenario: "duration: 1m\n",
+			expected: "at least one region",
+		},
+		{
+			name:     "no nodes",
+			scenario: "regions:\n- name: us-east\n  zones:\n  - {name: us-east-1}\n",
+			expected: "at least one node",
+		},
+		{
+			name:     "placement",
+			scenario: regions + "ranges: {placement: random}\n",
+			expected: "unsupported range placement",
+		},
+		{
+			name:     "rebalancing mode",
+			scenario: regions + "settings: {rebalancing_mode: all}\n",
+			expected: "unknown rebalancing mode",
+		},
+		{
+			name:     "read ratio",
+			scenario: regions + "workload:\n- {rate: 10, read_ratio: 2}\n",
+			expected: "read_ratio must be in [0, 1]",
+		},
+		{
+			name:     "zone config span",
+			scenario: regions + "zone_configs:\n- {start_key: 10, end_key: 10}\n",
+			expected: "start_key must be less than end_key",
+		},
+		{
+			name:     "zone config",
+			scenario: regions + "zone_configs:\n- {start_key: 0, end_key: 10, config: {num_replicas: 2}}\n",
+			expected: "at least 3 replicas are required",
+		},
+		{
+			name:     "event type",
+			scenario: regions + "events:\n- {at: 1m, type: remove_node}\n",
+			expected: "unknown event type",
+		},
+		{
+			name:     "liveness",
+			scenario: regions + "events:\n- {at: 1m, type: set_liveness, node: 1, liveness: gone}\n",
+			expected: "unknown liveness status",
+		},
+		{
+			name:     "locality",
+			scenario: regions + "events:\n- {at: 1m, type: add_node, locality: us-east}\n",
+			expected: "tier must be in the form",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.scenario))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestWriteHistory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	data, err := os.ReadFile(datapathutils.TestDataPath(t, "add_nodes.yaml"))
+	require.NoError(t, err)
+	s, err := Parse(data)
+	require.NoError(t, err)
+
+	sim := s.Simulator()
+	sim.RunSim(ctx)
+	h := sim.History()
+	require.NotEmpty(t, h.Recorded)
+
+	// The cluster starts with 6 stores and has 8 stores once the nodes are
+	// added.
+	require.Len(t, h.Recorded[0], 6)
+	require.Len(t, h.Recorded[len(h.Recorded)-1], 8)
+	samples := Samples(h)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteHistory(&buf, h, OutputCSV))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, csvHeader, records[0])
+	require.Len(t, records, len(samples)+1)
+
+	buf.Reset()
+	require.NoError(t, WriteHistory(&buf, h, OutputJSON))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, len(samples))
+	var last Sample
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+	require.Equal(t, samples[len(samples)-1], last)
+}
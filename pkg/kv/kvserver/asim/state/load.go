@@ -42,6 +42,11 @@ func LoadEventQPS(le workload.LoadEvent) float64 {
 	return float64(le.Reads) + float64(le.Writes)
 }
 
+// SimulatedCPUNanosPerQuery is the cpu time attributed to each query served
+// by a leaseholder replica. The simulator doesn't measure cpu, so cpu load is
+// modeled as being proportional to qps.
+const SimulatedCPUNanosPerQuery = 50000
+
 // ReplicaLoadCounter is the sum of all key accesses and size of bytes, both written
 // and read.
 // TODO(kvoli): In the non-simulated code, replica_stats currently maintains
@@ -86,8 +91,9 @@ func (rl *ReplicaLoadCounter) Load() allocator.RangeUsageInfo {
 	stats := rl.loadStats.Stats()
 
 	return allocator.RangeUsageInfo{
-		QueriesPerSecond: stats.QueriesPerSecond,
-		WritesPerSecond:  float64(rl.WriteKeys),
+		QueriesPerSecond:         stats.QueriesPerSecond,
+		WritesPerSecond:          float64(rl.WriteKeys),
+		RequestCPUNanosPerSecond: stats.QueriesPerSecond * SimulatedCPUNanosPerQuery,
 	}
 }
 
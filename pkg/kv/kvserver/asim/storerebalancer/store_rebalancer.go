@@ -40,6 +40,9 @@ const (
 	// rangeRebalancing indicates that the store rebalancer is searching for or
 	// waiting on range (replica+lease) rebalancing.
 	rangeRebalancing
+	// multiMetricRebalancing indicates that the store rebalancer is applying
+	// the changes proposed by the multi-metric allocator.
+	multiMetricRebalancing
 )
 
 // StoreRebalancer is a tickable actor which scans the replicas on the store
@@ -61,6 +64,12 @@ type storeRebalancerState struct {
 	pendingRelocateExistingVoters    []roachpb.ReplicaDescriptor
 	pendingTransferTarget            roachpb.ReplicaDescriptor
 
+	// multiMetricChanges are the changes proposed by the multi-metric
+	// allocator that have not been dispatched yet, and pendingMultiMetric is
+	// the dispatched change, if any.
+	multiMetricChanges []kvserver.LoadRebalanceChange
+	pendingMultiMetric *kvserver.LoadRebalanceChange
+
 	pendingTicket op.DispatchedTicket
 	lastTick      time.Time
 }
@@ -167,6 +176,8 @@ func (src *storeRebalancerControl) Tick(ctx context.Context, tick time.Time, sta
 		src.phaseLeaseRebalancing(ctx, tick, state)
 	case rangeRebalancing:
 		src.phaseRangeRebalancing(ctx, tick, state)
+	case multiMetricRebalancing:
+		src.phaseMultiMetricRebalancing(ctx, tick, state)
 	}
 }
 
@@ -187,6 +198,20 @@ func (src *storeRebalancerControl) phaseSleep(ctx context.Context, tick time.Tim
 func (src *storeRebalancerControl) phasePrologue(
 	ctx context.Context, tick time.Time, s state.State,
 ) {
+	if src.settings.MultiMetricRebalancing {
+		src.rebalancerState.multiMetricChanges = src.sr.ComputeLoadRebalanceChanges(
+			ctx,
+			hottestRanges(
+				s, src.storeID,
+				kvserver.LBRebalancingObjective(src.settings.LBRebalancingObjective).ToDimension(),
+			),
+			kvserver.LBRebalancingMode(src.settings.LBRebalancingMode),
+		)
+		src.rebalancerState.phase = multiMetricRebalancing
+		src.phaseMultiMetricRebalancing(ctx, tick, s)
+		return
+	}
+
 	rctx := src.sr.NewRebalanceContext(
 		ctx, src.scorerOptions(),
 		hottestRanges(
@@ -384,10 +409,79 @@ func (src *storeRebalancerControl) phaseRangeRebalancing(
 	src.phaseEpilogue(ctx, tick)
 }
 
+func (src *storeRebalancerControl) checkPendingMultiMetricRebalance(ctx context.Context) bool {
+	// No pending change, we can continue to dispatching changes.
+	change := src.rebalancerState.pendingMultiMetric
+	if change == nil {
+		return true
+	}
+
+	done, _, err := src.checkPendingTicket()
+	if !done {
+		// No more we can do in this tick - we need to wait for the change to
+		// complete.
+		return false
+	}
+
+	if err == nil && change.IsLeaseTransfer() {
+		src.storepool.UpdateLocalStoresAfterLeaseTransfer(
+			roachpb.StoreID(src.storeID),
+			change.LeaseTarget.StoreID,
+			change.CandidateReplica.RangeUsageInfo(),
+		)
+	}
+	src.sr.PostLoadRebalanceChange(ctx, *change, err == nil)
+
+	src.rebalancerState.pendingTicket = -1
+	src.rebalancerState.pendingMultiMetric = nil
+	return true
+}
+
+// phaseMultiMetricRebalancing dispatches the changes proposed by the
+// multi-metric allocator one at a time, waiting for each to complete.
+func (src *storeRebalancerControl) phaseMultiMetricRebalancing(
+	ctx context.Context, tick time.Time, s state.State,
+) {
+	for {
+		if !src.checkPendingMultiMetricRebalance(ctx) {
+			return
+		}
+		if len(src.rebalancerState.multiMetricChanges) == 0 {
+			break
+		}
+		change := src.rebalancerState.multiMetricChanges[0]
+		src.rebalancerState.multiMetricChanges = src.rebalancerState.multiMetricChanges[1:]
+
+		var dispatchedOp op.ControlledOperation
+		if change.IsLeaseTransfer() {
+			dispatchedOp = op.NewTransferLeaseOp(
+				tick,
+				change.RangeID,
+				change.CandidateReplica.StoreID(),
+				change.LeaseTarget.StoreID,
+				change.CandidateReplica.RangeUsageInfo(),
+			)
+		} else {
+			dispatchedOp = op.NewRelocateRangeOp(
+				tick,
+				change.CandidateReplica.Desc().StartKey.AsRawKey(),
+				change.VoterTargets,
+				change.NonVoterTargets,
+				true, /* transferLeaseToFirstVoter */
+			)
+		}
+		src.rebalancerState.pendingTicket = src.controller.Dispatch(ctx, tick, s, dispatchedOp)
+		src.rebalancerState.pendingMultiMetric = &change
+	}
+
+	src.phaseEpilogue(ctx, tick)
+}
+
 // phaseEpilogue clears the rebalancing context and updates the last tick
 // interval. This transfers into a sleeping phase.
 func (src *storeRebalancerControl) phaseEpilogue(ctx context.Context, tick time.Time) {
 	src.rebalancerState.phase = rebalancerSleeping
 	src.rebalancerState.rctx = nil
+	src.rebalancerState.multiMetricChanges = nil
 	src.rebalancerState.lastTick = tick
 }
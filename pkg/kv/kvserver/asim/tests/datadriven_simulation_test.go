@@ -129,10 +129,12 @@ import (
 //   - "setting" [rebalance_mode=<int>] [rebalance_interval=<duration>]
 //     [rebalance_qps_threshold=<float>] [split_qps_threshold=<float>]
 //     [rebalance_range_threshold=<float>] [gossip_delay=<duration>]
+//     [multi_metric_rebalancing=<bool>]
 //     Configure the simulation's various settings. The default values are:
 //     rebalance_mode=2 (leases and replicas) rebalance_interval=1m (1 minute)
 //     rebalance_qps_threshold=0.1 split_qps_threshold=2500
-//     rebalance_range_threshold=0.05 gossip_delay=500ms.
+//     rebalance_range_threshold=0.05 gossip_delay=500ms
+//     multi_metric_rebalancing=false.
 //
 //   - "eval" [duration=<string>] [samples=<int>] [seed=<int>]
 //     Run samples (e.g. samples=5) number of simulations for duration (e.g.
@@ -443,6 +445,7 @@ func TestDataDriven(t *testing.T) {
 				scanIfExists(t, d, "rebalance_range_threshold", &settingsGen.Settings.RangeRebalanceThreshold)
 				scanIfExists(t, d, "gossip_delay", &settingsGen.Settings.StateExchangeDelay)
 				scanIfExists(t, d, "range_size_split_threshold", &settingsGen.Settings.RangeSizeSplitThreshold)
+				scanIfExists(t, d, "multi_metric_rebalancing", &settingsGen.Settings.MultiMetricRebalancing)
 				return ""
 			case "plot":
 				var stat string
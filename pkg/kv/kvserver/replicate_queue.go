@@ -610,14 +610,28 @@ func (rq *replicateQueue) shouldQueue(
 		return false, 0
 	}
 	desc := repl.Desc()
-	return rq.planner.ShouldPlanChange(
+	multiMetric := MultiMetricRebalancingEnabled.Get(&rq.store.cfg.Settings.SV)
+	shouldQueue, priority = rq.planner.ShouldPlanChange(
 		ctx,
 		now,
 		repl,
 		desc,
 		&conf,
-		plan.PlannerOptions{},
+		plan.PlannerOptions{
+			DisableRebalancing: multiMetric,
+		},
 	)
+	if !shouldQueue && multiMetric && rq.store.storeRebalancer != nil && repl.OwnsValidLease(ctx, now) {
+		// Rebalancing is planned by the multi-metric allocator, see
+		// processLoadRebalance. Only leaseholders shed load.
+		candidate := candidateReplica{Replica: repl, usage: repl.RangeUsageInfo()}
+		if _, ok := rq.store.storeRebalancer.computeRangeLoadRebalanceChange(
+			ctx, candidate, true /* dryRun */); ok {
+			log.KvDistribution.VEventf(ctx, 2, "multi-metric rebalance found, enqueuing")
+			return true, 0
+		}
+	}
+	return shouldQueue, priority
 }
 
 func (rq *replicateQueue) process(
@@ -850,7 +864,10 @@ func (rq *replicateQueue) processOneChange(
 	scatter, dryRun bool,
 ) (requeue bool, _ error) {
 	change, err := rq.planner.PlanOneChange(
-		ctx, repl, desc, conf, plan.PlannerOptions{Scatter: scatter})
+		ctx, repl, desc, conf, plan.PlannerOptions{
+			Scatter:            scatter,
+			DisableRebalancing: MultiMetricRebalancingEnabled.Get(&rq.store.cfg.Settings.SV),
+		})
 	// When there is an error planning a change, return the error immediately
 	// and do not requeue. It is unlikely that the range or storepool state
 	// will change quickly enough in order to not get the same error and
@@ -870,6 +887,15 @@ func (rq *replicateQueue) processOneChange(
 		return false, maybeAnnotateDecommissionErr(err, change.Action)
 	}
 
+	// When multi-metric rebalancing is enabled, the planner only plans repair
+	// actions, and rebalancing decisions are made by the multi-metric
+	// allocator instead. Scatter is still planned by the planner.
+	if _, noop := change.Op.(plan.AllocationNoop); noop && !scatter &&
+		change.Action == allocatorimpl.AllocatorConsiderRebalance &&
+		MultiMetricRebalancingEnabled.Get(&rq.store.cfg.Settings.SV) {
+		return false, rq.processLoadRebalance(ctx, repl, dryRun)
+	}
+
 	// There is nothing further to do during a dry run.
 	if dryRun {
 		return false, nil
@@ -905,6 +931,33 @@ func (rq *replicateQueue) processOneChange(
 	return ShouldRequeue(ctx, change, conf), nil
 }
 
+// processLoadRebalance asks the multi-metric allocator whether moving the
+// lease or the replicas of the range would shed load from the local store, and
+// if so, applies that change.
+func (rq *replicateQueue) processLoadRebalance(
+	ctx context.Context, repl *Replica, dryRun bool,
+) error {
+	sr := rq.store.storeRebalancer
+	if sr == nil {
+		return nil
+	}
+	change, ok := sr.computeRangeLoadRebalanceChange(
+		ctx, candidateReplica{Replica: repl, usage: repl.RangeUsageInfo()}, dryRun)
+	if !ok || dryRun {
+		return nil
+	}
+	var err error
+	if change.IsLeaseTransfer() {
+		err = rq.TransferLease(ctx, repl, repl.StoreID(), change.LeaseTarget.StoreID,
+			change.CandidateReplica.RangeUsageInfo())
+	} else {
+		err = rq.RelocateRange(ctx, change.desc.StartKey.AsRawKey(),
+			change.VoterTargets, change.NonVoterTargets, true /* transferLeaseToFirstVoter */)
+	}
+	sr.PostLoadRebalanceChange(ctx, change, err == nil)
+	return err
+}
+
 func maybeAnnotateDecommissionErr(err error, action allocatorimpl.AllocatorAction) error {
 	if err != nil && isDecommissionAction(action) {
 		err = decommissionPurgatoryError{err}
@@ -2218,6 +2218,13 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		})
 	}
 
+	// The store rebalancer is created before the scanner starts the replicate
+	// queue, which uses it for multi-metric rebalancing.
+	if s.replicateQueue != nil {
+		s.storeRebalancer = NewStoreRebalancer(
+			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings, s.rebalanceObjManager)
+	}
+
 	// Gossip is only ever nil while bootstrapping a cluster and
 	// in unittests.
 	if s.cfg.Gossip != nil {
@@ -2264,9 +2271,7 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 
 	s.startRangefeedTxnPushNotifier(ctx)
 
-	if s.replicateQueue != nil {
-		s.storeRebalancer = NewStoreRebalancer(
-			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings, s.rebalanceObjManager)
+	if s.storeRebalancer != nil {
 		s.storeRebalancer.Start(ctx, s.stopper)
 	}
 
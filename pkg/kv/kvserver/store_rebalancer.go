@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocator2"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/load"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
@@ -27,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/redact"
 )
@@ -83,6 +85,20 @@ var LoadBasedRebalancingMode = settings.RegisterEnumSetting(
 	},
 	settings.WithPublic)
 
+// MultiMetricRebalancingEnabled controls whether load-based lease and replica
+// rebalancing decisions are made by the multi-metric allocator, which
+// considers cpu, write and disk load together, instead of balancing the single
+// dimension of the rebalance objective. When enabled, the replicate queue also
+// uses the multi-metric allocator to rebalance, instead of balancing replica
+// counts.
+var MultiMetricRebalancingEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.allocator.multi_metric_rebalancing.enabled",
+	"if enabled, lease and replica rebalancing by the store rebalancer and the "+
+		"replicate queue considers multiple load dimensions together",
+	false,
+)
+
 // LBRebalancingMode controls if and when we do store-level rebalancing
 // based on load.
 type LBRebalancingMode int64
@@ -154,6 +170,23 @@ type StoreRebalancer struct {
 	processTimeoutFn        func(replica CandidateReplica) time.Duration
 	objectiveProvider       RebalanceObjectiveProvider
 	subscribedToSpanConfigs func() bool
+	// rebalancer2 makes the rebalancing decisions when multi-metric
+	// rebalancing is enabled. Its state persists across rebalancing loops. It
+	// is shared with the replicate queue, so accesses are serialized by
+	// rebalancer2Mu.
+	rebalancer2Mu syncutil.Mutex
+	rebalancer2   *allocator2.Rebalancer
+	// lastPass is the outcome of the last multi-metric rebalancing pass of the
+	// store rebalancer. The replicate queue reuses it until the next pass, so
+	// that it does not rebuild the store list for every replica. It is
+	// protected by rebalancer2Mu.
+	lastPass struct {
+		// stores are the stores the pass was computed with.
+		stores []allocator2.StoreInfo
+		// shedding is set if the pass proposed changes to shed load from the
+		// local store.
+		shedding bool
+	}
 }
 
 // NewStoreRebalancer creates a StoreRebalancer to work in tandem with the
@@ -178,6 +211,7 @@ func NewStoreRebalancer(
 		allocator:       rq.allocator,
 		storePool:       storePool,
 		replicaRankings: rr,
+		rebalancer2:     allocator2.NewRebalancer(rq.store.StoreID()),
 		getRaftStatusFn: func(replica CandidateReplica) *raft.Status {
 			return replica.RaftStatus()
 		},
@@ -220,6 +254,7 @@ func SimulatorStoreRebalancer(
 		storePool:         storePool,
 		getRaftStatusFn:   getRaftStatusFn,
 		objectiveProvider: objectiveProvider,
+		rebalancer2:       allocator2.NewRebalancer(storeID),
 	}
 	return sr
 }
@@ -330,6 +365,10 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 			ctx = sr.AnnotateCtx(ctx)
 
 			hottestRanges := sr.replicaRankings.TopLoad(objective.ToDimension())
+			if MultiMetricRebalancingEnabled.Get(&sr.st.SV) {
+				sr.rebalanceStoreMultiMetric(ctx, hottestRanges, mode)
+				continue
+			}
 			options := sr.scorerOptions(ctx, objective.ToDimension())
 			rctx := sr.NewRebalanceContext(ctx, options, hottestRanges, mode)
 			sr.rebalanceStore(ctx, rctx)
@@ -1234,6 +1273,228 @@ func (sr *StoreRebalancer) getRebalanceTargetsBasedOnLoad(
 	return finalVoterTargets, finalNonVoterTargets, foundRebalance
 }
 
+// LoadRebalanceChange is a lease transfer or range relocation proposed by the
+// multi-metric allocator, along with the replica of the range it applies to.
+type LoadRebalanceChange struct {
+	allocator2.RebalanceChange
+	CandidateReplica CandidateReplica
+	// desc is the range descriptor the change was computed for.
+	desc *roachpb.RangeDescriptor
+}
+
+// rebalanceStoreMultiMetric sheds load from the local store using the
+// multi-metric allocator. Unlike rebalanceStore, it does not loop until the
+// local store is below the overfull threshold: the allocator accounts for the
+// changes it proposes, so all the changes that are worthwhile are returned by
+// a single call.
+func (sr *StoreRebalancer) rebalanceStoreMultiMetric(
+	ctx context.Context, hottestRanges []CandidateReplica, mode LBRebalancingMode,
+) {
+	changes := sr.ComputeLoadRebalanceChanges(ctx, hottestRanges, mode)
+	for _, change := range changes {
+		var success bool
+		if change.IsLeaseTransfer() {
+			success = sr.applyLeaseRebalance(ctx, change.CandidateReplica, change.LeaseTarget)
+		} else {
+			success = sr.applyRangeRebalance(
+				ctx, change.CandidateReplica, change.VoterTargets, change.NonVoterTargets)
+		}
+		sr.PostLoadRebalanceChange(ctx, change, success)
+	}
+}
+
+// ComputeLoadRebalanceChanges returns the lease transfers and range
+// relocations that the multi-metric allocator proposes in order to shed load
+// from the local store, choosing from the given hottest ranges. The changes
+// are remembered as pending by the allocator, and the outcome of each must be
+// reported via PostLoadRebalanceChange.
+func (sr *StoreRebalancer) ComputeLoadRebalanceChanges(
+	ctx context.Context, hottestRanges []CandidateReplica, mode LBRebalancingMode,
+) []LoadRebalanceChange {
+	stores := sr.makeAllocator2Stores()
+	changes := sr.computeLoadRebalanceChanges(ctx, stores, hottestRanges, allocator2.ChangeOptions{
+		LeaseTransfersOnly: mode != LBRebalancingLeasesAndReplicas,
+	})
+	sr.rebalancer2Mu.Lock()
+	defer sr.rebalancer2Mu.Unlock()
+	sr.lastPass.stores = stores
+	sr.lastPass.shedding = len(changes) > 0
+	return changes
+}
+
+// computeRangeLoadRebalanceChange returns the lease transfer or range
+// relocation that the multi-metric allocator proposes for a single range, if
+// moving it would shed load from the local store. It is used by the replicate
+// queue in place of its own rebalancing. Unless dryRun is set, the outcome of
+// the change must be reported via PostLoadRebalanceChange.
+//
+// Changes are only computed if the last pass of the store rebalancer proposed
+// changes to shed load from the local store, and they are computed with the
+// stores of that pass.
+func (sr *StoreRebalancer) computeRangeLoadRebalanceChange(
+	ctx context.Context, repl CandidateReplica, dryRun bool,
+) (LoadRebalanceChange, bool) {
+	mode := sr.RebalanceMode()
+	if mode == LBRebalancingOff {
+		return LoadRebalanceChange{}, false
+	}
+	sr.rebalancer2Mu.Lock()
+	stores, shedding := sr.lastPass.stores, sr.lastPass.shedding
+	sr.rebalancer2Mu.Unlock()
+	if !shedding {
+		return LoadRebalanceChange{}, false
+	}
+	changes := sr.computeLoadRebalanceChanges(ctx, stores, []CandidateReplica{repl}, allocator2.ChangeOptions{
+		DryRun:             dryRun,
+		LeaseTransfersOnly: mode != LBRebalancingLeasesAndReplicas,
+	})
+	if len(changes) == 0 {
+		return LoadRebalanceChange{}, false
+	}
+	return changes[0], true
+}
+
+// makeAllocator2Stores returns the stores known to the store pool, as
+// understood by the multi-metric allocator.
+func (sr *StoreRebalancer) makeAllocator2Stores() []allocator2.StoreInfo {
+	allStoresList, _, _ := sr.storePool.GetStoreList(storepool.StoreFilterNone)
+	validStoresList, _, _ := sr.storePool.GetStoreList(storepool.StoreFilterSuspect)
+	validStores := validStoresList.ToMap()
+	stores := make([]allocator2.StoreInfo, 0, len(allStoresList.Stores))
+	for _, desc := range allStoresList.Stores {
+		_, valid := validStores[desc.StoreID]
+		stores = append(stores, allocator2.StoreInfo{
+			Desc:    desc,
+			Load:    makeAllocator2StoreLoad(desc.Capacity),
+			Suspect: !valid,
+		})
+	}
+	return stores
+}
+
+func (sr *StoreRebalancer) computeLoadRebalanceChanges(
+	ctx context.Context,
+	stores []allocator2.StoreInfo,
+	hottestRanges []CandidateReplica,
+	opts allocator2.ChangeOptions,
+) []LoadRebalanceChange {
+	now := sr.storePool.Clock().NowAsClockTimestamp()
+	candidates := make(map[roachpb.RangeID]CandidateReplica, len(hottestRanges))
+	ranges := make([]allocator2.RangeInfo, 0, len(hottestRanges))
+	for _, candidateReplica := range hottestRanges {
+		if !candidateReplica.OwnsValidLease(ctx, now) {
+			log.KvDistribution.VEventf(ctx, 3, "store doesn't own the lease for r%d", candidateReplica.GetRangeID())
+			continue
+		}
+		conf, err := candidateReplica.LoadSpanConfig(ctx)
+		if err != nil {
+			log.KvDistribution.VEventf(ctx, 2, "unable to load span config: %v", err)
+			continue
+		}
+		desc := candidateReplica.Desc()
+		// Voters that are lagging behind the leader should not receive the lease,
+		// see chooseLeaseToTransfer.
+		voters := desc.Replicas().VoterDescriptors()
+		upToDate := roachpb.MakeReplicaSet(
+			allocatorimpl.FilterBehindReplicas(ctx, sr.getRaftStatusFn(candidateReplica), voters))
+		var lagging []roachpb.ReplicaID
+		for _, voter := range voters {
+			if _, ok := upToDate.GetReplicaDescriptorByID(voter.ReplicaID); !ok {
+				lagging = append(lagging, voter.ReplicaID)
+			}
+		}
+		ranges = append(ranges, allocator2.RangeInfo{
+			Desc:            desc,
+			Conf:            *conf,
+			Load:            makeAllocator2RangeLoad(candidateReplica.RangeUsageInfo()),
+			LaggingReplicas: lagging,
+		})
+		candidates[desc.RangeID] = candidateReplica
+	}
+
+	sr.rebalancer2Mu.Lock()
+	changes := sr.rebalancer2.ComputeChanges(sr.storePool.Clock().PhysicalTime(), stores, ranges, opts)
+	sr.rebalancer2Mu.Unlock()
+	result := make([]LoadRebalanceChange, 0, len(changes))
+	for _, change := range changes {
+		if !opts.DryRun {
+			log.KvDistribution.Infof(ctx, "multi-metric rebalancing of r%d from local store s%d load=%s: %s",
+				change.RangeID, sr.storeID, candidates[change.RangeID].RangeUsageInfo(),
+				formatLoadRebalanceChange(change))
+		}
+		result = append(result, LoadRebalanceChange{
+			RebalanceChange:  change,
+			CandidateReplica: candidates[change.RangeID],
+			desc:             candidates[change.RangeID].Desc(),
+		})
+	}
+	return result
+}
+
+// PostLoadRebalanceChange informs the multi-metric allocator of the outcome of
+// a change returned by ComputeLoadRebalanceChanges, and updates the metrics.
+func (sr *StoreRebalancer) PostLoadRebalanceChange(
+	ctx context.Context, change LoadRebalanceChange, success bool,
+) {
+	if success {
+		if change.IsLeaseTransfer() {
+			// NB: Lease transfers update the local storepool themselves.
+			sr.metrics.LeaseTransferCount.Inc(1)
+		} else {
+			sr.metrics.RangeRebalanceCount.Inc(1)
+			sr.storePool.UpdateLocalStoreAfterRelocate(
+				change.VoterTargets, change.NonVoterTargets,
+				change.desc.Replicas().VoterDescriptors(), change.desc.Replicas().NonVoterDescriptors(),
+				sr.storeID,
+				change.CandidateReplica.RangeUsageInfo(),
+			)
+		}
+	}
+	sr.rebalancer2Mu.Lock()
+	defer sr.rebalancer2Mu.Unlock()
+	sr.rebalancer2.AdjustChangeDisposition(
+		sr.storePool.Clock().PhysicalTime(), change.RebalanceChange, success)
+}
+
+func formatLoadRebalanceChange(change allocator2.RebalanceChange) redact.RedactableString {
+	if change.IsLeaseTransfer() {
+		return redact.Sprintf("transfer lease to s%d", change.LeaseTarget.StoreID)
+	}
+	return redact.Sprintf("relocate voters to %v and non-voters to %v",
+		change.VoterTargets, change.NonVoterTargets)
+}
+
+// makeAllocator2StoreLoad returns the load of a store as understood by the
+// multi-metric allocator. Each load dimension is filled from the matching
+// store statistic, independent of the rebalance objective. Write load is
+// measured in keys written per second, since that is what both stores and
+// ranges report.
+func makeAllocator2StoreLoad(capacity roachpb.StoreCapacity) allocator2.StoreLoad {
+	cpu := capacity.CPUPerSecond
+	if cpu < 0 {
+		// CPU is not measured on this store.
+		cpu = 0
+	}
+	return allocator2.StoreLoad{
+		CPU:            int64(cpu),
+		WriteBandwidth: int64(capacity.WritesPerSecond),
+		ByteSize:       capacity.LogicalBytes,
+		ByteCapacity:   capacity.Capacity,
+		LeaseCount:     int64(capacity.LeaseCount),
+	}
+}
+
+// makeAllocator2RangeLoad returns the load of a range as understood by the
+// multi-metric allocator. See makeAllocator2StoreLoad.
+func makeAllocator2RangeLoad(usage allocator.RangeUsageInfo) allocator2.RangeLoad {
+	return allocator2.RangeLoad{
+		CPU:            int64(usage.RequestCPUNanosPerSecond + usage.RaftCPUNanosPerSecond),
+		RaftCPU:        int64(usage.RaftCPUNanosPerSecond),
+		WriteBandwidth: int64(usage.WritesPerSecond),
+		ByteSize:       usage.LogicalBytes,
+	}
+}
+
 // jitteredInterval returns a randomly jittered (+/-25%) duration
 // from checkInterval.
 func jitteredInterval(interval time.Duration) time.Duration {
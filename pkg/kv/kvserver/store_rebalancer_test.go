@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocator2"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/load"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
@@ -1841,6 +1842,47 @@ func TestStoreRebalancerHotRangesLogging(t *testing.T) {
 	), formatHotRanges(hottestRanges))
 }
 
+// TestMakeAllocator2Load checks that each load dimension of the multi-metric
+// allocator is filled from the matching statistic, regardless of the
+// rebalance objective.
+func TestMakeAllocator2Load(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	require.Equal(t, allocator2.StoreLoad{
+		CPU:            500,
+		WriteBandwidth: 20,
+		ByteSize:       1000,
+		ByteCapacity:   4000,
+		LeaseCount:     3,
+	}, makeAllocator2StoreLoad(roachpb.StoreCapacity{
+		QueriesPerSecond: 10000,
+		CPUPerSecond:     500,
+		WritesPerSecond:  20,
+		LogicalBytes:     1000,
+		Capacity:         4000,
+		LeaseCount:       3,
+	}))
+	// CPU is reported as -1 when it isn't measured.
+	require.Equal(t, int64(0), makeAllocator2StoreLoad(roachpb.StoreCapacity{
+		QueriesPerSecond: 10000,
+		CPUPerSecond:     -1,
+	}).CPU)
+
+	require.Equal(t, allocator2.RangeLoad{
+		CPU:            300,
+		RaftCPU:        100,
+		WriteBandwidth: 20,
+		ByteSize:       1000,
+	}, makeAllocator2RangeLoad(allocator.RangeUsageInfo{
+		QueriesPerSecond:         10000,
+		RequestCPUNanosPerSecond: 200,
+		RaftCPUNanosPerSecond:    100,
+		WritesPerSecond:          20,
+		LogicalBytes:             1000,
+	}))
+}
+
 // TestingRaftStatusFn returns a raft status where all replicas are up to date and
 // the replica on the store with ID StoreID is the leader. It may be used for
 // testing.
@@ -0,0 +1,607 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// Advisory locks are implemented on top of KV locks. Each advisory lock is
+// backed by a key under the descriptor ID of the session's current database,
+// which mirrors Postgres, where advisory locks are scoped to a database.
+// Database descriptors have no table data of their own, so this keyspace is
+// otherwise unused.
+//
+// KV locks can only be acquired on keys that have a value, so the key backing
+// an advisory lock is written when the lock is requested and the key does not
+// exist. The key is deleted again when the lock is released, by the last
+// transaction holding a lock on it. The deletion never waits on other
+// holders, so a key may be left behind if holders of a shared lock release it
+// concurrently, or if a session holding the lock crashes; such a key is
+// deleted the next time the lock is released.
+//
+// Transaction-scoped locks are acquired by the session's transaction, so they
+// are released when it commits or aborts, and waiting on them takes part in
+// the distributed deadlock detection performed by txnwait. Session-scoped
+// locks are all acquired by a single KV transaction per session, which is
+// kept open (and heartbeated by its coordinator) as long as the session holds
+// a session-scoped lock. It runs at the session's default transaction
+// priority.
+//
+// Since the same transaction holds the session-scoped locks of a session and
+// waits for the session-scoped locks it requests, deadlocks between sessions
+// waiting for session-scoped locks are detected by txnwait as well, which
+// aborts the transaction of one of the sessions (the one with the lower
+// priority) to break them. That session loses all of its session-scoped
+// locks, and its statement waiting for a lock fails with a deadlock error.
+//
+// KV locks are only released when the transaction holding them finishes. To
+// release a single session-scoped lock, the lock on the key backing it is
+// resolved as if it had been acquired by a previous epoch of the transaction,
+// which releases it. For the same reason, a session cannot hold the same
+// session-scoped lock in both modes.
+//
+// Since the session's transaction and its lock transaction are independent of
+// one another, txnwait cannot attribute both of them to the session. A
+// deadlock in which the session's transaction waits for another session which
+// itself waits for a session-scoped lock of the first session is therefore
+// not detected. Such waits are only bounded by the session's lock_timeout.
+
+// advisoryLockIndexID is the index ID under a database's descriptor ID at
+// which the keys backing advisory locks are stored.
+const advisoryLockIndexID = 1
+
+// advisoryLockSpan returns the span containing the keys backing the advisory
+// locks of the given database.
+func advisoryLockSpan(codec keys.SQLCodec, dbID descpb.ID) roachpb.Span {
+	prefix := codec.IndexPrefix(uint32(dbID), advisoryLockIndexID)
+	return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+}
+
+// makeAdvisoryLockKey returns the key backing the given advisory lock in the
+// given database.
+func makeAdvisoryLockKey(
+	codec keys.SQLCodec, dbID descpb.ID, key eval.AdvisoryLockKey,
+) roachpb.Key {
+	k := codec.IndexPrefix(uint32(dbID), advisoryLockIndexID)
+	k = encoding.EncodeUvarintAscending(k, uint64(key.ObjSubID))
+	k = encoding.EncodeUvarintAscending(k, uint64(key.ClassID))
+	k = encoding.EncodeUvarintAscending(k, uint64(key.ObjID))
+	return keys.MakeFamilyKey(k, 0)
+}
+
+// decodeAdvisoryLockKey is the inverse of makeAdvisoryLockKey.
+func decodeAdvisoryLockKey(
+	codec keys.SQLCodec, k roachpb.Key,
+) (descpb.ID, eval.AdvisoryLockKey, error) {
+	rem, dbID, indexID, err := codec.DecodeIndexPrefix(k)
+	if err != nil {
+		return 0, eval.AdvisoryLockKey{}, err
+	}
+	if indexID != advisoryLockIndexID {
+		return 0, eval.AdvisoryLockKey{}, errors.AssertionFailedf("%s is not an advisory lock key", k)
+	}
+	var vals [3]uint64
+	for i := range vals {
+		if rem, vals[i], err = encoding.DecodeUvarintAscending(rem); err != nil {
+			return 0, eval.AdvisoryLockKey{}, errors.Wrapf(err, "decoding advisory lock key %s", k)
+		}
+	}
+	return descpb.ID(dbID), eval.AdvisoryLockKey{
+		ObjSubID: uint16(vals[0]),
+		ClassID:  uint32(vals[1]),
+		ObjID:    uint32(vals[2]),
+	}, nil
+}
+
+// ensureAdvisoryLockKeyExists writes the key backing an advisory lock if it
+// does not exist. The write happens outside of the transaction acquiring the
+// lock, so that the key is visible to other transactions requesting the lock
+// even if the acquiring transaction does not commit. The write does not wait
+// on locks held on the key: if a conflicting lock is held, the requester
+// waits for it when it tries to lock the key.
+func ensureAdvisoryLockKeyExists(ctx context.Context, db *kv.DB, key roachpb.Key) error {
+	b := &kv.Batch{}
+	b.Header.WaitPolicy = lock.WaitPolicy_Error
+	b.CPut(key, []byte{}, nil /* expValue */)
+	err := db.Run(ctx, b)
+	// A concurrent request may have written the key in the meantime, in which
+	// case the conditional put fails, which is fine.
+	if err != nil &&
+		!errors.HasType(err, (*kvpb.ConditionFailedError)(nil)) &&
+		!errors.HasType(err, (*kvpb.WriteIntentError)(nil)) {
+		return err
+	}
+	return nil
+}
+
+// lockAdvisoryLockKey acquires a replicated KV lock on the key backing an
+// advisory lock using the given transaction, writing the key first if it
+// does not exist. If wait is false, it returns false instead of waiting for a
+// conflicting lock to be released.
+func lockAdvisoryLockKey(
+	ctx context.Context,
+	db *kv.DB,
+	txn *kv.Txn,
+	key roachpb.Key,
+	mode eval.AdvisoryLockMode,
+	wait bool,
+	lockTimeout time.Duration,
+) (bool, error) {
+	for {
+		b := txn.NewBatch()
+		if wait {
+			b.Header.LockTimeout = lockTimeout
+		} else {
+			b.Header.WaitPolicy = lock.WaitPolicy_Error
+		}
+		if mode == eval.AdvisoryLockShared {
+			b.GetForShare(key, kvpb.GuaranteedDurability)
+		} else {
+			b.GetForUpdate(key, kvpb.GuaranteedDurability)
+		}
+		if err := txn.Run(ctx, b); err != nil {
+			var wiErr *kvpb.WriteIntentError
+			if !errors.As(err, &wiErr) {
+				return false, err
+			}
+			if !wait {
+				return false, nil
+			}
+			if wiErr.Reason == kvpb.WriteIntentError_REASON_LOCK_TIMEOUT {
+				return false, pgerror.New(pgcode.LockNotAvailable,
+					"canceling statement due to lock timeout on advisory lock")
+			}
+			return false, pgerror.Wrap(err, pgcode.LockNotAvailable, "could not obtain advisory lock")
+		}
+		if b.Results[0].Rows[0].Exists() {
+			return true, nil
+		}
+		// The locking read did not acquire a lock, because the key does not
+		// exist: either the lock was never requested, or the key was deleted
+		// when the lock was last released. Write the key and try again.
+		if err := ensureAdvisoryLockKeyExists(ctx, db, key); err != nil {
+			return false, err
+		}
+	}
+}
+
+// deleteAdvisoryLockKey deletes the key backing an advisory lock using the
+// given transaction, which holds a lock on the key, unless another
+// transaction also holds a lock on it. It returns whether the key was
+// deleted.
+func deleteAdvisoryLockKey(ctx context.Context, txn *kv.Txn, key roachpb.Key) (bool, error) {
+	b := txn.NewBatch()
+	b.Header.WaitPolicy = lock.WaitPolicy_Error
+	b.Del(key)
+	if err := txn.Run(ctx, b); err != nil {
+		if errors.HasType(err, (*kvpb.WriteIntentError)(nil)) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteUnlockedAdvisoryLockKey deletes the key backing an advisory lock in
+// a new transaction, unless a transaction holds a lock on it.
+func deleteUnlockedAdvisoryLockKey(ctx context.Context, db *kv.DB, key roachpb.Key) {
+	if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		_, err := deleteAdvisoryLockKey(ctx, txn, key)
+		return err
+	}); err != nil {
+		log.Warningf(ctx, "unable to delete the key %s backing an advisory lock: %v", key, err)
+	}
+}
+
+// releaseAdvisoryLockKey releases the lock held by the given transaction on
+// the key backing an advisory lock, while the transaction keeps its other
+// locks. The lock is resolved as if it had been acquired by a previous epoch
+// of the transaction, which releases it.
+func releaseAdvisoryLockKey(ctx context.Context, db *kv.DB, txn *kv.Txn, key roachpb.Key) error {
+	state, err := txn.GetLeafTxnInputState(ctx)
+	if err != nil {
+		return err
+	}
+	update := roachpb.MakeLockUpdate(&state.Txn, roachpb.Span{Key: key})
+	update.Txn.Epoch++
+	b := &kv.Batch{}
+	b.AddRawRequest(&kvpb.ResolveIntentRequest{
+		RequestHeader: kvpb.RequestHeader{Key: key},
+		IntentTxn:     update.Txn,
+		Status:        update.Status,
+	})
+	return db.Run(ctx, b)
+}
+
+// advisoryLockHold identifies an advisory lock held by a session in a given
+// mode.
+type advisoryLockHold struct {
+	dbID descpb.ID
+	key  eval.AdvisoryLockKey
+	mode eval.AdvisoryLockMode
+}
+
+// otherMode returns the same hold in the other lock mode.
+func (h advisoryLockHold) otherMode() advisoryLockHold {
+	if h.mode == eval.AdvisoryLockShared {
+		h.mode = eval.AdvisoryLockExclusive
+	} else {
+		h.mode = eval.AdvisoryLockShared
+	}
+	return h
+}
+
+// sessionAdvisoryLock is a session-scoped advisory lock, held by the session's
+// lock transaction. As in Postgres, a session may acquire the same lock
+// multiple times, and must then release it as many times before it is
+// released.
+type sessionAdvisoryLock struct {
+	key   roachpb.Key
+	count int
+}
+
+// advisoryLockState tracks the advisory locks held by a session.
+type advisoryLockState struct {
+	// session contains the session-scoped locks held by the session.
+	session map[advisoryLockHold]*sessionAdvisoryLock
+	// sessionTxn is the KV transaction holding the session-scoped locks, or nil
+	// if the session holds none.
+	sessionTxn *kv.Txn
+	// txn contains the transaction-scoped locks acquired by the transaction
+	// with ID txnID, along with the keys backing them. It is reset when the
+	// transaction finishes, or lazily when a lock is requested by another
+	// transaction.
+	txnID uuid.UUID
+	txn   map[advisoryLockHold]roachpb.Key
+}
+
+// txnLocks returns the transaction-scoped locks held by the given
+// transaction.
+func (s *advisoryLockState) txnLocks(txn *kv.Txn) map[advisoryLockHold]roachpb.Key {
+	if s.txnID != txn.ID() {
+		s.txnID = txn.ID()
+		s.txn = nil
+	}
+	if s.txn == nil {
+		s.txn = make(map[advisoryLockHold]roachpb.Key)
+	}
+	return s.txn
+}
+
+// txnFinished is called once the session's transaction has committed or
+// aborted, which released its transaction-scoped locks. The keys backing
+// these locks are deleted unless another transaction holds a lock on them.
+func (s *advisoryLockState) txnFinished(ctx context.Context, db *kv.DB) {
+	txnLocks := s.txn
+	s.txnID, s.txn = uuid.UUID{}, nil
+	for _, key := range txnLocks {
+		deleteUnlockedAdvisoryLockKey(ctx, db, key)
+	}
+}
+
+// lockTxn returns the transaction holding the session-scoped locks of the
+// session, creating it if the session holds none.
+func (s *advisoryLockState) lockTxn(
+	ctx context.Context, db *kv.DB, nodeID roachpb.NodeID, priority roachpb.UserPriority,
+) (*kv.Txn, error) {
+	if s.sessionTxn != nil {
+		return s.sessionTxn, nil
+	}
+	txn := kv.NewTxn(ctx, db, nodeID)
+	txn.SetDebugName("advisory lock")
+	if err := txn.SetUserPriority(priority); err != nil {
+		return nil, err
+	}
+	s.sessionTxn = txn
+	return txn, nil
+}
+
+// finishLockTxn commits or rolls back the transaction holding the
+// session-scoped locks of the session, which releases all of them.
+func (s *advisoryLockState) finishLockTxn(ctx context.Context, commit bool) error {
+	txn := s.sessionTxn
+	s.session, s.sessionTxn = nil, nil
+	if commit {
+		return txn.Commit(ctx)
+	}
+	return txn.Rollback(ctx)
+}
+
+// abandonLockTxn is called when the transaction holding the session-scoped
+// locks of the session failed with the given error and cannot be used
+// anymore, e.g. because it was aborted to break a deadlock. The transaction is
+// rolled back, releasing all of the session-scoped locks, and the returned
+// error reports it.
+func (s *advisoryLockState) abandonLockTxn(ctx context.Context, err error) error {
+	if rollbackErr := s.finishLockTxn(ctx, false /* commit */); rollbackErr != nil {
+		log.Warningf(ctx, "unable to roll back advisory lock transaction: %v", rollbackErr)
+	}
+	var retryErr *kvpb.TransactionRetryWithProtoRefreshError
+	if errors.As(err, &retryErr) && retryErr.PrevTxnAborted() {
+		err = pgerror.New(pgcode.DeadlockDetected, "deadlock detected")
+	}
+	return errors.WithDetail(err, "All session-level advisory locks held by the session were released.")
+}
+
+// release releases the given session-scoped lock. If the session holds no
+// other session-scoped lock, the key backing the lock is deleted unless
+// another transaction holds a lock on it, and the lock transaction is
+// finished. Otherwise, only the lock on the key is released.
+func (s *advisoryLockState) release(ctx context.Context, db *kv.DB, hold advisoryLockHold) error {
+	l := s.session[hold]
+	delete(s.session, hold)
+	if len(s.session) == 0 {
+		deleted, err := deleteAdvisoryLockKey(ctx, s.sessionTxn, l.key)
+		if err != nil {
+			return s.abandonLockTxn(ctx, err)
+		}
+		return s.finishLockTxn(ctx, deleted /* commit */)
+	}
+	if err := releaseAdvisoryLockKey(ctx, db, s.sessionTxn, l.key); err != nil {
+		return s.abandonLockTxn(ctx, err)
+	}
+	deleteUnlockedAdvisoryLockKey(ctx, db, l.key)
+	return nil
+}
+
+// releaseAll releases all session-scoped locks held by the session.
+func (s *advisoryLockState) releaseAll(ctx context.Context) error {
+	if s.sessionTxn == nil {
+		return nil
+	}
+	for _, l := range s.session {
+		if _, err := deleteAdvisoryLockKey(ctx, s.sessionTxn, l.key); err != nil {
+			return s.abandonLockTxn(ctx, err)
+		}
+	}
+	return s.finishLockTxn(ctx, true /* commit */)
+}
+
+// errAdvisoryLocksUnavailable is returned when advisory locks are used by a
+// planner which is not associated with a session, e.g. one used by an
+// internal executor.
+var errAdvisoryLocksUnavailable = pgerror.New(
+	pgcode.FeatureNotSupported, "advisory locks are not available in this context",
+)
+
+// errAdvisoryLockModeConflict is returned when a session requests an advisory
+// lock in a mode which conflicts with a hold of the same lock by the session
+// itself that cannot be reconciled with the KV locks backing them, either
+// because the request would wait on the session's own KV lock or because the
+// locks could not be released individually.
+func errAdvisoryLockModeConflict(hold advisoryLockHold, held string) error {
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"cannot acquire advisory lock (%d,%d,%d) in mode %s while it is held %s",
+		hold.key.ClassID, hold.key.ObjID, hold.key.ObjSubID, hold.mode, held,
+	)
+}
+
+var _ eval.AdvisoryLocker = &planner{}
+
+// advisoryLockDatabaseID returns the ID of the database which scopes the
+// advisory locks of the session.
+func (p *planner) advisoryLockDatabaseID(ctx context.Context) (descpb.ID, error) {
+	if p.CurrentDatabase() == "" {
+		return 0, pgerror.New(pgcode.InvalidCatalogName,
+			"cannot use advisory locks without a current database")
+	}
+	dbDesc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return 0, err
+	}
+	return dbDesc.GetID(), nil
+}
+
+// AcquireAdvisoryLock is part of the eval.AdvisoryLocker interface.
+func (p *planner) AcquireAdvisoryLock(
+	ctx context.Context,
+	key eval.AdvisoryLockKey,
+	mode eval.AdvisoryLockMode,
+	txnScoped bool,
+	wait bool,
+) (bool, error) {
+	if p.advisoryLocks == nil {
+		return false, errAdvisoryLocksUnavailable
+	}
+	dbID, err := p.advisoryLockDatabaseID(ctx)
+	if err != nil {
+		return false, err
+	}
+	hold := advisoryLockHold{dbID: dbID, key: key, mode: mode}
+	state := p.advisoryLocks
+	txnLocks := state.txnLocks(p.txn)
+
+	// A session never conflicts with itself. Locks held by the session are
+	// either re-acquired without going to KV, or, if the session's own KV lock
+	// would block the request, the request is rejected.
+	if txnScoped {
+		if _, ok := state.session[hold]; ok {
+			return true, nil
+		}
+		if _, ok := state.session[advisoryLockHold{dbID: dbID, key: key, mode: eval.AdvisoryLockExclusive}]; ok {
+			return true, nil
+		}
+		if _, ok := txnLocks[hold]; ok {
+			return true, nil
+		}
+		if mode == eval.AdvisoryLockExclusive {
+			if _, ok := state.session[hold.otherMode()]; ok {
+				return false, errAdvisoryLockModeConflict(hold, "by the session in mode ShareLock")
+			}
+		}
+	} else {
+		if l, ok := state.session[hold]; ok {
+			l.count++
+			return true, nil
+		}
+		if _, ok := state.session[hold.otherMode()]; ok {
+			return false, errAdvisoryLockModeConflict(hold, "by the session in mode "+hold.otherMode().mode.String())
+		}
+		for _, h := range []advisoryLockHold{hold, hold.otherMode()} {
+			if _, ok := txnLocks[h]; ok && (h.mode == eval.AdvisoryLockExclusive || mode == eval.AdvisoryLockExclusive) {
+				return false, errAdvisoryLockModeConflict(hold, "by the current transaction in mode "+h.mode.String())
+			}
+		}
+	}
+
+	db := p.ExecCfg().DB
+	lockKey := makeAdvisoryLockKey(p.ExecCfg().Codec, dbID, key)
+	lockTimeout := p.SessionData().LockTimeout
+
+	if txnScoped {
+		acquired, err := lockAdvisoryLockKey(ctx, db, p.txn, lockKey, mode, wait, lockTimeout)
+		if err != nil || !acquired {
+			return false, err
+		}
+		txnLocks[hold] = lockKey
+		return true, nil
+	}
+
+	nodeID, _ := p.ExecCfg().NodeInfo.NodeID.OptionalNodeID()
+	priority := txnPriorityToProto(tree.UserPriority(p.SessionData().DefaultTxnPriority))
+	txn, err := state.lockTxn(ctx, db, nodeID, priority)
+	if err != nil {
+		return false, err
+	}
+	acquired, err := lockAdvisoryLockKey(ctx, db, txn, lockKey, mode, wait, lockTimeout)
+	if errors.HasType(err, (*kvpb.TransactionRetryWithProtoRefreshError)(nil)) {
+		return false, state.abandonLockTxn(ctx, err)
+	}
+	if err != nil || !acquired {
+		if len(state.session) == 0 {
+			if rollbackErr := state.finishLockTxn(ctx, false /* commit */); rollbackErr != nil {
+				log.Warningf(ctx, "unable to roll back advisory lock transaction: %v", rollbackErr)
+			}
+		}
+		return false, err
+	}
+	if state.session == nil {
+		state.session = make(map[advisoryLockHold]*sessionAdvisoryLock)
+	}
+	state.session[hold] = &sessionAdvisoryLock{key: lockKey, count: 1}
+	return true, nil
+}
+
+// ReleaseAdvisoryLock is part of the eval.AdvisoryLocker interface.
+func (p *planner) ReleaseAdvisoryLock(
+	ctx context.Context, key eval.AdvisoryLockKey, mode eval.AdvisoryLockMode,
+) (bool, error) {
+	if p.advisoryLocks == nil {
+		return false, errAdvisoryLocksUnavailable
+	}
+	dbID, err := p.advisoryLockDatabaseID(ctx)
+	if err != nil {
+		return false, err
+	}
+	hold := advisoryLockHold{dbID: dbID, key: key, mode: mode}
+	l, ok := p.advisoryLocks.session[hold]
+	if !ok {
+		p.BufferClientNotice(ctx, pgnotice.NewWithSeverityf(
+			"WARNING", "you don't own a lock of type %s", mode,
+		))
+		return false, nil
+	}
+	l.count--
+	if l.count > 0 {
+		return true, nil
+	}
+	if err := p.advisoryLocks.release(ctx, p.ExecCfg().DB, hold); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseAllAdvisoryLocks is part of the eval.AdvisoryLocker interface.
+func (p *planner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	if p.advisoryLocks == nil {
+		return errAdvisoryLocksUnavailable
+	}
+	return p.advisoryLocks.releaseAll(ctx)
+}
+
+// forEachAdvisoryLock calls fn for every holder of and waiter on an advisory
+// lock in the given database.
+func forEachAdvisoryLock(
+	ctx context.Context,
+	p *planner,
+	dbID descpb.ID,
+	fn func(key eval.AdvisoryLockKey, mode eval.AdvisoryLockMode, txn *enginepb.TxnMeta, granted bool) error,
+) error {
+	modeFromStrength := func(str lock.Strength) eval.AdvisoryLockMode {
+		if str == lock.Shared {
+			return eval.AdvisoryLockShared
+		}
+		return eval.AdvisoryLockExclusive
+	}
+	span := advisoryLockSpan(p.ExecCfg().Codec, dbID)
+	var prevKey roachpb.Key
+	for span.Key != nil {
+		b := p.Txn().NewBatch()
+		b.AddRawRequest(&kvpb.QueryLocksRequest{
+			RequestHeader:      kvpb.RequestHeaderFromSpan(span),
+			IncludeUncontended: true,
+		})
+		b.Header.MaxSpanRequestKeys = int64(rowinfra.ProductionKVBatchSize)
+		if err := p.Txn().Run(ctx, b); err != nil {
+			return err
+		}
+		resp := b.RawResponse().Responses[0].GetQueryLocks()
+		for i := range resp.Locks {
+			l := &resp.Locks[i]
+			_, key, err := decodeAdvisoryLockKey(p.ExecCfg().Codec, l.Key)
+			if err != nil {
+				return err
+			}
+			if l.LockHolder != nil {
+				if err := fn(key, modeFromStrength(l.LockStrength), l.LockHolder, true /* granted */); err != nil {
+					return err
+				}
+			}
+			// Locks held in shared mode by multiple transactions are reported once
+			// per holder, each with the same set of waiters.
+			if l.Key.Equal(prevKey) {
+				continue
+			}
+			prevKey = l.Key
+			for j := range l.Waiters {
+				w := &l.Waiters[j]
+				if err := fn(key, modeFromStrength(w.Strength), w.WaitingTxn, false /* granted */); err != nil {
+					return err
+				}
+			}
+		}
+		span = roachpb.Span{}
+		if resp.ResumeSpan != nil {
+			span = *resp.ResumeSpan
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterDomainNode struct {
+	n    *tree.AlterDomain
+	desc *typedesc.Mutable
+}
+
+// alterDomainNode implements planNode. We set n here to satisfy the linter.
+var _ planNode = &alterDomainNode{n: nil}
+
+func (p *planner) AlterDomain(ctx context.Context, n *tree.AlterDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+
+	// Resolve the type.
+	_, desc, err := p.ResolveMutableTypeDescriptor(ctx, n.Domain, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a domain", tree.AsStringWithFQNames(n.Domain, &p.semaCtx.Annotations))
+	}
+
+	// The user needs ownership privilege to alter the domain.
+	if err := p.canModifyType(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	return &alterDomainNode{
+		n:    n,
+		desc: desc,
+	}, nil
+}
+
+func (n *alterDomainNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeAlterCounterWithExtra("domain", n.n.Cmd.TelemetryName()))
+
+	jobDesc := tree.AsStringWithFQNames(n.n, params.p.Ann())
+	var err error
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterDomainAddConstraint:
+		err = params.p.addDomainConstraint(params.ctx, n.desc, &t.Constraint, jobDesc)
+	case *tree.AlterDomainDropConstraint:
+		err = params.p.dropDomainConstraint(params.ctx, n.desc, t, jobDesc)
+	default:
+		err = errors.AssertionFailedf("unknown alter domain cmd %s", t)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Write a log event.
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterType{
+			TypeName: tree.AsStringWithFQNames(n.n.Domain, params.p.Ann()),
+		})
+}
+
+// addDomainConstraint adds a CHECK constraint to a domain. The constraint is
+// enforced right away, but it is only made public by the type schema change
+// job once the values of the domain stored in tables have been validated.
+func (p *planner) addDomainConstraint(
+	ctx context.Context, desc *typedesc.Mutable, c *tree.DomainConstraint, jobDesc string,
+) error {
+	if c.Check == nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"only CHECK constraints can be added to a domain")
+	}
+	if domainHasValidatingConstraints(desc) {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"a constraint of domain %q is being validated, try again later", desc.Name)
+	}
+	constraint, err := makeDomainCheckConstraint(ctx, desc.Domain, desc.Name, c)
+	if err != nil {
+		return err
+	}
+	constraint.Validity = descpb.ConstraintValidity_Validating
+	desc.Domain.Constraints = append(desc.Domain.Constraints, constraint)
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+// dropDomainConstraint removes a constraint from a domain.
+func (p *planner) dropDomainConstraint(
+	ctx context.Context, desc *typedesc.Mutable, n *tree.AlterDomainDropConstraint, jobDesc string,
+) error {
+	idx := -1
+	for i := range desc.Domain.Constraints {
+		if desc.Domain.Constraints[i].Name == string(n.Constraint) {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		if n.IfExists {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"constraint %q of domain %q does not exist, skipping", n.Constraint, desc.Name))
+			return nil
+		}
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"constraint %q of domain %q does not exist", n.Constraint, desc.Name)
+	}
+	if desc.Domain.Constraints[idx].Validity == descpb.ConstraintValidity_Validating {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"constraint %q in the middle of being added, try again later", n.Constraint)
+	}
+	desc.Domain.Constraints = append(desc.Domain.Constraints[:idx], desc.Domain.Constraints[idx+1:]...)
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+func (n *alterDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *alterDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *alterDomainNode) Close(ctx context.Context)           {}
+func (n *alterDomainNode) ReadingOwnWrites()                   {}
@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -60,6 +61,15 @@ func (p *planner) AlterFunctionOptions(
 	); err != nil {
 		return nil, err
 	}
+	for _, option := range n.Options {
+		switch option.(type) {
+		case tree.RoutineSecurity, tree.RoutineSetConfig, tree.RoutineResetConfig:
+			if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_RoutineSecurityDefiner) {
+				return nil, unimplemented.New("routine security",
+					"SECURITY and SET clauses are not supported until version 24.1")
+			}
+		}
+	}
 
 	return &alterFunctionOptionsNode{n: n}, nil
 }
@@ -460,6 +470,7 @@ func toSchemaOverloadSignature(fnDesc *funcdesc.Mutable) descpb.SchemaDescriptor
 		ReturnType:  fnDesc.ReturnType.Type,
 		ReturnSet:   fnDesc.ReturnType.ReturnSet,
 		IsProcedure: fnDesc.IsProcedure(),
+		IsVariadic:  fnDesc.IsVariadic(),
 	}
 	for _, param := range fnDesc.Params {
 		if tree.IsParamIncludedIntoSignature(funcdesc.ToTreeRoutineParamClass(param.Class), ret.IsProcedure) {
@@ -0,0 +1,136 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
+	"github.com/cockroachdb/cockroach/pkg/sql/storageparam/viewstorageparam"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterMaterializedViewStorageParamsNode struct {
+	n    *tree.AlterMaterializedViewStorageParams
+	desc *tabledesc.Mutable
+}
+
+// alterMaterializedViewStorageParamsNode implements planNode. We set n here to
+// satisfy the linter.
+var _ planNode = &alterMaterializedViewStorageParamsNode{n: nil}
+
+// AlterMaterializedViewStorageParams sets or resets the storage parameters of
+// a materialized view.
+// Privileges: ownership of the view.
+func (p *planner) AlterMaterializedViewStorageParams(
+	ctx context.Context, n *tree.AlterMaterializedViewStorageParams,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER MATERIALIZED VIEW",
+	); err != nil {
+		return nil, err
+	}
+
+	_, desc, err := p.ResolveMutableTableDescriptorEx(ctx, n.Name, !n.IfExists, tree.ResolveRequireViewDesc)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		// Noop.
+		return newZeroNode(nil /* columns */), nil
+	}
+	if !desc.MaterializedView() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
+	}
+
+	// Only the owner or an admin (superuser) can alter the view.
+	hasAdminRole, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hasOwnership, err := p.HasOwnership(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	if !(hasOwnership || hasAdminRole) {
+		return nil, pgerror.Newf(
+			pgcode.InsufficientPrivilege,
+			"must be owner of materialized view %s",
+			desc.Name,
+		)
+	}
+
+	return &alterMaterializedViewStorageParamsNode{n: n, desc: desc}, nil
+}
+
+func (n *alterMaterializedViewStorageParamsNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeAlterCounterWithExtra("materialized_view", n.n.Cmd.TelemetryName()))
+
+	setter := &viewstorageparam.Setter{
+		IncrementalRefresh: n.desc.IncrementalRefreshJobID != catpb.InvalidJobID,
+	}
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterTableSetStorageParams:
+		if err := storageparam.Set(
+			params.ctx,
+			params.p.SemaCtx(),
+			params.EvalContext(),
+			t.StorageParams,
+			setter,
+		); err != nil {
+			return err
+		}
+	case *tree.AlterTableResetStorageParams:
+		if err := storageparam.Reset(
+			params.ctx,
+			params.EvalContext(),
+			t.Params,
+			setter,
+		); err != nil {
+			return err
+		}
+	default:
+		return errors.AssertionFailedf("unknown alter materialized view cmd %T", t)
+	}
+
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	if err := params.p.setMaterializedViewIncrementalRefresh(
+		params.ctx, n.desc, setter.IncrementalRefresh, jobDesc,
+	); err != nil {
+		return err
+	}
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.desc, descpb.InvalidMutationID, jobDesc,
+	); err != nil {
+		return err
+	}
+
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterTable{
+			TableName: params.p.ResolvedName(n.n.Name).FQString(),
+		})
+}
+
+func (n *alterMaterializedViewStorageParamsNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterMaterializedViewStorageParamsNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterMaterializedViewStorageParamsNode) Close(context.Context)        {}
@@ -257,6 +257,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 				if t.ValidationBehavior == tree.ValidationSkip {
 					return sqlerrors.NewUnsupportedUnvalidatedConstraintError(catconstants.ConstraintTypeUnique)
 				}
+				if d.Deferrability.IsDeferrable() {
+					return sqlerrors.NewDeferrableUniqueIndexError()
+				}
 
 				if err := validateColumnsAreAccessible(n.tableDesc, d.Columns); err != nil {
 					return err
@@ -365,6 +368,20 @@ func (n *alterTableNode) startExec(params runParams) error {
 						return err
 					}
 				}
+			case *tree.ExcludeConstraintTableDef:
+				if err := addExcludeConstraintTableDef(
+					params.ctx,
+					params.EvalContext(),
+					d,
+					n.tableDesc,
+					*tn,
+					NonEmptyTable,
+					t.ValidationBehavior,
+					params.p.SemaCtx(),
+				); err != nil {
+					return err
+				}
+
 			case *tree.CheckConstraintTableDef:
 				var err error
 				params.p.runWithOptions(resolveFlags{contextDatabaseID: n.tableDesc.ParentID}, func() {
@@ -638,65 +655,37 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if t.All {
 				return unimplemented.NewWithIssue(58736, "PARTITION ALL BY not yet implemented")
 			}
-			if n.tableDesc.GetLocalityConfig() != nil {
-				return pgerror.Newf(
-					pgcode.FeatureNotSupported,
-					"cannot set PARTITION BY on a table in a multi-region enabled database",
-				)
-			}
-			if n.tableDesc.IsPartitionAllBy() {
-				return unimplemented.NewWithIssue(58736, "changing partition of table with PARTITION ALL BY not yet implemented")
+			changed, err := alterTablePartitionBy(params, n.tableDesc, t.PartitionBy)
+			if err != nil {
+				return err
 			}
-			if n.tableDesc.GetPrimaryIndex().IsSharded() {
-				return pgerror.New(
-					pgcode.FeatureNotSupported,
-					"cannot set explicit partitioning with PARTITION BY on hash sharded primary key",
-				)
+			descriptorChanged = descriptorChanged || changed
+
+		case *tree.AlterTableAttachPartition:
+			partitionBy, err := partitionByWithAttachedPartition(
+				params.ExecCfg().Codec, n.tableDesc, t.Name, &t.Bound,
+			)
+			if err != nil {
+				return err
 			}
-			oldPartitioning := n.tableDesc.GetPrimaryIndex().GetPartitioning().DeepCopy()
-			if oldPartitioning.NumImplicitColumns() > 0 {
-				return unimplemented.NewWithIssue(
-					58731,
-					"cannot ALTER TABLE PARTITION BY on a table which already has implicit column partitioning",
-				)
+			changed, err := alterTablePartitionBy(params, n.tableDesc, partitionBy)
+			if err != nil {
+				return err
 			}
-			newPrimaryIndexDesc := n.tableDesc.GetPrimaryIndex().IndexDescDeepCopy()
-			newImplicitCols, newPartitioning, err := CreatePartitioning(
-				params.ctx, params.p.ExecCfg().Settings,
-				params.EvalContext(),
-				n.tableDesc,
-				newPrimaryIndexDesc,
-				t.PartitionBy,
-				nil, /* allowedNewColumnNames */
-				params.p.EvalContext().SessionData().ImplicitColumnPartitioningEnabled ||
-					n.tableDesc.IsLocalityRegionalByRow(),
+			descriptorChanged = descriptorChanged || changed
+
+		case *tree.AlterTableDetachPartition:
+			partitionBy, err := partitionByWithDetachedPartition(
+				params.ExecCfg().Codec, n.tableDesc, t.Name,
 			)
 			if err != nil {
 				return err
 			}
-			if newPartitioning.NumImplicitColumns > 0 {
-				return unimplemented.NewWithIssue(
-					58731,
-					"cannot ALTER TABLE and change the partitioning to contain implicit columns",
-				)
-			}
-			isIndexAltered := tabledesc.UpdateIndexPartitioning(&newPrimaryIndexDesc, true /* isIndexPrimary */, newImplicitCols, newPartitioning)
-			if isIndexAltered {
-				n.tableDesc.SetPrimaryIndex(newPrimaryIndexDesc)
-				descriptorChanged = true
-				if err := deleteRemovedPartitionZoneConfigs(
-					params.ctx,
-					params.p.InternalSQLTxn(),
-					n.tableDesc,
-					n.tableDesc.GetPrimaryIndexID(),
-					oldPartitioning,
-					n.tableDesc.GetPrimaryIndex().GetPartitioning(),
-					params.extendedEvalCtx.ExecCfg,
-					params.extendedEvalCtx.Tracing.KVTracingEnabled(),
-				); err != nil {
-					return err
-				}
+			changed, err := alterTablePartitionBy(params, n.tableDesc, partitionBy)
+			if err != nil {
+				return err
 			}
+			descriptorChanged = descriptorChanged || changed
 
 		case *tree.AlterTableSetAudit:
 			changed, err := params.p.setAuditMode(params.ctx, n.tableDesc, t.Mode)
@@ -918,6 +907,74 @@ func (p *planner) setAuditMode(
 	return desc.SetAuditMode(auditMode)
 }
 
+// alterTablePartitionBy sets the partitioning of the primary index of the
+// table to the given PARTITION BY clause, and removes the zone configs of the
+// partitions that no longer exist. It returns true if the descriptor was
+// changed.
+func alterTablePartitionBy(
+	params runParams, tableDesc *tabledesc.Mutable, partitionBy *tree.PartitionBy,
+) (bool, error) {
+	if tableDesc.GetLocalityConfig() != nil {
+		return false, pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			"cannot set PARTITION BY on a table in a multi-region enabled database",
+		)
+	}
+	if tableDesc.IsPartitionAllBy() {
+		return false, unimplemented.NewWithIssue(58736, "changing partition of table with PARTITION ALL BY not yet implemented")
+	}
+	if tableDesc.GetPrimaryIndex().IsSharded() {
+		return false, pgerror.New(
+			pgcode.FeatureNotSupported,
+			"cannot set explicit partitioning with PARTITION BY on hash sharded primary key",
+		)
+	}
+	oldPartitioning := tableDesc.GetPrimaryIndex().GetPartitioning().DeepCopy()
+	if oldPartitioning.NumImplicitColumns() > 0 {
+		return false, unimplemented.NewWithIssue(
+			58731,
+			"cannot ALTER TABLE PARTITION BY on a table which already has implicit column partitioning",
+		)
+	}
+	newPrimaryIndexDesc := tableDesc.GetPrimaryIndex().IndexDescDeepCopy()
+	newImplicitCols, newPartitioning, err := CreatePartitioning(
+		params.ctx, params.p.ExecCfg().Settings,
+		params.EvalContext(),
+		tableDesc,
+		newPrimaryIndexDesc,
+		partitionBy,
+		nil, /* allowedNewColumnNames */
+		params.p.EvalContext().SessionData().ImplicitColumnPartitioningEnabled ||
+			tableDesc.IsLocalityRegionalByRow(),
+	)
+	if err != nil {
+		return false, err
+	}
+	if newPartitioning.NumImplicitColumns > 0 {
+		return false, unimplemented.NewWithIssue(
+			58731,
+			"cannot ALTER TABLE and change the partitioning to contain implicit columns",
+		)
+	}
+	isIndexAltered := tabledesc.UpdateIndexPartitioning(&newPrimaryIndexDesc, true /* isIndexPrimary */, newImplicitCols, newPartitioning)
+	if isIndexAltered {
+		tableDesc.SetPrimaryIndex(newPrimaryIndexDesc)
+		if err := deleteRemovedPartitionZoneConfigs(
+			params.ctx,
+			params.p.InternalSQLTxn(),
+			tableDesc,
+			tableDesc.GetPrimaryIndexID(),
+			oldPartitioning,
+			tableDesc.GetPrimaryIndex().GetPartitioning(),
+			params.extendedEvalCtx.ExecCfg,
+			params.extendedEvalCtx.Tracing.KVTracingEnabled(),
+		); err != nil {
+			return false, err
+		}
+	}
+	return isIndexAltered, nil
+}
+
 func (n *alterTableNode) Next(runParams) (bool, error) { return false, nil }
 func (n *alterTableNode) Values() tree.Datums          { return tree.Datums{} }
 func (n *alterTableNode) Close(context.Context)        {}
@@ -1576,6 +1633,9 @@ func validateConstraintNameIsNotUsed(
 	case *tree.ForeignKeyConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
+	case *tree.ExcludeConstraintTableDef:
+		name = d.Name
+		hasIfNotExists = d.IfNotExists
 	case *tree.UniqueConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
@@ -1820,6 +1880,9 @@ func dropColumnImpl(
 	if err := schemaexpr.ValidateTTLExpressionDoesNotDependOnColumn(tableDesc, rowLevelTTL, colToDrop); err != nil {
 		return nil, err
 	}
+	if err := validateColumnNotReferencedByTriggers(tableDesc, colToDrop); err != nil {
+		return nil, err
+	}
 
 	if tableDesc.GetPrimaryIndex().CollectKeyColumnIDs().Contains(colToDrop.GetID()) {
 		return nil, sqlerrors.NewColumnReferencedByPrimaryKeyError(colToDrop.GetName())
@@ -1607,6 +1607,12 @@ func ValidateConstraint(
 			return txn.WithSyntheticDescriptors(
 				[]catalog.Descriptor{tableDesc},
 				func() error {
+					if uwi.IsExclusion() {
+						return validateExclusionConstraint(
+							ctx, tableDesc, uwi.UniqueWithoutIndexDesc(), indexIDForValidation, txn,
+							sessionData.User(), false, /* preExisting */
+						)
+					}
 					return validateUniqueConstraint(
 						ctx, tableDesc, uwi.GetName(),
 						uwi.CollectKeyColumnIDs().Ordered(),
@@ -2813,6 +2819,11 @@ func validateUniqueWithoutIndexConstraintInTxn(
 	return txn.WithSyntheticDescriptors(
 		syntheticDescs,
 		func() error {
+			if uc.IsExclusion() {
+				return validateExclusionConstraint(
+					ctx, tableDesc, uc, 0 /* indexIDForValidation */, txn, user, false, /* preExisting */
+				)
+			}
 			return validateUniqueConstraint(
 				ctx,
 				tableDesc,
@@ -78,6 +78,8 @@ var (
 		catconstants.MVCCStatistics,
 		catconstants.TxnExecInsightsTableName,
 		catconstants.StmtExecInsightsTableName,
+		catconstants.ReplicationSlotsTableName,
+		catconstants.NotificationsTableName,
 	}
 
 	readWriteSystemSequences = []catconstants.SystemTableName{
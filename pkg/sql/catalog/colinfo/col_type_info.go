@@ -140,6 +140,22 @@ func ValidateColumnDefType(ctx context.Context, version clusterversion.Handle, t
 			)
 		}
 
+	case types.RangeFamily, types.MultirangeFamily:
+		if !version.IsActive(ctx, clusterversion.V24_1_RangeTypes) {
+			return pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"range types not supported until version 24.1",
+			)
+		}
+
+	case types.JsonpathFamily:
+		if !version.IsActive(ctx, clusterversion.V24_1_Jsonpath) {
+			return pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"jsonpath not supported until version 24.1",
+			)
+		}
+
 	default:
 		return pgerror.Newf(pgcode.InvalidTableDefinition,
 			"value type %s cannot be used for table columns", t.String())
@@ -173,7 +189,7 @@ func ColumnTypeIsInvertedIndexable(t *types.T) bool {
 	switch t.Family() {
 	case types.ArrayFamily:
 		return t.ArrayContents().Family() != types.RefCursorFamily
-	case types.JsonFamily, types.StringFamily:
+	case types.JsonFamily, types.StringFamily, types.RangeFamily, types.MultirangeFamily:
 		return true
 	}
 	return ColumnTypeIsOnlyInvertedIndexable(t)
@@ -211,7 +227,7 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		}
 	case types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
 		return true
-	case types.TSVectorFamily, types.TSQueryFamily:
+	case types.TSVectorFamily, types.TSQueryFamily, types.JsonpathFamily:
 		return true
 	}
 	return false
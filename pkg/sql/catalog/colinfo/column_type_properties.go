@@ -64,6 +64,8 @@ func CanHaveCompositeKeyEncoding(typ *types.T) bool {
 			}
 		}
 		return false
+	case types.RangeFamily, types.MultirangeFamily:
+		return CanHaveCompositeKeyEncoding(typ.RangeContents())
 	case types.BoolFamily,
 		types.IntFamily,
 		types.DateFamily,
@@ -85,9 +87,11 @@ func CanHaveCompositeKeyEncoding(typ *types.T) bool {
 		types.PGLSNFamily,
 		types.RefCursorFamily,
 		types.VoidFamily,
+		types.TriggerFamily,
 		types.EncodedKeyFamily,
 		types.TSQueryFamily,
-		types.TSVectorFamily:
+		types.TSVectorFamily,
+		types.JsonpathFamily:
 		return false
 	case types.UnknownFamily,
 		types.AnyFamily:
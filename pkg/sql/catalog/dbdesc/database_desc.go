@@ -14,6 +14,7 @@ package dbdesc
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -22,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
@@ -228,6 +231,29 @@ func (desc *immutable) GetNonDroppedSchemaName(schemaID descpb.ID) string {
 	return ""
 }
 
+// ForEachPublication implements the DatabaseDescriptor interface.
+func (desc *immutable) ForEachPublication(
+	f func(pub *descpb.DatabaseDescriptor_Publication) error,
+) error {
+	for i := range desc.Publications {
+		if err := f(&desc.Publications[i]); err != nil {
+			return iterutil.Map(err)
+		}
+	}
+	return nil
+}
+
+// GetPublication implements the DatabaseDescriptor interface.
+func (desc *immutable) GetPublication(name string) *descpb.DatabaseDescriptor_Publication {
+	i := sort.Search(len(desc.Publications), func(i int) bool {
+		return desc.Publications[i].Name >= name
+	})
+	if i < len(desc.Publications) && desc.Publications[i].Name == name {
+		return &desc.Publications[i]
+	}
+	return nil
+}
+
 // ValidateSelf validates that the database descriptor is well formed.
 // Checks include validate the database name, and verifying that there
 // is at least one read and write user.
@@ -256,6 +282,33 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	}
 
 	desc.maybeValidateSystemDatabaseSchemaVersion(vea)
+	desc.validatePublications(vea)
+}
+
+// validatePublications checks that the publications are sorted by name, that
+// their names are unique and that they reference valid table IDs.
+func (desc *immutable) validatePublications(vea catalog.ValidationErrorAccumulator) {
+	for i := range desc.Publications {
+		pub := &desc.Publications[i]
+		if pub.Name == "" {
+			vea.Report(errors.AssertionFailedf("empty publication name"))
+		}
+		if i > 0 && desc.Publications[i-1].Name >= pub.Name {
+			vea.Report(errors.AssertionFailedf(
+				"publications are not sorted or not unique: %q before %q",
+				desc.Publications[i-1].Name, pub.Name))
+		}
+		if pub.AllTables && len(pub.TableIDs) > 0 {
+			vea.Report(errors.AssertionFailedf(
+				"publication %q includes all tables but lists table IDs", pub.Name))
+		}
+		for _, id := range pub.TableIDs {
+			if id == descpb.InvalidID {
+				vea.Report(errors.AssertionFailedf(
+					"invalid table ID in publication %q", pub.Name))
+			}
+		}
+	}
 }
 
 // validateMultiRegion performs checks specific to multi-region DBs.
@@ -553,6 +606,34 @@ func (desc *Mutable) AddSchemaToDatabase(
 	desc.Schemas[schemaName] = schemaInfo
 }
 
+// AddPublication adds a publication to the database. Publications are kept
+// sorted by name. An error is returned if a publication with the same name
+// already exists.
+func (desc *Mutable) AddPublication(pub descpb.DatabaseDescriptor_Publication) error {
+	i := sort.Search(len(desc.Publications), func(i int) bool {
+		return desc.Publications[i].Name >= pub.Name
+	})
+	if i < len(desc.Publications) && desc.Publications[i].Name == pub.Name {
+		return pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", pub.Name)
+	}
+	desc.Publications = append(desc.Publications, descpb.DatabaseDescriptor_Publication{})
+	copy(desc.Publications[i+1:], desc.Publications[i:])
+	desc.Publications[i] = pub
+	return nil
+}
+
+// RemovePublication removes the publication with the given name from the
+// database. It returns false if the database has no such publication.
+func (desc *Mutable) RemovePublication(name string) bool {
+	for i := range desc.Publications {
+		if desc.Publications[i].Name == name {
+			desc.Publications = append(desc.Publications[:i], desc.Publications[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // GetDeclarativeSchemaChangerState is part of the catalog.MutableDescriptor
 // interface.
 func (desc *immutable) GetDeclarativeSchemaChangerState() *scpb.DescriptorState {
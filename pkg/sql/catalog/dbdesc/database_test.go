@@ -113,6 +113,29 @@ func TestValidateDatabaseDesc(t *testing.T) {
 				Privileges:   catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
 			},
 		},
+		{
+			`publications are not sorted or not unique: "pub" before "pub"`,
+			descpb.DatabaseDescriptor{
+				Name: "db",
+				ID:   200,
+				Publications: []descpb.DatabaseDescriptor_Publication{
+					{Name: "pub", TableIDs: []descpb.ID{104}},
+					{Name: "pub", AllTables: true},
+				},
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+			},
+		},
+		{
+			`invalid table ID in publication "pub"`,
+			descpb.DatabaseDescriptor{
+				Name: "db",
+				ID:   200,
+				Publications: []descpb.DatabaseDescriptor_Publication{
+					{Name: "pub", TableIDs: []descpb.ID{0}},
+				},
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+			},
+		},
 	}
 	for i, d := range testData {
 		t.Run(d.err, func(t *testing.T) {
@@ -74,6 +74,9 @@ type IndexID = catid.IndexID
 // ConstraintID is a custom type for TableDescriptor constraint IDs.
 type ConstraintID = catid.ConstraintID
 
+// TriggerID is a custom type for TableDescriptor trigger IDs.
+type TriggerID = catid.TriggerID
+
 // DescriptorVersion is a custom type for TableDescriptor Versions.
 type DescriptorVersion uint64
 
@@ -327,6 +330,23 @@ func (u *UniqueWithoutIndexConstraint) IsPartial() bool {
 	return u.Predicate != ""
 }
 
+// IsExclusion returns true if the constraint is an exclusion constraint.
+func (u *UniqueWithoutIndexConstraint) IsExclusion() bool {
+	return u.Exclusion != nil
+}
+
+// IsRange returns true if the element compares ranges built out of the values
+// of two columns.
+func (e *ExclusionConstraint_Element) IsRange() bool {
+	return e.RangeFunction != ""
+}
+
+// InclusiveBounds returns true if both bounds of the ranges compared by the
+// element are inclusive.
+func (e *ExclusionConstraint_Element) InclusiveBounds() bool {
+	return e.RangeBounds == "[]"
+}
+
 // GetParentID implements the catalog.NameKeyHaver interface.
 func (ni NameInfo) GetParentID() ID {
 	return ni.ParentID
@@ -295,6 +295,12 @@ type DatabaseDescriptor interface {
 	// HasPublicSchemaWithDescriptor returns true iff the database has a public
 	// schema which itself has a descriptor.
 	HasPublicSchemaWithDescriptor() bool
+	// ForEachPublication iterates f over each publication of the database in
+	// name order. iterutil.StopIteration is supported.
+	ForEachPublication(f func(pub *descpb.DatabaseDescriptor_Publication) error) error
+	// GetPublication returns the publication with the given name, or nil if the
+	// database has no such publication.
+	GetPublication(name string) *descpb.DatabaseDescriptor_Publication
 }
 
 // TableDescriptor is an interface around the table descriptor types.
@@ -629,6 +635,8 @@ type TableDescriptor interface {
 	// GetDependsOnFunctions returns the IDs of all functions that this view
 	// depends on. It's only non-nil if IsView is true.
 	GetDependsOnFunctions() []descpb.ID
+	// GetTriggers returns the triggers defined on this table.
+	GetTriggers() []descpb.TableDescriptor_Trigger
 
 	// AllConstraints returns all constraints in this table, regardless if
 	// they're enforced yet or not. The ordering of the constraints within this
@@ -753,6 +761,10 @@ type TableDescriptor interface {
 	// IsRefreshViewRequired indicates if a REFRESH VIEW operation needs to be called
 	// on a materialized view.
 	IsRefreshViewRequired() bool
+	// GetIncrementalRefreshJobID returns the ID of the job which incrementally
+	// refreshes a materialized view, or 0 if the view is not refreshed
+	// incrementally.
+	GetIncrementalRefreshJobID() catpb.JobID
 	// GetInProgressImportStartTime returns the start wall time of the in progress import,
 	// if it exists.
 	GetInProgressImportStartTime() int64
@@ -820,6 +832,11 @@ type TypeDescriptor interface {
 	// nil otherwise.
 	AsCompositeTypeDescriptor() CompositeTypeDescriptor
 
+	// AsDomainTypeDescriptor returns this instance cast to
+	// DomainTypeDescriptor if this type is a domain type,
+	// nil otherwise.
+	AsDomainTypeDescriptor() DomainTypeDescriptor
+
 	// AsTableImplicitRecordTypeDescriptor returns this instance cast to
 	// TableImplicitRecordTypeDescriptor if this type is an implicit table record
 	// type, nil otherwise.
@@ -907,6 +924,26 @@ type CompositeTypeDescriptor interface {
 	GetElementType(ordinal int) *types.T
 }
 
+// DomainTypeDescriptor is the TypeDescriptor subtype for domain types, which
+// are base types with constraints.
+type DomainTypeDescriptor interface {
+	NonAliasTypeDescriptor
+
+	// BaseType returns the type on which the domain is defined.
+	BaseType() *types.T
+
+	// IsNotNull returns true if the domain does not allow NULL values.
+	IsNotNull() bool
+
+	// NumDomainConstraints returns the number of CHECK constraints of the
+	// domain.
+	NumDomainConstraints() int
+
+	// GetDomainConstraint returns the CHECK constraint of the domain at the
+	// given ordinal.
+	GetDomainConstraint(ordinal int) *descpb.TypeDescriptor_Domain_Constraint
+}
+
 // TableImplicitRecordTypeDescriptor is the TypeDescriptor subtype for the
 // record type implicitly defined by a table.
 type TableImplicitRecordTypeDescriptor interface {
@@ -954,6 +991,14 @@ type FunctionDescriptor interface {
 	// GetNullInputBehavior returns the function's attribute on null inputs.
 	GetNullInputBehavior() catpb.Function_NullInputBehavior
 
+	// GetSecurity returns whether the function executes with the privileges of
+	// the invoking user or of the function owner.
+	GetSecurity() catpb.Function_Security
+
+	// GetConfig returns the session variables that are set while the function
+	// executes, each in the form name=value.
+	GetConfig() []string
+
 	// GetFunctionBody returns the function body string.
 	GetFunctionBody() string
 
@@ -991,6 +1036,10 @@ type FunctionDescriptor interface {
 	// IsProcedure returns true if the descriptor represents a procedure. It
 	// returns false if the descriptor represents a user-defined function.
 	IsProcedure() bool
+
+	// IsVariadic returns true if the last parameter in the signature of the
+	// routine is a VARIADIC parameter.
+	IsVariadic() bool
 }
 
 // FilterDroppedDescriptor returns an error if the descriptor state is DROP.
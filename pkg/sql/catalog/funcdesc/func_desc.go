@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
@@ -330,9 +331,9 @@ func (desc *immutable) validateInboundFunctionRef(
 			backrefFunctionDesc.GetName(), backrefFunctionDesc.GetID())
 	}
 	// Validate all other references are unset.
-	if ref.ColumnIDs != nil || ref.IndexIDs != nil || ref.ConstraintIDs != nil {
-		return errors.AssertionFailedf("function reference has invalid references (%v, %v %v)",
-			ref.ColumnIDs, ref.IndexIDs, ref.ConstraintIDs)
+	if ref.ColumnIDs != nil || ref.IndexIDs != nil || ref.ConstraintIDs != nil || ref.TriggerIDs != nil {
+		return errors.AssertionFailedf("function reference has invalid references (%v, %v, %v, %v)",
+			ref.ColumnIDs, ref.IndexIDs, ref.ConstraintIDs, ref.TriggerIDs)
 	}
 	// Validate a reference exists to this function.
 	for _, refID := range backrefFunctionDesc.GetDependsOnFunctions() {
@@ -409,6 +410,22 @@ func (desc *immutable) validateInboundTableRef(
 			cstID, backRefTbl.GetName(), backRefTbl.GetID(), desc.GetName(), desc.GetID(),
 		)
 	}
+	for _, triggerID := range by.TriggerIDs {
+		var found bool
+		for _, trigger := range backRefTbl.GetTriggers() {
+			if trigger.ID == triggerID {
+				found = trigger.FuncID == desc.GetID()
+				break
+			}
+		}
+		if !found {
+			return errors.AssertionFailedf(
+				"trigger %d in depended-on-by relation %q (%d) does not have reference to function %q (%d)",
+				triggerID, backRefTbl.GetName(), backRefTbl.GetID(), desc.GetName(), desc.GetID(),
+			)
+		}
+		foundInTable = true
+	}
 	if foundInTable {
 		return nil
 	}
@@ -566,6 +583,34 @@ func (desc *Mutable) SetNullInputBehavior(v catpb.Function_NullInputBehavior) {
 	desc.NullInputBehavior = v
 }
 
+// SetSecurity sets the security attribute.
+func (desc *Mutable) SetSecurity(v catpb.Function_Security) {
+	desc.Security = v
+}
+
+// SetConfig sets the configuration parameter with the given name to the given
+// value. Any existing setting for the parameter is replaced.
+func (desc *Mutable) SetConfig(name, value string) {
+	desc.ResetConfig(name)
+	desc.Config = append(desc.Config, name+"="+value)
+}
+
+// ResetConfig removes the setting for the configuration parameter with the
+// given name, if any.
+func (desc *Mutable) ResetConfig(name string) {
+	for i := range desc.Config {
+		if n, _ := funcinfo.SplitConfig(desc.Config[i]); n == name {
+			desc.Config = append(desc.Config[:i], desc.Config[i+1:]...)
+			return
+		}
+	}
+}
+
+// ResetAllConfig removes the settings for all configuration parameters.
+func (desc *Mutable) ResetAllConfig() {
+	desc.Config = nil
+}
+
 // SetLang sets the function language.
 func (desc *Mutable) SetLang(v catpb.Function_Language) {
 	desc.Lang = v
@@ -630,6 +675,55 @@ func (desc *Mutable) RemoveConstraintReference(id descpb.ID, constraintID descpb
 	}
 }
 
+// AddTriggerReference adds back reference to a trigger to the function.
+func (desc *Mutable) AddTriggerReference(id descpb.ID, triggerID descpb.TriggerID) {
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			for _, existing := range desc.DependedOnBy[i].TriggerIDs {
+				if existing == triggerID {
+					return
+				}
+			}
+			desc.DependedOnBy[i].TriggerIDs = append(desc.DependedOnBy[i].TriggerIDs, triggerID)
+			sort.Slice(desc.DependedOnBy[i].TriggerIDs, func(a, b int) bool {
+				return desc.DependedOnBy[i].TriggerIDs[a] < desc.DependedOnBy[i].TriggerIDs[b]
+			})
+			return
+		}
+	}
+	desc.DependedOnBy = append(
+		desc.DependedOnBy,
+		descpb.FunctionDescriptor_Reference{
+			ID:         id,
+			TriggerIDs: []descpb.TriggerID{triggerID},
+		},
+	)
+	sort.Slice(desc.DependedOnBy, func(i, j int) bool {
+		return desc.DependedOnBy[i].ID < desc.DependedOnBy[j].ID
+	})
+}
+
+// RemoveTriggerReference removes back reference to a trigger from the
+// function.
+func (desc *Mutable) RemoveTriggerReference(id descpb.ID, triggerID descpb.TriggerID) {
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			ids := desc.DependedOnBy[i].TriggerIDs[:0]
+			for _, existing := range desc.DependedOnBy[i].TriggerIDs {
+				if existing != triggerID {
+					ids = append(ids, existing)
+				}
+			}
+			if len(ids) == 0 {
+				ids = nil
+			}
+			desc.DependedOnBy[i].TriggerIDs = ids
+			desc.maybeRemoveTableReference(id)
+			return
+		}
+	}
+}
+
 // AddFunctionReference adds back reference for a function invoking this function.
 func (desc *Mutable) AddFunctionReference(id descpb.ID) error {
 	for _, f := range desc.DependsOnFunctions {
@@ -705,13 +799,14 @@ func (desc *Mutable) RemoveColumnReference(id descpb.ID, colID descpb.ColumnID)
 }
 
 // maybeRemoveTableReference removes a table's references from the function if
-// the column, index and constraint references are all empty. This function is
-// only used internally when removing an individual column, index or constraint
-// reference.
+// the column, index, constraint and trigger references are all empty. This
+// function is only used internally when removing an individual column, index,
+// constraint or trigger reference.
 func (desc *Mutable) maybeRemoveTableReference(id descpb.ID) {
 	var ret []descpb.FunctionDescriptor_Reference
 	for _, ref := range desc.DependedOnBy {
-		if ref.ID == id && len(ref.ColumnIDs) == 0 && len(ref.IndexIDs) == 0 && len(ref.ConstraintIDs) == 0 {
+		if ref.ID == id && len(ref.ColumnIDs) == 0 && len(ref.IndexIDs) == 0 &&
+			len(ref.ConstraintIDs) == 0 && len(ref.TriggerIDs) == 0 {
 			continue
 		}
 		ret = append(ret, ref)
@@ -796,10 +891,16 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 		})
 	}
 	ret.ReturnType = tree.FixedReturnType(desc.ReturnType.Type)
+	if tree.ContainsPolymorphicType(desc.ReturnType.Type) {
+		ret.ReturnType = tree.PolymorphicReturnType(
+			signatureTypes, desc.IsVariadic(), desc.ReturnType.Type,
+		)
+	}
 	// TODO(yuzefovich): we should not be setting ReturnsRecordType to 'true'
 	// when the return type is based on output parameters.
 	ret.ReturnsRecordType = types.IsRecordType(desc.ReturnType.Type)
 	ret.Types = signatureTypes
+	ret.Variadic = desc.IsVariadic()
 	ret.Volatility, err = desc.getOverloadVolatility()
 	if err != nil {
 		return nil, err
@@ -811,6 +912,9 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 	if desc.ReturnType.ReturnSet {
 		ret.Class = tree.GeneratorClass
 	}
+	ret.Security = desc.getCreateExprSecurity()
+	ret.Owner = desc.GetPrivileges().Owner()
+	ret.Config = desc.Config
 
 	return ret, nil
 }
@@ -874,12 +978,22 @@ func (desc *immutable) ToCreateExpr() (ret *tree.CreateRoutine, err error) {
 			}
 		}
 	}
-	// We only store 5 function attributes at the moment. We may extend the
-	// pre-allocated capacity in the future.
-	ret.Options = make(tree.RoutineOptions, 0, 5)
+	// We store 5 function attributes, plus the optional security attribute
+	// and configuration parameter settings.
+	ret.Options = make(tree.RoutineOptions, 0, 6+len(desc.Config))
 	ret.Options = append(ret.Options, desc.getCreateExprVolatility())
 	ret.Options = append(ret.Options, tree.RoutineLeakproof(desc.LeakProof))
 	ret.Options = append(ret.Options, desc.getCreateExprNullInputBehavior())
+	if desc.Security == catpb.Function_DEFINER {
+		ret.Options = append(ret.Options, tree.RoutineDefiner)
+	}
+	for _, setting := range desc.Config {
+		opt, err := getCreateExprSetConfig(setting)
+		if err != nil {
+			return nil, err
+		}
+		ret.Options = append(ret.Options, opt)
+	}
 	ret.Options = append(ret.Options, tree.RoutineBodyStr(desc.FunctionBody))
 	ret.Options = append(ret.Options, desc.getCreateExprLang())
 	return ret, nil
@@ -890,6 +1004,17 @@ func (desc *immutable) IsProcedure() bool {
 	return desc.FunctionDescriptor.IsProcedure
 }
 
+// IsVariadic implements the FunctionDescriptor interface.
+func (desc *immutable) IsVariadic() bool {
+	for i := len(desc.Params) - 1; i >= 0; i-- {
+		class := ToTreeRoutineParamClass(desc.Params[i].Class)
+		if tree.IsParamIncludedIntoSignature(class, desc.IsProcedure()) {
+			return class == tree.RoutineParamVariadic
+		}
+	}
+	return false
+}
+
 func (desc *immutable) getCreateExprLang() tree.RoutineLanguage {
 	switch desc.Lang {
 	case catpb.Function_SQL:
@@ -900,6 +1025,32 @@ func (desc *immutable) getCreateExprLang() tree.RoutineLanguage {
 	return tree.RoutineLangUnknown
 }
 
+func (desc *immutable) getCreateExprSecurity() tree.RoutineSecurity {
+	if desc.Security == catpb.Function_DEFINER {
+		return tree.RoutineDefiner
+	}
+	return tree.RoutineInvoker
+}
+
+// getCreateExprSetConfig returns the SET clause for the given configuration
+// parameter setting. The search_path setting is split into its elements, the
+// same way that it was specified.
+func getCreateExprSetConfig(setting string) (tree.RoutineSetConfig, error) {
+	name, value := funcinfo.SplitConfig(setting)
+	if name != "search_path" {
+		return tree.RoutineSetConfig{Name: name, Values: tree.Exprs{tree.NewStrVal(value)}}, nil
+	}
+	paths, err := sessiondata.ParseSearchPath(value)
+	if err != nil {
+		return tree.RoutineSetConfig{}, err
+	}
+	values := make(tree.Exprs, len(paths))
+	for i := range paths {
+		values[i] = tree.NewStrVal(paths[i])
+	}
+	return tree.RoutineSetConfig{Name: name, Values: values}, nil
+}
+
 func (desc *immutable) getCreateExprVolatility() tree.RoutineVolatility {
 	switch desc.Volatility {
 	case catpb.Function_IMMUTABLE:
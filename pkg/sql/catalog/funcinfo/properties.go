@@ -11,6 +11,8 @@
 package funcinfo
 
 import (
+	"strings"
+
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -133,3 +135,22 @@ func ParamClassToProto(v tree.RoutineParamClass) (catpb.Function_Param_Class, er
 
 	return -1, errors.AssertionFailedf("unknown function parameter class %q", v)
 }
+
+// SecurityToProto converts sql statement input security mode to protobuf type.
+func SecurityToProto(v tree.RoutineSecurity) (catpb.Function_Security, error) {
+	switch v {
+	case tree.RoutineInvoker:
+		return catpb.Function_INVOKER, nil
+	case tree.RoutineDefiner:
+		return catpb.Function_DEFINER, nil
+	}
+
+	return -1, errors.AssertionFailedf("unknown function security %q", v)
+}
+
+// SplitConfig splits a configuration parameter setting of the form name=value,
+// as stored in a function descriptor, into its name and value.
+func SplitConfig(setting string) (name, value string) {
+	name, value, _ = strings.Cut(setting, "=")
+	return name, value
+}
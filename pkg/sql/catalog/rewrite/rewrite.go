@@ -86,6 +86,13 @@ func TableDescs(
 			return err
 		}
 
+		// Drop triggers whose trigger functions are not being restored, and
+		// rewrite the function references of the remaining ones.
+		dropTriggersMissingDeps(table, descriptorRewrites)
+		for i := range table.Triggers {
+			table.Triggers[i].FuncID = descriptorRewrites[table.Triggers[i].FuncID].ID
+		}
+
 		// Remap type IDs and sequence IDs in all serialized expressions within the TableDescriptor.
 		// TODO (rohany): This needs tests once partial indexes are ready.
 		if err := tabledesc.ForEachExprStringInTableDesc(table, func(expr *string) error {
@@ -948,6 +955,16 @@ func dropColumnExpressionsMissingDeps(
 	return nil
 }
 
+func dropTriggersMissingDeps(table *tabledesc.Mutable, descriptorRewrites jobspb.DescRewriteMap) {
+	var newTriggers []descpb.TableDescriptor_Trigger
+	for i := range table.Triggers {
+		if _, ok := descriptorRewrites[table.Triggers[i].FuncID]; ok {
+			newTriggers = append(newTriggers, table.Triggers[i])
+		}
+	}
+	table.Triggers = newTriggers
+}
+
 // DatabaseDescs rewrites all ID's in the input slice of DatabaseDescriptors
 // using the input ID rewrite mapping. The function elides remapping offline schemas,
 // since they will not get restored into the cluster.
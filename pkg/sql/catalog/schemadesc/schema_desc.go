@@ -537,6 +537,10 @@ func (desc *immutable) GetResolvedFuncDefinition(
 			)
 		}
 		overload.Types = paramTypes
+		overload.Variadic = sig.IsVariadic
+		if tree.ContainsPolymorphicType(retType) {
+			overload.ReturnType = tree.PolymorphicReturnType(paramTypes, sig.IsVariadic, retType)
+		}
 		prefixedOverload := tree.MakeQualifiedOverload(desc.GetName(), overload)
 		funcDef.Overloads = append(funcDef.Overloads, prefixedOverload)
 	}
@@ -0,0 +1,203 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// TriggerNewTableName and TriggerOldTableName are the names through which the
+// WHEN condition and the function of a row-level trigger can refer to the new
+// and old versions of the row being modified.
+const (
+	TriggerNewTableName tree.Name = "new"
+	TriggerOldTableName tree.Name = "old"
+)
+
+// TriggerWhenExprOptions describes the trigger to which a WHEN condition
+// belongs. It determines which of NEW and OLD the condition may reference.
+type TriggerWhenExprOptions struct {
+	ForEachRow bool
+	HasInsert  bool
+	HasUpdate  bool
+	HasDelete  bool
+}
+
+// ValidateTriggerWhenExpr verifies that the WHEN condition of a trigger is a
+// boolean expression which only references columns of desc through NEW and
+// OLD, and only when the trigger's events make those rows available. The
+// serialized expression and the IDs of the referenced columns are returned.
+func ValidateTriggerWhenExpr(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	expr tree.Expr,
+	opts TriggerWhenExprOptions,
+	semaCtx *tree.SemaContext,
+) (string, catalog.TableColSet, error) {
+	var colIDs catalog.TableColSet
+	replacedExpr, err := walkTriggerColumnRefs(expr, func(
+		prefix tree.Name, colName tree.Name,
+	) (tree.Expr, error) {
+		if !opts.ForEachRow {
+			return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"statement trigger's WHEN condition cannot reference column values")
+		}
+		if prefix == TriggerNewTableName && opts.HasDelete {
+			return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"DELETE trigger's WHEN condition cannot reference NEW values")
+		}
+		if prefix == TriggerOldTableName && opts.HasInsert {
+			return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"INSERT trigger's WHEN condition cannot reference OLD values")
+		}
+		col, err := catalog.MustFindColumnByTreeName(desc, colName)
+		if err != nil || col.Dropped() {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist, referenced in %q", colName, expr.String())
+		}
+		if col.IsInaccessible() {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q is inaccessible and cannot be referenced", colName)
+		}
+		colIDs.Add(col.GetID())
+		return &triggerDummyColumn{typ: col.GetType(), prefix: prefix, name: colName}, nil
+	})
+	if err != nil {
+		return "", colIDs, err
+	}
+
+	typedExpr, err := SanitizeVarFreeExpr(
+		ctx,
+		replacedExpr,
+		types.Bool,
+		tree.TriggerWhenExpr,
+		semaCtx,
+		volatility.Volatile,
+		false, /* allowAssignmentCast */
+	)
+	if err != nil {
+		return "", colIDs, err
+	}
+	return tree.Serialize(typedExpr), colIDs, nil
+}
+
+// TriggerWhenExprColumnIDs returns the IDs of the columns of desc that are
+// referenced by the given serialized trigger WHEN condition.
+func TriggerWhenExprColumnIDs(
+	desc catalog.TableDescriptor, whenExpr string,
+) (catalog.TableColSet, error) {
+	var colIDs catalog.TableColSet
+	if whenExpr == "" {
+		return colIDs, nil
+	}
+	expr, err := parser.ParseExpr(whenExpr)
+	if err != nil {
+		return colIDs, err
+	}
+	_, err = walkTriggerColumnRefs(expr, func(prefix, colName tree.Name) (tree.Expr, error) {
+		if col, err := catalog.MustFindColumnByTreeName(desc, colName); err == nil {
+			colIDs.Add(col.GetID())
+		}
+		return nil, nil
+	})
+	return colIDs, err
+}
+
+// walkTriggerColumnRefs calls fn for every column reference in the given
+// expression and replaces the reference with the result of fn, if it is not
+// nil. References that are not qualified by NEW or OLD result in an error.
+func walkTriggerColumnRefs(
+	rootExpr tree.Expr, fn func(prefix tree.Name, colName tree.Name) (tree.Expr, error),
+) (tree.Expr, error) {
+	return tree.SimpleVisit(rootExpr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			return true, expr, nil
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return false, nil, err
+		}
+		c, ok := v.(*tree.ColumnItem)
+		if !ok {
+			return true, expr, nil
+		}
+		var prefix tree.Name
+		if c.TableName != nil && c.TableName.NumParts == 1 {
+			prefix = tree.Name(c.TableName.Parts[0])
+		}
+		if prefix != TriggerNewTableName && prefix != TriggerOldTableName {
+			return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist, referenced in %q", tree.ErrString(c), rootExpr.String())
+		}
+		replaced, err := fn(prefix, c.ColumnName)
+		if err != nil {
+			return false, nil, err
+		}
+		if replaced == nil {
+			replaced = expr
+		}
+		return false, replaced, nil
+	})
+}
+
+// triggerDummyColumn is similar to dummyColumn, but it retains the NEW or OLD
+// qualification of the column so that the type-checked expression can be
+// serialized.
+type triggerDummyColumn struct {
+	typ    *types.T
+	prefix tree.Name
+	name   tree.Name
+}
+
+var _ tree.TypedExpr = &triggerDummyColumn{}
+
+// String implements the Stringer interface.
+func (d *triggerDummyColumn) String() string {
+	return tree.AsString(d)
+}
+
+// Format implements the NodeFormatter interface.
+func (d *triggerDummyColumn) Format(ctx *tree.FmtCtx) {
+	ctx.FormatNode(&d.prefix)
+	ctx.WriteByte('.')
+	ctx.FormatNode(&d.name)
+}
+
+// Walk implements the Expr interface.
+func (d *triggerDummyColumn) Walk(_ tree.Visitor) tree.Expr {
+	return d
+}
+
+// TypeCheck implements the Expr interface.
+func (d *triggerDummyColumn) TypeCheck(
+	_ context.Context, _ *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	return d, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (*triggerDummyColumn) Eval(ctx context.Context, v tree.ExprEvaluator) (tree.Datum, error) {
+	panic("triggerDummyColumn.Eval() is undefined")
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *triggerDummyColumn) ResolvedType() *types.T {
+	return d.typ
+}
@@ -1156,6 +1156,34 @@ CREATE TABLE system.mvcc_statistics (
 			created
 		)
 	);`
+
+	// ReplicationSlotsTableSchema stores the replication slots which are used
+	// by the Postgres logical replication protocol.
+	ReplicationSlotsTableSchema = `
+CREATE TABLE system.replication_slots (
+    slot_name           STRING NOT NULL,
+    plugin              STRING NOT NULL,
+    slot_type           STRING NOT NULL,
+    database_id         INT8 NOT NULL,
+    created             TIMESTAMPTZ NOT NULL DEFAULT now(),
+    confirmed_flush_lsn INT8 NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (slot_name),
+    FAMILY "primary" (slot_name, plugin, slot_type, database_id, created, confirmed_flush_lsn)
+)`
+
+	// NotificationsTableSchema stores the notifications sent with NOTIFY or
+	// pg_notify until they have been delivered to the listening sessions.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+    id          INT8 NOT NULL DEFAULT unique_rowid(),
+    created     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    database_id INT8 NOT NULL,
+    channel     STRING NOT NULL,
+    payload     STRING NOT NULL,
+    sender_pid  INT8 NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (id),
+    FAMILY "primary" (id, created, database_id, channel, payload, sender_pid)
+)`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -1205,7 +1233,7 @@ const SystemDatabaseName = catconstants.SystemDatabaseName
 // SystemDatabaseSchemaBootstrapVersion is the system database schema version
 // that should be used during bootstrap. It should be bumped up alongside any
 // upgrade that creates or modifies the schema of a system table.
-var SystemDatabaseSchemaBootstrapVersion = clusterversion.V24_1_SessionBasedLeasingUpgradeDescriptor.Version()
+var SystemDatabaseSchemaBootstrapVersion = clusterversion.V24_1_NotificationsTable.Version()
 
 // MakeSystemDatabaseDesc constructs a copy of the system database
 // descriptor.
@@ -1396,6 +1424,8 @@ func MakeSystemTables() []SystemTable {
 		SystemMVCCStatisticsTable,
 		StatementExecInsightsTable,
 		TransactionExecInsightsTable,
+		ReplicationSlotsTable,
+		NotificationsTable,
 	}
 }
 
@@ -4680,6 +4710,58 @@ var (
 			tbl.NextConstraintID++
 		},
 	)
+
+	ReplicationSlotsTable = makeSystemTable(
+		ReplicationSlotsTableSchema,
+		systemTable(
+			catconstants.ReplicationSlotsTableName,
+			descpb.InvalidID, // dynamically assigned table ID
+			[]descpb.ColumnDescriptor{
+				{Name: "slot_name", ID: 1, Type: types.String},
+				{Name: "plugin", ID: 2, Type: types.String},
+				{Name: "slot_type", ID: 3, Type: types.String},
+				{Name: "database_id", ID: 4, Type: types.Int},
+				{Name: "created", ID: 5, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+				{Name: "confirmed_flush_lsn", ID: 6, Type: types.Int},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:            "primary",
+					ID:              0,
+					ColumnNames:     []string{"slot_name", "plugin", "slot_type", "database_id", "created", "confirmed_flush_lsn"},
+					ColumnIDs:       []descpb.ColumnID{1, 2, 3, 4, 5, 6},
+					DefaultColumnID: 0,
+				},
+			},
+			pk("slot_name"),
+		),
+	)
+
+	NotificationsTable = makeSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			descpb.InvalidID, // dynamically assigned table ID
+			[]descpb.ColumnDescriptor{
+				{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString},
+				{Name: "created", ID: 2, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+				{Name: "database_id", ID: 3, Type: types.Int},
+				{Name: "channel", ID: 4, Type: types.String},
+				{Name: "payload", ID: 5, Type: types.String},
+				{Name: "sender_pid", ID: 6, Type: types.Int},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:            "primary",
+					ID:              0,
+					ColumnNames:     []string{"id", "created", "database_id", "channel", "payload", "sender_pid"},
+					ColumnIDs:       []descpb.ColumnID{1, 2, 3, 4, 5, 6},
+					DefaultColumnID: 0,
+				},
+			},
+			pk("id"),
+		),
+	)
 )
 
 // SpanConfigurationsTableName represents system.span_configurations.
@@ -586,6 +586,10 @@ type ForeignKeyConstraint interface {
 
 	// Match returns the type of algorithm used to match composite keys.
 	Match() semenumpb.Match
+
+	// Deferrability returns whether the checks of the foreign key can be
+	// deferred until the end of the transaction.
+	Deferrability() semenumpb.ConstraintDeferrability
 }
 
 // UniqueWithoutIndexConstraint is an interface around a unique constraint
@@ -599,6 +603,14 @@ type UniqueWithoutIndexConstraint interface {
 
 	// ParentTableID returns the ID of the table this constraint applies to.
 	ParentTableID() descpb.ID
+
+	// IsExclusion returns true iff the constraint is an exclusion constraint
+	// rather than a unique constraint.
+	IsExclusion() bool
+
+	// Deferrability returns whether the checks of the constraint can be
+	// deferred until the end of the transaction.
+	Deferrability() semenumpb.ConstraintDeferrability
 }
 
 // PrimaryKeySwap is an interface around a primary key swap mutation.
@@ -146,12 +146,24 @@ func (c uniqueWithoutIndexConstraint) ParentTableID() descpb.ID {
 	return c.desc.TableID
 }
 
+// IsExclusion implements the catalog.UniqueWithoutIndexConstraint interface.
+func (c uniqueWithoutIndexConstraint) IsExclusion() bool {
+	return c.desc.IsExclusion()
+}
+
+// Deferrability implements the catalog.UniqueWithoutIndexConstraint
+// interface.
+func (c uniqueWithoutIndexConstraint) Deferrability() semenumpb.ConstraintDeferrability {
+	return c.desc.Deferrability
+}
+
 // IsValidReferencedUniqueConstraint implements the catalog.UniqueConstraint
 // interface.
 func (c uniqueWithoutIndexConstraint) IsValidReferencedUniqueConstraint(
 	fk catalog.ForeignKeyConstraint,
 ) bool {
-	return !c.IsPartial() && descpb.ColumnIDs(c.desc.ColumnIDs).PermutationOf(fk.ForeignKeyDesc().ReferencedColumnIDs)
+	return !c.IsPartial() && !c.IsExclusion() &&
+		descpb.ColumnIDs(c.desc.ColumnIDs).PermutationOf(fk.ForeignKeyDesc().ReferencedColumnIDs)
 }
 
 // NumKeyColumns implements the catalog.UniqueConstraint interface.
@@ -292,6 +304,11 @@ func (c foreignKeyConstraint) Match() semenumpb.Match {
 	return c.desc.Match
 }
 
+// Deferrability implements the catalog.ForeignKeyConstraint interface.
+func (c foreignKeyConstraint) Deferrability() semenumpb.ConstraintDeferrability {
+	return c.desc.Deferrability
+}
+
 // GetConstraintID implements the catalog.Constraint interface.
 func (c foreignKeyConstraint) GetConstraintID() descpb.ConstraintID {
 	return c.desc.ConstraintID
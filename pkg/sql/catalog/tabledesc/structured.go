@@ -315,6 +315,15 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 		}
 	}
 
+	// Process trigger WHEN conditions.
+	for i := range desc.Triggers {
+		if desc.Triggers[i].WhenExpr != "" {
+			if err := f(&desc.Triggers[i].WhenExpr); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Process all non-index mutations.
 	for _, mut := range desc.Mutations {
 		if c := mut.GetColumn(); c != nil {
@@ -382,6 +391,9 @@ func (desc *wrapper) GetAllReferencedFunctionIDs() (catalog.DescriptorIDSet, err
 			ret.Add(id)
 		}
 	}
+	for i := range desc.Triggers {
+		ret.Add(desc.Triggers[i].FuncID)
+	}
 	// TODO(chengxiong): add logic to extract references from indexes when UDFs
 	// are allowed in them.
 	return ret.Union(catalog.MakeDescriptorIDSet(desc.DependsOnFunctions...)), nil
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/errors"
@@ -219,6 +220,9 @@ func MakeColumnDefDescs(
 	}
 
 	if d.PrimaryKey.IsPrimaryKey || (d.Unique.IsUnique && !d.Unique.WithoutIndex) {
+		if d.Unique.Deferrability.IsDeferrable() {
+			return nil, sqlerrors.NewDeferrableUniqueIndexError()
+		}
 		if !d.PrimaryKey.Sharded {
 			ret.PrimaryKeyOrUniqueIndexDescriptor = &descpb.IndexDescriptor{
 				Unique:              true,
@@ -492,6 +496,15 @@ func RenameColumnInTable(
 		}
 	}
 
+	// Rename the column in trigger WHEN conditions.
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].WhenExpr != "" {
+			if err := renameInExpr(&tableDesc.Triggers[i].WhenExpr); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Rename the column in computed columns.
 	for i := range tableDesc.Columns {
 		if otherCol := &tableDesc.Columns[i]; otherCol.IsComputed() {
@@ -613,6 +613,11 @@ func (desc *wrapper) IsRefreshViewRequired() bool {
 	return desc.IsMaterializedView && desc.RefreshViewRequired
 }
 
+// GetIncrementalRefreshJobID implements the TableDescriptor interface.
+func (desc *wrapper) GetIncrementalRefreshJobID() catpb.JobID {
+	return desc.IncrementalRefreshJobID
+}
+
 // GetObjectType implements the Object interface.
 func (desc *wrapper) GetObjectType() privilege.ObjectType {
 	if desc.IsVirtualTable() {
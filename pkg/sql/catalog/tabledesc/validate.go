@@ -199,6 +199,11 @@ func (desc *wrapper) ValidateForwardReferences(
 		}
 	}
 
+	// Check all functions referenced by triggers exist.
+	for i := range desc.Triggers {
+		vea.Report(desc.validateOutboundFuncRef(desc.Triggers[i].FuncID, vdg))
+	}
+
 	// Check enforced outbound foreign keys.
 	for _, fk := range desc.EnforcedOutboundForeignKeys() {
 		vea.Report(desc.validateOutboundFK(fk.ForeignKeyDesc(), vdg))
@@ -263,6 +268,17 @@ func (desc *wrapper) ValidateBackReferences(
 		}
 	}
 
+	// Check back-references in functions referenced by triggers.
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		fn, err := vdg.GetFunctionDescriptor(trigger.FuncID)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		vea.Report(desc.validateOutboundFuncRefBackReferenceForTrigger(fn, trigger.ID))
+	}
+
 	// For views, check dependent relations.
 	if desc.IsView() {
 		for _, id := range desc.DependsOnTypes {
@@ -376,6 +392,23 @@ func (desc *wrapper) validateOutboundFuncRefBackReferenceForColumn(
 		ref.GetName(), ref.GetID())
 }
 
+func (desc *wrapper) validateOutboundFuncRefBackReferenceForTrigger(
+	ref catalog.FunctionDescriptor, triggerID descpb.TriggerID,
+) error {
+	for _, dep := range ref.GetDependedOnBy() {
+		if dep.ID != desc.GetID() {
+			continue
+		}
+		for _, id := range dep.TriggerIDs {
+			if id == triggerID {
+				return nil
+			}
+		}
+	}
+	return errors.AssertionFailedf("depends-on function %q (%d) has no corresponding depended-on-by back reference",
+		ref.GetName(), ref.GetID())
+}
+
 func (desc *wrapper) validateInboundFunctionRef(
 	by descpb.TableDescriptor_Reference, vdg catalog.ValidationDescGetter,
 ) error {
@@ -749,6 +782,11 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		}
 	}
 
+	if desc.IncrementalRefreshJobID != catpb.InvalidJobID && !desc.MaterializedView() {
+		vea.Report(errors.AssertionFailedf(
+			"has an incremental refresh job despite not being a materialized view"))
+	}
+
 	if !desc.IsView() {
 		if len(desc.DependsOn) > 0 {
 			vea.Report(errors.AssertionFailedf(
@@ -865,6 +903,7 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	// actually a table, not if it's just a view.
 	if desc.IsPhysicalTable() {
 		desc.validateConstraintNamesAndIDs(vea)
+		desc.validateTriggers(vea, columnsByID)
 		newErrs := []error{
 			desc.validateColumnFamilies(columnsByID),
 			desc.validateCheckConstraints(columnsByID),
@@ -1101,6 +1140,48 @@ func (desc *wrapper) validateConstraintNamesAndIDs(vea catalog.ValidationErrorAc
 
 }
 
+func (desc *wrapper) validateTriggers(
+	vea catalog.ValidationErrorAccumulator, columnsByID map[descpb.ColumnID]catalog.Column,
+) {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	ids := make(map[descpb.TriggerID]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		if trigger.ID == 0 {
+			vea.Report(errors.AssertionFailedf(
+				"trigger ID was missing for trigger %q", trigger.Name))
+		} else if trigger.ID >= desc.NextTriggerID {
+			vea.Report(errors.AssertionFailedf(
+				"trigger %q has ID %d not less than NextTriggerID value %d for table",
+				trigger.Name, trigger.ID, desc.NextTriggerID))
+		}
+		if trigger.Name == "" {
+			vea.Report(pgerror.Newf(pgcode.Syntax, "empty trigger name"))
+		}
+		if _, found := names[trigger.Name]; found {
+			vea.Report(pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate trigger name: %q", trigger.Name))
+		}
+		names[trigger.Name] = struct{}{}
+		if _, found := ids[trigger.ID]; found {
+			vea.Report(pgerror.Newf(pgcode.DuplicateObject,
+				"trigger ID %d in trigger %q already in use", trigger.ID, trigger.Name))
+		}
+		ids[trigger.ID] = struct{}{}
+		if len(trigger.Events) == 0 {
+			vea.Report(errors.AssertionFailedf("trigger %q has no events", trigger.Name))
+		}
+		for _, ev := range trigger.Events {
+			for _, colID := range ev.ColumnIDs {
+				if _, ok := columnsByID[colID]; !ok {
+					vea.Report(errors.AssertionFailedf(
+						"trigger %q references unknown column ID %d", trigger.Name, colID))
+				}
+			}
+		}
+	}
+}
+
 func (desc *wrapper) validateColumns() error {
 	columnIDs := make(map[descpb.ColumnID]*descpb.ColumnDescriptor, len(desc.Columns))
 	columnNames := make(map[string]descpb.ColumnID, len(desc.Columns))
@@ -1379,6 +1460,34 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 				)
 			}
 		}
+
+		if excl := c.UniqueWithoutIndexDesc().Exclusion; excl != nil {
+			if len(excl.Elements) == 0 {
+				return errors.Newf("exclusion constraint %q has no elements", c.GetName())
+			}
+			for i := range excl.Elements {
+				e := &excl.Elements[i]
+				switch e.Operator {
+				case "=", "<>", "&&":
+				default:
+					return errors.Newf(
+						"exclusion constraint %q has invalid operator %q", c.GetName(), e.Operator,
+					)
+				}
+				if n := len(e.ColumnIDs); (e.IsRange() && n != 2) || (!e.IsRange() && n != 1) {
+					return errors.Newf(
+						"exclusion constraint %q has an element with %d columns", c.GetName(), n,
+					)
+				}
+				for _, colID := range e.ColumnIDs {
+					if !seen.Contains(int(colID)) {
+						return errors.Newf(
+							"exclusion constraint %q has an element with unknown column \"%d\"", c.GetName(), colID,
+						)
+					}
+				}
+			}
+		}
 	}
 
 	return nil
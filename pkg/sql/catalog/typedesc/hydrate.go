@@ -136,6 +136,20 @@ func ensureTypeMetadataIsHydrated(
 		tm.ImplicitRecordType = true
 		return
 	}
+	if d := maybeDesc.AsDomainTypeDescriptor(); d != nil {
+		n := d.NumDomainConstraints()
+		tm.DomainData = &types.DomainMetadata{
+			NotNull:    d.IsNotNull(),
+			CheckNames: make([]string, n),
+			CheckExprs: make([]string, n),
+		}
+		for i := 0; i < n; i++ {
+			c := d.GetDomainConstraint(i)
+			tm.DomainData.CheckNames[i] = c.Name
+			tm.DomainData.CheckExprs[i] = c.Expr
+		}
+		return
+	}
 	if e := maybeDesc.AsEnumTypeDescriptor(); e != nil {
 		if imm, ok := e.(*immutable); ok {
 			// Fast-path for immutable enum descriptors. We can use a pointer into the
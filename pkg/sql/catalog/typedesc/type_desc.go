@@ -43,6 +43,7 @@ var _ catalog.EnumTypeDescriptor = (*immutable)(nil)
 var _ catalog.RegionEnumTypeDescriptor = (*immutable)(nil)
 var _ catalog.AliasTypeDescriptor = (*immutable)(nil)
 var _ catalog.CompositeTypeDescriptor = (*immutable)(nil)
+var _ catalog.DomainTypeDescriptor = (*immutable)(nil)
 var _ catalog.TypeDescriptor = (*Mutable)(nil)
 var _ catalog.MutableDescriptor = (*Mutable)(nil)
 
@@ -508,6 +509,20 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		if desc.Composite == nil {
 			vea.Report(errors.AssertionFailedf("COMPOSITE type desc has nil composite type"))
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		if desc.Domain == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil domain type"))
+		} else if desc.Domain.BaseType == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+		} else {
+			names := make(map[string]struct{}, len(desc.Domain.Constraints))
+			for _, c := range desc.Domain.Constraints {
+				if _, ok := names[c.Name]; ok {
+					vea.Report(errors.AssertionFailedf("duplicate domain constraint %q", c.Name))
+				}
+				names[c.Name] = struct{}{}
+			}
+		}
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		vea.Report(errors.AssertionFailedf("invalid type descriptor: kind %s should never be serialized or validated", desc.Kind.String()))
 	default:
@@ -612,6 +627,13 @@ func (desc *immutable) ValidateForwardReferences(
 		}
 	}
 
+	if d := desc.AsDomainTypeDescriptor(); d != nil && d.BaseType().UserDefined() {
+		// Domains over user-defined types are currently not supported.
+		vea.Report(errors.AssertionFailedf("invalid reference to user-defined type %q from domain %q",
+			d.BaseType().String(), desc.GetName(),
+		))
+	}
+
 	if c := desc.AsCompositeTypeDescriptor(); c != nil {
 		for i := 0; i < c.NumElements(); i++ {
 			t := c.GetElementType(i)
@@ -773,6 +795,12 @@ func (desc *immutable) AsTypesT() *types.T {
 			contents,
 			labels,
 		)
+	case descpb.TypeDescriptor_DOMAIN:
+		return types.MakeDomain(
+			catid.TypeIDToOID(desc.GetID()),
+			catid.TypeIDToOID(desc.ArrayTypeID),
+			desc.Domain.BaseType,
+		)
 	}
 	panic(errors.AssertionFailedf("unsupported descriptor kind %s", desc.Kind.String()))
 }
@@ -855,6 +883,15 @@ func (desc *immutable) HasPendingSchemaChanges() bool {
 			}
 		}
 		return false
+	case descpb.TypeDescriptor_DOMAIN:
+		// If there are any constraints being validated, then a type schema change
+		// is needed to validate them.
+		for i := range desc.Domain.Constraints {
+			if desc.Domain.Constraints[i].Validity != descpb.ConstraintValidity_Validated {
+				return true
+			}
+		}
+		return false
 	default:
 		return false
 	}
@@ -1011,6 +1048,14 @@ func (desc *immutable) AsCompositeTypeDescriptor() catalog.CompositeTypeDescript
 	return nil
 }
 
+// AsDomainTypeDescriptor implements the catalog.TypeDescriptor interface.
+func (desc *immutable) AsDomainTypeDescriptor() catalog.DomainTypeDescriptor {
+	if desc.Kind == descpb.TypeDescriptor_DOMAIN {
+		return desc
+	}
+	return nil
+}
+
 // AsTableImplicitRecordTypeDescriptor implements the catalog.TypeDescriptor
 // interface.
 func (desc *immutable) AsTableImplicitRecordTypeDescriptor() catalog.TableImplicitRecordTypeDescriptor {
@@ -1037,6 +1082,28 @@ func (desc *immutable) GetElementType(ordinal int) *types.T {
 	return desc.Composite.Elements[ordinal].ElementType
 }
 
+// BaseType implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) BaseType() *types.T {
+	return desc.Domain.BaseType
+}
+
+// IsNotNull implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) IsNotNull() bool {
+	return desc.Domain.NotNull
+}
+
+// NumDomainConstraints implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) NumDomainConstraints() int {
+	return len(desc.Domain.Constraints)
+}
+
+// GetDomainConstraint implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) GetDomainConstraint(
+	ordinal int,
+) *descpb.TypeDescriptor_Domain_Constraint {
+	return &desc.Domain.Constraints[ordinal]
+}
+
 // ForEachRegionInSuperRegion implements the catalog.RegionEnumTypeDescriptor
 // interface.
 func (desc *immutable) ForEachRegionInSuperRegion(
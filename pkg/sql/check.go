@@ -503,6 +503,12 @@ func (p *planner) RevalidateUniqueConstraint(
 	// Check UNIQUE WITHOUT INDEX constraints.
 	for _, uc := range tableDesc.EnforcedUniqueConstraintsWithoutIndex() {
 		if uc.GetName() == constraintName {
+			if uc.IsExclusion() {
+				return validateExclusionConstraint(
+					ctx, tableDesc, uc.UniqueWithoutIndexDesc(), 0, /* indexIDForValidation */
+					p.InternalSQLTxn(), p.User(), true, /* preExisting */
+				)
+			}
 			return validateUniqueConstraint(
 				ctx,
 				tableDesc,
@@ -583,17 +589,26 @@ func RevalidateUniqueConstraintsInTable(
 	// Check UNIQUE WITHOUT INDEX constraints.
 	for _, uc := range tableDesc.EnforcedUniqueConstraintsWithoutIndex() {
 		if uc.IsConstraintValidated() {
-			if err := validateUniqueConstraint(
-				ctx,
-				tableDesc,
-				uc.GetName(),
-				uc.CollectKeyColumnIDs().Ordered(),
-				uc.GetPredicate(),
-				0, /* indexIDForValidation */
-				txn,
-				user,
-				true, /* preExisting */
-			); err != nil {
+			var err error
+			if uc.IsExclusion() {
+				err = validateExclusionConstraint(
+					ctx, tableDesc, uc.UniqueWithoutIndexDesc(), 0, /* indexIDForValidation */
+					txn, user, true, /* preExisting */
+				)
+			} else {
+				err = validateUniqueConstraint(
+					ctx,
+					tableDesc,
+					uc.GetName(),
+					uc.CollectKeyColumnIDs().Ordered(),
+					uc.GetPredicate(),
+					0, /* indexIDForValidation */
+					txn,
+					user,
+					true, /* preExisting */
+				)
+			}
+			if err != nil {
 				log.Errorf(ctx, "validation of unique constraints failed for table %s: %s", tableDesc.GetName(), err)
 				return errors.Wrapf(err, "for table %s", tableDesc.GetName())
 			}
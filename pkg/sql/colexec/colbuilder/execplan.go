@@ -857,6 +857,12 @@ func NewColOperator(
 						core.TableReader.LockingWaitPolicy == descpb.ScanLockingWaitPolicy_SKIP_LOCKED {
 						return false
 					}
+					// Rows are sampled for TABLESAMPLE BERNOULLI by the
+					// cFetcher on the client side, which the direct scans
+					// bypass.
+					if core.TableReader.Sample != nil {
+						return false
+					}
 					// At the moment, the ColBatchDirectScan cannot handle Gets
 					// (it's not clear whether it is worth to handle them via
 					// the same path as for Scans and ReverseScans (which could
@@ -2243,6 +2249,11 @@ func planCastOperator(
 	factory coldata.ColumnFactory,
 	evalCtx *eval.Context,
 ) (op colexecop.Operator, resultIdx int, typs []*types.T, err error) {
+	if toType.IsDomain() {
+		// Casts to domain types must check the constraints of the domain, which
+		// is only done by the row-by-row engine.
+		return nil, resultIdx, nil, errors.Errorf("unhandled cast to domain type %s", toType.SQLStringForError())
+	}
 	outputIdx := len(columnTypes)
 	op, err = colexecbase.GetCastOperator(colmem.NewAllocator(ctx, acc, factory), input, inputIdx, outputIdx, fromType, toType, evalCtx)
 	typs = append(columnTypes, toType)
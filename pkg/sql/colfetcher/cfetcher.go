@@ -191,6 +191,10 @@ type cFetcherArgs struct {
 	// the last one returned on the NextBatch calls if the caller wishes to keep
 	// multiple batches at the same time.
 	alwaysReallocate bool
+	// sampleRow, if set, is called with the prefix of each row's key, and only
+	// the rows for which it returns true are output. It is used to implement
+	// TABLESAMPLE BERNOULLI.
+	sampleRow func(rowPrefix roachpb.Key) bool
 }
 
 // noOutputColumn is a sentinel value to denote that a system column is not
@@ -281,6 +285,8 @@ type cFetcher struct {
 		lastRowPrefix roachpb.Key
 		// firstKeyOfRow, if set, is the first key in the current row.
 		firstKeyOfRow roachpb.Key
+		// rowSampled is false if the current row is skipped by sampleRow.
+		rowSampled bool
 		// prettyValueBuf is a temp buffer used to create strings for tracing.
 		prettyValueBuf *bytes.Buffer
 
@@ -816,6 +822,7 @@ func (cf *cFetcher) NextBatch(ctx context.Context) (coldata.Batch, error) {
 				}
 				cf.machine.lastRowPrefix = cf.machine.nextKV.Key[:prefixLen+(origRemainingBytesLen-len(remainingBytes))]
 			}
+			cf.machine.rowSampled = cf.sampleRow == nil || cf.sampleRow(cf.machine.lastRowPrefix)
 
 			familyID, err := cf.getCurrentColumnFamilyID()
 			if err != nil {
@@ -908,6 +915,16 @@ func (cf *cFetcher) NextBatch(ctx context.Context) (coldata.Batch, error) {
 			}
 
 		case stateFinalizeRow:
+			if !cf.machine.rowSampled {
+				// The row is not part of the sample. Clear the nulls that might
+				// have been set while decoding it and reuse its position in the
+				// batch for the next row.
+				for i := range cf.machine.colvecs.Nulls {
+					cf.machine.colvecs.Nulls[i].UnsetNull(cf.machine.rowIdx)
+				}
+				cf.shiftState()
+				continue
+			}
 			// Populate the timestamp system column if needed. We have to do it
 			// on a per row basis since each row can be modified at a different
 			// time.
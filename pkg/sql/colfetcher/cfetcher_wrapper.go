@@ -243,6 +243,7 @@ func newCFetcherWrapper(
 		true,  /* singleUse */
 		collectStats,
 		alwaysReallocate,
+		nil, /* sampleRow */
 	}
 
 	// This memory monitor is not connected to the memory accounting system
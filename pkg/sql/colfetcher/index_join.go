@@ -590,6 +590,7 @@ func NewColIndexJoin(
 		false, /* singleUse */
 		execstats.ShouldCollectStats(ctx, flowCtx.CollectStats),
 		false, /* alwaysReallocate */
+		nil,   /* sampleRow */
 	}
 	if err = fetcher.Init(
 		fetcherAllocator, kvFetcher, tableArgs,
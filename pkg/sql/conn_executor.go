@@ -55,6 +55,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
@@ -1097,6 +1098,10 @@ func (s *Server) newConnExecutor(
 		totalActiveTimeStopWatch:  timeutil.NewStopWatch(),
 		txnFingerprintIDCache:     NewTxnFingerprintIDCache(ctx, s.cfg.Settings, &txnFingerprintIDCacheAcc),
 		txnFingerprintIDAcc:       &txnFingerprintIDCacheAcc,
+		notifications: notificationState{
+			registry: s.cfg.NotificationRegistry,
+			stmtBuf:  stmtBuf,
+		},
 	}
 
 	ex.state.txnAbortCount = ex.metrics.EngineMetrics.TxnAbortCount
@@ -1170,6 +1175,7 @@ func (s *Server) newConnExecutor(
 		mode:   ex.sessionData().NewSchemaChangerMode,
 		memAcc: ex.sessionMon.MakeBoundAccount(),
 	}
+	ex.extraTxnState.deferredConstraints = &deferredConstraints{}
 	ex.queryCancelKey = pgwirecancel.MakeBackendKeyData(ex.rng, ex.server.cfg.NodeInfo.NodeID.SQLInstanceID())
 	ex.mu.ActiveQueries = make(map[clusterunique.ID]*queryMeta)
 	ex.machine = fsm.MakeMachine(TxnStateTransitions, stateNoTxn{}, &ex.state)
@@ -1253,6 +1259,10 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	if err := ex.extraTxnState.sqlCursors.closeAll(cursorCloseForExplicitClose); err != nil {
 		log.Warningf(ctx, "error closing cursors: %v", err)
 	}
+	if err := ex.advisoryLocks.releaseAll(ctx); err != nil {
+		log.Warningf(ctx, "error releasing advisory locks: %v", err)
+	}
+	ex.notifications.close()
 
 	var payloadErr error
 	if closeType == normalClose {
@@ -1450,6 +1460,10 @@ type connExecutor struct {
 		// validateDbZoneConfig should the DB zone config on commit.
 		validateDbZoneConfig bool
 
+		// deferredConstraints tracks the modes of deferrable constraints and the
+		// violations of deferred constraints, which are checked on commit.
+		deferredConstraints *deferredConstraints
+
 		// txnCounter keeps track of how many SQL txns have been open since
 		// the start of the session. This is used for logging, to
 		// distinguish statements that belong to separate SQL transactions.
@@ -1723,6 +1737,14 @@ type connExecutor struct {
 	// temporary schema, which requires special cleanup on close.
 	hasCreatedTemporarySchema bool
 
+	// advisoryLocks tracks the advisory locks held by the session.
+	// Session-scoped advisory locks are released on close.
+	advisoryLocks advisoryLockState
+
+	// notifications tracks the channels the session listens on and the
+	// notifications queued for it.
+	notifications notificationState
+
 	// stmtDiagnosticsRecorder is used to track which queries need to have
 	// information collected.
 	stmtDiagnosticsRecorder *stmtdiagnostics.Registry
@@ -2044,6 +2066,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 		ex.extraTxnState.descCollection.ReleaseAll(ctx)
 		ex.extraTxnState.jobs.reset()
 		ex.extraTxnState.validateDbZoneConfig = false
+		ex.extraTxnState.deferredConstraints.reset()
 		ex.extraTxnState.schemaChangerState.memAcc.Clear(ctx)
 		ex.extraTxnState.schemaChangerState = &SchemaChangerState{
 			mode:   ex.sessionData().NewSchemaChangerMode,
@@ -2071,6 +2094,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 			ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 		)
 		ex.extraTxnState.savepoints.clear()
+		ex.advisoryLocks.txnFinished(ctx, ex.server.cfg.DB)
 		ex.onTxnFinish(ctx, ev, payloadErr)
 	case txnRestart:
 		ex.onTxnRestart(ctx)
@@ -2256,7 +2280,7 @@ func (ex *connExecutor) execCmd() (retErr error) {
 				tcmd.AST,
 				NeedRowDesc,
 				pos,
-				nil, /* formatCodes */
+				ex.fetchFormatCodes(tcmd.AST),
 				ex.sessionData().DataConversionConfig,
 				ex.sessionData().GetLocation(),
 				0,  /* limit */
@@ -2429,6 +2453,10 @@ func (ex *connExecutor) execCmd() (retErr error) {
 		// inside a BEGIN/COMMIT transaction block (“close” meaning to commit if no
 		// error, or roll back if error)."
 		// In other words, Sync is treated as commit for implicit transactions.
+		//
+		// The notifications queued for the session are delivered before the
+		// ReadyForQuery message if the session is not in a transaction block.
+		deliverNotifications := ex.implicitTxn() || ex.idleConn()
 		if ex.implicitTxn() {
 			// Note that the handling of ev in the case of Sync is massaged a bit
 			// later - Sync is special in that, if it encounters an error, that does
@@ -2436,7 +2464,11 @@ func (ex *connExecutor) execCmd() (retErr error) {
 			ev, payload = ex.handleAutoCommit(ctx, &tree.CommitTransaction{})
 		}
 		// Note that the Sync result will flush results to the network connection.
-		res = ex.clientComm.CreateSyncResult(pos)
+		syncRes := ex.clientComm.CreateSyncResult(pos)
+		if deliverNotifications {
+			ex.notifications.deliver(syncRes)
+		}
+		res = syncRes
 		if ex.draining {
 			// If we're draining, then after handing the Sync connExecutor state
 			// transition, check whether this is a good time to finish the
@@ -2482,6 +2514,14 @@ func (ex *connExecutor) execCmd() (retErr error) {
 		//   was created when the statement started executing (via the
 		//   reset() method).
 		ex.statsCollector.PhaseTimes().SetSessionPhaseTime(sessionphase.SessionQueryServiced, timeutil.Now())
+	case StartReplication:
+		ex.phaseTimes.SetSessionPhaseTime(sessionphase.SessionQueryReceived, tcmd.TimeReceived)
+		ex.phaseTimes.SetSessionPhaseTime(sessionphase.SessionStartParse, tcmd.ParseStart)
+		ex.phaseTimes.SetSessionPhaseTime(sessionphase.SessionEndParse, tcmd.ParseEnd)
+		replRes := ex.clientComm.CreateStartReplicationResult(tcmd, pos)
+		res = replRes
+		stmtCtx := withStatement(ctx, tcmd.Stmt)
+		ev, payload = ex.execStartReplication(stmtCtx, tcmd, replRes)
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -2495,6 +2535,16 @@ func (ex *connExecutor) execCmd() (retErr error) {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		// Notifications are only delivered while the session is not in a
+		// transaction. Otherwise, they are delivered by the Sync which ends it.
+		if ex.idleConn() {
+			flushRes := ex.clientComm.CreateFlushResult(pos)
+			ex.notifications.deliver(flushRes)
+			res = flushRes
+		} else {
+			res = ex.clientComm.CreateDrainResult(pos)
+		}
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -2759,10 +2809,14 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				// Can't advance.
 			case CopyOut:
 				// Can't advance.
+			case StartReplication:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -3684,6 +3738,8 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 			JobExecContext:                 p,
 			ClientNoticeSender:             p,
 			Sequence:                       p,
+			AdvisoryLocker:                 p,
+			Notifier:                       p,
 			Tenant:                         p,
 			Regions:                        p,
 			Gossip:                         p,
@@ -3710,6 +3766,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		TxnModesSetter:       ex,
 		jobs:                 ex.extraTxnState.jobs,
 		validateDbZoneConfig: &ex.extraTxnState.validateDbZoneConfig,
+		deferredConstraints:  ex.extraTxnState.deferredConstraints,
 		statsProvider:        ex.server.sqlStats,
 		indexUsageStats:      ex.indexUsageStats,
 		statementPreparer:    ex,
@@ -3777,6 +3834,20 @@ func (ex *connExecutor) getTransactionState() string {
 	return state.(fmt.Stringer).String()
 }
 
+// fetchFormatCodes returns the format codes to use for the result of the given
+// statement of the simple query protocol. Rows fetched from a BINARY cursor are
+// returned in the binary format; all other results use the text format.
+func (ex *connExecutor) fetchFormatCodes(stmt tree.Statement) []pgwirebase.FormatCode {
+	fetch, ok := stmt.(*tree.FetchCursor)
+	if !ok {
+		return nil
+	}
+	if cursor := ex.extraTxnState.sqlCursors.getCursor(fetch.Name); cursor != nil {
+		return cursor.formatCodes
+	}
+	return nil
+}
+
 func (ex *connExecutor) implicitTxn() bool {
 	state := ex.machine.CurState()
 	os, ok := state.(stateOpen)
@@ -3796,6 +3867,8 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sqlCursors = ex.getCursorAccessor()
 	p.storedProcTxnState = ex.getStoredProcTxnStateAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
+	p.advisoryLocks = &ex.advisoryLocks
+	p.notifications = &ex.notifications
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
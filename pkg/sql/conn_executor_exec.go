@@ -1548,6 +1548,12 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) (retEr
 		ex.state.mu.txn.ConfigureStepping(ctx, prevSteppingMode)
 	}
 
+	if dc := ex.extraTxnState.deferredConstraints; dc.hasPending() {
+		if err := dc.check(ctx, ex.planner.InternalSQLTxn(), nil /* include */); err != nil {
+			return err
+		}
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -429,6 +430,48 @@ func (c CopyOut) String() string {
 
 var _ Command = CopyOut{}
 
+// StartReplication is the command for execution of the START_REPLICATION
+// replication protocol command, which streams changes to the client using the
+// Copy-both pgwire subprotocol.
+type StartReplication struct {
+	ParsedStmt statements.Statement[tree.Statement]
+	Stmt       *pgrepltree.StartReplication
+	// Conn is the network connection. Execution of the START_REPLICATION
+	// command takes control of the connection.
+	Conn pgwirebase.Conn
+	// ReplicationDone is used to signal that control of the connection is being
+	// handed back to the network routine.
+	ReplicationDone struct {
+		// WaitGroup is decremented once execution finishes.
+		*sync.WaitGroup
+		// Once is used to decrement the WaitGroup exactly once.
+		*sync.Once
+	}
+	// TimeReceived is the time at which the message was received
+	// from the client. Used to compute the service latency.
+	TimeReceived time.Time
+	// ParseStart/ParseEnd are the timing info for parsing of the query. Used for
+	// stats reporting.
+	ParseStart time.Time
+	ParseEnd   time.Time
+}
+
+// command implements the Command interface.
+func (StartReplication) command() string { return "start replication" }
+
+// isExtendedProtocolCmd implements the Command interface.
+func (StartReplication) isExtendedProtocolCmd() bool { return false }
+
+func (c StartReplication) String() string {
+	s := "(empty)"
+	if c.Stmt != nil {
+		s = c.Stmt.String()
+	}
+	return fmt.Sprintf("StartReplication: %s", s)
+}
+
+var _ Command = StartReplication{}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -447,6 +490,24 @@ func (DrainRequest) String() string {
 
 var _ Command = DrainRequest{}
 
+// DeliverNotifications is a Command asking for the notifications queued for
+// the session by LISTEN to be delivered to the client. It is pushed by the
+// NotificationRegistry rather than generated by the client, and has no effect
+// if the session is in a transaction.
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+// isExtendedProtocolCmd implements the Command interface.
+func (DeliverNotifications) isExtendedProtocolCmd() bool { return false }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // SendError is a command that, upon execution, send a specific error to the
 // client. This is used by pgwire to schedule errors to be sent at an
 // appropriate time.
@@ -741,6 +802,9 @@ type ClientComm interface {
 	CreateCopyInResult(cmd CopyIn, pos CmdPos) CopyInResult
 	// CreateCopyOutResult creates a result for a Copy-out command.
 	CreateCopyOutResult(cmd CopyOut, pos CmdPos) CopyOutResult
+	// CreateStartReplicationResult creates a result for a StartReplication
+	// command.
+	CreateStartReplicationResult(cmd StartReplication, pos CmdPos) StartReplicationResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
 
@@ -939,12 +1003,20 @@ type DeleteResult interface {
 // flushed.
 type SyncResult interface {
 	ResultBase
+
+	// BufferNotification buffers a notification which is sent to the client
+	// before the readyForQuery message.
+	BufferNotification(Notification)
 }
 
 // FlushResult represents the result of a Flush command. When this result is
 // closed, all previously accumulated results are flushed to the client.
 type FlushResult interface {
 	ResultBase
+
+	// BufferNotification buffers a notification which is sent to the client
+	// when the result is closed.
+	BufferNotification(Notification)
 }
 
 // DrainResult represents the result of a Drain command. Closing this result
@@ -968,6 +1040,12 @@ type CopyInResult interface {
 	SetRowsAffected(ctx context.Context, n int)
 }
 
+// StartReplicationResult represents the result of a StartReplication command.
+// Closing this result sends a CommandComplete message to the client.
+type StartReplicationResult interface {
+	ResultBase
+}
+
 // CopyOutResult represents the result of a CopyOut command. Closing this result
 // sends a CommandComplete message to the client.
 type CopyOutResult interface {
@@ -1098,6 +1176,11 @@ func (r *streamingCommandResult) BufferNotice(notice pgnotice.Notice) {
 	// Unimplemented: the internal executor does not support notices.
 }
 
+// BufferNotification is part of the SyncResult interface.
+func (r *streamingCommandResult) BufferNotification(Notification) {
+	// Unimplemented: the internal executor does not support notifications.
+}
+
 // SendNotice is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) SendNotice(ctx context.Context, notice pgnotice.Notice) error {
 	// Unimplemented: the internal executor does not support notices.
@@ -3521,6 +3521,42 @@ func writeCreateTypeDescRow(
 			tree.DNull,                           // enum_members
 		)
 	}
+	if d := typeDesc.AsDomainTypeDescriptor(); d != nil {
+		name, err := tree.NewUnresolvedObjectName(2, [3]string{d.GetName(), sc.GetName()}, 0)
+		if err != nil {
+			return false, err
+		}
+		var constraints []tree.DomainConstraint
+		if d.IsNotNull() {
+			constraints = append(constraints, tree.DomainConstraint{})
+		}
+		for i := 0; i < d.NumDomainConstraints(); i++ {
+			c := d.GetDomainConstraint(i)
+			expr, err := parser.ParseExpr(c.Expr)
+			if err != nil {
+				return false, err
+			}
+			constraints = append(constraints, tree.DomainConstraint{
+				Name:  tree.Name(c.Name),
+				Check: expr,
+			})
+		}
+		node := &tree.CreateType{
+			Variety:           tree.Domain,
+			TypeName:          name,
+			DomainBaseType:    d.BaseType(),
+			DomainConstraints: constraints,
+		}
+		return true, addRow(
+			tree.NewDInt(tree.DInt(db.GetID())),  // database_id
+			tree.NewDString(db.GetName()),        // database_name
+			tree.NewDString(sc.GetName()),        // schema_name
+			tree.NewDInt(tree.DInt(d.GetID())),   // descriptor_id
+			tree.NewDString(d.GetName()),         // descriptor_name
+			tree.NewDString(tree.AsString(node)), // create_statement
+			tree.DNull,                           // enum_members
+		)
+	}
 	return false, errors.AssertionFailedf("unknown type descriptor kind %s", typeDesc.GetKind())
 }
 
@@ -7926,6 +7962,19 @@ func genClusterLocksGenerator(
 					continue
 				}
 				spansToQuery = append(spansToQuery, desc.TableSpan(p.execCfg.Codec))
+			case catalog.DatabaseDescriptor:
+				// Advisory locks are held on keys under the ID of the database that
+				// scopes them.
+				if filters.tableName != nil {
+					continue
+				}
+				if filters.tableID != nil && descpb.ID(*filters.tableID) != desc.GetID() {
+					continue
+				}
+				if filters.databaseName != nil && *filters.databaseName != desc.GetName() {
+					continue
+				}
+				spansToQuery = append(spansToQuery, advisoryLockSpan(p.execCfg.Codec, desc.GetID()))
 			}
 		}
 
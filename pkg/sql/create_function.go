@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -24,10 +25,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
@@ -169,6 +172,7 @@ func (n *createFunctionNode) createNewFunction(
 			ReturnType:  returnType,
 			ReturnSet:   udfDesc.ReturnType.ReturnSet,
 			IsProcedure: udfDesc.IsProcedure(),
+			IsVariadic:  udfDesc.IsVariadic(),
 		},
 	)
 	if err := params.p.writeSchemaDescChange(params.ctx, scDesc, "Create Function"); err != nil {
@@ -492,6 +496,22 @@ func setFuncOptions(
 			// Handle the body after the loop, since we don't yet know what language
 			// it is.
 			body = string(t)
+		case tree.RoutineSecurity:
+			v, err := funcinfo.SecurityToProto(t)
+			if err != nil {
+				return err
+			}
+			udfDesc.SetSecurity(v)
+		case tree.RoutineSetConfig:
+			if err := setFuncConfig(params, udfDesc, t); err != nil {
+				return err
+			}
+		case tree.RoutineResetConfig:
+			if t.All {
+				udfDesc.ResetAllConfig()
+			} else {
+				udfDesc.ResetConfig(strings.ToLower(t.Name))
+			}
 		default:
 			return pgerror.Newf(pgcode.InvalidParameterValue, "Unknown function option %q", t)
 		}
@@ -513,11 +533,73 @@ func setFuncOptions(
 	return nil
 }
 
+// setFuncConfig evaluates the values of the given SET clause and stores the
+// resulting setting in the function descriptor. SET ... TO DEFAULT removes the
+// setting from the function.
+func setFuncConfig(
+	params runParams, udfDesc *funcdesc.Mutable, setConfig tree.RoutineSetConfig,
+) error {
+	name := strings.ToLower(setConfig.Name)
+	_, v, err := getSessionVar(name, false /* missingOk */)
+	if err != nil {
+		return err
+	}
+	if v.Set == nil {
+		// The setting is applied while the function executes, so only variables
+		// that can be set without a planner are allowed.
+		return newCannotChangeParameterError(name)
+	}
+	if len(setConfig.Values) == 1 {
+		if _, ok := setConfig.Values[0].(tree.DefaultVal); ok {
+			udfDesc.ResetConfig(name)
+			return nil
+		}
+	}
+
+	values := make([]tree.TypedExpr, len(setConfig.Values))
+	for i, expr := range setConfig.Values {
+		expr = paramparse.UnresolvedNameToStrVal(expr)
+		var dummyHelper tree.IndexedVarHelper
+		typedValue, err := params.p.analyzeExpr(
+			params.ctx, expr, dummyHelper, types.String, false, "SET "+name)
+		if err != nil {
+			return wrapSetVarError(err, name, expr.String())
+		}
+		values[i], err = eval.Expr(params.ctx, params.EvalContext(), typedValue)
+		if err != nil {
+			return err
+		}
+	}
+	var value string
+	if v.GetStringVal != nil {
+		value, err = v.GetStringVal(params.ctx, params.extendedEvalCtx, values, params.p.Txn())
+	} else {
+		value, err = getStringVal(params.ctx, params.EvalContext(), name, values)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Validate the value by applying it to a copy of the session data, so that
+	// invalid settings are rejected when the function is defined rather than
+	// when it is executed.
+	m := params.p.sessionDataMutatorIterator.mutator(
+		false /* applyCallbacks */, params.p.SessionData().Clone(),
+	)
+	if err := v.Set(params.ctx, m, value); err != nil {
+		return err
+	}
+	udfDesc.SetConfig(name, value)
+	return nil
+}
+
 // resetFuncOption sets all function options to default values.
 func resetFuncOption(udfDesc *funcdesc.Mutable) {
 	udfDesc.SetVolatility(catpb.Function_VOLATILE)
 	udfDesc.SetNullInputBehavior(catpb.Function_CALLED_ON_NULL_INPUT)
 	udfDesc.SetLeakProof(false)
+	udfDesc.SetSecurity(catpb.Function_INVOKER)
+	udfDesc.ResetAllConfig()
 }
 
 func makeFunctionParam(
@@ -0,0 +1,162 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type createPublicationNode struct {
+	n      *tree.CreatePublication
+	dbDesc *dbdesc.Mutable
+	pub    descpb.DatabaseDescriptor_Publication
+}
+
+// CreatePublication creates a publication in the current database.
+// Privileges: CREATE on the database, and ownership of the published tables.
+// Publications for all tables require the admin role.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_Publications) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"publications are not supported until the cluster version is upgraded")
+	}
+	if p.CurrentDatabase() == "" {
+		return nil, sqlerrors.ErrNoDatabase
+	}
+	dbDesc, err := p.Descriptors().MutableByName(p.txn).Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	if dbDesc.GetID() == keys.SystemDatabaseID {
+		return nil, pgerror.New(pgcode.InvalidObjectDefinition,
+			"cannot create publications in the system database")
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if dbDesc.GetPublication(string(n.Name)) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", n.Name)
+	}
+
+	pub := descpb.DatabaseDescriptor_Publication{
+		Name:       string(n.Name),
+		AllTables:  n.AllTables,
+		OwnerProto: p.User().EncodeProto(),
+	}
+	if n.AllTables {
+		hasAdmin, err := p.HasAdminRole(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !hasAdmin {
+			return nil, pgerror.New(pgcode.InsufficientPrivilege,
+				"must be admin to create FOR ALL TABLES publication")
+		}
+	}
+	lookupFlags := tree.ObjectLookupFlags{
+		Required:             true,
+		DesiredObjectKind:    tree.TableObject,
+		DesiredTableDescKind: tree.ResolveRequireTableDesc,
+	}
+	for i := range n.Tables {
+		tn := &n.Tables[i]
+		_, tableDesc, err := resolver.ResolveExistingTableObject(ctx, p, tn, lookupFlags)
+		if err != nil {
+			return nil, err
+		}
+		if tableDesc.GetParentID() != dbDesc.GetID() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot add relation %q of database %q to a publication of database %q",
+				tableDesc.GetName(), tn.Catalog(), dbDesc.GetName())
+		}
+		if tableDesc.IsTemporary() {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot add relation %q to publication", tableDesc.GetName())
+		}
+		hasOwnership, err := p.HasOwnership(ctx, tableDesc)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOwnership {
+			return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of table %s", tree.Name(tableDesc.GetName()))
+		}
+		duplicate := false
+		for _, id := range pub.TableIDs {
+			duplicate = duplicate || id == tableDesc.GetID()
+		}
+		if !duplicate {
+			pub.TableIDs = append(pub.TableIDs, tableDesc.GetID())
+		}
+	}
+	return &createPublicationNode{n: n, dbDesc: dbDesc, pub: pub}, nil
+}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("publication"))
+
+	if err := n.dbDesc.AddPublication(n.pub); err != nil {
+		return err
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// publicationIncludesTable returns whether the changes of the table are
+// published by the publication. FOR ALL TABLES publications include all the
+// tables of the database except temporary tables.
+func publicationIncludesTable(
+	pub *descpb.DatabaseDescriptor_Publication, table catalog.TableDescriptor,
+) bool {
+	if !table.IsTable() || table.IsTemporary() {
+		return false
+	}
+	if pub.AllTables {
+		return true
+	}
+	for _, id := range pub.TableIDs {
+		if id == table.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+func (*createPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*createPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createPublicationNode) Close(context.Context)        {}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+func (*createPublicationNode) ReadingOwnWrites() {}
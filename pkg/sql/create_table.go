@@ -668,10 +668,12 @@ func addUniqueWithoutIndexColumnTableDef(
 	// Add a unique constraint.
 	if err := ResolveUniqueWithoutIndexConstraint(
 		ctx,
+		evalCtx,
 		desc,
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		d.Unique.Deferrability,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -735,7 +737,7 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, evalCtx, desc, string(d.Name), colNames, predicate, d.Deferrability, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -751,13 +753,18 @@ func addUniqueWithoutIndexTableDef(
 // added. This only applies for existing tables, not new tables.
 func ResolveUniqueWithoutIndexConstraint(
 	ctx context.Context,
+	evalCtx *eval.Context,
 	tbl *tabledesc.Mutable,
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
+	if err := checkDeferrableConstraintsSupported(ctx, evalCtx, deferrability); err != nil {
+		return err
+	}
 	var colSet catalog.TableColSet
 	cols := make([]catalog.Column, len(colNames))
 	for i, name := range colNames {
@@ -803,12 +810,13 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:         constraintName,
-		TableID:      tbl.ID,
-		ColumnIDs:    columnIDs,
-		Predicate:    predicate,
-		Validity:     validity,
-		ConstraintID: tbl.NextConstraintID,
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		ConstraintID:  tbl.NextConstraintID,
+		Deferrability: tree.ConstraintDeferrabilityValue[deferrability],
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -820,6 +828,19 @@ func ResolveUniqueWithoutIndexConstraint(
 	return nil
 }
 
+// checkDeferrableConstraintsSupported returns an error if the constraint is
+// deferrable and not all nodes know to defer its checks.
+func checkDeferrableConstraintsSupported(
+	ctx context.Context, evalCtx *eval.Context, deferrability tree.ConstraintDeferrability,
+) error {
+	if deferrability.IsDeferrable() &&
+		!evalCtx.Settings.Version.IsActive(ctx, clusterversion.V24_1_DeferrableConstraints) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"deferrable constraints are not supported until the upgrade to version 24.1 is finalized")
+	}
+	return nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -858,6 +879,9 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *eval.Context,
 ) error {
+	if err := checkDeferrableConstraintsSupported(ctx, evalCtx, d.Deferrability); err != nil {
+		return err
+	}
 	var originColSet catalog.TableColSet
 	originCols := make([]catalog.Column, len(d.FromCols))
 	for i, fromCol := range d.FromCols {
@@ -1053,6 +1077,7 @@ func ResolveFK(
 		OnUpdate:            tree.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               tree.CompositeKeyMatchMethodValue[d.Match],
 		ConstraintID:        tbl.NextConstraintID,
+		Deferrability:       tree.ConstraintDeferrabilityValue[d.Deferrability],
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -1922,6 +1947,9 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			if d.Deferrability.IsDeferrable() {
+				return nil, sqlerrors.NewDeferrableUniqueIndexError()
+			}
 			// If the index is named, ensure that the name is unique. Unnamed
 			// indexes will be given a unique auto-generated name later on when
 			// AllocateIDs is called.
@@ -2040,7 +2068,8 @@ func NewTableDesc(
 					return nil, err
 				}
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExcludeConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -2208,6 +2237,13 @@ func NewTableDesc(
 				}
 			}
 
+		case *tree.ExcludeConstraintTableDef:
+			if err := addExcludeConstraintTableDef(
+				ctx, evalCtx, d, &desc, n.Table, NewTable, tree.ValidationDefault, semaCtx,
+			); err != nil {
+				return nil, err
+			}
+
 		case *tree.IndexTableDef, *tree.FamilyTableDef, *tree.LikeTableDef:
 			// Pass, handled above.
 
@@ -2636,6 +2672,21 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 				defs = append(defs, &def)
 			}
 			for _, c := range td.UniqueWithoutIndexConstraints {
+				if c.IsExclusion() {
+					def, err := exclusionConstraintTableDef(td, &c)
+					if err != nil {
+						return nil, err
+					}
+					def.Name = tree.Name(c.Name)
+					if c.IsPartial() {
+						def.Predicate, err = parser.ParseExpr(c.Predicate)
+						if err != nil {
+							return nil, err
+						}
+					}
+					defs = append(defs, def)
+					continue
+				}
 				def := tree.UniqueConstraintTableDef{
 					IndexTableDef: tree.IndexTableDef{
 						Name:    tree.Name(c.Name),
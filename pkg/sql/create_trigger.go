@@ -0,0 +1,232 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *tabledesc.Mutable
+	fnDesc    *funcdesc.Mutable
+}
+
+// CreateTrigger creates a trigger on a table.
+// Privileges: CREATE on the table and EXECUTE on the trigger function.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_Triggers) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"triggers are not supported until the cluster version is upgraded")
+	}
+
+	switch n.ActionTime {
+	case tree.TriggerActionTimeBefore, tree.TriggerActionTimeAfter:
+	case tree.TriggerActionTimeInsteadOf:
+		return nil, unimplemented.NewWithIssue(28296, "INSTEAD OF triggers are not supported")
+	default:
+		return nil, errors.AssertionFailedf("unexpected trigger action time %s", n.ActionTime)
+	}
+	for _, ev := range n.Events {
+		if ev.EventType == tree.TriggerEventTruncate {
+			return nil, unimplemented.NewWithIssue(28296, "TRUNCATE triggers are not supported")
+		}
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.TableName, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc.IsVirtualTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is a virtual table", tableDesc.GetName())
+	}
+	if err := checkTableSchemaUnlocked(tableDesc); err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	// The trigger function must be a function without arguments which returns
+	// the trigger type.
+	ol, err := p.matchRoutine(ctx, &tree.RoutineObj{
+		FuncName: n.FuncName,
+		Params:   tree.RoutineParams{},
+	}, true /* required */, tree.UDFRoutine)
+	if err != nil {
+		return nil, err
+	}
+	if ol.Type != tree.UDFRoutine {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%s is not a user-defined function", n.FuncName.String())
+	}
+	fnDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(ctx, funcdesc.UserDefinedFunctionOIDToID(ol.Oid))
+	if err != nil {
+		return nil, err
+	}
+	if fnDesc.ReturnType.Type.Family() != types.TriggerFamily {
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"function %s must return type trigger", fnDesc.GetName())
+	}
+	if err := p.CheckPrivilege(ctx, fnDesc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc, fnDesc: fnDesc}, nil
+}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	tableDesc := n.tableDesc
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == string(n.n.Name) {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", n.n.Name, tableDesc.GetName())
+		}
+	}
+
+	trigger := descpb.TableDescriptor_Trigger{
+		Name:       string(n.n.Name),
+		ActionTime: descpb.TableDescriptor_Trigger_BEFORE,
+		ForEachRow: n.n.ForEach == tree.TriggerForEachRow,
+		FuncID:     n.fnDesc.GetID(),
+		FuncArgs:   n.n.FuncArgs,
+	}
+	if n.n.ActionTime == tree.TriggerActionTimeAfter {
+		trigger.ActionTime = descpb.TableDescriptor_Trigger_AFTER
+	}
+
+	var whenOpts schemaexpr.TriggerWhenExprOptions
+	whenOpts.ForEachRow = trigger.ForEachRow
+	seenEvents := make(map[tree.TriggerEventType]struct{}, len(n.n.Events))
+	for _, ev := range n.n.Events {
+		if _, ok := seenEvents[ev.EventType]; ok {
+			return pgerror.Newf(pgcode.Syntax,
+				"duplicate trigger events specified at or near %q", ev.EventType.String())
+		}
+		seenEvents[ev.EventType] = struct{}{}
+		event := descpb.TableDescriptor_Trigger_Event{}
+		switch ev.EventType {
+		case tree.TriggerEventInsert:
+			event.Type = descpb.TableDescriptor_Trigger_INSERT
+			whenOpts.HasInsert = true
+		case tree.TriggerEventUpdate:
+			event.Type = descpb.TableDescriptor_Trigger_UPDATE
+			whenOpts.HasUpdate = true
+			for _, colName := range ev.Columns {
+				col, err := catalog.MustFindColumnByTreeName(tableDesc, colName)
+				if err != nil {
+					return err
+				}
+				event.ColumnIDs = append(event.ColumnIDs, col.GetID())
+			}
+		case tree.TriggerEventDelete:
+			event.Type = descpb.TableDescriptor_Trigger_DELETE
+			whenOpts.HasDelete = true
+		default:
+			return errors.AssertionFailedf("unexpected trigger event %s", ev.EventType)
+		}
+		trigger.Events = append(trigger.Events, event)
+	}
+
+	if n.n.When != nil {
+		whenExpr, _, err := schemaexpr.ValidateTriggerWhenExpr(
+			params.ctx, tableDesc, n.n.When, whenOpts, &params.p.semaCtx,
+		)
+		if err != nil {
+			return err
+		}
+		trigger.WhenExpr = whenExpr
+	}
+
+	if tableDesc.NextTriggerID == 0 {
+		tableDesc.NextTriggerID = 1
+	}
+	trigger.ID = tableDesc.NextTriggerID
+	tableDesc.NextTriggerID++
+	tableDesc.Triggers = append(tableDesc.Triggers, trigger)
+
+	n.fnDesc.AddTriggerReference(tableDesc.GetID(), trigger.ID)
+	if err := params.p.writeFuncSchemaChange(params.ctx, n.fnDesc); err != nil {
+		return err
+	}
+	if err := validateDescriptor(params.ctx, params.p, tableDesc); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTriggerNode) Close(context.Context)        {}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+func (*createTriggerNode) ReadingOwnWrites() {}
+
+// validateColumnNotReferencedByTriggers returns an error if the column is
+// referenced by the UPDATE OF column list or the WHEN condition of any trigger
+// on the table.
+func validateColumnNotReferencedByTriggers(
+	tableDesc catalog.TableDescriptor, col catalog.Column,
+) error {
+	for _, trigger := range tableDesc.GetTriggers() {
+		referenced := false
+		for _, ev := range trigger.Events {
+			for _, colID := range ev.ColumnIDs {
+				if colID == col.GetID() {
+					referenced = true
+				}
+			}
+		}
+		whenColIDs, err := schemaexpr.TriggerWhenExprColumnIDs(tableDesc, trigger.WhenExpr)
+		if err != nil {
+			return err
+		}
+		if referenced || whenColIDs.Contains(col.GetID()) {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop column %q because trigger %q on table %q depends on it",
+					col.GetName(), trigger.Name, tableDesc.GetName()),
+				"drop the trigger first",
+			)
+		}
+	}
+	return nil
+}
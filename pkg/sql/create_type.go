@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
@@ -237,6 +238,8 @@ func CreateUserDefinedArrayTypeDesc(
 			labels[i] = e.ElementLabel
 		}
 		elemTyp = types.NewCompositeType(catid.TypeIDToOID(typDesc.GetID()), catid.TypeIDToOID(id), contents, labels)
+	case descpb.TypeDescriptor_DOMAIN:
+		elemTyp = types.MakeDomain(catid.TypeIDToOID(typDesc.GetID()), catid.TypeIDToOID(id), typDesc.Domain.BaseType)
 	default:
 		return nil, errors.AssertionFailedf("cannot make array type for kind %s", t.String())
 	}
@@ -323,6 +326,14 @@ func (p *planner) createUserDefinedType(params runParams, n *createTypeNode) err
 		return params.p.createCompositeWithID(
 			params, id, n.n.CompositeTypeList, n.dbDesc, n.typeName,
 		)
+	case tree.Domain:
+		if !p.execCfg.Settings.Version.IsActive(params.ctx, clusterversion.V24_1_Domains) {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"domains are not supported until the upgrade to version 24.1 is finalized")
+		}
+		return params.p.createDomainWithID(
+			params, id, n.n.DomainBaseType, n.n.DomainConstraints, n.dbDesc, n.typeName,
+		)
 	}
 	return unimplemented.NewWithIssue(25123, "CREATE TYPE")
 }
@@ -464,6 +475,124 @@ func CreateCompositeTypeDesc(
 	}).BuildCreatedMutableType(), nil
 }
 
+// CreateDomainTypeDesc creates a new domain type descriptor.
+func CreateDomainTypeDesc(
+	params runParams,
+	id descpb.ID,
+	baseTypeRef tree.ResolvableTypeReference,
+	constraints []tree.DomainConstraint,
+	dbDesc catalog.DatabaseDescriptor,
+	schema catalog.SchemaDescriptor,
+	typeName *tree.TypeName,
+) (*typedesc.Mutable, error) {
+	baseType, err := tree.ResolveType(params.ctx, baseTypeRef, params.p.semaCtx.TypeResolver)
+	if err != nil {
+		return nil, err
+	}
+	if err := tree.CheckUnsupportedType(params.ctx, &params.p.semaCtx, baseType); err != nil {
+		return nil, err
+	}
+	if baseType.UserDefined() {
+		return nil, unimplemented.NewWithIssue(27796,
+			"domains over user-defined types are not yet supported")
+	}
+	if baseType.Family() == types.ArrayFamily || baseType.Family() == types.TupleFamily {
+		return nil, unimplemented.NewWithIssuef(27796,
+			"domains over %s types are not yet supported", baseType.Family().Name())
+	}
+
+	domain := &descpb.TypeDescriptor_Domain{BaseType: baseType}
+	var sawNull bool
+	for i := range constraints {
+		c := &constraints[i]
+		if c.Check == nil {
+			if (c.Nullable && domain.NotNull) || (!c.Nullable && sawNull) {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			sawNull = sawNull || c.Nullable
+			domain.NotNull = domain.NotNull || !c.Nullable
+			continue
+		}
+		constraint, err := makeDomainCheckConstraint(params.ctx, domain, typeName.Type(), c)
+		if err != nil {
+			return nil, err
+		}
+		domain.Constraints = append(domain.Constraints, constraint)
+	}
+
+	privs, err := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		dbDesc.GetDefaultPrivilegeDescriptor(),
+		schema.GetDefaultPrivilegeDescriptor(),
+		dbDesc.GetID(),
+		params.SessionData().User(),
+		privilege.Types,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           typeName.Type(),
+		ID:             id,
+		ParentID:       dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		Domain:         domain,
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType(), nil
+}
+
+// makeDomainCheckConstraint validates the CHECK constraint of a domain and
+// returns its descriptor. Unnamed constraints are named <domain>_check, with a
+// numeric suffix if that name is already used by the domain. The expression is
+// type checked with VALUE replaced by a NULL of the base type, the same way it
+// is evaluated when values are cast to the domain.
+func makeDomainCheckConstraint(
+	ctx context.Context,
+	domain *descpb.TypeDescriptor_Domain,
+	domainName string,
+	c *tree.DomainConstraint,
+) (descpb.TypeDescriptor_Domain_Constraint, error) {
+	nameInUse := func(name string) bool {
+		for i := range domain.Constraints {
+			if domain.Constraints[i].Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	name := string(c.Name)
+	if name == "" {
+		name = domainName + "_check"
+		for i := 1; nameInUse(name); i++ {
+			name = fmt.Sprintf("%s_check%d", domainName, i)
+		}
+	} else if nameInUse(name) {
+		return descpb.TypeDescriptor_Domain_Constraint{}, pgerror.Newf(pgcode.DuplicateObject,
+			"constraint %q for domain %q already exists", name, domainName)
+	}
+
+	expr, err := eval.SubstituteDomainValue(c.Check, &tree.CastExpr{
+		Expr:       tree.DNull,
+		Type:       domain.BaseType,
+		SyntaxMode: tree.CastShort,
+	})
+	if err != nil {
+		return descpb.TypeDescriptor_Domain_Constraint{}, err
+	}
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.Properties.Require("CHECK", tree.RejectSpecial|tree.RejectSubqueries)
+	if _, err := tree.TypeCheckAndRequire(ctx, expr, &semaCtx, types.Bool, "CHECK"); err != nil {
+		return descpb.TypeDescriptor_Domain_Constraint{}, err
+	}
+	return descpb.TypeDescriptor_Domain_Constraint{
+		Name:     name,
+		Expr:     tree.Serialize(c.Check),
+		Validity: descpb.ConstraintValidity_Validated,
+	}, nil
+}
+
 func (p *planner) createEnumWithID(
 	params runParams,
 	id descpb.ID,
@@ -516,6 +645,28 @@ func (p *planner) createCompositeWithID(
 	return nil
 }
 
+func (p *planner) createDomainWithID(
+	params runParams,
+	id descpb.ID,
+	baseType tree.ResolvableTypeReference,
+	constraints []tree.DomainConstraint,
+	dbDesc catalog.DatabaseDescriptor,
+	typeName *tree.TypeName,
+) error {
+	// Generate a key in the namespace table and a new id for this type.
+	schema, err := getCreateTypeParams(params, typeName, dbDesc)
+	if err != nil {
+		return err
+	}
+
+	typeDesc, err := CreateDomainTypeDesc(params, id, baseType, constraints, dbDesc, schema, typeName)
+	if err != nil {
+		return err
+	}
+
+	return p.finishCreateType(params, id, typeName, typeDesc, dbDesc, schema)
+}
+
 func (p *planner) finishCreateType(
 	params runParams,
 	id descpb.ID,
@@ -38,6 +38,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
+	"github.com/cockroachdb/cockroach/pkg/sql/storageparam/viewstorageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -276,6 +278,26 @@ func (n *createViewNode) startExec(params runParams) error {
 					orderedTypeDeps.Add(backrefID)
 				}
 				desc.DependsOnTypes = append(desc.DependsOnTypes, orderedTypeDeps.Ordered()...)
+
+				// Start the job which refreshes the view incrementally if requested.
+				if createView.Materialized && len(createView.StorageParams) > 0 {
+					setter := &viewstorageparam.Setter{}
+					if err := storageparam.Set(
+						params.ctx,
+						params.p.SemaCtx(),
+						params.EvalContext(),
+						createView.StorageParams,
+						setter,
+					); err != nil {
+						return err
+					}
+					if err := params.p.setMaterializedViewIncrementalRefresh(
+						params.ctx, &desc, setter.IncrementalRefresh,
+						tree.AsStringWithFQNames(n.createView, params.Ann()),
+					); err != nil {
+						return err
+					}
+				}
 				newDesc = &desc
 
 				if err = params.p.createDescriptor(
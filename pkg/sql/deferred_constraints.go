@@ -0,0 +1,333 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// deferredConstraints tracks the deferrable constraints of a transaction: the
+// modes set with SET CONSTRAINTS, and the violations of deferred constraints
+// which must be checked again before the transaction commits.
+type deferredConstraints struct {
+	// allMode is the mode set by the last SET CONSTRAINTS ALL statement.
+	allMode constraintMode
+
+	// modes contains the modes set by SET CONSTRAINTS for constraints by name
+	// since the last SET CONSTRAINTS ALL statement.
+	modes map[string]constraintMode
+
+	mu struct {
+		// The checks of a statement may run concurrently.
+		syncutil.Mutex
+
+		// pending contains the violations of deferred constraints found by
+		// the statements of the transaction.
+		pending []deferredViolation
+	}
+}
+
+// constraintMode is the mode of a deferrable constraint in a transaction.
+type constraintMode int8
+
+const (
+	// constraintModeDefault means that the constraint is deferred if it is
+	// INITIALLY DEFERRED.
+	constraintModeDefault constraintMode = iota
+	// constraintModeImmediate means that the constraint is checked at the end
+	// of each statement.
+	constraintModeImmediate
+	// constraintModeDeferred means that the constraint is checked when the
+	// transaction commits.
+	constraintModeDeferred
+)
+
+// deferredViolation is a key which violated a deferred constraint at the end of
+// a statement.
+type deferredViolation struct {
+	// tableID is the table the constraint is defined on. For foreign keys, this
+	// is the origin table.
+	tableID    descpb.ID
+	constraint string
+
+	// keyVals are the values of the constraint columns.
+	keyVals tree.Datums
+
+	// err is the error reported if the constraint is still violated when it is
+	// checked again.
+	err error
+}
+
+// reset clears the state at the end of a transaction.
+func (dc *deferredConstraints) reset() {
+	dc.allMode = constraintModeDefault
+	dc.modes = nil
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.mu.pending = nil
+}
+
+// isDeferred returns whether the constraint with the given name is currently
+// deferred.
+func (dc *deferredConstraints) isDeferred(name string, initiallyDeferred bool) bool {
+	mode, ok := dc.modes[name]
+	if !ok {
+		mode = dc.allMode
+	}
+	switch mode {
+	case constraintModeImmediate:
+		return false
+	case constraintModeDeferred:
+		return true
+	default:
+		return initiallyDeferred
+	}
+}
+
+// setModes sets the mode of the given constraints, or of all constraints if
+// names is empty.
+func (dc *deferredConstraints) setModes(names tree.NameList, mode constraintMode) {
+	if len(names) == 0 {
+		dc.allMode = mode
+		dc.modes = nil
+		return
+	}
+	if dc.modes == nil {
+		dc.modes = make(map[string]constraintMode)
+	}
+	for _, name := range names {
+		dc.modes[string(name)] = mode
+	}
+}
+
+// addViolation records the violation of a deferred constraint.
+func (dc *deferredConstraints) addViolation(v deferredViolation) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.mu.pending = append(dc.mu.pending, v)
+}
+
+// hasPending returns whether there are violations of deferred constraints to
+// check.
+func (dc *deferredConstraints) hasPending() bool {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	return len(dc.mu.pending) > 0
+}
+
+// check checks again the pending violations of the constraints for which
+// include returns true, or of all constraints if include is nil. It returns the
+// error of the first violation which still holds.
+func (dc *deferredConstraints) check(
+	ctx context.Context, txn descs.Txn, include func(constraint string) bool,
+) error {
+	var pending, remaining []deferredViolation
+	func() {
+		dc.mu.Lock()
+		defer dc.mu.Unlock()
+		pending, dc.mu.pending = dc.mu.pending, nil
+	}()
+	defer func() {
+		dc.mu.Lock()
+		defer dc.mu.Unlock()
+		dc.mu.pending = append(dc.mu.pending, remaining...)
+	}()
+	for i, v := range pending {
+		if include != nil && !include(v.constraint) {
+			remaining = append(remaining, v)
+			continue
+		}
+		violated, err := v.stillViolated(ctx, txn)
+		if err == nil && violated {
+			err = v.err
+		}
+		if err != nil {
+			remaining = append(remaining, pending[i:]...)
+			return err
+		}
+	}
+	return nil
+}
+
+// stillViolated returns whether the constraint is still violated for the key
+// of the violation. Violations of constraints which have been dropped since
+// are ignored.
+func (v *deferredViolation) stillViolated(ctx context.Context, txn descs.Txn) (bool, error) {
+	tbl, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, v.tableID)
+	if err != nil {
+		return false, err
+	}
+	if tbl.Dropped() {
+		return false, nil
+	}
+	c := catalog.FindConstraintByName(tbl, v.constraint)
+	if c == nil {
+		return false, nil
+	}
+	var query string
+	if fk := c.AsForeignKey(); fk != nil {
+		// A foreign key is violated if there is a row with the key in the origin
+		// table but not in the referenced table.
+		referenced, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, fk.GetReferencedTableID())
+		if err != nil {
+			return false, err
+		}
+		fkDesc := fk.ForeignKeyDesc()
+		originCols, err := deferredCheckColumnNames(tbl, fkDesc.OriginColumnIDs)
+		if err != nil {
+			return false, err
+		}
+		referencedCols, err := deferredCheckColumnNames(referenced, fkDesc.ReferencedColumnIDs)
+		if err != nil {
+			return false, err
+		}
+		query = fmt.Sprintf(
+			`SELECT EXISTS (SELECT 1 FROM [%d AS o] WHERE %s) AND NOT EXISTS (SELECT 1 FROM [%d AS r] WHERE %s)`,
+			tbl.GetID(), deferredCheckKeyFilter(originCols),
+			referenced.GetID(), deferredCheckKeyFilter(referencedCols),
+		)
+	} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
+		// A unique constraint is violated if there is more than one row with the
+		// key. The key values are ordered by column ID, like the columns of the
+		// constraint in the optimizer catalog.
+		cols, err := deferredCheckColumnNames(tbl, uwi.CollectKeyColumnIDs().Ordered())
+		if err != nil {
+			return false, err
+		}
+		filter := deferredCheckKeyFilter(cols)
+		if uwi.IsPartial() {
+			filter = fmt.Sprintf("%s AND (%s)", filter, uwi.GetPredicate())
+		}
+		query = fmt.Sprintf(
+			`SELECT count(*) > 1 FROM [%d AS t] WHERE %s`, tbl.GetID(), filter,
+		)
+	} else {
+		return false, nil
+	}
+	qargs := make([]interface{}, len(v.keyVals))
+	for i, d := range v.keyVals {
+		qargs[i] = d
+	}
+	row, err := txn.QueryRowEx(
+		ctx, "check-deferred-constraint", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, query, qargs...,
+	)
+	if err != nil {
+		return false, err
+	}
+	return row != nil && tree.MustBeDBool(row[0]) == tree.DBoolTrue, nil
+}
+
+// deferredCheckColumnNames returns the names of the given columns.
+func deferredCheckColumnNames(
+	tbl catalog.TableDescriptor, colIDs []descpb.ColumnID,
+) ([]string, error) {
+	names := make([]string, len(colIDs))
+	for i, colID := range colIDs {
+		col, err := catalog.MustFindColumnByID(tbl, colID)
+		if err != nil {
+			return nil, err
+		}
+		names[i] = col.GetName()
+	}
+	return names, nil
+}
+
+// deferredCheckKeyFilter returns a filter which compares the given columns to
+// the placeholders $1, $2, etc.
+func deferredCheckKeyFilter(cols []string) string {
+	var buf strings.Builder
+	for i, col := range cols {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		fmt.Fprintf(&buf, "%s = $%d", tree.NameString(col), i+1)
+	}
+	return buf.String()
+}
+
+// SetConstraints sets the mode of deferrable constraints in the current
+// transaction.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	if p.extendedEvalCtx.deferredConstraints == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"SET CONSTRAINTS is not supported in this context")
+	}
+	return &setConstraintsNode{n: n}, nil
+}
+
+type setConstraintsNode struct {
+	n *tree.SetConstraints
+}
+
+func (n *setConstraintsNode) startExec(params runParams) error {
+	p := params.p
+	names := n.n.Names
+	if n.n.All {
+		names = nil
+	}
+	for _, name := range names {
+		row, err := p.InternalSQLTxn().QueryRowEx(
+			params.ctx, "set-constraints", p.txn, sessiondata.NoSessionDataOverride,
+			`SELECT count(*), count(*) FILTER (WHERE condeferrable) FROM pg_catalog.pg_constraint WHERE conname = $1`,
+			string(name),
+		)
+		if err != nil {
+			return err
+		}
+		if tree.MustBeDInt(row[0]) == 0 {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q does not exist", string(name))
+		}
+		if tree.MustBeDInt(row[1]) == 0 {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"constraint %q is not deferrable", string(name))
+		}
+	}
+	dc := p.extendedEvalCtx.deferredConstraints
+	if n.n.Deferred {
+		dc.setModes(names, constraintModeDeferred)
+		return nil
+	}
+	dc.setModes(names, constraintModeImmediate)
+	if !dc.hasPending() {
+		return nil
+	}
+	// The pending violations of the constraints which become immediate are
+	// checked right away.
+	var include func(string) bool
+	if len(names) > 0 {
+		include = func(constraint string) bool {
+			for _, name := range names {
+				if string(name) == constraint {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return dc.check(params.ctx, p.InternalSQLTxn(), include)
+}
+
+func (n *setConstraintsNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *setConstraintsNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *setConstraintsNode) Close(ctx context.Context)           {}
@@ -67,6 +67,11 @@ func (n *discardNode) startExec(params runParams) error {
 			return err
 		}
 
+		// UNLISTEN *
+		if params.p.notifications != nil && params.p.notifications.listener != nil {
+			params.p.notifications.listener.unlistenAll()
+		}
+
 	case tree.DiscardModeSequences:
 		params.p.sessionDataMutatorIterator.applyOnEachMutator(func(m sessionDataMutator) {
 			m.data.SequenceState = sessiondata.NewSequenceState()
@@ -14,6 +14,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
 	"sort"
 
@@ -42,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra/execopnode"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -1920,6 +1923,7 @@ func (dsp *DistSQLPlanner) createTableReaders(
 			parallelize:       n.parallelize,
 			estimatedRowCount: n.estimatedRowCount,
 			reqOrdering:       n.reqOrdering,
+			sample:            n.sample,
 		},
 	)
 	return p, err
@@ -1937,6 +1941,7 @@ type tableReaderPlanningInfo struct {
 	parallelize       bool
 	estimatedRowCount uint64
 	reqOrdering       ReqOrdering
+	sample            opt.TableSample
 }
 
 const defaultLocalScansConcurrencyLimit = 1024
@@ -2065,7 +2070,15 @@ func (dsp *DistSQLPlanner) planTableReaders(
 		ignoreMisplannedRanges bool
 		err                    error
 	)
-	if planCtx.isLocal {
+	if info.sample.IsSet() {
+		if err := dsp.planTableSample(ctx, planCtx, info); err != nil {
+			return err
+		}
+	}
+	if len(info.spans) == 0 {
+		// All ranges were skipped by TABLESAMPLE SYSTEM, so there is nothing
+		// to scan.
+	} else if planCtx.isLocal {
 		spanPartitions, parallelizeLocal = dsp.maybeParallelizeLocalScans(ctx, planCtx, info)
 	} else if info.post.Limit == 0 {
 		// No hard limit - plan all table readers where their data live. Note
@@ -2149,6 +2162,13 @@ func (dsp *DistSQLPlanner) planTableReaders(
 		typs[i] = info.spec.FetchSpec.FetchedColumns[i].Type
 	}
 
+	if len(corePlacement) == 0 {
+		// Plan a Values processor that produces no rows in place of the
+		// TableReaders.
+		corePlacement = []physicalplan.ProcessorCorePlacement{{SQLInstanceID: dsp.gatewaySQLInstanceID}}
+		corePlacement[0].Core.Values = dsp.createValuesSpec(planCtx, typs, 0 /* numRows */, nil /* rawBytes */)
+	}
+
 	// Note: we will set a merge ordering below.
 	p.AddNoInputStage(corePlacement, info.post, typs, execinfrapb.Ordering{})
 
@@ -2164,6 +2184,66 @@ func (dsp *DistSQLPlanner) planTableReaders(
 	return nil
 }
 
+// planTableSample prepares the table readers described by info for a
+// TABLESAMPLE clause. BERNOULLI sampling is performed by the table readers
+// themselves, while SYSTEM sampling selects whole ranges to scan and replaces
+// info.spans with the parts of the spans that fall into the selected ranges.
+func (dsp *DistSQLPlanner) planTableSample(
+	ctx context.Context, planCtx *PlanningCtx, info *tableReaderPlanningInfo,
+) error {
+	sample := &execinfrapb.TableReaderSampleSpec{Probability: info.sample.Probability}
+	if info.sample.Repeatable {
+		sample.Seed = math.Float64bits(info.sample.Seed)
+	} else {
+		sample.Seed = rand.Uint64()
+	}
+	if info.sample.Method == tree.TableSampleBernoulli {
+		info.spec.Sample = sample
+		return nil
+	}
+
+	it := planCtx.spanIter
+	if it == nil {
+		it = dsp.spanResolver.NewSpanResolverIterator(planCtx.ExtendedEvalCtx.Txn, nil /* optionalOracle */)
+	}
+	var spans roachpb.Spans
+	for _, span := range info.spans {
+		if len(span.EndKey) == 0 {
+			// A point lookup reads a single row, so it is sampled on its own.
+			if sample.SampleKey(span.Key) {
+				spans = append(spans, span)
+			}
+			continue
+		}
+		rSpan, err := keys.SpanAddr(span)
+		if err != nil {
+			return err
+		}
+		for it.Seek(ctx, span, kvcoord.Ascending); ; it.Next(ctx) {
+			if !it.Valid() {
+				return it.Error()
+			}
+			desc := it.Desc()
+			if sample.SampleKey(desc.StartKey.AsRawKey()) {
+				// Only keep the part of the span that falls into the range.
+				piece := span
+				if rSpan.Key.Less(desc.StartKey) {
+					piece.Key = desc.StartKey.AsRawKey()
+				}
+				if desc.EndKey.Less(rSpan.EndKey) {
+					piece.EndKey = desc.EndKey.AsRawKey()
+				}
+				spans = append(spans, piece)
+			}
+			if !it.NeedAnother() {
+				break
+			}
+		}
+	}
+	info.spans = spans
+	return nil
+}
+
 // createPlanForRender takes a PhysicalPlan and updates it to produce results
 // corresponding to the render node. An evaluator stage is added if the render
 // node has any expressions which are not just simple column references.
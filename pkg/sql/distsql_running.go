@@ -2092,7 +2092,11 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 			}
 		}
 
-		log.VEventf(ctx, 2, "executing cascade for constraint %s", plan.cascades[i].FKConstraint.Name())
+		if t := plan.cascades[i].Trigger; t != nil {
+			log.VEventf(ctx, 2, "executing cascade for trigger %s", t.Name())
+		} else {
+			log.VEventf(ctx, 2, "executing cascade for constraint %s", plan.cascades[i].FKConstraint.Name())
+		}
 
 		// We place a sequence point before every cascade, so that each subsequent
 		// cascade can observe the writes by the previous step. However, The
@@ -47,10 +47,6 @@ func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret pl
 		return nil, err
 	}
 
-	if n.DropBehavior == tree.DropCascade {
-		// TODO(chengxiong): remove this check when drop function cascade is supported.
-		return nil, unimplemented.Newf("DROP FUNCTION...CASCADE", "drop function cascade not supported")
-	}
 	dropNode := &dropFunctionNode{
 		toDrop:       make([]*funcdesc.Mutable, 0, len(n.Routines)),
 		dropBehavior: n.DropBehavior,
@@ -77,7 +73,16 @@ func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret pl
 		if err != nil {
 			return nil, err
 		}
-		if n.DropBehavior != tree.DropCascade && len(mut.DependedOnBy) > 0 {
+		if n.DropBehavior == tree.DropCascade {
+			// Only triggers are dropped along with the function.
+			// TODO(chengxiong): support dropping other dependent objects.
+			for _, ref := range mut.DependedOnBy {
+				if len(ref.TriggerIDs) == 0 || len(ref.IndexIDs) > 0 ||
+					len(ref.ColumnIDs) > 0 || len(ref.ConstraintIDs) > 0 {
+					return nil, unimplemented.Newf("DROP FUNCTION...CASCADE", "drop function cascade not supported")
+				}
+			}
+		} else if len(mut.DependedOnBy) > 0 {
 			dependedOnByIDs := make([]descpb.ID, 0, len(mut.DependedOnBy))
 			for _, ref := range mut.DependedOnBy {
 				dependedOnByIDs = append(dependedOnByIDs, ref.ID)
@@ -106,6 +111,11 @@ func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret pl
 
 func (n *dropFunctionNode) startExec(params runParams) error {
 	for _, fnMutable := range n.toDrop {
+		if n.dropBehavior == tree.DropCascade {
+			if err := params.p.dropTriggersUsingFunction(params.ctx, fnMutable); err != nil {
+				return err
+			}
+		}
 		if err := params.p.dropFunctionImpl(params.ctx, fnMutable); err != nil {
 			return err
 		}
@@ -194,6 +204,58 @@ func (p *planner) canDropFunction(ctx context.Context, fnDesc catalog.FunctionDe
 	return nil
 }
 
+// dropTriggersUsingFunction removes the triggers which execute the given
+// function from their tables. It is used by DROP FUNCTION ... CASCADE.
+func (p *planner) dropTriggersUsingFunction(
+	ctx context.Context, fnMutable *funcdesc.Mutable,
+) error {
+	// Removing the trigger references modifies DependedOnBy, so iterate over a
+	// copy.
+	refs := append([]descpb.FunctionDescriptor_Reference(nil), fnMutable.DependedOnBy...)
+	for _, ref := range refs {
+		if len(ref.TriggerIDs) == 0 {
+			continue
+		}
+		tableDesc, err := p.Descriptors().MutableByID(p.txn).Table(ctx, ref.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkTableSchemaUnlocked(tableDesc); err != nil {
+			return err
+		}
+		triggerIDs := append([]descpb.TriggerID(nil), ref.TriggerIDs...)
+		triggers := tableDesc.Triggers[:0]
+		for _, trigger := range tableDesc.Triggers {
+			dropped := false
+			for _, id := range triggerIDs {
+				if trigger.ID == id {
+					dropped = true
+					break
+				}
+			}
+			if !dropped {
+				triggers = append(triggers, trigger)
+			}
+		}
+		tableDesc.Triggers = triggers
+		for _, id := range triggerIDs {
+			fnMutable.RemoveTriggerReference(tableDesc.GetID(), id)
+		}
+		if err := validateDescriptor(ctx, p, tableDesc); err != nil {
+			return err
+		}
+		if err := p.writeSchemaChange(
+			ctx, tableDesc, descpb.InvalidMutationID,
+			fmt.Sprintf("dropping triggers of table %s(%d) which use function %s(%d)",
+				tableDesc.Name, tableDesc.ID, fnMutable.Name, fnMutable.ID,
+			),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *planner) dropFunctionImpl(ctx context.Context, fnMutable *funcdesc.Mutable) error {
 	if fnMutable.Dropped() {
 		return errors.Errorf("function %q is already being dropped", fnMutable.Name)
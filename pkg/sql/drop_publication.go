@@ -0,0 +1,107 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type dropPublicationNode struct {
+	n      *tree.DropPublication
+	dbDesc *dbdesc.Mutable
+}
+
+// DropPublication drops publications of the current database. CASCADE and
+// RESTRICT have no effect since nothing in the cluster depends on
+// publications.
+// Privileges: ownership of the publications or the admin role.
+func (p *planner) DropPublication(ctx context.Context, n *tree.DropPublication) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+	if p.CurrentDatabase() == "" {
+		return nil, sqlerrors.ErrNoDatabase
+	}
+	dbDesc, err := p.Descriptors().MutableByName(p.txn).Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range n.Names {
+		pub := dbDesc.GetPublication(string(name))
+		if pub == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+		}
+		if hasAdmin {
+			continue
+		}
+		isOwner, err := p.checkRolePredicate(ctx, p.User(), func(role username.SQLUsername) (bool, error) {
+			return role == pub.OwnerProto.Decode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !isOwner {
+			return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of publication %s", name)
+		}
+	}
+	return &dropPublicationNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *dropPublicationNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("publication"))
+
+	dropped := false
+	for _, name := range n.n.Names {
+		if !n.dbDesc.RemovePublication(string(name)) {
+			params.p.BufferClientNotice(
+				params.ctx,
+				pgnotice.Newf("publication %q does not exist, skipping", name),
+			)
+			continue
+		}
+		dropped = true
+	}
+	if !dropped {
+		return nil
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*dropPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropPublicationNode) Close(context.Context)        {}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+func (*dropPublicationNode) ReadingOwnWrites() {}
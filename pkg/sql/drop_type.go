@@ -38,10 +38,27 @@ type dropTypeNode struct {
 var _ planNode = &dropTypeNode{n: nil}
 
 func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	return p.dropType(ctx, n, false /* domain */)
+}
+
+// DropDomain drops domains like DROP TYPE, but only accepts domains.
+func (p *planner) DropDomain(ctx context.Context, n *tree.DropDomain) (planNode, error) {
+	return p.dropType(ctx, &tree.DropType{
+		Names:        n.Names,
+		IfExists:     n.IfExists,
+		DropBehavior: n.DropBehavior,
+	}, true /* domain */)
+}
+
+func (p *planner) dropType(ctx context.Context, n *tree.DropType, domain bool) (planNode, error) {
+	stmt := "DROP TYPE"
+	if domain {
+		stmt = "DROP DOMAIN"
+	}
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP TYPE",
+		stmt,
 	); err != nil {
 		return nil, err
 	}
@@ -51,7 +68,7 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		toDrop: make(map[descpb.ID]*typedesc.Mutable),
 	}
 	if n.DropBehavior == tree.DropCascade {
-		return nil, unimplemented.NewWithIssue(51480, "DROP TYPE CASCADE is not yet supported")
+		return nil, unimplemented.NewWithIssuef(51480, "%s CASCADE is not yet supported", stmt)
 	}
 	for _, name := range n.Names {
 		// Resolve the desired type descriptor.
@@ -66,6 +83,9 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		if _, ok := node.toDrop[typeDesc.ID]; ok {
 			continue
 		}
+		if domain && typeDesc.Kind != descpb.TypeDescriptor_DOMAIN {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", name)
+		}
 		switch typeDesc.Kind {
 		case descpb.TypeDescriptor_ALIAS:
 			// The implicit array types are not directly droppable.
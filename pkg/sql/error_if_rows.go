@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 )
@@ -26,6 +27,12 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr exec.MkErrFn
 
+	// deferrable is set if the wrapped node checks a deferrable constraint. If
+	// the constraint is deferred, the rows produced are recorded as violations
+	// to be checked again before the transaction commits, instead of causing an
+	// error.
+	deferrable *exec.DeferrableCheck
+
 	nexted bool
 }
 
@@ -43,9 +50,37 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	if ok {
+	if !ok {
+		return false, nil
+	}
+	dc := params.extendedEvalCtx.deferredConstraints
+	if n.deferrable == nil || dc == nil ||
+		!dc.isDeferred(n.deferrable.Constraint, n.deferrable.InitiallyDeferred) {
 		return false, n.mkErr(n.plan.Values())
 	}
+	for ok {
+		row := n.plan.Values()
+		keyVals, err := n.deferrable.KeyVals(row)
+		if err != nil {
+			return false, err
+		}
+		for _, d := range keyVals {
+			// A NULL in the key can only come from a MATCH FULL violation. Those
+			// cannot be checked again by key, so they are reported right away.
+			if d == tree.DNull {
+				return false, n.mkErr(row)
+			}
+		}
+		dc.addViolation(deferredViolation{
+			tableID:    descpb.ID(n.deferrable.Table),
+			constraint: n.deferrable.Constraint,
+			keyVals:    keyVals,
+			err:        n.mkErr(row),
+		})
+		if ok, err = n.plan.Next(params); err != nil {
+			return false, err
+		}
+	}
 	return false, nil
 }
 
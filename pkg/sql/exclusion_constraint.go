@@ -0,0 +1,495 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// exclusionRangeFunctions maps the range constructors which can be used in the
+// elements of an exclusion constraint to the type family of their bounds.
+var exclusionRangeFunctions = map[string]types.Family{
+	"int4range": types.IntFamily,
+	"int8range": types.IntFamily,
+	"numrange":  types.DecimalFamily,
+	"tsrange":   types.TimestampFamily,
+	"tstzrange": types.TimestampTZFamily,
+	"daterange": types.DateFamily,
+}
+
+// defaultRangeBounds are the bounds of the ranges built by a range constructor
+// called with only two arguments.
+const defaultRangeBounds = "[)"
+
+// addExcludeConstraintTableDef runs various checks on the given
+// ExcludeConstraintTableDef before adding it as an exclusion constraint to the
+// given table descriptor.
+//
+// Exclusion constraints are stored as UNIQUE WITHOUT INDEX constraints with an
+// exclusion spec, and are enforced the same way: with a check query run after
+// each mutation of the table.
+func addExcludeConstraintTableDef(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	d *tree.ExcludeConstraintTableDef,
+	desc *tabledesc.Mutable,
+	tn tree.TableName,
+	ts TableState,
+	validationBehavior tree.ValidationBehavior,
+	semaCtx *tree.SemaContext,
+) error {
+	if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V24_1_ExclusionConstraints) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"exclusion constraints are not supported until the upgrade to version 24.1 is finalized")
+	}
+	switch d.Using {
+	case "", "btree", "gist":
+	default:
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"access method %q does not support exclusion constraints", d.Using,
+		)
+	}
+
+	excl := &descpb.ExclusionConstraint{Method: d.Using}
+	var columnIDs descpb.ColumnIDs
+	nameParts := make([]string, 0, len(d.Elems))
+	resolveColumn := func(name tree.Name) (catalog.Column, error) {
+		col, err := desc.FindActiveOrNewColumnByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if col.IsInaccessible() {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q is inaccessible and cannot be referenced by an exclusion constraint", name,
+			)
+		}
+		if !columnIDs.Contains(col.GetID()) {
+			columnIDs = append(columnIDs, col.GetID())
+		}
+		return col, nil
+	}
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		if elem.OpClass != "" || elem.Direction != tree.DefaultDirection ||
+			elem.NullsOrder != tree.DefaultNullsOrder {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"operator classes and orderings are not supported in exclusion constraints",
+			)
+		}
+		op := elem.Operator.Symbol
+		switch op {
+		case treecmp.EQ, treecmp.NE, treecmp.Overlaps:
+		default:
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"operator %s is not supported in exclusion constraints", op,
+			)
+		}
+		if d.Using == "btree" && op != treecmp.EQ {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"operator %s is not supported by access method btree", op,
+			)
+		}
+		e := descpb.ExclusionConstraint_Element{Operator: op.String()}
+
+		if elem.Expr == nil {
+			col, err := resolveColumn(elem.Column)
+			if err != nil {
+				return err
+			}
+			lookupOp := op
+			if op == treecmp.NE {
+				lookupOp = treecmp.EQ
+			}
+			if _, ok := tree.CmpOps[lookupOp].LookupImpl(col.GetType(), col.GetType()); !ok {
+				return pgerror.Newf(pgcode.UndefinedFunction,
+					"unsupported comparison operator: <%s> %s <%s>",
+					col.GetType().SQLStringForError(), op, col.GetType().SQLStringForError(),
+				)
+			}
+			e.ColumnIDs = []descpb.ColumnID{col.GetID()}
+			nameParts = append(nameParts, col.GetName())
+			excl.Elements = append(excl.Elements, e)
+			continue
+		}
+
+		// The only supported expressions are range constructors over two columns,
+		// such as tstzrange(start, end).
+		fn, cols, bounds, ok := rangeConstructorArgs(elem.Expr)
+		if !ok {
+			return unimplemented.NewWithIssuef(46657,
+				"exclusion constraints only support columns and range constructors over two columns: %s",
+				tree.AsString(elem.Expr),
+			)
+		}
+		family, ok := exclusionRangeFunctions[fn]
+		if !ok {
+			return pgerror.Newf(pgcode.UndefinedFunction, "unknown range constructor %s", fn)
+		}
+		if op != treecmp.Overlaps {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"operator %s is not supported for ranges in exclusion constraints", op,
+			)
+		}
+		switch bounds {
+		case "[)", "(]", "[]", "()":
+		default:
+			return pgerror.Newf(pgcode.Syntax, "invalid range bound flags %q", bounds)
+		}
+		e.RangeFunction = fn
+		e.RangeBounds = bounds
+		for _, name := range cols {
+			col, err := resolveColumn(name)
+			if err != nil {
+				return err
+			}
+			if col.GetType().Family() != family {
+				return pgerror.Newf(pgcode.DatatypeMismatch,
+					"%s cannot be built from column %q of type %s",
+					fn, col.GetName(), col.GetType().SQLStringForError(),
+				)
+			}
+			e.ColumnIDs = append(e.ColumnIDs, col.GetID())
+		}
+		nameParts = append(nameParts, fn)
+		excl.Elements = append(excl.Elements, e)
+	}
+
+	// If there is a predicate, validate it.
+	var predicate string
+	if d.Predicate != nil {
+		var err error
+		predicate, err = schemaexpr.ValidateUniqueWithoutIndexPredicate(
+			ctx, tn, desc, d.Predicate, semaCtx, evalCtx.Settings.Version.ActiveVersionOrEmpty(ctx),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Verify we are not writing a constraint over the same name.
+	constraintName := string(d.Name)
+	if constraintName == "" {
+		// Mirror the name Postgres would generate.
+		constraintName = tabledesc.GenerateUniqueName(
+			fmt.Sprintf("%s_%s_excl", desc.GetName(), strings.Join(nameParts, "_")),
+			func(p string) bool {
+				return catalog.FindConstraintByName(desc, p) != nil
+			},
+		)
+	} else if c := catalog.FindConstraintByName(desc, constraintName); c != nil {
+		return pgerror.Newf(pgcode.DuplicateObject, "duplicate constraint name: %q", constraintName)
+	}
+
+	validity := descpb.ConstraintValidity_Validated
+	if ts != NewTable {
+		if validationBehavior == tree.ValidationSkip {
+			validity = descpb.ConstraintValidity_Unvalidated
+		} else {
+			validity = descpb.ConstraintValidity_Validating
+		}
+	}
+
+	uc := descpb.UniqueWithoutIndexConstraint{
+		Name:         constraintName,
+		TableID:      desc.ID,
+		ColumnIDs:    columnIDs,
+		Predicate:    predicate,
+		Validity:     validity,
+		ConstraintID: desc.NextConstraintID,
+		Exclusion:    excl,
+	}
+	desc.NextConstraintID++
+	if ts == NewTable {
+		desc.UniqueWithoutIndexConstraints = append(desc.UniqueWithoutIndexConstraints, uc)
+	} else {
+		desc.AddUniqueWithoutIndexMutation(&uc, descpb.DescriptorMutation_ADD)
+	}
+	return nil
+}
+
+// rangeConstructorArgs returns the function name, the column arguments and the
+// bounds of the given expression if it is a call of a range constructor with
+// two column arguments and optional constant bounds, such as
+// tstzrange(start, end, '[]').
+func rangeConstructorArgs(
+	expr tree.Expr,
+) (fn string, cols [2]tree.Name, bounds string, ok bool) {
+	f, ok := expr.(*tree.FuncExpr)
+	if !ok || f.Type != 0 || f.Filter != nil || f.WindowDef != nil || len(f.OrderBy) > 0 {
+		return "", cols, "", false
+	}
+	name, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+	if !ok || name.NumParts != 1 {
+		return "", cols, "", false
+	}
+	if len(f.Exprs) != 2 && len(f.Exprs) != 3 {
+		return "", cols, "", false
+	}
+	for i := range cols {
+		arg, ok := f.Exprs[i].(*tree.UnresolvedName)
+		if !ok || arg.NumParts != 1 || arg.Star {
+			return "", cols, "", false
+		}
+		cols[i] = tree.Name(arg.Parts[0])
+	}
+	bounds = defaultRangeBounds
+	if len(f.Exprs) == 3 {
+		s, ok := f.Exprs[2].(*tree.StrVal)
+		if !ok {
+			return "", cols, "", false
+		}
+		bounds = s.RawString()
+	}
+	return name.Parts[0], cols, bounds, true
+}
+
+// exclusionOperator returns the comparison operator stored in an element of an
+// exclusion constraint.
+func exclusionOperator(op string) (treecmp.ComparisonOperator, error) {
+	switch op {
+	case "=":
+		return treecmp.MakeComparisonOperator(treecmp.EQ), nil
+	case "<>":
+		return treecmp.MakeComparisonOperator(treecmp.NE), nil
+	case "&&":
+		return treecmp.MakeComparisonOperator(treecmp.Overlaps), nil
+	}
+	return treecmp.ComparisonOperator{}, errors.AssertionFailedf(
+		"unknown exclusion constraint operator %q", op,
+	)
+}
+
+// exclusionConstraintTableDef returns the EXCLUDE definition of the given
+// exclusion constraint. The name and the predicate of the constraint are not
+// included.
+func exclusionConstraintTableDef(
+	desc catalog.TableDescriptor, uc *descpb.UniqueWithoutIndexConstraint,
+) (*tree.ExcludeConstraintTableDef, error) {
+	def := &tree.ExcludeConstraintTableDef{
+		Using: uc.Exclusion.Method,
+		Elems: make(tree.ExcludeElemList, len(uc.Exclusion.Elements)),
+	}
+	for i := range uc.Exclusion.Elements {
+		e := &uc.Exclusion.Elements[i]
+		op, err := exclusionOperator(e.Operator)
+		if err != nil {
+			return nil, err
+		}
+		colNames, err := catalog.ColumnNamesForIDs(desc, e.ColumnIDs)
+		if err != nil {
+			return nil, err
+		}
+		elem := &def.Elems[i]
+		elem.Operator = op
+		if !e.IsRange() {
+			elem.Column = tree.Name(colNames[0])
+			continue
+		}
+		args := tree.Exprs{
+			&tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{colNames[0]}},
+			&tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{colNames[1]}},
+		}
+		if e.RangeBounds != defaultRangeBounds {
+			args = append(args, tree.NewStrVal(e.RangeBounds))
+		}
+		elem.Expr = &tree.FuncExpr{
+			Func: tree.ResolvableFunctionReference{
+				FunctionReference: &tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{e.RangeFunction}},
+			},
+			Exprs: args,
+		}
+	}
+	return def, nil
+}
+
+// formatExclusionConstraint writes the EXCLUDE definition of the given
+// exclusion constraint, without its name and predicate, to the given buffer.
+func formatExclusionConstraint(
+	f *tree.FmtCtx, desc catalog.TableDescriptor, uc *descpb.UniqueWithoutIndexConstraint,
+) error {
+	def, err := exclusionConstraintTableDef(desc, uc)
+	if err != nil {
+		return err
+	}
+	f.FormatNode(def)
+	return nil
+}
+
+// exclusionViolationQuery generates and returns a query for a pair of rows
+// which violate the given exclusion constraint. Each pair of distinct rows is
+// compared once, which is enough since all the supported operators are
+// commutative.
+//
+// For example, the constraint EXCLUDE (room WITH =, tstzrange(s, e) WITH &&)
+// on the table "tbl" with primary key k would require the following query:
+//
+// SELECT a.room, a.s, a.e, b.room, b.s, b.e
+// FROM (SELECT room, s, e, k FROM tbl) AS a, (SELECT room, s, e, k FROM tbl) AS b
+// WHERE (a.k) < (b.k) AND a.room = b.room AND <a.s, a.e overlaps b.s, b.e>
+// LIMIT 1
+//
+// The predicate of a partial constraint filters the rows in both subqueries.
+func exclusionViolationQuery(
+	srcTbl catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	indexIDForValidation descpb.IndexID,
+) (sql string, colNames []string, _ error) {
+	colNames, err := catalog.ColumnNamesForIDs(srcTbl, uc.ColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	pkColNames, err := catalog.ColumnNamesForIDs(srcTbl, srcTbl.GetPrimaryIndex().IndexDesc().KeyColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	colName := func(alias string, id descpb.ColumnID) string {
+		for i := range uc.ColumnIDs {
+			if uc.ColumnIDs[i] == id {
+				return fmt.Sprintf("%s.%s", alias, tree.NameString(colNames[i]))
+			}
+		}
+		panic(errors.AssertionFailedf("column %d is not part of constraint %q", id, uc.Name))
+	}
+
+	// The subqueries select the constraint columns, followed by the primary key
+	// columns with generated aliases to avoid clashes.
+	var selectCols, aCols, bCols, aPK, bPK []string
+	for i := range colNames {
+		selectCols = append(selectCols, tree.NameString(colNames[i]))
+		aCols = append(aCols, colName("a", uc.ColumnIDs[i]))
+		bCols = append(bCols, colName("b", uc.ColumnIDs[i]))
+	}
+	for i := range pkColNames {
+		alias := fmt.Sprintf("pk%d", i+1)
+		selectCols = append(selectCols, fmt.Sprintf("%s AS %s", tree.NameString(pkColNames[i]), alias))
+		aPK = append(aPK, "a."+alias)
+		bPK = append(bPK, "b."+alias)
+	}
+	source := fmt.Sprintf("[%d AS tbl]", srcTbl.GetID())
+	if indexIDForValidation != 0 {
+		source = fmt.Sprintf("%s@[%d]", source, indexIDForValidation)
+	}
+	subquery := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectCols, ", "), source)
+	if uc.Predicate != "" {
+		subquery = fmt.Sprintf("%s WHERE (%s)", subquery, uc.Predicate)
+	}
+
+	where := []string{fmt.Sprintf("(%s) < (%s)", strings.Join(aPK, ", "), strings.Join(bPK, ", "))}
+	for i := range uc.Exclusion.Elements {
+		e := &uc.Exclusion.Elements[i]
+		if !e.IsRange() {
+			where = append(where, fmt.Sprintf("%s %s %s",
+				colName("a", e.ColumnIDs[0]), e.Operator, colName("b", e.ColumnIDs[0]),
+			))
+			continue
+		}
+		// Ranges with NULL bounds are unbounded, and ranges whose lower bound is
+		// not before their upper bound are empty and never overlap.
+		cmp := "<"
+		if e.InclusiveBounds() {
+			cmp = "<="
+		}
+		aLo, aHi := colName("a", e.ColumnIDs[0]), colName("a", e.ColumnIDs[1])
+		bLo, bHi := colName("b", e.ColumnIDs[0]), colName("b", e.ColumnIDs[1])
+		for _, bounds := range [][2]string{{aLo, bHi}, {bLo, aHi}, {aLo, aHi}, {bLo, bHi}} {
+			where = append(where, fmt.Sprintf("(%[1]s IS NULL OR %[2]s IS NULL OR %[1]s %[3]s %[2]s)",
+				bounds[0], bounds[1], cmp,
+			))
+		}
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %[1]s, %[2]s FROM (%[3]s) AS a, (%[3]s) AS b WHERE %[4]s LIMIT 1`,
+		strings.Join(aCols, ", "),    // 1
+		strings.Join(bCols, ", "),    // 2
+		subquery,                     // 3
+		strings.Join(where, " AND "), // 4
+	)
+	return query, colNames, nil
+}
+
+// validateExclusionConstraint verifies that no two rows in the srcTable
+// conflict according to the given exclusion constraint. See
+// validateUniqueConstraint for the meaning of the arguments.
+func validateExclusionConstraint(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	indexIDForValidation descpb.IndexID,
+	txn isql.Txn,
+	user username.SQLUsername,
+	preExisting bool,
+) error {
+	query, colNames, err := exclusionViolationQuery(srcTable, uc, indexIDForValidation)
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		uc.Name,
+		srcTable.GetName(),
+		colNames,
+		query,
+	)
+
+	sessionDataOverride := sessiondata.NoSessionDataOverride
+	sessionDataOverride.User = user
+	values, err := txn.QueryRowEx(ctx, "validate exclusion constraint", txn.KV(), sessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() == 0 {
+		return nil
+	}
+	valuesStr := make([]string, len(values))
+	for i := range values {
+		valuesStr[i] = values[i].String()
+	}
+	n := len(colNames)
+	// Note: this error message mirrors the message produced by Postgres when
+	// it fails to add an exclusion constraint due to conflicting rows.
+	errMsg := "could not create exclusion constraint"
+	if preExisting {
+		errMsg = "failed to validate exclusion constraint"
+	}
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s %q", errMsg, uc.Name),
+			uc.Name,
+		),
+		fmt.Sprintf(
+			"Key (%[1]s)=(%[2]s) conflicts with key (%[1]s)=(%[3]s).",
+			strings.Join(colNames, ","),
+			strings.Join(valuesStr[:n], ","),
+			strings.Join(valuesStr[n:], ","),
+		),
+	)
+}
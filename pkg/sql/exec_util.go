@@ -1312,6 +1312,10 @@ type ExecutorConfig struct {
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// NotificationRegistry delivers the notifications sent with NOTIFY to the
+	// sessions of this node which listen on their channel.
+	NotificationRegistry *NotificationRegistry
+
 	ExternalIODirConfig base.ExternalIODirConfig
 
 	GCJobNotifier *gcjobnotifier.Notifier
@@ -2013,10 +2017,13 @@ func checkResultType(typ *types.T, fmtCode pgwirebase.FormatCode) error {
 	case types.TSVectorFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.JsonpathFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
 	case types.PGLSNFamily:
+	case types.RangeFamily:
+	case types.MultirangeFamily:
 	case types.RefCursorFamily:
 	case types.TupleFamily:
 	case types.EnumFamily:
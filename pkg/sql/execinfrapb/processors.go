@@ -12,9 +12,11 @@ package execinfrapb
 
 import (
 	context "context"
+	"math"
 	"unicode/utf8"
 	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -22,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treewindow"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/errors"
 )
@@ -90,6 +93,38 @@ func (spec *AggregatorSpec) IsRowCount() bool {
 		spec.IsScalar()
 }
 
+// SampleKey returns whether the row (or range) starting at the given key is
+// part of the sample described by the spec. The decision is a deterministic
+// function of the key and the seed, so scans with the same seed select the
+// same rows as long as the table is unchanged.
+//
+// Unlike the stats.SampleReservoir used by the sampler processor for table
+// statistics, which keeps a fixed number of rows chosen at random and only
+// returns them once its input is exhausted, this decides for each row
+// independently and before it is decoded. That is what TABLESAMPLE requires:
+// the sample size is proportional to the table, rows are streamed, and the
+// sample is repeatable for a given seed.
+func (s *TableReaderSampleSpec) SampleKey(key roachpb.Key) bool {
+	if s.Probability >= 1 {
+		return true
+	}
+	if s.Probability <= 0 {
+		return false
+	}
+	h := util.MakeFNV64()
+	h.Add(s.Seed)
+	for _, b := range key {
+		h.Add(uint64(b))
+	}
+	// FNV mixes the last bytes of the input poorly, so finalize the hash (see
+	// splitmix64) to get a value which is uniformly distributed over uint64.
+	x := h.Sum()
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x) < math.Ldexp(s.Probability, 64)
+}
+
 // GetWindowFuncIdx converts the window function name to the enum value with
 // the same string representation.
 func GetWindowFuncIdx(funcName string) (int32, error) {
@@ -544,6 +544,13 @@ func (ep *DummyEvalPlanner) GetDetailsForSpanStats(
 	return
 }
 
+// TableChanges is part of the eval.Planner interface.
+func (ep *DummyEvalPlanner) TableChanges(
+	context.Context, int64, hlc.Timestamp, hlc.Timestamp,
+) (eval.InternalRows, error) {
+	return nil, errors.AssertionFailedf("TableChanges unimplemented")
+}
+
 // MaybeReallocateAnnotations is part of the eval.Planner interface.
 func (ep *DummyEvalPlanner) MaybeReallocateAnnotations(numAnnotations tree.AnnotationIdx) {
 }
@@ -337,6 +337,19 @@ func NewParquetColumn(typ *types.T, name string, nullable bool) (ParquetColumn,
 		col.DecodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.NewDRefCursor(string(x.([]byte))), nil
 		}
+	case types.RangeFamily, types.MultirangeFamily:
+		populateLogicalStringCol(schemaEl)
+		col.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return []byte(tree.AsStringWithFlags(d, tree.FmtPgwireText)), nil
+		}
+		col.DecodeFn = func(x interface{}) (tree.Datum, error) {
+			if typ.Family() == types.RangeFamily {
+				r, _, err := tree.ParseDRangeFromString(nil /* ctx */, string(x.([]byte)), typ)
+				return r, err
+			}
+			r, _, err := tree.ParseDMultirangeFromString(nil /* ctx */, string(x.([]byte)), typ)
+			return r, err
+		}
 	case types.Box2DFamily:
 		populateLogicalStringCol(schemaEl)
 		col.encodeFn = func(d tree.Datum) (interface{}, error) {
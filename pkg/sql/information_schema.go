@@ -192,6 +192,18 @@ func yesOrNoDatum(b bool) tree.Datum {
 	return noString
 }
 
+// constraintDeferrability returns the deferrability of the constraint. Only
+// foreign key and UNIQUE WITHOUT INDEX constraints can be deferrable.
+func constraintDeferrability(c catalog.Constraint) tree.ConstraintDeferrability {
+	if fk := c.AsForeignKey(); fk != nil {
+		return tree.ConstraintDeferrabilityType[fk.Deferrability()]
+	}
+	if uwoi := c.AsUniqueWithoutIndex(); uwoi != nil {
+		return tree.ConstraintDeferrabilityType[uwoi.Deferrability()]
+	}
+	return tree.ConstraintNotDeferrable
+}
+
 func alwaysOrNeverDatum(b bool) tree.Datum {
 	if b {
 		return alwaysString
@@ -530,6 +542,20 @@ https://www.postgresql.org/docs/9.5/infoschema-columns.html`,
 					udtSchema = tree.NewDString(typeMetaName.Schema)
 				}
 
+				// Columns of a domain type report the domain, and the base type of
+				// the domain as their udt.
+				udtName := tree.NewDString(column.GetType().PGName())
+				domainCatalog := tree.DNull
+				domainSchema := tree.DNull
+				domainName := tree.DNull
+				if column.GetType().IsDomain() && typeMetaName != nil {
+					domainCatalog = tree.NewDString(typeMetaName.Catalog)
+					domainSchema = udtSchema
+					domainName = udtName
+					udtSchema = pgCatalogNameDString
+					udtName = tree.NewDString(column.GetType().DomainBaseType().PGName())
+				}
+
 				// Get the sequence option if it's an identity column.
 				identityStart := tree.DNull
 				identityIncrement := tree.DNull
@@ -570,18 +596,18 @@ https://www.postgresql.org/docs/9.5/infoschema-columns.html`,
 					collationCatalog,                                          // collation_catalog
 					collationSchema,                                           // collation_schema
 					collationName,                                             // collation_name
-					tree.DNull,                                                // domain_catalog
-					tree.DNull,                                                // domain_schema
-					tree.DNull,                                                // domain_name
+					domainCatalog,                                             // domain_catalog
+					domainSchema,                                              // domain_schema
+					domainName,                                                // domain_name
 					dbNameStr,                                                 // udt_catalog
 					udtSchema,                                                 // udt_schema
-					tree.NewDString(column.GetType().PGName()), // udt_name
-					tree.DNull, // scope_catalog
-					tree.DNull, // scope_schema
-					tree.DNull, // scope_name
-					tree.DNull, // maximum_cardinality
-					tree.DNull, // dtd_identifier
-					tree.DNull, // is_self_referencing
+					udtName,                                                   // udt_name
+					tree.DNull,                                                // scope_catalog
+					tree.DNull,                                                // scope_schema
+					tree.DNull,                                                // scope_name
+					tree.DNull,                                                // maximum_cardinality
+					tree.DNull,                                                // dtd_identifier
+					tree.DNull,                                                // is_self_referencing
 					yesOrNoDatum(column.IsGeneratedAsIdentity()), // is_identity
 					colGeneratedAsIdentity,                       // identity_generation
 					identityStart,                                // identity_start
@@ -820,7 +846,7 @@ https://www.postgresql.org/docs/9.5/infoschema-constraint-column-usage.html`,
 					cols = refTable.ForeignKeyReferencedColumns(fk)
 				} else if uwi := c.AsUniqueWithIndex(); uwi != nil {
 					cols = table.IndexKeyColumns(uwi)
-				} else if uwoi := c.AsUniqueWithoutIndex(); uwoi != nil {
+				} else if uwoi := c.AsUniqueWithoutIndex(); uwoi != nil && !uwoi.IsExclusion() {
 					cols = table.UniqueWithoutIndexColumns(uwoi)
 				}
 				for _, col := range cols {
@@ -866,7 +892,7 @@ https://www.postgresql.org/docs/9.5/infoschema-key-column-usage.html`,
 					cols = table.ForeignKeyOriginColumns(fk)
 				} else if uwi := c.AsUniqueWithIndex(); uwi != nil {
 					cols = table.IndexKeyColumns(uwi)
-				} else if uwoi := c.AsUniqueWithoutIndex(); uwoi != nil {
+				} else if uwoi := c.AsUniqueWithoutIndex(); uwoi != nil && !uwoi.IsExclusion() {
 					cols = table.UniqueWithoutIndexColumns(uwoi)
 				}
 				for pos, col := range cols {
@@ -1463,6 +1489,10 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for _, c := range table.AllConstraints() {
+					if u := c.AsUniqueWithoutIndex(); u != nil && u.IsExclusion() {
+						// Like Postgres, exclusion constraints are not listed.
+						continue
+					}
 					kind := catconstants.ConstraintTypeUnique
 					if c.AsCheck() != nil {
 						kind = catconstants.ConstraintTypeCheck
@@ -1471,6 +1501,9 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 					} else if u := c.AsUniqueWithIndex(); u != nil && u.Primary() {
 						kind = catconstants.ConstraintTypePK
 					}
+					deferrability := constraintDeferrability(c)
+					isDeferrable := yesOrNoDatum(deferrability.IsDeferrable())
+					initiallyDeferred := yesOrNoDatum(deferrability == tree.ConstraintInitiallyDeferred)
 					if err := addRow(
 						dbNameStr,                     // constraint_catalog
 						scNameStr,                     // constraint_schema
@@ -1479,8 +1512,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 						scNameStr,                     // table_schema
 						tbNameStr,                     // table_name
 						tree.NewDString(string(kind)), // constraint_type
-						yesOrNoDatum(false),           // is_deferrable
-						yesOrNoDatum(false),           // initially_deferred
+						isDeferrable,                  // is_deferrable
+						initiallyDeferred,             // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -2049,12 +2082,35 @@ var informationSchemaRoleColumnGrantsTable = virtualSchemaTable{
 }
 
 var informationSchemaDomainConstraintsTable = virtualSchemaTable{
-	comment: "domain_constraints was created for compatibility and is currently unimplemented",
-	schema:  vtable.InformationSchemaDomainConstraints,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `CHECK constraints of domains
+https://www.postgresql.org/docs/current/infoschema-domain-constraints.html`,
+	schema: vtable.InformationSchemaDomainConstraints,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachTypeDesc(ctx, p, dbContext, func(db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, typeDesc catalog.TypeDescriptor) error {
+			domain := typeDesc.AsDomainTypeDescriptor()
+			if domain == nil {
+				return nil
+			}
+			dbNameStr := tree.NewDString(db.GetName())
+			scNameStr := tree.NewDString(sc.GetName())
+			domainNameStr := tree.NewDString(domain.GetName())
+			for i := 0; i < domain.NumDomainConstraints(); i++ {
+				if err := addRow(
+					dbNameStr, // constraint_catalog
+					scNameStr, // constraint_schema
+					tree.NewDString(domain.GetDomainConstraint(i).Name), // constraint_name
+					dbNameStr,     // domain_catalog
+					scNameStr,     // domain_schema
+					domainNameStr, // domain_name
+					noString,      // is_deferrable
+					noString,      // initially_deferred
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	},
-	unimplemented: true,
 }
 
 var informationSchemaUserMappingsTable = virtualSchemaTable{
@@ -2121,12 +2177,56 @@ var informationSchemaUsagePrivilegesTable = virtualSchemaTable{
 }
 
 var informationSchemaDomainsTable = virtualSchemaTable{
-	comment: "domains was created for compatibility and is currently unimplemented",
-	schema:  vtable.InformationSchemaDomains,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `domains
+https://www.postgresql.org/docs/current/infoschema-domains.html`,
+	schema: vtable.InformationSchemaDomains,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachTypeDesc(ctx, p, dbContext, func(db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, typeDesc catalog.TypeDescriptor) error {
+			domain := typeDesc.AsDomainTypeDescriptor()
+			if domain == nil {
+				return nil
+			}
+			base := domain.BaseType()
+			dbNameStr := tree.NewDString(db.GetName())
+			collationCatalog := tree.DNull
+			collationSchema := tree.DNull
+			collationName := tree.DNull
+			if locale := base.Locale(); locale != "" {
+				collationCatalog = dbNameStr
+				collationSchema = pgCatalogNameDString
+				collationName = tree.NewDString(locale)
+			}
+			return addRow(
+				dbNameStr,                                     // domain_catalog
+				tree.NewDString(sc.GetName()),                 // domain_schema
+				tree.NewDString(domain.GetName()),             // domain_name
+				tree.NewDString(base.InformationSchemaName()), // data_type
+				characterMaximumLength(base),                  // character_maximum_length
+				characterOctetLength(base),                    // character_octet_length
+				tree.DNull,                                    // character_set_catalog
+				tree.DNull,                                    // character_set_schema
+				tree.DNull,                                    // character_set_name
+				collationCatalog,                              // collation_catalog
+				collationSchema,                               // collation_schema
+				collationName,                                 // collation_name
+				numericPrecision(base),                        // numeric_precision
+				numericPrecisionRadix(base),                   // numeric_precision_radix
+				numericScale(base),                            // numeric_scale
+				datetimePrecision(base),                       // datetime_precision
+				tree.DNull,                                    // interval_type
+				tree.DNull,                                    // interval_precision
+				tree.DNull,                                    // domain_default
+				dbNameStr,                                     // udt_catalog
+				pgCatalogNameDString,                          // udt_schema
+				tree.NewDString(base.PGName()),                // udt_name
+				tree.DNull,                                    // scope_catalog
+				tree.DNull,                                    // scope_schema
+				tree.DNull,                                    // scope_name
+				tree.DNull,                                    // maximum_cardinality
+				tree.DNull,                                    // dtd_identifier
+			)
+		})
 	},
-	unimplemented: true,
 }
 
 var informationSchemaSQLImplementationInfoTable = virtualSchemaTable{
@@ -365,6 +365,9 @@ func (ie *InternalExecutor) newConnExecutorWithTxn(
 			ex.extraTxnState.fromOuterTxn = true
 			ex.extraTxnState.jobs = ie.extraTxnState.jobs
 			ex.extraTxnState.schemaChangerState = ie.extraTxnState.schemaChangerState
+			if ie.extraTxnState.deferredConstraints != nil {
+				ex.extraTxnState.deferredConstraints = ie.extraTxnState.deferredConstraints
+			}
 			ex.extraTxnState.shouldResetSyntheticDescriptors = shouldResetSyntheticDescriptors
 			ex.initPlanner(ctx, &ex.planner)
 		}
@@ -860,6 +863,9 @@ func applyOverrides(o sessiondata.InternalExecutorOverride, sd *sessiondata.Sess
 	if o.OptimizerUseHistograms {
 		sd.OptimizerUseHistograms = true
 	}
+	if o.AllowMaterializedViewMutations {
+		sd.AllowMaterializedViewMutations = true
+	}
 
 	if o.MultiOverride != "" {
 		overrides := strings.Split(o.MultiOverride, ",")
@@ -1513,6 +1519,13 @@ func (icc *internalClientComm) CreateCopyOutResult(cmd CopyOut, pos CmdPos) Copy
 	panic("unimplemented")
 }
 
+// CreateStartReplicationResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateStartReplicationResult(
+	cmd StartReplication, pos CmdPos,
+) StartReplicationResult {
+	panic("unimplemented")
+}
+
 // CreateDrainResult is part of the ClientComm interface.
 func (icc *internalClientComm) CreateDrainResult(pos CmdPos) DrainResult {
 	panic("unimplemented")
@@ -1571,6 +1584,11 @@ type extraTxnState struct {
 	jobs               *txnJobsCollection
 	schemaChangerState *SchemaChangerState
 
+	// deferredConstraints is set when the internal executor runs in the
+	// transaction of a session, so that the violations of deferred constraints
+	// are checked when the session's transaction commits.
+	deferredConstraints *deferredConstraints
+
 	// regionsProvider is populated lazily.
 	regionsProvider *regions.Provider
 }
@@ -0,0 +1,742 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsnutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// logicalReplicationKeepaliveInterval is the interval at which keepalive
+// messages are sent to the client of a logical replication stream. The
+// keepalive messages carry the LSN up to which all changes have been sent, so
+// they also allow idle clients to advance their replication slot.
+const logicalReplicationKeepaliveInterval = 10 * time.Second
+
+// execStartReplication executes the START_REPLICATION command. It takes
+// control of the connection and streams the changes to the tables of the
+// database of the replication slot to the client, in the format of the
+// pgoutput plugin, until the client ends the stream.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication, res StartReplicationResult,
+) (retEv fsm.Event, retPayload fsm.EventPayload) {
+	// When we're done, unblock the network connection.
+	defer cmd.ReplicationDone.Once.Do(cmd.ReplicationDone.WaitGroup.Done)
+
+	if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+		return ex.makeErrEvent(pgerror.New(pgcode.ActiveSQLTransaction,
+			"START_REPLICATION cannot be executed inside a transaction block"), cmd.ParsedStmt.AST)
+	}
+
+	ex.incrementStartedStmtCounter(cmd.Stmt)
+	var cancelQuery context.CancelFunc
+	ctx, cancelQuery = ctxlog.WithCancel(ctx)
+	queryID := ex.server.cfg.GenerateID()
+	ex.addActiveQuery(cmd.ParsedStmt, nil /* placeholders */, queryID, cancelQuery)
+	ex.metrics.EngineMetrics.SQLActiveStatements.Inc(1)
+
+	defer func() {
+		ex.removeActiveQuery(queryID, cmd.Stmt)
+		cancelQuery()
+		ex.metrics.EngineMetrics.SQLActiveStatements.Dec(1)
+		if !payloadHasError(retPayload) {
+			ex.incrementExecutedStmtCounter(cmd.Stmt)
+		}
+		if p, ok := retPayload.(payloadWithError); ok {
+			log.SqlExec.Errorf(ctx, "error executing %s: %+v", cmd, p.errorCause())
+		}
+	}()
+
+	s, err := ex.newLogicalReplicationStream(ctx, cmd)
+	if err == nil {
+		err = s.run(ctx)
+	}
+	if err != nil {
+		return ex.makeErrEvent(err, cmd.ParsedStmt.AST)
+	}
+	return nil, nil
+}
+
+// logicalReplicationStream streams the changes to the tables of a database to
+// a client of the logical replication protocol.
+//
+// Changes are read using a rangefeed over the tables of the database which
+// exist when the stream starts. Every change is mapped to the LSN of its MVCC
+// timestamp (see lsnutil.HLCToLSN), and all the changes with the same LSN are
+// sent as a single transaction once the frontier of the rangefeed has passed
+// the LSN. This means that changes are sent in the order of their commit
+// timestamps and that a transaction is never split across LSNs.
+type logicalReplicationStream struct {
+	execCfg *ExecutorConfig
+	conn    pgwirebase.Conn
+	slot    *replicationSlot
+	// startLSN is the LSN after which changes are streamed.
+	startLSN lsn.LSN
+	tables   map[descpb.ID]*replicatedTable
+	fmtCtx   *tree.FmtCtx
+
+	// walEnd is the LSN up to which all changes have been sent to the client.
+	walEnd lsn.LSN
+	// xid is the transaction ID of the last transaction sent to the client.
+	xid uint32
+	// buf is reused to encode the messages sent to the client.
+	buf []byte
+}
+
+// replicatedTable contains the state required to decode the changes to a
+// replicated table.
+type replicatedTable struct {
+	id descpb.ID
+	// desc is the version of the table descriptor with which changes are
+	// currently decoded. It is nil until the first change is decoded.
+	desc     catalog.TableDescriptor
+	relation pgoutput.Relation
+	// relationSent is true once the Relation message for the current version of
+	// the table has been sent to the client.
+	relationSent bool
+	fetcher      *row.Fetcher
+	alloc        tree.DatumAlloc
+	values       []pgoutput.Value
+}
+
+// replicationBatch is a batch of resolved rangefeed events.
+type replicationBatch struct {
+	// events are sorted by timestamp and key.
+	events []*kvpb.RangeFeedValue
+	// resolved is the LSN up to which all the changes are contained in this
+	// batch or in previous ones.
+	resolved lsn.LSN
+}
+
+func (ex *connExecutor) newLogicalReplicationStream(
+	ctx context.Context, cmd StartReplication,
+) (*logicalReplicationStream, error) {
+	n := cmd.Stmt
+	if n.Kind != pgrepltree.LogicalReplication {
+		return nil, unimplemented.New("physical replication", "physical replication is not supported")
+	}
+	execCfg := ex.server.cfg
+	if err := checkLogicalReplicationSupported(ctx, execCfg.Settings.Version, ex.sessionData()); err != nil {
+		return nil, err
+	}
+	publicationNames, err := parsePgoutputOptions(n.Options)
+	if err != nil {
+		return nil, err
+	}
+	s := &logicalReplicationStream{
+		execCfg: execCfg,
+		conn:    cmd.Conn,
+		tables:  make(map[descpb.ID]*replicatedTable),
+		fmtCtx: tree.NewFmtCtx(
+			tree.FmtPgwireText,
+			tree.FmtDataConversionConfig(ex.sessionData().DataConversionConfig),
+			tree.FmtLocation(ex.sessionData().GetLocation()),
+		),
+	}
+	if err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) (err error) {
+		s.slot, err = getReplicationSlot(ctx, txn, txn.KV(), string(n.Slot))
+		if err != nil {
+			return err
+		}
+		if s.slot == nil {
+			return newUndefinedReplicationSlotError(string(n.Slot))
+		}
+		db, err := txn.Descriptors().ByNameWithLeased(txn.KV()).Get().Database(ctx, ex.sessionData().Database)
+		if err != nil {
+			return err
+		}
+		if db.GetID() != s.slot.databaseID {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"replication slot %q was not created in this database", n.Slot)
+		}
+		pubs := make([]*descpb.DatabaseDescriptor_Publication, 0, len(publicationNames))
+		for _, name := range publicationNames {
+			pub := db.GetPublication(name)
+			if pub == nil {
+				return pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+			}
+			pubs = append(pubs, pub)
+		}
+		tables, err := txn.Descriptors().GetAllTablesInDatabase(ctx, txn.KV(), db)
+		if err != nil {
+			return err
+		}
+		return tables.ForEachDescriptor(func(desc catalog.Descriptor) error {
+			tbl, ok := desc.(catalog.TableDescriptor)
+			if !ok || !tbl.IsPhysicalTable() || tbl.IsSequence() {
+				return nil
+			}
+			// Without publications, the changes to all the tables of the
+			// database are streamed.
+			published := len(pubs) == 0
+			for _, pub := range pubs {
+				published = published || publicationIncludesTable(pub, tbl)
+			}
+			if published {
+				s.tables[tbl.GetID()] = &replicatedTable{id: tbl.GetID()}
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	// Like Postgres, streaming starts at the requested LSN unless the client
+	// has already confirmed a later LSN.
+	s.startLSN = n.LSN
+	if s.slot.confirmedFlushLSN > s.startLSN {
+		s.startLSN = s.slot.confirmedFlushLSN
+	}
+	s.walEnd = s.startLSN
+	return s, nil
+}
+
+// parsePgoutputOptions validates the options passed to the pgoutput plugin
+// by START_REPLICATION. It returns the names of the publications whose changes
+// are streamed.
+func parsePgoutputOptions(opts pgrepltree.Options) (publicationNames []string, _ error) {
+	for _, opt := range opts {
+		var val string
+		if s, ok := opt.Value.(*tree.StrVal); ok {
+			val = s.RawString()
+		}
+		switch opt.Key {
+		case "proto_version":
+			if val != "1" {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"client sent proto_version=%s but server only supports protocol 1", val)
+			}
+		case "publication_names":
+			publicationNames = splitPublicationNames(val)
+		case "binary", "messages", "streaming", "two_phase":
+			if val == "true" || val == "on" || val == "1" {
+				return nil, unimplemented.Newf("pgoutput "+string(opt.Key),
+					"pgoutput option %s is not supported", opt.Key)
+			}
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", opt.Key)
+		}
+	}
+	return publicationNames, nil
+}
+
+// splitPublicationNames splits the value of the publication_names option, a
+// comma-separated list of identifiers. Like in SQL, unquoted identifiers are
+// case-insensitive.
+func splitPublicationNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+			name = name[1 : len(name)-1]
+		} else {
+			name = strings.ToLower(name)
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// run streams changes to the client. It returns once both the server and the
+// client have ended the Copy-both subprotocol, so that the connection can be
+// handed back to the network routine.
+func (s *logicalReplicationStream) run(ctx context.Context) error {
+	if err := s.conn.BeginCopyBoth(ctx); err != nil {
+		return err
+	}
+
+	// The client sends messages concurrently with the changes being streamed,
+	// so they are read on a separate goroutine. The goroutine runs until the
+	// client sends CopyDone or the connection fails.
+	replyRequested := make(chan struct{}, 1)
+	clientDone := make(chan struct{})
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(clientDone)
+		return s.readClientMessages(ctx, replyRequested)
+	})
+
+	streamErr := s.stream(ctx, replyRequested, clientDone)
+	clientEnded := false
+	select {
+	case <-clientDone:
+		clientEnded = true
+	default:
+		// The server ended the stream. The client is expected to acknowledge it
+		// with a CopyDone message, which ends the reader goroutine.
+		streamErr = errors.CombineErrors(streamErr, s.conn.SendCopyDone(ctx))
+	}
+	if err := g.Wait(); err != nil {
+		return errors.CombineErrors(streamErr, err)
+	}
+	if streamErr != nil {
+		return streamErr
+	}
+	if clientEnded {
+		// The client ended the stream, so we end it as well.
+		return s.conn.SendCopyDone(ctx)
+	}
+	return nil
+}
+
+// readClientMessages reads the messages sent by the client until it ends the
+// stream. Standby status updates advance the replication slot.
+func (s *logicalReplicationStream) readClientMessages(
+	ctx context.Context, replyRequested chan<- struct{},
+) error {
+	readBuf := pgwirebase.MakeReadBuffer(
+		pgwirebase.ReadBufferOptionWithClusterSettings(&s.execCfg.Settings.SV),
+	)
+	confirmedFlushLSN := s.slot.confirmedFlushLSN
+	for {
+		typ, _, err := readBuf.ReadTypedMsg(s.conn.Rd())
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case pgwirebase.ClientMsgCopyData:
+			update, ok, err := pgoutput.ParseClientMessage(readBuf.Msg)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if update.FlushLSN > confirmedFlushLSN {
+				if err := updateReplicationSlotLSN(
+					ctx, s.execCfg.InternalDB, s.slot.name, update.FlushLSN,
+				); err != nil {
+					return err
+				}
+				confirmedFlushLSN = update.FlushLSN
+			}
+			if update.ReplyRequested {
+				select {
+				case replyRequested <- struct{}{}:
+				default:
+				}
+			}
+		case pgwirebase.ClientMsgCopyDone:
+			return nil
+		case pgwirebase.ClientMsgCopyFail:
+			return pgerror.Newf(pgcode.QueryCanceled, "replication stream failed: %s", string(readBuf.Msg))
+		case pgwirebase.ClientMsgFlush, pgwirebase.ClientMsgSync:
+			// Like in copy-in mode, Flush and Sync messages are ignored.
+		default:
+			return pgwirebase.NewUnrecognizedMsgTypeErr(typ)
+		}
+	}
+}
+
+// stream sends the changes to the client until the client ends the stream or
+// an error occurs.
+func (s *logicalReplicationStream) stream(
+	ctx context.Context, replyRequested <-chan struct{}, clientDone <-chan struct{},
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make(chan replicationBatch)
+	rangefeedErr := make(chan error, 1)
+	var pending []*kvpb.RangeFeedValue
+	spans := make([]roachpb.Span, 0, len(s.tables))
+	for id := range s.tables {
+		prefix := s.execCfg.Codec.TablePrefix(uint32(id))
+		spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	}
+	// The callbacks of the rangefeed are invoked sequentially, so pending does
+	// not need to be synchronized.
+	rf, err := s.execCfg.RangeFeedFactory.RangeFeed(
+		ctx, "logical-replication-"+s.slot.name, spans, lsnutil.LSNToHLC(s.startLSN),
+		func(ctx context.Context, value *kvpb.RangeFeedValue) {
+			pending = append(pending, value)
+		},
+		rangefeed.WithDiff(true),
+		rangefeed.WithOnFrontierAdvance(func(ctx context.Context, frontier hlc.Timestamp) {
+			var b replicationBatch
+			b, pending = resolveReplicationEvents(pending, frontier)
+			select {
+			case batches <- b:
+			case <-ctx.Done():
+			}
+		}),
+		// Range deletions and SSTable ingestion only happen on dropped tables
+		// and on indexes which are being backfilled, which are not replicated.
+		rangefeed.WithOnDeleteRange(func(context.Context, *kvpb.RangeFeedDeleteRange) {}),
+		rangefeed.WithOnSSTable(func(context.Context, *kvpb.RangeFeedSSTable, roachpb.Span) {}),
+		rangefeed.WithOnInternalError(func(ctx context.Context, err error) {
+			select {
+			case rangefeedErr <- err:
+			default:
+			}
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	defer rf.Close()
+	defer func() {
+		for _, t := range s.tables {
+			if t.fetcher != nil {
+				t.fetcher.Close(ctx)
+			}
+		}
+	}()
+
+	var keepalive timeutil.Timer
+	defer keepalive.Stop()
+	keepalive.Reset(logicalReplicationKeepaliveInterval)
+	for {
+		select {
+		case b := <-batches:
+			if err := s.sendBatch(ctx, b); err != nil {
+				return err
+			}
+		case <-keepalive.C:
+			keepalive.Read = true
+			if err := s.sendKeepalive(ctx); err != nil {
+				return err
+			}
+			keepalive.Reset(logicalReplicationKeepaliveInterval)
+		case <-replyRequested:
+			if err := s.sendKeepalive(ctx); err != nil {
+				return err
+			}
+		case err := <-rangefeedErr:
+			return err
+		case <-clientDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// resolveReplicationEvents splits the pending rangefeed events into the events
+// which are resolved by the frontier, which are returned in a batch, and the
+// remaining events. All the changes with the same LSN must be sent together,
+// so only the events with a wall time below the wall time of the frontier are
+// resolved.
+func resolveReplicationEvents(
+	pending []*kvpb.RangeFeedValue, frontier hlc.Timestamp,
+) (replicationBatch, []*kvpb.RangeFeedValue) {
+	b := replicationBatch{resolved: lsnutil.HLCToLSN(frontier) - 1}
+	var remaining []*kvpb.RangeFeedValue
+	for _, ev := range pending {
+		if ev.Value.Timestamp.WallTime < frontier.WallTime {
+			b.events = append(b.events, ev)
+		} else {
+			remaining = append(remaining, ev)
+		}
+	}
+	sort.Slice(b.events, func(i, j int) bool {
+		if c := b.events[i].Value.Timestamp.Compare(b.events[j].Value.Timestamp); c != 0 {
+			return c < 0
+		}
+		return b.events[i].Key.Compare(b.events[j].Key) < 0
+	})
+	// Rangefeeds can emit the same event more than once.
+	deduped := b.events[:0]
+	for i, ev := range b.events {
+		if i > 0 {
+			prev := b.events[i-1]
+			if prev.Value.Timestamp == ev.Value.Timestamp && prev.Key.Equal(ev.Key) {
+				continue
+			}
+		}
+		deduped = append(deduped, ev)
+	}
+	b.events = deduped
+	return b, remaining
+}
+
+// sendBatch sends the changes of a batch to the client, grouped in
+// transactions by LSN.
+func (s *logicalReplicationStream) sendBatch(ctx context.Context, b replicationBatch) error {
+	for i := 0; i < len(b.events); {
+		j := i + 1
+		txnLSN := lsnutil.HLCToLSN(b.events[i].Value.Timestamp)
+		for j < len(b.events) && lsnutil.HLCToLSN(b.events[j].Value.Timestamp) == txnLSN {
+			j++
+		}
+		if txnLSN > s.startLSN {
+			if err := s.sendTransaction(ctx, txnLSN, b.events[i:j]); err != nil {
+				return err
+			}
+		}
+		i = j
+	}
+	if b.resolved > s.walEnd {
+		s.walEnd = b.resolved
+	}
+	return nil
+}
+
+// sendTransaction sends the changes with the given LSN to the client, wrapped
+// in Begin and Commit messages. Nothing is sent if none of the events are
+// changes to the rows of replicated tables, e.g. if all of them are writes to
+// secondary indexes.
+func (s *logicalReplicationStream) sendTransaction(
+	ctx context.Context, txnLSN lsn.LSN, events []*kvpb.RangeFeedValue,
+) error {
+	commitTime := timeutil.Unix(0, int64(txnLSN))
+	began := false
+	for _, ev := range events {
+		t, ok, err := s.tableForEvent(ctx, ev)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if !began {
+			s.xid++
+			if err := s.send(ctx, pgoutput.AppendBegin(s.header(txnLSN), txnLSN, commitTime, s.xid)); err != nil {
+				return err
+			}
+			began = true
+		}
+		if !t.relationSent {
+			if err := s.send(ctx, pgoutput.AppendRelation(s.header(txnLSN), &t.relation)); err != nil {
+				return err
+			}
+			t.relationSent = true
+		}
+		if err := s.sendChange(ctx, txnLSN, t, ev); err != nil {
+			return err
+		}
+	}
+	if !began {
+		return nil
+	}
+	return s.send(ctx, pgoutput.AppendCommit(s.header(txnLSN), txnLSN, txnLSN, commitTime))
+}
+
+// tableForEvent returns the replicated table whose primary index contains the
+// key of the event, using the version of the table at the timestamp of the
+// event. It returns false if the event is not a change to a row of a
+// replicated table.
+func (s *logicalReplicationStream) tableForEvent(
+	ctx context.Context, ev *kvpb.RangeFeedValue,
+) (*replicatedTable, bool, error) {
+	_, tableID, indexID, err := s.execCfg.Codec.DecodeIndexPrefix(ev.Key)
+	if err != nil {
+		return nil, false, err
+	}
+	t, ok := s.tables[descpb.ID(tableID)]
+	if !ok {
+		return nil, false, nil
+	}
+	ts := ev.Value.Timestamp
+	leased, err := s.execCfg.LeaseManager.Acquire(ctx, ts, t.id)
+	if err != nil {
+		if catalog.HasInactiveDescriptorError(err) || errors.Is(err, catalog.ErrDescriptorNotFound) {
+			// The table was dropped or is offline, so its changes are not
+			// replicated.
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	version := leased.Underlying().GetVersion()
+	leased.Release(ctx)
+	if t.desc == nil || t.desc.GetVersion() != version {
+		if err := s.updateTable(ctx, t, ts); err != nil {
+			return nil, false, err
+		}
+	}
+	// Only the primary index contains full rows. Rows are written to the
+	// primary index of a table which is being rebuilt by a schema change too,
+	// but only the current primary index is replicated.
+	if descpb.IndexID(indexID) != t.desc.GetPrimaryIndexID() {
+		return nil, false, nil
+	}
+	return t, true, nil
+}
+
+// updateTable updates the state of a replicated table to its version at the
+// given timestamp. A new Relation message is sent before the next change to
+// the table.
+func (s *logicalReplicationStream) updateTable(
+	ctx context.Context, t *replicatedTable, ts hlc.Timestamp,
+) error {
+	var desc catalog.TableDescriptor
+	var schemaName string
+	if err := s.execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) (err error) {
+		if err := txn.KV().SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		desc, err = txn.Descriptors().ByIDWithLeased(txn.KV()).WithoutNonPublic().Get().Table(ctx, t.id)
+		if err != nil {
+			return err
+		}
+		sc, err := txn.Descriptors().ByIDWithLeased(txn.KV()).Get().Schema(ctx, desc.GetParentSchemaID())
+		if err != nil {
+			return err
+		}
+		schemaName = sc.GetName()
+		return nil
+	}); err != nil {
+		return err
+	}
+	if desc.NumFamilies() > 1 {
+		return unimplemented.Newf("logical replication column families",
+			"logical replication of table %q with multiple column families is not supported", desc.GetName())
+	}
+
+	keyCols := desc.GetPrimaryIndex().CollectKeyColumnIDs()
+	var colIDs []descpb.ColumnID
+	rel := pgoutput.Relation{
+		ID:        oid.Oid(desc.GetID()),
+		Namespace: schemaName,
+		Name:      desc.GetName(),
+	}
+	for _, col := range desc.PublicColumns() {
+		isKey := keyCols.Contains(col.GetID())
+		if col.IsVirtual() || (col.IsHidden() && !isKey) {
+			continue
+		}
+		colIDs = append(colIDs, col.GetID())
+		rel.Columns = append(rel.Columns, pgoutput.Column{
+			Name:    col.GetName(),
+			TypeOID: col.GetType().Oid(),
+			TypeMod: col.GetType().TypeModifier(),
+			IsKey:   isKey,
+		})
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(
+		&spec, s.execCfg.Codec, desc, desc.GetPrimaryIndex(), colIDs,
+	); err != nil {
+		return err
+	}
+	var fetcher row.Fetcher
+	if err := fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &t.alloc,
+		Spec:              &spec,
+	}); err != nil {
+		return err
+	}
+	if t.fetcher != nil {
+		t.fetcher.Close(ctx)
+	}
+	t.desc = desc
+	t.relation = rel
+	t.relationSent = false
+	t.fetcher = &fetcher
+	return nil
+}
+
+// sendChange sends the Insert, Update or Delete message for a change to a row.
+func (s *logicalReplicationStream) sendChange(
+	ctx context.Context, txnLSN lsn.LSN, t *replicatedTable, ev *kvpb.RangeFeedValue,
+) error {
+	relID := t.relation.ID
+	if !ev.Value.IsPresent() {
+		if !ev.PrevValue.IsPresent() {
+			// The row did not exist before the deletion.
+			return nil
+		}
+		vals, err := s.decodeRow(ctx, t, ev.Key, ev.PrevValue, true /* keyOnly */)
+		if err != nil {
+			return err
+		}
+		return s.send(ctx, pgoutput.AppendDelete(s.header(txnLSN), relID, vals))
+	}
+	vals, err := s.decodeRow(ctx, t, ev.Key, ev.Value, false /* keyOnly */)
+	if err != nil {
+		return err
+	}
+	if ev.PrevValue.IsPresent() {
+		return s.send(ctx, pgoutput.AppendUpdate(s.header(txnLSN), relID, vals))
+	}
+	return s.send(ctx, pgoutput.AppendInsert(s.header(txnLSN), relID, vals))
+}
+
+// decodeRow decodes a row of the primary index of a replicated table and
+// returns the text representation of its values. If keyOnly is true, only the
+// values of the key columns are returned.
+func (s *logicalReplicationStream) decodeRow(
+	ctx context.Context, t *replicatedTable, key roachpb.Key, value roachpb.Value, keyOnly bool,
+) ([]pgoutput.Value, error) {
+	kvs := row.KVProvider{KVs: []roachpb.KeyValue{{Key: key, Value: value}}}
+	if err := t.fetcher.ConsumeKVProvider(ctx, &kvs); err != nil {
+		return nil, err
+	}
+	datums, err := t.fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if datums == nil {
+		return nil, errors.AssertionFailedf("failed to decode row of table %q", t.desc.GetName())
+	}
+	t.values = t.values[:0]
+	for i, d := range datums {
+		if d == tree.DNull || (keyOnly && !t.relation.Columns[i].IsKey) {
+			t.values = append(t.values, nil)
+			continue
+		}
+		s.fmtCtx.Reset()
+		s.fmtCtx.FormatNode(d)
+		t.values = append(t.values, append(pgoutput.Value{}, s.fmtCtx.Bytes()...))
+	}
+	return t.values, nil
+}
+
+// header resets the message buffer and appends the header of a XLogData
+// message carrying a change with the given LSN.
+func (s *logicalReplicationStream) header(txnLSN lsn.LSN) []byte {
+	return pgoutput.AppendXLogData(s.buf[:0], txnLSN, txnLSN, timeutil.Now())
+}
+
+// send sends a message to the client.
+func (s *logicalReplicationStream) send(ctx context.Context, msg []byte) error {
+	s.buf = msg
+	return s.conn.SendCopyData(ctx, msg)
+}
+
+// sendKeepalive sends a keepalive message with the LSN up to which all the
+// changes have been sent.
+func (s *logicalReplicationStream) sendKeepalive(ctx context.Context) error {
+	s.buf = pgoutput.AppendPrimaryKeepalive(s.buf[:0], s.walEnd, timeutil.Now(), false /* replyRequested */)
+	return s.conn.SendCopyData(ctx, s.buf)
+}
@@ -0,0 +1,405 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// materializedViewRefreshOverride is used by the statements which modify the
+// rows of materialized views while refreshing them.
+var materializedViewRefreshOverride = sessiondata.InternalExecutorOverride{
+	User:                           username.NodeUserName(),
+	AllowMaterializedViewMutations: true,
+}
+
+// materializedViewColumnNames returns the names of the columns of the view
+// which are computed by the view query.
+func materializedViewColumnNames(view catalog.TableDescriptor) []string {
+	cols := view.VisibleColumns()
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.GetName()
+	}
+	return names
+}
+
+// concurrentRefreshKey returns the key columns of the unique index used to
+// refresh the materialized view concurrently. Like in Postgres, the view must
+// have a unique index without a WHERE clause on its columns.
+func concurrentRefreshKey(view catalog.TableDescriptor) ([]catalog.Column, error) {
+	for _, idx := range view.PublicNonPrimaryIndexes() {
+		if !idx.IsUnique() || idx.IsPartial() || idx.GetType() != descpb.IndexDescriptor_FORWARD {
+			continue
+		}
+		key := make([]catalog.Column, 0, idx.NumKeyColumns())
+		for i := 0; i < idx.NumKeyColumns(); i++ {
+			col, err := catalog.MustFindColumnByID(view, idx.GetKeyColumnID(i))
+			if err != nil {
+				return nil, err
+			}
+			if col.IsHidden() || col.IsInaccessible() {
+				key = nil
+				break
+			}
+			key = append(key, col)
+		}
+		if key != nil {
+			return key, nil
+		}
+	}
+	return nil, errors.WithHint(
+		pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot refresh materialized view %q concurrently", view.GetName()),
+		"Create a unique index with no WHERE clause on one or more columns of the materialized view.")
+}
+
+// refreshMaterializedViewByDiff refreshes the view by deleting the rows which
+// are not in the result of the view query anymore and inserting the rows which
+// are not in the view yet. The rows of the view with a NULL value in one of the
+// nullableKey columns are always replaced, since they may be duplicated.
+func refreshMaterializedViewByDiff(
+	ctx context.Context, txn isql.Txn, view catalog.TableDescriptor, nullableKey []string,
+) error {
+	cols := materializedViewColumnNames(view)
+	newRows := fmt.Sprintf("(%s) AS n (%s)", view.GetViewQuery(), columnList(cols))
+
+	var deleteFilter strings.Builder
+	for _, col := range nullableKey {
+		fmt.Fprintf(&deleteFilter, "o.%s IS NULL OR ", tree.NameString(col))
+	}
+	fmt.Fprintf(&deleteFilter, "NOT EXISTS (SELECT 1 FROM %s WHERE %s)",
+		newRows, rowsMatchFilter("n", "o", cols))
+	if _, err := txn.ExecEx(
+		ctx, "refresh-materialized-view-delete", txn.KV(), materializedViewRefreshOverride,
+		fmt.Sprintf(`DELETE FROM [%d AS o] WHERE %s`, view.GetID(), deleteFilter.String()),
+	); err != nil {
+		return err
+	}
+	_, err := txn.ExecEx(
+		ctx, "refresh-materialized-view-insert", txn.KV(), materializedViewRefreshOverride,
+		fmt.Sprintf(`INSERT INTO [%d AS v] (%s) SELECT * FROM %s WHERE NOT EXISTS (SELECT 1 FROM [%d AS o] WHERE %s)`,
+			view.GetID(), columnList(cols), newRows, view.GetID(), rowsMatchFilter("n", "o", cols)),
+	)
+	return err
+}
+
+// columnList returns the comma-separated list of the given column names.
+func columnList(cols []string) string {
+	var buf strings.Builder
+	for i, col := range cols {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(col))
+	}
+	return buf.String()
+}
+
+// rowsMatchFilter returns a filter which matches the rows of the relations
+// with the aliases left and right which have the same values in all the given
+// columns, NULLs included.
+func rowsMatchFilter(left, right string, cols []string) string {
+	if len(cols) == 0 {
+		return "true"
+	}
+	var buf strings.Builder
+	for i, col := range cols {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		name := tree.NameString(col)
+		fmt.Fprintf(&buf, "%s.%s IS NOT DISTINCT FROM %s.%s", left, name, right, name)
+	}
+	return buf.String()
+}
+
+// incrementalRefreshSpec describes how a materialized view is refreshed
+// incrementally with the changes of the table it selects from.
+type incrementalRefreshSpec struct {
+	// tableID is the ID of the table the view selects from.
+	tableID descpb.ID
+
+	// keyColumns are the columns of the view which identify the rows of the
+	// view computed from a row of the table. It is empty if every row of the
+	// view may be computed from every row of the table, which is the case when
+	// the view query computes aggregates without GROUP BY.
+	keyColumns []string
+
+	// tableColumns are the columns of the table which the key columns of the
+	// view are computed from, in the same order.
+	tableColumns []string
+
+	// grouped is true if the view query groups the rows of the table by the
+	// table columns. Otherwise, the table columns are the primary key columns
+	// of the table.
+	grouped bool
+}
+
+// makeIncrementalRefreshSpec returns how the materialized view is refreshed
+// incrementally, or an error if it can't be. Only the views which select from
+// a single table, without joins or subqueries, and which filter and project
+// its rows or compute aggregates over them can be refreshed incrementally.
+func makeIncrementalRefreshSpec(
+	view catalog.TableDescriptor, table catalog.TableDescriptor,
+) (incrementalRefreshSpec, error) {
+	notEligible := func(reason string) error {
+		return errors.WithDetail(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"materialized view %q cannot be refreshed incrementally", view.GetName()),
+			reason)
+	}
+	if !table.IsTable() || table.IsVirtualTable() {
+		return incrementalRefreshSpec{}, notEligible("The view query must select from a table.")
+	}
+	primary := table.GetPrimaryIndex()
+	for i := 0; i < primary.NumKeyColumns(); i++ {
+		col, err := catalog.MustFindColumnByID(table, primary.GetKeyColumnID(i))
+		if err != nil {
+			return incrementalRefreshSpec{}, err
+		}
+		if col.GetType().Family() == types.CollatedStringFamily {
+			return incrementalRefreshSpec{}, notEligible(
+				"The primary key of the table cannot contain collated strings.")
+		}
+	}
+
+	stmt, err := parser.ParseOne(view.GetViewQuery())
+	if err != nil {
+		return incrementalRefreshSpec{}, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return incrementalRefreshSpec{}, errors.AssertionFailedf(
+			"unexpected view query %s", view.GetViewQuery())
+	}
+	for {
+		if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+			return incrementalRefreshSpec{}, notEligible(
+				"The view query cannot have WITH, ORDER BY, LIMIT or locking clauses.")
+		}
+		paren, ok := sel.Select.(*tree.ParenSelect)
+		if !ok {
+			break
+		}
+		sel = paren.Select
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return incrementalRefreshSpec{}, notEligible(
+			"The view query cannot use set operations or VALUES.")
+	}
+	if clause.Distinct || clause.DistinctOn != nil || clause.Window != nil {
+		return incrementalRefreshSpec{}, notEligible(
+			"The view query cannot use DISTINCT or window functions.")
+	}
+	if len(clause.From.Tables) != 1 {
+		return incrementalRefreshSpec{}, notEligible(
+			"The view query must select from a single table without joins.")
+	}
+	if ate, ok := clause.From.Tables[0].(*tree.AliasedTableExpr); !ok || ate.Ordinality || ate.Lateral {
+		return incrementalRefreshSpec{}, notEligible(
+			"The view query must select from a single table without joins.")
+	}
+
+	var hasSubquery, hasWindow, hasAggregate bool
+	visit := func(expr tree.Expr) {
+		if expr == nil {
+			return
+		}
+		_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+			switch t := expr.(type) {
+			case *tree.Subquery:
+				hasSubquery = true
+				return false, expr, nil
+			case *tree.FuncExpr:
+				if t.WindowDef != nil {
+					hasWindow = true
+				} else if isAggregateFuncExpr(t) {
+					hasAggregate = true
+				}
+			}
+			return true, expr, nil
+		})
+	}
+	for _, e := range clause.Exprs {
+		visit(e.Expr)
+	}
+	if clause.Where != nil {
+		visit(clause.Where.Expr)
+	}
+	for _, e := range clause.GroupBy {
+		visit(e)
+	}
+	if clause.Having != nil {
+		visit(clause.Having.Expr)
+	}
+	if hasSubquery {
+		return incrementalRefreshSpec{}, notEligible("The view query cannot contain subqueries.")
+	}
+	if hasWindow {
+		return incrementalRefreshSpec{}, notEligible(
+			"The view query cannot use DISTINCT or window functions.")
+	}
+
+	viewCols := materializedViewColumnNames(view)
+	if len(viewCols) != len(clause.Exprs) {
+		return incrementalRefreshSpec{}, notEligible("The view query cannot select all columns with *.")
+	}
+	columnRef := func(expr tree.Expr) (string, bool) {
+		if n, ok := expr.(*tree.UnresolvedName); ok && !n.Star {
+			return n.Parts[0], true
+		}
+		return "", false
+	}
+	findViewColumn := func(tableCol string) (string, bool) {
+		for i, e := range clause.Exprs {
+			if name, ok := columnRef(e.Expr); ok && name == tableCol {
+				return viewCols[i], true
+			}
+		}
+		return "", false
+	}
+
+	spec := incrementalRefreshSpec{tableID: table.GetID()}
+	switch {
+	case len(clause.GroupBy) > 0:
+		spec.grouped = true
+		for _, e := range clause.GroupBy {
+			tableCol, ok := columnRef(e)
+			if !ok {
+				return incrementalRefreshSpec{}, notEligible(
+					"The GROUP BY clause of the view query can only contain columns.")
+			}
+			viewCol, ok := findViewColumn(tableCol)
+			if !ok {
+				return incrementalRefreshSpec{}, notEligible(
+					"The columns of the GROUP BY clause must be selected by the view query.")
+			}
+			spec.keyColumns = append(spec.keyColumns, viewCol)
+			spec.tableColumns = append(spec.tableColumns, tableCol)
+		}
+	case hasAggregate || clause.Having != nil:
+		// The view contains a single row computed from all the rows of the
+		// table.
+		spec.grouped = true
+	default:
+		for i := 0; i < primary.NumKeyColumns(); i++ {
+			tableCol := primary.GetKeyColumnName(i)
+			viewCol, ok := findViewColumn(tableCol)
+			if !ok {
+				return incrementalRefreshSpec{}, notEligible(
+					"The primary key columns of the table must be selected by the view query.")
+			}
+			spec.keyColumns = append(spec.keyColumns, viewCol)
+			spec.tableColumns = append(spec.tableColumns, tableCol)
+		}
+	}
+	return spec, nil
+}
+
+// isAggregateFuncExpr returns whether the function is a builtin aggregate
+// function.
+func isAggregateFuncExpr(f *tree.FuncExpr) bool {
+	name, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+	if !ok {
+		return false
+	}
+	fn, err := name.ToRoutineName()
+	if err != nil {
+		return false
+	}
+	_, overloads := builtinsregistry.GetBuiltinProperties(fn.Object())
+	return len(overloads) > 0 && overloads[0].Class == tree.AggregateClass
+}
+
+// setMaterializedViewIncrementalRefresh starts or stops the job which
+// refreshes the materialized view incrementally.
+func (p *planner) setMaterializedViewIncrementalRefresh(
+	ctx context.Context, desc *tabledesc.Mutable, enable bool, jobDesc string,
+) error {
+	jobID := desc.IncrementalRefreshJobID
+	if enable == (jobID != catpb.InvalidJobID) {
+		return nil
+	}
+	if !enable {
+		desc.IncrementalRefreshJobID = catpb.InvalidJobID
+		err := p.ExecCfg().JobRegistry.UpdateJobWithTxn(ctx, jobID, p.InternalSQLTxn(),
+			func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+				if md.Status.Terminal() {
+					return nil
+				}
+				return ju.CancelRequested(ctx, md)
+			})
+		if jobs.HasJobNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !p.execCfg.Settings.Version.IsActive(ctx, clusterversion.V24_1_IncrementalMaterializedViews) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"incremental refresh of materialized views is not supported until the upgrade to version 24.1 is finalized")
+	}
+	if desc.IsRefreshViewRequired() {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"materialized view %q must be populated to be refreshed incrementally", desc.Name)
+	}
+	if len(desc.DependsOn) != 1 {
+		return errors.WithDetail(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"materialized view %q cannot be refreshed incrementally", desc.Name),
+			"The view query must select from a single table.")
+	}
+	table, err := p.Descriptors().ByIDWithLeased(p.txn).WithoutNonPublic().Get().Table(ctx, desc.DependsOn[0])
+	if err != nil {
+		return err
+	}
+	if _, err := makeIncrementalRefreshSpec(desc, table); err != nil {
+		return err
+	}
+
+	jobID = p.ExecCfg().JobRegistry.MakeJobID()
+	record := jobs.Record{
+		Description:   jobDesc,
+		Username:      p.User(),
+		DescriptorIDs: descpb.IDs{desc.ID},
+		Details:       jobspb.MaterializedViewRefreshDetails{ViewID: desc.ID},
+		Progress:      jobspb.MaterializedViewRefreshProgress{},
+	}
+	if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+		ctx, record, jobID, p.InternalSQLTxn(),
+	); err != nil {
+		return err
+	}
+	desc.IncrementalRefreshJobID = jobID
+	return nil
+}
@@ -0,0 +1,588 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// materializedViewRefreshMaxKeys is the maximum number of rows of a
+	// materialized view which are refreshed incrementally for a batch of
+	// changes of the table; the view is refreshed entirely when more rows are
+	// affected.
+	materializedViewRefreshMaxKeys = 10000
+
+	// materializedViewRefreshBatchSize is the number of keys or rows which are
+	// read or written by a single statement.
+	materializedViewRefreshBatchSize = 100
+
+	// materializedViewRefreshCheckpointInterval is the interval at which the
+	// job checkpoints its progress when the table does not change.
+	materializedViewRefreshCheckpointInterval = 30 * time.Second
+)
+
+// errMaterializedViewFullRefresh is returned when the changes of the table
+// cannot be applied incrementally to the materialized view, which must then be
+// refreshed entirely.
+var errMaterializedViewFullRefresh = errors.New("materialized view must be refreshed entirely")
+
+// materializedViewRefreshResumer implements the jobs.Resumer interface for the
+// jobs which refresh materialized views incrementally. The job runs a
+// rangefeed on the primary index of the table the view selects from, and
+// recomputes the rows of the view which are affected by the changes of the
+// table each time the frontier of the rangefeed advances. The high-water mark
+// of the job is the timestamp up to which the changes have been applied.
+type materializedViewRefreshResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*materializedViewRefreshResumer)(nil)
+
+// materializedViewRefreshTarget is the state of a materialized view refreshed
+// by the job and of the table it selects from.
+type materializedViewRefreshTarget struct {
+	view  catalog.TableDescriptor
+	table catalog.TableDescriptor
+	spec  incrementalRefreshSpec
+}
+
+// materializedViewRefreshBatch contains the keys of the rows of the table which
+// changed up to the frontier of the rangefeed.
+type materializedViewRefreshBatch struct {
+	keys     []roachpb.Key
+	frontier hlc.Timestamp
+	// fullRefresh is set when the table was changed without writing its rows.
+	fullRefresh bool
+}
+
+// Resume implements the jobs.Resumer interface.
+func (r *materializedViewRefreshResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	execCfg := execCtx.(JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.MaterializedViewRefreshDetails)
+
+	var highWater hlc.Timestamp
+	if hw := r.job.Progress().GetHighWater(); hw != nil {
+		highWater = *hw
+	}
+	// The job spends its life waiting for changes, which is safe to interrupt.
+	r.job.MarkIdle(true)
+	for {
+		err := r.run(ctx, execCfg, details.ViewID, highWater)
+		if !errors.Is(err, errMaterializedViewFullRefresh) {
+			return err
+		}
+		log.Infof(ctx, "refreshing materialized view %d entirely", details.ViewID)
+		highWater = hlc.Timestamp{}
+	}
+}
+
+// run refreshes the view incrementally from the high-water mark, or entirely
+// first if the high-water mark is empty. It returns nil once the view is
+// dropped or not refreshed by this job anymore.
+func (r *materializedViewRefreshResumer) run(
+	ctx context.Context, execCfg *ExecutorConfig, viewID descpb.ID, highWater hlc.Timestamp,
+) error {
+	target, ok, err := r.loadTarget(ctx, execCfg, viewID)
+	if err != nil || !ok {
+		return err
+	}
+	if highWater.IsEmpty() {
+		if highWater, err = r.refreshEntirely(ctx, execCfg, viewID); err != nil || highWater.IsEmpty() {
+			return err
+		}
+		if err := r.checkpoint(ctx, highWater); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make(chan materializedViewRefreshBatch)
+	rangefeedErr := make(chan error, 1)
+	primaryIndexID := target.table.GetPrimaryIndexID()
+	prefix := execCfg.Codec.IndexPrefix(uint32(target.table.GetID()), uint32(primaryIndexID))
+	// The callbacks of the rangefeed are invoked sequentially, so pending and
+	// fullRefresh do not need to be synchronized.
+	var pending []*kvpb.RangeFeedValue
+	var fullRefresh bool
+	rf, err := execCfg.RangeFeedFactory.RangeFeed(
+		ctx, fmt.Sprintf("materialized-view-refresh-%d", viewID),
+		[]roachpb.Span{{Key: prefix, EndKey: prefix.PrefixEnd()}}, highWater,
+		func(ctx context.Context, value *kvpb.RangeFeedValue) {
+			pending = append(pending, value)
+		},
+		rangefeed.WithOnFrontierAdvance(func(ctx context.Context, frontier hlc.Timestamp) {
+			b := materializedViewRefreshBatch{frontier: frontier, fullRefresh: fullRefresh}
+			var remaining []*kvpb.RangeFeedValue
+			for _, ev := range pending {
+				if ev.Value.Timestamp.LessEq(frontier) {
+					b.keys = append(b.keys, ev.Key)
+				} else {
+					remaining = append(remaining, ev)
+				}
+			}
+			pending = remaining
+			select {
+			case batches <- b:
+			case <-ctx.Done():
+			}
+		}),
+		// Range deletions and SSTable ingestion change the rows of the table
+		// without rangefeed values for each row, so the view is refreshed
+		// entirely after them.
+		rangefeed.WithOnDeleteRange(func(context.Context, *kvpb.RangeFeedDeleteRange) {
+			fullRefresh = true
+		}),
+		rangefeed.WithOnSSTable(func(context.Context, *kvpb.RangeFeedSSTable, roachpb.Span) {
+			fullRefresh = true
+		}),
+		rangefeed.WithOnInternalError(func(ctx context.Context, err error) {
+			select {
+			case rangefeedErr <- err:
+			default:
+			}
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	defer rf.Close()
+
+	lastCheckpoint := timeutil.Now()
+	for {
+		select {
+		case b := <-batches:
+			if b.fullRefresh {
+				return errMaterializedViewFullRefresh
+			}
+			if len(b.keys) == 0 && timeutil.Since(lastCheckpoint) < materializedViewRefreshCheckpointInterval {
+				continue
+			}
+			target, ok, err := r.loadTarget(ctx, execCfg, viewID)
+			if err != nil || !ok {
+				return err
+			}
+			if target.table.GetPrimaryIndexID() != primaryIndexID {
+				// The primary key of the table changed, so the rangefeed must be
+				// restarted on the new primary index.
+				return errMaterializedViewFullRefresh
+			}
+			if err := r.applyChanges(ctx, execCfg, target, highWater, b); err != nil {
+				return err
+			}
+			if err := r.checkpoint(ctx, b.frontier); err != nil {
+				return err
+			}
+			highWater = b.frontier
+			lastCheckpoint = timeutil.Now()
+		case err := <-rangefeedErr:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// isRefreshedBy returns whether the view is still refreshed by the job.
+func (r *materializedViewRefreshResumer) isRefreshedBy(view catalog.TableDescriptor) bool {
+	return !view.Dropped() && view.GetIncrementalRefreshJobID() == r.job.ID()
+}
+
+// loadTarget returns the current state of the view and of its table. It
+// returns false if the view is not refreshed by the job anymore.
+func (r *materializedViewRefreshResumer) loadTarget(
+	ctx context.Context, execCfg *ExecutorConfig, viewID descpb.ID,
+) (t materializedViewRefreshTarget, ok bool, _ error) {
+	err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		ok = false
+		view, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, viewID)
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				return nil
+			}
+			return err
+		}
+		if !r.isRefreshedBy(view) {
+			return nil
+		}
+		if view.Adding() {
+			return jobs.MarkAsRetryJobError(
+				errors.Newf("materialized view %q is still being created", view.GetName()))
+		}
+		deps := view.GetDependsOn()
+		if len(deps) != 1 {
+			return errors.AssertionFailedf(
+				"materialized view %q refreshed incrementally depends on %d relations", view.GetName(), len(deps))
+		}
+		table, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, deps[0])
+		if err != nil {
+			return err
+		}
+		spec, err := makeIncrementalRefreshSpec(view, table)
+		if err != nil {
+			return err
+		}
+		t = materializedViewRefreshTarget{view: view, table: table, spec: spec}
+		ok = true
+		return nil
+	})
+	return t, ok, err
+}
+
+// refreshEntirely refreshes the whole view, and returns the timestamp of the
+// refresh. It returns an empty timestamp if the view is not refreshed by the
+// job anymore.
+func (r *materializedViewRefreshResumer) refreshEntirely(
+	ctx context.Context, execCfg *ExecutorConfig, viewID descpb.ID,
+) (ts hlc.Timestamp, _ error) {
+	err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		ts = hlc.Timestamp{}
+		view, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, viewID)
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				return nil
+			}
+			return err
+		}
+		if !r.isRefreshedBy(view) {
+			return nil
+		}
+		if err := refreshMaterializedViewByDiff(ctx, txn, view, nil /* nullableKey */); err != nil {
+			return err
+		}
+		// The view reflects the table as of the commit timestamp of the
+		// transaction, from which the rangefeed starts.
+		ts, err = txn.KV().CommitTimestamp()
+		return err
+	})
+	return ts, err
+}
+
+// applyChanges recomputes the rows of the view which are affected by the
+// changes of the table in the batch, which happened after prev.
+func (r *materializedViewRefreshResumer) applyChanges(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	t materializedViewRefreshTarget,
+	prev hlc.Timestamp,
+	b materializedViewRefreshBatch,
+) error {
+	if len(b.keys) == 0 {
+		return nil
+	}
+	pks, err := decodeChangedPrimaryKeys(execCfg.Codec, t.table, b.keys)
+	if err != nil {
+		return err
+	}
+	if len(pks) > materializedViewRefreshMaxKeys {
+		return errMaterializedViewFullRefresh
+	}
+
+	// Find the keys of the rows of the view which are affected by the changes.
+	var viewKeys []tree.Datums
+	switch {
+	case !t.spec.grouped:
+		// The key of the view is the primary key of the table.
+		viewKeys = pks
+	case len(t.spec.keyColumns) > 0:
+		// The groups of the changed rows before and after the changes are
+		// affected.
+		pkCols := make([]string, t.table.GetPrimaryIndex().NumKeyColumns())
+		for i := range pkCols {
+			pkCols[i] = t.table.GetPrimaryIndex().GetKeyColumnName(i)
+		}
+		seen := make(map[string]struct{})
+		for _, ts := range []hlc.Timestamp{prev, b.frontier} {
+			if err := forEachKeyBatch(pkCols, pks, func(keys []tree.Datums) error {
+				filter, args := materializedViewKeyFilter("t", pkCols, keys)
+				rows, err := queryMaterializedViewRowsAsOf(ctx, execCfg, ts, "materialized-view-refresh-groups",
+					fmt.Sprintf(`SELECT DISTINCT %s FROM [%d AS t] WHERE %s`,
+						columnList(t.spec.tableColumns), t.table.GetID(), filter), args...)
+				if err != nil {
+					return err
+				}
+				for _, row := range rows {
+					k := tree.AsString(&row)
+					if _, ok := seen[k]; !ok {
+						seen[k] = struct{}{}
+						viewKeys = append(viewKeys, row)
+					}
+				}
+				return nil
+			}); err != nil {
+				if errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil)) {
+					return errMaterializedViewFullRefresh
+				}
+				return err
+			}
+		}
+		if len(viewKeys) > materializedViewRefreshMaxKeys {
+			return errMaterializedViewFullRefresh
+		}
+	}
+
+	// Compute the affected rows of the view as of the frontier.
+	cols := materializedViewColumnNames(t.view)
+	var newRows []tree.Datums
+	if err := forEachKeyBatch(t.spec.keyColumns, viewKeys, func(keys []tree.Datums) error {
+		filter, args := materializedViewKeyFilter("n", t.spec.keyColumns, keys)
+		rows, err := queryMaterializedViewRowsAsOf(ctx, execCfg, b.frontier, "materialized-view-refresh-rows",
+			fmt.Sprintf(`SELECT * FROM (%s) AS n (%s) WHERE %s`,
+				t.view.GetViewQuery(), columnList(cols), filter), args...)
+		newRows = append(newRows, rows...)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Replace the affected rows of the view.
+	return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		if err := forEachKeyBatch(t.spec.keyColumns, viewKeys, func(keys []tree.Datums) error {
+			filter, args := materializedViewKeyFilter("o", t.spec.keyColumns, keys)
+			_, err := txn.ExecEx(ctx, "materialized-view-refresh-delete", txn.KV(),
+				materializedViewRefreshOverride,
+				fmt.Sprintf(`DELETE FROM [%d AS o] WHERE %s`, t.view.GetID(), filter), args...)
+			return err
+		}); err != nil {
+			return err
+		}
+		for len(newRows) > 0 {
+			n := len(newRows)
+			if n > materializedViewRefreshBatchSize {
+				n = materializedViewRefreshBatchSize
+			}
+			var values strings.Builder
+			args := make([]interface{}, 0, n*len(cols))
+			for i, row := range newRows[:n] {
+				if i > 0 {
+					values.WriteString(", ")
+				}
+				values.WriteByte('(')
+				for j, d := range row {
+					if j > 0 {
+						values.WriteString(", ")
+					}
+					args = append(args, d)
+					fmt.Fprintf(&values, "$%d", len(args))
+				}
+				values.WriteByte(')')
+			}
+			if _, err := txn.ExecEx(ctx, "materialized-view-refresh-insert", txn.KV(),
+				materializedViewRefreshOverride,
+				fmt.Sprintf(`INSERT INTO [%d AS v] (%s) VALUES %s`,
+					t.view.GetID(), columnList(cols), values.String()), args...,
+			); err != nil {
+				return err
+			}
+			newRows = newRows[n:]
+		}
+		return nil
+	})
+}
+
+// decodeChangedPrimaryKeys returns the distinct primary keys of the rows of the
+// table with the given keys.
+func decodeChangedPrimaryKeys(
+	codec keys.SQLCodec, table catalog.TableDescriptor, changed []roachpb.Key,
+) ([]tree.Datums, error) {
+	primary := table.GetPrimaryIndex()
+	typs := make([]*types.T, primary.NumKeyColumns())
+	for i := range typs {
+		col, err := catalog.MustFindColumnByID(table, primary.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		typs[i] = col.GetType()
+	}
+	dirs := primary.IndexDesc().KeyColumnDirections
+	seen := make(map[string]struct{})
+	var alloc tree.DatumAlloc
+	var pks []tree.Datums
+	for _, key := range changed {
+		// All the column families of a row share the prefix of the row.
+		n, err := keys.GetRowPrefixLength(key)
+		if err != nil {
+			return nil, err
+		}
+		rowKey := key[:n]
+		if _, ok := seen[string(rowKey)]; ok {
+			continue
+		}
+		seen[string(rowKey)] = struct{}{}
+		vals := make([]rowenc.EncDatum, len(typs))
+		if _, err := rowenc.DecodeIndexKey(codec, vals, dirs, rowKey); err != nil {
+			return nil, err
+		}
+		pk := make(tree.Datums, len(typs))
+		for i := range vals {
+			if err := vals[i].EnsureDecoded(typs[i], &alloc); err != nil {
+				return nil, err
+			}
+			pk[i] = vals[i].Datum
+		}
+		pks = append(pks, pk)
+	}
+	return pks, nil
+}
+
+// forEachKeyBatch calls fn with batches of the given keys of the given columns.
+// If there are no key columns, fn is called once with no keys, since the
+// filter of the keys then matches all rows.
+func forEachKeyBatch(cols []string, keys []tree.Datums, fn func(keys []tree.Datums) error) error {
+	if len(cols) == 0 {
+		return fn(nil)
+	}
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > materializedViewRefreshBatchSize {
+			n = materializedViewRefreshBatchSize
+		}
+		if err := fn(keys[:n]); err != nil {
+			return err
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
+
+// materializedViewKeyFilter returns a filter which matches the rows of the
+// relation with the given alias which have one of the given keys, and the
+// arguments of its placeholders.
+func materializedViewKeyFilter(
+	alias string, cols []string, keys []tree.Datums,
+) (string, []interface{}) {
+	if len(cols) == 0 {
+		return "true", nil
+	}
+	var buf strings.Builder
+	var args []interface{}
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteString(" OR ")
+		}
+		buf.WriteByte('(')
+		for j, col := range cols {
+			if j > 0 {
+				buf.WriteString(" AND ")
+			}
+			if key[j] == tree.DNull {
+				fmt.Fprintf(&buf, "%s.%s IS NULL", alias, tree.NameString(col))
+				continue
+			}
+			args = append(args, key[j])
+			fmt.Fprintf(&buf, "%s.%s = $%d", alias, tree.NameString(col), len(args))
+		}
+		buf.WriteByte(')')
+	}
+	return buf.String(), args
+}
+
+// queryMaterializedViewRowsAsOf runs the query as of the given timestamp.
+func queryMaterializedViewRowsAsOf(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	ts hlc.Timestamp,
+	opName string,
+	query string,
+	args ...interface{},
+) (rows []tree.Datums, _ error) {
+	err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		var err error
+		rows, err = txn.QueryBufferedEx(ctx, opName, txn.KV(),
+			sessiondata.NodeUserSessionDataOverride, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// checkpoint records the timestamp up to which the changes of the table have
+// been applied to the view as the high-water mark of the job.
+func (r *materializedViewRefreshResumer) checkpoint(ctx context.Context, ts hlc.Timestamp) error {
+	return r.job.NoTxn().Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		if err := md.CheckRunningOrReverting(); err != nil {
+			return err
+		}
+		md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &ts}
+		md.Progress.RunningStatus = fmt.Sprintf("refreshed until %s",
+			timeutil.Unix(0, ts.WallTime).Format(time.RFC3339))
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface. The view stops being
+// refreshed incrementally if the job still refreshes it.
+func (r *materializedViewRefreshResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, _ error,
+) error {
+	execCfg := execCtx.(JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.MaterializedViewRefreshDetails)
+	return execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		view, err := txn.Descriptors().MutableByID(txn.KV()).Table(ctx, details.ViewID)
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) || catalog.HasInactiveDescriptorError(err) {
+				return nil
+			}
+			return err
+		}
+		if !r.isRefreshedBy(view) {
+			return nil
+		}
+		view.IncrementalRefreshJobID = catpb.InvalidJobID
+		return txn.Descriptors().WriteDesc(ctx, false /* kvTrace */, view, txn.KV())
+	})
+}
+
+// CollectProfile implements the jobs.Resumer interface.
+func (r *materializedViewRefreshResumer) CollectProfile(context.Context, interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeMaterializedViewRefresh,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &materializedViewRefreshResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
@@ -0,0 +1,561 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Notifications sent with NOTIFY or pg_notify are written to the
+// system.notifications table by the sending transaction, so they become
+// visible, and are delivered, only if and when it commits. Every node runs a
+// rangefeed over the table, which is started when a session on the node first
+// listens on a channel, and dispatches the rows it receives to the sessions of
+// the node listening on their channel. Like in Postgres, channels are scoped to
+// the current database.
+//
+// The notifications dispatched to a session are queued until the session is
+// idle: the registry pushes a DeliverNotifications command into the session's
+// statement buffer, which the connExecutor executes by sending the queued
+// notifications to the client if it is not in a transaction. Otherwise, they
+// are sent before the ReadyForQuery message which ends the transaction.
+//
+// Every node periodically deletes the notifications which are older than
+// sql.notifications.retention.
+
+var notificationRetention = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.notifications.retention",
+	"the amount of time for which notifications sent with NOTIFY are kept in "+
+		"system.notifications, which bounds how long a node's rangefeed over the "+
+		"table can be interrupted without losing notifications",
+	10*time.Minute,
+	settings.PositiveDuration,
+)
+
+// notificationCleanupInterval is the interval at which each node deletes the
+// expired notifications.
+const notificationCleanupInterval = time.Minute
+
+const (
+	// maxNotificationChannelLength is the maximum length of a channel name,
+	// which matches the maximum length of an identifier in Postgres.
+	maxNotificationChannelLength = 63
+	// maxNotificationPayloadLength is the maximum length of a payload, which
+	// matches the limit of Postgres.
+	maxNotificationPayloadLength = 7999
+	// maxPendingNotifications bounds the number of notifications queued for a
+	// session. Further notifications are dropped until the queue is drained.
+	maxPendingNotifications = 10000
+)
+
+// Notification is a notification sent with NOTIFY or pg_notify, as it is
+// delivered to the sessions listening on its channel.
+type Notification struct {
+	// PID is the backend PID of the session which sent the notification.
+	PID     uint32
+	Channel string
+	Payload string
+}
+
+// notificationChannel identifies a channel. Channels with the same name in
+// different databases are distinct.
+type notificationChannel struct {
+	dbID descpb.ID
+	name string
+}
+
+// NotificationRegistry dispatches the notifications committed to
+// system.notifications to the sessions of the node which listen on their
+// channel.
+type NotificationRegistry struct {
+	execCfg *ExecutorConfig
+	stopper *stop.Stopper
+
+	mu struct {
+		syncutil.Mutex
+		// feed is the rangefeed over system.notifications. It is started when
+		// the first listener is registered, and is not stopped afterwards.
+		feed      *rangefeed.RangeFeed
+		listeners map[*notificationListener]struct{}
+		// frontier is the resolved timestamp of feed. The rangefeed only
+		// replays values at or below it, which have already been dispatched.
+		frontier hlc.Timestamp
+		// dispatched contains the keys of the rows above the frontier which have
+		// been dispatched, so that they are not dispatched again if the rangefeed
+		// replays them.
+		dispatched map[string]hlc.Timestamp
+	}
+}
+
+// NewNotificationRegistry creates a NotificationRegistry. Start must be called
+// before any listeners are registered.
+func NewNotificationRegistry(execCfg *ExecutorConfig) *NotificationRegistry {
+	r := &NotificationRegistry{execCfg: execCfg}
+	r.mu.listeners = make(map[*notificationListener]struct{})
+	r.mu.dispatched = make(map[string]hlc.Timestamp)
+	return r
+}
+
+// Start starts the task which deletes the expired notifications.
+func (r *NotificationRegistry) Start(ctx context.Context, stopper *stop.Stopper) {
+	r.stopper = stopper
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, "notifications-cleanup", r.cleanupLoop)
+}
+
+func (r *NotificationRegistry) cleanupLoop(ctx context.Context) {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		timer.Reset(notificationCleanupInterval)
+		select {
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		if !r.execCfg.Settings.Version.IsActive(ctx, clusterversion.V24_1_NotificationsTable) {
+			continue
+		}
+		cutoff := timeutil.Now().Add(-notificationRetention.Get(&r.execCfg.Settings.SV))
+		if err := r.deleteExpired(ctx, cutoff); err != nil && ctx.Err() == nil {
+			log.Warningf(ctx, "error deleting expired notifications: %v", err)
+		}
+	}
+}
+
+// deleteExpired deletes the notifications created before the cutoff.
+func (r *NotificationRegistry) deleteExpired(ctx context.Context, cutoff time.Time) error {
+	return r.execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := txn.ExecEx(
+			ctx, "delete-expired-notifications", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.notifications WHERE created < $1`, cutoff,
+		)
+		return err
+	})
+}
+
+// register registers a listener, starting the rangefeed over
+// system.notifications if needed.
+func (r *NotificationRegistry) register(ctx context.Context, l *notificationListener) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.feed == nil {
+		if err := r.startRangeFeedLocked(ctx); err != nil {
+			return err
+		}
+	}
+	r.mu.listeners[l] = struct{}{}
+	return nil
+}
+
+// unregister unregisters a listener.
+func (r *NotificationRegistry) unregister(l *notificationListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.listeners, l)
+}
+
+func (r *NotificationRegistry) startRangeFeedLocked(ctx context.Context) error {
+	if r.stopper == nil || r.execCfg.RangeFeedFactory == nil {
+		return pgerror.New(pgcode.FeatureNotSupported, "notifications are not available on this server")
+	}
+	id, err := r.execCfg.SystemTableIDResolver.LookupSystemTableID(ctx, systemschema.NotificationsTable.GetName())
+	if err != nil {
+		return err
+	}
+	prefix := r.execCfg.Codec.TablePrefix(uint32(id))
+	decoder := valueside.MakeDecoder(systemschema.NotificationsTable.PublicColumns())
+	var alloc tree.DatumAlloc
+	// The rangefeed outlives the session which started it, so it does not use
+	// its context.
+	feedCtx := r.execCfg.AmbientCtx.AnnotateCtx(context.Background())
+	// The callbacks of the rangefeed are invoked sequentially, so the decoder
+	// and alloc do not need to be synchronized.
+	r.mu.feed, err = r.execCfg.RangeFeedFactory.RangeFeed(
+		feedCtx, "notifications", []roachpb.Span{{Key: prefix, EndKey: prefix.PrefixEnd()}},
+		r.execCfg.Clock.Now(),
+		func(ctx context.Context, value *kvpb.RangeFeedValue) {
+			// Values which are not present are the deletions of expired
+			// notifications.
+			if !value.Value.IsPresent() {
+				return
+			}
+			ch, n, err := decodeNotification(&decoder, &alloc, value)
+			if err != nil {
+				log.Warningf(ctx, "error decoding notification: %v", err)
+				return
+			}
+			r.dispatch(value.Key, value.Value.Timestamp, ch, n)
+		},
+		rangefeed.WithSystemTablePriority(),
+		rangefeed.WithOnFrontierAdvance(func(ctx context.Context, frontier hlc.Timestamp) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.mu.frontier = frontier
+			for k, ts := range r.mu.dispatched {
+				if ts.LessEq(frontier) {
+					delete(r.mu.dispatched, k)
+				}
+			}
+		}),
+	)
+	return err
+}
+
+// decodeNotification decodes a row of system.notifications.
+func decodeNotification(
+	decoder *valueside.Decoder, alloc *tree.DatumAlloc, value *kvpb.RangeFeedValue,
+) (notificationChannel, Notification, error) {
+	bytes, err := value.Value.GetTuple()
+	if err != nil {
+		return notificationChannel{}, Notification{}, err
+	}
+	datums, err := decoder.Decode(alloc, bytes)
+	if err != nil {
+		return notificationChannel{}, Notification{}, err
+	}
+	if datums[2] == tree.DNull || datums[3] == tree.DNull {
+		return notificationChannel{}, Notification{}, errors.AssertionFailedf(
+			"notification without a database or channel")
+	}
+	ch := notificationChannel{
+		dbID: descpb.ID(tree.MustBeDInt(datums[2])),
+		name: string(tree.MustBeDString(datums[3])),
+	}
+	n := Notification{Channel: ch.name}
+	if datums[4] != tree.DNull {
+		n.Payload = string(tree.MustBeDString(datums[4]))
+	}
+	if datums[5] != tree.DNull {
+		n.PID = uint32(tree.MustBeDInt(datums[5]))
+	}
+	return ch, n, nil
+}
+
+// dispatch queues a notification for the listeners of its channel, unless it
+// has already been dispatched.
+func (r *NotificationRegistry) dispatch(
+	key roachpb.Key, ts hlc.Timestamp, ch notificationChannel, n Notification,
+) {
+	var toWake []*notificationListener
+	func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if ts.LessEq(r.mu.frontier) {
+			return
+		}
+		if _, ok := r.mu.dispatched[string(key)]; ok {
+			return
+		}
+		r.mu.dispatched[string(key)] = ts
+		for l := range r.mu.listeners {
+			if l.enqueue(ch, n) {
+				toWake = append(toWake, l)
+			}
+		}
+	}()
+	for _, l := range toWake {
+		l.wake()
+	}
+}
+
+// notificationListener holds the channels a session listens on and the
+// notifications dispatched to it which have not been delivered yet.
+type notificationListener struct {
+	// wake is called when notifications are queued for a listener which has
+	// not been woken since its queue was last drained.
+	wake func()
+
+	mu struct {
+		syncutil.Mutex
+		channels map[notificationChannel]struct{}
+		pending  []Notification
+		woken    bool
+	}
+}
+
+var droppedNotificationLogEvery = log.Every(time.Minute)
+
+// enqueue queues the notification if the listener listens on its channel. It
+// returns true if the listener needs to be woken.
+func (l *notificationListener) enqueue(ch notificationChannel, n Notification) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.mu.channels[ch]; !ok {
+		return false
+	}
+	if len(l.mu.pending) >= maxPendingNotifications {
+		if droppedNotificationLogEvery.ShouldLog() {
+			log.Warningf(context.Background(),
+				"dropping notifications for a session which has %d pending notifications", len(l.mu.pending))
+		}
+		return false
+	}
+	l.mu.pending = append(l.mu.pending, n)
+	if l.mu.woken {
+		return false
+	}
+	l.mu.woken = true
+	return true
+}
+
+// drain returns and clears the pending notifications.
+func (l *notificationListener) drain() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pending := l.mu.pending
+	l.mu.pending = nil
+	l.mu.woken = false
+	return pending
+}
+
+func (l *notificationListener) listen(ch notificationChannel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.channels[ch] = struct{}{}
+}
+
+func (l *notificationListener) unlisten(ch notificationChannel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.mu.channels, ch)
+}
+
+func (l *notificationListener) unlistenAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.channels = make(map[notificationChannel]struct{})
+}
+
+// notificationState is the state of a session related to LISTEN.
+type notificationState struct {
+	registry *NotificationRegistry
+	stmtBuf  *StmtBuf
+	// listener is created when the session first runs LISTEN.
+	listener *notificationListener
+}
+
+// getOrCreateListener returns the listener of the session, creating and
+// registering it if needed.
+func (s *notificationState) getOrCreateListener(
+	ctx context.Context,
+) (*notificationListener, error) {
+	if s.listener != nil {
+		return s.listener, nil
+	}
+	if s.registry == nil || s.stmtBuf == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "LISTEN is not supported in this context")
+	}
+	l := &notificationListener{
+		wake: func() {
+			// The error is returned if the session has been closed, in which case
+			// there is nobody left to deliver the notifications to.
+			_ = s.stmtBuf.Push(context.Background(), DeliverNotifications{})
+		},
+	}
+	l.mu.channels = make(map[notificationChannel]struct{})
+	if err := s.registry.register(ctx, l); err != nil {
+		return nil, err
+	}
+	s.listener = l
+	return l, nil
+}
+
+// deliver buffers the pending notifications of the session into the given
+// result.
+func (s *notificationState) deliver(res interface{ BufferNotification(Notification) }) {
+	if s.listener == nil {
+		return
+	}
+	for _, n := range s.listener.drain() {
+		res.BufferNotification(n)
+	}
+}
+
+// close unregisters the listener of the session.
+func (s *notificationState) close() {
+	if s.listener != nil {
+		s.registry.unregister(s.listener)
+		s.listener = nil
+	}
+}
+
+// checkNotificationsSupported returns an error if the cluster version does
+// not support notifications yet.
+func checkNotificationsSupported(ctx context.Context, p *planner, stmt string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_NotificationsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported until upgrade to version %s is finalized",
+			stmt, clusterversion.V24_1_NotificationsTable.String())
+	}
+	return nil
+}
+
+// notificationChannel returns the channel with the given name in the current
+// database.
+func (p *planner) notificationChannel(
+	ctx context.Context, name string,
+) (notificationChannel, error) {
+	if p.CurrentDatabase() == "" {
+		return notificationChannel{}, pgerror.New(pgcode.InvalidCatalogName,
+			"cannot use notifications without a current database")
+	}
+	dbDesc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return notificationChannel{}, err
+	}
+	return notificationChannel{dbID: dbDesc.GetID(), name: name}, nil
+}
+
+type listenNode struct {
+	n *tree.Listen
+}
+
+// Listen registers the session as a listener on a channel when the current
+// transaction commits.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	if err := checkNotificationsSupported(ctx, p, "LISTEN"); err != nil {
+		return nil, err
+	}
+	if p.notifications == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "LISTEN is not supported in this context")
+	}
+	return &listenNode{n: n}, nil
+}
+
+func (n *listenNode) startExec(params runParams) error {
+	p := params.p
+	ch, err := p.notificationChannel(params.ctx, string(n.n.ChannelName))
+	if err != nil {
+		return err
+	}
+	l, err := p.notifications.getOrCreateListener(params.ctx)
+	if err != nil {
+		return err
+	}
+	p.txn.AddCommitTrigger(func(context.Context) { l.listen(ch) })
+	return nil
+}
+
+func (n *listenNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *listenNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *listenNode) Close(ctx context.Context)           {}
+
+type unlistenNode struct {
+	n *tree.Unlisten
+}
+
+// Unlisten unregisters the session as a listener on one or all channels when
+// the current transaction commits.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	if p.notifications == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "UNLISTEN is not supported in this context")
+	}
+	return &unlistenNode{n: n}, nil
+}
+
+func (n *unlistenNode) startExec(params runParams) error {
+	p := params.p
+	// A session which never listened has nothing to unlisten.
+	l := p.notifications.listener
+	if l == nil {
+		return nil
+	}
+	if n.n.Star {
+		p.txn.AddCommitTrigger(func(context.Context) { l.unlistenAll() })
+		return nil
+	}
+	ch, err := p.notificationChannel(params.ctx, n.n.ChannelName.Object())
+	if err != nil {
+		return err
+	}
+	p.txn.AddCommitTrigger(func(context.Context) { l.unlisten(ch) })
+	return nil
+}
+
+func (n *unlistenNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *unlistenNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *unlistenNode) Close(ctx context.Context)           {}
+
+type notifyNode struct {
+	n *tree.Notify
+}
+
+// Notify sends a notification when the current transaction commits.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	return &notifyNode{n: n}, nil
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	return params.p.SendNotification(params.ctx, string(n.n.ChannelName), n.n.Payload)
+}
+
+func (n *notifyNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *notifyNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *notifyNode) Close(ctx context.Context)           {}
+
+var _ eval.Notifier = &planner{}
+
+// SendNotification is part of the eval.Notifier interface.
+func (p *planner) SendNotification(ctx context.Context, channel, payload string) error {
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) > maxNotificationChannelLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	if len(payload) > maxNotificationPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	if p.EvalContext().TxnReadOnly {
+		return pgerror.New(pgcode.ReadOnlySQLTransaction,
+			"cannot execute NOTIFY in a read-only transaction")
+	}
+	if err := checkNotificationsSupported(ctx, p, "NOTIFY"); err != nil {
+		return err
+	}
+	ch, err := p.notificationChannel(ctx, channel)
+	if err != nil {
+		return err
+	}
+	_, err = p.InternalSQLTxn().ExecEx(
+		ctx, "notify", p.txn, sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.notifications (database_id, channel, payload, sender_pid) VALUES ($1, $2, $3, $4)`,
+		int64(ch.dbID), channel, payload, int64(p.extendedEvalCtx.QueryCancelKey.GetPGBackendPID()),
+	)
+	return err
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListenNotify checks that notifications are delivered to the sessions
+// listening on their channel on other nodes, and only once the transaction
+// which sent them commits.
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 2 /* nodes */, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	connect := func(serverIdx int) *pgx.Conn {
+		pgURL, cleanup := sqlutils.PGUrl(
+			t, tc.Server(serverIdx).AdvSQLAddr(), t.Name(), url.User(username.RootUser),
+		)
+		defer cleanup()
+		conn, err := pgx.Connect(ctx, pgURL.String())
+		require.NoError(t, err)
+		return conn
+	}
+	listener := connect(0)
+	defer func() { _ = listener.Close(ctx) }()
+	notifier := connect(1)
+	defer func() { _ = notifier.Close(ctx) }()
+
+	var notifierPID uint32
+	require.NoError(t, notifier.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&notifierPID))
+
+	exec := func(conn *pgx.Conn, stmts ...string) {
+		for _, stmt := range stmts {
+			_, err := conn.Exec(ctx, stmt)
+			require.NoError(t, err)
+		}
+	}
+	// waitForPayloads waits for notifications on the given channel with the
+	// given payloads, in any order.
+	waitForPayloads := func(channel string, expected ...string) {
+		var payloads []string
+		for range expected {
+			waitCtx, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+			n, err := listener.WaitForNotification(waitCtx)
+			cancel()
+			require.NoError(t, err)
+			require.Equal(t, channel, n.Channel)
+			require.Equal(t, notifierPID, n.PID)
+			payloads = append(payloads, n.Payload)
+		}
+		require.ElementsMatch(t, expected, payloads)
+	}
+
+	exec(listener, "LISTEN foo")
+
+	// Notifications sent by a transaction which rolls back are not delivered,
+	// and neither are notifications on other channels.
+	exec(notifier,
+		"BEGIN", "NOTIFY foo, 'rolled back'", "ROLLBACK",
+		"NOTIFY bar, 'other channel'",
+		"BEGIN", "NOTIFY foo, 'a'", "SELECT pg_notify('foo', 'b')", "COMMIT",
+	)
+	waitForPayloads("foo", "a", "b")
+
+	// Notifications on a channel are no longer delivered after UNLISTEN.
+	exec(listener, "UNLISTEN foo", "LISTEN bar")
+	exec(notifier, "NOTIFY foo, 'unlistened'", "NOTIFY bar, 'c'")
+	waitForPayloads("bar", "c")
+
+	// Channels are scoped to the current database.
+	exec(notifier, "CREATE DATABASE other", "SET database = other", "NOTIFY bar, 'other database'")
+	exec(notifier, "SET database = defaultdb", "NOTIFY bar")
+	waitForPayloads("bar", "")
+}
@@ -36,6 +36,42 @@ const (
 	T__box2d     = oid.Oid(90005)
 )
 
+// OIDs in this block are multirange types, which were added in postgres 14
+// and are not present in `github.com/lib/pq/oid`. They match the OIDs used by
+// postgres.
+const (
+	T_int4multirange = oid.Oid(4451)
+	T_nummultirange  = oid.Oid(4532)
+	T_tsmultirange   = oid.Oid(4533)
+	T_tstzmultirange = oid.Oid(4534)
+	T_datemultirange = oid.Oid(4535)
+	T_int8multirange = oid.Oid(4536)
+	T_anymultirange  = oid.Oid(4537)
+
+	T__int4multirange = oid.Oid(6150)
+	T__nummultirange  = oid.Oid(6151)
+	T__tsmultirange   = oid.Oid(6152)
+	T__tstzmultirange = oid.Oid(6153)
+	T__datemultirange = oid.Oid(6155)
+	T__int8multirange = oid.Oid(6157)
+)
+
+// OIDs in this block are the jsonpath types, which were added in postgres 12
+// and are not present in `github.com/lib/pq/oid`. They match the OIDs used by
+// postgres.
+const (
+	T_jsonpath  = oid.Oid(4072)
+	T__jsonpath = oid.Oid(4073)
+)
+
+// OIDs in this block are the anycompatible polymorphic pseudo-types, which were
+// added in postgres 13 and are not present in `github.com/lib/pq/oid`. They
+// match the OIDs used by postgres.
+const (
+	T_anycompatible      = oid.Oid(5077)
+	T_anycompatiblearray = oid.Oid(5078)
+)
+
 // ExtensionTypeName returns a mapping from extension oids
 // to their type name.
 var ExtensionTypeName = map[oid.Oid]string{
@@ -45,6 +81,27 @@ var ExtensionTypeName = map[oid.Oid]string{
 	T__geography: "_GEOGRAPHY",
 	T_box2d:      "BOX2D",
 	T__box2d:     "_BOX2D",
+
+	T_int4multirange: "INT4MULTIRANGE",
+	T_nummultirange:  "NUMMULTIRANGE",
+	T_tsmultirange:   "TSMULTIRANGE",
+	T_tstzmultirange: "TSTZMULTIRANGE",
+	T_datemultirange: "DATEMULTIRANGE",
+	T_int8multirange: "INT8MULTIRANGE",
+	T_anymultirange:  "ANYMULTIRANGE",
+
+	T__int4multirange: "_INT4MULTIRANGE",
+	T__nummultirange:  "_NUMMULTIRANGE",
+	T__tsmultirange:   "_TSMULTIRANGE",
+	T__tstzmultirange: "_TSTZMULTIRANGE",
+	T__datemultirange: "_DATEMULTIRANGE",
+	T__int8multirange: "_INT8MULTIRANGE",
+
+	T_jsonpath:  "JSONPATH",
+	T__jsonpath: "_JSONPATH",
+
+	T_anycompatible:      "ANYCOMPATIBLE",
+	T_anycompatiblearray: "ANYCOMPATIBLEARRAY",
 }
 
 // TypeName checks the name for a given type by first looking up oid.TypeName
@@ -15,6 +15,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
@@ -139,6 +140,13 @@ type Table interface {
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
 
+	// TriggerCount returns the number of triggers defined on this table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger defined on this table, where
+	// i < TriggerCount.
+	Trigger(i int) Trigger
+
 	// Zone returns a table's zone.
 	Zone() Zone
 
@@ -328,6 +336,11 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checks of the foreign key can be
+	// deferred until the end of the transaction. Only the checks which do not
+	// involve a cascade are deferred.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -375,6 +388,44 @@ type UniqueConstraint interface {
 	// satisfied when building functional dependencies for the table. This enables
 	// additional optimizations, such as omission of uniqueness checks.
 	UniquenessGuaranteedByAnotherIndex() bool
+
+	// ExclusionElementCount returns the number of elements of the constraint if
+	// it is an exclusion constraint, or 0 if it is a unique constraint. Exclusion
+	// constraints are enforced like unique constraints without an index, but
+	// they do not make their columns a key of the table.
+	ExclusionElementCount() int
+
+	// ExclusionElement returns the ith element of an exclusion constraint.
+	ExclusionElement(i int) ExclusionElement
+
+	// Deferrability returns whether the uniqueness checks of the constraint can
+	// be deferred until the end of the transaction. It is only deferrable if
+	// WithoutIndex() returns true.
+	Deferrability() tree.ConstraintDeferrability
+}
+
+// ExclusionElement is an element of an exclusion constraint. Two rows conflict
+// if all the elements of the constraint compare true between them.
+type ExclusionElement struct {
+	// Operator compares the element between two rows. It is one of EQ, NE or
+	// Overlaps.
+	Operator treecmp.ComparisonOperatorSymbol
+
+	// Column is the index, among the columns of the constraint, of the column of
+	// the element, or of the lower bound column of a range element.
+	Column int
+
+	// UpperColumn is the index, among the columns of the constraint, of the
+	// upper bound column of a range element.
+	UpperColumn int
+
+	// IsRange is true if the element compares ranges built out of the Column and
+	// UpperColumn bounds. NULL bounds are unbounded, and ranges whose lower bound
+	// is not before their upper bound are empty.
+	IsRange bool
+
+	// InclusiveBounds is true if both bounds of the ranges are inclusive.
+	InclusiveBounds bool
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
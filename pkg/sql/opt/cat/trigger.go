@@ -0,0 +1,62 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/lib/pq/oid"
+)
+
+// Trigger is an interface to a trigger on a table. A trigger executes a
+// trigger function when rows of the table are inserted, updated or deleted.
+// For example:
+//
+//	CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW EXECUTE FUNCTION f()
+type Trigger interface {
+	// Name is the name of the trigger. It is unique within the table.
+	Name() tree.Name
+
+	// ActionTime returns whether the trigger fires before or after the
+	// triggering operation.
+	ActionTime() tree.TriggerActionTime
+
+	// EventCount returns the number of events which fire the trigger.
+	EventCount() int
+
+	// Event returns the ith event which fires the trigger.
+	Event(i int) TriggerEvent
+
+	// ForEachRow returns true if the trigger function is executed once for
+	// every modified row, and false if it is executed once per statement.
+	ForEachRow() bool
+
+	// WhenExpr is the SQL text of the optional WHEN condition of the trigger.
+	// It is empty if the trigger has no WHEN condition.
+	WhenExpr() string
+
+	// FuncOID is the OID of the trigger function.
+	FuncOID() oid.Oid
+
+	// FuncArgs returns the arguments that are passed to the trigger function
+	// through TG_ARGV.
+	FuncArgs() []string
+}
+
+// TriggerEvent describes an event which fires a trigger.
+type TriggerEvent struct {
+	// EventType is the type of the operation which fires the trigger.
+	EventType tree.TriggerEventType
+
+	// ColumnOrdinals is the list of table column ordinals from the UPDATE OF
+	// clause of the trigger. An UPDATE trigger with a non-empty list only
+	// fires when one of these columns is a target of the update.
+	ColumnOrdinals []int
+}
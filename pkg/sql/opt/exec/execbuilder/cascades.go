@@ -154,6 +154,7 @@ func makeCascadeBuilder(b *Builder, mutationWithID opt.WithID) (*cascadeBuilder,
 func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
 	return exec.Cascade{
 		FKConstraint: cascade.FKConstraint,
+		Trigger:      cascade.Trigger,
 		Buffer:       cb.mutationBuffer,
 		PlanFn: func(
 			ctx context.Context,
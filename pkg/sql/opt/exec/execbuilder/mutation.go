@@ -129,6 +129,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (_ execPlan, outputCols colO
 		return execPlan{}, colOrdMap{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, colOrdMap{}, err
+	}
+
 	return ep, outputCols, nil
 }
 
@@ -152,6 +156,25 @@ func (b *Builder) tryBuildFastPathInsert(
 	if len(ins.UniqueChecks) != len(ins.FastPathUniqueChecks) {
 		return execPlan{}, colOrdMap{}, false, nil
 	}
+	// Cascades, which are used to execute AFTER triggers, are not supported by
+	// the fast path.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, colOrdMap{}, false, nil
+	}
+	// Checks of deferrable constraints are not supported by the fast path, since
+	// violations of deferred constraints must not cause an error.
+	md := b.mem.Metadata()
+	for i := range ins.UniqueChecks {
+		c := &ins.UniqueChecks[i]
+		if md.Table(c.Table).Unique(c.CheckOrdinal).Deferrability().IsDeferrable() {
+			return execPlan{}, colOrdMap{}, false, nil
+		}
+	}
+	for i := range ins.FKChecks {
+		if fkCheckConstraint(md, &ins.FKChecks[i]).Deferrability().IsDeferrable() {
+			return execPlan{}, colOrdMap{}, false, nil
+		}
+	}
 
 	insInput := ins.Input
 	values, ok := insInput.(*memo.ValuesExpr)
@@ -161,7 +184,6 @@ func (b *Builder) tryBuildFastPathInsert(
 		return execPlan{}, colOrdMap{}, false, nil
 	}
 
-	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
 	uniqChecks := make([]exec.InsertFastPathCheck, len(ins.UniqueChecks))
@@ -782,18 +804,25 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 			return err
 		}
 		// Wrap the query in an error node.
+		keyVals := mkCheckKeyValsFn(queryCols, c.KeyCols)
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				ord, err := getNodeColumnOrdinal(queryCols, col)
-				if err != nil {
-					return err
-				}
-				keyVals[i] = row[ord]
+			vals, err := keyVals(row)
+			if err != nil {
+				return err
+			}
+			return mkUniqueCheckErr(md, c, vals)
+		}
+		var deferrable *exec.DeferrableCheck
+		tab := md.Table(c.Table)
+		if uc := tab.Unique(c.CheckOrdinal); uc.Deferrability().IsDeferrable() {
+			deferrable = &exec.DeferrableCheck{
+				Table:             tab.ID(),
+				Constraint:        uc.Name(),
+				InitiallyDeferred: uc.Deferrability() == tree.ConstraintInitiallyDeferred,
+				KeyVals:           keyVals,
 			}
-			return mkUniqueCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -814,18 +843,24 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 			return err
 		}
 		// Wrap the query in an error node.
+		keyVals := mkCheckKeyValsFn(queryCols, c.KeyCols)
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				ord, err := getNodeColumnOrdinal(queryCols, col)
-				if err != nil {
-					return err
-				}
-				keyVals[i] = row[ord]
+			vals, err := keyVals(row)
+			if err != nil {
+				return err
+			}
+			return mkFKCheckErr(md, c, vals)
+		}
+		var deferrable *exec.DeferrableCheck
+		if fk := fkCheckConstraint(md, c); fk.Deferrability().IsDeferrable() {
+			deferrable = &exec.DeferrableCheck{
+				Table:             fk.OriginTableID(),
+				Constraint:        fk.Name(),
+				InitiallyDeferred: fk.Deferrability() == tree.ConstraintInitiallyDeferred,
+				KeyVals:           keyVals,
 			}
-			return mkFKCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -834,6 +869,33 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// mkCheckKeyValsFn returns a function which extracts the values of the given
+// key columns from a row produced by a check query.
+func mkCheckKeyValsFn(
+	queryCols colOrdMap, keyCols opt.ColList,
+) func(row tree.Datums) (tree.Datums, error) {
+	return func(row tree.Datums) (tree.Datums, error) {
+		keyVals := make(tree.Datums, len(keyCols))
+		for i, col := range keyCols {
+			ord, err := getNodeColumnOrdinal(queryCols, col)
+			if err != nil {
+				return nil, err
+			}
+			keyVals[i] = row[ord]
+		}
+		return keyVals, nil
+	}
+}
+
+// fkCheckConstraint returns the foreign key constraint checked by the given FK
+// check.
+func fkCheckConstraint(md *opt.Metadata, c *memo.FKChecksItem) cat.ForeignKeyConstraint {
+	if c.FKOutbound {
+		return md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal)
+	}
+	return md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -842,11 +904,19 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 	uc := tabMeta.Table.Unique(c.CheckOrdinal)
 	constraintName := uc.Name()
 	var msg, details bytes.Buffer
+	isExclusion := uc.ExclusionElementCount() > 0
 
 	// Generate an error of the form:
 	//   ERROR:  duplicate key value violates unique constraint "foo"
 	//   DETAIL: Key (k)=(2) already exists.
-	msg.WriteString("duplicate key value violates unique constraint ")
+	// or, for exclusion constraints:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (k)=(2) conflicts with an existing key.
+	if isExclusion {
+		msg.WriteString("conflicting key value violates exclusion constraint ")
+	} else {
+		msg.WriteString("duplicate key value violates unique constraint ")
+	}
 	lexbase.EncodeEscapedSQLIdent(&msg, constraintName)
 
 	details.WriteString("Key (")
@@ -865,11 +935,17 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 		details.WriteString(d.String())
 	}
 
-	details.WriteString(") already exists.")
+	code := pgcode.UniqueViolation
+	if isExclusion {
+		details.WriteString(") conflicts with an existing key.")
+		code = pgcode.ExclusionViolation
+	} else {
+		details.WriteString(") already exists.")
+	}
 
 	return errors.WithDetail(
 		pgerror.WithConstraintName(
-			pgerror.Newf(pgcode.UniqueViolation, "%s", msg.String()),
+			pgerror.Newf(code, "%s", msg.String()),
 			constraintName,
 		),
 		details.String(),
@@ -1014,6 +1090,13 @@ func (b *Builder) buildFKCascades(withID opt.WithID, cascades memo.FKCascades) e
 		return err
 	}
 	for i := range cascades {
+		if cascades[i].Trigger != nil && cascades[i].WithID == 0 {
+			// Statement-level triggers do not use the mutation input, and must
+			// be executed even if no rows were modified.
+			noInputCB := &cascadeBuilder{b: b}
+			b.cascades = append(b.cascades, noInputCB.setupCascade(&cascades[i]))
+			continue
+		}
 		b.cascades = append(b.cascades, cb.setupCascade(&cascades[i]))
 	}
 	return nil
@@ -716,6 +716,7 @@ func (b *Builder) scanParams(
 		Locking:            locking,
 		EstimatedRowCount:  rowCount,
 		LocalityOptimized:  scan.LocalityOptimized,
+		Sample:             scan.Sample,
 	}, outputMap, nil
 }
 
@@ -732,6 +733,11 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (_ execPlan, outputCols colOrdM
 	}
 
 	isUnfiltered := scan.IsUnfiltered(md)
+	if scan.Sample.Method == tree.TableSampleBernoulli && scan.IsFullIndexScan(md) {
+		// A BERNOULLI sample reads all rows of the table, even though only some
+		// of them are returned.
+		isUnfiltered = true
+	}
 	if scan.Flags.NoFullScan {
 		// Normally a full scan of a partial index would be allowed with the
 		// NO_FULL_SCAN hint (isUnfiltered is false for partial indexes), but if the
@@ -3394,6 +3400,8 @@ func (b *Builder) buildCall(c *memo.CallExpr) (_ execPlan, outputCols colOrdMap,
 		true, /* procedure */
 		nil,  /* blockState */
 		nil,  /* cursorDeclaration */
+		nil,  /* resultBuffer */
+		udf.Def.SessionSettings,
 	)
 
 	var ep execPlan
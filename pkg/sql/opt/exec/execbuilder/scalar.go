@@ -704,6 +704,8 @@ func (b *Builder) buildExistsSubquery(
 				false, /* procedure */
 				nil,   /* blockState */
 				nil,   /* cursorDeclaration */
+				nil,   /* resultBuffer */
+				nil,   /* sessionSettings */
 			),
 			tree.DBoolFalse,
 		}, types.Bool), nil
@@ -824,6 +826,8 @@ func (b *Builder) buildSubquery(
 			false, /* procedure */
 			nil,   /* blockState */
 			nil,   /* cursorDeclaration */
+			nil,   /* resultBuffer */
+			nil,   /* sessionSettings */
 		), nil
 	}
 
@@ -883,6 +887,8 @@ func (b *Builder) buildSubquery(
 			false, /* procedure */
 			nil,   /* blockState */
 			nil,   /* cursorDeclaration */
+			nil,   /* resultBuffer */
+			nil,   /* sessionSettings */
 		), nil
 	}
 
@@ -982,6 +988,8 @@ func (b *Builder) buildUDF(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Typ
 		false, /* procedure */
 		blockState,
 		udf.Def.CursorDeclaration,
+		udf.Def.ResultBuffer,
+		udf.Def.SessionSettings,
 	), nil
 }
 
@@ -1037,6 +1045,8 @@ func (b *Builder) initRoutineExceptionHandler(
 			false, /* procedure */
 			nil,   /* blockState */
 			nil,   /* cursorDeclaration */
+			nil,   /* resultBuffer */
+			nil,   /* sessionSettings */
 		)
 	}
 	blockState.ExceptionHandler = exceptionHandler
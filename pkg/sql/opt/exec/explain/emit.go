@@ -121,6 +121,17 @@ func emitInternal(
 	}
 
 	for _, cascade := range plan.Cascades {
+		if cascade.Trigger != nil {
+			// The plans for triggers only invoke the trigger function, so there
+			// is no need to recurse into them.
+			ob.EnterMetaNode("after-trigger")
+			ob.Attr("trigger", string(cascade.Trigger.Name()))
+			if buffer := cascade.Buffer; buffer != nil {
+				ob.Attr("input", buffer.(*Node).args.(*bufferArgs).Label)
+			}
+			ob.LeaveNode()
+			continue
+		}
 		ob.EnterMetaNode("fk-cascade")
 		ob.Attr("fk", cascade.FKConstraint.Name())
 		// Here we do want to allow creation of the plans for the cascades to be
@@ -635,6 +646,10 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			ob.Attr("limit", "")
 		}
 
+		if a.Params.Sample.IsSet() {
+			ob.Attr("sample", a.Params.Sample.String())
+		}
+
 		if a.Params.Parallelize {
 			ob.VAttr("parallel", "")
 		}
@@ -128,6 +128,10 @@ type ScanParams struct {
 	// to work correctly, the execution engine must create a local DistSQL plan
 	// for the main query (subqueries and postqueries need not be local).
 	LocalityOptimized bool
+
+	// If set, the scan only returns a random sample of the rows, as specified
+	// by a TABLESAMPLE clause.
+	Sample opt.TableSample
 }
 
 // OutputOrdering indicates the required output ordering on a Node that is being
@@ -275,8 +279,14 @@ type ApplyJoinPlanRightSideFn func(ctx context.Context, ef Factory, leftRow tree
 // ConstructBuffer as an input; it should only be triggered if this buffer is
 // not empty.
 type Cascade struct {
+	// FKConstraint is the foreign key constraint that the cascade enforces. It
+	// is nil if the cascade executes a trigger.
 	FKConstraint cat.ForeignKeyConstraint
 
+	// Trigger is the AFTER trigger that the cascade executes. It is nil if the
+	// cascade enforces a foreign key constraint.
+	Trigger cat.Trigger
+
 	// Buffer is the Node returned by ConstructBuffer which stores the input to
 	// the mutation. It is nil if the cascade does not require a buffer.
 	Buffer Node
@@ -355,6 +365,28 @@ type InsertFastPathCheck struct {
 // relevant row.
 type MkErrFn func(tree.Datums) error
 
+// DeferrableCheck contains information about a check of a deferrable foreign
+// key or uniqueness constraint (see ConstructErrorIfRows). When the constraint
+// is deferred, violations are not reported immediately; instead, their keys
+// are recorded and the constraint is checked again when the transaction
+// commits (or when the constraint is set to IMMEDIATE).
+type DeferrableCheck struct {
+	// Table is the table the constraint is defined on. For foreign keys, this
+	// is the origin (referencing) table.
+	Table cat.StableID
+
+	// Constraint is the name of the constraint.
+	Constraint string
+
+	// InitiallyDeferred is true if the constraint is deferred unless SET
+	// CONSTRAINTS IMMEDIATE is used in the transaction.
+	InitiallyDeferred bool
+
+	// KeyVals returns the values of the constraint columns for a row produced
+	// by the check. The values are in the order of the constraint columns.
+	KeyVals func(tree.Datums) (tree.Datums, error)
+}
+
 // ExplainFactory is an extension of Factory used when constructing a plan that
 // can be explained. It allows annotation of nodes with extra information.
 type ExplainFactory interface {
@@ -136,6 +136,13 @@ func TryFilterInvertedIndex(
 				index:           index,
 				computedColumns: computedColumns,
 			}
+		case types.RangeFamily, types.MultirangeFamily:
+			filterPlanner = &rangeFilterPlanner{
+				tabID:           tabID,
+				index:           index,
+				computedColumns: computedColumns,
+				typ:             typ,
+			}
 		default:
 			return nil, nil, nil, nil, false
 		}
@@ -213,6 +220,12 @@ func TryJoinInvertedIndex(
 			getSpanExpr: getSpanExprForGeometryIndex,
 		}
 	} else {
+		col := index.InvertedColumn().InvertedSourceColumnOrdinal()
+		switch factory.Metadata().Table(tabID).Column(col).DatumType().Family() {
+		case types.RangeFamily, types.MultirangeFamily:
+			// Inverted joins are not yet supported for range indexes.
+			return nil
+		}
 		joinPlanner = &jsonOrArrayJoinPlanner{
 			factory:   factory,
 			tabID:     tabID,
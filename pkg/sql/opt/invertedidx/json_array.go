@@ -382,6 +382,8 @@ func (j *jsonOrArrayFilterPlanner) extractInvertedFilterConditionFromLeaf(
 		invertedExpr = j.extractJSONExistsCondition(ctx, evalCtx, t.Left, t.Right, false /* all */)
 	case *memo.JsonAllExistsExpr:
 		invertedExpr = j.extractJSONExistsCondition(ctx, evalCtx, t.Left, t.Right, true /* all */)
+	case *memo.JsonPathExistsExpr:
+		invertedExpr = j.extractJSONPathExistsCondition(ctx, evalCtx, t.Left, t.Right)
 	case *memo.EqExpr:
 		if fetch, ok := t.Left.(*memo.FetchValExpr); ok {
 			invertedExpr = j.extractJSONFetchValEqCondition(ctx, evalCtx, fetch, t.Right)
@@ -553,6 +555,50 @@ func (j *jsonOrArrayFilterPlanner) extractJSONExistsCondition(
 	return inverted.NonInvertedColExpression{}
 }
 
+// extractJSONPathExistsCondition extracts an InvertedExpression representing
+// an inverted filter with the JSONPathExists (@?) operator over the planner's
+// inverted index, based on the given left and right expression arguments. Only
+// simple paths that compare a chain of keys with a constant, such as
+// '$.a.b ? (@ == 1)', can be used to constrain the index. Returns an empty
+// InvertedExpression if no inverted filter could be extracted.
+func (j *jsonOrArrayFilterPlanner) extractJSONPathExistsCondition(
+	ctx context.Context, evalCtx *eval.Context, left, right opt.ScalarExpr,
+) inverted.Expression {
+	if !isIndexColumn(j.tabID, j.index, left, j.computedColumns) ||
+		!memo.CanExtractConstDatum(right) {
+		return inverted.NonInvertedColExpression{}
+	}
+	p, ok := memo.ExtractConstDatum(right).(*tree.DJsonpath)
+	if !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	// The path returns an item only if the JSON value contains one of the
+	// containments of the path. In lax mode, there are several of them since
+	// arrays are automatically unwrapped.
+	containments, ok := p.Containments()
+	if !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	var invertedExpr inverted.Expression
+	for _, c := range containments {
+		expr := getInvertedExprForJSONOrArrayIndexForContaining(ctx, evalCtx, tree.NewDJSON(c))
+		if invertedExpr == nil {
+			invertedExpr = expr
+		} else {
+			invertedExpr = inverted.Or(invertedExpr, expr)
+		}
+	}
+	if invertedExpr == nil {
+		return inverted.NonInvertedColExpression{}
+	}
+
+	// The generated inverted expression is not tight, since the index may
+	// return rows that contain one of the containments but for which the path
+	// does not return any item. The path must be evaluated on these rows.
+	invertedExpr.SetNotTight()
+	return invertedExpr
+}
+
 // extractJSONEqCondition extracts an InvertedExpression representing an
 // inverted filter over the planner's inverted index, based on equality between
 // two scalar expressions. If an InvertedExpression cannot be generated from the
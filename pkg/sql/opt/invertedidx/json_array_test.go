@@ -866,6 +866,38 @@ func TestTryFilterJsonOrArrayIndex(t *testing.T) {
 			indexOrd: jsonOrd,
 			ok:       false,
 		},
+		{
+			// JSONPathExists is supported for paths comparing a chain of keys with
+			// a constant. Unique is false in lax mode because the path matches
+			// several containments.
+			filters:          "j @? '$.a ? (@ == 1)'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @? '$.a ? (@ == 1)'",
+		},
+		{
+			// A strict path has a single containment.
+			filters:          "j @? 'strict $.a.b ? (@ == \"foo\")'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: "j @? 'strict $.a.b ? (@ == \"foo\")'",
+		},
+		{
+			// JSONPathExists with a wildcard isn't supported.
+			filters:  "j @? '$.a[*] ? (@ == 1)'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
+		{
+			// JSONPathExists with an inequality isn't supported.
+			filters:  "j @? '$.a ? (@ > 1)'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
 		{
 			// Overlaps is supported for arrays.
 			// Overlaps with a single element array produces
@@ -0,0 +1,118 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type rangeFilterPlanner struct {
+	tabID           opt.TableID
+	index           cat.Index
+	computedColumns map[opt.ColumnID]opt.ScalarExpr
+	// typ is the type of the indexed range or multirange column.
+	typ *types.T
+}
+
+var _ invertedFilterPlanner = &rangeFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf implements the invertedFilterPlanner
+// interface. Overlaps (&&), contains (@>) and contained by (<@) expressions
+// between the indexed column and a constant are supported. The inverted index
+// only stores the lower bounds of the indexed ranges, so the returned
+// expressions are never tight.
+func (r *rangeFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	ctx context.Context, evalCtx *eval.Context, expr opt.ScalarExpr,
+) (
+	invertedExpr inverted.Expression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	var left, right opt.ScalarExpr
+	switch t := expr.(type) {
+	case *memo.OverlapsExpr:
+		left, right = t.Left, t.Right
+	case *memo.ContainsExpr:
+		left, right = t.Left, t.Right
+	case *memo.ContainedByExpr:
+		left, right = t.Left, t.Right
+	default:
+		// Only the above types are supported.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+	var constantVal opt.ScalarExpr
+	commuted := false
+	if isIndexColumn(r.tabID, r.index, left, r.computedColumns) && memo.CanExtractConstDatum(right) {
+		constantVal = right
+	} else if isIndexColumn(r.tabID, r.index, right, r.computedColumns) && memo.CanExtractConstDatum(left) {
+		constantVal = left
+		commuted = true
+	} else {
+		// Can only accelerate with a single constant value.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+	d := memo.ExtractConstDatum(constantVal)
+	if d == tree.DNull {
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+	if f := d.ResolvedType().Family(); f != types.RangeFamily && f != types.MultirangeFamily {
+		// The constant is an element of the range, which is treated as a range
+		// containing only that element.
+		rangeTyp := r.typ
+		if rangeTyp.Family() == types.MultirangeFamily {
+			rangeTyp = rangeTyp.RangeContents()
+		}
+		elemRange, err := tree.NewDRange(rangeTyp, d, d, true /* lowerInc */, true /* upperInc */)
+		if err != nil {
+			return inverted.NonInvertedColExpression{}, expr, nil
+		}
+		d = elemRange
+	}
+
+	var err error
+	switch expr.(type) {
+	case *memo.OverlapsExpr:
+		invertedExpr, err = rowenc.EncodeOverlapsInvertedIndexSpans(ctx, evalCtx, d)
+	case *memo.ContainsExpr:
+		if commuted {
+			invertedExpr, err = rowenc.EncodeContainedInvertedIndexSpans(ctx, evalCtx, d)
+		} else {
+			invertedExpr, err = rowenc.EncodeContainingInvertedIndexSpans(ctx, evalCtx, d)
+		}
+	case *memo.ContainedByExpr:
+		if commuted {
+			invertedExpr, err = rowenc.EncodeContainingInvertedIndexSpans(ctx, evalCtx, d)
+		} else {
+			invertedExpr, err = rowenc.EncodeContainedInvertedIndexSpans(ctx, evalCtx, d)
+		}
+	}
+	if err != nil {
+		// An inverted expression could not be extracted.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+	if !invertedExpr.IsTight() {
+		remainingFilters = expr
+	}
+
+	// We do not currently support pre-filtering for range indexes, so the
+	// returned pre-filter state is nil.
+	return invertedExpr, remainingFilters, nil
+}
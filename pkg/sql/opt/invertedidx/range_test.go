@@ -0,0 +1,106 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryFilterRange(t *testing.T) {
+	semaCtx := tree.MakeSemaContext()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.NewTestingEvalContext(st)
+
+	tc := testcat.New()
+	if _, err := tc.ExecuteDDL(
+		"CREATE TABLE t (r int4range, s int4range, m int4multirange, " +
+			"INVERTED INDEX r_idx (r), INVERTED INDEX m_idx (m))",
+	); err != nil {
+		t.Fatal(err)
+	}
+	var f norm.Factory
+	f.Init(context.Background(), evalCtx, tc)
+	md := f.Metadata()
+	tn := tree.NewUnqualifiedTableName("t")
+	tab := md.AddTable(tc.Table(tn), tn)
+	rOrd, mOrd := 1, 2
+
+	// If we can create an inverted filter with the given filter expression and
+	// index, ok=true. The inverted index only stores the lower bounds of the
+	// ranges, so the spans are never tight unless they are empty.
+	testCases := []struct {
+		filters  string
+		indexOrd int
+		ok       bool
+		tight    bool
+	}{
+		{filters: "r && '[1,5)'", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "'[1,5)' && r", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "r && '[1,)'", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "r && 'empty'", indexOrd: rOrd, ok: true, tight: true},
+		{filters: "r @> '[1,5)'", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "r @> 3", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "r <@ '[1,5)'", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "'[1,5)' @> r", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "r && '{[1,2), [5,6)}'::int4multirange", indexOrd: rOrd, ok: true, tight: false},
+		{filters: "m && '[1,5)'", indexOrd: mOrd, ok: true, tight: false},
+		{filters: "m @> 3", indexOrd: mOrd, ok: true, tight: false},
+		{filters: "m <@ '{[1,2), [5,6)}'", indexOrd: mOrd, ok: true, tight: false},
+
+		// The constant must be compared with the indexed column.
+		{filters: "s && '[1,5)'", indexOrd: rOrd, ok: false},
+		{filters: "r && s", indexOrd: rOrd, ok: false},
+		{filters: "r -|- '[1,5)'", indexOrd: rOrd, ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("test case: %v", tc)
+		filters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.filters)
+
+		spanExpr, _, remainingFilters, _, ok := invertedidx.TryFilterInvertedIndex(
+			context.Background(),
+			evalCtx,
+			&f,
+			filters,
+			nil, /* optionalFilters */
+			tab,
+			md.Table(tab).Index(tc.indexOrd),
+			nil,       /* computedColumns */
+			func() {}, /* checkCancellation */
+		)
+		if tc.ok != ok {
+			t.Fatalf("expected %v, got %v", tc.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+
+		if tc.tight != spanExpr.Tight {
+			t.Fatalf("For (%s), expected tight=%v, but got %v", tc.filters, tc.tight, spanExpr.Tight)
+		}
+		if tc.tight {
+			require.True(t, remainingFilters.IsTrue())
+		} else {
+			require.Equal(t, filters.String(), remainingFilters.String(),
+				"mismatched remaining filters")
+		}
+	}
+}
@@ -751,6 +751,19 @@ type UDFDefinition struct {
 	// result of the routine. This invariant is enforced when the PLpgSQL routine
 	// is built. CursorDeclaration may be unset.
 	CursorDeclaration *tree.RoutineOpenCursor
+
+	// ResultBuffer is shared between the routines that make up a set-returning
+	// PLpgSQL routine. If it is set for the root routine, the output of the
+	// routine is the set of rows that were added to the buffer. If it is set for
+	// a sub-routine, the result of the *first* body statement is added to the
+	// buffer. ResultBuffer may be unset.
+	ResultBuffer *tree.RoutineResultBuffer
+
+	// SessionSettings contains the session data changes that are applied while
+	// the routine executes, for routines defined with SECURITY DEFINER or with
+	// SET clauses. Only the root routine of a UDF has it set. SessionSettings
+	// may be unset.
+	SessionSettings *tree.RoutineSessionSettings
 }
 
 // ExceptionBlock contains the information needed to match and handle errors in
@@ -826,7 +839,8 @@ func (s *ScanPrivate) IsCanonical() bool {
 	return s.Index == cat.PrimaryIndex &&
 		s.Constraint == nil &&
 		s.HardLimit == 0 &&
-		!s.LocalityOptimized
+		!s.LocalityOptimized &&
+		!s.Sample.IsSet()
 }
 
 // IsUnfiltered returns true if the ScanPrivate will produce all rows in the
@@ -836,7 +850,8 @@ func (s *ScanPrivate) IsUnfiltered(md *opt.Metadata) bool {
 		s.InvertedConstraint == nil &&
 		s.HardLimit == 0 &&
 		s.PartialIndexPredicate(md) == nil &&
-		s.Locking.WaitPolicy != tree.LockWaitSkipLocked
+		s.Locking.WaitPolicy != tree.LockWaitSkipLocked &&
+		!s.Sample.IsSet()
 }
 
 // IsFullIndexScan returns true if the ScanPrivate will produce all rows in the
@@ -1326,9 +1341,17 @@ type FKCascades []FKCascade
 
 // FKCascade stores metadata necessary for building a cascading query.
 // Cascading queries are built as needed, after the original query is executed.
+// Cascading queries are also used to execute AFTER triggers, in which case
+// FKConstraint is nil and Trigger is set.
 type FKCascade struct {
+	// FKConstraint is the foreign key constraint that the cascade enforces. It
+	// is nil if the cascade executes a trigger.
 	FKConstraint cat.ForeignKeyConstraint
 
+	// Trigger is the AFTER trigger that the cascade executes. It is nil if the
+	// cascade enforces a foreign key constraint.
+	Trigger cat.Trigger
+
 	// Builder is an object that can be used as the "optbuilder" for the cascading
 	// query.
 	Builder CascadeBuilder
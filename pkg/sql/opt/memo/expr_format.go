@@ -490,6 +490,9 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		if private.HardLimit.IsSet() {
 			tp.Childf("limit: %s", private.HardLimit)
 		}
+		if private.Sample.IsSet() {
+			tp.Childf("sample: %s", private.Sample)
+		}
 
 		if private.shouldPrintFlags(md, f.HasFlags(ExprFmtHideNotVisibleIndexInfo)) {
 			var b strings.Builder
@@ -963,6 +966,13 @@ func (f *ExprFmtCtx) formatScalarWithLabel(
 					f.formatExpr(def.Body[i], cur)
 					continue
 				}
+				if i == 0 && def.ResultBuffer != nil && !def.SetReturning {
+					// The result of the first statement is added to the result set of
+					// the set-returning routine.
+					res := n.Child("add-to-result")
+					f.formatExpr(def.Body[i], res)
+					continue
+				}
 				f.formatExpr(def.Body[i], n)
 			}
 			delete(f.seenUDFs, def)
@@ -1526,7 +1536,11 @@ func (f *ExprFmtCtx) formatMutationCommon(tp treeprinter.Node, p *MutationPrivat
 	if len(p.FKCascades) > 0 {
 		c := tp.Childf("cascades")
 		for i := range p.FKCascades {
-			c.Child(p.FKCascades[i].FKConstraint.Name())
+			if t := p.FKCascades[i].Trigger; t != nil {
+				c.Childf("trigger %s", t.Name())
+			} else {
+				c.Child(p.FKCascades[i].FKConstraint.Name())
+			}
 		}
 	}
 }
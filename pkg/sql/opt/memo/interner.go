@@ -655,6 +655,13 @@ func (h *hasher) HashLocking(val opt.Locking) {
 	h.HashByte(byte(val.WaitPolicy))
 }
 
+func (h *hasher) HashTableSample(val opt.TableSample) {
+	h.HashInt(int(val.Method))
+	h.HashFloat64(val.Probability)
+	h.HashFloat64(val.Seed)
+	h.HashBool(val.Repeatable)
+}
+
 func (h *hasher) HashInvertedSpans(val inverted.Spans) {
 	for i := range val {
 		span := &val[i]
@@ -1094,6 +1101,10 @@ func (h *hasher) IsLockingEqual(l, r opt.Locking) bool {
 	return l == r
 }
 
+func (h *hasher) IsTableSampleEqual(l, r opt.TableSample) bool {
+	return l == r
+}
+
 func (h *hasher) IsInvertedSpansEqual(l, r inverted.Spans) bool {
 	return l.Equals(r)
 }
@@ -1326,6 +1337,12 @@ func (h *hasher) IsUDFDefinitionEqual(l, r *UDFDefinition) bool {
 	} else if r.CursorDeclaration != nil {
 		return false
 	}
+	if l.ResultBuffer != r.ResultBuffer {
+		return false
+	}
+	if l.SessionSettings != r.SessionSettings {
+		return false
+	}
 	return h.IsColListEqual(l.Params, r.Params) && l.IsRecursive == r.IsRecursive
 }
 
@@ -543,6 +543,30 @@ func TestInterner(t *testing.T) {
 			},
 		}},
 
+		{hashFn: in.hasher.HashTableSample, eqFn: in.hasher.IsTableSampleEqual, variations: []testVariation{
+			{val1: opt.TableSample{}, val2: opt.TableSample{}, equal: true},
+			{
+				val1:  opt.TableSample{},
+				val2:  opt.TableSample{Method: tree.TableSampleBernoulli},
+				equal: false,
+			},
+			{
+				val1:  opt.TableSample{Method: tree.TableSampleBernoulli, Probability: 0.1},
+				val2:  opt.TableSample{Method: tree.TableSampleSystem, Probability: 0.1},
+				equal: false,
+			},
+			{
+				val1:  opt.TableSample{Method: tree.TableSampleBernoulli, Probability: 0.1, Seed: 1, Repeatable: true},
+				val2:  opt.TableSample{Method: tree.TableSampleBernoulli, Probability: 0.1, Seed: 2, Repeatable: true},
+				equal: false,
+			},
+			{
+				val1:  opt.TableSample{Method: tree.TableSampleSystem, Probability: 0.5, Seed: 1, Repeatable: true},
+				val2:  opt.TableSample{Method: tree.TableSampleSystem, Probability: 0.5, Seed: 1, Repeatable: true},
+				equal: true,
+			},
+		}},
+
 		{hashFn: in.hasher.HashFastPathUniqueChecksExpr, eqFn: in.hasher.IsFastPathUniqueChecksExprEqual, variations: []testVariation{
 			{
 				val1:  FastPathUniqueChecksExpr{FastPathUniqueChecksItem{Check: scanNode}},
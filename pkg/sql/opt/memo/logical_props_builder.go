@@ -1897,6 +1897,11 @@ func MakeTableFuncDep(md *opt.Metadata, tabID opt.TableID) *props.FuncDepSet {
 			continue
 		}
 
+		if unique.ExclusionElementCount() > 0 {
+			// Exclusion constraints do not make their columns a key of the table.
+			continue
+		}
+
 		// If any of the columns are nullable, add a lax key FD. Otherwise, add a
 		// strict key.
 		var keyCols opt.ColSet
@@ -436,6 +436,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().OptimizerMergeJoinsEnabled = false
 	notStale()
 
+	// Stale allow materialized view mutations.
+	evalCtx.SessionData().AllowMaterializedViewMutations = true
+	stale()
+	evalCtx.SessionData().AllowMaterializedViewMutations = false
+	notStale()
+
 	// User no longer has access to view.
 	catalog.View(tree.NewTableNameWithSchema("t", catconstants.PublicSchemaName, "abcview")).Revoked = true
 	_, err = o.Memo().IsStale(ctx, &evalCtx, catalog)
@@ -809,6 +809,10 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 	// scan on a non-partial index. The stats of the scan are the same as the
 	// underlying table stats.
 	if scan.Constraint == nil && scan.InvertedConstraint == nil && pred == nil {
+		if scan.Sample.IsSet() {
+			// A sampled scan only returns the given fraction of the table.
+			s.ApplySelectivity(props.MakeSelectivity(scan.Sample.Probability))
+		}
 		sb.finalizeFromCardinality(relProps)
 		return
 	}
@@ -79,6 +79,14 @@ func (c *CustomFuncs) neededMutationCols(
 		cols.Add(private.CanaryCol)
 	}
 
+	// Add the columns that are passed to AFTER triggers.
+	for i := range private.FKCascades {
+		if private.FKCascades[i].Trigger != nil {
+			cols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+			cols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+		}
+	}
+
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
 			withUses := memo.WithUses(uniqueChecks[i].Check)
@@ -242,6 +250,13 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// Add the old values of the rows that are passed to AFTER triggers.
+	for i := range private.FKCascades {
+		if private.FKCascades[i].Trigger != nil {
+			cols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+		}
+	}
+
 	return cols
 }
 
@@ -146,6 +146,8 @@ var ComparisonOpReverseMap = map[Operator]treecmp.ComparisonOperatorSymbol{
 	BBoxCoversOp:     treecmp.RegMatch,
 	BBoxIntersectsOp: treecmp.Overlaps,
 	TSMatchesOp:      treecmp.TSMatches,
+	AdjacentOp:       treecmp.Adjacent,
+	JsonPathExistsOp: treecmp.JSONPathExists,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
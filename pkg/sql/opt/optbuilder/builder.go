@@ -130,6 +130,10 @@ type Builder struct {
 	// chain. It is used to detect circular dependencies.
 	sourceViews map[string]struct{}
 
+	// triggersInProgress contains the BEFORE triggers whose trigger functions
+	// are currently being built. It is used to detect recursive triggers.
+	triggersInProgress map[string]struct{}
+
 	// subquery contains a pointer to the subquery which is currently being built
 	// (if any).
 	subquery *subquery
@@ -146,9 +150,17 @@ type Builder struct {
 	// UDF.
 	insideUDF bool
 
+	// plpgsqlBuilder is the builder for the PL/pgSQL routine whose body is
+	// currently being built, if any. It is used to resolve references to names
+	// declared with ALIAS FOR.
+	plpgsqlBuilder *plpgsqlBuilder
+
 	// insideDataSource is true when we are processing a data source.
 	insideDataSource bool
 
+	// insideCascade is true when we are building a cascading query.
+	insideCascade bool
+
 	// If set, we are collecting view dependencies in schemaDeps. This can only
 	// happen inside view/function definitions.
 	//
@@ -318,7 +330,7 @@ func (b *Builder) buildStmt(
 	if b.insideViewDef {
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
-		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+		case *tree.Delete, *tree.Insert, *tree.Update, *tree.Merge, *tree.CreateTable, *tree.CreateView,
 			*tree.Split, *tree.Unsplit, *tree.Relocate, *tree.RelocateRange,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions,
 			*tree.CreateRoutine:
@@ -364,6 +376,11 @@ func (b *Builder) buildStmt(
 			return b.buildUpdate(stmt, inScope)
 		})
 
+	case *tree.Merge:
+		return b.processWiths(stmt.With, inScope, func(inScope *scope) *scope {
+			return b.buildMerge(stmt, inScope)
+		})
+
 	case *tree.CreateTable:
 		return b.buildCreateTable(stmt, inScope)
 
@@ -13,6 +13,7 @@ package optbuilder
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -27,6 +28,7 @@ import (
 	plpgsqlparser "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
@@ -110,6 +112,11 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			if _, err := funcinfo.FunctionLangToProto(opt); err != nil {
 				panic(err)
 			}
+		case tree.RoutineSecurity, tree.RoutineSetConfig:
+			if !activeVersion.IsActive(clusterversion.V24_1_RoutineSecurityDefiner) {
+				panic(unimplemented.New("routine security",
+					"SECURITY and SET clauses are not supported until version 24.1"))
+			}
 		}
 	}
 
@@ -178,6 +185,10 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 	bodyScope := b.allocScope()
 	// routineParams are all parameters of PLpgSQL routines.
 	var routineParams []routineParam
+	// signatureTypes are the types of the parameters that are included into the
+	// signature of the routine.
+	var signatureTypes []*types.T
+	hasPolymorphicTypes := false
 	var outParamTypes []*types.T
 	// When multiple OUT parameters are present, parameter names become the
 	// labels in the output RECORD type.
@@ -198,6 +209,14 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 		}
 		// The parameter type must be supported by the current cluster version.
 		checkUnsupportedType(b.ctx, b.semaCtx, typ)
+		if typ.Family() == types.TriggerFamily {
+			if language == tree.RoutineLangSQL {
+				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"SQL functions cannot have arguments of type trigger"))
+			}
+			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"PL/pgSQL functions cannot accept type trigger"))
+		}
 		if types.IsRecordType(typ) {
 			if language == tree.RoutineLangSQL {
 				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
@@ -210,8 +229,32 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			}
 		}
 
+		if param.Class == tree.RoutineParamVariadic {
+			if !activeVersion.IsActive(clusterversion.V24_1_VariadicAndPolymorphicRoutines) {
+				panic(unimplemented.NewWithIssueDetail(88947, "variadic user-defined functions",
+					"VARIADIC parameters are not supported until version 24.1"))
+			}
+			if typ.Family() != types.ArrayFamily {
+				panic(pgerror.New(pgcode.InvalidFunctionDefinition, "VARIADIC parameter must be an array"))
+			}
+			for j := i + 1; j < len(cf.Params); j++ {
+				if tree.IsParamIncludedIntoSignature(cf.Params[j].Class, cf.IsProcedure) {
+					panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+						"VARIADIC parameter must be the last input parameter"))
+				}
+			}
+		}
+		if tree.IsPolymorphicType(typ) {
+			if !activeVersion.IsActive(clusterversion.V24_1_VariadicAndPolymorphicRoutines) {
+				panic(unimplemented.New("polymorphic parameters",
+					"parameters of polymorphic types are not supported until version 24.1"))
+			}
+			hasPolymorphicTypes = true
+		}
+
 		// Add this parameter to the base scope of the body if needed.
 		if tree.IsParamIncludedIntoSignature(param.Class, cf.IsProcedure) {
+			signatureTypes = append(signatureTypes, typ)
 			paramColName := funcParamColName(param.Name, i)
 			col := b.synthesizeColumn(bodyScope, paramColName, typ, nil /* expr */, nil /* scalar */)
 			col.setParamOrd(i)
@@ -278,11 +321,39 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			panic(pgerror.New(pgcode.InvalidFunctionDefinition, "PL/pgSQL functions cannot return type unknown"))
 		}
 	}
+	if tree.ContainsPolymorphicType(funcReturnType) {
+		if err := tree.CheckPolymorphicReturnType(signatureTypes, funcReturnType); err != nil {
+			panic(err)
+		}
+		hasPolymorphicTypes = true
+	}
+	// Trigger functions must be written in PL/pgSQL, and they receive their
+	// arguments through TG_NARGS and TG_ARGV rather than declared parameters.
+	isTriggerFunc := funcReturnType.Family() == types.TriggerFamily
+	if isTriggerFunc {
+		if language == tree.RoutineLangSQL {
+			panic(pgerror.New(pgcode.InvalidFunctionDefinition, "SQL functions cannot return type trigger"))
+		}
+		if len(cf.Params) > 0 {
+			panic(errors.WithHint(
+				pgerror.New(pgcode.InvalidFunctionDefinition, "trigger functions cannot have declared arguments"),
+				"The arguments of the trigger can be accessed through TG_NARGS and TG_ARGV instead.",
+			))
+		}
+	}
 	// Collect the user defined type dependency of the return type.
 	typedesc.GetTypeDescriptorClosure(funcReturnType).ForEach(func(id descpb.ID) {
 		typeDeps.Add(int(id))
 	})
 
+	// Names in the body of a routine with a SET search_path clause are resolved
+	// using that search_path, the same way as when the routine is executed.
+	if setting, ok := routineSearchPathSetting(cf.Options); ok {
+		defer b.withRoutineSessionSettings(&tree.RoutineSessionSettings{
+			Config: []string{setting},
+		})()
+	}
+
 	targetVolatility := tree.GetRoutineVolatility(cf.Options)
 	fmtCtx := tree.NewFmtCtx(tree.FmtSerializable)
 
@@ -306,23 +377,30 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			b.semaCtx.Annotations = ann
 			b.evalCtx.Annotations = &ann
 
-			// We need to disable stable function folding because we want to catch the
-			// volatility of stable functions. If folded, we only get a scalar and
-			// lose the volatility.
-			b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
-				stmtScope = b.buildStmtAtRootWithScope(stmts[i].AST, nil /* desiredTypes */, bodyScope)
-			})
-			checkStmtVolatility(targetVolatility, stmtScope, stmt.AST)
+			// The body of a routine with polymorphic types can only be built once
+			// the concrete types are known, when the routine is invoked.
+			// TODO(#88947): this means that the dependencies of the body of the
+			// routine are not tracked.
+			if !hasPolymorphicTypes {
+				// We need to disable stable function folding because we want to catch
+				// the volatility of stable functions. If folded, we only get a scalar
+				// and lose the volatility.
+				b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
+					stmtScope = b.buildStmtAtRootWithScope(stmts[i].AST, nil /* desiredTypes */, bodyScope)
+				})
+				checkStmtVolatility(targetVolatility, stmtScope, stmt.AST)
+			}
 
 			// Format the statements with qualified datasource names.
 			formatFuncBodyStmt(fmtCtx, stmt.AST, language, i > 0 /* newLine */)
 			afterBuildStmt()
 		}
 	case tree.RoutineLangPLpgSQL:
-		if cf.ReturnType != nil && cf.ReturnType.SetOf {
+		setReturning := cf.ReturnType != nil && cf.ReturnType.SetOf
+		if setReturning && types.IsWildcardTupleType(funcReturnType) {
 			panic(unimplemented.NewWithIssueDetail(105240,
-				"set-returning PL/pgSQL functions",
-				"set-returning PL/pgSQL functions are not yet supported",
+				"set-returning PL/pgSQL functions returning RECORD",
+				"set-returning PL/pgSQL functions returning RECORD are not yet supported",
 			))
 		}
 
@@ -332,16 +410,23 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			panic(err)
 		}
 
-		// We need to disable stable function folding because we want to catch the
-		// volatility of stable functions. If folded, we only get a scalar and lose
-		// the volatility.
-		b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
-			plBuilder := newPLpgSQLBuilder(
-				b, cf.Name.Object(), nil /* colRefs */, routineParams, funcReturnType, cf.IsProcedure,
-			)
-			stmtScope = plBuilder.buildRootBlock(stmt.AST, bodyScope, routineParams)
-		})
-		checkStmtVolatility(targetVolatility, stmtScope, stmt)
+		// The body of a trigger function depends on the table of the trigger
+		// through NEW and OLD, so it is only built once it is invoked by a
+		// trigger. Similarly, the body of a routine with polymorphic types is
+		// only built once the concrete types are known.
+		if !isTriggerFunc && !hasPolymorphicTypes {
+			// We need to disable stable function folding because we want to catch
+			// the volatility of stable functions. If folded, we only get a scalar
+			// and lose the volatility.
+			b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
+				plBuilder := newPLpgSQLBuilder(
+					b, cf.Name.Object(), nil /* colRefs */, routineParams, funcReturnType,
+					setReturning, cf.IsProcedure,
+				)
+				stmtScope = plBuilder.buildRootBlock(stmt.AST, bodyScope, routineParams)
+			})
+			checkStmtVolatility(targetVolatility, stmtScope, stmt)
+		}
 
 		// Format the statements with qualified datasource names.
 		formatFuncBodyStmt(fmtCtx, stmt.AST, language, false /* newLine */)
@@ -537,3 +622,36 @@ func checkDuplicateParamName(param tree.RoutineParam, seen map[tree.Name]struct{
 	}
 	seen[param.Name] = struct{}{}
 }
+
+// routineSearchPathSetting returns the search_path set by the given routine
+// options as a "name=value" setting. It returns ok=false if the options do not
+// set the search_path.
+func routineSearchPathSetting(options tree.RoutineOptions) (setting string, ok bool) {
+	for _, option := range options {
+		setConfig, isSetConfig := option.(tree.RoutineSetConfig)
+		if !isSetConfig || !strings.EqualFold(setConfig.Name, "search_path") {
+			continue
+		}
+		paths := make([]string, 0, len(setConfig.Values))
+		for _, v := range setConfig.Values {
+			switch t := v.(type) {
+			case *tree.UnresolvedName:
+				paths = append(paths, t.Parts[0])
+			case *tree.StrVal:
+				paths = append(paths, t.RawString())
+			case tree.DefaultVal:
+				paths = nil
+			default:
+				panic(pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid value for parameter \"search_path\": %s", tree.AsString(v)))
+			}
+		}
+		if paths == nil {
+			// SET search_path TO DEFAULT.
+			setting, ok = "", false
+			continue
+		}
+		setting, ok = "search_path="+sessiondata.FormatSearchPaths(paths), true
+	}
+	return setting, ok
+}
@@ -13,6 +13,8 @@ package optbuilder
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -25,6 +27,9 @@ import (
 // statement.
 func (b *Builder) buildCreateTable(ct *tree.CreateTable, inScope *scope) (outScope *scope) {
 	b.DisableMemoReuse = true
+	if ct.PartitionOf != nil {
+		return b.buildCreateTablePartitionOf(ct, inScope)
+	}
 	isTemp := resolveTemporaryStatus(&ct.Table, ct.Persistence)
 	if isTemp {
 		// Postgres allows using `pg_temp` as an alias for the session specific temp
@@ -110,3 +115,23 @@ func (b *Builder) buildCreateTable(ct *tree.CreateTable, inScope *scope) (outSco
 	)
 	return outScope
 }
+
+// buildCreateTablePartitionOf builds a CREATE TABLE ... PARTITION OF
+// statement. Partitions of a table are partitions of its primary index, so
+// the statement adds a partition to the partitioned table in the same way as
+// ALTER TABLE ... ATTACH PARTITION, rather than creating a new table.
+func (b *Builder) buildCreateTablePartitionOf(
+	ct *tree.CreateTable, inScope *scope,
+) (outScope *scope) {
+	if ct.Persistence.IsTemporary() || ct.Persistence.IsUnlogged() {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"temporary and unlogged partitions are not supported"))
+	}
+	return b.tryBuildOpaque(&tree.AlterTable{
+		Table: ct.PartitionOf.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{&tree.AlterTableAttachPartition{
+			Name:  ct.Table.ObjectName,
+			Bound: *ct.PartitionBound,
+		}},
+	}, inScope)
+}
@@ -105,8 +105,14 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 // buildDelete constructs a Delete operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildDelete(returning *tree.ReturningExprs) {
+	// Apply BEFORE triggers, which may skip some of the deleted rows. This must
+	// happen before the FK checks and cascades are built.
+	mb.buildBeforeRowTriggers(tree.TriggerEventDelete)
+
 	mb.buildFKChecksAndCascadesForDelete()
 
+	mb.buildAfterTriggers(tree.TriggerEventDelete)
+
 	// Project partial index DEL boolean columns.
 	mb.projectPartialIndexDelCols()
 
@@ -120,5 +126,7 @@ func (mb *mutationBuilder) buildDelete(returning *tree.ReturningExprs) {
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
 
+	mb.buildBeforeStatementTriggers(tree.TriggerEventDelete)
+
 	mb.buildReturning(returning)
 }
@@ -300,6 +300,7 @@ func (cb *onDeleteFastCascadeBuilder) Build(
 				includeInverted:  false,
 			}),
 			nil, /* indexFlags */
+			nil, /* sample */
 			noRowLocking,
 			b.allocScope(),
 			true, /* disableNotVisibleIndex */
@@ -522,6 +523,7 @@ func (b *Builder) buildDeleteCascadeMutationInput(
 			includeInverted:  false,
 		}),
 		nil, /* indexFlags */
+		nil, /* sample */
 		noRowLocking,
 		b.allocScope(),
 		true, /* disableNotVisibleIndex */
@@ -759,6 +761,7 @@ func (b *Builder) buildUpdateCascadeMutationInput(
 			includeInverted:  false,
 		}),
 		nil, /* indexFlags */
+		nil, /* sample */
 		noRowLocking,
 		b.allocScope(),
 		true, /* disableNotVisibleIndex */
@@ -900,6 +903,7 @@ func buildCascadeHelper(
 ) (_ memo.RelExpr, err error) {
 	factory := factoryI.(*norm.Factory)
 	b := New(ctx, semaCtx, evalCtx, catalog, factory, nil /* stmt */)
+	b.insideCascade = true
 
 	// Enact panic handling similar to Builder.Build().
 	defer func() {
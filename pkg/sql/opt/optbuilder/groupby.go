@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,6 +101,27 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSetIDCol is non-zero if the GROUP BY clause contains GROUPING
+	// SETS, ROLLUP or CUBE. It is an additional grouping column which
+	// identifies the grouping set that each group belongs to. See
+	// buildGroupingSets for details.
+	groupingSetIDCol opt.ColumnID
+
+	// numGroupingSets is the number of grouping sets when groupingSetIDCol is
+	// set.
+	numGroupingSets int
+
+	// groupingSetInputCol is non-zero if the grouping sets include an empty
+	// grouping set. It is true for the rows of the input, and NULL for the row
+	// which is added for the empty grouping sets if the input has no rows. See
+	// buildGroupingSets for details.
+	groupingSetInputCol opt.ColumnID
+
+	// groupingSetExclusions maps the string representation of each grouping
+	// expression (as in groupStrs) to the set of IDs of the grouping sets that
+	// do not include that expression. It is used to build GROUPING operations.
+	groupingSetExclusions map[string]intsets.Fast
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -126,18 +148,27 @@ func (g *groupby) hasNonCommutativeAggregates() bool {
 	return false
 }
 
+// numGroupingCols returns the number of grouping columns, including the
+// grouping set ID column, if any.
+func (g *groupby) numGroupingCols() int {
+	if g.groupingSetIDCol != 0 {
+		return len(g.groupStrs) + 1
+	}
+	return len(g.groupStrs)
+}
+
 // groupingCols returns the columns in the aggInScope corresponding to grouping
 // columns.
 func (g *groupby) groupingCols() []scopeColumn {
 	// Grouping cols are always clustered at the end of the column list.
-	return g.aggInScope.cols[len(g.aggInScope.cols)-len(g.groupStrs):]
+	return g.aggInScope.cols[len(g.aggInScope.cols)-g.numGroupingCols():]
 }
 
 // getAggregateArgCols returns the columns in the aggInScope corresponding to
 // arguments to aggregate functions. If the aggregate has a filter, the column
 // corresponding to the filter's input will immediately follow the arguments.
 func (g *groupby) aggregateArgCols() []scopeColumn {
-	return g.aggInScope.cols[:len(g.aggInScope.cols)-len(g.groupStrs)]
+	return g.aggInScope.cols[:len(g.aggInScope.cols)-g.numGroupingCols()]
 }
 
 // getAggregateResultCols returns the columns in the aggOutScope corresponding
@@ -397,11 +428,20 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		// if FILTER (WHERE ...) was specified in the query.
 		// TODO(justin): add a norm rule to push these filters below GroupBy where
 		// possible.
-		if agg.filter != nil {
-			// Column containing filter expression is always after the argument
-			// columns (which have already been processed).
-			colID := argCols[0].id
-			argCols = argCols[1:]
+		if agg.filter != nil || g.groupingSetInputCol != 0 {
+			var filterCol *scopeColumn
+			var colID opt.ColumnID
+			if agg.filter != nil {
+				// Column containing filter expression is always after the argument
+				// columns (which have already been processed).
+				filterCol = &argCols[0]
+				colID = filterCol.id
+				argCols = argCols[1:]
+			}
+			if g.groupingSetInputCol != 0 {
+				// Ignore the row added for empty grouping sets.
+				colID = b.buildGroupingSetsAggFilter(g, filterCol)
+			}
 			variable := b.factory.ConstructVariable(colID)
 			aggCols[i].scalar = b.factory.ConstructAggFilter(aggCols[i].scalar, variable)
 		}
@@ -510,8 +550,12 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if hasGroupingSets(groupBy) {
+		b.buildGroupingSets(groupBy, selects, projectionsScope, fromScope, g.aggInScope)
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
 	}
 	g.buildingGroupingCols = false
 }
@@ -539,9 +583,35 @@ func (b *Builder) buildGroupingList(
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
 ) {
+	exprs, alias := b.resolveGrouping(groupBy, selects, projectionsScope, fromScope)
+
+	// Build each of the GROUP BY columns.
+	for _, e := range exprs {
+		// If a grouping column has already been added, don't add it again.
+		// GROUP BY a, a is semantically equivalent to GROUP BY a.
+		exprStr := symbolicExprStr(e)
+		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			continue
+		}
+
+		// Save a representation of the GROUP BY expression for validation of the
+		// SELECT and HAVING expressions. This enables queries such as:
+		//   SELECT x+y FROM t GROUP BY x+y
+		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
+		b.buildScalar(e, fromScope, aggInScope, col, nil)
+		fromScope.groupby.groupStrs[exprStr] = col
+	}
+}
+
+// resolveGrouping resolves the types of a GROUP BY expression, expanding stars
+// and flattening tuples. It returns the resulting grouping expressions along
+// with the alias of the SELECT expression that the GROUP BY expression refers
+// to, if any.
+func (b *Builder) resolveGrouping(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) (exprs []tree.TypedExpr, alias string) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
-	alias := ""
 
 	// Comment below pasted from PostgreSQL (findTargetListEntrySQL92 in
 	// src/backend/parser/parse_clause.c).
@@ -628,25 +698,8 @@ func (b *Builder) buildGrouping(
 	fromScope.context = exprKindGroupBy
 
 	// Resolve types, expand stars, and flatten tuples.
-	exprs := b.expandStarAndResolveType(groupBy, fromScope)
-	exprs = flattenTuples(exprs)
-
-	// Finally, build each of the GROUP BY columns.
-	for _, e := range exprs {
-		// If a grouping column has already been added, don't add it again.
-		// GROUP BY a, a is semantically equivalent to GROUP BY a.
-		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
-			continue
-		}
-
-		// Save a representation of the GROUP BY expression for validation of the
-		// SELECT and HAVING expressions. This enables queries such as:
-		//   SELECT x+y FROM t GROUP BY x+y
-		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
-		b.buildScalar(e, fromScope, aggInScope, col, nil)
-		fromScope.groupby.groupStrs[exprStr] = col
-	}
+	exprs = b.expandStarAndResolveType(groupBy, fromScope)
+	return flattenTuples(exprs), alias
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -966,6 +1019,10 @@ func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bo
 	// Check UNIQUE WITHOUT INDEX constraints.
 	for i := 0; i < tab.UniqueCount(); i++ {
 		uniqueConstraint := tab.Unique(i)
+		if uniqueConstraint.ExclusionElementCount() > 0 {
+			// Exclusion constraints do not make their columns a key of the table.
+			continue
+		}
 		var uniqueCols opt.ColSet
 		nullable := false
 		for j := 0; j < uniqueConstraint.ColumnCount(); j++ {
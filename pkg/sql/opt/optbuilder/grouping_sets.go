@@ -0,0 +1,440 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+// This file has builder code specific to GROUPING SETS, ROLLUP and CUBE, as
+// well as the GROUPING operation.
+//
+// A GROUP BY clause with grouping sets is built as a single aggregation over
+// an input that is replicated once per grouping set. For example:
+//
+//   SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)
+//
+// denotes the grouping sets (a, b), (a) and (). It is built as:
+//
+//   input:           t CROSS JOIN (VALUES (0), (1), (2)) AS v(grouping_set_id)
+//   pre-projection:  CASE WHEN grouping_set_id = 2 THEN NULL ELSE a END,
+//                    CASE WHEN grouping_set_id IN (1, 2) THEN NULL ELSE b END,
+//                    c
+//   aggregation:     group by the two CASE columns and grouping_set_id,
+//                    calculate sum(c)
+//
+// Since every grouping set has its own copy of the input rows, and the
+// grouping expressions that a grouping set does not include are replaced with
+// NULL in the rows of that grouping set, each group of the aggregation
+// corresponds to a group of exactly one grouping set. The grouping set ID
+// column ensures that groups of different grouping sets are never merged, even
+// if their grouping columns happen to be equal. It also allows GROUPING(...)
+// to be computed from the output of the aggregation.
+//
+// An empty grouping set must produce a row even if the input has no rows, like
+// an aggregation without GROUP BY. To that end, if there is an empty grouping
+// set, the input is right joined with the grouping set IDs instead, and the
+// NULL-extended rows are kept only for the empty grouping sets:
+//
+//   input:  (SELECT *, true AS input_row FROM t)
+//           RIGHT JOIN (VALUES (0), (1), (2)) AS v(grouping_set_id) ON true
+//           WHERE input_row OR grouping_set_id = 2
+//
+// The NULL-extended row only exists if t has no rows, and every aggregate is
+// filtered by input_row so that it ignores that row. See
+// buildGroupingSetsAggFilter.
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// maxGroupingSets is the maximum number of grouping sets that a GROUP BY
+	// clause can expand to. It is the same limit as in Postgres.
+	maxGroupingSets = 4096
+
+	// maxCubeElements is the maximum number of elements in a CUBE. It is the
+	// same limit as in Postgres.
+	maxCubeElements = 12
+
+	// maxGroupingArgs is the maximum number of arguments to a GROUPING
+	// operation, so that the result fits in an INT4 like in Postgres.
+	maxGroupingArgs = 31
+)
+
+var errTooManyGroupingSets = pgerror.Newf(
+	pgcode.ProgramLimitExceeded, "too many grouping sets present (maximum %d)", maxGroupingSets,
+)
+
+var errInvalidGroupingArgs = pgerror.New(
+	pgcode.Grouping, "arguments to GROUPING must be grouping expressions of the associated query level",
+)
+
+// hasGroupingSets returns true if the GROUP BY clause contains GROUPING SETS,
+// ROLLUP or CUBE.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandGroupingSets returns the grouping sets denoted by a GROUP BY clause.
+// The clause denotes the cartesian product of the grouping sets denoted by
+// each of its items, where an item which is an ordinary expression denotes a
+// single grouping set containing that expression.
+//
+// The expressions of the GROUP BY clause that are not grouping sets
+// themselves are returned as a list of leaves. Each grouping set is returned
+// as a list of indexes into the leaves.
+func expandGroupingSets(groupBy tree.GroupBy) (leaves []tree.Expr, sets [][]int) {
+	sets = [][]int{nil}
+	for _, item := range groupBy {
+		var itemSets [][]int
+		leaves, itemSets = expandGroupingSet(item, leaves)
+		if len(sets)*len(itemSets) > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		product := make([][]int, 0, len(sets)*len(itemSets))
+		for _, set := range sets {
+			for _, itemSet := range itemSets {
+				newSet := make([]int, 0, len(set)+len(itemSet))
+				newSet = append(newSet, set...)
+				product = append(product, append(newSet, itemSet...))
+			}
+		}
+		sets = product
+	}
+	return leaves, sets
+}
+
+// expandGroupingSet appends the leaf expressions of a GROUP BY item to leaves
+// and returns the grouping sets denoted by the item:
+//
+//   - ROLLUP (e1, ..., en) denotes the n+1 prefixes of e1, ..., en, from the
+//     longest to the empty one.
+//   - CUBE (e1, ..., en) denotes all 2^n subsets of e1, ..., en.
+//   - GROUPING SETS (...) denotes the concatenation of the grouping sets
+//     denoted by each of its elements.
+//   - Any other expression denotes a single grouping set containing the
+//     expression. A tuple is later flattened into its elements, so that
+//     (e1, e2) is a unit in a ROLLUP or CUBE, and () is the empty grouping
+//     set.
+func expandGroupingSet(item tree.Expr, leaves []tree.Expr) ([]tree.Expr, [][]int) {
+	gs, ok := item.(*tree.GroupingSet)
+	if !ok {
+		return append(leaves, item), [][]int{{len(leaves)}}
+	}
+	first, n := len(leaves), len(gs.Exprs)
+	var sets [][]int
+	switch gs.Kind {
+	case tree.Rollup:
+		leaves = append(leaves, gs.Exprs...)
+		sets = make([][]int, 0, n+1)
+		for k := n; k >= 0; k-- {
+			set := make([]int, k)
+			for i := range set {
+				set[i] = first + i
+			}
+			sets = append(sets, set)
+		}
+
+	case tree.Cube:
+		if n > maxCubeElements {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"CUBE is limited to %d elements", maxCubeElements))
+		}
+		leaves = append(leaves, gs.Exprs...)
+		sets = make([][]int, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set []int
+			for i := 0; i < n; i++ {
+				if mask&(1<<(n-1-i)) != 0 {
+					set = append(set, first+i)
+				}
+			}
+			sets = append(sets, set)
+		}
+
+	case tree.GroupingSets:
+		for _, e := range gs.Exprs {
+			var elemSets [][]int
+			leaves, elemSets = expandGroupingSet(e, leaves)
+			if len(sets)+len(elemSets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+			sets = append(sets, elemSets...)
+		}
+
+	default:
+		panic(errors.AssertionFailedf("unexpected grouping set kind %v", gs.Kind))
+	}
+	return leaves, sets
+}
+
+// buildGroupingSets builds the grouping columns for a GROUP BY clause that
+// contains GROUPING SETS, ROLLUP or CUBE. It is the counterpart of
+// buildGrouping; see the comment at the top of this file for an overview.
+// In addition to the grouping columns, the input in fromScope is replaced by
+// its cross join with the grouping set IDs.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
+) {
+	g := fromScope.groupby
+	leaves, sets := expandGroupingSets(groupBy)
+
+	// Resolve each leaf expression once, and deduplicate the resulting
+	// grouping expressions across all the grouping sets.
+	var exprs []tree.TypedExpr
+	var aliases []string
+	exprIdxs := make(map[string]int)
+	leafExprs := make([][]int, len(leaves))
+	for i, leaf := range leaves {
+		resolved, alias := b.resolveGrouping(leaf, selects, projectionsScope, fromScope)
+		for _, e := range resolved {
+			exprStr := symbolicExprStr(e)
+			idx, ok := exprIdxs[exprStr]
+			if !ok {
+				idx = len(exprs)
+				exprIdxs[exprStr] = idx
+				exprs = append(exprs, e)
+				aliases = append(aliases, alias)
+			}
+			leafExprs[i] = append(leafExprs[i], idx)
+		}
+	}
+
+	// Determine the grouping sets that exclude each grouping expression, and
+	// the grouping sets that are empty.
+	exclusions := make([]intsets.Fast, len(exprs))
+	var emptySets intsets.Fast
+	for setID, set := range sets {
+		var included intsets.Fast
+		for _, leaf := range set {
+			for _, idx := range leafExprs[leaf] {
+				included.Add(idx)
+			}
+		}
+		if included.Empty() {
+			emptySets.Add(setID)
+		}
+		for idx := range exprs {
+			if !included.Contains(idx) {
+				exclusions[idx].Add(setID)
+			}
+		}
+	}
+
+	// Replicate the input once per grouping set by cross joining it with the
+	// grouping set IDs.
+	idColName := scopeColName("").WithMetadataName("grouping_set_id")
+	idCol := *b.synthesizeColumn(fromScope, idColName, types.Int, nil /* expr */, nil /* scalar */)
+	rows := make(memo.ScalarListExpr, len(sets))
+	tupleTyp := types.MakeTuple([]*types.T{types.Int})
+	for i := range rows {
+		rows[i] = b.factory.ConstructTuple(memo.ScalarListExpr{b.constructGroupingSetID(i)}, tupleTyp)
+	}
+	values := b.factory.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{idCol.id},
+		ID:   b.factory.Metadata().NextUniqueID(),
+	})
+	if emptySets.Empty() {
+		fromScope.expr = b.factory.ConstructInnerJoin(
+			fromScope.expr, values, memo.TrueFilter, memo.EmptyJoinPrivate,
+		)
+	} else {
+		// Mark the input rows, so that the NULL-extended rows of the right join
+		// can be told apart from them.
+		inputColName := scopeColName("").WithMetadataName("grouping_set_input_row")
+		inputCol := b.synthesizeColumn(fromScope, inputColName, types.Bool, nil /* expr */, nil /* scalar */)
+		input := b.factory.ConstructProject(
+			fromScope.expr,
+			memo.ProjectionsExpr{b.factory.ConstructProjectionsItem(memo.TrueSingleton, inputCol.id)},
+			fromScope.expr.Relational().OutputCols,
+		)
+		join := b.factory.ConstructRightJoin(input, values, memo.TrueFilter, memo.EmptyJoinPrivate)
+		filter := b.factory.ConstructOr(
+			b.factory.ConstructVariable(inputCol.id),
+			b.constructGroupingSetIDCheck(idCol.id, emptySets),
+		)
+		fromScope.expr = b.factory.ConstructSelect(
+			join, memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)},
+		)
+		g.groupingSetInputCol = inputCol.id
+	}
+
+	// Build the grouping columns. Expressions that are excluded from some
+	// grouping sets are replaced with NULL in the rows of those grouping sets.
+	g.groupingSetExclusions = make(map[string]intsets.Fast, len(exprs))
+	for idx, e := range exprs {
+		exprStr := symbolicExprStr(e)
+		colName := scopeColName(tree.Name(aliases[idx]))
+		var col *scopeColumn
+		if exclusions[idx].Empty() {
+			col = aggInScope.addColumn(colName, e)
+			b.buildScalar(e, fromScope, aggInScope, col, nil)
+		} else {
+			scalar := b.buildScalar(e, fromScope, nil, nil, nil)
+			excluded := b.constructGroupingSetIDCheck(idCol.id, exclusions[idx])
+			when := b.factory.ConstructWhen(excluded, b.factory.ConstructNull(e.ResolvedType()))
+			scalar = b.factory.ConstructCase(memo.TrueSingleton, memo.ScalarListExpr{when}, scalar)
+			col = b.synthesizeColumn(aggInScope, colName, e.ResolvedType(), e, scalar)
+		}
+		g.groupStrs[exprStr] = col
+		g.groupingSetExclusions[exprStr] = exclusions[idx]
+	}
+
+	// The grouping set ID is the last grouping column.
+	aggInScope.appendColumn(&idCol)
+	g.groupingSetIDCol = idCol.id
+	g.numGroupingSets = len(sets)
+}
+
+// buildGroupingSetsAggFilter returns the column by which the input rows of an
+// aggregate must be filtered if the GROUP BY clause contains an empty grouping
+// set, so that the aggregate ignores the NULL-extended row which is added for
+// the empty grouping sets when the input has no rows. filter is the column for
+// the FILTER clause of the aggregate, if any. The returned column is added to
+// the extra columns of the aggInScope, so that it is rendered by the
+// pre-projection.
+func (b *Builder) buildGroupingSetsAggFilter(g *groupby, filter *scopeColumn) opt.ColumnID {
+	if filter == nil {
+		g.aggInScope.extraCols = append(g.aggInScope.extraCols, scopeColumn{
+			name: scopeColName(""),
+			typ:  types.Bool,
+			id:   g.groupingSetInputCol,
+		})
+		return g.groupingSetInputCol
+	}
+	scalar := filter.scalar
+	if scalar == nil {
+		scalar = b.factory.ConstructVariable(filter.id)
+	}
+	colName := scopeColName("").WithMetadataName("grouping_set_filter")
+	g.aggInScope.extraCols = append(g.aggInScope.extraCols, scopeColumn{
+		name:   colName,
+		typ:    types.Bool,
+		id:     b.factory.Metadata().AddColumn(colName.MetadataName(), types.Bool),
+		scalar: b.factory.ConstructAnd(scalar, b.factory.ConstructVariable(g.groupingSetInputCol)),
+	})
+	return g.aggInScope.extraCols[len(g.aggInScope.extraCols)-1].id
+}
+
+// constructGroupingSetID constructs the constant value of a grouping set ID.
+func (b *Builder) constructGroupingSetID(setID int) opt.ScalarExpr {
+	return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(setID)), types.Int)
+}
+
+// constructGroupingSetIDCheck constructs a boolean expression that is true
+// if the grouping set ID in idCol is one of the given IDs.
+func (b *Builder) constructGroupingSetIDCheck(
+	idCol opt.ColumnID, setIDs intsets.Fast,
+) opt.ScalarExpr {
+	variable := b.factory.ConstructVariable(idCol)
+	if setIDs.Len() == 1 {
+		setID, _ := setIDs.Next(0)
+		return b.factory.ConstructEq(variable, b.constructGroupingSetID(setID))
+	}
+	elems := make(memo.ScalarListExpr, 0, setIDs.Len())
+	elemTypes := make([]*types.T, 0, setIDs.Len())
+	setIDs.ForEach(func(setID int) {
+		elems = append(elems, b.constructGroupingSetID(setID))
+		elemTypes = append(elemTypes, types.Int)
+	})
+	return b.factory.ConstructIn(variable, b.factory.ConstructTuple(elems, types.MakeTuple(elemTypes)))
+}
+
+// groupingInfo stores information about a GROUPING operation.
+type groupingInfo struct {
+	*tree.GroupingExpr
+
+	// args contains the typed arguments of the operation.
+	args []tree.TypedExpr
+}
+
+// Walk is part of the tree.Expr interface.
+func (g *groupingInfo) Walk(v tree.Visitor) tree.Expr {
+	return g
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (g *groupingInfo) TypeCheck(
+	ctx context.Context, semaCtx *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	return g, nil
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (g *groupingInfo) Eval(_ context.Context, _ tree.ExprEvaluator) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("groupingInfo must be replaced before evaluation"))
+}
+
+// ResolvedType is part of the tree.TypedExpr interface.
+func (g *groupingInfo) ResolvedType() *types.T {
+	return types.Int
+}
+
+var _ tree.Expr = &groupingInfo{}
+var _ tree.TypedExpr = &groupingInfo{}
+
+// buildGroupingOp builds the scalar expression for a GROUPING operation. The
+// result is a bit mask in which the bit for an argument is set if the
+// argument is not included in the grouping set of the current row. The last
+// argument corresponds to the least significant bit.
+func (b *Builder) buildGroupingOp(info *groupingInfo, inScope *scope) opt.ScalarExpr {
+	if inScope.inAgg {
+		panic(pgerror.New(pgcode.Grouping,
+			"aggregate function calls cannot contain grouping operations"))
+	}
+	g := inScope.groupby
+	if g == nil {
+		panic(errInvalidGroupingArgs)
+	}
+
+	numSets := 1
+	if g.groupingSetIDCol != 0 {
+		numSets = g.numGroupingSets
+	}
+	results := make([]int, numSets)
+	for i, arg := range info.args {
+		exprStr := symbolicExprStr(arg)
+		if _, ok := g.groupStrs[exprStr]; !ok {
+			panic(errInvalidGroupingArgs)
+		}
+		bit := 1 << (len(info.args) - 1 - i)
+		g.groupingSetExclusions[exprStr].ForEach(func(setID int) {
+			results[setID] |= bit
+		})
+	}
+
+	if g.groupingSetIDCol == 0 {
+		return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(results[0])), types.Int)
+	}
+	var whens memo.ScalarListExpr
+	for setID, res := range results {
+		if res != 0 {
+			val := b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(res)), types.Int)
+			whens = append(whens, b.factory.ConstructWhen(b.constructGroupingSetID(setID), val))
+		}
+	}
+	zero := b.factory.ConstructConstVal(tree.NewDInt(0), types.Int)
+	if len(whens) == 0 {
+		return zero
+	}
+	return b.factory.ConstructCase(b.factory.ConstructVariable(g.groupingSetIDCol), whens, zero)
+}
@@ -326,6 +326,10 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 			// derived from the primary index as the join condition.
 			mb.buildInputForUpsert(inScope, ins.Table, nil /* onConflict */, nil /* whereClause */)
 
+			// Record the columns which are updated when a conflict occurs, so
+			// that UPDATE OF triggers fire for them.
+			mb.addUpsertTargetCols()
+
 			// Add additional columns for computed expressions that may depend on any
 			// updated columns, as well as mutation columns with default values.
 			mb.addSynthesizedColsForUpdate()
@@ -365,6 +369,8 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //     values specified for them.
 //  4. Each update value is the same as the corresponding insert value.
 //  5. There are no inbound foreign keys containing non-key columns.
+//  6. There are no triggers on the table. Triggers need the existing values,
+//     and need to know whether a row was inserted or updated.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -376,6 +382,11 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	// #6: Triggers need the existing rows.
+	if mb.tab.TriggerCount() > 0 {
+		return true
+	}
+
 	// If there are any implicit partitioning columns in the primary index,
 	// these columns will need to be fetched.
 	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
@@ -669,6 +680,10 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.insertColIDs)
 
+	// Apply BEFORE triggers, which may modify the inserted values. This must
+	// happen before computed columns are added.
+	mb.buildBeforeRowTriggers(tree.TriggerEventInsert)
+
 	// Now add all computed columns.
 	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
 
@@ -693,11 +708,15 @@ func (mb *mutationBuilder) buildInsert(returning *tree.ReturningExprs) {
 
 	mb.buildFKChecksForInsert()
 
+	mb.buildAfterTriggers(tree.TriggerEventInsert)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fastPathUniqueChecks, mb.fkChecks, private,
 	)
 
+	mb.buildBeforeStatementTriggers(tree.TriggerEventInsert)
+
 	mb.buildReturning(returning)
 }
 
@@ -924,14 +943,38 @@ func (mb *mutationBuilder) buildUpsert(returning *tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpsert()
 
+	mb.buildAfterTriggers(tree.TriggerEventInsert)
+	mb.buildAfterTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
 
+	// BEFORE INSERT statement triggers are executed before BEFORE UPDATE
+	// statement triggers, so they are built last.
+	mb.buildBeforeStatementTriggers(tree.TriggerEventUpdate)
+	mb.buildBeforeStatementTriggers(tree.TriggerEventInsert)
+
 	mb.buildReturning(returning)
 }
 
+// addUpsertTargetCols adds the columns which are updated by an UPSERT when a
+// conflict occurs to the list of target columns. It must be called after
+// setUpsertCols and buildInputForUpsert.
+func (mb *mutationBuilder) addUpsertTargetCols() {
+	for i := range mb.updateColIDs {
+		if mb.updateColIDs[i] == 0 {
+			continue
+		}
+		colID := mb.tabID.ColumnID(i)
+		if !mb.targetColSet.Contains(colID) {
+			mb.targetColSet.Add(colID)
+			mb.targetColList = append(mb.targetColList, colID)
+		}
+	}
+}
+
 // projectUpsertColumns projects a set of merged columns that will be either
 // inserted into the target table, or else used to update an existing row,
 // depending on whether the canary column is null. For example:
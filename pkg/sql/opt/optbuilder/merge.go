@@ -0,0 +1,642 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+)
+
+// duplicateMergeErrText is error text used when a target row is matched by
+// more than one source row which is not skipped by a MERGE statement.
+const duplicateMergeErrText = "MERGE command cannot affect row a second time"
+
+// mergeInput describes the input of the mutations of a MERGE statement. The
+// input is a CTE with the columns of the source, followed by the columns of the
+// target table, followed by the action column. The action column contains the
+// 1-based number of the WHEN clause that applies to the row.
+type mergeInput struct {
+	cte           *cteSource
+	cols          []scopeColumn
+	numSourceCols int
+	numFetchCols  int
+}
+
+// buildMerge builds a memo group for a MERGE statement. The source and the
+// target table are joined once, and the WHEN clause that applies to each row
+// of the join is computed in a single projection:
+//
+//	WITH input AS MATERIALIZED (
+//	  SELECT <source-cols>, <target-cols>, CASE
+//	    WHEN <target-pk> IS NULL THEN CASE WHEN <not-matched-cond> THEN 1 ... ELSE 0 END
+//	    ELSE CASE WHEN <matched-cond> THEN 2 ... ELSE 0 END
+//	  END AS action
+//	  FROM <source> LEFT JOIN <target> ON <on-cond>
+//	  WHERE action != 0
+//	)
+//
+// Rows that are not acted upon are filtered out, and the remaining rows must be
+// distinct on the primary key of the target table, like in Postgres. There is
+// one Delete, Update and Insert operator for the DELETE, UPDATE and INSERT
+// clauses of the statement respectively, each of which reads the rows of the
+// input with the matching actions. When there are several WHEN clauses with the
+// same action, the values of the mutation are chosen with a CASE expression on
+// the action column.
+//
+// If the statement performs a single kind of action, the mutation is the root
+// of the statement. Otherwise the mutations are built as CTEs, and the result
+// of the statement is the UNION ALL of their RETURNING rows, or the number of
+// affected rows if there is no RETURNING clause.
+func (b *Builder) buildMerge(mrg *tree.Merge, inScope *scope) (outScope *scope) {
+	// Find which table we're working on, check the permissions.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(mrg.Table, privilege.SELECT)
+
+	if tab.IsVirtualTable() {
+		panic(pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot merge into view \"%s\"", tab.Name(),
+		))
+	}
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	// Collect the numbers of the WHEN clauses of each action.
+	var deletes, updates, inserts []int
+	for i, when := range mrg.Whens {
+		switch when.Action {
+		case tree.MergeActionDelete:
+			deletes = append(deletes, i+1)
+		case tree.MergeActionUpdate:
+			updates = append(updates, i+1)
+		case tree.MergeActionInsert:
+			inserts = append(inserts, i+1)
+		}
+	}
+
+	// Only check the privileges of the actions that can be performed.
+	if len(deletes) > 0 {
+		b.checkPrivilege(depName, tab, privilege.DELETE)
+	}
+	if len(updates) > 0 {
+		b.checkPrivilege(depName, tab, privilege.UPDATE)
+	}
+	if len(inserts) > 0 {
+		b.checkPrivilege(depName, tab, privilege.INSERT)
+	}
+
+	// Check if this table has already been mutated in another subquery.
+	b.checkMultipleMutations(tab, generalMutation)
+
+	input := b.buildMergeInput(mrg, tab, alias, inScope)
+
+	// Build the mutations. When more than one mutation is needed, the results
+	// of the mutations are combined, so each of them must return rows.
+	numMutations := 0
+	for _, actions := range [][]int{deletes, updates, inserts} {
+		if len(actions) > 0 {
+			numMutations++
+		}
+	}
+	var returning *tree.ReturningExprs
+	if resultsNeeded(mrg.Returning) {
+		returning = mrg.Returning.(*tree.ReturningExprs)
+	} else if numMutations > 1 {
+		returning = &tree.ReturningExprs{{Expr: tree.DBoolTrue}}
+	}
+	var mutations []*scope
+	if len(deletes) > 0 {
+		mutations = append(mutations,
+			b.buildMergeDelete(tab, alias, input, deletes, returning, inScope))
+	}
+	if len(updates) > 0 {
+		mutations = append(mutations,
+			b.buildMergeUpdate(mrg, tab, alias, input, updates, returning, inScope))
+	}
+	if len(inserts) > 0 {
+		mutations = append(mutations,
+			b.buildMergeInsert(mrg, tab, alias, input, inserts, returning, inScope))
+	}
+
+	switch len(mutations) {
+	case 0:
+		// Every WHEN clause is DO NOTHING, so no row is affected.
+		inputScope := b.scanMergeInput(input, nil /* actions */, inScope)
+		if returning != nil {
+			outScope = inputScope.replace()
+			b.analyzeReturningList(returning, nil /* desiredTypes */, inputScope, outScope)
+			b.buildProjectionList(inputScope, outScope)
+			b.constructProjectForScope(inputScope, outScope)
+			return outScope
+		}
+		return b.buildMergeRowCount(inputScope, inScope)
+
+	case 1:
+		return mutations[0]
+	}
+
+	// The rows updated and deleted are disjoint, since the input has at most
+	// one row for each row of the target table, so the results of the
+	// mutations can simply be concatenated.
+	outScope = b.scanMergeCTE(b.addMergeCTE(mutations[0], mrg), mutations[0].cols, inScope)
+	for _, mutation := range mutations[1:] {
+		rightScope := b.scanMergeCTE(b.addMergeCTE(mutation, mrg), mutation.cols, inScope)
+		outScope = b.buildSetOp(tree.UnionOp, true /* all */, inScope, outScope, rightScope)
+	}
+	if resultsNeeded(mrg.Returning) {
+		return outScope
+	}
+	return b.buildMergeRowCount(outScope, inScope)
+}
+
+// buildMergeInput builds the input of the mutations of a MERGE statement, and
+// registers it as a CTE. See buildMerge for details.
+func (b *Builder) buildMergeInput(
+	mrg *tree.Merge, tab cat.Table, alias tree.TableName, inScope *scope,
+) mergeInput {
+	var indexFlags *tree.IndexFlags
+	if source, ok := mrg.Table.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
+		indexFlags = source.IndexFlags
+		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
+	}
+
+	// NOTE: Include mutation columns, but be careful to never use them for any
+	//       reason other than as "fetch columns". See buildScan comment.
+	fetchScope := b.buildScan(
+		b.addTable(tab, &alias),
+		tableOrdinals(tab, columnKinds{
+			includeMutations: true,
+			includeSystem:    true,
+			includeInverted:  false,
+		}),
+		indexFlags,
+		nil, /* sample */
+		noRowLocking,
+		inScope,
+		false, /* disableNotVisibleIndex */
+	)
+	sourceScope := b.buildDataSource(mrg.Source, nil /* indexFlags */, nil /* sample */, noLocking, inScope)
+
+	// Check that the same table name is not used multiple times.
+	b.validateJoinTableNames(sourceScope, fetchScope)
+
+	joinScope := inScope.push()
+	joinScope.appendColumnsFromScope(sourceScope)
+	joinScope.appendColumnsFromScope(fetchScope)
+
+	// Do not allow special functions in the ON clause.
+	on := b.resolveAndBuildScalar(
+		mrg.On, types.Bool, exprKindOn,
+		tree.RejectGenerators|tree.RejectWindowApplications|tree.RejectProcedures, joinScope,
+	)
+	filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(on)}
+
+	// Source rows without a matching target row are only needed if there is a
+	// NOT MATCHED clause.
+	hasNotMatched := false
+	for _, when := range mrg.Whens {
+		if !when.Matched {
+			hasNotMatched = true
+		}
+	}
+	if hasNotMatched {
+		joinScope.expr = b.factory.ConstructLeftJoin(
+			sourceScope.expr, fetchScope.expr, filters, memo.EmptyJoinPrivate,
+		)
+	} else {
+		joinScope.expr = b.factory.ConstructInnerJoin(
+			sourceScope.expr, fetchScope.expr, filters, memo.EmptyJoinPrivate,
+		)
+	}
+
+	// Build the action column. The first column of the primary key is never
+	// NULL in the target table, so it is NULL in the join if and only if the
+	// source row has no match.
+	buildActions := func(matched bool, condScope *scope) opt.ScalarExpr {
+		var whens memo.ScalarListExpr
+		for i, when := range mrg.Whens {
+			if when.Matched != matched {
+				continue
+			}
+			action := 0
+			if when.Action != tree.MergeActionDoNothing {
+				action = i + 1
+			}
+			cond := opt.ScalarExpr(memo.TrueSingleton)
+			if when.Cond != nil {
+				cond = b.resolveAndBuildScalar(
+					when.Cond, types.Bool, exprKindMergeWhen, tree.RejectSpecial, condScope,
+				)
+			}
+			whens = append(whens, b.factory.ConstructWhen(
+				cond, b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int),
+			))
+		}
+		doNothing := b.factory.ConstructConstVal(tree.NewDInt(0), types.Int)
+		if len(whens) == 0 {
+			return doNothing
+		}
+		return b.factory.ConstructCase(memo.TrueSingleton, whens, doNothing)
+	}
+	canary := fetchScope.getColumnForTableOrdinal(tab.Index(cat.PrimaryIndex).Column(0).Ordinal())
+	// NOT MATCHED conditions can only refer to the columns of the source.
+	action := b.factory.ConstructCase(
+		memo.TrueSingleton,
+		memo.ScalarListExpr{b.factory.ConstructWhen(
+			b.factory.ConstructIs(b.factory.ConstructVariable(canary.id), memo.NullSingleton),
+			buildActions(false /* matched */, sourceScope),
+		)},
+		buildActions(true /* matched */, joinScope),
+	)
+
+	projectionsScope := joinScope.replace()
+	projectionsScope.appendColumnsFromScope(joinScope)
+	actionCol := b.synthesizeColumn(
+		projectionsScope, scopeColName("").WithMetadataName("merge_action"), types.Int, nil, action,
+	)
+	actionCol.visibility = inaccessible
+	b.constructProjectForScope(joinScope, projectionsScope)
+	projectionsScope.expr = b.factory.ConstructSelect(
+		projectionsScope.expr,
+		memo.FiltersExpr{b.factory.ConstructFiltersItem(b.factory.ConstructNe(
+			b.factory.ConstructVariable(actionCol.id),
+			b.factory.ConstructConstVal(tree.NewDInt(0), types.Int),
+		))},
+	)
+
+	// Each target row can be acted upon at most once.
+	var pkCols opt.ColSet
+	primaryIndex := tab.Index(cat.PrimaryIndex)
+	for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+		pkCols.Add(fetchScope.getColumnForTableOrdinal(primaryIndex.Column(i).Ordinal()).id)
+	}
+	outScope := b.buildDistinctOn(
+		pkCols, projectionsScope, true /* nullsAreDistinct */, duplicateMergeErrText,
+	)
+
+	cte := b.addMergeCTE(outScope, mrg)
+	cte.mtr = tree.CTEMaterializeAlways
+	return mergeInput{
+		cte:           cte,
+		cols:          outScope.cols,
+		numSourceCols: len(sourceScope.cols),
+		numFetchCols:  len(fetchScope.cols),
+	}
+}
+
+// addMergeCTE registers the expression of the given scope as a CTE of the
+// MERGE statement.
+func (b *Builder) addMergeCTE(s *scope, mrg *tree.Merge) *cteSource {
+	id := b.factory.Memo().NextWithID()
+	b.factory.Metadata().AddWithBinding(id, s.expr)
+	cte := &cteSource{
+		name:         tree.AliasClause{},
+		cols:         s.makePresentationWithHiddenCols(),
+		originalExpr: mrg,
+		expr:         s.expr,
+		id:           id,
+	}
+	b.addCTE(cte)
+	return cte
+}
+
+// scanMergeCTE builds a WithScan of the given CTE with new column IDs. The
+// returned scope has a copy of the given columns of the CTE.
+func (b *Builder) scanMergeCTE(cte *cteSource, cols []scopeColumn, inScope *scope) *scope {
+	md := b.factory.Metadata()
+	inCols := make(opt.ColList, len(cte.cols))
+	outCols := make(opt.ColList, len(cte.cols))
+	for i, col := range cte.cols {
+		inCols[i] = col.ID
+		outCols[i] = md.AddColumn(col.Alias, md.ColumnMeta(col.ID).Type)
+	}
+
+	outScope := inScope.push()
+	// Similar to appendColumnsFromScope, but with re-numbering the column IDs.
+	for i, col := range cols {
+		col.scalar = nil
+		col.id = outCols[i]
+		outScope.cols = append(outScope.cols, col)
+	}
+	outScope.expr = b.factory.ConstructWithScan(&memo.WithScanPrivate{
+		With:    cte.id,
+		Name:    string(cte.name.Alias),
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      md.NextUniqueID(),
+		Mtr:     cte.mtr,
+	})
+	return outScope
+}
+
+// scanMergeInput builds a scan of the input of a MERGE statement which only
+// returns the rows for the given actions. All rows are returned if no action is
+// given.
+func (b *Builder) scanMergeInput(input mergeInput, actions []int, inScope *scope) *scope {
+	outScope := b.scanMergeCTE(input.cte, input.cols, inScope)
+	if len(actions) == 0 {
+		return outScope
+	}
+	actionCol := b.factory.ConstructVariable(outScope.cols[len(outScope.cols)-1].id)
+	var filter opt.ScalarExpr
+	for _, action := range actions {
+		eq := b.factory.ConstructEq(
+			actionCol, b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int),
+		)
+		if filter == nil {
+			filter = eq
+		} else {
+			filter = b.factory.ConstructOr(filter, eq)
+		}
+	}
+	outScope.expr = b.factory.ConstructSelect(
+		outScope.expr, memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)},
+	)
+	return outScope
+}
+
+// initMergeMutation initializes the mutation builder for one of the mutations
+// of a MERGE statement, using the rows of the input with the given actions as
+// the input of the mutation.
+func (mb *mutationBuilder) initMergeMutation(
+	b *Builder,
+	opName string,
+	tab cat.Table,
+	alias tree.TableName,
+	input mergeInput,
+	actions []int,
+	inScope *scope,
+) {
+	mb.init(b, opName, tab, alias)
+	mb.outScope = b.scanMergeInput(input, actions, inScope)
+
+	fetchCols := mb.outScope.cols[input.numSourceCols : input.numSourceCols+input.numFetchCols]
+	mb.fetchScope = b.allocScope()
+	mb.fetchScope.appendColumns(fetchCols)
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(fetchCols)
+}
+
+// mergeActionCol returns the action column of the input of a mutation of a
+// MERGE statement.
+func (mb *mutationBuilder) mergeActionCol() *scopeColumn {
+	return &mb.outScope.cols[len(mb.outScope.cols)-1]
+}
+
+// buildMergeDelete builds the Delete operator for the DELETE clauses of a
+// MERGE statement.
+func (b *Builder) buildMergeDelete(
+	tab cat.Table,
+	alias tree.TableName,
+	input mergeInput,
+	actions []int,
+	returning *tree.ReturningExprs,
+	inScope *scope,
+) *scope {
+	var mb mutationBuilder
+	mb.initMergeMutation(b, "delete", tab, alias, input, actions, inScope)
+	mb.buildDelete(returning)
+	return mb.outScope
+}
+
+// buildMergeUpdate builds the Update operator for the UPDATE clauses of a
+// MERGE statement. A column which is not assigned by all of the UPDATE clauses
+// keeps its value for the rows of the other clauses.
+func (b *Builder) buildMergeUpdate(
+	mrg *tree.Merge,
+	tab cat.Table,
+	alias tree.TableName,
+	input mergeInput,
+	actions []int,
+	returning *tree.ReturningExprs,
+	inScope *scope,
+) *scope {
+	var mb mutationBuilder
+	mb.initMergeMutation(b, "update", tab, alias, input, actions, inScope)
+
+	// Collect the value assigned to each target column by each clause.
+	values := make(map[int][]mergeValue)
+	for _, action := range actions {
+		var assigned intsets.Fast
+		for _, set := range mrg.Whens[action-1].Exprs {
+			exprs := tree.Exprs{set.Expr}
+			if set.Tuple {
+				t, ok := set.Expr.(*tree.Tuple)
+				if !ok {
+					panic(unimplemented.Newf("merge update tuple",
+						"source for a multiple-column UPDATE item in MERGE must be a ROW() expression; not supported: %T",
+						set.Expr))
+				}
+				if len(set.Names) != len(t.Exprs) {
+					panic(pgerror.Newf(pgcode.Syntax,
+						"number of columns (%d) does not match number of values (%d)",
+						len(set.Names), len(t.Exprs)))
+				}
+				exprs = t.Exprs
+			}
+			for i, name := range set.Names {
+				ord := mb.findMergeTargetCol(name)
+				if assigned.Contains(ord) {
+					panic(pgerror.Newf(pgcode.Syntax,
+						"multiple assignments to the same column %q", name))
+				}
+				assigned.Add(ord)
+				if _, ok := values[ord]; !ok {
+					mb.addTargetCol(ord)
+				}
+				values[ord] = append(values[ord], mergeValue{action: action, expr: exprs[i]})
+			}
+		}
+	}
+
+	updateExprs := make(tree.UpdateExprs, len(mb.targetColList))
+	for i, colID := range mb.targetColList {
+		ord := mb.tabID.ColumnOrdinal(colID)
+		var expr tree.Expr
+		if len(actions) == 1 {
+			expr = values[ord][0].expr
+		} else {
+			expr = mb.buildMergeCase(values[ord], ord, mb.fetchScope.getColumnForTableOrdinal(ord))
+		}
+		updateExprs[i] = &tree.UpdateExpr{Expr: expr}
+	}
+	mb.addUpdateCols(updateExprs)
+
+	mb.buildUpdate(returning)
+	return mb.outScope
+}
+
+// buildMergeInsert builds the Insert operator for the INSERT clauses of a
+// MERGE statement. The values of an INSERT clause can only refer to the
+// columns of the source.
+func (b *Builder) buildMergeInsert(
+	mrg *tree.Merge,
+	tab cat.Table,
+	alias tree.TableName,
+	input mergeInput,
+	actions []int,
+	returning *tree.ReturningExprs,
+	inScope *scope,
+) *scope {
+	var mb mutationBuilder
+	mb.initMergeMutation(b, "insert", tab, alias, input, actions, inScope)
+
+	// Collect the value assigned to each target column by each clause. Columns
+	// which are only assigned DEFAULT are not targeted, so that they are
+	// synthesized like any other column with a default value.
+	values := make(map[int][]mergeValue)
+	var targetOrds []int
+	for _, action := range actions {
+		when := mrg.Whens[action-1]
+		var ords []int
+		if len(when.Columns) > 0 {
+			for _, name := range when.Columns {
+				ords = append(ords, mb.findMergeTargetCol(name))
+			}
+		} else {
+			for i, n := 0, tab.ColumnCount(); i < n && len(ords) < len(when.Values); i++ {
+				if col := tab.Column(i); col.Kind() == cat.Ordinary && col.Visibility() == cat.Visible {
+					ords = append(ords, i)
+				}
+			}
+		}
+		mb.checkNumCols(len(ords), len(when.Values))
+
+		var assigned intsets.Fast
+		for i, ord := range ords {
+			if assigned.Contains(ord) {
+				panic(pgerror.Newf(pgcode.Syntax,
+					"multiple assignments to the same column %q", tab.Column(ord).ColName()))
+			}
+			assigned.Add(ord)
+			expr := when.Values[i]
+			if _, ok := expr.(tree.DefaultVal); !ok {
+				if col := tab.Column(ord); col.IsGeneratedAlwaysAsIdentity() {
+					panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnOverrideError(string(col.ColName())))
+				}
+				if _, ok := values[ord]; !ok {
+					mb.addTargetCol(ord)
+					targetOrds = append(targetOrds, ord)
+				}
+			}
+			values[ord] = append(values[ord], mergeValue{action: action, expr: expr})
+		}
+	}
+
+	// Project the values to insert. Only the columns of the source are
+	// accessible.
+	valuesScope := mb.outScope.replace()
+	valuesScope.appendColumns(mb.outScope.cols[:input.numSourceCols])
+	valuesScope.appendColumns(mb.outScope.cols[len(mb.outScope.cols)-1:])
+
+	scalarProps := &b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	b.semaCtx.Properties.Require("MERGE INSERT", tree.RejectSpecial)
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for _, ord := range targetOrds {
+		targetCol := tab.Column(ord)
+		var expr tree.Expr
+		if len(actions) == 1 {
+			expr = values[ord][0].expr
+			if _, ok := expr.(tree.DefaultVal); ok {
+				expr = mb.parseDefaultExpr(mb.tabID.ColumnID(ord))
+			}
+		} else {
+			expr = mb.buildMergeCase(values[ord], ord, mb.parseDefaultExpr(mb.tabID.ColumnID(ord)))
+		}
+		texpr := valuesScope.resolveType(expr, targetCol.DatumType())
+		scopeCol := projectionsScope.addColumn(scopeColName(targetCol.ColName()), texpr)
+		b.buildScalar(texpr, valuesScope, projectionsScope, scopeCol, nil)
+		mb.insertColIDs[ord] = scopeCol.id
+	}
+	b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Add assignment casts for insert columns.
+	mb.addAssignmentCasts(mb.insertColIDs)
+	mb.inputForInsertExpr = mb.outScope.expr
+
+	// Add default and computed columns that were not explicitly specified by
+	// name or implicitly targeted by input columns.
+	mb.addSynthesizedColsForInsert()
+
+	mb.buildInsert(returning)
+	return mb.outScope
+}
+
+// mergeValue is the value assigned to a column by a WHEN clause of a MERGE
+// statement.
+type mergeValue struct {
+	action int
+	expr   tree.Expr
+}
+
+// buildMergeCase returns a CASE expression on the action column of the input
+// which chooses the value assigned to the given column by the clause of each
+// row, or the given expression for the rows of the other clauses.
+func (mb *mutationBuilder) buildMergeCase(
+	values []mergeValue, ord int, orElse tree.Expr,
+) tree.Expr {
+	whens := make([]*tree.When, len(values))
+	for i, v := range values {
+		expr := v.expr
+		if _, ok := expr.(tree.DefaultVal); ok {
+			expr = mb.parseDefaultExpr(mb.tabID.ColumnID(ord))
+		}
+		whens[i] = &tree.When{Cond: tree.NewDInt(tree.DInt(v.action)), Val: expr}
+	}
+	return &tree.CaseExpr{Expr: mb.mergeActionCol(), Whens: whens, Else: orElse}
+}
+
+// findMergeTargetCol returns the ordinal of the named target column of an
+// UPDATE or INSERT clause of a MERGE statement.
+func (mb *mutationBuilder) findMergeTargetCol(name tree.Name) int {
+	ord := findPublicTableColumnByName(mb.tab, name)
+	if ord == -1 {
+		panic(colinfo.NewUndefinedColumnError(string(name)))
+	}
+	// System columns are invalid target columns.
+	if mb.tab.Column(ord).Kind() == cat.System {
+		panic(pgerror.Newf(pgcode.InvalidColumnReference, "cannot modify system column %q", name))
+	}
+	return ord
+}
+
+// buildMergeRowCount returns a scope with the number of rows of the given
+// scope, which is the result of a MERGE statement without RETURNING clause.
+func (b *Builder) buildMergeRowCount(rowsScope, inScope *scope) *scope {
+	outScope := inScope.push()
+	countCol := b.synthesizeColumn(outScope, scopeColName("count"), types.Int, nil, nil)
+	outScope.expr = b.factory.ConstructScalarGroupBy(
+		rowsScope.expr,
+		memo.AggregationsExpr{b.factory.ConstructAggregationsItem(
+			b.factory.ConstructCountRows(), countCol.id,
+		)},
+		&memo.GroupingPrivate{},
+	)
+	return outScope
+}
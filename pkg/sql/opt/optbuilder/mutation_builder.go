@@ -304,6 +304,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 			includeInverted:  false,
 		}),
 		indexFlags,
+		nil, /* sample */
 		noRowLocking,
 		inScope,
 		false, /* disableNotVisibleIndex */
@@ -415,6 +416,7 @@ func (mb *mutationBuilder) buildInputForDelete(
 			includeInverted:  false,
 		}),
 		indexFlags,
+		nil, /* sample */
 		noRowLocking,
 		inScope,
 		false, /* disableNotVisibleIndex */
@@ -1402,8 +1404,10 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 		targetType := mb.tab.Column(ord).DatumType()
 
 		// An assignment cast is not necessary if the source and target types
-		// are identical.
-		if srcType.Identical(targetType) {
+		// are identical, unless the target type is a domain. The cast checks the
+		// constraints of the domain, which may not hold for values of the domain
+		// type, like placeholders.
+		if srcType.Identical(targetType) && !targetType.IsDomain() {
 			continue
 		}
 
@@ -79,6 +79,10 @@ func (mb *mutationBuilder) findArbiters(onConflict *tree.OnConflict) arbiterSet
 		for i, uc := 0, mb.tab.UniqueCount(); i < uc; i++ {
 			constraint := mb.tab.Unique(i)
 			if constraint.Name() == string(onConflict.Constraint) {
+				if constraint.ExclusionElementCount() > 0 {
+					panic(pgerror.Newf(pgcode.FeatureNotSupported,
+						"exclusion constraint %q cannot be used as an arbiter", onConflict.Constraint))
+				}
 				if _, partial := constraint.Predicate(); partial {
 					panic(partialIndexArbiterError(onConflict, mb.tab.Name()))
 				}
@@ -166,7 +170,7 @@ func (mb *mutationBuilder) inferArbitersFromConflictOrds(
 			}
 		}
 		for uc, ucCount := 0, mb.tab.UniqueCount(); uc < ucCount; uc++ {
-			if mb.tab.Unique(uc).WithoutIndex() {
+			if u := mb.tab.Unique(uc); u.WithoutIndex() && u.ExclusionElementCount() == 0 {
 				arbiters.AddUniqueConstraint(uc)
 			}
 		}
@@ -224,6 +228,10 @@ func (mb *mutationBuilder) inferArbitersFromConflictOrds(
 			// Unique constraints with an index were handled above.
 			continue
 		}
+		if uniqueConstraint.ExclusionElementCount() > 0 {
+			// Exclusion constraints cannot be arbiters.
+			continue
+		}
 
 		// Determine whether the conflict columns match the columns in the
 		// unique constraint. If not, the constraint cannot be an arbiter. We
@@ -336,6 +344,7 @@ func (mb *mutationBuilder) buildAntiJoinForDoNothingArbiter(
 			includeInverted:  false,
 		}),
 		indexFlags,
+		nil, /* sample */
 		locking,
 		inScope,
 		true, /* disableNotVisibleIndex */
@@ -474,6 +483,7 @@ func (mb *mutationBuilder) buildLeftJoinForUpsertArbiter(
 			includeInverted:  false,
 		}),
 		indexFlags,
+		nil, /* sample */
 		locking,
 		inScope,
 		true, /* disableNotVisibleIndex */
@@ -691,6 +701,7 @@ func (h *arbiterPredicateHelper) tableScope() *scope {
 				includeInverted:  false,
 			}),
 			nil, /* indexFlags */
+			nil, /* sample */
 			noRowLocking,
 			h.mb.b.allocScope(),
 			false, /* disableNotVisibleIndex */
@@ -663,6 +663,7 @@ func (h *fkCheckHelper) buildOtherTableScan(parent bool) (outScope *scope, tabMe
 		otherTabMeta,
 		h.otherTabOrdinals,
 		&tree.IndexFlags{IgnoreForeignKeys: true},
+		nil, /* sample */
 		locking,
 		h.mb.b.allocScope(),
 		true, /* disableNotVisibleIndex */
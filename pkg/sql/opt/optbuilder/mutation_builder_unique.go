@@ -17,10 +17,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+	"github.com/cockroachdb/errors"
 )
 
 // UniquenessChecksForGenRandomUUIDClusterMode controls the cluster setting for
@@ -245,8 +249,15 @@ func (h *uniqueCheckHelper) init(mb *mutationBuilder, uniqueOrdinal int) bool {
 	// Similarly, we don't need a check for a partial unique constraint if there
 	// exists a non-partial unique constraint with columns that are a subset of
 	// the partial unique constraint columns.
+	//
+	// Exclusion constraints do not necessarily compare the primary key columns
+	// for equality, so all of them are used to prevent rows from conflicting
+	// with themselves.
+	isExclusion := h.unique.ExclusionElementCount() > 0
 	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
-	primaryOrds.DifferenceWith(uniqueOrds)
+	if !isExclusion {
+		primaryOrds.DifferenceWith(uniqueOrds)
+	}
 	if primaryOrds.Empty() {
 		// The primary key columns are a subset of the unique columns; unique check
 		// not needed.
@@ -256,6 +267,10 @@ func (h *uniqueCheckHelper) init(mb *mutationBuilder, uniqueOrdinal int) bool {
 	h.uniqueOrdinals = uniqueOrds
 	h.primaryKeyOrdinals = primaryOrds
 
+	if isExclusion {
+		return h.initExclusion()
+	}
+
 	for tabOrd, ok := h.uniqueOrdinals.Next(0); ok; tabOrd, ok = h.uniqueOrdinals.Next(tabOrd + 1) {
 		colID := mb.mapToReturnColID(tabOrd)
 		// Check if we are setting NULL values for the unique columns, like when
@@ -315,6 +330,104 @@ func (h *uniqueCheckHelper) init(mb *mutationBuilder, uniqueOrdinal int) bool {
 	return !fds.ColsAreLaxKey(uniqueCols)
 }
 
+// initExclusion finishes the initialization of the helper for an exclusion
+// constraint. None of the shortcuts used for unique constraints apply here
+// except for NULL values, which can never conflict when compared with EQ or
+// NE. NULL range bounds are unbounded, so they are always checked.
+func (h *uniqueCheckHelper) initExclusion() bool {
+	mb := h.mb
+	if mb.b.evalCtx.TxnIsoLevel != isolation.Serializable {
+		// Under weaker isolation levels, checks must take predicate locks on the
+		// rows they look for, which requires a lookup join into the table. The
+		// checks of exclusion constraints can't always be planned this way, e.g.
+		// when they only compare ranges for overlap.
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"exclusion constraint %q cannot be enforced under %s isolation",
+			h.unique.Name(), mb.b.evalCtx.TxnIsoLevel.StringLower(),
+		))
+	}
+	for i, n := 0, h.unique.ExclusionElementCount(); i < n; i++ {
+		elem := h.unique.ExclusionElement(i)
+		if elem.IsRange {
+			continue
+		}
+		colID := mb.mapToReturnColID(h.unique.ColumnOrdinal(mb.tab, elem.Column))
+		if memo.OutputColumnIsAlwaysNull(mb.outScope.expr, colID) {
+			return false
+		}
+	}
+	h.scanScope, h.scanOrdinals = h.buildTableScan()
+	return true
+}
+
+// buildExclusionFilters builds the filters which determine whether a new row
+// conflicts with an existing row according to an exclusion constraint:
+//
+//	(new_a = existing_a) AND (new_b <> existing_b) AND ...
+//
+// A range element built from bounds lo and hi, where NULL bounds are unbounded,
+// conflicts when both ranges are non-empty and overlap:
+//
+//	(new_lo IS NULL OR existing_hi IS NULL OR new_lo < existing_hi) AND
+//	(existing_lo IS NULL OR new_hi IS NULL OR existing_lo < new_hi) AND
+//	(new_lo IS NULL OR new_hi IS NULL OR new_lo < new_hi) AND
+//	(existing_lo IS NULL OR existing_hi IS NULL OR existing_lo < existing_hi)
+//
+// The comparisons use <= instead of < if both bounds of the ranges are
+// inclusive.
+func (h *uniqueCheckHelper) buildExclusionFilters(
+	newCols, existingCols []scopeColumn, filters memo.FiltersExpr,
+) memo.FiltersExpr {
+	f := h.mb.b.factory
+	colID := func(cols []scopeColumn, i int) opt.ColumnID {
+		return cols[h.unique.ColumnOrdinal(h.mb.tab, i)].id
+	}
+	for i, n := 0, h.unique.ExclusionElementCount(); i < n; i++ {
+		elem := h.unique.ExclusionElement(i)
+		newCol := f.ConstructVariable(colID(newCols, elem.Column))
+		existingCol := f.ConstructVariable(colID(existingCols, elem.Column))
+		if !elem.IsRange {
+			var cond opt.ScalarExpr
+			switch elem.Operator {
+			case treecmp.EQ:
+				cond = f.ConstructEq(newCol, existingCol)
+			case treecmp.NE:
+				cond = f.ConstructNe(newCol, existingCol)
+			case treecmp.Overlaps:
+				cond = f.ConstructOverlaps(newCol, existingCol)
+			default:
+				panic(errors.AssertionFailedf("unexpected exclusion operator %s", elem.Operator))
+			}
+			filters = append(filters, f.ConstructFiltersItem(cond))
+			continue
+		}
+		newUpper := f.ConstructVariable(colID(newCols, elem.UpperColumn))
+		existingUpper := f.ConstructVariable(colID(existingCols, elem.UpperColumn))
+		before := func(lo, hi opt.ScalarExpr) opt.ScalarExpr {
+			var cmp opt.ScalarExpr
+			if elem.InclusiveBounds {
+				cmp = f.ConstructLe(lo, hi)
+			} else {
+				cmp = f.ConstructLt(lo, hi)
+			}
+			return f.ConstructOr(
+				f.ConstructOr(
+					f.ConstructIs(lo, memo.NullSingleton),
+					f.ConstructIs(hi, memo.NullSingleton),
+				),
+				cmp,
+			)
+		}
+		filters = append(filters,
+			f.ConstructFiltersItem(before(newCol, existingUpper)),
+			f.ConstructFiltersItem(before(existingCol, newUpper)),
+			f.ConstructFiltersItem(before(newCol, newUpper)),
+			f.ConstructFiltersItem(before(existingCol, existingUpper)),
+		)
+	}
+	return filters
+}
+
 // buildFiltersForFastPathCheck builds ANDed equality filters between the
 // columns in the uniqueness check defined by h.uniqueOrdinals and scalar
 // expressions present in a single Values row being inserted. It is expected
@@ -456,13 +569,18 @@ func (h *uniqueCheckHelper) buildInsertionCheck(
 		numFilters += 2
 	}
 	semiJoinFilters := make(memo.FiltersExpr, 0, numFilters)
-	for i, ok := h.uniqueOrdinals.Next(0); ok; i, ok = h.uniqueOrdinals.Next(i + 1) {
-		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(
-			f.ConstructEq(
-				f.ConstructVariable(uniqueCheckScope.cols[i].id),
-				f.ConstructVariable(h.scanScope.cols[i].id),
-			),
-		))
+	isExclusion := h.unique.ExclusionElementCount() > 0
+	if isExclusion {
+		semiJoinFilters = h.buildExclusionFilters(uniqueCheckScope.cols, h.scanScope.cols, semiJoinFilters)
+	} else {
+		for i, ok := h.uniqueOrdinals.Next(0); ok; i, ok = h.uniqueOrdinals.Next(i + 1) {
+			semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(
+				f.ConstructEq(
+					f.ConstructVariable(uniqueCheckScope.cols[i].id),
+					f.ConstructVariable(h.scanScope.cols[i].id),
+				),
+			))
+		}
 	}
 	// Find the ScanExpr which reads from the table this unique check applies to.
 	var uniqueFastPathCheck memo.RelExpr
@@ -481,8 +599,8 @@ func (h *uniqueCheckHelper) buildInsertionCheck(
 		scanExpr, foundScan = possibleScan.(*memo.ScanExpr)
 
 		// Fast path is disabled if this check is for a UNIQUE WITHOUT INDEX with a
-		// partial index predicate.
-		if foundScan && !isPartial {
+		// partial index predicate, or for an exclusion constraint.
+		if foundScan && !isPartial && !isExclusion {
 			scanFilters = h.buildFiltersForFastPathCheck(uniqueCheckExpr, uniqueCheckCols, scanExpr)
 		}
 	}
@@ -620,6 +738,7 @@ func (h *uniqueCheckHelper) buildTableScan() (outScope *scope, ordinals []int) {
 		// After the update we can't guarantee that the constraints are unique
 		// (which is why we need the uniqueness checks in the first place).
 		&tree.IndexFlags{IgnoreUniqueWithoutIndexKeys: true},
+		nil, /* sample */
 		locking,
 		h.mb.b.allocScope(),
 		true, /* disableNotVisibleIndex */
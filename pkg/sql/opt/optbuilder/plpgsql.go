@@ -24,6 +24,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	ast "github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treebin"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
@@ -169,6 +171,23 @@ type plpgsqlBuilder struct {
 	// outParams is the set of OUT parameters for the routine.
 	outParams []ast.Variable
 
+	// params is the list of parameters for the routine. It is used to resolve
+	// ALIAS declarations that refer to a parameter by position.
+	params []routineParam
+
+	// setReturning is true if the routine returns a set of rows. The rows are
+	// added to the result with RETURN NEXT and RETURN QUERY statements.
+	setReturning bool
+
+	// resultBuffer is shared between all routines that make up a set-returning
+	// PL/pgSQL routine. It is used to collect the rows of the result.
+	resultBuffer *tree.RoutineResultBuffer
+
+	// loopCursors is a stack of the implicit cursors opened by the FOR loops
+	// over query results that enclose the statements currently being built.
+	// These cursors must be closed when a RETURN statement exits the loops.
+	loopCursors []ast.Variable
+
 	isProcedure  bool
 	identCounter int
 }
@@ -187,15 +206,21 @@ func newPLpgSQLBuilder(
 	colRefs *opt.ColSet,
 	routineParams []routineParam,
 	returnType *types.T,
+	setReturning bool,
 	isProcedure bool,
 ) *plpgsqlBuilder {
 	const initialBlocksCap = 2
 	b := &plpgsqlBuilder{
-		ob:          ob,
-		colRefs:     colRefs,
-		returnType:  returnType,
-		blocks:      make([]plBlock, 0, initialBlocksCap),
-		isProcedure: isProcedure,
+		ob:           ob,
+		colRefs:      colRefs,
+		returnType:   returnType,
+		params:       routineParams,
+		setReturning: setReturning,
+		blocks:       make([]plBlock, 0, initialBlocksCap),
+		isProcedure:  isProcedure,
+	}
+	if setReturning {
+		b.resultBuffer = &tree.RoutineResultBuffer{}
 	}
 	// Build the initial block for the routine parameters, which are considered
 	// PL/pgSQL variables.
@@ -236,6 +261,10 @@ type plBlock struct {
 	// constants tracks the variables that were declared as constant.
 	constants map[ast.Variable]struct{}
 
+	// aliases maps from each name declared with ALIAS FOR to the variable that
+	// it refers to.
+	aliases map[ast.Variable]ast.Variable
+
 	// cursors is the set of cursor declarations for a PL/pgSQL block. It is set
 	// for bound cursor declarations, which allow a query to be associated with a
 	// cursor before it is opened.
@@ -265,6 +294,14 @@ func (b *plpgsqlBuilder) buildRootBlock(
 	s = s.push()
 	b.ensureScopeHasExpr(s)
 
+	// Make the builder available for resolving references to PL/pgSQL aliases
+	// while building the SQL expressions and statements of the routine.
+	prevPLpgSQLBuilder := b.ob.plpgsqlBuilder
+	b.ob.plpgsqlBuilder = b
+	defer func() {
+		b.ob.plpgsqlBuilder = prevPLpgSQLBuilder
+	}()
+
 	// Initialize OUT parameters to NULL. Note that the initial block for
 	// parameters was already created in newPLpgSQLBuilder().
 	for _, param := range routineParams {
@@ -306,6 +343,7 @@ func (b *plpgsqlBuilder) buildBlock(astBlock *ast.Block, s *scope) *scope {
 		vars:      make([]ast.Variable, 0, len(astBlock.Decls)),
 		varTypes:  make(map[ast.Variable]*types.T),
 		constants: make(map[ast.Variable]struct{}),
+		aliases:   make(map[ast.Variable]ast.Variable),
 		cursors:   make(map[ast.Variable]ast.CursorDeclaration),
 	})
 	defer b.popBlock()
@@ -353,6 +391,8 @@ func (b *plpgsqlBuilder) buildBlock(astBlock *ast.Block, s *scope) *scope {
 			b.addVariable(dec.Name, types.RefCursor)
 			s = b.addPLpgSQLAssign(s, dec.Name, &tree.CastExpr{Expr: tree.DNull, Type: types.RefCursor})
 			block.cursors[dec.Name] = *dec
+		case *ast.AliasDeclaration:
+			s = b.addAlias(s, dec)
 		}
 	}
 	if types.IsRecordType(b.returnType) && !b.hasOutParam() {
@@ -408,11 +448,32 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			return b.buildBlock(t, s)
 
 		case *ast.Return:
-			// If the routine has OUT-parameters or a VOID return type, the RETURN
-			// statement must have no expression. Otherwise, the RETURN statement must
-			// have a non-empty expression.
+			if len(b.loopCursors) > 0 {
+				// The implicit cursors of any enclosing FOR loops over query results
+				// must be closed before control leaves the routine.
+				newStmts := make([]ast.Statement, 0, len(b.loopCursors)+1)
+				for j := len(b.loopCursors) - 1; j >= 0; j-- {
+					newStmts = append(newStmts, &ast.Close{CurVar: b.loopCursors[j]})
+				}
+				newStmts = append(newStmts, t)
+				loopCursors := b.loopCursors
+				b.loopCursors = nil
+				returnScope := b.buildPLpgSQLStatements(newStmts, s)
+				b.loopCursors = loopCursors
+				return returnScope
+			}
+			// If the routine is set-returning, has OUT-parameters or has a VOID
+			// return type, the RETURN statement must have no expression. Otherwise,
+			// the RETURN statement must have a non-empty expression.
 			expr := t.Expr
-			if b.hasOutParam() {
+			if b.setReturning {
+				// The result of a set-returning routine is built by RETURN NEXT and
+				// RETURN QUERY statements, so RETURN only exits the routine.
+				if expr != nil {
+					panic(returnWithSetErr)
+				}
+				expr = tree.DNull
+			} else if b.hasOutParam() {
 				if expr != nil {
 					panic(returnWithOUTParameterErr)
 				}
@@ -439,7 +500,11 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 		case *ast.Assignment:
 			// Assignment (:=) is handled by projecting a new column with the same
 			// name as the variable being assigned.
-			s = b.addPLpgSQLAssign(s, t.Var, t.Value)
+			val := t.Value
+			if t.Indirection != "" {
+				val = b.makeElementAssignExpr(t.Var, t.Indirection, t.Value)
+			}
+			s = b.addPLpgSQLAssign(s, t.Var, val)
 			if b.hasExceptionHandler() {
 				// If exception handling is required, we have to start a new
 				// continuation after each variable assignment. This ensures that in the
@@ -617,7 +682,7 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 		case *ast.Execute:
 			if len(t.Target) > 1 {
 				seenTargets := make(map[ast.Variable]struct{})
-				for _, name := range t.Target {
+				for _, name := range b.resolveAliases(t.Target) {
 					if _, ok := seenTargets[name]; ok {
 						panic(dupIntoErr)
 					}
@@ -687,54 +752,13 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			return b.callContinuation(&execCon, s)
 
 		case *ast.Open:
-			// OPEN statements are used to create a CURSOR for the current session.
-			// This is handled by calling the plpgsql_open_cursor internal builtin
-			// function in a separate body statement that returns no results, similar
-			// to the RAISE implementation.
 			if t.Scroll == tree.Scroll {
 				panic(scrollableCursorErr)
 			}
-			openCon := b.makeContinuation("_stmt_open")
-			openCon.def.Volatility = volatility.Volatile
-			_, source, _, err := openCon.s.FindSourceProvidingColumn(b.ob.ctx, t.CurVar)
-			if err != nil {
-				if pgerror.GetPGCode(err) == pgcode.UndefinedColumn {
-					panic(pgerror.Newf(pgcode.Syntax, "\"%s\" is not a known variable", t.CurVar))
-				}
-				panic(err)
-			}
-			if !source.(*scopeColumn).typ.Identical(types.RefCursor) {
-				panic(pgerror.Newf(pgcode.DatatypeMismatch,
-					"variable \"%s\" must be of type cursor or refcursor", t.CurVar,
-				))
-			}
-			// Initialize the routine with the information needed to pipe the first
-			// body statement into a cursor.
-			query := b.resolveOpenQuery(t)
-			fmtCtx := b.ob.evalCtx.FmtCtx(tree.FmtSimple)
-			fmtCtx.FormatNode(query)
-			openCon.def.CursorDeclaration = &tree.RoutineOpenCursor{
-				NameArgIdx: source.(*scopeColumn).getParamOrd(),
-				Scroll:     t.Scroll,
-				CursorSQL:  fmtCtx.CloseAndGetString(),
-			}
-			openScope := b.ob.buildStmtAtRootWithScope(query, nil /* desiredTypes */, openCon.s)
-			if openScope.expr.Relational().CanMutate {
-				// Cursors with mutations are invalid.
-				panic(cursorMutationErr)
-			}
-			b.appendBodyStmt(&openCon, openScope)
-			b.appendPlpgSQLStmts(&openCon, stmts[i+1:])
-
-			// Build a statement to generate a unique name for the cursor if one
-			// was not supplied. Add this to its own volatile routine to ensure that
-			// the name generation isn't reordered with other operations. Use the
-			// resulting projected column as input to the OPEN continuation.
-			nameCon := b.makeContinuation("_gen_cursor_name")
-			nameCon.def.Volatility = volatility.Volatile
-			nameScope := b.buildCursorNameGen(&nameCon, t.CurVar)
-			b.appendBodyStmt(&nameCon, b.callContinuation(&openCon, nameScope))
-			return b.callContinuation(&nameCon, s)
+			open := *t
+			open.CurVar = b.resolveAlias(t.CurVar)
+			query, argDecls := b.resolveOpenQuery(&open)
+			return b.buildOpen(&open, query, argDecls, false /* addFoundCol */, stmts[i+1:], s)
 
 		case *ast.Close:
 			// CLOSE statements close the cursor with the name supplied by a PLpgSQL
@@ -748,16 +772,17 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			if len(overloads) != 1 {
 				panic(errors.AssertionFailedf("expected one overload for %s", closeFnName))
 			}
-			_, source, _, err := closeCon.s.FindSourceProvidingColumn(b.ob.ctx, t.CurVar)
+			curVar := b.resolveAlias(t.CurVar)
+			_, source, _, err := closeCon.s.FindSourceProvidingColumn(b.ob.ctx, curVar)
 			if err != nil {
 				if pgerror.GetPGCode(err) == pgcode.UndefinedColumn {
-					panic(pgerror.Newf(pgcode.Syntax, "\"%s\" is not a known variable", t.CurVar))
+					panic(pgerror.Newf(pgcode.Syntax, "\"%s\" is not a known variable", curVar))
 				}
 				panic(err)
 			}
 			if !source.(*scopeColumn).typ.Identical(types.RefCursor) {
 				panic(pgerror.Newf(pgcode.DatatypeMismatch,
-					"variable \"%s\" must be of type cursor or refcursor", t.CurVar,
+					"variable \"%s\" must be of type cursor or refcursor", curVar,
 				))
 			}
 			closeCall := b.ob.factory.ConstructFunction(
@@ -787,6 +812,7 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			//
 			// All cursor interactions are handled by the crdb_internal.plpgsql_fetch
 			// builtin function.
+			t = b.resolveFetchAliases(t)
 			if !t.IsMove {
 				if t.Cursor.FetchType == tree.FetchAll || t.Cursor.FetchType == tree.FetchBackwardAll {
 					panic(fetchRowsErr)
@@ -828,6 +854,92 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			b.appendBodyStmt(&fetchCon, intoScope)
 			return b.callContinuation(&fetchCon, s)
 
+		case *ast.ReturnNext:
+			// RETURN NEXT adds a row to the result of a set-returning routine, and
+			// then resumes execution with the next statement. The row is built by
+			// the first body statement of a continuation, which adds its result to
+			// the buffer that is shared by the routines that make up the function.
+			if !b.setReturning {
+				panic(returnNextNonSetErr)
+			}
+			expr := t.Expr
+			if b.hasOutParam() {
+				if expr != nil {
+					panic(returnNextWithOUTParameterErr)
+				}
+				expr = b.makeReturnForOutParams()
+			}
+			if expr == nil {
+				panic(emptyReturnNextErr)
+			}
+			nextCon := b.makeContinuation("_stmt_return_next")
+			nextCon.def.Volatility = volatility.Volatile
+			nextCon.def.ResultBuffer = b.resultBuffer
+			nextScalar := b.buildPLpgSQLExpr(expr, b.returnType, nextCon.s)
+			nextColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_return_next"))
+			nextScope := nextCon.s.push()
+			b.ob.synthesizeColumn(nextScope, nextColName, b.returnType, nil /* expr */, nextScalar)
+			b.ob.constructProjectForScope(nextCon.s, nextScope)
+			b.appendBodyStmt(&nextCon, nextScope)
+			b.appendPlpgSQLStmts(&nextCon, stmts[i+1:])
+			return b.callContinuation(&nextCon, s)
+
+		case *ast.ReturnQuery:
+			// RETURN QUERY adds the rows returned by a query to the result of a
+			// set-returning routine. It is handled similarly to RETURN NEXT.
+			if !b.setReturning {
+				panic(returnQueryNonSetErr)
+			}
+			queryCon := b.makeContinuation("_stmt_return_query")
+			queryCon.def.Volatility = volatility.Volatile
+			queryCon.def.ResultBuffer = b.resultBuffer
+			b.appendBodyStmt(&queryCon, b.buildReturnQuery(queryCon.s, t.Query))
+			b.appendPlpgSQLStmts(&queryCon, stmts[i+1:])
+			return b.callContinuation(&queryCon, s)
+
+		case *ast.Perform:
+			// PERFORM executes a query and discards its result. Similar to a SQL
+			// statement without an INTO target, the query is built into a body
+			// statement that is only executed for its side effects.
+			performCon := b.makeContinuation("_stmt_perform")
+			performScope := b.ob.buildStmtAtRootWithScope(t.Query, nil /* desiredTypes */, performCon.s)
+			b.appendBodyStmt(&performCon, performScope)
+			b.appendPlpgSQLStmts(&performCon, stmts[i+1:])
+			return b.callContinuation(&performCon, s)
+
+		case *ast.ForInt:
+			if t.Label != "" {
+				panic(loopLabelErr)
+			}
+			// An integer FOR loop is rewritten as a LOOP within a block that declares
+			// the loop's hidden state. See makeForIntBlock for details.
+			newStmts := make([]ast.Statement, 0, len(stmts)-i)
+			newStmts = append(newStmts, b.makeForIntBlock(t))
+			newStmts = append(newStmts, stmts[i+1:]...)
+			return b.buildPLpgSQLStatements(newStmts, s)
+
+		case *ast.ForSelect:
+			if t.Label != "" {
+				panic(loopLabelErr)
+			}
+			// A FOR loop over the results of a query is rewritten as a LOOP that
+			// fetches from an implicit cursor. See makeForSelectBlock for details.
+			newStmts := make([]ast.Statement, 0, len(stmts)-i)
+			newStmts = append(newStmts, b.makeForSelectBlock(t))
+			newStmts = append(newStmts, stmts[i+1:]...)
+			return b.buildPLpgSQLStatements(newStmts, s)
+
+		case *openLoopCursor:
+			// Open the implicit cursor for a FOR loop over the results of a query.
+			// The cursor must be closed if a RETURN statement exits the loop, so it
+			// is tracked while the loop is built.
+			b.loopCursors = append(b.loopCursors, t.curVar)
+			openScope := b.buildOpen(
+				&ast.Open{CurVar: t.curVar}, t.query, nil /* argDecls */, true /* addFoundCol */, stmts[i+1:], s,
+			)
+			b.loopCursors = b.loopCursors[:len(b.loopCursors)-1]
+			return openScope
+
 		case *ast.Null:
 			// PL/pgSQL NULL statements are a no-op.
 			continue
@@ -870,17 +982,344 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 	return b.callContinuation(b.getContinuation(), s)
 }
 
+// buildOpen builds an OPEN statement for the given query, followed by the
+// given statements. argDecls are the arguments of the bound cursor, if any. If
+// addFoundCol is true, a leading column that is always true is added to the
+// result of the query; it is used by FOR loops to determine when the cursor
+// has been exhausted (see makeForSelectBlock).
+//
+// OPEN statements are used to create a CURSOR for the current session. This is
+// handled by piping the result of the query into a cursor in a separate body
+// statement that returns no results, similar to the RAISE implementation.
+func (b *plpgsqlBuilder) buildOpen(
+	open *ast.Open,
+	query tree.Statement,
+	argDecls []ast.CursorArg,
+	addFoundCol bool,
+	stmts []ast.Statement,
+	s *scope,
+) *scope {
+	openCon := b.makeContinuation("_stmt_open")
+	openCon.def.Volatility = volatility.Volatile
+	_, source, _, err := openCon.s.FindSourceProvidingColumn(b.ob.ctx, open.CurVar)
+	if err != nil {
+		if pgerror.GetPGCode(err) == pgcode.UndefinedColumn {
+			panic(pgerror.Newf(pgcode.Syntax, "\"%s\" is not a known variable", open.CurVar))
+		}
+		panic(err)
+	}
+	if !source.(*scopeColumn).typ.Identical(types.RefCursor) {
+		panic(pgerror.Newf(pgcode.DatatypeMismatch,
+			"variable \"%s\" must be of type cursor or refcursor", open.CurVar,
+		))
+	}
+	// Initialize the routine with the information needed to pipe the first
+	// body statement into a cursor.
+	fmtCtx := b.ob.evalCtx.FmtCtx(tree.FmtSimple)
+	fmtCtx.FormatNode(query)
+	openCon.def.CursorDeclaration = &tree.RoutineOpenCursor{
+		NameArgIdx: source.(*scopeColumn).getParamOrd(),
+		Scroll:     open.Scroll,
+		CursorSQL:  fmtCtx.CloseAndGetString(),
+	}
+	openScope := b.buildCursorQuery(open, query, argDecls, openCon.s)
+	if openScope.expr.Relational().CanMutate {
+		// Cursors with mutations are invalid.
+		panic(cursorMutationErr)
+	}
+	if addFoundCol {
+		foundScope := openScope.push()
+		foundColName := scopeColName("").WithMetadataName(b.makeIdentifier("found"))
+		b.ob.synthesizeColumn(foundScope, foundColName, types.Bool, nil /* expr */, memo.TrueSingleton)
+		for j := range openScope.cols {
+			foundScope.appendColumn(&openScope.cols[j])
+		}
+		foundScope.copyOrdering(openScope)
+		b.ob.constructProjectForScope(openScope, foundScope)
+		openScope = foundScope
+	}
+	b.appendBodyStmt(&openCon, openScope)
+	b.appendPlpgSQLStmts(&openCon, stmts)
+
+	// Build a statement to generate a unique name for the cursor if one
+	// was not supplied. Add this to its own volatile routine to ensure that
+	// the name generation isn't reordered with other operations. Use the
+	// resulting projected column as input to the OPEN continuation.
+	nameCon := b.makeContinuation("_gen_cursor_name")
+	nameCon.def.Volatility = volatility.Volatile
+	nameScope := b.buildCursorNameGen(&nameCon, open.CurVar)
+	b.appendBodyStmt(&nameCon, b.callContinuation(&openCon, nameScope))
+	return b.callContinuation(&nameCon, s)
+}
+
+// buildCursorQuery builds the query for an OPEN statement. If the cursor was
+// declared with arguments, the values supplied by the OPEN statement are
+// projected as columns with the names of the arguments, so that they can be
+// referenced by the query.
+func (b *plpgsqlBuilder) buildCursorQuery(
+	open *ast.Open, query tree.Statement, argDecls []ast.CursorArg, s *scope,
+) *scope {
+	switch {
+	case len(argDecls) == 0 && len(open.Args) == 0:
+		return b.ob.buildStmtAtRootWithScope(query, nil /* desiredTypes */, s)
+	case len(argDecls) == 0:
+		panic(pgerror.Newf(pgcode.Syntax, "cursor \"%s\" has no arguments", open.CurVar))
+	case len(open.Args) == 0:
+		panic(pgerror.Newf(pgcode.Syntax, "cursor \"%s\" has arguments", open.CurVar))
+	case len(open.Args) < len(argDecls):
+		panic(pgerror.Newf(pgcode.Syntax, "not enough arguments for cursor \"%s\"", open.CurVar))
+	case len(open.Args) > len(argDecls):
+		panic(pgerror.Newf(pgcode.Syntax, "too many arguments for cursor \"%s\"", open.CurVar))
+	}
+	argScope := s.push()
+	for j := range argDecls {
+		typ, err := tree.ResolveType(b.ob.ctx, argDecls[j].Typ, b.ob.semaCtx.TypeResolver)
+		if err != nil {
+			panic(err)
+		}
+		scalar := b.buildPLpgSQLExpr(open.Args[j], typ, s)
+		b.ob.synthesizeColumn(argScope, scopeColName(argDecls[j].Name), typ, nil /* expr */, scalar)
+	}
+	b.ob.constructProjectForScope(s, argScope)
+	// The query can reference the arguments as outer columns, so it is joined to
+	// the projection of the argument values with an apply join.
+	queryScope := b.ob.buildStmtAtRootWithScope(query, nil /* desiredTypes */, argScope)
+	queryScope.expr = b.ob.factory.ConstructInnerJoinApply(
+		argScope.expr, queryScope.expr, memo.TrueFilter, memo.EmptyJoinPrivate,
+	)
+	return queryScope
+}
+
+// buildReturnQuery builds the query for a RETURN QUERY statement, and projects
+// its result as a single column with the return type of the routine.
+func (b *plpgsqlBuilder) buildReturnQuery(s *scope, query tree.Statement) *scope {
+	queryScope := b.ob.buildStmtAtRootWithScope(query, nil /* desiredTypes */, s)
+	isComposite := b.returnType.Family() == types.TupleFamily
+	colTypes := []*types.T{b.returnType}
+	if isComposite {
+		colTypes = b.returnType.TupleContents()
+	}
+	if len(queryScope.cols) != len(colTypes) {
+		panic(errors.WithDetailf(
+			pgerror.New(pgcode.DatatypeMismatch, "structure of query does not match function result type"),
+			"Number of returned columns (%d) does not match expected column count (%d).",
+			len(queryScope.cols), len(colTypes),
+		))
+	}
+	elems := make(memo.ScalarListExpr, len(colTypes))
+	for j := range colTypes {
+		elems[j] = b.coerceType(b.ob.factory.ConstructVariable(queryScope.cols[j].id), colTypes[j])
+	}
+	resultScalar := elems[0]
+	if isComposite {
+		resultScalar = b.ob.factory.ConstructTuple(elems, b.returnType)
+	}
+	resultColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_return_query"))
+	resultScope := queryScope.push()
+	b.ob.synthesizeColumn(resultScope, resultColName, b.returnType, nil /* expr */, resultScalar)
+	resultScope.copyOrdering(queryScope)
+	b.ob.constructProjectForScope(queryScope, resultScope)
+	return resultScope
+}
+
+// makeForIntBlock rewrites an integer FOR loop as a LOOP within a block that
+// declares hidden variables for the next value of the loop variable, the upper
+// (or lower) bound of the loop, and the step size:
+//
+//	FOR i IN lower..upper BY step LOOP
+//	  [body];
+//	END LOOP;
+//	=>
+//	DECLARE
+//	  i INT;
+//	  _next INT := lower;
+//	  _bound INT := upper;
+//	  _step INT := step;
+//	BEGIN
+//	  [check that _next, _bound and _step are valid];
+//	  LOOP
+//	    IF _next > _bound THEN
+//	      EXIT;
+//	    END IF;
+//	    i := _next;
+//	    _next := _next + _step;
+//	    [body];
+//	  END LOOP;
+//	END;
+//
+// For a REVERSE loop, the comparison is reversed and the step is subtracted.
+// Advancing the hidden counter at the start of each iteration ensures that a
+// CONTINUE statement proceeds to the next iteration, and that assignments to
+// the loop variable within the body do not affect the number of iterations.
+//
+// Postgres always declares a new loop variable that is local to the loop.
+// Since variable shadowing is not yet supported, an existing variable with the
+// same name is used as the loop variable instead.
+func (b *plpgsqlBuilder) makeForIntBlock(loop *ast.ForInt) *ast.Block {
+	name := func(v ast.Variable) tree.Expr {
+		return tree.NewUnresolvedName(string(v))
+	}
+	loopVar := b.resolveAlias(loop.Var)
+	next := ast.Variable(b.makeIdentifier("_for_next"))
+	bound := ast.Variable(b.makeIdentifier("_for_bound"))
+	step := ast.Variable(b.makeIdentifier("_for_step"))
+	var stepExpr ast.Expr = tree.NewDInt(1)
+	if loop.Step != nil {
+		stepExpr = loop.Step
+	}
+	decls := make([]ast.Statement, 0, 4)
+	if _, ok := b.lookupVariable(loopVar); !ok {
+		decls = append(decls, &ast.Declaration{Var: loopVar, Typ: types.Int})
+	}
+	decls = append(decls,
+		&ast.Declaration{Var: next, Typ: types.Int, Expr: loop.Lower},
+		&ast.Declaration{Var: bound, Typ: types.Int, Expr: loop.Upper},
+		&ast.Declaration{Var: step, Typ: types.Int, Expr: stepExpr},
+	)
+	makeCheck := func(cond tree.Expr, msg string, code pgcode.Code) ast.Statement {
+		return &ast.If{
+			Condition: cond,
+			ThenBody: []ast.Statement{
+				&ast.Raise{LogLevel: "EXCEPTION", Message: msg, Code: code.String()},
+			},
+		}
+	}
+	cmpOp, binOp := treecmp.GT, treebin.Plus
+	if loop.Reverse {
+		cmpOp, binOp = treecmp.LT, treebin.Minus
+	}
+	body := make([]ast.Statement, 0, len(loop.Body)+3)
+	body = append(body,
+		&ast.If{
+			Condition: &tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(cmpOp), Left: name(next), Right: name(bound),
+			},
+			ThenBody: []ast.Statement{&ast.Exit{}},
+		},
+		&ast.Assignment{Var: loopVar, Value: name(next)},
+		&ast.Assignment{Var: next, Value: &tree.BinaryExpr{
+			Operator: treebin.MakeBinaryOperator(binOp), Left: name(next), Right: name(step),
+		}},
+	)
+	body = append(body, loop.Body...)
+	return &ast.Block{
+		Decls: decls,
+		Body: []ast.Statement{
+			makeCheck(&tree.IsNullExpr{Expr: name(next)},
+				"lower bound of FOR loop cannot be null", pgcode.NullValueNotAllowed),
+			makeCheck(&tree.IsNullExpr{Expr: name(bound)},
+				"upper bound of FOR loop cannot be null", pgcode.NullValueNotAllowed),
+			makeCheck(&tree.IsNullExpr{Expr: name(step)},
+				"BY value of FOR loop cannot be null", pgcode.NullValueNotAllowed),
+			makeCheck(&tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(treecmp.LE), Left: name(step), Right: tree.NewDInt(0),
+			}, "BY value of FOR loop must be greater than zero", pgcode.InvalidParameterValue),
+			&ast.Loop{Body: body},
+		},
+	}
+}
+
+// makeForSelectBlock rewrites a FOR loop over the results of a query as a LOOP
+// that fetches rows from an implicit cursor:
+//
+//	FOR a, b IN [query] LOOP
+//	  [body];
+//	END LOOP;
+//	=>
+//	DECLARE
+//	  _cursor REFCURSOR;
+//	  _found BOOL;
+//	BEGIN
+//	  OPEN _cursor FOR SELECT true, * FROM [query];
+//	  LOOP
+//	    FETCH _cursor INTO _found, a, b;
+//	    IF _found IS NULL THEN
+//	      EXIT;
+//	    END IF;
+//	    [body];
+//	  END LOOP;
+//	  CLOSE _cursor;
+//	END;
+//
+// The leading column is always true for a row returned by the query, so that
+// _found is only NULL once the cursor has been exhausted. The column is added
+// without wrapping the query, so that its ordering is preserved (see
+// openLoopCursor).
+func (b *plpgsqlBuilder) makeForSelectBlock(loop *ast.ForSelect) *ast.Block {
+	target := b.resolveAliases(loop.Target)
+	if b.targetIsRecordVar(target) {
+		panic(forLoopCompositeTargetErr)
+	}
+	cursor := ast.Variable(b.makeIdentifier("_for_cursor"))
+	found := ast.Variable(b.makeIdentifier("_for_found"))
+	fetchTarget := make([]ast.Variable, 0, len(target)+1)
+	fetchTarget = append(fetchTarget, found)
+	fetchTarget = append(fetchTarget, target...)
+	body := make([]ast.Statement, 0, len(loop.Body)+2)
+	body = append(body,
+		&ast.Fetch{
+			Cursor: tree.CursorStmt{Name: cursor, FetchType: tree.FetchNormal, Count: 1},
+			Target: fetchTarget,
+		},
+		&ast.If{
+			Condition: &tree.IsNullExpr{Expr: tree.NewUnresolvedName(string(found))},
+			ThenBody:  []ast.Statement{&ast.Exit{}},
+		},
+	)
+	body = append(body, loop.Body...)
+	return &ast.Block{
+		Decls: []ast.Statement{
+			&ast.Declaration{Var: cursor, Typ: types.RefCursor},
+			&ast.Declaration{Var: found, Typ: types.Bool},
+		},
+		Body: []ast.Statement{
+			&openLoopCursor{curVar: cursor, query: loop.Query},
+			&ast.Loop{Body: body},
+			&ast.Close{CurVar: cursor},
+		},
+	}
+}
+
+// openLoopCursor is an internal statement used by the rewrite of a FOR loop
+// over the results of a query. It opens the implicit cursor of the loop, adding
+// a leading column to the result of the query that is always true.
+type openLoopCursor struct {
+	ast.StatementImpl
+	curVar ast.Variable
+	query  tree.Statement
+}
+
+var _ ast.Statement = &openLoopCursor{}
+
+// Format implements the tree.NodeFormatter interface.
+func (s *openLoopCursor) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("OPEN ")
+	ctx.FormatNode(&s.curVar)
+	ctx.WriteString(" FOR ")
+	ctx.FormatNode(s.query)
+	ctx.WriteString(";\n")
+}
+
+// WalkStmt implements the ast.Statement interface.
+func (s *openLoopCursor) WalkStmt(visitor ast.StatementVisitor) ast.Statement {
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
+}
+
 // resolveOpenQuery finds and validates the query that is bound to cursor for
-// the given OPEN statement.
-func (b *plpgsqlBuilder) resolveOpenQuery(open *ast.Open) tree.Statement {
+// the given OPEN statement. It also returns the arguments of the bound cursor,
+// if any.
+func (b *plpgsqlBuilder) resolveOpenQuery(open *ast.Open) (tree.Statement, []ast.CursorArg) {
 	// Search the blocks in reverse order to ensure that more recent declarations
 	// are encountered first.
 	var boundStmt tree.Statement
+	var boundArgs []ast.CursorArg
 	for i := len(b.blocks) - 1; i >= 0; i-- {
 		block := &b.blocks[i]
 		for name := range block.cursors {
 			if open.CurVar == name {
 				boundStmt = block.cursors[name].Query
+				boundArgs = block.cursors[name].Args
 				break
 			}
 		}
@@ -910,7 +1349,7 @@ func (b *plpgsqlBuilder) resolveOpenQuery(open *ast.Open) tree.Statement {
 			pgcode.InvalidCursorDefinition, "cannot open %s query as cursor", stmt.StatementTag(),
 		))
 	}
-	return stmt
+	return stmt, boundArgs
 }
 
 // buildCursorNameGen builds a statement that generates a unique name for the
@@ -944,6 +1383,7 @@ func (b *plpgsqlBuilder) buildCursorNameGen(nameCon *continuation, nameVar ast.V
 // If there is a column with the same name in the previous scope, it will be
 // replaced. This allows the plpgsqlBuilder to model variable mutations.
 func (b *plpgsqlBuilder) addPLpgSQLAssign(inScope *scope, ident ast.Variable, val ast.Expr) *scope {
+	ident = b.resolveAlias(ident)
 	typ := b.resolveVariableForAssign(ident)
 	assignScope := inScope.push()
 	for i := range inScope.cols {
@@ -965,9 +1405,41 @@ func (b *plpgsqlBuilder) addPLpgSQLAssign(inScope *scope, ident ast.Variable, va
 	return assignScope
 }
 
+// makeElementAssignExpr returns an expression that evaluates to the value of
+// the given composite-typed variable, with the named element replaced by val.
+// It is used to handle assignments like NEW.x := 1.
+func (b *plpgsqlBuilder) makeElementAssignExpr(
+	ident ast.Variable, elem tree.Name, val ast.Expr,
+) ast.Expr {
+	typ := b.resolveVariableForAssign(ident)
+	if typ.Family() != types.TupleFamily {
+		panic(pgerror.Newf(pgcode.Syntax, "\"%s.%s\" is not a known variable", ident, elem))
+	}
+	labels := typ.TupleLabels()
+	tup := &tree.Tuple{Exprs: make(tree.Exprs, len(typ.TupleContents())), Labels: labels}
+	found := false
+	for i := range typ.TupleContents() {
+		if i < len(labels) && labels[i] == string(elem) {
+			tup.Exprs[i] = &tree.CastExpr{Expr: val, Type: typ.TupleContents()[i]}
+			found = true
+			continue
+		}
+		tup.Exprs[i] = &tree.ColumnAccessExpr{
+			Expr:     &tree.ColumnItem{ColumnName: ident},
+			ByIndex:  true,
+			ColIndex: i,
+		}
+	}
+	if !found {
+		panic(pgerror.Newf(pgcode.UndefinedColumn, "record \"%s\" has no field \"%s\"", ident, elem))
+	}
+	return tup
+}
+
 // buildInto handles the mapping from the columns of a SQL statement to the
 // variables in an INTO target.
 func (b *plpgsqlBuilder) buildInto(stmtScope *scope, target []ast.Variable) *scope {
+	target = b.resolveAliases(target)
 	var targetTypes []*types.T
 	var targetNames []ast.Variable
 	if b.targetIsRecordVar(target) {
@@ -1320,11 +1792,12 @@ func (b *plpgsqlBuilder) buildExceptions(block *ast.Block) *memo.ExceptionBlock
 // handleEndOfFunction handles the case when control flow reaches the end of a
 // PL/pgSQL routine without reaching a RETURN statement.
 func (b *plpgsqlBuilder) handleEndOfFunction(inScope *scope) *scope {
-	if b.hasOutParam() || b.returnType.Family() == types.VoidFamily {
+	if b.hasOutParam() || b.returnType.Family() == types.VoidFamily || b.setReturning {
 		// Routines with OUT-parameters and VOID return types need not explicitly
-		// specify a RETURN statement.
+		// specify a RETURN statement. Neither do set-returning routines, since
+		// their result is built by RETURN NEXT and RETURN QUERY statements.
 		var returnExpr tree.Expr = tree.DNull
-		if b.hasOutParam() {
+		if b.hasOutParam() && !b.setReturning {
 			returnExpr = b.makeReturnForOutParams()
 		}
 		returnScope := inScope.push()
@@ -1729,6 +2202,7 @@ func (b *plpgsqlBuilder) coerceType(scalar opt.ScalarExpr, typ *types.T) opt.Sca
 // resolveVariableForAssign attempts to retrieve the type of the variable with
 // the given name, throwing an error if no such variable exists.
 func (b *plpgsqlBuilder) resolveVariableForAssign(name ast.Variable) *types.T {
+	name = b.resolveAlias(name)
 	// Search the blocks in reverse order to ensure that more recent declarations
 	// are encountered first.
 	for i := len(b.blocks) - 1; i >= 0; i-- {
@@ -1747,6 +2221,101 @@ func (b *plpgsqlBuilder) resolveVariableForAssign(name ast.Variable) *types.T {
 	panic(pgerror.Newf(pgcode.Syntax, "\"%s\" is not a known variable", name))
 }
 
+// lookupVariable returns the type of the variable with the given name, if it
+// is in scope.
+func (b *plpgsqlBuilder) lookupVariable(name ast.Variable) (*types.T, bool) {
+	for i := len(b.blocks) - 1; i >= 0; i-- {
+		if typ, ok := b.blocks[i].varTypes[name]; ok {
+			return typ, true
+		}
+	}
+	return nil, false
+}
+
+// addAlias handles an ALIAS FOR declaration, which adds a new name for a
+// routine parameter or a previously declared alias. Parameters can be
+// referenced either by name or by position (e.g. $1).
+func (b *plpgsqlBuilder) addAlias(s *scope, dec *ast.AliasDeclaration) *scope {
+	target := dec.Target
+	if dec.TargetIdx > 0 {
+		if dec.TargetIdx > len(b.params) {
+			panic(pgerror.Newf(pgcode.Syntax, "\"$%d\" is not a known variable", dec.TargetIdx))
+		}
+		param := b.params[dec.TargetIdx-1]
+		if param.name == "" {
+			// An unnamed parameter can only be referenced by a placeholder, so the
+			// alias is added as a new variable that is assigned the value of the
+			// parameter.
+			b.addVariable(dec.Name, param.typ)
+			return b.addPLpgSQLAssign(
+				s, dec.Name, &tree.Placeholder{Idx: tree.PlaceholderIdx(dec.TargetIdx - 1)},
+			)
+		}
+		target = param.name
+	}
+	target = b.resolveAlias(target)
+	if _, ok := b.lookupVariable(target); !ok {
+		panic(pgerror.Newf(pgcode.Syntax, "\"%s\" is not a known variable", target))
+	}
+	block := b.block()
+	if _, ok := block.varTypes[dec.Name]; ok {
+		panic(pgerror.Newf(pgcode.Syntax, "duplicate declaration at or near \"%s\"", dec.Name))
+	}
+	if _, ok := block.aliases[dec.Name]; ok {
+		panic(pgerror.Newf(pgcode.Syntax, "duplicate declaration at or near \"%s\"", dec.Name))
+	}
+	block.aliases[dec.Name] = target
+	return s
+}
+
+// lookupAlias returns the variable referenced by the alias with the given
+// name, if one is in scope.
+func (b *plpgsqlBuilder) lookupAlias(name tree.Name) (ast.Variable, bool) {
+	for i := len(b.blocks) - 1; i >= 0; i-- {
+		if target, ok := b.blocks[i].aliases[name]; ok {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+// resolveAlias returns the variable referenced by the given name if it is an
+// alias. Otherwise, it returns the name unchanged.
+func (b *plpgsqlBuilder) resolveAlias(name ast.Variable) ast.Variable {
+	if target, ok := b.lookupAlias(name); ok {
+		return target
+	}
+	return name
+}
+
+// resolveAliases is similar to resolveAlias, but for a list of names.
+func (b *plpgsqlBuilder) resolveAliases(names []ast.Variable) []ast.Variable {
+	var resolved []ast.Variable
+	for i := range names {
+		target := b.resolveAlias(names[i])
+		if target != names[i] && resolved == nil {
+			resolved = make([]ast.Variable, len(names))
+			copy(resolved, names)
+		}
+		if resolved != nil {
+			resolved[i] = target
+		}
+	}
+	if resolved == nil {
+		return names
+	}
+	return resolved
+}
+
+// resolveFetchAliases returns a copy of the given FETCH or MOVE statement with
+// any aliases in the cursor name and target resolved.
+func (b *plpgsqlBuilder) resolveFetchAliases(fetch *ast.Fetch) *ast.Fetch {
+	newFetch := *fetch
+	newFetch.Cursor.Name = b.resolveAlias(fetch.Cursor.Name)
+	newFetch.Target = b.resolveAliases(fetch.Target)
+	return &newFetch
+}
+
 func (b *plpgsqlBuilder) ensureScopeHasExpr(s *scope) {
 	if s.expr == nil {
 		s.expr = b.ob.factory.ConstructNoColsRow()
@@ -2067,4 +2636,25 @@ var (
 	txnControlWithChainErr = unimplemented.NewWithIssue(119646,
 		"COMMIT or ROLLBACK with AND CHAIN syntax is not yet implemented",
 	)
+	returnWithSetErr = errors.WithHint(
+		pgerror.New(pgcode.DatatypeMismatch,
+			"RETURN cannot have a parameter in function returning set",
+		),
+		"Use RETURN NEXT or RETURN QUERY.",
+	)
+	returnNextNonSetErr = pgerror.New(pgcode.DatatypeMismatch,
+		"cannot use RETURN NEXT in a non-SETOF function",
+	)
+	returnQueryNonSetErr = pgerror.New(pgcode.DatatypeMismatch,
+		"cannot use RETURN QUERY in a non-SETOF function",
+	)
+	returnNextWithOUTParameterErr = pgerror.New(pgcode.DatatypeMismatch,
+		"RETURN NEXT cannot have a parameter in function with OUT parameters",
+	)
+	emptyReturnNextErr = pgerror.New(pgcode.Syntax,
+		"RETURN NEXT must have a parameter",
+	)
+	forLoopCompositeTargetErr = unimplemented.New("FOR loop composite target",
+		"FOR loops over queries with a RECORD or ROW target are not yet supported",
+	)
 )
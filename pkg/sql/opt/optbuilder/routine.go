@@ -11,6 +11,7 @@
 package optbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
@@ -22,9 +23,9 @@ import (
 	plpgsql "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -171,6 +172,10 @@ func (b *Builder) buildRoutine(
 	// the function. Return types like user defined return types may change
 	// since the function was first created.
 	rtyp := f.ResolvedType()
+	if rtyp.Family() == types.TriggerFamily {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"trigger functions can only be called as triggers"))
+	}
 	if rtyp.UserDefined() {
 		funcReturnType, err := tree.ResolveType(b.ctx,
 			&tree.OIDTypeReference{OID: rtyp.Oid()}, b.semaCtx.TypeResolver)
@@ -209,13 +214,45 @@ func (b *Builder) buildRoutine(
 		}
 	}
 
+	// Determine the concrete types of polymorphic parameters.
+	paramTypes := o.Types.(tree.ParamTypes)
+	argExprs := make([]tree.TypedExpr, len(f.Exprs))
+	for i, pexpr := range f.Exprs {
+		argExprs[i] = pexpr.(tree.TypedExpr)
+	}
+	poly, err := o.ResolvePolymorphicTypes(argExprs)
+	if err != nil {
+		panic(err)
+	}
+	resolveParamType := func(typ *types.T) *types.T {
+		if resolved := poly.Resolve(typ); resolved != nil {
+			return resolved
+		}
+		return typ
+	}
+
+	// The trailing arguments of a variadic routine are passed to the VARIADIC
+	// parameter as an array.
+	if o.Variadic {
+		n := len(paramTypes) - 1
+		arrType := resolveParamType(paramTypes[n].Typ)
+		elems := make(tree.TypedExprs, 0, len(argExprs)-n)
+		for _, arg := range argExprs[n:] {
+			if !arg.ResolvedType().Identical(arrType.ArrayContents()) {
+				arg = tree.NewTypedCastExpr(arg, arrType.ArrayContents())
+			}
+			elems = append(elems, arg)
+		}
+		argExprs = append(argExprs[:n:n], tree.NewTypedArray(elems, arrType))
+	}
+
 	// Build the argument expressions.
 	var args memo.ScalarListExpr
-	if len(f.Exprs) > 0 {
-		args = make(memo.ScalarListExpr, len(f.Exprs))
-		for i, pexpr := range f.Exprs {
+	if len(argExprs) > 0 {
+		args = make(memo.ScalarListExpr, len(argExprs))
+		for i, pexpr := range argExprs {
 			args[i] = b.buildScalar(
-				pexpr.(tree.TypedExpr),
+				pexpr,
 				inScope,
 				nil, /* outScope */
 				nil, /* outCol */
@@ -233,17 +270,14 @@ func (b *Builder) buildRoutine(
 	// CTEs that mutate and are not at the top-level.
 	bodyScope := b.allocScope()
 	var params opt.ColList
-	if o.Types.Length() > 0 {
-		paramTypes, ok := o.Types.(tree.ParamTypes)
-		if !ok {
-			panic(unimplemented.NewWithIssue(88947,
-				"variadiac user-defined functions are not yet supported"))
-		}
+	if len(paramTypes) > 0 {
 		params = make(opt.ColList, len(paramTypes))
 		for i := range paramTypes {
 			paramType := &paramTypes[i]
 			argColName := funcParamColName(tree.Name(paramType.Name), i)
-			col := b.synthesizeColumn(bodyScope, argColName, paramType.Typ, nil /* expr */, nil /* scalar */)
+			col := b.synthesizeColumn(
+				bodyScope, argColName, resolveParamType(paramType.Typ), nil /* expr */, nil, /* scalar */
+			)
 			col.setParamOrd(i)
 			params[i] = col.id
 		}
@@ -258,19 +292,39 @@ func (b *Builder) buildRoutine(
 	// track the previous state).
 	oldTrackingSchemaDeps := b.trackSchemaDeps
 	oldInsideUDF := b.insideUDF
+	oldPLpgSQLBuilder := b.plpgsqlBuilder
 	defer func() {
 		b.trackSchemaDeps = oldTrackingSchemaDeps
 		b.insideUDF = oldInsideUDF
+		b.plpgsqlBuilder = oldPLpgSQLBuilder
 	}()
 	b.trackSchemaDeps = false
 	b.insideUDF = true
+	// The aliases of a PL/pgSQL routine are not visible within the body of a
+	// routine that it invokes.
+	b.plpgsqlBuilder = nil
 	isSetReturning := o.Class == tree.GeneratorClass
 	isMultiColDataSource = false
 
+	// Routines defined with SECURITY DEFINER or with SET clauses change the
+	// session data for the duration of their execution.
+	var sessionSettings *tree.RoutineSessionSettings
+	if o.Security == tree.RoutineDefiner || len(o.Config) > 0 {
+		sessionSettings = &tree.RoutineSessionSettings{Config: o.Config}
+		if o.Security == tree.RoutineDefiner {
+			sessionSettings.Definer = o.Owner
+		}
+		// The memo cannot be reused, because staleness checks do not account for
+		// the session data that the body was built with.
+		b.DisableMemoReuse = true
+		defer b.withRoutineSessionSettings(sessionSettings)()
+	}
+
 	// Build an expression for each statement in the function body.
 	var body []memo.RelExpr
 	var bodyProps []*physical.Required
 	var bodyStmts []string
+	var resultBuffer *tree.RoutineResultBuffer
 	switch o.Language {
 	case tree.RoutineLangSQL:
 		// Parse the function body.
@@ -304,7 +358,7 @@ func (b *Builder) buildRoutine(
 			if i == len(stmts)-1 {
 				finishResolveType(stmtScope)
 				expr, physProps, isMultiColDataSource =
-					b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, f)
+					b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, f.ResolvedType())
 			}
 			body[i] = expr
 			bodyProps[i] = physProps
@@ -331,18 +385,21 @@ func (b *Builder) buildRoutine(
 			}
 			routineParams = append(routineParams, routineParam{
 				name:  param.Name,
-				typ:   typ,
+				typ:   resolveParamType(typ),
 				class: param.Class,
 			})
 		}
 		var expr memo.RelExpr
 		var physProps *physical.Required
 		isProc := o.Type == tree.ProcedureRoutine
-		plBuilder := newPLpgSQLBuilder(b, def.Name, colRefs, routineParams, rtyp, isProc)
+		plBuilder := newPLpgSQLBuilder(
+			b, def.Name, colRefs, routineParams, rtyp, isSetReturning, isProc,
+		)
 		stmtScope := plBuilder.buildRootBlock(stmt.AST, bodyScope, routineParams)
+		resultBuffer = plBuilder.resultBuffer
 		finishResolveType(stmtScope)
 		expr, physProps, isMultiColDataSource =
-			b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, f)
+			b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, f.ResolvedType())
 		body = []memo.RelExpr{expr}
 		bodyProps = []*physical.Required{physProps}
 		if b.verboseTracing {
@@ -367,21 +424,65 @@ func (b *Builder) buildRoutine(
 				BodyProps:          bodyProps,
 				BodyStmts:          bodyStmts,
 				Params:             params,
+				ResultBuffer:       resultBuffer,
+				SessionSettings:    sessionSettings,
 			},
 		},
 	)
 	return routine, isMultiColDataSource
 }
 
+// withRoutineSessionSettings applies the given settings of a routine to the
+// session data while the routine body is built, so that privileges are checked
+// for the owner of a SECURITY DEFINER routine, and so that names are resolved
+// using the search_path set by the routine. Other settings only take effect
+// when the routine is executed. The returned function restores the original
+// session data.
+func (b *Builder) withRoutineSessionSettings(
+	settings *tree.RoutineSessionSettings,
+) (restore func()) {
+	sds := b.evalCtx.SessionDataStack
+	if sds == nil {
+		return func() {}
+	}
+	sds.PushTopClone()
+	sd := sds.Top()
+	if !settings.Definer.Undefined() {
+		// The session_user is unchanged, similar to SET ROLE.
+		if sd.SessionUserProto == "" {
+			sd.SessionUserProto = sd.UserProto
+		}
+		sd.UserProto = settings.Definer.EncodeProto()
+		sd.SearchPath = sd.SearchPath.WithUserSchemaName(sd.User().Normalized())
+	}
+	for _, setting := range settings.Config {
+		if name, value := funcinfo.SplitConfig(setting); name == "search_path" {
+			paths, err := sessiondata.ParseSearchPath(value)
+			if err != nil {
+				_ = sds.Pop()
+				panic(err)
+			}
+			sd.SearchPath = sd.SearchPath.UpdatePaths(paths)
+		}
+	}
+	oldSearchPath := b.semaCtx.SearchPath
+	b.semaCtx.SearchPath = &sd.SearchPath
+	return func() {
+		b.semaCtx.SearchPath = oldSearchPath
+		if err := sds.Pop(); err != nil {
+			panic(err)
+		}
+	}
+}
+
 // finishBuildLastStmt manages the columns returned by the last statement of a
 // UDF. Depending on the context and return type of the UDF, this may mean
 // expanding a tuple into multiple columns, or combining multiple columns into
 // a tuple.
 func (b *Builder) finishBuildLastStmt(
-	stmtScope *scope, bodyScope *scope, isSetReturning bool, f *tree.FuncExpr,
+	stmtScope *scope, bodyScope *scope, isSetReturning bool, rtyp *types.T,
 ) (expr memo.RelExpr, physProps *physical.Required, isMultiColDataSource bool) {
 	expr, physProps = stmtScope.expr, stmtScope.makePhysicalProps()
-	rtyp := f.ResolvedType()
 
 	// Add a LIMIT 1 to the last statement if the UDF is not
 	// set-returning. This is valid because any other rows after the
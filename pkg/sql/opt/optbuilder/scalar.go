@@ -120,6 +120,9 @@ func (b *Builder) buildScalar(
 	case *windowInfo:
 		return b.finishBuildScalarRef(t.col, inScope, outScope, outCol, colRefs)
 
+	case *groupingInfo:
+		out = b.buildGroupingOp(t, inScope)
+
 	case *tree.AndExpr:
 		left := b.buildScalar(reType(t.TypedLeft(), types.Bool), inScope, nil, nil, colRefs)
 		right := b.buildScalar(reType(t.TypedRight(), types.Bool), inScope, nil, nil, colRefs)
@@ -783,6 +786,10 @@ func (b *Builder) constructComparison(
 		return b.factory.ConstructOverlaps(left, right)
 	case treecmp.TSMatches:
 		return b.factory.ConstructTSMatches(left, right)
+	case treecmp.Adjacent:
+		return b.factory.ConstructAdjacent(left, right)
+	case treecmp.JSONPathExists:
+		return b.factory.ConstructJsonPathExists(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", redact.Safe(cmp.Operator)))
 }
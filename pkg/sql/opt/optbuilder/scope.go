@@ -130,6 +130,7 @@ const (
 	exprKindHaving
 	exprKindLateralJoin
 	exprKindLimit
+	exprKindMergeWhen
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
@@ -138,6 +139,7 @@ const (
 	exprKindReturning
 	exprKindSelect
 	exprKindStoreID
+	exprKindTableSample
 	exprKindValues
 	exprKindWhere
 	exprKindWindowFrameStart
@@ -153,6 +155,7 @@ var exprKindName = [...]string{
 	exprKindHaving:            "HAVING",
 	exprKindLateralJoin:       "LATERAL JOIN",
 	exprKindLimit:             "LIMIT",
+	exprKindMergeWhen:         "MERGE WHEN",
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
@@ -161,6 +164,7 @@ var exprKindName = [...]string{
 	exprKindReturning:         "RETURNING",
 	exprKindSelect:            "SELECT",
 	exprKindStoreID:           "RELOCATE STORE ID",
+	exprKindTableSample:       "TABLESAMPLE",
 	exprKindValues:            "VALUES",
 	exprKindWhere:             "WHERE",
 	exprKindWindowFrameStart:  "WINDOW FRAME START",
@@ -657,6 +661,43 @@ func (s *scope) findFuncArgCol(idx tree.PlaceholderIdx) *scopeColumn {
 	return nil
 }
 
+// resolveRoutineVarElement attempts to resolve a column item of the form
+// var.elem, where var is a tuple-typed variable of the routine being built,
+// as an access of the named tuple element. It returns nil if the column item
+// cannot be resolved in this way.
+func (s *scope) resolveRoutineVarElement(c *tree.ColumnItem) tree.Expr {
+	if !s.builder.insideUDF && !s.builder.insideFuncDef {
+		return nil
+	}
+	if c.TableName == nil || c.TableName.NumParts != 1 {
+		return nil
+	}
+	varItem := &tree.ColumnItem{ColumnName: tree.Name(c.TableName.Parts[0])}
+	colI, err := colinfo.ResolveColumnItem(s.builder.ctx, s, varItem)
+	if err != nil {
+		return nil
+	}
+	col := colI.(*scopeColumn)
+	if col.typ.Family() != types.TupleFamily {
+		return nil
+	}
+	return &tree.ColumnAccessExpr{Expr: col, ColName: c.ColumnName}
+}
+
+// resolvePLpgSQLAlias attempts to resolve a column item as a reference to a
+// name declared with ALIAS FOR in the PL/pgSQL routine that is being built. It
+// returns nil if the column item does not refer to an alias.
+func (s *scope) resolvePLpgSQLAlias(c *tree.ColumnItem) *tree.ColumnItem {
+	if s.builder.plpgsqlBuilder == nil || c.TableName != nil {
+		return nil
+	}
+	target, ok := s.builder.plpgsqlBuilder.lookupAlias(c.ColumnName)
+	if !ok {
+		return nil
+	}
+	return &tree.ColumnItem{ColumnName: target}
+}
+
 // startAggFunc is called when the builder starts building an aggregate
 // function. It is used to disallow nested aggregates and ensure that a
 // grouping error is not called on the aggregate arguments. For example:
@@ -1031,6 +1072,16 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 	case *tree.ColumnItem:
 		colI, resolveErr := colinfo.ResolveColumnItem(s.builder.ctx, s, t)
 		if resolveErr != nil {
+			// Inside a PL/pgSQL routine, the reference may be to a name declared
+			// with ALIAS FOR.
+			if target := s.resolvePLpgSQLAlias(t); target != nil {
+				return s.VisitPre(target)
+			}
+			// Inside a routine, the reference may be to an element of a
+			// composite-typed variable, e.g. NEW.x in a trigger function.
+			if access := s.resolveRoutineVarElement(t); access != nil {
+				return false, access
+			}
 			// It may be a reference to a table, e.g. SELECT tbl FROM tbl.
 			// Attempt to resolve as a TupleStar.
 			if sqlerrors.IsUndefinedColumnError(resolveErr) {
@@ -1086,6 +1137,9 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+	case *tree.GroupingExpr:
+		expr = s.replaceGrouping(t)
+
 	case *tree.ArrayFlatten:
 		if sub, ok := t.Subquery.(*tree.Subquery); ok {
 			// Copy the ArrayFlatten expression so that the tree isn't mutated.
@@ -1294,6 +1348,28 @@ func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.ResolvedFunctionDef
 	return s.builder.buildAggregateFunction(f, &private, tempScope, s)
 }
 
+// replaceGrouping returns a groupingInfo that can be used to replace a
+// GROUPING operation. The arguments of the operation are resolved here, and
+// matched against the grouping expressions when the groupingInfo is built in
+// buildGroupingOp.
+func (s *scope) replaceGrouping(g *tree.GroupingExpr) *groupingInfo {
+	switch {
+	case s.context == exprKindOn:
+		panic(tree.NewInvalidGroupingUsageError("JOIN conditions"))
+	case s.context == exprKindWhere, s.builder.semaCtx.Properties.IsSet(tree.RejectAggregates):
+		panic(tree.NewInvalidGroupingUsageError(s.context.String()))
+	}
+	if len(g.Exprs) > maxGroupingArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingArgs+1))
+	}
+	info := &groupingInfo{GroupingExpr: g, args: make([]tree.TypedExpr, len(g.Exprs))}
+	for i, e := range g.Exprs {
+		info.args[i] = s.resolveType(e, types.Any)
+	}
+	return info
+}
+
 func (s *scope) lookupWindowDef(name tree.Name) *tree.WindowDef {
 	for i := range s.windowDefs {
 		if s.windowDefs[i].Name == name {
@@ -50,7 +50,11 @@ import (
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildDataSource(
-	texpr tree.TableExpr, indexFlags *tree.IndexFlags, lockCtx lockingContext, inScope *scope,
+	texpr tree.TableExpr,
+	indexFlags *tree.IndexFlags,
+	sample *tree.TableSample,
+	lockCtx lockingContext,
+	inScope *scope,
 ) (outScope *scope) {
 	defer func(prevAtRoot bool, prevInsideDataSource bool) {
 		inScope.atRoot = prevAtRoot
@@ -66,6 +70,10 @@ func (b *Builder) buildDataSource(
 			telemetry.Inc(sqltelemetry.IndexHintSelectUseCounter)
 			indexFlags = source.IndexFlags
 		}
+		if source.Sample != nil {
+			telemetry.Inc(sqltelemetry.TableSampleUseCounter)
+			sample = source.Sample
+		}
 
 		if source.As.Alias == "" {
 			// The alias is an empty string. If we are in a view or UDF
@@ -91,7 +99,7 @@ func (b *Builder) buildDataSource(
 			lockCtx.withoutTargets()
 		}
 
-		outScope = b.buildDataSource(source.Expr, indexFlags, lockCtx, inScope)
+		outScope = b.buildDataSource(source.Expr, indexFlags, sample, lockCtx, inScope)
 
 		if source.Ordinality {
 			outScope = b.buildWithOrdinality(outScope)
@@ -110,6 +118,9 @@ func (b *Builder) buildDataSource(
 
 		// CTEs take precedence over other data sources.
 		if cte := inScope.resolveCTE(tn); cte != nil {
+			if sample != nil {
+				panic(errTableSampleNotSupported)
+			}
 			lockCtx.locking.ignoreLockingForCTE()
 			outScope = inScope.push()
 			inCols := make(opt.ColList, len(cte.cols), len(cte.cols)+len(inScope.ordering))
@@ -170,14 +181,20 @@ func (b *Builder) buildDataSource(
 					includeSystem:    true,
 					includeInverted:  false,
 				}),
-				indexFlags, locking, inScope,
+				indexFlags, sample, locking, inScope,
 				false, /* disableNotVisibleIndex */
 			)
 
 		case cat.Sequence:
+			if sample != nil {
+				panic(errTableSampleNotSupported)
+			}
 			return b.buildSequenceSelect(t, &resName, inScope)
 
 		case cat.View:
+			if sample != nil {
+				panic(errTableSampleNotSupported)
+			}
 			return b.buildView(t, &resName, lockCtx, inScope)
 
 		default:
@@ -185,7 +202,7 @@ func (b *Builder) buildDataSource(
 		}
 
 	case *tree.ParenTableExpr:
-		return b.buildDataSource(source.Expr, indexFlags, lockCtx, inScope)
+		return b.buildDataSource(source.Expr, indexFlags, sample, lockCtx, inScope)
 
 	case *tree.RowsFromExpr:
 		return b.buildZip(source.Items, inScope)
@@ -493,7 +510,7 @@ func (b *Builder) buildScanFromTableRef(
 		locking = nil
 	}
 	return b.buildScan(
-		tabMeta, ordinals, indexFlags, locking, inScope, false, /* disableNotVisibleIndex */
+		tabMeta, ordinals, indexFlags, nil /* sample */, locking, inScope, false, /* disableNotVisibleIndex */
 	)
 }
 
@@ -536,6 +553,9 @@ func errorOnInvalidMultiregionDB(
 // be in the list (in practice, this coincides with all "ordinary" table columns
 // being in the list).
 //
+// If sample is not nil, the scan only returns a random sample of the rows of
+// the table, as specified by the TABLESAMPLE clause.
+//
 // If scanMutationCols is true, then include columns being added or dropped from
 // the table. These are currently required by the execution engine as "fetch
 // columns", when performing mutation DML statements (INSERT, UPDATE, UPSERT,
@@ -554,6 +574,7 @@ func (b *Builder) buildScan(
 	tabMeta *opt.TableMeta,
 	ordinals []int,
 	indexFlags *tree.IndexFlags,
+	sample *tree.TableSample,
 	locking lockingSpec,
 	inScope *scope,
 	disableNotVisibleIndex bool,
@@ -621,6 +642,10 @@ func (b *Builder) buildScan(
 			panic(pgerror.Newf(pgcode.Syntax,
 				"%s not allowed with virtual tables", locking.get().Strength))
 		}
+		if sample != nil {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"TABLESAMPLE not allowed with virtual tables"))
+		}
 		private := memo.ScanPrivate{Table: tabID, Cols: scanColIDs}
 		outScope.expr = b.factory.ConstructScan(&private)
 
@@ -731,6 +756,9 @@ func (b *Builder) buildScan(
 			}
 		}
 	}
+	if sample != nil {
+		private.Sample = b.buildTableSample(sample)
+	}
 	if locking.isSet() {
 		private.Locking = locking.get()
 		if b.shouldUseGuaranteedDurability() {
@@ -1394,7 +1422,7 @@ func (b *Builder) buildFromTables(
 func (b *Builder) buildFromTablesRightDeep(
 	tables tree.TableExprs, lockCtx lockingContext, inScope *scope,
 ) (outScope *scope) {
-	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, lockCtx, inScope)
+	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, nil /* sample */, lockCtx, inScope)
 
 	// Recursively build table join.
 	tables = tables[1:]
@@ -1444,7 +1472,7 @@ func (b *Builder) exprIsLateral(t tree.TableExpr) bool {
 func (b *Builder) buildFromWithLateral(
 	tables tree.TableExprs, lockCtx lockingContext, inScope *scope,
 ) (outScope *scope) {
-	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, lockCtx, inScope)
+	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, nil /* sample */, lockCtx, inScope)
 	for i := 1; i < len(tables); i++ {
 		scope := inScope
 		// Lateral expressions need to be able to refer to the expressions that
@@ -1453,7 +1481,7 @@ func (b *Builder) buildFromWithLateral(
 			scope = outScope
 			scope.context = exprKindLateralJoin
 		}
-		tableScope := b.buildDataSource(tables[i], nil /* indexFlags */, lockCtx, scope)
+		tableScope := b.buildDataSource(tables[i], nil /* indexFlags */, nil /* sample */, lockCtx, scope)
 
 		// Check that the same table name is not used multiple times.
 		b.validateJoinTableNames(outScope, tableScope)
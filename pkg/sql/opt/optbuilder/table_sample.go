@@ -0,0 +1,71 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+var errTableSampleNotSupported = pgerror.New(
+	pgcode.WrongObjectType,
+	"TABLESAMPLE clause can only be applied to tables and materialized views",
+)
+
+// buildTableSample builds the sampling properties of a scan from the given
+// TABLESAMPLE clause. The sampling percentage and the seed of the REPEATABLE
+// clause must be constant expressions; they are evaluated once when the query
+// is planned.
+func (b *Builder) buildTableSample(sample *tree.TableSample) opt.TableSample {
+	res := opt.TableSample{Method: sample.Method}
+
+	percent := b.buildTableSampleArg(sample.Percent)
+	if percent == tree.DNull {
+		panic(pgerror.New(pgcode.InvalidTablesampleArgument,
+			"TABLESAMPLE parameter cannot be null"))
+	}
+	p := float64(*percent.(*tree.DFloat))
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		panic(pgerror.New(pgcode.InvalidTablesampleArgument,
+			"sample percentage must be between 0 and 100"))
+	}
+	res.Probability = p / 100
+
+	if sample.Repeatable != nil {
+		seed := b.buildTableSampleArg(sample.Repeatable)
+		if seed == tree.DNull {
+			panic(pgerror.New(pgcode.InvalidTablesampleRepeat,
+				"TABLESAMPLE REPEATABLE parameter cannot be null"))
+		}
+		res.Seed = float64(*seed.(*tree.DFloat))
+		res.Repeatable = true
+	}
+	return res
+}
+
+// buildTableSampleArg builds an argument of a TABLESAMPLE clause, which cannot
+// reference any columns, and returns its value.
+func (b *Builder) buildTableSampleArg(arg tree.Expr) tree.Datum {
+	e := b.resolveAndBuildScalar(
+		arg, types.Float, exprKindTableSample, tree.RejectSpecial, b.allocScope(),
+	)
+	if !memo.CanExtractConstDatum(e) {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"TABLESAMPLE arguments must be constant expressions"))
+	}
+	return memo.ExtractConstDatum(e)
+}
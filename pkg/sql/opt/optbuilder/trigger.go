@@ -0,0 +1,663 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	plpgsql "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// Triggers are built as part of the mutation which fires them:
+//
+//   - BEFORE ROW triggers are projected on top of the mutation input. The
+//     trigger function is invoked with the NEW and OLD rows, and returns the
+//     row that should be used for the mutation. Rows for which the trigger
+//     function returns NULL are filtered out, so that they are skipped by the
+//     mutation.
+//
+//   - BEFORE STATEMENT triggers are built as a materialized With expression
+//     that wraps the mutation, so that the trigger function is invoked once
+//     before the mutation is executed.
+//
+//   - AFTER triggers are built as "cascades" (see memo.FKCascade), which are
+//     planned and executed after the mutation. AFTER ROW triggers read the
+//     modified rows from the buffered mutation input.
+//
+// For example, for the following trigger:
+//
+//	CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW EXECUTE FUNCTION f()
+//
+// the statement INSERT INTO t VALUES (1, 2) is built as a query like:
+//
+//	INSERT INTO t
+//	SELECT (r).a, (r).b FROM (
+//	  SELECT f(new, NULL, 'tr', 'BEFORE', 'ROW', 'INSERT', ...) AS r
+//	  FROM (SELECT (a, b) AS new FROM (VALUES (1, 2)) v(a, b))
+//	) WHERE r IS DISTINCT FROM NULL
+//
+// UPSERT and INSERT .. ON CONFLICT DO UPDATE statements fire both INSERT and
+// UPDATE triggers. BEFORE INSERT ROW triggers are applied to every proposed
+// row before conflicts are detected. BEFORE UPDATE ROW triggers are only
+// invoked for rows that conflict with an existing row, which is determined by
+// the canary column (see mutationBuilder.canaryColID). Similarly, AFTER INSERT
+// ROW triggers only fire for inserted rows, and AFTER UPDATE ROW triggers only
+// fire for updated rows.
+
+// triggerColumnOrdinals returns the ordinals of the table columns that are
+// part of the NEW and OLD rows that are passed to trigger functions.
+func triggerColumnOrdinals(tab cat.Table) []int {
+	ords := make([]int, 0, tab.ColumnCount())
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		col := tab.Column(i)
+		if col.Kind() == cat.Ordinary && col.Visibility() == cat.Visible {
+			ords = append(ords, i)
+		}
+	}
+	return ords
+}
+
+// triggerRowType returns the type of the NEW and OLD rows that are passed to
+// trigger functions.
+func triggerRowType(tab cat.Table, ords []int) *types.T {
+	contents := make([]*types.T, len(ords))
+	labels := make([]string, len(ords))
+	for i, ord := range ords {
+		col := tab.Column(ord)
+		contents[i] = col.DatumType()
+		labels[i] = string(col.ColName())
+	}
+	return types.MakeLabeledTuple(contents, labels)
+}
+
+// findTriggers returns the triggers on the given table with the given action
+// time and level that fire for the given event, in the order in which they
+// should be executed. For UPDATE, updatedOrds contains the ordinals of the
+// columns that are targets of the update.
+func findTriggers(
+	tab cat.Table,
+	eventType tree.TriggerEventType,
+	actionTime tree.TriggerActionTime,
+	forEachRow bool,
+	updatedOrds intsets.Fast,
+) []cat.Trigger {
+	var triggers []cat.Trigger
+	for i, n := 0, tab.TriggerCount(); i < n; i++ {
+		trigger := tab.Trigger(i)
+		if trigger.ActionTime() != actionTime || trigger.ForEachRow() != forEachRow {
+			continue
+		}
+		for j, m := 0, trigger.EventCount(); j < m; j++ {
+			event := trigger.Event(j)
+			if event.EventType != eventType {
+				continue
+			}
+			if len(event.ColumnOrdinals) > 0 {
+				// An UPDATE OF trigger only fires if one of its columns is a
+				// target of the update.
+				var found bool
+				for _, ord := range event.ColumnOrdinals {
+					if updatedOrds.Contains(ord) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					continue
+				}
+			}
+			triggers = append(triggers, trigger)
+			break
+		}
+	}
+	// Triggers of the same kind are fired in alphabetical order by name.
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].Name() < triggers[j].Name()
+	})
+	return triggers
+}
+
+// triggerUpdatedOrdinals returns the ordinals of the table columns that are
+// targets of the mutation.
+func (mb *mutationBuilder) triggerUpdatedOrdinals() intsets.Fast {
+	var ords intsets.Fast
+	for i, n := 0, mb.tab.ColumnCount(); i < n; i++ {
+		if mb.targetColSet.Contains(mb.tabID.ColumnID(i)) {
+			ords.Add(i)
+		}
+	}
+	return ords
+}
+
+// buildBeforeRowTriggers projects the results of the BEFORE ROW triggers that
+// fire for the given event on top of the mutation input. For INSERT and
+// UPDATE, the new values of the mutation are replaced with the row returned by
+// the trigger function. Rows for which a trigger function returns NULL are
+// removed from the mutation input.
+func (mb *mutationBuilder) buildBeforeRowTriggers(eventType tree.TriggerEventType) {
+	triggers := findTriggers(
+		mb.tab, eventType, tree.TriggerActionTimeBefore, true /* forEachRow */, mb.triggerUpdatedOrdinals(),
+	)
+	if len(triggers) == 0 {
+		return
+	}
+
+	// For an UPSERT, BEFORE UPDATE triggers only fire for rows which conflict
+	// with an existing row.
+	f := mb.b.factory
+	var fireCond opt.ScalarExpr
+	if eventType == tree.TriggerEventUpdate && mb.canaryColID != 0 {
+		fireCond = f.ConstructIsNot(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+	}
+
+	ords := triggerColumnOrdinals(mb.tab)
+	for _, trigger := range triggers {
+		var newCols, oldCols opt.OptionalColList
+		if eventType != tree.TriggerEventDelete {
+			newCols = make(opt.OptionalColList, len(ords))
+			for i, ord := range ords {
+				newCols[i] = mb.triggerNewColID(eventType, ord)
+			}
+		}
+		if eventType != tree.TriggerEventInsert {
+			oldCols = make(opt.OptionalColList, len(ords))
+			for i, ord := range ords {
+				oldCols[i] = mb.fetchColIDs[ord]
+			}
+		}
+		var resultCol opt.ColumnID
+		mb.outScope, resultCol = mb.b.buildRowTriggerCall(
+			mb.outScope, mb.tab, trigger, eventType, newCols, oldCols, fireCond,
+		)
+
+		// Skip the rows for which the trigger function returned NULL.
+		mb.outScope.expr = f.ConstructSelect(
+			mb.outScope.expr,
+			memo.FiltersExpr{f.ConstructFiltersItem(
+				f.ConstructIsNot(f.ConstructVariable(resultCol), memo.NullSingleton),
+			)},
+		)
+		if eventType == tree.TriggerEventDelete {
+			continue
+		}
+
+		// Replace the new values of the mutation with the values of the row
+		// returned by the trigger function. Computed columns are not replaced,
+		// since they are computed after BEFORE triggers are executed.
+		projectionsScope := mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		for i, ord := range ords {
+			tabCol := mb.tab.Column(ord)
+			if tabCol.IsComputed() {
+				continue
+			}
+			elem := f.ConstructColumnAccess(f.ConstructVariable(resultCol), memo.TupleOrdinal(i))
+			colName := scopeColName(tabCol.ColName()).WithMetadataName(
+				fmt.Sprintf("%s_%s", tabCol.ColName(), trigger.Name()),
+			)
+			col := mb.b.synthesizeColumn(projectionsScope, colName, tabCol.DatumType(), nil /* expr */, elem)
+			if eventType == tree.TriggerEventInsert {
+				mb.insertColIDs[ord] = col.id
+			} else {
+				mb.updateColIDs[ord] = col.id
+			}
+		}
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+	}
+}
+
+// triggerNewColID returns the ID of the column that holds the new value for
+// the table column with the given ordinal, or 0 if the value has not been
+// computed yet.
+func (mb *mutationBuilder) triggerNewColID(eventType tree.TriggerEventType, ord int) opt.ColumnID {
+	if eventType == tree.TriggerEventInsert {
+		return mb.insertColIDs[ord]
+	}
+	if colID := mb.updateColIDs[ord]; colID != 0 {
+		return colID
+	}
+	return mb.fetchColIDs[ord]
+}
+
+// buildBeforeStatementTriggers wraps the mutation expression in a With
+// expression which executes the BEFORE STATEMENT triggers that fire for the
+// given event. It must be called after the mutation expression is built.
+func (mb *mutationBuilder) buildBeforeStatementTriggers(eventType tree.TriggerEventType) {
+	triggers := findTriggers(
+		mb.tab, eventType, tree.TriggerActionTimeBefore, false /* forEachRow */, mb.triggerUpdatedOrdinals(),
+	)
+	if len(triggers) > 0 && mb.b.insideCascade {
+		// Cascading queries cannot have subqueries, which are used to execute
+		// BEFORE STATEMENT triggers before the mutation.
+		panic(unimplemented.NewWithIssuef(28296,
+			"BEFORE STATEMENT trigger %q cannot be fired by a cascading mutation", triggers[0].Name(),
+		))
+	}
+	// The With expressions are nested so that the first trigger is executed
+	// first.
+	for i := len(triggers) - 1; i >= 0; i-- {
+		inScope := mb.b.allocScope()
+		inScope.expr = mb.b.factory.ConstructNoColsRow()
+		triggerScope, _ := mb.b.buildRowTriggerCall(
+			inScope, mb.tab, triggers[i], eventType, nil /* newCols */, nil /* oldCols */, nil, /* fireCond */
+		)
+		mb.outScope.expr = mb.b.factory.ConstructWith(
+			triggerScope.expr,
+			mb.outScope.expr,
+			&memo.WithPrivate{
+				ID:   mb.b.factory.Memo().NextWithID(),
+				Mtr:  tree.CTEMaterializeAlways,
+				Name: fmt.Sprintf("trigger_%s", triggers[i].Name()),
+			},
+		)
+	}
+}
+
+// buildAfterTriggers adds a cascade for each AFTER trigger that fires for the
+// given event. The cascades are executed after the mutation. It must be called
+// before the mutation private is built.
+//
+// For an UPSERT, the canary column is passed to AFTER ROW triggers as the last
+// of the old values, so that the triggers can tell inserted and updated rows
+// apart.
+func (mb *mutationBuilder) buildAfterTriggers(eventType tree.TriggerEventType) {
+	updatedOrds := mb.triggerUpdatedOrdinals()
+	ords := triggerColumnOrdinals(mb.tab)
+	isUpsert := mb.canaryColID != 0
+	for _, forEachRow := range []bool{true, false} {
+		triggers := findTriggers(mb.tab, eventType, tree.TriggerActionTimeAfter, forEachRow, updatedOrds)
+		for _, trigger := range triggers {
+			cascade := memo.FKCascade{
+				Trigger: trigger,
+				Builder: newAfterTriggerBuilder(mb.tab, trigger, eventType, isUpsert && forEachRow),
+			}
+			if forEachRow {
+				mb.ensureWithID()
+				cascade.WithID = mb.withID
+				if eventType != tree.TriggerEventInsert {
+					cascade.OldValues = make(opt.ColList, len(ords), len(ords)+1)
+					for i, ord := range ords {
+						cascade.OldValues[i] = mb.fetchColIDs[ord]
+					}
+				}
+				if isUpsert {
+					cascade.OldValues = append(cascade.OldValues, mb.canaryColID)
+				}
+				if eventType != tree.TriggerEventDelete {
+					cascade.NewValues = make(opt.ColList, len(ords))
+					for i, ord := range ords {
+						cascade.NewValues[i] = mb.triggerNewColID(eventType, ord)
+					}
+				}
+			}
+			mb.cascades = append(mb.cascades, cascade)
+		}
+	}
+}
+
+// afterTriggerBuilder is a memo.CascadeBuilder implementation for AFTER
+// triggers.
+//
+// It provides a method to build a query that executes the trigger function for
+// every modified row (or once, for statement-level triggers), equivalent to a
+// query like:
+//
+//	SELECT f(new, old, ...) FROM original_mutation_input WHERE false
+//
+// An optimization barrier ensures that the trigger function is executed even
+// though no rows are returned.
+type afterTriggerBuilder struct {
+	mutatedTable cat.Table
+	trigger      cat.Trigger
+	eventType    tree.TriggerEventType
+
+	// upsert is true if the trigger is a row-level trigger fired by an UPSERT.
+	// In this case the last of the old values is the canary column, which is
+	// NULL for inserted rows and non-NULL for updated rows.
+	upsert bool
+}
+
+var _ memo.CascadeBuilder = &afterTriggerBuilder{}
+
+func newAfterTriggerBuilder(
+	mutatedTable cat.Table, trigger cat.Trigger, eventType tree.TriggerEventType, upsert bool,
+) *afterTriggerBuilder {
+	return &afterTriggerBuilder{
+		mutatedTable: mutatedTable,
+		trigger:      trigger,
+		eventType:    eventType,
+		upsert:       upsert,
+	}
+}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (tb *afterTriggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		opt.MaybeInjectOptimizerTestingPanic(ctx, evalCtx)
+
+		f := b.factory
+		inScope := b.allocScope()
+		var newCols, oldCols opt.OptionalColList
+		if binding == 0 {
+			// Statement-level triggers do not use the mutation input.
+			inScope.expr = f.ConstructNoColsRow()
+		} else {
+			md := f.Metadata()
+			inCols := make(opt.ColList, 0, len(oldValues)+len(newValues))
+			inCols = append(inCols, oldValues...)
+			inCols = append(inCols, newValues...)
+			outCols := make(opt.ColList, len(inCols))
+			for i := range outCols {
+				c := md.ColumnMeta(inCols[i])
+				outCols[i] = md.AddColumn(c.Alias, c.Type)
+			}
+
+			// Construct a dummy operator as the binding.
+			md.AddWithBinding(binding, f.ConstructFakeRel(&memo.FakeRelPrivate{
+				Props: bindingProps,
+			}))
+			inScope.expr = f.ConstructWithScan(&memo.WithScanPrivate{
+				With:    binding,
+				InCols:  inCols,
+				OutCols: outCols,
+				ID:      md.NextUniqueID(),
+			})
+			numOldValues := len(oldValues)
+			if tb.upsert {
+				// Only fire the trigger for the rows that were inserted (or
+				// updated, for UPDATE triggers).
+				numOldValues--
+				canary := f.ConstructVariable(outCols[numOldValues])
+				var filter opt.ScalarExpr
+				if tb.eventType == tree.TriggerEventInsert {
+					filter = f.ConstructIs(canary, memo.NullSingleton)
+				} else {
+					filter = f.ConstructIsNot(canary, memo.NullSingleton)
+				}
+				inScope.expr = f.ConstructSelect(
+					inScope.expr, memo.FiltersExpr{f.ConstructFiltersItem(filter)},
+				)
+			}
+			if numOldValues > 0 {
+				oldCols = opt.OptionalColList(outCols[:numOldValues])
+			}
+			if len(newValues) > 0 {
+				newCols = opt.OptionalColList(outCols[len(oldValues):])
+			}
+		}
+
+		outScope, _ := b.buildRowTriggerCall(
+			inScope, tb.mutatedTable, tb.trigger, tb.eventType, newCols, oldCols, nil, /* fireCond */
+		)
+
+		// The results of the trigger function are discarded.
+		return f.ConstructSelect(
+			outScope.expr,
+			memo.FiltersExpr{f.ConstructFiltersItem(memo.FalseSingleton)},
+		)
+	})
+}
+
+// buildRowTriggerCall projects the result of the given trigger on top of the
+// input scope, and returns the resulting scope and the ID of the result
+// column. newCols and oldCols contain the IDs of the columns holding the
+// NEW and OLD values of the columns returned by triggerColumnOrdinals, and
+// are nil if the NEW or OLD row is not available. A zero column ID indicates
+// a NULL value. The result column is NULL if the trigger function returns NULL.
+//
+// If the trigger has a WHEN condition, the trigger function is only invoked for
+// rows which satisfy the condition; for other rows the result column holds the
+// unmodified NEW row (or the OLD row for DELETE triggers). If fireCond is
+// non-nil, the trigger function is additionally only invoked for rows for
+// which fireCond is true.
+//
+// The resulting expression is wrapped in an optimization barrier, so that the
+// trigger function is not duplicated, eliminated, or reordered.
+func (b *Builder) buildRowTriggerCall(
+	inScope *scope,
+	tab cat.Table,
+	trigger cat.Trigger,
+	eventType tree.TriggerEventType,
+	newCols, oldCols opt.OptionalColList,
+	fireCond opt.ScalarExpr,
+) (outScope *scope, resultCol opt.ColumnID) {
+	f := b.factory
+	ords := triggerColumnOrdinals(tab)
+	rowTyp := triggerRowType(tab, ords)
+
+	// Project the NEW and OLD rows as tuples. The columns of the rows are
+	// projected as well, so that they can be referenced by the WHEN condition
+	// as new.<column> and old.<column>.
+	rowScope := inScope.replace()
+	rowScope.appendColumnsFromScope(inScope)
+	buildRow := func(cols opt.OptionalColList, tableName string) opt.ScalarExpr {
+		if cols == nil {
+			return f.ConstructNull(rowTyp)
+		}
+		elems := make(memo.ScalarListExpr, len(ords))
+		for i, ord := range ords {
+			tabCol := tab.Column(ord)
+			if cols[i] == 0 {
+				elems[i] = f.ConstructNull(tabCol.DatumType())
+			} else {
+				elems[i] = f.ConstructVariable(cols[i])
+			}
+			if trigger.WhenExpr() != "" {
+				col := b.synthesizeColumn(
+					rowScope, scopeColName(tabCol.ColName()), tabCol.DatumType(), nil /* expr */, elems[i],
+				)
+				col.table = tree.MakeUnqualifiedTableName(tree.Name(tableName))
+			}
+		}
+		return f.ConstructTuple(elems, rowTyp)
+	}
+	newRow := buildRow(newCols, "new")
+	oldRow := buildRow(oldCols, "old")
+	b.constructProjectForScope(inScope, rowScope)
+
+	call := b.buildTriggerFunctionCall(tab, trigger, eventType, rowTyp, newRow, oldRow)
+	cond := fireCond
+	if whenExpr := trigger.WhenExpr(); whenExpr != "" {
+		expr, err := parser.ParseExpr(whenExpr)
+		if err != nil {
+			panic(err)
+		}
+		texpr := rowScope.resolveAndRequireType(expr, types.Bool)
+		whenCond := b.buildScalar(texpr, rowScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+		if cond == nil {
+			cond = whenCond
+		} else {
+			cond = f.ConstructAnd(cond, whenCond)
+		}
+	}
+	if cond != nil {
+		unmodified := newRow
+		if eventType == tree.TriggerEventDelete {
+			unmodified = oldRow
+		}
+		call = f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{f.ConstructWhen(cond, call)},
+			unmodified,
+		)
+	}
+
+	outScope = inScope.replace()
+	outScope.appendColumnsFromScope(inScope)
+	colName := scopeColName("").WithMetadataName(fmt.Sprintf("trigger_%s", trigger.Name()))
+	col := b.synthesizeColumn(outScope, colName, rowTyp, nil /* expr */, call)
+	outScope.expr = f.ConstructBarrier(b.constructProject(rowScope.expr, outScope.cols))
+	return outScope, col.id
+}
+
+// buildTriggerFunctionCall builds an invocation of the trigger function of the
+// given trigger. The function returns the row that is used for the mutation,
+// or NULL if the row should be skipped. The return value is ignored for
+// statement-level and AFTER triggers.
+//
+// In addition to the NEW and OLD rows, the trigger function has access to the
+// following variables, which are passed as arguments:
+//
+//	TG_NAME, TG_WHEN, TG_LEVEL, TG_OP, TG_RELID, TG_TABLE_NAME,
+//	TG_TABLE_SCHEMA, TG_NARGS, TG_ARGV
+func (b *Builder) buildTriggerFunctionCall(
+	tab cat.Table,
+	trigger cat.Trigger,
+	eventType tree.TriggerEventType,
+	rowTyp *types.T,
+	newRow, oldRow opt.ScalarExpr,
+) opt.ScalarExpr {
+	f := b.factory
+	name, o, err := b.catalog.ResolveFunctionByOID(b.ctx, trigger.FuncOID())
+	if err != nil {
+		panic(err)
+	}
+	if o.Language != tree.RoutineLangPLpgSQL {
+		panic(errors.AssertionFailedf("unexpected trigger function language: %v", o.Language))
+	}
+	f.Metadata().AddUserDefinedFunction(o, nil /* name */)
+
+	// Detect recursive BEFORE triggers, which would otherwise cause infinite
+	// recursion while building the trigger function.
+	triggerKey := fmt.Sprintf("%d.%s", tab.ID(), trigger.Name())
+	if _, ok := b.triggersInProgress[triggerKey]; ok {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"recursive invocation of trigger %q on table %q is not supported",
+			trigger.Name(), tab.Name(),
+		))
+	}
+	if b.triggersInProgress == nil {
+		b.triggersInProgress = make(map[string]struct{})
+	}
+	b.triggersInProgress[triggerKey] = struct{}{}
+	defer delete(b.triggersInProgress, triggerKey)
+
+	tabName, err := b.catalog.FullyQualifiedName(b.ctx, tab)
+	if err != nil {
+		panic(err)
+	}
+	level := "STATEMENT"
+	if trigger.ForEachRow() {
+		level = "ROW"
+	}
+	funcArgs := trigger.FuncArgs()
+	argv := tree.NewDArray(types.String)
+	for _, arg := range funcArgs {
+		if err := argv.Append(tree.NewDString(arg)); err != nil {
+			panic(err)
+		}
+	}
+
+	// The trigger variables are passed to the trigger function as arguments.
+	type triggerParam struct {
+		name string
+		typ  *types.T
+		arg  opt.ScalarExpr
+	}
+	triggerParams := []triggerParam{
+		{name: "new", typ: rowTyp, arg: newRow},
+		{name: "old", typ: rowTyp, arg: oldRow},
+		{name: "tg_name", typ: types.Name, arg: f.ConstructConstVal(tree.NewDName(string(trigger.Name())), types.Name)},
+		{name: "tg_when", typ: types.String, arg: f.ConstructConstVal(tree.NewDString(trigger.ActionTime().String()), types.String)},
+		{name: "tg_level", typ: types.String, arg: f.ConstructConstVal(tree.NewDString(level), types.String)},
+		{name: "tg_op", typ: types.String, arg: f.ConstructConstVal(tree.NewDString(eventType.String()), types.String)},
+		{name: "tg_relid", typ: types.Oid, arg: f.ConstructConstVal(tree.NewDOid(oid.Oid(tab.ID())), types.Oid)},
+		{name: "tg_table_name", typ: types.Name, arg: f.ConstructConstVal(tree.NewDName(string(tab.Name())), types.Name)},
+		{name: "tg_table_schema", typ: types.Name, arg: f.ConstructConstVal(tree.NewDName(tabName.Schema()), types.Name)},
+		{name: "tg_nargs", typ: types.Int, arg: f.ConstructConstVal(tree.NewDInt(tree.DInt(len(funcArgs))), types.Int)},
+		{name: "tg_argv", typ: types.StringArray, arg: f.ConstructConstVal(argv, types.StringArray)},
+	}
+	args := make(memo.ScalarListExpr, len(triggerParams))
+	params := make(opt.ColList, len(triggerParams))
+	routineParams := make([]routineParam, len(triggerParams))
+	bodyScope := b.allocScope()
+	for i := range triggerParams {
+		p := &triggerParams[i]
+		args[i] = p.arg
+		col := b.synthesizeColumn(bodyScope, funcParamColName(tree.Name(p.name), i), p.typ, nil /* expr */, nil /* scalar */)
+		col.setParamOrd(i)
+		params[i] = col.id
+		routineParams[i] = routineParam{name: tree.Name(p.name), typ: p.typ, class: tree.RoutineParamIn}
+	}
+
+	// Build the body of the trigger function. It is built in the same way as
+	// the body of a PL/pgSQL function which returns the row type of the table.
+	oldTrackingSchemaDeps := b.trackSchemaDeps
+	oldInsideUDF := b.insideUDF
+	oldInsideDataSource := b.insideDataSource
+	defer func() {
+		b.trackSchemaDeps = oldTrackingSchemaDeps
+		b.insideUDF = oldInsideUDF
+		b.insideDataSource = oldInsideDataSource
+	}()
+	b.trackSchemaDeps = false
+	b.insideUDF = true
+	b.insideDataSource = false
+
+	stmt, err := plpgsql.Parse(o.Body)
+	if err != nil {
+		panic(err)
+	}
+	plBuilder := newPLpgSQLBuilder(
+		b, name.Object(), nil /* colRefs */, routineParams, rowTyp,
+		false /* setReturning */, false, /* isProcedure */
+	)
+	stmtScope := plBuilder.buildRootBlock(stmt.AST, bodyScope, routineParams)
+	expr, physProps, _ := b.finishBuildLastStmt(stmtScope, bodyScope, false /* isSetReturning */, rowTyp)
+	var bodyStmts []string
+	if b.verboseTracing {
+		bodyStmts = []string{stmt.String()}
+	}
+
+	return f.ConstructUDFCall(
+		args,
+		&memo.UDFCallPrivate{
+			Def: &memo.UDFDefinition{
+				Name:              name.Object(),
+				Typ:               rowTyp,
+				Volatility:        o.Volatility,
+				CalledOnNullInput: true,
+				RoutineType:       o.Type,
+				Body:              []memo.RelExpr{expr},
+				BodyProps:         []*physical.Required{physProps},
+				BodyStmts:         bodyStmts,
+				Params:            params,
+			},
+		},
+	)
+}
@@ -319,6 +319,10 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.updateColIDs)
 
+	// Apply BEFORE triggers, which may modify the updated values. This must
+	// happen before computed columns are added.
+	mb.buildBeforeRowTriggers(tree.TriggerEventUpdate)
+
 	// Disambiguate names so that references in the computed expression refer to
 	// the correct columns.
 	mb.disambiguateColumns()
@@ -352,6 +356,8 @@ func (mb *mutationBuilder) buildUpdate(returning *tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpdate()
 
+	mb.buildAfterTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -361,5 +367,6 @@ func (mb *mutationBuilder) buildUpdate(returning *tree.ReturningExprs) {
 	mb.outScope.expr = mb.b.factory.ConstructUpdate(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
+	mb.buildBeforeStatementTriggers(tree.TriggerEventUpdate)
 	mb.buildReturning(returning)
 }
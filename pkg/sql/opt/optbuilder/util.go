@@ -617,8 +617,8 @@ func (b *Builder) resolveTableForMutation(
 		alias = *outerAlias
 	}
 
-	// We can't mutate materialized views.
-	if tab.IsMaterializedView() {
+	// We can't mutate materialized views, except when they are refreshed.
+	if tab.IsMaterializedView() && !b.evalCtx.SessionData().AllowMaterializedViewMutations {
 		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate materialized view %q", tab.Name()))
 	}
 
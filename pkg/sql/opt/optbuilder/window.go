@@ -275,11 +275,20 @@ func (b *Builder) buildAggregationAsWindow(
 			orderings[i].FromOrdering(ord)
 		}
 
+		var filterCol *scopeColumn
 		if agg.Filter != nil {
-			col := b.buildFilterCol(agg.Filter, i, agg.def.Name, fromScope, g.aggInScope)
-			filterCols[i] = col.id
+			filterCol = b.buildFilterCol(agg.Filter, i, agg.def.Name, fromScope, g.aggInScope)
+			filterCols[i] = filterCol.id
+		}
+		if g.groupingSetInputCol != 0 {
+			// Ignore the row added for empty grouping sets.
+			filterCols[i] = b.buildGroupingSetsAggFilter(g, filterCol)
 		}
 	}
+	if g.groupingSetInputCol != 0 {
+		// Render the filter columns added by buildGroupingSetsAggFilter.
+		b.constructProjectForScope(fromScope, g.aggInScope)
+	}
 
 	// Initialize the aggregate expression.
 	aggregateExpr := g.aggInScope.expr
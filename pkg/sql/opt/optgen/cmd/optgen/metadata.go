@@ -245,6 +245,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"SchemaTypeDeps":       {fullName: "opt.SchemaTypeDeps", passByVal: true},
 		"SchemaFunctionDeps":   {fullName: "opt.SchemaFunctionDeps", passByVal: true},
 		"Locking":              {fullName: "opt.Locking", passByVal: true},
+		"TableSample":          {fullName: "opt.TableSample", passByVal: true},
 		"CTEMaterializeClause": {fullName: "tree.CTEMaterializeClause", passByVal: true},
 		"SpanExpression":       {fullName: "inverted.SpanExpression", isPointer: true, usePointerIntern: true},
 		"InvertedSpans":        {fullName: "inverted.Spans", passByVal: true},
@@ -0,0 +1,56 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// TableSample represents the TABLESAMPLE clause of a table scan. The zero value
+// means that the table is not sampled.
+type TableSample struct {
+	// Method is the sampling method. BERNOULLI sampling decides for each row
+	// whether it is returned, while SYSTEM sampling decides for each range of
+	// the table whether all of its rows are returned.
+	Method tree.TableSampleMethod
+
+	// Probability is the probability with which each row or range is returned,
+	// between 0 and 1.
+	Probability float64
+
+	// Seed is the seed given by the REPEATABLE clause. It is only meaningful if
+	// Repeatable is true; otherwise, a random seed is chosen each time the
+	// query is executed.
+	Seed float64
+
+	// Repeatable is true if the REPEATABLE clause was specified, in which case
+	// the same seed returns the same sample as long as the table is not
+	// modified.
+	Repeatable bool
+}
+
+// IsSet returns true if the table is sampled.
+func (s TableSample) IsSet() bool {
+	return s.Method != tree.TableSampleNone
+}
+
+// String returns a description of the sample, like "bernoulli 10% seed=42".
+func (s TableSample) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %g%%", strings.ToLower(s.Method.String()), s.Probability*100)
+	if s.Repeatable {
+		fmt.Fprintf(&b, " seed=%g", s.Seed)
+	}
+	return b.String()
+}
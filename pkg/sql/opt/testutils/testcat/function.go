@@ -83,6 +83,7 @@ func (tc *Catalog) CreateRoutine(c *tree.CreateRoutine) {
 
 	// Resolve the parameter names and types.
 	signatureTypes := make(tree.ParamTypes, 0, len(c.Params))
+	variadic := false
 	var outParamTypes []*types.T
 	var outParamNames []string
 	for i := range c.Params {
@@ -96,6 +97,7 @@ func (tc *Catalog) CreateRoutine(c *tree.CreateRoutine) {
 				Name: string(param.Name),
 				Typ:  typ,
 			})
+			variadic = param.Class == tree.RoutineParamVariadic
 		}
 		if param.IsOutParam() {
 			outParamTypes = append(outParamTypes, typ)
@@ -167,6 +169,10 @@ func (tc *Catalog) CreateRoutine(c *tree.CreateRoutine) {
 		Language:          language,
 		Type:              routineType,
 		RoutineParams:     c.Params,
+		Variadic:          variadic,
+	}
+	if tree.ContainsPolymorphicType(retType) {
+		overload.ReturnType = tree.PolymorphicReturnType(signatureTypes, variadic, retType)
 	}
 	overload.ReturnsRecordType = types.IsRecordType(retType)
 	if c.ReturnType != nil && c.ReturnType.SetOf {
@@ -774,6 +774,8 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []optCheckConstraint
 
+	triggers []optTrigger
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -907,12 +909,16 @@ func newOptTable(
 	ot.uniqueConstraints = make([]optUniqueConstraint, len(ot.desc.EnforcedUniqueConstraintsWithoutIndex()))
 	for i, u := range ot.desc.EnforcedUniqueConstraintsWithoutIndex() {
 		ot.uniqueConstraints[i] = optUniqueConstraint{
-			name:         u.GetName(),
-			table:        ot.ID(),
-			columns:      u.CollectKeyColumnIDs().Ordered(),
-			predicate:    u.GetPredicate(),
-			withoutIndex: true,
-			validity:     u.GetConstraintValidity(),
+			name:          u.GetName(),
+			table:         ot.ID(),
+			columns:       u.CollectKeyColumnIDs().Ordered(),
+			predicate:     u.GetPredicate(),
+			withoutIndex:  true,
+			validity:      u.GetConstraintValidity(),
+			deferrability: tree.ConstraintDeferrabilityType[u.Deferrability()],
+		}
+		if u.IsExclusion() {
+			ot.uniqueConstraints[i].initExclusionElements(u.UniqueWithoutIndexDesc().Exclusion)
 		}
 	}
 
@@ -1017,6 +1023,7 @@ func newOptTable(
 			match:             tree.CompositeKeyMatchMethodType[fk.Match()],
 			deleteAction:      tree.ForeignKeyReferenceActionType[fk.OnDelete()],
 			updateAction:      tree.ForeignKeyReferenceActionType[fk.OnUpdate()],
+			deferrability:     tree.ConstraintDeferrabilityType[fk.Deferrability()],
 		})
 	}
 	for _, fk := range ot.desc.InboundForeignKeys() {
@@ -1030,6 +1037,7 @@ func newOptTable(
 			match:             tree.CompositeKeyMatchMethodType[fk.Match()],
 			deleteAction:      tree.ForeignKeyReferenceActionType[fk.OnDelete()],
 			updateAction:      tree.ForeignKeyReferenceActionType[fk.OnUpdate()],
+			deferrability:     tree.ConstraintDeferrabilityType[fk.Deferrability()],
 		})
 	}
 
@@ -1084,6 +1092,17 @@ func newOptTable(
 	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	// Add triggers.
+	triggers := desc.GetTriggers()
+	if len(triggers) > 0 {
+		ot.triggers = make([]optTrigger, len(triggers))
+		for i := range triggers {
+			if err := ot.triggers[i].init(ot, &triggers[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -1309,6 +1328,16 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return &ot.triggers[i]
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
@@ -1783,6 +1812,87 @@ func (oc *optCheckConstraint) ColumnOrdinal(i int) int {
 	return ord
 }
 
+// optTrigger implements cat.Trigger. See that interface for more information
+// on the fields.
+type optTrigger struct {
+	desc   *descpb.TableDescriptor_Trigger
+	events []cat.TriggerEvent
+}
+
+var _ cat.Trigger = &optTrigger{}
+
+func (ot *optTrigger) init(tab *optTable, desc *descpb.TableDescriptor_Trigger) error {
+	ot.desc = desc
+	ot.events = make([]cat.TriggerEvent, len(desc.Events))
+	for i := range desc.Events {
+		ev := &desc.Events[i]
+		switch ev.Type {
+		case descpb.TableDescriptor_Trigger_INSERT:
+			ot.events[i].EventType = tree.TriggerEventInsert
+		case descpb.TableDescriptor_Trigger_UPDATE:
+			ot.events[i].EventType = tree.TriggerEventUpdate
+		case descpb.TableDescriptor_Trigger_DELETE:
+			ot.events[i].EventType = tree.TriggerEventDelete
+		default:
+			return errors.AssertionFailedf("unexpected trigger event type %s", ev.Type)
+		}
+		if len(ev.ColumnIDs) > 0 {
+			ot.events[i].ColumnOrdinals = make([]int, len(ev.ColumnIDs))
+			for j, colID := range ev.ColumnIDs {
+				ord, err := tab.lookupColumnOrdinal(colID)
+				if err != nil {
+					return err
+				}
+				ot.events[i].ColumnOrdinals[j] = ord
+			}
+		}
+	}
+	return nil
+}
+
+// Name is part of the cat.Trigger interface.
+func (ot *optTrigger) Name() tree.Name {
+	return tree.Name(ot.desc.Name)
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (ot *optTrigger) ActionTime() tree.TriggerActionTime {
+	if ot.desc.ActionTime == descpb.TableDescriptor_Trigger_AFTER {
+		return tree.TriggerActionTimeAfter
+	}
+	return tree.TriggerActionTimeBefore
+}
+
+// EventCount is part of the cat.Trigger interface.
+func (ot *optTrigger) EventCount() int {
+	return len(ot.events)
+}
+
+// Event is part of the cat.Trigger interface.
+func (ot *optTrigger) Event(i int) cat.TriggerEvent {
+	return ot.events[i]
+}
+
+// ForEachRow is part of the cat.Trigger interface.
+func (ot *optTrigger) ForEachRow() bool {
+	return ot.desc.ForEachRow
+}
+
+// WhenExpr is part of the cat.Trigger interface.
+func (ot *optTrigger) WhenExpr() string {
+	return ot.desc.WhenExpr
+}
+
+// FuncOID is part of the cat.Trigger interface.
+func (ot *optTrigger) FuncOID() oid.Oid {
+	return catid.FuncIDToOID(ot.desc.FuncID)
+}
+
+// FuncArgs is part of the cat.Trigger interface.
+func (ot *optTrigger) FuncArgs() []string {
+	return ot.desc.FuncArgs
+}
+
 type optTableStat struct {
 	stat           *stats.TableStatistic
 	columnOrdinals []int
@@ -1936,14 +2046,53 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability tree.ConstraintDeferrability
 
 	uniquenessGuaranteedByAnotherIndex bool
+
+	// exclusionElements is set if the constraint is an exclusion constraint.
+	exclusionElements []cat.ExclusionElement
 }
 
 var _ cat.UniqueConstraint = &optUniqueConstraint{}
 
+// initExclusionElements initializes the elements of an exclusion constraint.
+func (u *optUniqueConstraint) initExclusionElements(excl *descpb.ExclusionConstraint) {
+	columnIndex := func(colID descpb.ColumnID) int {
+		for i := range u.columns {
+			if u.columns[i] == colID {
+				return i
+			}
+		}
+		panic(errors.AssertionFailedf(
+			"column %d is not part of exclusion constraint %q", colID, u.name,
+		))
+	}
+	u.exclusionElements = make([]cat.ExclusionElement, len(excl.Elements))
+	for i := range excl.Elements {
+		e := &excl.Elements[i]
+		elem := &u.exclusionElements[i]
+		switch e.Operator {
+		case "=":
+			elem.Operator = treecmp.EQ
+		case "<>":
+			elem.Operator = treecmp.NE
+		case "&&":
+			elem.Operator = treecmp.Overlaps
+		default:
+			panic(errors.AssertionFailedf("unknown exclusion constraint operator %q", e.Operator))
+		}
+		elem.Column = columnIndex(e.ColumnIDs[0])
+		if e.IsRange() {
+			elem.IsRange = true
+			elem.UpperColumn = columnIndex(e.ColumnIDs[1])
+			elem.InclusiveBounds = e.InclusiveBounds()
+		}
+	}
+}
+
 // Name is part of the cat.UniqueConstraint interface.
 func (u *optUniqueConstraint) Name() string {
 	return u.name
@@ -1995,6 +2144,21 @@ func (u *optUniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return u.uniquenessGuaranteedByAnotherIndex
 }
 
+// ExclusionElementCount is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) ExclusionElementCount() int {
+	return len(u.exclusionElements)
+}
+
+// ExclusionElement is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) ExclusionElement(i int) cat.ExclusionElement {
+	return u.exclusionElements[i]
+}
+
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -2008,10 +2172,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validity      descpb.ConstraintValidity
+	match         tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -2083,6 +2248,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2385,6 +2555,16 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
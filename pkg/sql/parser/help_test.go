@@ -105,6 +105,10 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER TYPE t RENAME ??`, `ALTER TYPE`},
 		{`ALTER TYPE t DROP VALUE ??`, `ALTER TYPE`},
 
+		{`ALTER DOMAIN ??`, `ALTER DOMAIN`},
+		{`ALTER DOMAIN d ??`, `ALTER DOMAIN`},
+		{`ALTER DOMAIN d DROP ??`, `ALTER DOMAIN`},
+
 		{`ALTER INDEX foo@bar RENAME ??`, `ALTER INDEX`},
 		{`ALTER INDEX foo@bar RENAME TO blih ??`, `ALTER INDEX`},
 		{`ALTER INDEX foo@bar SPLIT ??`, `ALTER INDEX`},
@@ -208,6 +212,10 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
 		{`DROP TYPE ??`, `DROP TYPE`},
 
+		{`CREATE DOMAIN ??`, `CREATE DOMAIN`},
+		{`CREATE DOMAIN d ??`, `CREATE DOMAIN`},
+		{`DROP DOMAIN ??`, `DROP DOMAIN`},
+
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA bli ??`, `CREATE SCHEMA`},
@@ -304,6 +312,13 @@ func TestContextualHelp(t *testing.T) {
 		{`INSERT INTO blah VALUES (1) ??`, `VALUES`},
 		{`INSERT INTO blah TABLE foo ??`, `TABLE`},
 
+		{`LISTEN ??`, `LISTEN`},
+		{`NOTIFY ??`, `NOTIFY`},
+		{`UNLISTEN ??`, `UNLISTEN`},
+
+		{`MERGE ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON a = b ??`, `MERGE`},
+
 		{`UPSERT INTO ??`, `UPSERT`},
 		{`UPSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`UPSERT INTO blah VALUES (1) RETURNING ??`, `UPSERT`},
@@ -525,6 +540,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
 		{`SET TIME ??`, `SET SESSION`},
 		{`SET TIME ZONE 'UTC' ??`, `SET SESSION`},
 		{`SET blah TO ??`, `SET SESSION`},
@@ -549,6 +566,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`REFRESH ??`, `REFRESH`},
 
+		{`REINDEX ??`, `REINDEX`},
+		{`REINDEX TABLE ??`, `REINDEX`},
+
 		{`ROLLBACK TRANSACTION ??`, `ROLLBACK`},
 		{`ROLLBACK TO ??`, `ROLLBACK`},
 
@@ -588,6 +608,17 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE PROCEDURE ??`, `CREATE PROCEDURE`},
 		{`ALTER PROCEDURE ??`, `ALTER PROCEDURE`},
 		{`DROP PROCEDURE ??`, `DROP PROCEDURE`},
+
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE TRIGGER foo BEFORE ??`, `CREATE TRIGGER`},
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+
+		{`CREATE PUBLICATION ??`, `CREATE PUBLICATION`},
+		{`CREATE PUBLICATION foo FOR ??`, `CREATE PUBLICATION`},
+		{`DROP PUBLICATION ??`, `DROP PUBLICATION`},
+		{`CREATE SUBSCRIPTION ??`, `CREATE SUBSCRIPTION`},
+		{`CREATE SUBSCRIPTION foo CONNECTION ??`, `CREATE SUBSCRIPTION`},
+		{`DROP SUBSCRIPTION ??`, `DROP SUBSCRIPTION`},
 	}
 
 	// The following checks that the test definition above exercises all
@@ -380,6 +380,17 @@ func (l *lexer) UnimplementedWithIssueDetail(issue int, detail string) {
 	}
 }
 
+// UnimplementedWithIssueDetailHint is like UnimplementedWithIssueDetail, but
+// also attaches the given hint to the error.
+func (l *lexer) UnimplementedWithIssueDetailHint(issue int, detail, hint string) {
+	l.lastError = errors.WithHint(unimp.NewWithIssueDetail(issue, detail, "this syntax"), hint)
+	l.populateErrorDetails()
+	l.lastError = &tree.UnsupportedError{
+		Err:         l.lastError,
+		FeatureName: detail,
+	}
+}
+
 // Unimplemented wraps Error, setting lastUnimplementedError.
 func (l *lexer) Unimplemented(feature string) {
 	l.lastError = unimp.New(feature, "this syntax")
@@ -75,6 +75,11 @@ func unimplementedWithIssueDetail(sqllex sqlLexer, issue int, detail string) int
     return 1
 }
 
+func unimplementedWithIssueDetailHint(sqllex sqlLexer, issue int, detail, hint string) int {
+    sqllex.(*lexer).UnimplementedWithIssueDetailHint(issue, detail, hint)
+    return 1
+}
+
 func processBinaryQualOp(
   sqllex sqlLexer,
   op tree.Operator,
@@ -306,6 +311,12 @@ func (u *sqlSymUnion) enumValueList() tree.EnumValueList {
 func (u *sqlSymUnion) compositeTypeList() []tree.CompositeTypeElem {
     return u.val.([]tree.CompositeTypeElem)
 }
+func (u *sqlSymUnion) domainConstraint() tree.DomainConstraint {
+    return u.val.(tree.DomainConstraint)
+}
+func (u *sqlSymUnion) domainConstraints() []tree.DomainConstraint {
+    return u.val.([]tree.DomainConstraint)
+}
 func (u *sqlSymUnion) unresolvedName() *tree.UnresolvedName {
     return u.val.(*tree.UnresolvedName)
 }
@@ -324,6 +335,12 @@ func (u *sqlSymUnion) tableNames() tree.TableNames {
 func (u *sqlSymUnion) indexFlags() *tree.IndexFlags {
     return u.val.(*tree.IndexFlags)
 }
+func (u *sqlSymUnion) tableSample() *tree.TableSample {
+    if sample, ok := u.val.(*tree.TableSample); ok {
+        return sample
+    }
+    return nil
+}
 func (u *sqlSymUnion) arraySubscript() *tree.ArraySubscript {
     return u.val.(*tree.ArraySubscript)
 }
@@ -639,6 +656,12 @@ func (u *sqlSymUnion) idxElem() tree.IndexElem {
 func (u *sqlSymUnion) idxElems() tree.IndexElemList {
     return u.val.(tree.IndexElemList)
 }
+func (u *sqlSymUnion) excludeElem() tree.ExcludeElem {
+    return u.val.(tree.ExcludeElem)
+}
+func (u *sqlSymUnion) excludeElems() tree.ExcludeElemList {
+    return u.val.(tree.ExcludeElemList)
+}
 func (u *sqlSymUnion) indexInvisibility() tree.IndexInvisibility {
     return u.val.(tree.IndexInvisibility)
 }
@@ -651,6 +674,9 @@ func (u *sqlSymUnion) validationBehavior() tree.ValidationBehavior {
 func (u *sqlSymUnion) partitionBy() *tree.PartitionBy {
     return u.val.(*tree.PartitionBy)
 }
+func (u *sqlSymUnion) partitionBoundSpec() *tree.PartitionBoundSpec {
+    return u.val.(*tree.PartitionBoundSpec)
+}
 func (u *sqlSymUnion) partitionByTable() *tree.PartitionByTable {
     return u.val.(*tree.PartitionByTable)
 }
@@ -738,6 +764,9 @@ func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
 func (u *sqlSymUnion) referenceActions() tree.ReferenceActions {
     return u.val.(tree.ReferenceActions)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) createStatsOptions() *tree.CreateStatsOptions {
     return u.val.(*tree.CreateStatsOptions)
 }
@@ -862,6 +891,24 @@ func (u *sqlSymUnion) functionObj() tree.RoutineObj {
 func (u *sqlSymUnion) routineObjs() tree.RoutineObjs {
     return u.val.(tree.RoutineObjs)
 }
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvent() *tree.TriggerEvent {
+    return u.val.(*tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerEvents() []*tree.TriggerEvent {
+    return u.val.([]*tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
+    return u.val.(tree.TriggerForEach)
+}
+func (u *sqlSymUnion) mergeWhen() *tree.MergeWhen {
+    return u.val.(*tree.MergeWhen)
+}
+func (u *sqlSymUnion) mergeWhens() tree.MergeWhens {
+    return u.val.(tree.MergeWhens)
+}
 func (u *sqlSymUnion) tenantReplicationOptions() *tree.TenantReplicationOptions {
   return u.val.(*tree.TenantReplicationOptions)
 }
@@ -910,7 +957,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 // Ordinary key words in alphabetical order.
 %token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC AS_JSON AT_AT
-%token <str> ASENSITIVE ASYMMETRIC AT ATOMIC ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
+%token <str> ASENSITIVE ASYMMETRIC AT ATOMIC ATTACH ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
 
 %token <str> BACKUP BACKUPS BACKWARD BATCH BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
@@ -927,10 +974,10 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_IDS DEBUG_PAUSE_ON DEC DEBUG_DUMP_METADATA_SST DECIMAL DEFAULT DEFAULTS DEFINER
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACH DETACHED DETAILS
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTED ENCRYPTION_INFO_DIR ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENCODING ENCRYPTED ENCRYPTION_INFO_DIR ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT EXPERIMENTAL_RELOCATE
@@ -954,19 +1001,19 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
 %token <str> INDEX_BEFORE_PAREN INDEX_BEFORE_NAME_THEN_PAREN INDEX_AFTER_ORDER_BY_BEFORE_AT
-%token <str> INNER INOUT INPUT INSENSITIVE INSERT INT INTEGER
+%token <str> INNER INOUT INPUT INSENSITIVE INSERT INSTEAD INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED INVOKER IS ISERROR ISNULL ISOLATION
 
-%token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
+%token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS JSON_PATH_EXISTS
 
 %token <str> KEY KEYS KMS KV
 
 %token <str> LABEL LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEAKPROOF LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LISTEN LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MONTH MOVE
+%token <str> MATCH MATCHED MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
@@ -974,7 +1021,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> NAN NAME NAMES NATURAL NEVER NEW_DB_NAME NEW_KMS NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED
 %token <str> NOCONTROLJOB NOCREATEDB NOCREATELOGIN NOCREATEROLE NODE NOLOGIN NOMODIFYCLUSTERSETTING NOREPLICATION
 %token <str> NOSQLLOGIN NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NONVOTERS NORMAL NOT
-%token <str> NOTHING NOTHING_AFTER_RETURNING
+%token <str> NOTHING NOTHING_AFTER_RETURNING NOTIFY
 %token <str> NOTNULL
 %token <str> NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOVIEWCLUSTERSETTING NOWAIT NULL NULLIF NULLS NUMERIC
 
@@ -988,7 +1035,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 
 %token <str> QUERIES QUERY QUOTE
 
-%token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFRESH
+%token <str> RANGE RANGE_ADJACENT RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH REMOVE_REGIONS RENAME REPEATABLE REPLACE REPLICATION
 %token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
@@ -1001,9 +1048,9 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SKIP_MISSING_UDFS SMALLINT SMALLSERIAL
 %token <str> SNAPSHOT SOME SPLIT SQL SQLLOGIN
 %token <str> STABLE START STATE STATISTICS STATUS STDIN STDOUT STOP STRAIGHT STREAM STRICT STRING STORAGE STORE STORED STORING SUBJECT SUBSTRING SUPER
-%token <str> SUPPORT SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
+%token <str> SUPPORT SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENT STATEMENTS
 
-%token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANT_NAME TENANTS TESTING_RELOCATE TEXT THEN
+%token <str> TABLE TABLES TABLESAMPLE TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANT_NAME TENANTS TESTING_RELOCATE TEXT THEN
 %token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE
 %token <str> TRANSACTION TRANSACTIONS TRANSFER TRANSFORM TREAT TRIGGER TRIM TRUE
 %token <str> TRUNCATE TRUSTED TYPE TYPES
@@ -1062,6 +1109,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> alter_table_stmt
 %type <tree.Statement> alter_index_stmt
 %type <tree.Statement> alter_view_stmt
+%type <tree.Statement> alter_view_storage_params_stmt
 %type <tree.Statement> alter_sequence_stmt
 %type <tree.Statement> alter_database_stmt
 %type <tree.Statement> alter_range_stmt
@@ -1069,6 +1117,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> alter_role_stmt
 %type <*tree.SetVar> set_or_reset_clause
 %type <tree.Statement> alter_type_stmt
+%type <tree.Statement> alter_domain_stmt
 %type <tree.Statement> alter_schema_stmt
 %type <tree.Statement> alter_unsupported_stmt
 %type <tree.Statement> alter_func_stmt
@@ -1203,10 +1252,20 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> create_table_stmt
 %type <tree.Statement> create_table_as_stmt
 %type <tree.Statement> create_virtual_cluster_stmt
+%type <tree.Statement> create_publication_stmt
+%type <tree.Statement> create_subscription_stmt
 %type <tree.Statement> create_view_stmt
 %type <tree.Statement> create_sequence_stmt
 %type <tree.Statement> create_func_stmt
 %type <tree.Statement> create_proc_stmt
+%type <tree.Statement> create_trigger_stmt
+%type <tree.TriggerActionTime> trigger_action_time
+%type <*tree.TriggerEvent> trigger_event
+%type <[]*tree.TriggerEvent> trigger_event_list
+%type <tree.TriggerForEach> opt_trigger_for_each
+%type <tree.Expr> opt_trigger_when
+%type <[]string> opt_trigger_func_args trigger_func_args
+%type <str> trigger_func_arg
 
 %type <*tree.LikeTenantSpec> opt_like_virtual_cluster
 
@@ -1216,6 +1275,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <*tree.CreateStatsOptions> create_stats_option
 
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_domain_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -1223,15 +1283,19 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> drop_ddl_stmt
 %type <tree.Statement> drop_database_stmt
 %type <tree.Statement> drop_external_connection_stmt
+%type <tree.Statement> drop_publication_stmt
+%type <tree.Statement> drop_subscription_stmt
 %type <tree.Statement> drop_index_stmt
 %type <tree.Statement> drop_role_stmt
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_domain_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 %type <tree.Statement> drop_func_stmt
 %type <tree.Statement> drop_proc_stmt
+%type <tree.Statement> drop_trigger_stmt
 %type <tree.Statement> drop_virtual_cluster_stmt
 %type <bool>           opt_immediate
 
@@ -1271,6 +1335,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt set_or_reset_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -1334,6 +1399,8 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 
 %type <tree.Statement> transaction_stmt legacy_transaction_stmt legacy_begin_stmt legacy_end_stmt
 %type <tree.Statement> truncate_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> notify_stmt
 %type <tree.Statement> unlisten_stmt
 %type <tree.Statement> update_stmt
 %type <tree.Statement> upsert_stmt
@@ -1421,7 +1488,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Operator> subquery_op
 %type <*tree.UnresolvedName> func_name func_name_no_crdb_extra
 %type <tree.ResolvableFunctionReference> func_application_name
-%type <str> opt_class opt_collate
+%type <str> opt_class opt_collate opt_exclude_access_method
 
 %type <str> cursor_name database_name index_name opt_index_name column_name insert_column_item statistics_name window_name opt_in_database
 %type <str> family_name opt_family_name table_alias_name constraint_name target_name zone_name partition_name collation_name
@@ -1451,6 +1518,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.CreateTableOnCommitSetting> opt_create_table_on_commit
 %type <*tree.PartitionBy> opt_partition_by partition_by partition_by_inner
 %type <*tree.PartitionByTable> opt_partition_by_table partition_by_table
+%type <*tree.PartitionBoundSpec> partition_bound_spec
 %type <*tree.PartitionByIndex> opt_partition_by_index partition_by_index
 %type <str> partition opt_partition
 %type <str> opt_create_table_inherits
@@ -1468,6 +1536,9 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.OrderBy> sort_clause sort_clause_no_index single_sort_clause opt_sort_clause opt_sort_clause_no_index
 %type <[]*tree.Order> sortby_list sortby_no_index_list
 %type <tree.IndexElemList> index_params create_as_params
+%type <tree.ExcludeElem> exclude_elem
+%type <tree.ExcludeElemList> exclude_elem_list
+%type <tree.Expr> opt_exclude_where_clause
 %type <tree.IndexInvisibility> opt_index_visible alter_index_visible
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
@@ -1483,6 +1554,10 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.SelectExprs> opt_target_list target_list
 %type <tree.UpdateExprs> set_clause_list
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
+%type <tree.Statement> merge_stmt
+%type <tree.MergeWhens> merge_when_list
+%type <*tree.MergeWhen> merge_when_clause merge_matched_action merge_not_matched_action
+%type <tree.Expr> opt_merge_when_condition
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
 %type <tree.Exprs> group_by_list
@@ -1544,6 +1619,8 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <*tree.ArraySubscript> array_subscript
 %type <tree.Expr> opt_slice_bound
 %type <*tree.IndexFlags> opt_index_flags
+%type <*tree.TableSample> opt_tablesample_clause
+%type <tree.Expr> opt_repeatable_clause
 %type <*tree.IndexFlags> index_flags_param
 %type <*tree.IndexFlags> index_flags_param_list
 %type <tree.Expr> a_expr b_expr c_expr d_expr typed_literal
@@ -1580,6 +1657,8 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <str> explain_option_name
 %type <[]string> explain_option_list opt_enum_val_list enum_val_list
 %type <[]tree.CompositeTypeElem> composite_type_list opt_composite_type_list
+%type <tree.DomainConstraint> domain_constraint domain_constraint_elem
+%type <[]tree.DomainConstraint> opt_domain_constraint_list domain_constraint_list
 
 %type <tree.ResolvableTypeReference> typename simple_typename cast_target
 %type <*types.T> const_typename
@@ -1629,6 +1708,8 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
 %type <tree.ReferenceActions> reference_actions
+%type <tree.ConstraintDeferrability> opt_deferrable
+%type <bool> constraints_set_mode
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
@@ -1696,7 +1777,9 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.RoutineParam> routine_param_with_default routine_param
 %type <tree.ResolvableTypeReference> routine_return_type routine_param_type
 %type <tree.RoutineOptions> opt_create_routine_opt_list create_routine_opt_list alter_func_opt_list
-%type <tree.RoutineOption> create_routine_opt_item common_routine_opt_item
+%type <tree.RoutineOption> create_routine_opt_item common_routine_opt_item alter_func_opt_item
+%type <tree.Exprs> routine_set_value_list
+%type <tree.Expr> routine_set_value
 %type <tree.RoutineParamClass> routine_param_class
 %type <*tree.UnresolvedObjectName> routine_create_name
 %type <tree.Statement> routine_return_stmt routine_body_stmt
@@ -1750,11 +1833,11 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 // funny behavior of UNBOUNDED on the SQL standard, though.
 %nonassoc  UNBOUNDED         // ideally should have same precedence as IDENT
 %nonassoc  IDENT NULL PARTITION RANGE ROWS GROUPS PRECEDING FOLLOWING CUBE ROLLUP
-%left      CONCAT FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH REMOVE_PATH AT_AT  // multi-character ops
+%left      CONCAT FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH REMOVE_PATH AT_AT JSON_PATH_EXISTS // multi-character ops
 %left      '|'
 %left      '#'
 %left      '&'
-%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND SQRT CBRT
+%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND RANGE_ADJACENT SQRT CBRT
 %left      OPERATOR // if changing the last token before OPERATOR, change all instances of %prec <last token>
 %left      '+' '-'
 %left      '*' '/' FLOORDIV '%'
@@ -1818,8 +1901,10 @@ stmt_without_legacy_transaction:
 | declare_cursor_stmt        // EXTEND WITH HELP: DECLARE
 | fetch_cursor_stmt          // EXTEND WITH HELP: FETCH
 | move_cursor_stmt           // EXTEND WITH HELP: MOVE
-| reindex_stmt
-| unlisten_stmt
+| reindex_stmt               // EXTEND WITH HELP: REINDEX
+| listen_stmt                // EXTEND WITH HELP: LISTEN
+| notify_stmt                // EXTEND WITH HELP: NOTIFY
+| unlisten_stmt              // EXTEND WITH HELP: UNLISTEN
 | show_commit_timestamp_stmt // EXTEND WITH HELP: SHOW COMMIT TIMESTAMP
 
 // %Help: ALTER
@@ -1842,6 +1927,7 @@ alter_ddl_stmt:
 | alter_partition_stmt          // EXTEND WITH HELP: ALTER PARTITION
 | alter_schema_stmt             // EXTEND WITH HELP: ALTER SCHEMA
 | alter_type_stmt               // EXTEND WITH HELP: ALTER TYPE
+| alter_domain_stmt             // EXTEND WITH HELP: ALTER DOMAIN
 | alter_default_privileges_stmt // EXTEND WITH HELP: ALTER DEFAULT PRIVILEGES
 | alter_changefeed_stmt         // EXTEND WITH HELP: ALTER CHANGEFEED
 | alter_backup_stmt             // EXTEND WITH HELP: ALTER BACKUP
@@ -1881,6 +1967,8 @@ alter_ddl_stmt:
 //   ALTER TABLE ... PARTITION BY RANGE ( <name...> ) ( <rangespec> )
 //   ALTER TABLE ... PARTITION BY LIST ( <name...> ) ( <listspec> )
 //   ALTER TABLE ... PARTITION BY NOTHING
+//   ALTER TABLE ... ATTACH PARTITION <partitionname> <boundspec>
+//   ALTER TABLE ... DETACH PARTITION <partitionname>
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
@@ -1944,11 +2032,14 @@ alter_partition_stmt:
 // %Text:
 // ALTER [MATERIALIZED] VIEW [IF EXISTS] <name> RENAME TO <newname>
 // ALTER [MATERIALIZED] VIEW [IF EXISTS] <name> SET SCHEMA <newschemaname>
+// ALTER MATERIALIZED VIEW [IF EXISTS] <name> SET ( <storage_param> = <value> [, ...] )
+// ALTER MATERIALIZED VIEW [IF EXISTS] <name> RESET ( <storage_param> [, ...] )
 // %SeeAlso: WEBDOCS/alter-view.html
 alter_view_stmt:
   alter_rename_view_stmt
 | alter_view_set_schema_stmt
 | alter_view_owner_stmt
+| alter_view_storage_params_stmt
 // ALTER VIEW has its error help token here because the ALTER VIEW
 // prefix is spread over multiple non-terminals.
 | ALTER VIEW error // SHOW HELP: ALTER VIEW
@@ -2924,6 +3015,21 @@ alter_table_cmd:
       PartitionByTable: $1.partitionByTable(),
     }
   }
+  // ALTER TABLE <name> ATTACH PARTITION <name> FOR VALUES ...
+| ATTACH partition partition_bound_spec
+  {
+    $$.val = &tree.AlterTableAttachPartition{
+      Name: tree.Name($2),
+      Bound: *$3.partitionBoundSpec(),
+    }
+  }
+  // ALTER TABLE <name> DETACH PARTITION <name>
+| DETACH partition
+  {
+    $$.val = &tree.AlterTableDetachPartition{
+      Name: tree.Name($2),
+    }
+  }
   // ALTER TABLE <name> INJECT STATISTICS <json>
 | INJECT STATISTICS a_expr
   {
@@ -3161,6 +3267,49 @@ alter_type_stmt:
   }
 | ALTER TYPE error // SHOW HELP: ALTER TYPE
 
+// %Help: ALTER DOMAIN - change the definition of a domain
+// %Category: DDL
+// %Text: ALTER DOMAIN <type_name> <command>
+//
+// Commands:
+//   ALTER DOMAIN ... ADD [CONSTRAINT <name>] CHECK (<expr>)
+//   ALTER DOMAIN ... DROP CONSTRAINT [IF EXISTS] <name> [CASCADE | RESTRICT]
+//
+// %SeeAlso: CREATE DOMAIN, DROP DOMAIN
+alter_domain_stmt:
+  ALTER DOMAIN type_name ADD domain_constraint
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{
+        Constraint: $5.domainConstraint(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT constraint_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        Constraint: tree.Name($6),
+        IfExists: false,
+        DropBehavior: $7.dropBehavior(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT IF EXISTS constraint_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        Constraint: tree.Name($8),
+        IfExists: true,
+        DropBehavior: $9.dropBehavior(),
+      },
+    }
+  }
+| ALTER DOMAIN error // SHOW HELP: ALTER DOMAIN
+
 opt_add_val_placement:
   BEFORE SCONST
   {
@@ -3981,11 +4130,7 @@ import_format:
   }
 
 alter_unsupported_stmt:
-  ALTER DOMAIN error
-  {
-    return unimplemented(sqllex, "alter domain")
-  }
-| ALTER AGGREGATE error
+  ALTER AGGREGATE error
   {
     return unimplementedWithIssueDetail(sqllex, 74775, "alter aggregate")
   }
@@ -4571,6 +4716,8 @@ create_stmt:
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_external_connection_stmt // EXTEND WITH HELP: CREATE EXTERNAL CONNECTION
 | create_virtual_cluster_stmt     // EXTEND WITH HELP: CREATE VIRTUAL CLUSTER
+| create_publication_stmt         // EXTEND WITH HELP: CREATE PUBLICATION
+| create_subscription_stmt        // EXTEND WITH HELP: CREATE SUBSCRIPTION
 | create_schedule_stmt   // help texts in sub-rule
 | create_unsupported     {}
 | CREATE error           // SHOW HELP: CREATE
@@ -4682,6 +4829,48 @@ replication_options:
       $$.val = &tree.TenantReplicationOptions{ExpirationWindow: $4.expr()}
   }
 
+// %Help: CREATE PUBLICATION - define a new publication
+// %Category: DDL
+// %Text:
+// CREATE PUBLICATION <name> [ FOR TABLE <tablename> [, ...] | FOR ALL TABLES ]
+// %SeeAlso: DROP PUBLICATION, CREATE SUBSCRIPTION
+create_publication_stmt:
+  CREATE PUBLICATION name
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3)}
+  }
+| CREATE PUBLICATION name FOR TABLE table_name_list
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), Tables: $6.tableNames()}
+  }
+| CREATE PUBLICATION name FOR ALL TABLES
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), AllTables: true}
+  }
+| CREATE PUBLICATION error // SHOW HELP: CREATE PUBLICATION
+
+// %Help: CREATE SUBSCRIPTION - subscribe to publications of a remote cluster
+// %Category: CCL
+// %Text:
+// CREATE SUBSCRIPTION <name> CONNECTION '<uri>' PUBLICATION <publication> [, ...]
+//    [ WITH <option> [= <value>] [, ...] ]
+//
+// Options:
+//    conflict_resolution = 'last_write_wins' | 'overwrite'
+//    copy_data = true | false
+// %SeeAlso: DROP SUBSCRIPTION, CREATE PUBLICATION
+create_subscription_stmt:
+  CREATE SUBSCRIPTION name CONNECTION string_or_placeholder PUBLICATION name_list opt_with_options
+  {
+    $$.val = &tree.CreateSubscription{
+      Name: tree.Name($3),
+      ConnectionURI: $5.expr(),
+      Publications: $7.nameList(),
+      Options: $8.kvOptions(),
+    }
+  }
+| CREATE SUBSCRIPTION error // SHOW HELP: CREATE SUBSCRIPTION
+
 // %Help: CREATE SCHEDULE
 // %Category: Group
 // %Text:
@@ -4872,7 +5061,7 @@ routine_param_class:
 | OUT { $$.val = tree.RoutineParamOut }
 | INOUT { $$.val = tree.RoutineParamInOut }
 | IN OUT { $$.val = tree.RoutineParamInOut }
-| VARIADIC { return unimplementedWithIssueDetail(sqllex, 88947, "variadic user-defined functions") }
+| VARIADIC { $$.val = tree.RoutineParamVariadic }
 
 routine_param_type:
   typename
@@ -4938,19 +5127,19 @@ common_routine_opt_item:
   }
 | EXTERNAL SECURITY DEFINER
   {
-    return unimplemented(sqllex, "create function...security")
+    $$.val = tree.RoutineDefiner
   }
 | EXTERNAL SECURITY INVOKER
   {
-    return unimplemented(sqllex, "create function...security")
+    $$.val = tree.RoutineInvoker
   }
 | SECURITY DEFINER
   {
-    return unimplemented(sqllex, "create function...security")
+    $$.val = tree.RoutineDefiner
   }
 | SECURITY INVOKER
   {
-    return unimplemented(sqllex, "create function...security")
+    $$.val = tree.RoutineInvoker
   }
 | LEAKPROOF
   {
@@ -4972,13 +5161,56 @@ common_routine_opt_item:
   {
     return unimplemented(sqllex, "create function/procedure ... support")
   }
-
-// In theory we should parse the a whole set/reset statement here. But it's fine
-// to just return fast on SET/RESET keyword for now since it's not supported
-// yet.
-| SET { return unimplemented(sqllex, "create function/procedure ... set") }
+| SET var_name to_or_eq routine_set_value_list
+  {
+    $$.val = tree.RoutineSetConfig{Name: strings.Join($2.strs(), "."), Values: $4.exprs()}
+  }
+| SET var_name FROM CURRENT
+  {
+    return unimplemented(sqllex, "create function/procedure ... set from current")
+  }
 | PARALLEL { return unimplemented(sqllex, "create function/procedure ... parallel") }
 
+// The values of a SET clause are restricted to constants and names, unlike
+// those of a SET statement, so that the clause can be followed by other
+// routine options.
+routine_set_value_list:
+  routine_set_value
+  {
+    $$.val = tree.Exprs{$1.expr()}
+  }
+| routine_set_value_list ',' routine_set_value
+  {
+    $$.val = append($1.exprs(), $3.expr())
+  }
+
+routine_set_value:
+  non_reserved_word
+  {
+    $$.val = tree.Expr(&tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{$1}})
+  }
+| SCONST
+  {
+    $$.val = tree.NewStrVal($1)
+  }
+| numeric_only
+| ON
+  {
+    $$.val = tree.Expr(&tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{$1}})
+  }
+| TRUE
+  {
+    $$.val = tree.MakeDBool(true)
+  }
+| FALSE
+  {
+    $$.val = tree.MakeDBool(false)
+  }
+| DEFAULT
+  {
+    $$.val = tree.DefaultVal{}
+  }
+
 routine_as:
   SCONST
 
@@ -5030,6 +5262,145 @@ opt_link_sym:
   {
   }
 
+// %Help: CREATE TRIGGER - define a new trigger
+// %Category: DDL
+// %Text:
+// CREATE TRIGGER name { BEFORE | AFTER } { event [ OR ... ] }
+//    ON table_name
+//    [ FOR [ EACH ] { ROW | STATEMENT } ]
+//    [ WHEN ( condition ) ]
+//    EXECUTE { FUNCTION | PROCEDURE } function_name ( [ arguments ] )
+//
+// where event can be one of:
+//    INSERT
+//    UPDATE [ OF column_name [, ... ] ]
+//    DELETE
+// %SeeAlso: DROP TRIGGER, CREATE FUNCTION
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name
+  opt_trigger_for_each opt_trigger_when EXECUTE trigger_func_kind db_object_name
+  '(' opt_trigger_func_args ')'
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      TableName: $7.unresolvedObjectName().ToTableName(),
+      ForEach: $8.triggerForEach(),
+      When: $9.expr(),
+      FuncName: $12.unresolvedObjectName().ToRoutineName(),
+      FuncArgs: $14.strs(),
+    }
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerActionTimeBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerActionTimeAfter
+  }
+| INSTEAD OF
+  {
+    $$.val = tree.TriggerActionTimeInsteadOf
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = []*tree.TriggerEvent{$1.triggerEvent()}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvent())
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventInsert}
+  }
+| UPDATE
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventUpdate}
+  }
+| UPDATE OF name_list
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventUpdate, Columns: $3.nameList()}
+  }
+| DELETE
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventDelete}
+  }
+| TRUNCATE
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventTruncate}
+  }
+
+opt_trigger_for_each:
+  FOR opt_each ROW
+  {
+    $$.val = tree.TriggerForEachRow
+  }
+| FOR opt_each STATEMENT
+  {
+    $$.val = tree.TriggerForEachStatement
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TriggerForEachStatement
+  }
+
+opt_each:
+  EACH {}
+| /* EMPTY */ {}
+
+opt_trigger_when:
+  WHEN '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+trigger_func_kind:
+  FUNCTION {}
+| PROCEDURE {}
+
+opt_trigger_func_args:
+  trigger_func_args
+| /* EMPTY */
+  {
+    $$.val = []string(nil)
+  }
+
+trigger_func_args:
+  trigger_func_arg
+  {
+    $$.val = []string{$1}
+  }
+| trigger_func_args ',' trigger_func_arg
+  {
+    $$.val = append($1.strs(), $3)
+  }
+
+trigger_func_arg:
+  ICONST
+  {
+    $$ = $1.numVal().OrigString()
+  }
+| FCONST
+  {
+    $$ = $1.numVal().OrigString()
+  }
+| SCONST
+| unrestricted_name
+
 // %Help: DROP FUNCTION - remove a function
 // %Category: DDL
 // %Text:
@@ -5080,6 +5451,30 @@ drop_proc_stmt:
   }
 | DROP PROCEDURE error // SHOW HELP: DROP PROCEDURE
 
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [ IF EXISTS ] name ON table_name [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Trigger: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      IfExists: true,
+      Trigger: tree.Name($5),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
 function_with_paramtypes_list:
   function_with_paramtypes
   {
@@ -5135,15 +5530,26 @@ alter_func_options_stmt:
   }
 
 alter_func_opt_list:
-  common_routine_opt_item
+  alter_func_opt_item
   {
     $$.val = tree.RoutineOptions{$1.functionOption()}
   }
-| alter_func_opt_list common_routine_opt_item
+| alter_func_opt_list alter_func_opt_item
   {
     $$.val = append($1.routineOptions(), $2.functionOption())
   }
 
+alter_func_opt_item:
+  common_routine_opt_item
+| RESET var_name
+  {
+    $$.val = tree.RoutineResetConfig{Name: strings.Join($2.strs(), ".")}
+  }
+| RESET ALL
+  {
+    $$.val = tree.RoutineResetConfig{All: true}
+  }
+
 opt_restrict:
   RESTRICT {}
 | /* EMPTY */ {}
@@ -5229,20 +5635,22 @@ create_unsupported:
   CREATE ACCESS METHOD error { return unimplemented(sqllex, "create access method") }
 | CREATE AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "create aggregate") }
 | CREATE CAST error { return unimplemented(sqllex, "create cast") }
-| CREATE CONSTRAINT TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create constraint") }
+| CREATE CONSTRAINT TRIGGER error
+  {
+    return unimplementedWithIssueDetailHint(sqllex, 28296, "create constraint trigger",
+      "constraint triggers are not supported; a NOT DEFERRABLE constraint trigger " +
+      "behaves like CREATE TRIGGER ... AFTER ... FOR EACH ROW")
+  }
 | CREATE CONVERSION error { return unimplemented(sqllex, "create conversion") }
 | CREATE DEFAULT CONVERSION error { return unimplemented(sqllex, "create def conv") }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplementedWithIssue(sqllex, 65017) }
-| CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
 | CREATE SERVER error { return unimplemented(sqllex, "create server") }
-| CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create trigger") }
 
 opt_trusted:
   TRUSTED {}
@@ -5258,19 +5666,15 @@ drop_unsupported:
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
-| DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension if exists") }
 | DROP EXTENSION name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension") }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
-| DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
-| DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
@@ -5281,10 +5685,12 @@ create_ddl_stmt:
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE opt_persistence_temp_table TABLE error   // SHOW HELP: CREATE TABLE
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
+| create_domain_stmt   // EXTEND WITH HELP: CREATE DOMAIN
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 | create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
 | create_proc_stmt     // EXTEND WITH HELP: CREATE PROCEDURE
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
 
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
@@ -5658,6 +6064,8 @@ drop_stmt:
 | drop_schedule_stmt            // EXTEND WITH HELP: DROP SCHEDULES
 | drop_external_connection_stmt // EXTEND WITH HELP: DROP EXTERNAL CONNECTION
 | drop_virtual_cluster_stmt     // EXTEND WITH HELP: DROP VIRTUAL CLUSTER
+| drop_publication_stmt         // EXTEND WITH HELP: DROP PUBLICATION
+| drop_subscription_stmt        // EXTEND WITH HELP: DROP SUBSCRIPTION
 | drop_unsupported   {}
 | DROP error                    // SHOW HELP: DROP
 
@@ -5669,8 +6077,10 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_domain_stmt   // EXTEND WITH HELP: DROP DOMAIN
 | drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
 | drop_proc_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -5805,6 +6215,29 @@ drop_type_stmt:
   }
 | DROP TYPE error // SHOW HELP: DROP TYPE
 
+// %Help: DROP DOMAIN - remove a domain
+// %Category: DDL
+// %Text: DROP DOMAIN [IF EXISTS] <type_name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE DOMAIN
+drop_domain_stmt:
+  DROP DOMAIN type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP DOMAIN IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP DOMAIN error // SHOW HELP: DROP DOMAIN
+
 // %Help: DROP VIRTUAL CLUSTER - remove a virtual cluster
 // %Category: Experimental
 // %Text: DROP VIRTUAL CLUSTER [IF EXISTS] <virtual_cluster_spec> [IMMEDIATE]
@@ -5829,6 +6262,36 @@ drop_virtual_cluster_stmt:
   }
 | DROP virtual_cluster error // SHOW HELP: DROP VIRTUAL CLUSTER
 
+// %Help: DROP PUBLICATION - remove a publication
+// %Category: DDL
+// %Text: DROP PUBLICATION [IF EXISTS] <name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE PUBLICATION
+drop_publication_stmt:
+  DROP PUBLICATION name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropPublication{Names: $3.nameList(), DropBehavior: $4.dropBehavior()}
+  }
+| DROP PUBLICATION IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropPublication{Names: $5.nameList(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP PUBLICATION error // SHOW HELP: DROP PUBLICATION
+
+// %Help: DROP SUBSCRIPTION - remove a subscription
+// %Category: CCL
+// %Text: DROP SUBSCRIPTION [IF EXISTS] <name>
+// %SeeAlso: CREATE SUBSCRIPTION
+drop_subscription_stmt:
+  DROP SUBSCRIPTION name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($3)}
+  }
+| DROP SUBSCRIPTION IF EXISTS name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($5), IfExists: true}
+  }
+| DROP SUBSCRIPTION error // SHOW HELP: DROP SUBSCRIPTION
+
 opt_immediate:
   /* EMPTY */
   { $$.val = false }
@@ -6026,6 +6489,7 @@ preparable_stmt:
 | explain_stmt   // EXTEND WITH HELP: EXPLAIN
 | import_stmt    // EXTEND WITH HELP: IMPORT
 | insert_stmt    // EXTEND WITH HELP: INSERT
+| merge_stmt     // EXTEND WITH HELP: MERGE
 | pause_stmt     // help texts in sub-rule
 | reset_stmt     // help texts in sub-rule
 | restore_stmt   // EXTEND WITH HELP: RESTORE
@@ -6751,8 +7215,8 @@ use_stmt:
 // SET remainder, e.g. SET TRANSACTION
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
 
 // SET SESSION / SET LOCAL / SET CLUSTER SETTING
 preparable_set_stmt:
@@ -7232,6 +7696,37 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the constraint check timing of the transaction
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// Only the checks of DEFERRABLE constraints can be deferred until the
+// end of the transaction.
+//
+// %SeeAlso: SET TRANSACTION, CREATE TABLE
+// WEBDOCS/set-constraints.html
+set_constraints_stmt:
+  SET CONSTRAINTS ALL constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{All: true, Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS name_list constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_set_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -7694,32 +8189,54 @@ from_or_in:
   FROM { }
 | IN { }
 
+// %Help: REINDEX - rebuild indexes
+// %Category: DDL
+// %Text:
+// REINDEX TABLE [CONCURRENTLY] <tablename>
+// REINDEX INDEX [CONCURRENTLY] <idxname>
+// REINDEX DATABASE [CONCURRENTLY] <dbname>
+//
+// REINDEX backfills new indexes with the same definition as the existing
+// ones and swaps them in once they are valid. Reads and writes to the table
+// are not blocked while the new indexes are built, so CONCURRENTLY is accepted
+// for compatibility but has no effect.
+// %SeeAlso: CREATE INDEX, DROP INDEX, SCRUB TABLE
 reindex_stmt:
-  REINDEX TABLE error
+  REINDEX TABLE opt_concurrently table_name
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex table", "CockroachDB does not require reindexing.")
+    $$.val = &tree.Reindex{
+      Kind: tree.ReindexTable,
+      Concurrently: $3.bool(),
+      Table: $4.unresolvedObjectName(),
+    }
   }
-| REINDEX INDEX error
+| REINDEX INDEX opt_concurrently table_index_name
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex index", "CockroachDB does not require reindexing.")
+    $$.val = &tree.Reindex{
+      Kind: tree.ReindexIndex,
+      Concurrently: $3.bool(),
+      Index: $4.newTableIndexName(),
+    }
   }
-| REINDEX SCHEMA error
+| REINDEX DATABASE opt_concurrently database_name
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex schema", "CockroachDB does not require reindexing.")
+    $$.val = &tree.Reindex{
+      Kind: tree.ReindexDatabase,
+      Concurrently: $3.bool(),
+      Database: tree.Name($4),
+    }
   }
-| REINDEX DATABASE error
+| REINDEX SCHEMA error
   {
     /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex database", "CockroachDB does not require reindexing.")
+    return purposelyUnimplemented(sqllex, "reindex schema", "Use REINDEX TABLE or REINDEX DATABASE instead.")
   }
 | REINDEX SYSTEM error
   {
     /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex system", "CockroachDB does not require reindexing.")
+    return purposelyUnimplemented(sqllex, "reindex system", "System tables cannot be reindexed.")
   }
+| REINDEX error // SHOW HELP: REINDEX
 
 // %Help: SHOW SESSION - display session variables
 // %Category: Cfg
@@ -9734,6 +10251,7 @@ alter_schema_stmt:
 // %Text:
 // CREATE [[GLOBAL | LOCAL] {TEMPORARY | TEMP}] TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<on_commit>]
 // CREATE [[GLOBAL | LOCAL] {TEMPORARY | TEMP}] TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source> [<on commit>]
+// CREATE TABLE <partitionname> PARTITION OF <tablename> <boundspec>
 //
 // Table elements:
 //    <name> <type> [<qualifiers...>]
@@ -9758,6 +10276,11 @@ alter_schema_stmt:
 // On commit clause:
 //    ON COMMIT {PRESERVE ROWS | DROP | DELETE ROWS}
 //
+// Partition bounds:
+//    FOR VALUES IN ( <exprs...> )
+//    FOR VALUES FROM ( <exprs...> ) TO ( <exprs...> )
+//    DEFAULT
+//
 // %SeeAlso: SHOW TABLES, CREATE VIEW, SHOW CREATE,
 // WEBDOCS/create-table.html
 // WEBDOCS/create-table-as.html
@@ -9793,6 +10316,33 @@ create_table_stmt:
     }
   }
 
+| CREATE opt_persistence_temp_table TABLE table_name PARTITION OF table_name partition_bound_spec
+  {
+    name := $4.unresolvedObjectName().ToTableName()
+    parent := $7.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateTable{
+      Table: name,
+      IfNotExists: false,
+      Persistence: $2.persistence(),
+      PartitionOf: &parent,
+      PartitionBound: $8.partitionBoundSpec(),
+    }
+  }
+
+partition_bound_spec:
+  FOR VALUES IN '(' expr_list ')'
+  {
+    $$.val = &tree.PartitionBoundSpec{In: $5.exprs()}
+  }
+| FOR VALUES FROM '(' expr_list ')' TO '(' expr_list ')'
+  {
+    $$.val = &tree.PartitionBoundSpec{From: $5.exprs(), To: $9.exprs()}
+  }
+| DEFAULT
+  {
+    $$.val = &tree.PartitionBoundSpec{Default: true}
+  }
+
 opt_locality:
   locality
   {
@@ -10225,10 +10775,11 @@ col_qualification_elem:
   {
     $$.val = tree.HiddenConstraint{}
   }
-| UNIQUE opt_without_index
+| UNIQUE opt_without_index opt_deferrable
   {
     $$.val = tree.UniqueConstraint{
       WithoutIndex: $2.bool(),
+      Deferrability: $3.constraintDeferrability(),
     }
   }
 | PRIMARY KEY opt_with_storage_parameter_list
@@ -10257,7 +10808,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnOnUpdate{Expr: $3.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
   {
     name := $2.unresolvedObjectName().ToTableName()
     $$.val = &tree.ColumnFKConstraint{
@@ -10265,6 +10816,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrability: $6.constraintDeferrability(),
     }
   }
 | generated_as '(' a_expr ')' STORED
@@ -10421,6 +10973,10 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability().IsDeferrable() {
+      return setErr(sqllex, pgerror.New(pgcode.FeatureNotSupported,
+        "CHECK constraints cannot be marked DEFERRABLE"))
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -10436,6 +10992,7 @@ constraint_elem:
         PartitionByIndex: $7.partitionByIndex(),
         Predicate: $9.expr(),
       },
+      Deferrability: $8.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_with_storage_parameter_list
@@ -10459,11 +11016,57 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
+    }
+  }
+| EXCLUDE opt_exclude_access_method '(' exclude_elem_list ')' opt_exclude_where_clause
+  {
+    $$.val = &tree.ExcludeConstraintTableDef{
+      Using: $2,
+      Elems: $4.excludeElems(),
+      Predicate: $6.expr(),
+    }
+  }
+
+opt_exclude_access_method:
+  USING name
+  {
+    $$ = $2
+  }
+| /* EMPTY */
+  {
+    $$ = ""
+  }
+
+exclude_elem_list:
+  exclude_elem
+  {
+    $$.val = tree.ExcludeElemList{$1.excludeElem()}
+  }
+| exclude_elem_list ',' exclude_elem
+  {
+    $$.val = append($1.excludeElems(), $3.excludeElem())
+  }
+
+exclude_elem:
+  index_elem WITH all_op
+  {
+    op, ok := $3.op().(treecmp.ComparisonOperator)
+    if !ok {
+      sqllex.Error(fmt.Sprintf("operator %s is not a comparison operator", $3.op()))
+      return 1
     }
+    $$.val = tree.ExcludeElem{IndexElem: $1.idxElem(), Operator: op}
+  }
+
+opt_exclude_where_clause:
+  WHERE '(' a_expr ')'
+  {
+    $$.val = $3.expr()
   }
-| EXCLUDE USING error
+| /* EMPTY */
   {
-    return unimplementedWithIssueDetail(sqllex, 46657, "add constraint exclude using")
+    $$.val = tree.Expr(nil)
   }
 
 
@@ -10571,13 +11174,33 @@ create_as_col_qualification_elem:
     }
   }
 
+// INITIALLY DEFERRED implies DEFERRABLE, as in Postgres. NOT DEFERRABLE is
+// not supported since it would conflict with NOT NULL and NOT VALID.
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  DEFERRABLE
+  {
+    $$.val = tree.ConstraintInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintNotDeferrable
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.ConstraintNotDeferrable
+  }
 
 storing:
   COVERING
@@ -10956,7 +11579,8 @@ role_or_group_or_user:
 // %Category: DDL
 // %Text:
 // CREATE [TEMPORARY | TEMP] VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source>
-// CREATE [TEMPORARY | TEMP] MATERIALIZED VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source> [WITH [NO] DATA]
+// CREATE [TEMPORARY | TEMP] MATERIALIZED VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )]
+//   [WITH ( <storage_param> = <value> [, ...] )] AS <source> [WITH [NO] DATA]
 // %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
@@ -10997,27 +11621,29 @@ create_view_stmt:
       Replace: false,
     }
   }
-| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt opt_with_data
+| CREATE MATERIALIZED VIEW view_name opt_column_list opt_with_storage_parameter_list AS select_stmt opt_with_data
   {
     name := $4.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateView{
       Name: name,
       ColumnNames: $5.nameList(),
-      AsSource: $7.slct(),
+      StorageParams: $6.storageParams(),
+      AsSource: $8.slct(),
       Materialized: true,
-      WithData: $8.bool(),
+      WithData: $9.bool(),
     }
   }
-| CREATE MATERIALIZED VIEW IF NOT EXISTS view_name opt_column_list AS select_stmt opt_with_data
+| CREATE MATERIALIZED VIEW IF NOT EXISTS view_name opt_column_list opt_with_storage_parameter_list AS select_stmt opt_with_data
   {
     name := $7.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateView{
       Name: name,
       ColumnNames: $8.nameList(),
-      AsSource: $10.slct(),
+      StorageParams: $9.storageParams(),
+      AsSource: $11.slct(),
       Materialized: true,
       IfNotExists: true,
-      WithData: $11.bool(),
+      WithData: $12.bool(),
     }
   }
 | CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
@@ -11237,8 +11863,84 @@ create_type_stmt:
 | CREATE TYPE type_name '(' error         { return unimplementedWithIssueDetail(sqllex, 27793, "base") }
   // Shell types, gateway to define base types using the previous syntax.
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
-  // Domain types.
-| CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+
+// %Help: CREATE DOMAIN - create a domain
+// %Category: DDL
+// %Text:
+// CREATE DOMAIN <type_name> [AS] <type> [<constraint> ...]
+//
+// Constraints:
+//   [CONSTRAINT <name>] NOT NULL
+//   [CONSTRAINT <name>] NULL
+//   [CONSTRAINT <name>] CHECK (<expr>)
+//
+// The CHECK expressions refer to the value being checked as VALUE.
+// %SeeAlso: ALTER DOMAIN, DROP DOMAIN
+create_domain_stmt:
+  CREATE DOMAIN type_name opt_domain_as typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Domain,
+      DomainBaseType: $5.typeReference(),
+      DomainConstraints: $6.domainConstraints(),
+    }
+  }
+| CREATE DOMAIN error // SHOW HELP: CREATE DOMAIN
+
+opt_domain_as:
+  AS {}
+| /* EMPTY */ {}
+
+opt_domain_constraint_list:
+  domain_constraint_list
+  {
+    $$.val = $1.domainConstraints()
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.DomainConstraint(nil)
+  }
+
+domain_constraint_list:
+  domain_constraint
+  {
+    $$.val = []tree.DomainConstraint{$1.domainConstraint()}
+  }
+| domain_constraint_list domain_constraint
+  {
+    $$.val = append($1.domainConstraints(), $2.domainConstraint())
+  }
+
+domain_constraint:
+  CONSTRAINT constraint_name domain_constraint_elem
+  {
+    c := $3.domainConstraint()
+    c.Name = tree.Name($2)
+    $$.val = c
+  }
+| domain_constraint_elem
+  {
+    $$.val = $1.domainConstraint()
+  }
+
+domain_constraint_elem:
+  NOT NULL
+  {
+    $$.val = tree.DomainConstraint{}
+  }
+| NULL
+  {
+    $$.val = tree.DomainConstraint{Nullable: true}
+  }
+| CHECK '(' a_expr ')'
+  {
+    $$.val = tree.DomainConstraint{Check: $3.expr()}
+  }
+| DEFAULT b_expr
+  {
+    return unimplementedWithIssueDetail(sqllex, 27796, "domain default")
+  }
 
 opt_enum_val_list:
   enum_val_list
@@ -11688,6 +12390,38 @@ alter_view_set_schema_stmt:
 		}
 	}
 
+alter_view_storage_params_stmt:
+  ALTER MATERIALIZED VIEW relation_expr SET '(' storage_parameter_list ')'
+  {
+    $$.val = &tree.AlterMaterializedViewStorageParams{
+      Name: $4.unresolvedObjectName(),
+      Cmd: &tree.AlterTableSetStorageParams{StorageParams: $7.storageParams()},
+    }
+  }
+| ALTER MATERIALIZED VIEW IF EXISTS relation_expr SET '(' storage_parameter_list ')'
+  {
+    $$.val = &tree.AlterMaterializedViewStorageParams{
+      Name: $6.unresolvedObjectName(),
+      IfExists: true,
+      Cmd: &tree.AlterTableSetStorageParams{StorageParams: $9.storageParams()},
+    }
+  }
+| ALTER MATERIALIZED VIEW relation_expr RESET '(' storage_parameter_key_list ')'
+  {
+    $$.val = &tree.AlterMaterializedViewStorageParams{
+      Name: $4.unresolvedObjectName(),
+      Cmd: &tree.AlterTableResetStorageParams{Params: $7.storageParamKeys()},
+    }
+  }
+| ALTER MATERIALIZED VIEW IF EXISTS relation_expr RESET '(' storage_parameter_key_list ')'
+  {
+    $$.val = &tree.AlterMaterializedViewStorageParams{
+      Name: $6.unresolvedObjectName(),
+      IfExists: true,
+      Cmd: &tree.AlterTableResetStorageParams{Params: $9.storageParamKeys()},
+    }
+  }
+
 alter_view_owner_stmt:
 	ALTER VIEW relation_expr OWNER TO role_spec
   {
@@ -12677,6 +13411,98 @@ update_stmt:
   }
 | opt_with_clause UPDATE error // SHOW HELP: UPDATE
 
+// %Help: MERGE - insert, update or delete rows of a table based on a join
+// %Category: DML
+// %Text:
+// MERGE INTO <tablename> [[AS] <name>]
+//        USING <source> ON <expr>
+//        WHEN MATCHED [AND <expr>] THEN { UPDATE SET ... | DELETE | DO NOTHING }
+//        WHEN NOT MATCHED [AND <expr>] THEN
+//          { INSERT [( <colnames...> )] { VALUES ( <exprs...> ) | DEFAULT VALUES } | DO NOTHING }
+//        [...]
+//        [RETURNING <exprs...>]
+// %SeeAlso: INSERT, UPDATE, DELETE, UPSERT
+merge_stmt:
+  opt_with_clause MERGE INTO table_expr_opt_alias_idx USING table_ref ON a_expr merge_when_list returning_clause
+  {
+    $$.val = &tree.Merge{
+      With: $1.with(),
+      Table: $4.tblExpr(),
+      Source: $6.tblExpr(),
+      On: $8.expr(),
+      Whens: $9.mergeWhens(),
+      Returning: $10.retClause(),
+    }
+  }
+| opt_with_clause MERGE error // SHOW HELP: MERGE
+
+merge_when_list:
+  merge_when_clause
+  {
+    $$.val = tree.MergeWhens{$1.mergeWhen()}
+  }
+| merge_when_list merge_when_clause
+  {
+    $$.val = append($1.mergeWhens(), $2.mergeWhen())
+  }
+
+merge_when_clause:
+  WHEN MATCHED opt_merge_when_condition THEN merge_matched_action
+  {
+    when := $5.mergeWhen()
+    when.Matched = true
+    when.Cond = $3.expr()
+    $$.val = when
+  }
+| WHEN NOT MATCHED opt_merge_when_condition THEN merge_not_matched_action
+  {
+    when := $6.mergeWhen()
+    when.Cond = $4.expr()
+    $$.val = when
+  }
+
+opt_merge_when_condition:
+  AND a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+merge_matched_action:
+  UPDATE SET set_clause_list
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionUpdate, Exprs: $3.updateExprs()}
+  }
+| DELETE
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDelete}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDoNothing}
+  }
+
+merge_not_matched_action:
+  INSERT VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert, Values: $4.exprs()}
+  }
+| INSERT '(' insert_column_list ')' VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert, Columns: $3.nameList(), Values: $7.exprs()}
+  }
+| INSERT DEFAULT VALUES
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDoNothing}
+  }
+
 opt_from_list:
   FROM from_list {
     $$.val = $2.tblExprs()
@@ -13396,9 +14222,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Kind: tree.Rollup, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Kind: tree.Cube, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Kind: tree.GroupingSets, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -13575,6 +14410,7 @@ opt_index_flags:
 //   <source> NATURAL [ <jointype> ] JOIN <source>
 //   <source> CROSS JOIN <source>
 //   <source> WITH ORDINALITY
+//   <tablename> TABLESAMPLE { BERNOULLI | SYSTEM } ( <percent> ) [ REPEATABLE ( <seed> ) ]
 //   '[' EXPLAIN ... ']'
 //   '[' SHOW ... ']'
 //
@@ -13601,7 +14437,7 @@ table_ref:
         As:         $4.aliasClause(),
     }
   }
-| relation_expr opt_index_flags opt_ordinality opt_alias_clause
+| relation_expr opt_index_flags opt_ordinality opt_alias_clause opt_tablesample_clause
   {
     name := $1.unresolvedObjectName().ToTableName()
     $$.val = &tree.AliasedTableExpr{
@@ -13609,6 +14445,7 @@ table_ref:
       IndexFlags: $2.indexFlags(),
       Ordinality: $3.bool(),
       As:         $4.aliasClause(),
+      Sample:     $5.tableSample(),
     }
   }
 | select_with_parens opt_ordinality opt_alias_clause
@@ -13775,6 +14612,34 @@ tableref_col_list:
     $$.val = append($1.tableRefCols(), tree.ColumnID($3.int64()))
   }
 
+opt_tablesample_clause:
+  TABLESAMPLE name '(' a_expr ')' opt_repeatable_clause
+  {
+    method, err := tree.TableSampleMethodFromString($2)
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = &tree.TableSample{
+      Method:     method,
+      Percent:    $4.expr(),
+      Repeatable: $6.expr(),
+    }
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.TableSample)(nil)
+  }
+
+opt_repeatable_clause:
+  REPEATABLE '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
 opt_ordinality:
   WITH_LA ORDINALITY
   {
@@ -13977,7 +14842,36 @@ relation_expr_list:
     $$.val = append($1.tableNames(), name)
   }
 
-// UNLISTEN
+// %Help: LISTEN - listen for notifications on a channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: NOTIFY, UNLISTEN
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{ChannelName: tree.Name($2)}
+  }
+| LISTEN error // SHOW HELP: LISTEN
+
+// %Help: NOTIFY - send a notification on a channel
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload>]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY name
+  {
+    $$.val = &tree.Notify{ChannelName: tree.Name($2)}
+  }
+| NOTIFY name ',' SCONST
+  {
+    $$.val = &tree.Notify{ChannelName: tree.Name($2), Payload: $4}
+  }
+| NOTIFY error // SHOW HELP: NOTIFY
+
+// %Help: UNLISTEN - stop listening for notifications on a channel
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
 unlisten_stmt:
    UNLISTEN type_name
     {
@@ -13987,6 +14881,7 @@ unlisten_stmt:
       {
           $$.val = &tree.Unlisten{ ChannelName:nil, Star: true}
       }
+| UNLISTEN error // SHOW HELP: UNLISTEN
 
 
 // Given "UPDATE foo set set ...", we have to decide without looking any
@@ -14866,10 +15761,18 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.Overlaps), Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr RANGE_ADJACENT a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.Adjacent), Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr AT_AT a_expr
   {
     $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.TSMatches), Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr JSON_PATH_EXISTS a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.JSONPathExists), Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
     $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_equals"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
@@ -15424,7 +16327,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingExpr{Exprs: $3.exprs()}
+  }
 
 func_application:
   func_application_name '(' ')'
@@ -16081,7 +16987,9 @@ all_op:
 | REGIMATCH { $$.val = treecmp.MakeComparisonOperator(treecmp.RegIMatch) }
 | NOT_REGIMATCH { $$.val = treecmp.MakeComparisonOperator(treecmp.NotRegIMatch) }
 | AND_AND { $$.val = treecmp.MakeComparisonOperator(treecmp.Overlaps) }
+| RANGE_ADJACENT { $$.val = treecmp.MakeComparisonOperator(treecmp.Adjacent) }
 | AT_AT { $$.val = treecmp.MakeComparisonOperator(treecmp.TSMatches) }
+| JSON_PATH_EXISTS { $$.val = treecmp.MakeComparisonOperator(treecmp.JSONPathExists) }
 | '~' { $$.val = tree.MakeUnaryOperator(tree.UnaryComplement) }
 | SQRT { $$.val = tree.MakeUnaryOperator(tree.UnarySqrt) }
 | CBRT { $$.val = tree.MakeUnaryOperator(tree.UnaryCbrt) }
@@ -17031,6 +17939,7 @@ unreserved_keyword:
 | AS_JSON
 | AT
 | ATOMIC
+| ATTACH
 | ATTRIBUTE
 | AUTOMATIC
 | AVAILABILITY
@@ -17103,12 +18012,14 @@ unreserved_keyword:
 | DELIMITER
 | DEPENDS
 | DESTINATION
+| DETACH
 | DETACHED
 | DETAILS
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_PASSPHRASE
@@ -17185,6 +18096,7 @@ unreserved_keyword:
 | INJECT
 | INPUT
 | INSERT
+| INSTEAD
 | INTO_DB
 | INVERTED
 | INVISIBLE
@@ -17212,6 +18124,7 @@ unreserved_keyword:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCKED
 | LOGIN
@@ -17219,6 +18132,7 @@ unreserved_keyword:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -17250,6 +18164,7 @@ unreserved_keyword:
 | NO
 | NORMAL
 | NOTHING
+| NOTIFY
 | NO_INDEX_JOIN
 | NO_ZIGZAG_JOIN
 | NO_FULL_SCAN
@@ -17404,6 +18319,7 @@ unreserved_keyword:
 | STABLE
 | START
 | STATE
+| STATEMENT
 | STATEMENTS
 | STATISTICS
 | STDIN
@@ -17512,6 +18428,7 @@ bare_label_keywords:
 | AS_JSON
 | AT
 | ATOMIC
+| ATTACH
 | ATTRIBUTE
 | AUTHORIZATION
 | AUTOMATIC
@@ -17612,6 +18529,7 @@ bare_label_keywords:
 | DEPENDS
 | DESC
 | DESTINATION
+| DETACH
 | DETACHED
 | DETAILS
 | DISCARD
@@ -17620,6 +18538,7 @@ bare_label_keywords:
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
 | ELSE
 | ENCODING
 | ENCRYPTED
@@ -17721,6 +18640,7 @@ bare_label_keywords:
 | INPUT
 | INSENSITIVE
 | INSERT
+| INSTEAD
 | INT
 | INTEGER
 | INTERVAL
@@ -17759,6 +18679,7 @@ bare_label_keywords:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCALITY
 | LOCALTIME
@@ -17768,6 +18689,7 @@ bare_label_keywords:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -17813,6 +18735,7 @@ bare_label_keywords:
 | NOT
 | NOTHING
 | NOTHING_AFTER_RETURNING
+| NOTIFY
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOVIEWCLUSTERSETTING
@@ -17973,6 +18896,7 @@ bare_label_keywords:
 | STABLE
 | START
 | STATE
+| STATEMENT
 | STATEMENTS
 | STATISTICS
 | STATUS
@@ -17999,6 +18923,7 @@ bare_label_keywords:
 | SYSTEM
 | TABLE
 | TABLES
+| TABLESAMPLE
 | TABLESPACE
 | TEMP
 | TEMPLATE
@@ -18181,6 +19106,7 @@ type_func_name_no_crdb_extra_keyword:
 | OVERLAPS
 | RIGHT
 | SIMILAR
+| TABLESAMPLE
 
 // CockroachDB-specific keywords that can be used in type/function
 // identifiers.
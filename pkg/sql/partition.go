@@ -14,6 +14,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/errors"
@@ -127,6 +129,119 @@ func partitionByFromTableDescImpl(
 	return partitionBy, nil
 }
 
+// partitionByWithAttachedPartition returns the PARTITION BY clause of the
+// primary index of the table with a new partition with the given name and
+// bound. If the table is not partitioned yet, it is partitioned by as many
+// columns of the primary key as there are values in the bound.
+func partitionByWithAttachedPartition(
+	codec keys.SQLCodec, tableDesc *tabledesc.Mutable, name tree.Name, bound *tree.PartitionBoundSpec,
+) (*tree.PartitionBy, error) {
+	partitionBy, err := partitionByFromTableDesc(codec, tableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if partitionBy == nil {
+		numColumns := len(bound.From)
+		if bound.Default {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"cannot attach DEFAULT partition %q to table %q which is not partitioned",
+				name, tableDesc.GetName())
+		} else if bound.In != nil {
+			numColumns = 1
+			if t, ok := bound.In[0].(*tree.Tuple); ok {
+				numColumns = len(t.Exprs)
+			}
+		}
+		idx := tableDesc.GetPrimaryIndex()
+		if numColumns > idx.NumKeyColumns() {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"partition bound has %d values, but the primary key of table %q has %d columns",
+				numColumns, tableDesc.GetName(), idx.NumKeyColumns())
+		}
+		partitionBy = &tree.PartitionBy{Fields: make(tree.NameList, numColumns)}
+		for i := range partitionBy.Fields {
+			partitionBy.Fields[i] = tree.Name(idx.GetKeyColumnName(i))
+		}
+	}
+	if findPartition(partitionBy, name) >= 0 {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"partition %q already exists on table %q", name, tableDesc.GetName())
+	}
+	switch {
+	case bound.Default, bound.In != nil:
+		if len(partitionBy.Range) > 0 {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"invalid bound specification for a range partition")
+		}
+		exprs := bound.In
+		if bound.Default {
+			// The DEFAULT partition contains all values that are not in another
+			// partition.
+			defaults := make(tree.Exprs, len(partitionBy.Fields))
+			for i := range defaults {
+				defaults[i] = &tree.DefaultVal{}
+			}
+			exprs = tree.Exprs{&tree.Tuple{Exprs: defaults}}
+		}
+		partitionBy.List = append(partitionBy.List, tree.ListPartition{Name: name, Exprs: exprs})
+	default:
+		if len(partitionBy.List) > 0 {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"invalid bound specification for a list partition")
+		}
+		partitionBy.Range = append(partitionBy.Range, tree.RangePartition{
+			Name: name, From: bound.From, To: bound.To,
+		})
+	}
+	return partitionBy, nil
+}
+
+// partitionByWithDetachedPartition returns the PARTITION BY clause of the
+// primary index of the table without the partition with the given name. Nil is
+// returned if no partitions remain.
+func partitionByWithDetachedPartition(
+	codec keys.SQLCodec, tableDesc *tabledesc.Mutable, name tree.Name,
+) (*tree.PartitionBy, error) {
+	partitionBy, err := partitionByFromTableDesc(codec, tableDesc)
+	if err != nil {
+		return nil, err
+	}
+	i := findPartition(partitionBy, name)
+	if i < 0 {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"partition %q of table %q does not exist", name, tableDesc.GetName())
+	}
+	if len(partitionBy.List) > 0 {
+		partitionBy.List = append(partitionBy.List[:i], partitionBy.List[i+1:]...)
+	} else {
+		partitionBy.Range = append(partitionBy.Range[:i], partitionBy.Range[i+1:]...)
+	}
+	if len(partitionBy.List) == 0 && len(partitionBy.Range) == 0 {
+		return nil, nil
+	}
+	return partitionBy, nil
+}
+
+// findPartition returns the index of the top-level partition with the given
+// name in the LIST or RANGE of the PARTITION BY clause, or -1 if there is no
+// such partition.
+func findPartition(partitionBy *tree.PartitionBy, name tree.Name) int {
+	if partitionBy == nil {
+		return -1
+	}
+	for i := range partitionBy.List {
+		if partitionBy.List[i].Name == name {
+			return i
+		}
+	}
+	for i := range partitionBy.Range {
+		if partitionBy.Range[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func partitionTupleToExprs(t *rowenc.PartitionTuple) (tree.Exprs, error) {
 	exprs := make(tree.Exprs, len(t.Datums)+t.SpecialCount)
 	for i, d := range t.Datums {
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
@@ -53,6 +54,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/vtable"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/collatedstring"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
@@ -912,7 +914,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = conTypeTrigger
-	_ = conTypeExclusion
 
 	fkActionNone       = tree.NewDString("a")
 	fkActionRestrict   = tree.NewDString("r")
@@ -964,6 +965,7 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		deferrability := constraintDeferrability(c)
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -1057,13 +1059,21 @@ func populateTableConstraints(
 			conoid = h.UniqueWithoutIndexConstraintOid(
 				db.GetID(), sc.GetID(), table.GetID(), uwoi,
 			)
-			f.WriteString("UNIQUE WITHOUT INDEX (")
-			colNames, err := catalog.ColumnNamesForIDs(table, uwoi.UniqueWithoutIndexDesc().ColumnIDs)
-			if err != nil {
-				return err
+			if uwoi.IsExclusion() {
+				contype = conTypeExclusion
+				if err := formatExclusionConstraint(f, table, uwoi.UniqueWithoutIndexDesc()); err != nil {
+					return err
+				}
+			} else {
+				f.WriteString("UNIQUE WITHOUT INDEX (")
+				colNames, err := catalog.ColumnNamesForIDs(table, uwoi.UniqueWithoutIndexDesc().ColumnIDs)
+				if err != nil {
+					return err
+				}
+				f.WriteString(strings.Join(colNames, ", "))
+				f.WriteByte(')')
+				f.FormatNode(&deferrability)
 			}
-			f.WriteString(strings.Join(colNames, ", "))
-			f.WriteByte(')')
 			if !uwoi.IsConstraintValidated() {
 				f.WriteString(" NOT VALID")
 			}
@@ -1094,13 +1104,15 @@ func populateTableConstraints(
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
 		}
 
+		condeferrable := tree.MakeDBool(tree.DBool(deferrability.IsDeferrable()))
+		condeferred := tree.MakeDBool(tree.DBool(deferrability == tree.ConstraintInitiallyDeferred))
 		if err := addRow(
 			conoid,                   // oid
 			dNameOrNull(c.GetName()), // conname
 			namespaceOid,             // connamespace
 			contype,                  // contype
-			tree.DBoolFalse,          // condeferrable
-			tree.DBoolFalse,          // condeferred
+			condeferrable,            // condeferrable
+			condeferred,              // condeferred
 			tree.MakeDBool(tree.DBool(!c.IsConstraintUnvalidated())), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -2127,13 +2139,40 @@ https://www.postgresql.org/docs/9.5/catalog-pg-language.html`,
 }
 
 var pgCatalogLocksTable = virtualSchemaTable{
-	comment: `locks held by active processes (empty - feature does not exist)
+	comment: `locks held by active processes (only advisory locks are shown)
 https://www.postgresql.org/docs/9.6/view-pg-locks.html`,
 	schema: vtable.PGCatalogLocks,
 	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				dbOID := dbOid(db.GetID())
+				return forEachAdvisoryLock(ctx, p, db.GetID(), func(
+					key eval.AdvisoryLockKey, mode eval.AdvisoryLockMode, txn *enginepb.TxnMeta, granted bool,
+				) error {
+					virtualTxn := tree.DNull
+					if txn != nil {
+						virtualTxn = tree.NewDString(txn.ID.String())
+					}
+					return addRow(
+						tree.NewDString("advisory"),           // locktype
+						dbOID,                                 // database
+						tree.DNull,                            // relation
+						tree.DNull,                            // page
+						tree.DNull,                            // tuple
+						tree.DNull,                            // virtualxid
+						tree.DNull,                            // transactionid
+						tree.NewDOid(oid.Oid(key.ClassID)),    // classid
+						tree.NewDOid(oid.Oid(key.ObjID)),      // objid
+						tree.NewDInt(tree.DInt(key.ObjSubID)), // objsubid
+						virtualTxn,                            // virtualtransaction
+						tree.DNull,                            // pid
+						tree.NewDString(mode.String()),        // mode
+						tree.MakeDBool(tree.DBool(granted)),   // granted
+						tree.DBoolFalse,                       // fastpath
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogMatViewsTable = virtualSchemaTable{
@@ -2598,12 +2637,17 @@ func addPgProcUDFRow(
 	var argNames tree.Datum
 	argNamesArray := tree.NewDArray(types.String)
 	foundAnyArgNames := false
+	variadicType := oidZero
 	for _, param := range fnDesc.GetParams() {
 		if err := argTypes.Append(tree.NewDOid(param.Type.Oid())); err != nil {
 			return err
 		}
-		// We only support IN arguments at the moment.
-		if err := argModes.Append(tree.NewDString("i")); err != nil {
+		mode := "i"
+		if param.Class == catpb.Function_Param_VARIADIC {
+			mode = "v"
+			variadicType = tree.NewDOid(param.Type.ArrayContents().Oid())
+		}
+		if err := argModes.Append(tree.NewDString(mode)); err != nil {
 			return err
 		}
 		if len(param.Name) > 0 {
@@ -2623,6 +2667,18 @@ func addPgProcUDFRow(
 		kind = tree.NewDString("p")
 	}
 
+	isSecurityDefiner := fnDesc.GetSecurity() == catpb.Function_DEFINER
+	config := tree.DNull
+	if len(fnDesc.GetConfig()) > 0 {
+		configArray := tree.NewDArray(types.String)
+		for _, setting := range fnDesc.GetConfig() {
+			if err := configArray.Append(tree.NewDString(setting)); err != nil {
+				return err
+			}
+		}
+		config = configArray
+	}
+
 	lang := languageInternalOid
 	if fnDesc.GetLanguage() == catpb.Function_PLPGSQL {
 		lang = languagePlpgsqlOid
@@ -2637,11 +2693,11 @@ func addPgProcUDFRow(
 		lang,            // prolang
 		tree.DNull,      // procost
 		tree.DNull,      // prorows
-		oidZero,         // provariadic
+		variadicType,    // provariadic
 		tree.DNull,      // protransform
 		tree.DBoolFalse, // proisagg
 		tree.DBoolFalse, // proiswindow
-		tree.DBoolFalse, // prosecdef
+		tree.MakeDBool(tree.DBool(isSecurityDefiner)),                // prosecdef
 		tree.MakeDBool(tree.DBool(fnDesc.GetLeakProof())),            // proleakproof
 		tree.MakeDBool(tree.DBool(isStrict)),                         // proisstrict
 		tree.MakeDBool(tree.DBool(fnDesc.GetReturnType().ReturnSet)), // proretset
@@ -2658,7 +2714,7 @@ func addPgProcUDFRow(
 		tree.DNull,                                       // protrftypes
 		tree.NewDString(fnDesc.GetFunctionBody()),        // prosrc
 		tree.DNull,                                       // probin
-		tree.DNull,                                       // proconfig
+		config,                                           // proconfig
 		tree.DNull,                                       // proacl
 		kind,                                             // prokind
 		// These columns were automatically created by pg_catalog_test's missing column generator.
@@ -3170,17 +3226,16 @@ https://www.postgresql.org/docs/9.5/catalog-pg-trigger.html`,
 }
 
 var (
-	typTypeBase      = tree.NewDString("b")
-	typTypeComposite = tree.NewDString("c")
-	typTypeDomain    = tree.NewDString("d")
-	typTypeEnum      = tree.NewDString("e")
-	typTypePseudo    = tree.NewDString("p")
-	typTypeRange     = tree.NewDString("r")
+	typTypeBase       = tree.NewDString("b")
+	typTypeComposite  = tree.NewDString("c")
+	typTypeDomain     = tree.NewDString("d")
+	typTypeEnum       = tree.NewDString("e")
+	typTypePseudo     = tree.NewDString("p")
+	typTypeRange      = tree.NewDString("r")
+	typTypeMultirange = tree.NewDString("m")
 
 	// Avoid unused warning for constants.
-	_ = typTypeDomain
 	_ = typTypePseudo
-	_ = typTypeRange
 
 	// See https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
 	typCategoryArray       = tree.NewDString("A")
@@ -3202,7 +3257,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typCategoryEnum
 	_ = typCategoryGeometric
-	_ = typCategoryRange
 	_ = typCategoryBitString
 
 	commaTypDelim = tree.NewDString(",")
@@ -3308,14 +3362,30 @@ func addPGTypeRow(
 		if isUDT {
 			typrelid = tree.NewDOid(typ.Oid())
 		}
-	case types.VoidFamily:
-		// void does not have an array type.
+	case types.VoidFamily, types.TriggerFamily:
+		// void and trigger do not have array types.
+	case types.RangeFamily:
+		typType = typTypeRange
+		typArray = tree.NewDOid(types.CalcArrayOid(typ))
+	case types.MultirangeFamily:
+		typType = typTypeMultirange
+		typArray = tree.NewDOid(types.CalcArrayOid(typ))
 	default:
 		typArray = tree.NewDOid(types.CalcArrayOid(typ))
 	}
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	if typ.IsDomain() {
+		typType = typTypeDomain
+		typArray = tree.NewDOid(types.CalcArrayOid(typ))
+		typBaseType = tree.NewDOid(typ.DomainBaseType().Oid())
+		if typ.TypeMeta.DomainData != nil && typ.TypeMeta.DomainData.NotNull {
+			typNotNull = tree.DBoolTrue
+		}
+	}
 	typname := typ.PGName()
 	typDelim := tree.NewDString(typ.Delimiter())
 	return addRow(
@@ -3345,8 +3415,8 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
@@ -4138,12 +4208,28 @@ var pgCatalogStatUserFunctionsTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications
+https://www.postgresql.org/docs/16/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				return db.ForEachPublication(func(pub *descpb.DatabaseDescriptor_Publication) error {
+					return addRow(
+						h.PublicationOid(db.GetID(), pub.Name),    // oid
+						tree.NewDName(pub.Name),                   // pubname
+						h.UserOid(pub.OwnerProto.Decode()),        // pubowner
+						tree.MakeDBool(tree.DBool(pub.AllTables)), // puballtables
+						tree.DBoolTrue,                            // pubinsert
+						tree.DBoolTrue,                            // pubupdate
+						tree.DBoolTrue,                            // pubdelete
+						tree.DBoolFalse,                           // pubtruncate
+						tree.DBoolFalse,                           // pubviaroot
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAmprocTable = virtualSchemaTable{
@@ -4193,12 +4279,12 @@ https://www.postgresql.org/docs/14/view-pg-cursors.html`,
 				return err
 			}
 			if err := addRow(
-				tree.NewDString(string(name)),          /* name */
-				tree.NewDString(c.statement),           /* statement */
-				tree.MakeDBool(tree.DBool(c.withHold)), /* is_holdable */
-				tree.DBoolFalse,                        /* is_binary */
-				tree.DBoolFalse,                        /* is_scrollable */
-				tz,                                     /* creation_date */
+				tree.NewDString(string(name)),                    /* name */
+				tree.NewDString(c.statement),                     /* statement */
+				tree.MakeDBool(tree.DBool(c.withHold)),           /* is_holdable */
+				tree.MakeDBool(tree.DBool(c.formatCodes != nil)), /* is_binary */
+				tree.MakeDBool(tree.DBool(c.scroll)),             /* is_scrollable */
+				tz,                                               /* creation_date */
 			); err != nil {
 				return err
 			}
@@ -4298,12 +4384,21 @@ var pgCatalogStatXactUserTablesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables of publications, including the tables of FOR ALL TABLES publications
+https://www.postgresql.org/docs/16/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables cannot be published */
+			func(db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				return forEachPublicationOfTable(db, table, func(pub *descpb.DatabaseDescriptor_Publication) error {
+					return addRow(
+						tree.NewDName(pub.Name),        // pubname
+						tree.NewDName(sc.GetName()),    // schemaname
+						tree.NewDName(table.GetName()), // tablename
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogStatProgressClusterTable = virtualSchemaTable{
@@ -4610,12 +4705,40 @@ var pgCatalogStatReplicationTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables explicitly added to publications
+https://www.postgresql.org/docs/16/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables cannot be published */
+			func(db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				return forEachPublicationOfTable(db, table, func(pub *descpb.DatabaseDescriptor_Publication) error {
+					if pub.AllTables {
+						return nil
+					}
+					return addRow(
+						h.PublicationRelOid(db.GetID(), pub.Name, table.GetID()), // oid
+						h.PublicationOid(db.GetID(), pub.Name),                   // prpubid
+						tableOid(table.GetID()),                                  // prrelid
+					)
+				})
+			})
 	},
-	unimplemented: true,
+}
+
+// forEachPublicationOfTable calls fn for each publication of the database
+// which includes the table.
+func forEachPublicationOfTable(
+	db catalog.DatabaseDescriptor,
+	table catalog.TableDescriptor,
+	fn func(pub *descpb.DatabaseDescriptor_Publication) error,
+) error {
+	return db.ForEachPublication(func(pub *descpb.DatabaseDescriptor_Publication) error {
+		if !publicationIncludesTable(pub, table) {
+			return nil
+		}
+		return fn(pub)
+	})
 }
 
 var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
@@ -4685,15 +4808,19 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.TimestampTZFamily: typCategoryDateTime,
 	types.TSQueryFamily:     typCategoryUserDefined,
 	types.TSVectorFamily:    typCategoryUserDefined,
+	types.JsonpathFamily:    typCategoryUserDefined,
 	types.ArrayFamily:       typCategoryArray,
 	types.TupleFamily:       typCategoryPseudo,
 	types.OidFamily:         typCategoryNumeric,
 	types.PGLSNFamily:       typCategoryUserDefined,
 	types.RefCursorFamily:   typCategoryUserDefined,
+	types.RangeFamily:       typCategoryRange,
+	types.MultirangeFamily:  typCategoryRange,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
 	types.UnknownFamily:     typCategoryUnknown,
 	types.VoidFamily:        typCategoryPseudo,
+	types.TriggerFamily:     typCategoryPseudo,
 }
 
 func typCategory(typ *types.T) tree.Datum {
@@ -4907,6 +5034,8 @@ const (
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
 	castTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -5081,6 +5210,23 @@ func (h oidHasher) DBSchemaRoleOid(
 	return h.getOid()
 }
 
+func (h oidHasher) PublicationOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+func (h oidHasher) PublicationRelOid(
+	dbID descpb.ID, name string, tableID descpb.ID,
+) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	h.writeTable(tableID)
+	return h.getOid()
+}
+
 func tableOid(id descpb.ID) *tree.DOid {
 	return tree.NewDOid(oid.Oid(id))
 }
@@ -11,15 +11,21 @@
 package lsnutil
 
 import (
-	"time"
-
 	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
-// HLCToLSN converts a HLC to a LSN.
-// It is in a separate package to prevent the `lsn` package importing `log`.
+// HLCToLSN converts a HLC to a LSN. The LSN is the wall time of the timestamp
+// in nanoseconds, which preserves the ordering of timestamps and fits into 64
+// bits. The logical component of the timestamp is dropped, so all timestamps
+// which share a wall time map to the same LSN.
 func HLCToLSN(h hlc.Timestamp) lsn.LSN {
-	// TODO(#105130): correctly populate this field.
-	return lsn.LSN(h.WallTime/int64(time.Millisecond)) << 32
+	return lsn.LSN(h.WallTime)
+}
+
+// LSNToHLC converts a LSN to the latest HLC which maps to the LSN, i.e. the
+// returned timestamp is at least as large as every timestamp t for which
+// HLCToLSN(t) == l.
+func LSNToHLC(l lsn.LSN) hlc.Timestamp {
+	return hlc.Timestamp{WallTime: int64(l) + 1}.Prev()
 }
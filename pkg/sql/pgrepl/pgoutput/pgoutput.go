@@ -0,0 +1,183 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package pgoutput implements the encoding of the messages of the Postgres
+// logical replication protocol, as produced by the pgoutput output plugin,
+// and of the streaming replication messages that carry them.
+//
+// See https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html.
+package pgoutput
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/lib/pq/oid"
+)
+
+// PluginName is the name of the output plugin implemented by this package.
+const PluginName = "pgoutput"
+
+// MessageType is the type of a logical replication message.
+type MessageType byte
+
+// Logical replication message types.
+const (
+	MsgBegin    MessageType = 'B'
+	MsgCommit   MessageType = 'C'
+	MsgRelation MessageType = 'R'
+	MsgType     MessageType = 'Y'
+	MsgInsert   MessageType = 'I'
+	MsgUpdate   MessageType = 'U'
+	MsgDelete   MessageType = 'D'
+)
+
+// Tuple data markers.
+const (
+	tupleNew  = 'N'
+	tupleKey  = 'K'
+	valueNull = 'n'
+	valueText = 't'
+)
+
+// ReplicaIdentityDefault is the replica identity of a relation whose primary
+// key columns identify a row. It is the only replica identity supported.
+const ReplicaIdentityDefault = 'd'
+
+// pgEpoch is the epoch of Postgres timestamps.
+var pgEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Column describes a column of a relation.
+type Column struct {
+	Name    string
+	TypeOID oid.Oid
+	TypeMod int32
+	// IsKey is true if the column is part of the replica identity of the
+	// relation, i.e. of the primary key.
+	IsKey bool
+}
+
+// Relation describes a table whose changes are replicated. A Relation message
+// is sent before the first change to the table and after every schema change.
+type Relation struct {
+	ID        oid.Oid
+	Namespace string
+	Name      string
+	Columns   []Column
+}
+
+// Value is the value of a column in a tuple. A nil Value is a SQL NULL.
+// Otherwise it contains the text representation of the column value.
+type Value []byte
+
+// AppendBegin appends a Begin message, which starts a transaction. finalLSN is
+// the LSN of the commit of the transaction.
+func AppendBegin(buf []byte, finalLSN lsn.LSN, commitTime time.Time, xid uint32) []byte {
+	buf = append(buf, byte(MsgBegin))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(finalLSN))
+	buf = appendTimestamp(buf, commitTime)
+	return binary.BigEndian.AppendUint32(buf, xid)
+}
+
+// AppendCommit appends a Commit message, which ends a transaction.
+func AppendCommit(buf []byte, commitLSN, endLSN lsn.LSN, commitTime time.Time) []byte {
+	buf = append(buf, byte(MsgCommit))
+	// Flags, currently unused.
+	buf = append(buf, 0)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(commitLSN))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(endLSN))
+	return appendTimestamp(buf, commitTime)
+}
+
+// AppendRelation appends a Relation message.
+func AppendRelation(buf []byte, rel *Relation) []byte {
+	buf = append(buf, byte(MsgRelation))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(rel.ID))
+	buf = appendString(buf, rel.Namespace)
+	buf = appendString(buf, rel.Name)
+	buf = append(buf, ReplicaIdentityDefault)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(rel.Columns)))
+	for i := range rel.Columns {
+		col := &rel.Columns[i]
+		var flags byte
+		if col.IsKey {
+			flags = 1
+		}
+		buf = append(buf, flags)
+		buf = appendString(buf, col.Name)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(col.TypeOID))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(col.TypeMod))
+	}
+	return buf
+}
+
+// AppendType appends a Type message, which describes a user-defined type
+// before it is first referenced by a Relation message.
+func AppendType(buf []byte, typOID oid.Oid, namespace, name string) []byte {
+	buf = append(buf, byte(MsgType))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(typOID))
+	buf = appendString(buf, namespace)
+	return appendString(buf, name)
+}
+
+// AppendInsert appends an Insert message for a new row of the given relation.
+func AppendInsert(buf []byte, relID oid.Oid, newRow []Value) []byte {
+	buf = append(buf, byte(MsgInsert))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(relID))
+	buf = append(buf, tupleNew)
+	return appendTuple(buf, newRow)
+}
+
+// AppendUpdate appends an Update message with the new values of a row of the
+// given relation. The old values are not included, which matches the
+// behavior of Postgres for tables with the default replica identity when the
+// key is not modified.
+func AppendUpdate(buf []byte, relID oid.Oid, newRow []Value) []byte {
+	buf = append(buf, byte(MsgUpdate))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(relID))
+	buf = append(buf, tupleNew)
+	return appendTuple(buf, newRow)
+}
+
+// AppendDelete appends a Delete message for a row of the given relation.
+// keyRow must contain the values of the key columns of the row; all other
+// values should be nil.
+func AppendDelete(buf []byte, relID oid.Oid, keyRow []Value) []byte {
+	buf = append(buf, byte(MsgDelete))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(relID))
+	buf = append(buf, tupleKey)
+	return appendTuple(buf, keyRow)
+}
+
+func appendTuple(buf []byte, row []Value) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(row)))
+	for _, v := range row {
+		if v == nil {
+			buf = append(buf, valueNull)
+			continue
+		}
+		buf = append(buf, valueText)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(v)))
+		buf = append(buf, v...)
+	}
+	return buf
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, s...)
+	return append(buf, 0)
+}
+
+// appendTimestamp appends t as the number of microseconds since the Postgres
+// epoch.
+func appendTimestamp(buf []byte, t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(buf, uint64(t.Sub(pgEpoch).Microseconds()))
+}
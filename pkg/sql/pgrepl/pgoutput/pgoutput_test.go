@@ -0,0 +1,112 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgoutput
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/lib/pq/oid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendMessages(t *testing.T) {
+	commitTime := pgEpoch.Add(3 * time.Microsecond)
+
+	t.Run("begin", func(t *testing.T) {
+		buf := AppendBegin(nil, lsn.LSN(0x0102), commitTime, 7)
+		require.Equal(t, []byte{
+			'B',
+			0, 0, 0, 0, 0, 0, 1, 2,
+			0, 0, 0, 0, 0, 0, 0, 3,
+			0, 0, 0, 7,
+		}, buf)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		buf := AppendCommit(nil, lsn.LSN(1), lsn.LSN(2), commitTime)
+		require.Equal(t, []byte{
+			'C', 0,
+			0, 0, 0, 0, 0, 0, 0, 1,
+			0, 0, 0, 0, 0, 0, 0, 2,
+			0, 0, 0, 0, 0, 0, 0, 3,
+		}, buf)
+	})
+
+	t.Run("relation", func(t *testing.T) {
+		buf := AppendRelation(nil, &Relation{
+			ID:        104,
+			Namespace: "public",
+			Name:      "t",
+			Columns: []Column{
+				{Name: "k", TypeOID: oid.T_int8, TypeMod: -1, IsKey: true},
+				{Name: "v", TypeOID: oid.T_text, TypeMod: -1},
+			},
+		})
+		expected := []byte{'R', 0, 0, 0, 104}
+		expected = append(expected, "public\x00t\x00"...)
+		expected = append(expected, 'd', 0, 2)
+		expected = append(expected, 1)
+		expected = append(expected, "k\x00"...)
+		expected = binary.BigEndian.AppendUint32(expected, uint32(oid.T_int8))
+		expected = append(expected, 0xff, 0xff, 0xff, 0xff)
+		expected = append(expected, 0)
+		expected = append(expected, "v\x00"...)
+		expected = binary.BigEndian.AppendUint32(expected, uint32(oid.T_text))
+		expected = append(expected, 0xff, 0xff, 0xff, 0xff)
+		require.Equal(t, expected, buf)
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		buf := AppendInsert(nil, 104, []Value{Value("1"), nil})
+		require.Equal(t, []byte{
+			'I', 0, 0, 0, 104, 'N', 0, 2,
+			't', 0, 0, 0, 1, '1',
+			'n',
+		}, buf)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		buf := AppendDelete(nil, 104, []Value{Value("12"), nil})
+		require.Equal(t, []byte{
+			'D', 0, 0, 0, 104, 'K', 0, 2,
+			't', 0, 0, 0, 2, '1', '2',
+			'n',
+		}, buf)
+	})
+}
+
+func TestParseClientMessage(t *testing.T) {
+	msg := []byte{'r'}
+	msg = binary.BigEndian.AppendUint64(msg, 3)
+	msg = binary.BigEndian.AppendUint64(msg, 2)
+	msg = binary.BigEndian.AppendUint64(msg, 1)
+	msg = binary.BigEndian.AppendUint64(msg, 0)
+	msg = append(msg, 1)
+	status, ok, err := ParseClientMessage(msg)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, StandbyStatusUpdate{
+		WriteLSN: 3, FlushLSN: 2, ApplyLSN: 1, ReplyRequested: true,
+	}, status)
+
+	_, ok, err = ParseClientMessage([]byte{'h', 0})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = ParseClientMessage(msg[:10])
+	require.Error(t, err)
+
+	_, _, err = ParseClientMessage([]byte{'x'})
+	require.Error(t, err)
+}
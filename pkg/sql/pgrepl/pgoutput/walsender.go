@@ -0,0 +1,100 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgoutput
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// The messages below are exchanged in CopyData messages once the connection
+// is in the streaming replication (copy-both) mode. See
+// https://www.postgresql.org/docs/current/protocol-replication.html.
+const (
+	msgXLogData               = 'w'
+	msgPrimaryKeepalive       = 'k'
+	msgStandbyStatusUpdate    = 'r'
+	msgHotStandbyFeedback     = 'h'
+	standbyStatusUpdateLength = 1 + 8 + 8 + 8 + 8 + 1
+)
+
+// AppendXLogData appends the header of a XLogData message, which carries a
+// single logical replication message. The logical replication message must be
+// appended to the returned buffer.
+func AppendXLogData(buf []byte, startLSN, endLSN lsn.LSN, sendTime time.Time) []byte {
+	buf = append(buf, msgXLogData)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(startLSN))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(endLSN))
+	return appendTimestamp(buf, sendTime)
+}
+
+// AppendPrimaryKeepalive appends a primary keepalive message. walEnd is the
+// LSN up to which all changes have been sent to the client.
+func AppendPrimaryKeepalive(
+	buf []byte, walEnd lsn.LSN, sendTime time.Time, replyRequested bool,
+) []byte {
+	buf = append(buf, msgPrimaryKeepalive)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(walEnd))
+	buf = appendTimestamp(buf, sendTime)
+	var reply byte
+	if replyRequested {
+		reply = 1
+	}
+	return append(buf, reply)
+}
+
+// StandbyStatusUpdate is sent by the client to report its progress.
+type StandbyStatusUpdate struct {
+	// WriteLSN is the LSN up to which the client has received changes.
+	WriteLSN lsn.LSN
+	// FlushLSN is the LSN up to which the client has durably applied changes.
+	// Changes up to this LSN are not sent again when streaming restarts.
+	FlushLSN lsn.LSN
+	// ApplyLSN is the LSN up to which the client has applied changes.
+	ApplyLSN lsn.LSN
+	// ReplyRequested is true if the client asks for an immediate keepalive.
+	ReplyRequested bool
+}
+
+// ParseClientMessage parses the payload of a CopyData message sent by the
+// client. It returns ok=false for messages which carry no information for
+// logical replication, such as hot standby feedback.
+func ParseClientMessage(data []byte) (_ StandbyStatusUpdate, ok bool, _ error) {
+	if len(data) == 0 {
+		return StandbyStatusUpdate{}, false, pgerror.New(
+			pgcode.ProtocolViolation, "unexpected empty message in replication stream",
+		)
+	}
+	switch data[0] {
+	case msgStandbyStatusUpdate:
+		if len(data) < standbyStatusUpdateLength {
+			return StandbyStatusUpdate{}, false, pgerror.New(
+				pgcode.ProtocolViolation, "invalid standby status update message",
+			)
+		}
+		return StandbyStatusUpdate{
+			WriteLSN:       lsn.LSN(binary.BigEndian.Uint64(data[1:])),
+			FlushLSN:       lsn.LSN(binary.BigEndian.Uint64(data[9:])),
+			ApplyLSN:       lsn.LSN(binary.BigEndian.Uint64(data[17:])),
+			ReplyRequested: data[33] != 0,
+		}, true, nil
+	case msgHotStandbyFeedback:
+		return StandbyStatusUpdate{}, false, nil
+	default:
+		return StandbyStatusUpdate{}, false, pgerror.Newf(
+			pgcode.ProtocolViolation, "unexpected message type %q in replication stream", data[0],
+		)
+	}
+}
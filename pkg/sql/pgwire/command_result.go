@@ -60,6 +60,9 @@ type commandResult struct {
 	buffer struct {
 		notices            []pgnotice.Notice
 		paramStatusUpdates []paramStatusUpdate
+		// notifications are only buffered by the results of Sync and Flush
+		// commands, and are sent before ReadyForQuery.
+		notifications []sql.Notification
 	}
 
 	err error
@@ -147,6 +150,11 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err when sending notice"))
 		}
 	}
+	for _, n := range r.buffer.notifications {
+		if err := r.conn.bufferNotification(n); err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err when sending notification"))
+		}
+	}
 
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
@@ -332,6 +340,12 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 	r.buffer.notices = append(r.buffer.notices, notice)
 }
 
+// BufferNotification is part of the sql.SyncResult and sql.FlushResult
+// interfaces.
+func (r *commandResult) BufferNotification(n sql.Notification) {
+	r.buffer.notifications = append(r.buffer.notifications, n)
+}
+
 // SendNotice is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SendNotice(ctx context.Context, notice pgnotice.Notice) error {
 	if err := r.conn.bufferNotice(ctx, notice); err != nil {
@@ -642,6 +656,10 @@ func (r *limitedCommandResult) moreResultsNeeded(ctx context.Context) error {
 			if err := r.conn.Flush(r.pos); err != nil {
 				return err
 			}
+		case sql.DeliverNotifications:
+			// The session is in a transaction, so the notifications will be
+			// delivered once it finishes.
+			r.conn.stmtBuf.AdvanceOne()
 		default:
 			// If the portal is immediately followed by a COMMIT, we can proceed and
 			// let the portal be destroyed at the end of the transaction.
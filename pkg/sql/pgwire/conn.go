@@ -259,6 +259,16 @@ func (c *conn) bufferParamStatus(param, value string) error {
 	return c.msgBuilder.finishMsg(&c.writerState.buf)
 }
 
+// bufferNotification buffers a NotificationResponse message for a
+// notification sent with NOTIFY on a channel the session is listening on.
+func (c *conn) bufferNotification(n sql.Notification) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	c.msgBuilder.putInt32(int32(n.PID))
+	c.msgBuilder.writeTerminatedString(n.Channel)
+	c.msgBuilder.writeTerminatedString(n.Payload)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) bufferNotice(ctx context.Context, noticeErr pgnotice.Notice) error {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgNoticeResponse)
 	return c.writeErrFields(ctx, noticeErr, &c.writerState.buf)
@@ -354,8 +364,33 @@ func (c *conn) handleSimpleQuery(
 			log.SqlExec.Infof(ctx, "could not parse simple query in replication protocol: %s", query)
 			return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
 		}
-		switch stmt.AST.(type) {
-		case *pgrepltree.IdentifySystem:
+		switch t := stmt.AST.(type) {
+		case *pgrepltree.IdentifySystem,
+			*pgrepltree.CreateReplicationSlot,
+			*pgrepltree.DropReplicationSlot,
+			*pgrepltree.ReadReplicationSlot:
+		case *pgrepltree.StartReplication:
+			// START_REPLICATION is special, like COPY FROM: execution takes
+			// control of the connection to stream changes to the client, so we
+			// block this network routine until control is passed back.
+			var wg sync.WaitGroup
+			var once sync.Once
+			wg.Add(1)
+			cmd := sql.StartReplication{
+				Conn:         c,
+				ParsedStmt:   stmt,
+				Stmt:         t,
+				TimeReceived: timeReceived,
+				ParseStart:   startParse,
+				ParseEnd:     timeutil.Now(),
+			}
+			cmd.ReplicationDone.WaitGroup = &wg
+			cmd.ReplicationDone.Once = &once
+			if err := c.stmtBuf.Push(ctx, cmd); err != nil {
+				return err
+			}
+			wg.Wait()
+			return nil
 		default:
 			log.SqlExec.Infof(ctx, "unhandled replication protocol query: %s", query)
 			return c.stmtBuf.Push(ctx, sql.SendError{
@@ -805,6 +840,28 @@ func (c *conn) BeginCopyIn(
 	return c.msgBuilder.finishMsg(c.conn)
 }
 
+// BeginCopyBoth is part of the pgwirebase.Conn interface.
+func (c *conn) BeginCopyBoth(ctx context.Context) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	// The overall format is text and there are no columns.
+	c.msgBuilder.writeByte(byte(pgwirebase.FormatText))
+	c.msgBuilder.putInt16(0)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendCopyData is part of the pgwirebase.Conn interface.
+func (c *conn) SendCopyData(ctx context.Context, data []byte) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDataCommand)
+	c.msgBuilder.write(data)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendCopyDone is part of the pgwirebase.Conn interface.
+func (c *conn) SendCopyDone(ctx context.Context) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDoneCommand)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
 // Rd is part of the pgwirebase.Conn interface.
 func (c *conn) Rd() pgwirebase.BufferedReader {
 	return &pgwireReader{conn: c}
@@ -862,7 +919,7 @@ func cookTag(
 			tag = strconv.AppendUint(tag, uint64(rowsAffected), 10)
 		}
 
-	case tree.Ack, tree.DDL:
+	case tree.Ack, tree.DDL, tree.Replication:
 		if tagStr == "SELECT" {
 			tag = append(tag, ' ')
 			tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
@@ -1414,6 +1471,16 @@ func (c *conn) CreateCopyOutResult(cmd sql.CopyOut, pos sql.CmdPos) sql.CopyOutR
 	return res
 }
 
+// CreateStartReplicationResult is part of the sql.ClientComm interface.
+func (c *conn) CreateStartReplicationResult(
+	cmd sql.StartReplication, pos sql.CmdPos,
+) sql.StartReplicationResult {
+	res := c.newMiscResult(pos, commandComplete)
+	res.stmtType = cmd.Stmt.StatementReturnType()
+	res.cmdCompleteTag = cmd.Stmt.StatementTag()
+	return res
+}
+
 // pgwireReader is an io.Reader that wraps a conn, maintaining its metrics as
 // it is consumed.
 type pgwireReader struct {
@@ -30,4 +30,16 @@ type Conn interface {
 	// subprotocol (COPY ... FROM STDIN). This message informs the client about
 	// the columns that are expected for the rows to be inserted.
 	BeginCopyIn(ctx context.Context, columns []colinfo.ResultColumn, format FormatCode) error
+
+	// BeginCopyBoth sends the server message initiating the Copy-both
+	// subprotocol, which is used for streaming replication.
+	BeginCopyBoth(ctx context.Context) error
+
+	// SendCopyData sends a CopyData message to the client during the Copy-both
+	// subprotocol. The message is written to the connection immediately.
+	SendCopyData(ctx context.Context, data []byte) error
+
+	// SendCopyDone sends a CopyDone message to the client, which ends the
+	// Copy-both subprotocol on the server side.
+	SendCopyDone(ctx context.Context) error
 }
@@ -454,6 +454,11 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(bs)
+		case oidext.T_jsonpath:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(bs)
 		case oid.T_tsquery:
 			ret, err := tsearch.ParseTSQuery(bs)
 			if err != nil {
@@ -475,6 +480,18 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.NewDString(bs), nil
+		case types.RangeFamily:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			r, _, err := tree.ParseDRangeFromString(evalCtx, bs, typ)
+			return r, err
+		case types.MultirangeFamily:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			r, _, err := tree.ParseDMultirangeFromString(evalCtx, bs, typ)
+			return r, err
 		}
 	case FormatBinary:
 		switch id {
@@ -720,6 +737,19 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(encoding.UnsafeConvertBytesToString(b))
+		case oidext.T_jsonpath:
+			if len(b) < 1 {
+				return nil, NewProtocolViolationErrorf("no data to decode")
+			}
+			if b[0] != 1 {
+				return nil, NewProtocolViolationErrorf("expected jsonpath version 1")
+			}
+			// Skip over the version number.
+			b = b[1:]
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(string(b))
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -786,6 +816,12 @@ func DecodeDatum(
 			if typ.Family() == types.TupleFamily {
 				return decodeBinaryTuple(ctx, evalCtx, b)
 			}
+			if typ.Family() == types.RangeFamily {
+				return decodeBinaryRange(ctx, evalCtx, typ, b)
+			}
+			if typ.Family() == types.MultirangeFamily {
+				return decodeBinaryMultirange(ctx, evalCtx, typ, b)
+			}
 			if typ.Family() == types.OidFamily {
 				if len(b) < 4 {
 					return nil, pgerror.Newf(pgcode.ProtocolViolation, "oid requires 4 bytes for binary format")
@@ -1019,6 +1055,87 @@ func decodeBinaryArray(
 	return arr, nil
 }
 
+// Flags of the binary format of ranges.
+const (
+	binaryRangeEmpty    = 0x01
+	binaryRangeLowerInc = 0x02
+	binaryRangeUpperInc = 0x04
+	binaryRangeLowerInf = 0x08
+	binaryRangeUpperInf = 0x10
+)
+
+// decodeBinaryRange decodes the binary format of a range: a flags byte,
+// followed by the length-prefixed binary format of each finite bound.
+func decodeBinaryRange(
+	ctx context.Context, evalCtx *eval.Context, t *types.T, b []byte,
+) (*tree.DRange, error) {
+	if len(b) < 1 {
+		return nil, pgerror.Newf(pgcode.ProtocolViolation, "range requires a flags byte for binary format")
+	}
+	flags := b[0]
+	b = b[1:]
+	if flags&binaryRangeEmpty != 0 {
+		return tree.NewDEmptyRange(t), nil
+	}
+	bounds := [2]tree.Datum{tree.DNull, tree.DNull}
+	for i, inf := range []byte{binaryRangeLowerInf, binaryRangeUpperInf} {
+		if flags&inf != 0 {
+			continue
+		}
+		if len(b) < elementSize {
+			return nil, pgerror.Newf(pgcode.ProtocolViolation, "insufficient bytes reading range bound length")
+		}
+		n := int32(binary.BigEndian.Uint32(b))
+		b = b[elementSize:]
+		if n < 0 || int(n) > len(b) {
+			return nil, pgerror.Newf(pgcode.ProtocolViolation, "invalid range bound length %d", n)
+		}
+		var err error
+		bounds[i], err = DecodeDatum(ctx, evalCtx, t.RangeContents(), FormatBinary, b[:n])
+		if err != nil {
+			return nil, err
+		}
+		b = b[n:]
+	}
+	return tree.NewDRange(
+		t, bounds[0], bounds[1], flags&binaryRangeLowerInc != 0, flags&binaryRangeUpperInc != 0,
+	)
+}
+
+// decodeBinaryMultirange decodes the binary format of a multirange: the
+// number of ranges, followed by the length-prefixed binary format of each
+// range.
+func decodeBinaryMultirange(
+	ctx context.Context, evalCtx *eval.Context, t *types.T, b []byte,
+) (tree.Datum, error) {
+	if len(b) < elementSize {
+		return nil, pgerror.Newf(pgcode.ProtocolViolation, "multirange requires a 4 byte header for binary format")
+	}
+	n := int32(binary.BigEndian.Uint32(b))
+	b = b[elementSize:]
+	if n < 0 {
+		return nil, pgerror.Newf(pgcode.ProtocolViolation, "invalid number of ranges %d", n)
+	}
+	ranges := make([]*tree.DRange, 0, n)
+	for i := int32(0); i < n; i++ {
+		if len(b) < elementSize {
+			return nil, pgerror.Newf(pgcode.ProtocolViolation, "insufficient bytes reading range length")
+		}
+		rangeLen := int32(binary.BigEndian.Uint32(b))
+		b = b[elementSize:]
+		if rangeLen < 0 || int(rangeLen) > len(b) {
+			return nil, pgerror.Newf(pgcode.ProtocolViolation, "invalid range length %d", rangeLen)
+		}
+		r, err := decodeBinaryRange(ctx, evalCtx, t.RangeContents(), b[:rangeLen])
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+		b = b[rangeLen:]
+	}
+	return tree.NewDMultirange(t, ranges), nil
+}
+
 const tupleHeaderSize, oidSize, elementSize = 4, 4, 4
 
 func decodeBinaryTuple(ctx context.Context, evalCtx *eval.Context, b []byte) (tree.Datum, error) {
@@ -43,6 +43,7 @@ const (
 	ServerMsgBindComplete         ServerMessageType = '2'
 	ServerMsgCommandComplete      ServerMessageType = 'C'
 	ServerMsgCloseComplete        ServerMessageType = '3'
+	ServerMsgCopyBothResponse     ServerMessageType = 'W'
 	ServerMsgCopyInResponse       ServerMessageType = 'G'
 	ServerMsgCopyOutResponse      ServerMessageType = 'H'
 	ServerMsgCopyDataCommand      ServerMessageType = 'd'
@@ -51,6 +52,7 @@ const (
 	ServerMsgEmptyQuery           ServerMessageType = 'I'
 	ServerMsgErrorResponse        ServerMessageType = 'E'
 	ServerMsgNoticeResponse       ServerMessageType = 'N'
+	ServerMsgNotificationResponse ServerMessageType = 'A'
 	ServerMsgNoData               ServerMessageType = 'n'
 	ServerMsgParameterDescription ServerMessageType = 't'
 	ServerMsgParameterStatus      ServerMessageType = 'S'
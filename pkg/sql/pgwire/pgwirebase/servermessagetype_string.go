@@ -13,6 +13,7 @@ func _() {
 	_ = x[ServerMsgBindComplete-50]
 	_ = x[ServerMsgCommandComplete-67]
 	_ = x[ServerMsgCloseComplete-51]
+	_ = x[ServerMsgCopyBothResponse-87]
 	_ = x[ServerMsgCopyInResponse-71]
 	_ = x[ServerMsgCopyOutResponse-72]
 	_ = x[ServerMsgCopyDataCommand-100]
@@ -21,6 +22,7 @@ func _() {
 	_ = x[ServerMsgEmptyQuery-73]
 	_ = x[ServerMsgErrorResponse-69]
 	_ = x[ServerMsgNoticeResponse-78]
+	_ = x[ServerMsgNotificationResponse-65]
 	_ = x[ServerMsgNoData-110]
 	_ = x[ServerMsgParameterDescription-116]
 	_ = x[ServerMsgParameterStatus-83]
@@ -42,6 +44,8 @@ func (i ServerMessageType) String() string {
 		return "ServerMsgCommandComplete"
 	case ServerMsgCloseComplete:
 		return "ServerMsgCloseComplete"
+	case ServerMsgCopyBothResponse:
+		return "ServerMsgCopyBothResponse"
 	case ServerMsgCopyInResponse:
 		return "ServerMsgCopyInResponse"
 	case ServerMsgCopyOutResponse:
@@ -58,6 +62,8 @@ func (i ServerMessageType) String() string {
 		return "ServerMsgErrorResponse"
 	case ServerMsgNoticeResponse:
 		return "ServerMsgNoticeResponse"
+	case ServerMsgNotificationResponse:
+		return "ServerMsgNotificationResponse"
 	case ServerMsgNoData:
 		return "ServerMsgNoData"
 	case ServerMsgParameterDescription:
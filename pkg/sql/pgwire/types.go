@@ -58,6 +58,9 @@ type pgType struct {
 }
 
 func pgTypeForParserType(t *types.T) pgType {
+	// Values of a domain type are described by their base type, as in
+	// Postgres.
+	t = t.DomainBaseType()
 	size := tree.PGWireTypeSize(t)
 	tOid := t.Oid()
 	if tOid == oid.T_text && t.Width() > 0 {
@@ -154,7 +157,10 @@ func writeTextDatumNotNull(
 	sessionLoc *time.Location,
 	t *types.T,
 ) {
-
+	if t != nil {
+		// Values of a domain type are encoded like values of its base type.
+		t = t.DomainBaseType()
+	}
 	oldDCC := b.textFormatter.SetDataConversionConfig(conv)
 	oldLoc := b.textFormatter.SetLocation(sessionLoc)
 	defer func() {
@@ -246,6 +252,9 @@ func writeTextDatumNotNull(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DJsonpath:
+		b.writeLengthPrefixedString(v.Path.String())
+
 	case *tree.DTSQuery:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -258,6 +267,10 @@ func writeTextDatumNotNull(
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
 
+	case *tree.DRange, *tree.DMultirange:
+		b.textFormatter.FormatNode(v)
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DArray:
 		// Arrays have custom formatting depending on their OID.
 		b.textFormatter.FormatNode(d)
@@ -587,6 +600,10 @@ func (b *writeBuffer) writeBinaryDatum(
 func writeBinaryDatumNotNull(
 	ctx context.Context, b *writeBuffer, d tree.Datum, sessionLoc *time.Location, t *types.T,
 ) {
+	if t != nil {
+		// Values of a domain type are encoded like values of its base type.
+		t = t.DomainBaseType()
+	}
 	switch v := tree.UnwrapDOidWrapper(d).(type) {
 	case *tree.DBitArray:
 		words, lastBitsUsed := v.EncodingParts()
@@ -725,6 +742,24 @@ func writeBinaryDatumNotNull(
 		lengthToWrite := b.Len() - (initialLen + 4)
 		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
 
+	case *tree.DRange:
+		initialLen := b.Len()
+		b.putInt32(int32(0))
+		b.writeBinaryRange(ctx, v, sessionLoc, t)
+		b.putInt32AtIndex(initialLen /* index to write at */, int32(b.Len()-(initialLen+4)))
+
+	case *tree.DMultirange:
+		initialLen := b.Len()
+		b.putInt32(int32(0))
+		b.putInt32(int32(len(v.Ranges)))
+		for _, r := range v.Ranges {
+			rangeLen := b.Len()
+			b.putInt32(int32(0))
+			b.writeBinaryRange(ctx, r, sessionLoc, t.RangeContents())
+			b.putInt32AtIndex(rangeLen /* index to write at */, int32(b.Len()-(rangeLen+4)))
+		}
+		b.putInt32AtIndex(initialLen /* index to write at */, int32(b.Len()-(initialLen+4)))
+
 	case *tree.DVoid:
 		b.putInt32(0)
 
@@ -809,6 +844,13 @@ func writeBinaryDatumNotNull(
 	case *tree.DJSON:
 		writeBinaryJSON(b, v.JSON, t)
 
+	case *tree.DJsonpath:
+		s := v.Path.String()
+		b.putInt32(int32(len(s) + 1))
+		// Postgres version number, as of writing, `1` is the only valid value.
+		b.writeByte(1)
+		b.writeString(s)
+
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.Oid))
@@ -817,6 +859,46 @@ func writeBinaryDatumNotNull(
 	}
 }
 
+// Flags of the binary format of ranges.
+const (
+	pgBinaryRangeEmpty    = 0x01
+	pgBinaryRangeLowerInc = 0x02
+	pgBinaryRangeUpperInc = 0x04
+	pgBinaryRangeLowerInf = 0x08
+	pgBinaryRangeUpperInf = 0x10
+)
+
+// writeBinaryRange writes the binary format of a range, without its length
+// prefix: a flags byte, followed by the length-prefixed binary format of each
+// finite bound.
+func (b *writeBuffer) writeBinaryRange(
+	ctx context.Context, r *tree.DRange, sessionLoc *time.Location, rangeTyp *types.T,
+) {
+	if r.Empty {
+		b.writeByte(pgBinaryRangeEmpty)
+		return
+	}
+	var flags byte
+	if r.LowerInc {
+		flags |= pgBinaryRangeLowerInc
+	}
+	if r.UpperInc {
+		flags |= pgBinaryRangeUpperInc
+	}
+	if r.Lower == tree.DNull {
+		flags |= pgBinaryRangeLowerInf
+	}
+	if r.Upper == tree.DNull {
+		flags |= pgBinaryRangeUpperInf
+	}
+	b.writeByte(flags)
+	for _, bound := range []tree.Datum{r.Lower, r.Upper} {
+		if bound != tree.DNull {
+			b.writeBinaryDatum(ctx, bound, sessionLoc, rangeTyp.RangeContents())
+		}
+	}
+}
+
 // writeBinaryColumnarElement is the same as writeBinaryDatum where the datum is
 // represented in a columnar element (at position rowIdx in the vector at
 // position vecIdx in vecs).
@@ -115,6 +115,9 @@ type extendedEvalContext struct {
 
 	// validateDbZoneConfig should the DB zone config on commit.
 	validateDbZoneConfig *bool
+
+	// deferredConstraints refers to deferredConstraints in extraTxnState.
+	deferredConstraints *deferredConstraints
 }
 
 // copyFromExecCfg copies relevant fields from an ExecutorConfig.
@@ -242,6 +245,14 @@ type planner struct {
 
 	createdSequences createdSequences
 
+	// advisoryLocks tracks the advisory locks held by the session. It is nil
+	// if the planner is not associated with a connExecutor.
+	advisoryLocks *advisoryLockState
+
+	// notifications tracks the channels the session listens on. It is nil if
+	// the planner is not associated with a connExecutor.
+	notifications *notificationState
+
 	// autoCommit indicates whether the plan is allowed (but not required) to
 	// commit the transaction along with other KV operations. Committing the txn
 	// might be beneficial because it may enable the 1PC optimization. Note that
@@ -645,10 +656,11 @@ func (p *planner) InternalSQLTxn() descs.Txn {
 		ie := MakeInternalExecutor(ief.server, ief.memMetrics, ief.monitor)
 		ie.SetSessionData(p.SessionData())
 		ie.extraTxnState = &extraTxnState{
-			txn:                p.Txn(),
-			descCollection:     p.Descriptors(),
-			jobs:               p.extendedEvalCtx.jobs,
-			schemaChangerState: p.extendedEvalCtx.SchemaChangerState,
+			txn:                 p.Txn(),
+			descCollection:      p.Descriptors(),
+			jobs:                p.extendedEvalCtx.jobs,
+			schemaChangerState:  p.extendedEvalCtx.SchemaChangerState,
+			deferredConstraints: p.extendedEvalCtx.deferredConstraints,
 		}
 		p.internalSQLTxn.init(p.txn, ie)
 	}
@@ -11,6 +11,7 @@
 package parser
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -374,6 +375,139 @@ func (l *lexer) MakeFetchOrMoveStmt(isMove bool) (plpgsqltree.Statement, error)
 	}, nil
 }
 
+// MakeAliasDeclaration reads the target of an ALIAS FOR declaration, which is
+// either the name of a variable or a positional parameter reference like $1.
+// The name of the alias is set by the caller.
+func (l *lexer) MakeAliasDeclaration() (*plpgsqltree.AliasDeclaration, error) {
+	startPos, endPos, _, err := l.readSQLConstruct(true /* isExpr */, false /* allowEmpty */, ';')
+	if err != nil {
+		return nil, err
+	}
+	// Move past the semicolon.
+	l.lastPos++
+	target := strings.TrimSpace(l.getStr(startPos, endPos))
+	if strings.HasPrefix(target, "$") {
+		idx, err := strconv.Atoi(target[1:])
+		if err != nil || idx < 1 {
+			return nil, errors.Newf("\"%s\" is not a valid parameter reference", target)
+		}
+		return &plpgsqltree.AliasDeclaration{TargetIdx: idx}, nil
+	}
+	if endPos-startPos != 1 {
+		return nil, errors.Newf("\"%s\" is not a known variable", target)
+	}
+	return &plpgsqltree.AliasDeclaration{
+		Target: plpgsqltree.Variable(l.tokens[startPos].str),
+	}, nil
+}
+
+// MakeForControl reads the control portion of a FOR loop, up to the LOOP
+// keyword. It returns a ForInt statement for an integer FOR loop, and a
+// ForSelect statement for a FOR loop over the results of a query. The label and
+// body of the returned statement are set by the caller.
+func (l *lexer) MakeForControl() (plpgsqltree.Statement, error) {
+	startPos, endPos, _, err := l.readSQLConstruct(false /* isExpr */, false /* allowEmpty */, LOOP)
+	if err != nil {
+		return nil, err
+	}
+	// Read the comma-separated list of loop variables that precedes IN.
+	var target []plpgsqltree.Variable
+	pos := startPos
+	for ; pos < endPos; pos += 2 {
+		tok := l.tokens[pos]
+		if tok.id != IDENT {
+			return nil, errors.Newf("\"%s\" is not a scalar variable", tok.str)
+		}
+		target = append(target, plpgsqltree.Variable(tok.str))
+		if pos+1 == endPos || l.tokens[pos+1].id != ',' {
+			pos++
+			break
+		}
+	}
+	if pos >= endPos || l.tokens[pos].id != IN {
+		return nil, errors.New("expected IN in FOR loop")
+	}
+	pos++
+	reverse := false
+	if pos < endPos && l.tokens[pos].id == REVERSE {
+		reverse = true
+		pos++
+	}
+	// An integer FOR loop is distinguished from a FOR loop over a query by the
+	// ".." token that separates the lower and upper bounds.
+	dotDotPos, byPos := -1, -1
+	parenLevel := 0
+	for i := pos; i < endPos; i++ {
+		switch l.tokens[i].id {
+		case '(', '[':
+			parenLevel++
+		case ')', ']':
+			parenLevel--
+		case DOT_DOT:
+			if parenLevel == 0 && dotDotPos == -1 {
+				dotDotPos = i
+			}
+		case BY:
+			if parenLevel == 0 && dotDotPos != -1 && byPos == -1 {
+				byPos = i
+			}
+		}
+	}
+	if dotDotPos == -1 {
+		if reverse {
+			return nil, errors.New("cannot specify REVERSE in query FOR loop")
+		}
+		stmts, err := parser.Parse(l.getStr(pos, endPos))
+		if err != nil {
+			return nil, err
+		}
+		if len(stmts) != 1 {
+			return nil, errors.New("expected exactly one SQL statement for FOR loop")
+		}
+		return &plpgsqltree.ForSelect{
+			ForQuery: plpgsqltree.ForQuery{Target: target},
+			Query:    stmts[0].AST,
+		}, nil
+	}
+	if len(target) != 1 {
+		return nil, errors.New("integer FOR loop must have only one target variable")
+	}
+	upperEndPos := endPos
+	if byPos != -1 {
+		upperEndPos = byPos
+	}
+	lower, err := l.parseForBound(pos, dotDotPos)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := l.parseForBound(dotDotPos+1, upperEndPos)
+	if err != nil {
+		return nil, err
+	}
+	var step plpgsqltree.Expr
+	if byPos != -1 {
+		if step, err = l.parseForBound(byPos+1, endPos); err != nil {
+			return nil, err
+		}
+	}
+	return &plpgsqltree.ForInt{
+		Var:     target[0],
+		Lower:   lower,
+		Upper:   upper,
+		Step:    step,
+		Reverse: reverse,
+	}, nil
+}
+
+// parseForBound parses the expression between the given token positions as a
+// bound or step of an integer FOR loop.
+func (l *lexer) parseForBound(startPos, endPos int) (plpgsqltree.Expr, error) {
+	if endPos <= startPos {
+		return nil, errors.New("missing expression")
+	}
+	return l.ParseExpr(l.getStr(startPos, endPos))
+}
+
 func (l *lexer) ReadSqlExpr(
 	terminator1 int, terminators ...int,
 ) (sqlStr string, terminatorMet int, err error) {
@@ -167,6 +167,14 @@ func (u *plpgsqlSymUnion) sqlStatement() tree.Statement {
     return u.val.(tree.Statement)
 }
 
+func (u *plpgsqlSymUnion) cursorArg() plpgsqltree.CursorArg {
+    return u.val.(plpgsqltree.CursorArg)
+}
+
+func (u *plpgsqlSymUnion) cursorArgs() []plpgsqltree.CursorArg {
+    return u.val.([]plpgsqltree.CursorArg)
+}
+
 %}
 /*
  * Basic non-keyword token types.  These are hard-wired into the core lexer.
@@ -325,7 +333,7 @@ func (u *plpgsqlSymUnion) sqlStatement() tree.Statement {
 %type <*tree.NumVal>	foreach_slice
 %type <plpgsqltree.Statement>	for_control
 
-%type <str> any_identifier opt_block_label opt_loop_label opt_label query_options
+%type <str> any_identifier opt_block_label opt_loop_label opt_label
 %type <str> opt_error_level option_type
 
 %type <[]plpgsqltree.Statement> proc_sect
@@ -340,7 +348,10 @@ func (u *plpgsqlSymUnion) sqlStatement() tree.Statement {
 %type <plpgsqltree.Statement>	stmt_commit stmt_rollback
 %type <plpgsqltree.Statement>	stmt_case stmt_foreach_a
 
-%type <plpgsqltree.Statement> decl_stmt decl_statement
+%type <plpgsqltree.Statement> decl_stmt decl_statement decl_aliasitem
+%type <[]plpgsqltree.CursorArg> decl_cursor_args decl_cursor_arglist
+%type <plpgsqltree.CursorArg> decl_cursor_arg
+%type <tree.ResolvableTypeReference> decl_cursor_argtype
 %type <[]plpgsqltree.Statement> decl_sect opt_decl_stmts decl_stmts
 
 %type <[]plpgsqltree.Exception> exception_sect proc_exceptions
@@ -456,15 +467,18 @@ decl_statement: decl_varname decl_const decl_datatype decl_collate decl_notnull
       Expr: $6.expr(),
     }
   }
-| decl_varname ALIAS FOR decl_aliasitem ';'
+| decl_varname ALIAS FOR decl_aliasitem
   {
-    return unimplemented(plpgsqllex, "alias for")
+    alias := $4.statement().(*plpgsqltree.AliasDeclaration)
+    alias.Name = plpgsqltree.Variable($1)
+    $$.val = alias
   }
 | decl_varname opt_scrollable CURSOR decl_cursor_args decl_is_for decl_cursor_query
   {
     $$.val = &plpgsqltree.CursorDeclaration{
       Name: plpgsqltree.Variable($1),
       Scroll: $2.cursorScrollOption(),
+      Args: $4.cursorArgs(),
       Query: $6.sqlStatement(),
     }
   }
@@ -497,25 +511,47 @@ decl_cursor_query: stmt_until_semi ';'
   }
 ;
 
-decl_cursor_args: '('
+decl_cursor_args: '(' decl_cursor_arglist ')'
   {
-    return unimplemented(plpgsqllex, "cursor arguments")
+    $$.val = $2.cursorArgs()
   }
 | /* EMPTY */
   {
+    $$.val = []plpgsqltree.CursorArg(nil)
   }
 ;
 
 decl_cursor_arglist: decl_cursor_arg
   {
+    $$.val = []plpgsqltree.CursorArg{$1.cursorArg()}
   }
 | decl_cursor_arglist ',' decl_cursor_arg
   {
+    $$.val = append($1.cursorArgs(), $3.cursorArg())
   }
 ;
 
-decl_cursor_arg: decl_varname decl_datatype
+decl_cursor_arg: decl_varname decl_cursor_argtype
   {
+    $$.val = plpgsqltree.CursorArg{
+      Name: plpgsqltree.Variable($1),
+      Typ: $2.typ(),
+    }
+  }
+;
+
+decl_cursor_argtype:
+  {
+    // Read until reaching the end of the cursor argument.
+    sqlStr, _, err := plpgsqllex.(*lexer).ReadSqlExpr(',', ')')
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    typ, err := plpgsqllex.(*lexer).GetTypeFromValidSQLSyntax(sqlStr)
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = typ
   }
 ;
 
@@ -523,11 +559,13 @@ decl_is_for:
   IS   /* Oracle */
 | FOR  /* SQL standard */
 
-decl_aliasitem: IDENT
-  {
-  }
-| unreserved_keyword
+decl_aliasitem:
   {
+    alias, err := plpgsqllex.(*lexer).MakeAliasDeclaration()
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = alias
   }
 ;
 
@@ -736,7 +774,16 @@ proc_stmt:pl_block ';'
 
 stmt_perform: PERFORM stmt_until_semi ';'
   {
-    return unimplemented(plpgsqllex, "perform")
+    // PERFORM is equivalent to a SELECT statement with the SELECT keyword
+    // replaced by PERFORM.
+    stmts, err := parser.Parse("SELECT " + $2)
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    if len(stmts) != 1 {
+      return setErr(plpgsqllex, errors.New("expected exactly one SQL statement for PERFORM"))
+    }
+    $$.val = &plpgsqltree.Perform{Query: stmts[0].AST}
   }
 ;
 
@@ -770,6 +817,18 @@ stmt_assign: IDENT assign_operator expr_until_semi ';'
       Value: expr,
     }
   }
+| IDENT '.' any_identifier assign_operator expr_until_semi ';'
+  {
+    expr, err := plpgsqllex.(*lexer).ParseExpr($5)
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = &plpgsqltree.Assignment{
+      Var: plpgsqltree.Variable($1),
+      Value: expr,
+      Indirection: tree.Name($3),
+    }
+  }
 ;
 
 stmt_getdiag: GET getdiag_area_opt DIAGNOSTICS getdiag_list ';'
@@ -992,16 +1051,31 @@ stmt_while: opt_loop_label WHILE expr_until_loop LOOP loop_body opt_label ';'
   }
 ;
 
-stmt_for: opt_loop_label FOR for_control loop_body
+stmt_for: opt_loop_label FOR for_control LOOP loop_body opt_label ';'
   {
-    return unimplemented(plpgsqllex, "for loop")
+    loopLabel, loopEndLabel := $1, $6
+    if err := checkLoopLabels(loopLabel, loopEndLabel); err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    switch t := $3.statement().(type) {
+    case *plpgsqltree.ForInt:
+      t.Label = $1
+      t.Body = $5.statements()
+    case *plpgsqltree.ForSelect:
+      t.Label = $1
+      t.Body = $5.statements()
+    }
+    $$.val = $3.statement()
   }
 ;
 
-for_control: for_variable IN
-  // TODO(drewk) need to parse the sql expression here.
+for_control:
   {
-    return unimplemented(plpgsqllex, "for loop")
+    stmt, err := plpgsqllex.(*lexer).MakeForControl()
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = stmt
   }
 ;
 
@@ -1073,39 +1147,42 @@ stmt_return: RETURN return_expr ';'
     }
     $$.val = &plpgsqltree.Return{Expr: expr}
   }
-| RETURN_NEXT NEXT
+| RETURN_NEXT NEXT return_expr ';'
   {
-    return unimplemented(plpgsqllex, "return next")
+    var expr plpgsqltree.Expr
+    if $3 != "" {
+      var err error
+      expr, err = plpgsqllex.(*lexer).ParseExpr($3)
+      if err != nil {
+        return setErr(plpgsqllex, err)
+      }
+    }
+    $$.val = &plpgsqltree.ReturnNext{Expr: expr}
   }
-| RETURN_QUERY QUERY
- {
-   return unimplemented (plpgsqllex, "return query")
- }
-;
-
-return_expr:
+| RETURN_QUERY QUERY EXECUTE
   {
-    sqlStr, err := plpgsqllex.(*lexer).ReadReturnExpr()
+    return unimplemented(plpgsqllex, "return dynamic sql query")
+  }
+| RETURN_QUERY QUERY stmt_until_semi ';'
+  {
+    stmts, err := parser.Parse($3)
     if err != nil {
       return setErr(plpgsqllex, err)
     }
-    $$ = sqlStr
+    if len(stmts) != 1 {
+      return setErr(plpgsqllex, errors.New("expected exactly one SQL statement for RETURN QUERY"))
+    }
+    $$.val = &plpgsqltree.ReturnQuery{Query: stmts[0].AST}
   }
 ;
 
-query_options:
+return_expr:
   {
-    _, terminator, err := plpgsqllex.(*lexer).ReadSqlExpr(EXECUTE, ';')
-    if err != nil {
-      return setErr(plpgsqllex, err)
-    }
-    if terminator == EXECUTE {
-      return unimplemented (plpgsqllex, "return dynamic sql query")
-    }
-    _, _, err = plpgsqllex.(*lexer).ReadSqlExpr(';')
+    sqlStr, err := plpgsqllex.(*lexer).ReadReturnExpr()
     if err != nil {
       return setErr(plpgsqllex, err)
     }
+    $$ = sqlStr
   }
 ;
 
@@ -1312,6 +1389,14 @@ stmt_open: OPEN IDENT ';'
   {
     $$.val = &plpgsqltree.Open{CurVar: plpgsqltree.Variable($2)}
   }
+| OPEN IDENT '(' expr_until_paren ')' ';'
+  {
+    args, err := parser.ParseExprs([]string{$4})
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = &plpgsqltree.Open{CurVar: plpgsqltree.Variable($2), Args: args}
+  }
 | OPEN IDENT opt_scrollable FOR EXECUTE 
   {
     return unimplemented(plpgsqllex, "cursor for execute")
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/geo/geogen"
 	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
@@ -293,11 +294,49 @@ func RandDatumWithNullChance(
 		return tree.NewDTSVector(tsearch.RandomTSVector(rng))
 	case types.TSQueryFamily:
 		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
+	case types.JsonpathFamily:
+		return randJsonpath(rng)
+	case types.RangeFamily:
+		return randRange(rng, typ, favorCommonData)
+	case types.MultirangeFamily:
+		ranges := make([]*tree.DRange, rng.Intn(4))
+		for i := range ranges {
+			ranges[i] = randRange(rng, typ.RangeContents(), favorCommonData)
+		}
+		return tree.NewDMultirange(typ, ranges)
 	default:
 		panic(errors.AssertionFailedf("invalid type %v", typ.DebugString()))
 	}
 }
 
+// randRange returns a random range of the given range type. Each bound is
+// infinite with a small probability.
+func randRange(rng *rand.Rand, typ *types.T, favorCommonData bool) *tree.DRange {
+	if rng.Intn(10) == 0 {
+		return tree.NewDEmptyRange(typ)
+	}
+	bounds := [2]tree.Datum{tree.DNull, tree.DNull}
+	for i := range bounds {
+		if rng.Intn(10) != 0 {
+			bounds[i] = RandDatumWithNullChance(
+				rng, typ.RangeContents(), 0 /* nullChance */, favorCommonData, false, /* targetColumnIsUnique */
+			)
+		}
+	}
+	if bounds[0] != tree.DNull && bounds[1] != tree.DNull {
+		if cmp, err := bounds[0].CompareError(&eval.Context{}, bounds[1]); err != nil {
+			panic(err)
+		} else if cmp > 0 {
+			bounds[0], bounds[1] = bounds[1], bounds[0]
+		}
+	}
+	r, err := tree.NewDRange(typ, bounds[0], bounds[1], rng.Intn(2) == 0, rng.Intn(2) == 0)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
 // RandArray generates a random DArray where the contents have nullChance
 // of being null.
 func RandArray(rng *rand.Rand, typ *types.T, nullChance int) tree.Datum {
@@ -485,6 +524,8 @@ func RandDatumSimple(rng *rand.Rand, typ *types.T) tree.Datum {
 		datum = tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
 	case types.TSVectorFamily:
 		datum = tree.NewDTSVector(tsearch.RandomTSVector(rng))
+	case types.JsonpathFamily:
+		datum = randJsonpath(rng)
 	}
 	return datum
 }
@@ -529,6 +570,34 @@ func randJSONSimpleDepth(rng *rand.Rand, depth int) json.JSON {
 	}
 }
 
+// randJsonpath returns a random jsonpath made of a chain of accessors,
+// optionally followed by a filter.
+func randJsonpath(rng *rand.Rand) tree.Datum {
+	var buf bytes.Buffer
+	if rng.Intn(2) == 0 {
+		buf.WriteString("strict ")
+	}
+	buf.WriteString("$")
+	for i := rng.Intn(4); i > 0; i-- {
+		switch rng.Intn(3) {
+		case 0:
+			fmt.Fprintf(&buf, ".%s", randStringSimple(rng))
+		case 1:
+			buf.WriteString("[*]")
+		default:
+			fmt.Fprintf(&buf, "[%d]", rng.Intn(simpleRange))
+		}
+	}
+	if rng.Intn(2) == 0 {
+		fmt.Fprintf(&buf, " ? (@ == %d)", rng.Intn(simpleRange))
+	}
+	d, err := tree.ParseDJsonpath(buf.String())
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
 var (
 	// randInterestingDatums is a collection of interesting datums that can be
 	// used for random testing.
@@ -14,13 +14,14 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
 )
 
 type refreshMaterializedViewNode struct {
@@ -70,6 +71,16 @@ func (p *planner) RefreshMaterializedView(
 }
 
 func (n *refreshMaterializedViewNode) startExec(params runParams) error {
+	// Views which are refreshed concurrently, or incrementally by a job, are
+	// refreshed by applying the difference between the rows of the view and
+	// the result of the view query in the current transaction. Replacing the
+	// indexes of an incrementally refreshed view would lose the changes written
+	// by the job during the refresh.
+	incremental := n.desc.GetIncrementalRefreshJobID() != catpb.InvalidJobID
+	if n.n.Concurrently || incremental {
+		return n.refreshByDiff(params, incremental)
+	}
+
 	// We refresh a materialized view by creating a new set of indexes to write
 	// the result of the view query into. The existing set of indexes will remain
 	// present and readable so that reads of the view during the refresh operation
@@ -83,14 +94,6 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 
 	telemetry.Inc(sqltelemetry.SchemaRefreshMaterializedView)
 
-	// Inform the user that CONCURRENTLY is not needed.
-	if n.n.Concurrently {
-		params.p.BufferClientNotice(
-			params.ctx,
-			pgnotice.Newf("CONCURRENTLY is not required as views are refreshed concurrently"),
-		)
-	}
-
 	// Prepare the new set of indexes by cloning all existing indexes on the view.
 	newPrimaryIndex := n.desc.GetPrimaryIndex().IndexDescDeepCopy()
 	newIndexes := make([]descpb.IndexDescriptor, len(n.desc.PublicNonPrimaryIndexes()))
@@ -128,6 +131,44 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 	)
 }
 
+// refreshByDiff refreshes the view by deleting the rows which are not in the
+// result of the view query anymore and inserting the new rows. Readers of the
+// view see the rows which changed being replaced instead of the whole view.
+func (n *refreshMaterializedViewNode) refreshByDiff(params runParams, incremental bool) error {
+	telemetry.Inc(sqltelemetry.SchemaRefreshMaterializedView)
+
+	if n.n.RefreshDataOption == tree.RefreshDataClear {
+		if n.n.Concurrently {
+			return pgerror.New(pgcode.Syntax,
+				"CONCURRENTLY and WITH NO DATA options cannot be used together")
+		}
+		return errors.WithHint(
+			pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cannot clear materialized view %q which is refreshed incrementally", n.desc.Name),
+			"Reset the incremental_refresh storage parameter of the materialized view first.")
+	}
+	if n.desc.IsRefreshViewRequired() {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"CONCURRENTLY cannot be used when the materialized view is not populated")
+	}
+
+	// Incrementally refreshed views do not contain duplicate rows, so they do
+	// not need a unique index to be refreshed concurrently.
+	var nullableKey []string
+	if !incremental {
+		key, err := concurrentRefreshKey(n.desc)
+		if err != nil {
+			return err
+		}
+		for _, col := range key {
+			if col.IsNullable() {
+				nullableKey = append(nullableKey, col.GetName())
+			}
+		}
+	}
+	return refreshMaterializedViewByDiff(params.ctx, params.p.InternalSQLTxn(), n.desc, nullableKey)
+}
+
 func (n *refreshMaterializedViewNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *refreshMaterializedViewNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *refreshMaterializedViewNode) Close(ctx context.Context)           {}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// Reindex is only implemented in the declarative schema changer, so the
+// legacy schema changer is only reached when the declarative schema changer is
+// disabled or does not support rebuilding the indexes of the table.
+func (p *planner) Reindex(ctx context.Context, n *tree.Reindex) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"REINDEX",
+	); err != nil {
+		return nil, err
+	}
+	return nil, errors.WithHint(
+		pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported by the legacy schema changer", n.StatementTag()),
+		"REINDEX requires use_declarative_schema_changer to be enabled, and is "+
+			"not supported on tables with index or partition zone configurations.",
+	)
+}
@@ -0,0 +1,290 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsnutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// replicationSlotTypeLogical is the slot_type of logical replication slots in
+// system.replication_slots. Physical replication slots are not supported.
+const replicationSlotTypeLogical = "logical"
+
+// maxReplicationSlotNameLength matches the maximum length of a replication
+// slot name in Postgres.
+const maxReplicationSlotNameLength = 63
+
+// replicationSlot is a row of system.replication_slots.
+type replicationSlot struct {
+	name       string
+	plugin     string
+	slotType   string
+	databaseID descpb.ID
+	// confirmedFlushLSN is the LSN up to which the client has confirmed the
+	// receipt of changes. Streaming from the slot resumes after this LSN.
+	confirmedFlushLSN lsn.LSN
+}
+
+// validateReplicationSlotName checks that the name of a replication slot only
+// contains lower case letters, numbers and underscores, like Postgres does.
+func validateReplicationSlotName(name string) error {
+	if len(name) == 0 {
+		return pgerror.Newf(pgcode.InvalidName, "replication slot name %q is too short", name)
+	}
+	if len(name) > maxReplicationSlotNameLength {
+		return pgerror.Newf(pgcode.NameTooLong, "replication slot name %q is too long", name)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.InvalidName, "replication slot name %q contains invalid character", name),
+				"Replication slot names may only contain lower case letters, numbers, and the underscore character.",
+			)
+		}
+	}
+	return nil
+}
+
+// checkLogicalReplicationSupported returns an error if the session cannot use
+// logical replication slots.
+func checkLogicalReplicationSupported(
+	ctx context.Context, st clusterversion.Handle, sd *sessiondata.SessionData,
+) error {
+	if !st.IsActive(ctx, clusterversion.V24_1_ReplicationSlotsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"replication slots are not supported until upgrade to version %s is finalized",
+			clusterversion.V24_1_ReplicationSlotsTable.String())
+	}
+	if sd.ReplicationMode != sessiondatapb.ReplicationMode_REPLICATION_MODE_DATABASE {
+		return pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"logical decoding requires a database connection")
+	}
+	return nil
+}
+
+// getReplicationSlot reads a replication slot from system.replication_slots.
+// It returns nil if the slot does not exist.
+func getReplicationSlot(
+	ctx context.Context, txn isql.Txn, kvTxn *kv.Txn, name string,
+) (*replicationSlot, error) {
+	row, err := txn.QueryRowEx(
+		ctx, "get-replication-slot", kvTxn, sessiondata.NodeUserSessionDataOverride,
+		`SELECT plugin, slot_type, database_id, confirmed_flush_lsn
+		   FROM system.replication_slots WHERE slot_name = $1`,
+		name,
+	)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return &replicationSlot{
+		name:              name,
+		plugin:            string(tree.MustBeDString(row[0])),
+		slotType:          string(tree.MustBeDString(row[1])),
+		databaseID:        descpb.ID(tree.MustBeDInt(row[2])),
+		confirmedFlushLSN: lsn.LSN(tree.MustBeDInt(row[3])),
+	}, nil
+}
+
+// updateReplicationSlotLSN advances the confirmed flush LSN of a replication
+// slot. The LSN is never moved backwards.
+func updateReplicationSlotLSN(
+	ctx context.Context, db isql.DB, name string, confirmedFlushLSN lsn.LSN,
+) error {
+	return db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := txn.ExecEx(
+			ctx, "update-replication-slot", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+			`UPDATE system.replication_slots SET confirmed_flush_lsn = $2
+			  WHERE slot_name = $1 AND confirmed_flush_lsn < $2`,
+			name, int64(confirmedFlushLSN),
+		)
+		return err
+	})
+}
+
+func newUndefinedReplicationSlotError(name string) error {
+	return pgerror.Newf(pgcode.UndefinedObject, "replication slot %q does not exist", name)
+}
+
+type createReplicationSlotNode struct {
+	optColumnsSlot
+	n *pgrepltree.CreateReplicationSlot
+
+	consistentPoint lsn.LSN
+	shown           bool
+}
+
+// CreateReplicationSlot creates a logical replication slot.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, n *pgrepltree.CreateReplicationSlot,
+) (planNode, error) {
+	if n.Kind != pgrepltree.LogicalReplication {
+		return nil, unimplemented.New("physical replication", "physical replication slots are not supported")
+	}
+	if n.Temporary {
+		return nil, unimplemented.New("temporary replication slots", "temporary replication slots are not supported")
+	}
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg().Settings.Version, p.SessionData()); err != nil {
+		return nil, err
+	}
+	if err := validateReplicationSlotName(string(n.Slot)); err != nil {
+		return nil, err
+	}
+	if string(n.Plugin) != pgoutput.PluginName {
+		return nil, errors.WithHintf(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"logical decoding output plugin %q is not supported", n.Plugin),
+			"The only supported output plugin is %q.", pgoutput.PluginName,
+		)
+	}
+	return &createReplicationSlotNode{n: n}, nil
+}
+
+func (n *createReplicationSlotNode) startExec(params runParams) error {
+	p := params.p
+	dbDesc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(params.ctx, p.CurrentDatabase())
+	if err != nil {
+		return err
+	}
+	existing, err := getReplicationSlot(params.ctx, p.InternalSQLTxn(), p.txn, string(n.n.Slot))
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return pgerror.Newf(pgcode.DuplicateObject, "replication slot %q already exists", n.n.Slot)
+	}
+	// Changes which are committed after the read timestamp of the transaction
+	// are streamed from the slot.
+	n.consistentPoint = lsnutil.HLCToLSN(p.txn.ReadTimestamp())
+	_, err = p.InternalSQLTxn().ExecEx(
+		params.ctx, "create-replication-slot", p.txn, sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.replication_slots
+		   (slot_name, plugin, slot_type, database_id, confirmed_flush_lsn)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		string(n.n.Slot), string(n.n.Plugin), replicationSlotTypeLogical,
+		int64(dbDesc.GetID()), int64(n.consistentPoint),
+	)
+	return err
+}
+
+func (n *createReplicationSlotNode) Next(params runParams) (bool, error) {
+	if n.shown {
+		return false, nil
+	}
+	n.shown = true
+	return true, nil
+}
+
+func (n *createReplicationSlotNode) Values() tree.Datums {
+	return tree.Datums{
+		tree.NewDString(string(n.n.Slot)),
+		tree.NewDString(n.consistentPoint.String()),
+		// Snapshots are not exported. Clients may read the initial state of the
+		// replicated tables using AS OF SYSTEM TIME at the consistent point.
+		tree.DNull,
+		tree.NewDString(string(n.n.Plugin)),
+	}
+}
+
+func (n *createReplicationSlotNode) Close(ctx context.Context) {}
+
+type dropReplicationSlotNode struct {
+	n *pgrepltree.DropReplicationSlot
+}
+
+// DropReplicationSlot drops a replication slot.
+func (p *planner) DropReplicationSlot(
+	ctx context.Context, n *pgrepltree.DropReplicationSlot,
+) (planNode, error) {
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg().Settings.Version, p.SessionData()); err != nil {
+		return nil, err
+	}
+	return &dropReplicationSlotNode{n: n}, nil
+}
+
+func (n *dropReplicationSlotNode) startExec(params runParams) error {
+	p := params.p
+	rowsAffected, err := p.InternalSQLTxn().ExecEx(
+		params.ctx, "drop-replication-slot", p.txn, sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.replication_slots WHERE slot_name = $1`,
+		string(n.n.Slot),
+	)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return newUndefinedReplicationSlotError(string(n.n.Slot))
+	}
+	return nil
+}
+
+func (n *dropReplicationSlotNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropReplicationSlotNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropReplicationSlotNode) Close(ctx context.Context)           {}
+
+type readReplicationSlotNode struct {
+	optColumnsSlot
+	shown bool
+}
+
+// ReadReplicationSlot returns information about a physical replication slot.
+// Since only logical replication slots are supported, it always returns a
+// row of NULLs for missing slots and an error for existing ones, like Postgres
+// does for logical slots.
+func (p *planner) ReadReplicationSlot(
+	ctx context.Context, n *pgrepltree.ReadReplicationSlot,
+) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_ReplicationSlotsTable) {
+		return &readReplicationSlotNode{}, nil
+	}
+	slot, err := getReplicationSlot(ctx, p.InternalSQLTxn(), p.txn, string(n.Slot))
+	if err != nil {
+		return nil, err
+	}
+	if slot != nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"cannot use READ_REPLICATION_SLOT with a logical replication slot")
+	}
+	return &readReplicationSlotNode{}, nil
+}
+
+func (n *readReplicationSlotNode) startExec(params runParams) error {
+	return nil
+}
+
+func (n *readReplicationSlotNode) Next(params runParams) (bool, error) {
+	if n.shown {
+		return false, nil
+	}
+	n.shown = true
+	return true, nil
+}
+
+func (n *readReplicationSlotNode) Values() tree.Datums {
+	return tree.Datums{tree.DNull, tree.DNull, tree.DNull}
+}
+
+func (n *readReplicationSlotNode) Close(ctx context.Context) {}
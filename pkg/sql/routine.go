@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -75,8 +76,11 @@ func (p *planner) EvalRoutineExpr(
 		return expr.CachedResult, nil
 	}
 
-	if expr.TailCall && !expr.Generator && p.EvalContext().RoutineSender != nil {
-		// This is a nested routine in tail-call position.
+	if expr.TailCall && !expr.Generator && expr.SessionSettings == nil &&
+		p.EvalContext().RoutineSender != nil {
+		// This is a nested routine in tail-call position. Routines that change the
+		// session data are not deferred, since the changes must only be visible
+		// for the duration of the routine.
 		if tailCallOptimizationEnabled {
 			// Tail-call optimizations are enabled. Send the information needed to
 			// evaluate this routine to the parent routine, then return. It is safe to
@@ -147,6 +151,28 @@ type routineGenerator struct {
 		expr *tree.RoutineExpr
 		args tree.Datums
 	}
+	// resultBuffer is set when this is the root routine of a set-returning
+	// PLpgSQL routine. It holds the rows added by RETURN NEXT and RETURN QUERY
+	// statements, and is preserved when the generator is reset to evaluate a
+	// routine in tail-call position or an exception handler.
+	resultBuffer *routineResultBuffer
+}
+
+// routineResultBuffer accumulates the result rows of a set-returning PLpgSQL
+// routine.
+type routineResultBuffer struct {
+	// shared is the buffer that is referenced by all routines that make up the
+	// set-returning routine.
+	shared *tree.RoutineResultBuffer
+	// prevContainer is the value of shared.Container before the buffer was
+	// initialized. It is restored when the generator is closed.
+	prevContainer interface{}
+	rows          rowContainerHelper
+	// typ is the return type of the routine.
+	typ *types.T
+	// expandTuple is true if the routine returns multiple columns, in which case
+	// each tuple added to the buffer is expanded into its elements.
+	expandTuple bool
 }
 
 var _ eval.ValueGenerator = &routineGenerator{}
@@ -161,13 +187,17 @@ func (g *routineGenerator) init(p *planner, expr *tree.RoutineExpr, args tree.Da
 	}
 }
 
-// reset closes and re-initializes a routineGenerator for reuse.
+// reset closes and re-initializes a routineGenerator for reuse. The result
+// buffer of a set-returning PLpgSQL routine, if any, is preserved.
 // TODO(drewk): we should hold on to memory for the row container.
 func (g *routineGenerator) reset(
 	ctx context.Context, p *planner, expr *tree.RoutineExpr, args tree.Datums,
 ) {
+	resultBuffer := g.resultBuffer
+	g.resultBuffer = nil
 	g.Close(ctx)
 	g.init(p, expr, args)
+	g.resultBuffer = resultBuffer
 }
 
 // ResolvedType is part of the eval.ValueGenerator interface.
@@ -177,17 +207,103 @@ func (g *routineGenerator) ResolvedType() *types.T {
 
 // Start is part of the eval.ValueGenerator interface.
 func (g *routineGenerator) Start(ctx context.Context, txn *kv.Txn) (err error) {
+	if g.expr.SessionSettings != nil {
+		// The routine was defined with SECURITY DEFINER or with SET clauses.
+		// Since all statements are executed before Start returns, the session
+		// data changes can be undone once it finishes.
+		var popSessionSettings func() error
+		popSessionSettings, err = g.p.pushRoutineSessionSettings(ctx, g.expr.SessionSettings)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.CombineErrors(err, popSessionSettings())
+		}()
+	}
+	if g.expr.ResultBuffer != nil && g.expr.Generator {
+		// This is the root routine of a set-returning PLpgSQL routine.
+		g.initResultBuffer(ctx)
+	}
 	for {
 		err = g.startInternal(ctx, txn)
 		if err != nil || g.deferredRoutine.expr == nil {
 			// No tail-call optimization.
-			return err
+			break
 		}
 		// A nested routine in tail-call position deferred its execution until now.
 		// Since it's in tail-call position, evaluating it will give the result of
 		// this routine as well.
 		g.reset(ctx, g.p, g.deferredRoutine.expr, g.deferredRoutine.args)
 	}
+	if err != nil || g.resultBuffer == nil {
+		return err
+	}
+	// The output of a set-returning PLpgSQL routine is the set of rows added by
+	// RETURN NEXT and RETURN QUERY statements. The result of the last statement
+	// is discarded.
+	g.rci.Close()
+	g.rci = newRowContainerIterator(ctx, g.resultBuffer.rows)
+	return nil
+}
+
+// pushRoutineSessionSettings pushes a copy of the current session data onto
+// the session data stack, and applies the given routine settings to it. The
+// returned function pops the session data, restoring the original settings.
+func (p *planner) pushRoutineSessionSettings(
+	ctx context.Context, settings *tree.RoutineSessionSettings,
+) (pop func() error, err error) {
+	sds := p.sessionDataMutatorIterator.sds
+	sds.PushTopClone()
+	defer func() {
+		if err != nil {
+			_ = sds.Pop()
+		}
+	}()
+	sd := sds.Top()
+	if !settings.Definer.Undefined() {
+		// The routine executes with the privileges of its owner. The
+		// session_user is unchanged, similar to SET ROLE.
+		isAdmin, err := p.UserHasAdminRole(ctx, settings.Definer)
+		if err != nil {
+			return nil, err
+		}
+		if sd.SessionUserProto == "" {
+			sd.SessionUserProto = sd.UserProto
+		}
+		sd.UserProto = settings.Definer.EncodeProto()
+		sd.IsSuperuser = isAdmin
+		sd.SearchPath = sd.SearchPath.WithUserSchemaName(sd.User().Normalized())
+	}
+	m := p.sessionDataMutatorIterator.mutator(false /* applyCallbacks */, sd)
+	for _, setting := range settings.Config {
+		name, value := funcinfo.SplitConfig(setting)
+		_, v, err := getSessionVar(name, false /* missingOk */)
+		if err != nil {
+			return nil, err
+		}
+		if v.Set == nil {
+			return nil, newCannotChangeParameterError(name)
+		}
+		if err := v.Set(ctx, m, value); err != nil {
+			return nil, err
+		}
+	}
+	return sds.Pop, nil
+}
+
+// initResultBuffer initializes the buffer for the rows returned by a
+// set-returning PLpgSQL routine, and makes it available to the sub-routines
+// through the shared tree.RoutineResultBuffer.
+func (g *routineGenerator) initResultBuffer(ctx context.Context) {
+	typ := g.expr.ResolvedType()
+	g.resultBuffer = &routineResultBuffer{
+		shared:        g.expr.ResultBuffer,
+		prevContainer: g.expr.ResultBuffer.Container,
+		typ:           typ,
+		expandTuple:   g.expr.MultiColOutput,
+	}
+	g.resultBuffer.rows.Init(ctx, []*types.T{typ}, g.p.ExtendedEvalContext(), "routine_result" /* opName */)
+	g.expr.ResultBuffer.Container = &g.resultBuffer.rows
 }
 
 // startInternal implements logic for a single execution of a routine.
@@ -245,6 +361,7 @@ func (g *routineGenerator) startInternal(ctx context.Context, txn *kv.Txn) (err
 
 		var w rowResultWriter
 		openCursor := stmtIdx == 1 && g.expr.CursorDeclaration != nil
+		addToResult := stmtIdx == 1 && g.expr.ResultBuffer != nil && !g.expr.Generator
 		if isFinalPlan && !g.expr.Procedure {
 			// The result of this statement is the routine's output. This is never the
 			// case for a procedure, which does not output any rows (since we do not
@@ -257,6 +374,14 @@ func (g *routineGenerator) startInternal(ctx context.Context, txn *kv.Txn) (err
 				return err
 			}
 			w = NewRowResultWriter(&cursorHelper.container)
+		} else if addToResult {
+			// The result of the first statement is added to the result set of the
+			// enclosing set-returning routine (RETURN NEXT or RETURN QUERY).
+			rows, ok := g.expr.ResultBuffer.Container.(*rowContainerHelper)
+			if !ok {
+				return errors.AssertionFailedf("result buffer for routine %s is not initialized", g.expr.Name)
+			}
+			w = NewRowResultWriter(rows)
 		} else {
 			// The result of this statement is not needed. Use a rowResultWriter that
 			// drops all rows added to it.
@@ -430,6 +555,18 @@ func (g *routineGenerator) Next(ctx context.Context) (bool, error) {
 
 // Values is part of the eval.ValueGenerator interface.
 func (g *routineGenerator) Values() (tree.Datums, error) {
+	if g.resultBuffer != nil && g.resultBuffer.expandTuple {
+		// Expand the tuple added by RETURN NEXT or RETURN QUERY into the output
+		// columns of the routine.
+		if g.currVals[0] == tree.DNull {
+			vals := make(tree.Datums, len(g.resultBuffer.typ.TupleContents()))
+			for i := range vals {
+				vals[i] = tree.DNull
+			}
+			return vals, nil
+		}
+		return tree.MustBeDTuple(g.currVals[0]).D, nil
+	}
 	return g.currVals, nil
 }
 
@@ -439,6 +576,10 @@ func (g *routineGenerator) Close(ctx context.Context) {
 		g.rci.Close()
 	}
 	g.rch.Close(ctx)
+	if g.resultBuffer != nil {
+		g.resultBuffer.shared.Container = g.resultBuffer.prevContainer
+		g.resultBuffer.rows.Close(ctx)
+	}
 	*g = routineGenerator{}
 }
 
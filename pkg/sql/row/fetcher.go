@@ -247,6 +247,8 @@ type Fetcher struct {
 	// any family ID.
 	indexKey       []byte
 	prettyValueBuf *bytes.Buffer
+	// rowSampled is false if the current row is skipped by args.SampleRow.
+	rowSampled bool
 
 	valueColsFound int // how many needed cols we've found so far in the value
 
@@ -337,6 +339,10 @@ type FetcherInitArgs struct {
 	// row is being processed. In practice, this means that span IDs must be
 	// passed in when SpansCanOverlap is true.
 	SpansCanOverlap bool
+	// SampleRow, if set, is called with the index key of each row (not
+	// including any family ID), and only the rows for which it returns true
+	// are returned by NextRow. It is used to implement TABLESAMPLE BERNOULLI.
+	SampleRow func(indexKey roachpb.Key) bool
 }
 
 // Init sets up a Fetcher for a given table and index.
@@ -844,6 +850,7 @@ func (rf *Fetcher) processKV(
 	if rf.indexKey == nil {
 		// This is the first key for the row.
 		rf.indexKey = []byte(kv.Key[:len(kv.Key)-len(rf.keyRemainingBytes)])
+		rf.rowSampled = rf.args.SampleRow == nil || rf.args.SampleRow(rf.indexKey)
 
 		// Reset the row to nil; it will get filled in with the column
 		// values as we decode the key-value pairs for the row.
@@ -1158,6 +1165,14 @@ func (rf *Fetcher) NextRow(ctx context.Context) (row rowenc.EncDatumRow, spanID
 			return nil, 0, err
 		}
 		if rowDone {
+			if !rf.rowSampled {
+				// The row is not part of the sample, so move on to the next one.
+				rf.spanID = spanID
+				if rf.kvEnd {
+					return nil, 0, nil
+				}
+				continue
+			}
 			err := rf.finalizeRow()
 			rowSpanID := rf.spanID
 			rf.spanID = spanID
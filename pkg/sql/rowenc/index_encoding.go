@@ -698,6 +698,8 @@ func EncodeInvertedIndexTableKeys(
 		return encodeTrigramInvertedIndexTableKeys(string(*datum.(*tree.DString)), inKey, version, true /* pad */)
 	case types.TSVectorFamily:
 		return tsearch.EncodeInvertedIndexKeys(inKey, val.(*tree.DTSVector).TSVector)
+	case types.RangeFamily, types.MultirangeFamily:
+		return encodeRangeInvertedIndexTableKeys(datum, inKey)
 	}
 	return nil, errors.AssertionFailedf("trying to apply inverted index to unsupported type %s", datum.ResolvedType().SQLStringForError())
 }
@@ -725,6 +727,8 @@ func EncodeContainingInvertedIndexSpans(
 		return json.EncodeContainingInvertedIndexSpans(nil /* inKey */, val.(*tree.DJSON).JSON)
 	case types.ArrayFamily:
 		return encodeContainingArrayInvertedIndexSpans(val.(*tree.DArray), nil /* inKey */)
+	case types.RangeFamily, types.MultirangeFamily:
+		return encodeContainingRangeInvertedIndexSpans(datum, nil /* inKey */)
 	default:
 		return nil, errors.AssertionFailedf(
 			"trying to apply inverted index to unsupported type %s", datum.ResolvedType().SQLStringForError(),
@@ -756,6 +760,8 @@ func EncodeContainedInvertedIndexSpans(
 		return encodeContainedArrayInvertedIndexSpans(val.(*tree.DArray), nil /* inKey */)
 	case types.JsonFamily:
 		return json.EncodeContainedInvertedIndexSpans(nil /* inKey */, val.(*tree.DJSON).JSON)
+	case types.RangeFamily, types.MultirangeFamily:
+		return encodeContainedRangeInvertedIndexSpans(datum, nil /* inKey */)
 	default:
 		return nil, errors.AssertionFailedf(
 			"trying to apply inverted index to unsupported type %s", datum.ResolvedType().SQLStringForError(),
@@ -820,14 +826,15 @@ func EncodeExistsInvertedIndexSpans(
 
 // EncodeOverlapsInvertedIndexSpans returns the spans that must be scanned in
 // the inverted index to evaluate an overlaps (&&) predicate with the given
-// datum, which should be an Array. These spans should be used to find the
+// datum, which should be an Array, a range or a multirange. These spans
+// should be used to find the
 // objects in the index that could overlap with the given array. In other
 // words, if we have a predicate x && y, this function should use the value of
 // y to find the spans to scan in an inverted index on x.
 //
 // The spans are returned in an inverted.SpanExpression, which represents the
 // set operations that must be applied on the spans read during execution. The
-// span expression returned will be tight. See comments in the
+// span expression returned will be tight for arrays. See comments in the
 // SpanExpression definition for details.
 func EncodeOverlapsInvertedIndexSpans(
 	ctx context.Context, evalCtx *eval.Context, val tree.Datum,
@@ -839,6 +846,8 @@ func EncodeOverlapsInvertedIndexSpans(
 	switch val.ResolvedType().Family() {
 	case types.ArrayFamily:
 		return encodeOverlapsArrayInvertedIndexSpans(val.(*tree.DArray), nil /* inKey */)
+	case types.RangeFamily, types.MultirangeFamily:
+		return encodeOverlapsRangeInvertedIndexSpans(datum, nil /* inKey */)
 	default:
 		return nil, errors.AssertionFailedf(
 			"trying to apply inverted index to unsupported type %s", datum.ResolvedType().SQLStringForError(),
@@ -1006,6 +1015,178 @@ func encodeOverlapsArrayInvertedIndexSpans(
 	return invertedExpr, nil
 }
 
+// Markers used in the inverted index keys of ranges and multiranges.
+const (
+	rangeInvertedEmptyMarker    = 0
+	rangeInvertedLowerInfMarker = 1
+	rangeInvertedLowerMarker    = 2
+)
+
+// encodeRangeInvertedIndexTableKeys returns a list of inverted index keys for
+// the given range or multirange, one per range. The key of a range is the
+// encoding of its lower bound, so that ranges whose lower bound lies in a
+// given interval can be found with a single span. Empty ranges and empty
+// multiranges are encoded with a separate marker that sorts before all lower
+// bounds. The input inKey is prefixed to all returned keys.
+func encodeRangeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, err error) {
+	var ranges []*tree.DRange
+	switch t := val.(type) {
+	case *tree.DRange:
+		ranges = []*tree.DRange{t}
+	case *tree.DMultirange:
+		ranges = t.Ranges
+	}
+	if len(ranges) == 0 {
+		return [][]byte{encodeRangeInvertedIndexMarker(inKey, rangeInvertedEmptyMarker)}, nil
+	}
+	outKeys := make([][]byte, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Empty {
+			outKeys = append(outKeys, encodeRangeInvertedIndexMarker(inKey, rangeInvertedEmptyMarker))
+			continue
+		}
+		newKey, err := encodeRangeInvertedIndexLowerBound(inKey, r.Lower)
+		if err != nil {
+			return nil, err
+		}
+		outKeys = append(outKeys, newKey)
+	}
+	outKeys = unique.UniquifyByteSlices(outKeys)
+	return outKeys, nil
+}
+
+// encodeRangeInvertedIndexMarker returns a copy of inKey followed by the
+// given marker.
+func encodeRangeInvertedIndexMarker(inKey []byte, marker int64) []byte {
+	outKey := make([]byte, len(inKey))
+	copy(outKey, inKey)
+	return encoding.EncodeVarintAscending(outKey, marker)
+}
+
+// encodeRangeInvertedIndexLowerBound returns the inverted index key of a
+// non-empty range with the given lower bound, which is NULL if the bound is
+// infinite.
+func encodeRangeInvertedIndexLowerBound(inKey []byte, lower tree.Datum) ([]byte, error) {
+	if lower == tree.DNull {
+		return encodeRangeInvertedIndexMarker(inKey, rangeInvertedLowerInfMarker), nil
+	}
+	return keyside.Encode(
+		encodeRangeInvertedIndexMarker(inKey, rangeInvertedLowerMarker), lower, encoding.Ascending,
+	)
+}
+
+// encodeRangeInvertedIndexUpperLimit returns the end key of a span containing
+// the inverted index keys of all non-empty ranges whose lower bound is at most
+// the given bound, which is NULL if the bound is infinite.
+func encodeRangeInvertedIndexUpperLimit(inKey []byte, upper tree.Datum) (inverted.EncVal, error) {
+	key := encodeRangeInvertedIndexMarker(inKey, rangeInvertedLowerMarker)
+	if upper != tree.DNull {
+		var err error
+		if key, err = keyside.Encode(key, upper, encoding.Ascending); err != nil {
+			return nil, err
+		}
+	}
+	return inverted.EncVal(roachpb.Key(key).PrefixEnd()), nil
+}
+
+// rangeInvertedIndexBounds returns the bounds of the smallest range containing
+// the given range or multirange, and whether that range is empty.
+func rangeInvertedIndexBounds(val tree.Datum) (lower, upper tree.Datum, empty bool) {
+	switch t := val.(type) {
+	case *tree.DRange:
+		return t.Lower, t.Upper, t.Empty
+	case *tree.DMultirange:
+		if len(t.Ranges) == 0 {
+			return tree.DNull, tree.DNull, true
+		}
+		return t.Ranges[0].Lower, t.Ranges[len(t.Ranges)-1].Upper, false
+	}
+	return tree.DNull, tree.DNull, true
+}
+
+// encodeContainingRangeInvertedIndexSpans returns the spans that must be
+// scanned in the inverted index to evaluate a contains (@>) predicate with the
+// given range or multirange. A range can only contain a non-empty range if its
+// lower bound is at most the lower bound of the contained range. The input
+// inKey is prefixed to all returned keys.
+func encodeContainingRangeInvertedIndexSpans(
+	val tree.Datum, inKey []byte,
+) (invertedExpr inverted.Expression, err error) {
+	lower, _, empty := rangeInvertedIndexBounds(val)
+	if empty {
+		// All ranges contain the empty range. Return a SpanExpression that
+		// requires a full scan of the inverted index.
+		return inverted.ExprForSpan(inverted.MakeSingleValSpan(inKey), false /* tight */), nil
+	}
+	start := encodeRangeInvertedIndexMarker(inKey, rangeInvertedLowerInfMarker)
+	if lower == tree.DNull {
+		return inverted.ExprForSpan(inverted.MakeSingleValSpan(start), false /* tight */), nil
+	}
+	end, err := encodeRangeInvertedIndexUpperLimit(inKey, lower)
+	if err != nil {
+		return nil, err
+	}
+	return inverted.ExprForSpan(inverted.Span{Start: start, End: end}, false /* tight */), nil
+}
+
+// encodeContainedRangeInvertedIndexSpans returns the spans that must be
+// scanned in the inverted index to evaluate a contained by (<@) predicate with
+// the given range or multirange. A non-empty range can only be contained by
+// another if its lower bound lies within the other range. The input inKey is
+// prefixed to all returned keys.
+func encodeContainedRangeInvertedIndexSpans(
+	val tree.Datum, inKey []byte,
+) (invertedExpr inverted.Expression, err error) {
+	// The empty range should always be added to the spans, since it is
+	// contained by everything.
+	invertedExpr = inverted.ExprForSpan(
+		inverted.MakeSingleValSpan(encodeRangeInvertedIndexMarker(inKey, rangeInvertedEmptyMarker)),
+		false, /* tight */
+	)
+	lower, upper, empty := rangeInvertedIndexBounds(val)
+	if empty {
+		return invertedExpr, nil
+	}
+	var start []byte
+	if lower == tree.DNull {
+		start = encodeRangeInvertedIndexMarker(inKey, rangeInvertedLowerInfMarker)
+	} else if start, err = encodeRangeInvertedIndexLowerBound(inKey, lower); err != nil {
+		return nil, err
+	}
+	end, err := encodeRangeInvertedIndexUpperLimit(inKey, upper)
+	if err != nil {
+		return nil, err
+	}
+	invertedExpr = inverted.Or(
+		invertedExpr, inverted.ExprForSpan(inverted.Span{Start: start, End: end}, false /* tight */),
+	)
+	// The inverted expression produced for <@ will never be tight, since only
+	// the lower bounds of the indexed ranges are known.
+	invertedExpr.SetNotTight()
+	return invertedExpr, nil
+}
+
+// encodeOverlapsRangeInvertedIndexSpans returns the spans that must be
+// scanned in the inverted index to evaluate an overlaps (&&) predicate with
+// the given range or multirange. A range can only overlap another if it is
+// non-empty and its lower bound is at most the upper bound of the other
+// range. The input inKey is prefixed to all returned keys.
+func encodeOverlapsRangeInvertedIndexSpans(
+	val tree.Datum, inKey []byte,
+) (invertedExpr inverted.Expression, err error) {
+	_, upper, empty := rangeInvertedIndexBounds(val)
+	if empty {
+		// Nothing overlaps the empty range.
+		return &inverted.SpanExpression{Tight: true, Unique: true}, nil
+	}
+	start := encodeRangeInvertedIndexMarker(inKey, rangeInvertedLowerInfMarker)
+	end, err := encodeRangeInvertedIndexUpperLimit(inKey, upper)
+	if err != nil {
+		return nil, err
+	}
+	return inverted.ExprForSpan(inverted.Span{Start: start, End: end}, false /* tight */), nil
+}
+
 // EncodeTrigramSpans returns the spans that must be scanned to look up trigrams
 // present in the input string. If allMustMatch is true, the resultant inverted
 // expression must match every trigram in the input. Otherwise, it will match
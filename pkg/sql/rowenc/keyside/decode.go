@@ -49,6 +49,8 @@ func Decode(
 	switch valType.Family() {
 	case types.ArrayFamily:
 		return decodeArrayKey(a, valType, key, dir)
+	case types.RangeFamily, types.MultirangeFamily:
+		return decodeRangeKey(a, valType, key, dir)
 	case types.BitFamily:
 		var r bitarray.BitArray
 		if dir == encoding.Ascending {
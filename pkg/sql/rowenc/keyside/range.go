@@ -0,0 +1,182 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package keyside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// Markers used in the key encoding of ranges. They are encoded as ascending
+// varints, so the order of the markers determines the order of the ranges.
+const (
+	// rangeEmptyMarker and rangeNonEmptyMarker precede the bounds of a range.
+	// Empty ranges sort before all other ranges.
+	rangeEmptyMarker    = 0
+	rangeNonEmptyMarker = 1
+
+	// rangeLowerInfMarker, rangeBoundMarker and rangeUpperInfMarker precede
+	// each bound. An infinite lower bound sorts before all finite bounds, and
+	// an infinite upper bound sorts after them.
+	rangeLowerInfMarker = 0
+	rangeBoundMarker    = 1
+	rangeUpperInfMarker = 2
+
+	// multirangeRangeMarker precedes each range of a multirange, and
+	// multirangeTerminator follows the last one, so that a multirange sorts
+	// before any longer multirange it is a prefix of.
+	multirangeTerminator  = 0
+	multirangeRangeMarker = 1
+)
+
+// encodeRangeKey generates an ordered key encoding of a range or multirange.
+// The ranges are encoded in ascending order into a byte string, which is
+// then encoded in the given direction. This makes the encoding of a range a
+// single value, which can be skipped using encoding.PeekLength.
+//
+// The encoding of a range is [rangeEmptyMarker] if it is empty, and otherwise
+// [rangeNonEmptyMarker, lower, upper], where each bound is either an infinity
+// marker or [rangeBoundMarker, enc(value), inclusivity]. The inclusivity of a
+// bound is encoded such that [x sorts before (x, and x) sorts before x].
+func encodeRangeKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, error) {
+	var buf []byte
+	var err error
+	switch t := val.(type) {
+	case *tree.DRange:
+		if buf, err = appendRangeKey(buf, t); err != nil {
+			return nil, err
+		}
+	case *tree.DMultirange:
+		for _, r := range t.Ranges {
+			buf = encoding.EncodeVarintAscending(buf, multirangeRangeMarker)
+			if buf, err = appendRangeKey(buf, r); err != nil {
+				return nil, err
+			}
+		}
+		buf = encoding.EncodeVarintAscending(buf, multirangeTerminator)
+	default:
+		return nil, errors.AssertionFailedf("unexpected range datum %T", val)
+	}
+	if dir == encoding.Ascending {
+		return encoding.EncodeBytesAscending(b, buf), nil
+	}
+	return encoding.EncodeBytesDescending(b, buf), nil
+}
+
+func appendRangeKey(b []byte, r *tree.DRange) ([]byte, error) {
+	if r.Empty {
+		return encoding.EncodeVarintAscending(b, rangeEmptyMarker), nil
+	}
+	b = encoding.EncodeVarintAscending(b, rangeNonEmptyMarker)
+	var err error
+	if r.Lower == tree.DNull {
+		b = encoding.EncodeVarintAscending(b, rangeLowerInfMarker)
+	} else {
+		b = encoding.EncodeVarintAscending(b, rangeBoundMarker)
+		if b, err = Encode(b, r.Lower, encoding.Ascending); err != nil {
+			return nil, err
+		}
+		if r.LowerInc {
+			b = encoding.EncodeVarintAscending(b, 0)
+		} else {
+			b = encoding.EncodeVarintAscending(b, 1)
+		}
+	}
+	if r.Upper == tree.DNull {
+		b = encoding.EncodeVarintAscending(b, rangeUpperInfMarker)
+	} else {
+		b = encoding.EncodeVarintAscending(b, rangeBoundMarker)
+		if b, err = Encode(b, r.Upper, encoding.Ascending); err != nil {
+			return nil, err
+		}
+		if r.UpperInc {
+			b = encoding.EncodeVarintAscending(b, 1)
+		} else {
+			b = encoding.EncodeVarintAscending(b, 0)
+		}
+	}
+	return b, nil
+}
+
+// decodeRangeKey decodes a range or multirange key generated by
+// encodeRangeKey.
+func decodeRangeKey(
+	a *tree.DatumAlloc, t *types.T, key []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	var buf []byte
+	var err error
+	if dir == encoding.Ascending {
+		key, buf, err = encoding.DecodeBytesAscending(key, nil)
+	} else {
+		key, buf, err = encoding.DecodeBytesDescending(key, nil)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.Family() == types.RangeFamily {
+		r, _, err := decodeRangeKeyContents(a, t, buf)
+		return r, key, err
+	}
+	var ranges []*tree.DRange
+	for {
+		var marker int64
+		if buf, marker, err = encoding.DecodeVarintAscending(buf); err != nil {
+			return nil, nil, err
+		}
+		if marker == multirangeTerminator {
+			break
+		}
+		var r *tree.DRange
+		if r, buf, err = decodeRangeKeyContents(a, t.RangeContents(), buf); err != nil {
+			return nil, nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return tree.NewDMultirange(t, ranges), key, nil
+}
+
+func decodeRangeKeyContents(
+	a *tree.DatumAlloc, t *types.T, buf []byte,
+) (*tree.DRange, []byte, error) {
+	var marker int64
+	var err error
+	if buf, marker, err = encoding.DecodeVarintAscending(buf); err != nil {
+		return nil, nil, err
+	}
+	if marker == rangeEmptyMarker {
+		return tree.NewDEmptyRange(t), buf, nil
+	}
+	var bounds [2]tree.Datum
+	var inclusive [2]bool
+	for i := range bounds {
+		if buf, marker, err = encoding.DecodeVarintAscending(buf); err != nil {
+			return nil, nil, err
+		}
+		if marker != rangeBoundMarker {
+			bounds[i] = tree.DNull
+			continue
+		}
+		if bounds[i], buf, err = Decode(a, t.RangeContents(), buf, encoding.Ascending); err != nil {
+			return nil, nil, err
+		}
+		var inc int64
+		if buf, inc, err = encoding.DecodeVarintAscending(buf); err != nil {
+			return nil, nil, err
+		}
+		// The inclusivity of the lower bound is encoded as 0, and that of the
+		// upper bound as 1.
+		inclusive[i] = inc == int64(i)
+	}
+	r, err := tree.NewDRange(t, bounds[0], bounds[1], inclusive[0], inclusive[1])
+	return r, buf, err
+}
@@ -223,6 +223,13 @@ func DecodeUntaggedDatum(
 			return nil, b, err
 		}
 		return tree.NewDTSVector(v), b, nil
+	case types.JsonpathFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := tree.ParseDJsonpath(string(data))
+		return d, b, err
 	case types.OidFamily:
 		// TODO: This possibly should decode to uint32 (with corresponding changes
 		// to encoding) to ensure that the value fits in a DOid without any loss of
@@ -240,6 +247,13 @@ func DecodeUntaggedDatum(
 		return decodeArray(a, t, b)
 	case types.TupleFamily:
 		return decodeTuple(a, t, buf)
+	case types.RangeFamily, types.MultirangeFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, _, err := decodeRange(a, t, data)
+		return d, b, err
 	case types.EnumFamily:
 		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
 		if err != nil {
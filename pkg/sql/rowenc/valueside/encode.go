@@ -96,6 +96,8 @@ func Encode(appendTo []byte, colID ColumnIDDelta, val tree.Datum, scratch []byte
 			return nil, err
 		}
 		return encoding.EncodeTSVectorValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DJsonpath:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Path.String())), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -104,6 +106,12 @@ func Encode(appendTo []byte, colID ColumnIDDelta, val tree.Datum, scratch []byte
 		return encoding.EncodeArrayValue(appendTo, uint32(colID), a), nil
 	case *tree.DTuple:
 		return encodeTuple(t, appendTo, uint32(colID), scratch)
+	case *tree.DRange, *tree.DMultirange:
+		b, err := encodeUntaggedRange(t, nil /* appendTo */, scratch)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), b), nil
 	case *tree.DCollatedString:
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.UnsafeContentBytes()), nil
 	case *tree.DOid:
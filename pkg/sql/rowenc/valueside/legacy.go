@@ -159,6 +159,11 @@ func MarshalLegacy(colType *types.T, val tree.Datum) (roachpb.Value, error) {
 			r.SetBytes(data)
 			return r, nil
 		}
+	case types.JsonpathFamily:
+		if v, ok := val.(*tree.DJsonpath); ok {
+			r.SetBytes([]byte(v.Path.String()))
+			return r, nil
+		}
 	case types.TSVectorFamily:
 		if v, ok := val.(*tree.DTSVector); ok {
 			data, err := tsearch.EncodeTSVector(nil, v.TSVector)
@@ -189,6 +194,16 @@ func MarshalLegacy(colType *types.T, val tree.Datum) (roachpb.Value, error) {
 			r.SetBytes(b)
 			return r, nil
 		}
+	case types.RangeFamily, types.MultirangeFamily:
+		switch v := val.(type) {
+		case *tree.DRange, *tree.DMultirange:
+			b, err := encodeUntaggedRange(v, nil /* appendTo */, nil /* scratch */)
+			if err != nil {
+				return r, err
+			}
+			r.SetBytes(b)
+			return r, nil
+		}
 	case types.CollatedStringFamily:
 		if v, ok := val.(*tree.DCollatedString); ok {
 			if lex.LocaleNamesAreEqual(v.Locale, colType.Locale()) {
@@ -392,6 +407,13 @@ func UnmarshalLegacy(a *tree.DatumAlloc, typ *types.T, value roachpb.Value) (tre
 		}
 		datum, _, err := decodeTuple(a, typ, v)
 		return datum, err
+	case types.RangeFamily, types.MultirangeFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		datum, _, err := decodeRange(a, typ, v)
+		return datum, err
 	case types.JsonFamily:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -412,6 +434,12 @@ func UnmarshalLegacy(a *tree.DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return tree.NewDTSQuery(vec), nil
+	case types.JsonpathFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.ParseDJsonpath(string(v))
 	case types.TSVectorFamily:
 		v, err := value.GetBytes()
 		if err != nil {
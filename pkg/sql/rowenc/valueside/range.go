@@ -0,0 +1,125 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package valueside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// Flags describing a range in its value encoding. They match the flags of the
+// Postgres binary format of ranges.
+const (
+	rangeEmpty    = 0x01
+	rangeLowerInc = 0x02
+	rangeUpperInc = 0x04
+	rangeLowerInf = 0x08
+	rangeUpperInf = 0x10
+)
+
+// encodeUntaggedRange produces the value encoding for a range or multirange
+// without a value tag. A range is encoded as its flags, followed by the
+// value encoding of its finite bounds. A multirange is encoded as the number
+// of ranges, followed by the encoding of each range.
+func encodeUntaggedRange(val tree.Datum, appendTo []byte, scratch []byte) ([]byte, error) {
+	switch t := val.(type) {
+	case *tree.DRange:
+		return encodeUntaggedRangeContents(t, appendTo, scratch)
+	case *tree.DMultirange:
+		appendTo = encoding.EncodeNonsortingUvarint(appendTo, uint64(len(t.Ranges)))
+		var err error
+		for _, r := range t.Ranges {
+			if appendTo, err = encodeUntaggedRangeContents(r, appendTo, scratch); err != nil {
+				return nil, err
+			}
+		}
+		return appendTo, nil
+	}
+	return nil, errors.AssertionFailedf("unexpected range datum %T", val)
+}
+
+func encodeUntaggedRangeContents(r *tree.DRange, appendTo []byte, scratch []byte) ([]byte, error) {
+	var flags uint64
+	switch {
+	case r.Empty:
+		flags = rangeEmpty
+	default:
+		if r.LowerInc {
+			flags |= rangeLowerInc
+		}
+		if r.UpperInc {
+			flags |= rangeUpperInc
+		}
+		if r.Lower == tree.DNull {
+			flags |= rangeLowerInf
+		}
+		if r.Upper == tree.DNull {
+			flags |= rangeUpperInf
+		}
+	}
+	appendTo = encoding.EncodeNonsortingUvarint(appendTo, flags)
+	var err error
+	for _, bound := range []tree.Datum{r.Lower, r.Upper} {
+		if bound == tree.DNull {
+			continue
+		}
+		if appendTo, err = Encode(appendTo, NoColumnID, bound, scratch); err != nil {
+			return nil, err
+		}
+	}
+	return appendTo, nil
+}
+
+// decodeRange decodes a range or multirange from its value encoding. It is
+// the counterpart of encodeUntaggedRange.
+func decodeRange(a *tree.DatumAlloc, t *types.T, b []byte) (tree.Datum, []byte, error) {
+	if t.Family() == types.RangeFamily {
+		return decodeRangeContents(a, t, b)
+	}
+	b, _, n, err := encoding.DecodeNonsortingUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	ranges := make([]*tree.DRange, n)
+	for i := range ranges {
+		var d tree.Datum
+		if d, b, err = decodeRangeContents(a, t.RangeContents(), b); err != nil {
+			return nil, nil, err
+		}
+		ranges[i] = d.(*tree.DRange)
+	}
+	return tree.NewDMultirange(t, ranges), b, nil
+}
+
+func decodeRangeContents(a *tree.DatumAlloc, t *types.T, b []byte) (tree.Datum, []byte, error) {
+	b, _, flags, err := encoding.DecodeNonsortingUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if flags&rangeEmpty != 0 {
+		return tree.NewDEmptyRange(t), b, nil
+	}
+	bounds := [2]tree.Datum{tree.DNull, tree.DNull}
+	for i, inf := range []uint64{rangeLowerInf, rangeUpperInf} {
+		if flags&inf != 0 {
+			continue
+		}
+		if bounds[i], b, err = Decode(a, t.RangeContents(), b); err != nil {
+			return nil, nil, err
+		}
+	}
+	r, err := tree.NewDRange(
+		t, bounds[0], bounds[1], flags&rangeLowerInc != 0, flags&rangeUpperInc != 0,
+	)
+	return r, b, err
+}
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra/execopnode"
@@ -145,6 +146,10 @@ func newTableReader(
 		return nil, err
 	}
 
+	var sampleRow func(roachpb.Key) bool
+	if spec.Sample != nil {
+		sampleRow = spec.Sample.SampleKey
+	}
 	var fetcher row.Fetcher
 	if err := fetcher.Init(
 		ctx,
@@ -160,6 +165,7 @@ func newTableReader(
 			Spec:                       &spec.FetchSpec,
 			TraceKV:                    flowCtx.TraceKV,
 			ForceProductionKVBatchSize: flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
+			SampleRow:                  sampleRow,
 		},
 	); err != nil {
 		return nil, err
@@ -405,6 +405,10 @@ func (s *SQLScanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.AT_AT)
 			return
+		case '?': // @?
+			s.pos++
+			lval.SetID(lexbase.JSON_PATH_EXISTS)
+			return
 		}
 		return
 
@@ -419,6 +423,13 @@ func (s *SQLScanner) Scan(lval ScanSymType) {
 
 	case '-':
 		switch s.peek() {
+		case '|': // -|
+			if s.peekN(1) == '-' {
+				// -|-
+				s.pos += 2
+				lval.SetID(lexbase.RANGE_ADJACENT)
+				return
+			}
 		case '>': // ->
 			if s.peekN(1) == '>' {
 				// ->>
@@ -985,6 +985,9 @@ func (b *builderState) ResolveUserDefinedTypeType(
 	case descpb.TypeDescriptor_COMPOSITE:
 		b.ensureDescriptor(typ.GetID())
 		b.mustOwn(typ.GetID())
+	case descpb.TypeDescriptor_DOMAIN:
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"domain types are not supported by the declarative schema changer"))
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		// Implicit record types are not directly modifiable.
 		panic(pgerror.Newf(pgcode.DependentObjectsStillExist,
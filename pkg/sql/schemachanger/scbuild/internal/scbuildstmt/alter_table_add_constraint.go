@@ -45,11 +45,19 @@ func alterTableAddConstraint(
 		if d.PrimaryKey {
 			alterTableAddPrimaryKey(b, tn, tbl, t)
 		} else if d.WithoutIndex {
+			// Deferrable constraints are only supported by the legacy schema
+			// changer.
+			if d.Deferrability.IsDeferrable() {
+				panic(scerrors.NotImplementedError(t))
+			}
 			alterTableAddUniqueWithoutIndex(b, tn, tbl, t)
 		} else {
 			if t.ValidationBehavior == tree.ValidationSkip {
 				panic(sqlerrors.NewUnsupportedUnvalidatedConstraintError(catconstants.ConstraintTypeUnique))
 			}
+			if d.Deferrability.IsDeferrable() {
+				panic(sqlerrors.NewDeferrableUniqueIndexError())
+			}
 			CreateIndex(b, &tree.CreateIndex{
 				Name:        d.Name,
 				Table:       *tn,
@@ -62,7 +70,13 @@ func alterTableAddConstraint(
 	case *tree.CheckConstraintTableDef:
 		alterTableAddCheck(b, tn, tbl, t)
 	case *tree.ForeignKeyConstraintTableDef:
+		if d.Deferrability.IsDeferrable() {
+			panic(scerrors.NotImplementedError(t))
+		}
 		alterTableAddForeignKey(b, tn, tbl, t)
+	case *tree.ExcludeConstraintTableDef:
+		// Exclusion constraints are only supported by the legacy schema changer.
+		panic(scerrors.NotImplementedError(t))
 	}
 }
 
@@ -377,6 +377,15 @@ func walkDropColumnDependencies(b BuildCtx, col *scpb.Column, fn func(e scpb.Ele
 					catalog.MakeTableColSet(elt.ReferencedColumnIDs...).Contains(col.ColumnID) {
 					fn(e)
 				}
+			case *scpb.Trigger:
+				colNameElem := mustRetrieveColumnNameElem(b, col.TableID, col.ColumnID)
+				ns := mustRetrieveNamespaceElem(b, col.TableID)
+				panic(errors.WithHint(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"cannot drop column %q because trigger %q on table %q depends on it",
+						colNameElem.Name, elt.Name, ns.Name),
+					"drop the trigger first",
+				))
 			default:
 				panic(errors.AssertionFailedf("unknown column-dependent element type %T", elt))
 			}
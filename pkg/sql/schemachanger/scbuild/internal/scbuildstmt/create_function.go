@@ -32,6 +32,14 @@ func CreateFunction(b BuildCtx, n *tree.CreateRoutine) {
 	if n.Replace {
 		panic(scerrors.NotImplementedError(n))
 	}
+	for _, option := range n.Options {
+		switch option.(type) {
+		case tree.RoutineSecurity, tree.RoutineSetConfig:
+			// The security mode and configuration parameters are not yet
+			// represented as elements.
+			panic(scerrors.NotImplementedErrorf(n, "routine with security or configuration options"))
+		}
+	}
 	b.IncrementSchemaChangeCreateCounter("function")
 
 	dbElts, scElts := b.ResolveTargetObject(n.Name.ToUnresolvedObjectName(), privilege.CREATE)
@@ -362,7 +362,18 @@ func processColNodeType(
 			}
 			invertedKind = catpb.InvertedIndexColumnKind_TRIGRAM
 			b.IncrementSchemaChangeIndexCounter("trigram_inverted")
-
+		case types.RangeFamily:
+			switch columnNode.OpClass {
+			case "range_ops", "":
+			default:
+				panic(newUndefinedOpclassError(columnNode.OpClass))
+			}
+		case types.MultirangeFamily:
+			switch columnNode.OpClass {
+			case "multirange_ops", "":
+			default:
+				panic(newUndefinedOpclassError(columnNode.OpClass))
+			}
 		}
 		relationElts := b.QueryByID(indexSpec.secondary.TableID)
 		scpb.ForEachIndexColumn(relationElts, func(current scpb.Status, target scpb.TargetStatus, e *scpb.IndexColumn) {
@@ -0,0 +1,227 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuildstmt
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/errors"
+)
+
+// Reindex implements REINDEX.
+//
+// Each index is rebuilt by backfilling a new index with the same definition
+// as the existing one and swapping it in once it is valid, in the same way
+// that ALTER PRIMARY KEY recreates the secondary indexes of a table. The new
+// index keeps the name, columns, partitioning and comments of the old index,
+// but gets a new index ID.
+func Reindex(b BuildCtx, n *tree.Reindex) {
+	if n.Concurrently {
+		b.EvalCtx().ClientNoticeSender.BufferClientNotice(b,
+			pgnotice.Newf("CONCURRENTLY is not required as all indexes are rebuilt concurrently"))
+	}
+	switch n.Kind {
+	case tree.ReindexTable:
+		elts := b.ResolveTable(n.Table, ResolveParams{
+			RequiredPrivilege: privilege.CREATE,
+		})
+		_, _, tbl := scpb.FindTable(elts)
+		reindexTable(b, n, tbl)
+	case tree.ReindexIndex:
+		elts := b.ResolveIndexByName(n.Index, ResolveParams{
+			RequiredPrivilege: privilege.CREATE,
+		})
+		var tableID catid.DescID
+		var indexID catid.IndexID
+		if _, _, pie := scpb.FindPrimaryIndex(elts); pie != nil {
+			tableID, indexID = pie.TableID, pie.IndexID
+		} else if _, _, sie := scpb.FindSecondaryIndex(elts); sie != nil {
+			tableID, indexID = sie.TableID, sie.IndexID
+		} else {
+			panic(errors.AssertionFailedf("programming error: cannot find index element"))
+		}
+		tableElts := b.QueryByID(tableID)
+		_, _, relation := scpb.FindTable(tableElts)
+		if relation == nil {
+			_, _, ns := scpb.FindNamespace(tableElts)
+			panic(pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", ns.Name))
+		}
+		checkCanReindexTable(b, n, relation)
+		if indexID == getPrimaryIndexID(b, tableID) {
+			reindexPrimaryIndex(b, relation)
+		} else {
+			reindexSecondaryIndex(b, relation, indexID, getPrimaryIndexID(b, tableID))
+		}
+		b.IncrementSchemaChangeAlterCounter("index", "reindex")
+	case tree.ReindexDatabase:
+		elts := b.ResolveDatabase(n.Database, ResolveParams{
+			RequiredPrivilege: privilege.CREATE,
+		})
+		_, _, db := scpb.FindDatabase(elts)
+		if db.DatabaseID == keys.SystemDatabaseID {
+			panic(pgerror.New(pgcode.FeatureNotSupported, "system tables cannot be reindexed"))
+		}
+		scpb.ForEachSchema(b.BackReferences(db.DatabaseID), func(
+			_ scpb.Status, target scpb.TargetStatus, sc *scpb.Schema,
+		) {
+			if target != scpb.ToPublic {
+				return
+			}
+			scpb.ForEachTable(b.BackReferences(sc.SchemaID), func(
+				_ scpb.Status, target scpb.TargetStatus, tbl *scpb.Table,
+			) {
+				if target != scpb.ToPublic || tbl.IsTemporary {
+					return
+				}
+				_, _, ns := scpb.FindNamespace(b.QueryByID(tbl.TableID))
+				tn := tree.MakeTableNameFromPrefix(b.NamePrefix(tbl), tree.Name(ns.Name))
+				elts := b.ResolveTable(tn.ToUnresolvedObjectName(), ResolveParams{
+					RequiredPrivilege: privilege.CREATE,
+				})
+				_, _, tbl = scpb.FindTable(elts)
+				reindexTable(b, n, tbl)
+				b.IncrementSubWorkID()
+			})
+		})
+	default:
+		panic(errors.AssertionFailedf("unknown REINDEX kind %d", n.Kind))
+	}
+}
+
+// reindexTable rebuilds the primary index and all the secondary indexes of a
+// table.
+func reindexTable(b BuildCtx, n *tree.Reindex, tbl *scpb.Table) {
+	checkCanReindexTable(b, n, tbl)
+	newPrimaryIndex := reindexPrimaryIndex(b, tbl)
+	// Rebuild the secondary indexes from the new primary index, like
+	// recreateAllSecondaryIndexes does for ALTER PRIMARY KEY.
+	publicTableElts := b.QueryByID(tbl.TableID).Filter(publicTargetFilter)
+	scpb.ForEachSecondaryIndex(publicTableElts, func(
+		current scpb.Status, _ scpb.TargetStatus, idx *scpb.SecondaryIndex,
+	) {
+		if current == scpb.Status_PUBLIC {
+			reindexSecondaryIndex(b, tbl, idx.IndexID, newPrimaryIndex.IndexID)
+		}
+	})
+	b.IncrementSchemaChangeAlterCounter("table", "reindex")
+}
+
+// checkCanReindexTable panics if the indexes of the table cannot be rebuilt.
+func checkCanReindexTable(b BuildCtx, n *tree.Reindex, tbl *scpb.Table) {
+	tableElts := b.QueryByID(tbl.TableID)
+	_, _, ns := scpb.FindNamespace(tableElts)
+	if descpb.IsVirtualTable(tbl.TableID) {
+		panic(pgerror.Newf(pgcode.WrongObjectType, "%q is a virtual table", ns.Name))
+	}
+	if tbl.TableID < keys.MaxReservedDescID {
+		panic(pgerror.New(pgcode.FeatureNotSupported, "system tables cannot be reindexed"))
+	}
+	panicIfSchemaIsLocked(tableElts)
+	// Indexes which are being added or removed by an earlier statement in the
+	// same transaction cannot be rebuilt.
+	tableElts.Filter(notReachedTargetYetFilter).ForEach(func(
+		_ scpb.Status, _ scpb.TargetStatus, e scpb.Element,
+	) {
+		switch e.(type) {
+		case *scpb.PrimaryIndex, *scpb.SecondaryIndex, *scpb.TemporaryIndex:
+			panic(pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cannot reindex table %q while its indexes are being changed", ns.Name))
+		}
+	})
+	// The zone configurations of the indexes are keyed by index ID and would
+	// not apply to the rebuilt indexes.
+	fallBackIfSubZoneConfigExists(b, n, tbl.TableID)
+}
+
+// reindexPrimaryIndex swaps the primary index of the table with a new one
+// with the same definition, and returns the new primary index.
+func reindexPrimaryIndex(b BuildCtx, tbl *scpb.Table) *scpb.PrimaryIndex {
+	out := makeIndexSpec(b, tbl.TableID, getPrimaryIndexID(b, tbl.TableID))
+	checkNoDependentsOnIndex(b, tbl.TableID, out)
+	in, temp := makeSwapIndexSpec(b, out, out.indexID(), reindexColumns(out), false /* inUseTentativeIDs */)
+	out.apply(b.Drop)
+	in.apply(b.Add)
+	temp.apply(b.AddTransient)
+	b.LogEventForExistingTarget(in.primary)
+	return in.primary
+}
+
+// reindexSecondaryIndex swaps a secondary index of the table with a new one
+// with the same definition, backfilled from the given primary index.
+func reindexSecondaryIndex(
+	b BuildCtx, tbl *scpb.Table, indexID catid.IndexID, sourceIndexID catid.IndexID,
+) {
+	out := makeIndexSpec(b, tbl.TableID, indexID)
+	checkNoDependentsOnIndex(b, tbl.TableID, out)
+	in, temp := makeSwapIndexSpec(b, out, sourceIndexID, reindexColumns(out), false /* inUseTentativeIDs */)
+	in.secondary.RecreateSourceIndexID = out.indexID()
+	out.apply(b.Drop)
+	in.apply(b.Add)
+	temp.apply(b.AddTransient)
+	b.LogEventForExistingTarget(in.secondary)
+}
+
+// reindexColumns returns the columns of a rebuilt index, which are the same as
+// the columns of the index it replaces.
+func reindexColumns(out indexSpec) []indexColumnSpec {
+	ret := make([]indexColumnSpec, len(out.columns))
+	for i, ic := range out.columns {
+		ret[i] = makeIndexColumnSpec(ic)
+	}
+	return ret
+}
+
+// checkNoDependentsOnIndex panics if a view or a routine references the index
+// explicitly, since those references are by index ID.
+func checkNoDependentsOnIndex(b BuildCtx, tableID catid.DescID, idx indexSpec) {
+	var name string
+	if idx.name != nil {
+		name = idx.name.Name
+	}
+	backRefs := b.BackReferences(tableID)
+	scpb.ForEachView(backRefs, func(_ scpb.Status, _ scpb.TargetStatus, ve *scpb.View) {
+		for _, ref := range ve.ForwardReferences {
+			if ref.ToID == tableID && ref.IndexID == idx.indexID() {
+				_, _, ns := scpb.FindNamespace(b.QueryByID(ve.ViewID))
+				panic(sqlerrors.NewDependentBlocksOpError("reindex", "index", name, "view", ns.Name))
+			}
+		}
+	})
+	scpb.ForEachFunctionBody(backRefs, func(_ scpb.Status, _ scpb.TargetStatus, fb *scpb.FunctionBody) {
+		for _, ref := range fb.UsesTables {
+			if ref.TableID == tableID && ref.IndexID == idx.indexID() {
+				_, _, fnName := scpb.FindFunctionName(b.QueryByID(fb.FunctionID))
+				panic(sqlerrors.NewDependentBlocksOpError("reindex", "index", name, "function", fnName.Name))
+			}
+		}
+	})
+}
+
+// getPrimaryIndexID returns the ID of the current primary index of the table.
+func getPrimaryIndexID(b BuildCtx, tableID catid.DescID) (ret catid.IndexID) {
+	scpb.ForEachPrimaryIndex(b.QueryByID(tableID).Filter(publicTargetFilter), func(
+		_ scpb.Status, _ scpb.TargetStatus, e *scpb.PrimaryIndex,
+	) {
+		ret = e.IndexID
+	})
+	if ret == 0 {
+		panic(errors.AssertionFailedf("programming error: table %d has no primary index", tableID))
+	}
+	return ret
+}
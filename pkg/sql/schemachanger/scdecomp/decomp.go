@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -217,6 +218,9 @@ func (w *walkCtx) walkType(typ catalog.TypeDescriptor) {
 				Name:            comp.GetElementLabel(i),
 			})
 		}
+	} else if typ.AsDomainTypeDescriptor() != nil {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"domain types are not supported by the declarative schema changer"))
 	} else {
 		panic(errors.AssertionFailedf("unsupported type kind %q", typ.GetKind()))
 	}
@@ -396,6 +400,9 @@ func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
 	for _, c := range tbl.OutboundForeignKeys() {
 		w.walkForeignKeyConstraint(tbl, c)
 	}
+	for i := range tbl.GetTriggers() {
+		w.walkTrigger(tbl, &tbl.GetTriggers()[i])
+	}
 
 	_ = tbl.ForeachDependedOnBy(func(dep *descpb.TableDescriptor_Reference) error {
 		w.backRefs.Add(dep.ID)
@@ -684,6 +691,16 @@ func (w *walkCtx) walkIndex(tbl catalog.TableDescriptor, idx catalog.Index) {
 func (w *walkCtx) walkUniqueWithoutIndexConstraint(
 	tbl catalog.TableDescriptor, c catalog.UniqueWithoutIndexConstraint,
 ) {
+	// Exclusion constraints are only supported by the legacy schema changer.
+	if c.IsExclusion() {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"relation %q (%d) has exclusion constraint %q", tbl.GetName(), tbl.GetID(), c.GetName()))
+	}
+	// Deferrable constraints are only supported by the legacy schema changer.
+	if c.Deferrability() != semenumpb.ConstraintDeferrability_NOT_DEFERRABLE {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"relation %q (%d) has deferrable constraint %q", tbl.GetName(), tbl.GetID(), c.GetName()))
+	}
 	var expr *scpb.Expression
 	var err error
 	if c.IsPartial() {
@@ -763,6 +780,11 @@ func (w *walkCtx) walkCheckConstraint(tbl catalog.TableDescriptor, c catalog.Che
 func (w *walkCtx) walkForeignKeyConstraint(
 	tbl catalog.TableDescriptor, c catalog.ForeignKeyConstraint,
 ) {
+	// Deferrable constraints are only supported by the legacy schema changer.
+	if c.Deferrability() != semenumpb.ConstraintDeferrability_NOT_DEFERRABLE {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"relation %q (%d) has deferrable constraint %q", tbl.GetName(), tbl.GetID(), c.GetName()))
+	}
 	if c.IsConstraintUnvalidated() && w.clusterVersion.IsActive(clusterversion.V23_1) {
 		w.ev(scpb.Status_PUBLIC, &scpb.ForeignKeyConstraintUnvalidated{
 			TableID:                 tbl.GetID(),
@@ -800,6 +822,36 @@ func (w *walkCtx) walkForeignKeyConstraint(
 	}
 }
 
+func (w *walkCtx) walkTrigger(tbl catalog.TableDescriptor, t *descpb.TableDescriptor_Trigger) {
+	refColIDs, err := schemaexpr.TriggerWhenExprColumnIDs(tbl, t.WhenExpr)
+	if err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "trigger %q in table %q (%d)",
+			t.Name, tbl.GetName(), tbl.GetID()))
+	}
+	trigger := &scpb.Trigger{
+		TableID:    tbl.GetID(),
+		TriggerID:  t.ID,
+		Name:       t.Name,
+		ActionTime: scpb.Trigger_ActionTime(t.ActionTime),
+		Events:     make([]scpb.Trigger_Event, len(t.Events)),
+		ForEachRow: t.ForEachRow,
+		WhenExpr:   t.WhenExpr,
+		FuncID:     t.FuncID,
+		FuncArgs:   t.FuncArgs,
+	}
+	for i, ev := range t.Events {
+		trigger.Events[i] = scpb.Trigger_Event{
+			Type:      scpb.Trigger_EventType(ev.Type),
+			ColumnIDs: ev.ColumnIDs,
+		}
+		for _, colID := range ev.ColumnIDs {
+			refColIDs.Add(colID)
+		}
+	}
+	trigger.ReferencedColumnIDs = refColIDs.Ordered()
+	w.ev(scpb.Status_PUBLIC, trigger)
+}
+
 func (w *walkCtx) walkFunction(fnDesc catalog.FunctionDescriptor) {
 	typeT := newTypeT(fnDesc.GetReturnType().Type)
 	fn := &scpb.Function{
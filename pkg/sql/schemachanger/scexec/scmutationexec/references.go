@@ -632,6 +632,7 @@ func (i *immediateVisitor) SetObjectParentID(ctx context.Context, op scop.SetObj
 			ReturnType:  t.GetReturnType().Type,
 			ReturnSet:   t.GetReturnType().ReturnSet,
 			IsProcedure: t.IsProcedure(),
+			IsVariadic:  t.IsVariadic(),
 		}
 		for _, p := range t.Params {
 			if tree.IsParamIncludedIntoSignature(funcdesc.ToTreeRoutineParamClass(p.Class), ol.IsProcedure) {
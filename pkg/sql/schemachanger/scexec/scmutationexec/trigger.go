@@ -0,0 +1,84 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scmutationexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/errors"
+)
+
+func (i *immediateVisitor) AddTrigger(ctx context.Context, op scop.AddTrigger) error {
+	tbl, err := i.checkOutTable(ctx, op.Trigger.TableID)
+	if err != nil {
+		return err
+	}
+	trigger := descpb.TableDescriptor_Trigger{
+		ID:         op.Trigger.TriggerID,
+		Name:       op.Trigger.Name,
+		ActionTime: descpb.TableDescriptor_Trigger_ActionTime(op.Trigger.ActionTime),
+		Events:     make([]descpb.TableDescriptor_Trigger_Event, len(op.Trigger.Events)),
+		ForEachRow: op.Trigger.ForEachRow,
+		WhenExpr:   op.Trigger.WhenExpr,
+		FuncID:     op.Trigger.FuncID,
+		FuncArgs:   op.Trigger.FuncArgs,
+	}
+	for j, ev := range op.Trigger.Events {
+		trigger.Events[j] = descpb.TableDescriptor_Trigger_Event{
+			Type:      descpb.TableDescriptor_Trigger_EventType(ev.Type),
+			ColumnIDs: ev.ColumnIDs,
+		}
+	}
+	tbl.Triggers = append(tbl.Triggers, trigger)
+	if tbl.NextTriggerID <= trigger.ID {
+		tbl.NextTriggerID = trigger.ID + 1
+	}
+	return nil
+}
+
+func (i *immediateVisitor) RemoveTrigger(ctx context.Context, op scop.RemoveTrigger) error {
+	tbl, err := i.checkOutTable(ctx, op.TableID)
+	if err != nil || tbl.Dropped() {
+		return err
+	}
+	for idx := range tbl.Triggers {
+		if tbl.Triggers[idx].ID == op.TriggerID {
+			tbl.Triggers = append(tbl.Triggers[:idx], tbl.Triggers[idx+1:]...)
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("failed to find trigger %d in table %q (%d)",
+		op.TriggerID, tbl.GetName(), tbl.GetID())
+}
+
+func (i *immediateVisitor) AddTriggerBackReferenceInFunction(
+	ctx context.Context, op scop.AddTriggerBackReferenceInFunction,
+) error {
+	fnDesc, err := i.checkOutFunction(ctx, op.FunctionID)
+	if err != nil {
+		return err
+	}
+	fnDesc.AddTriggerReference(op.BackReferencedTableID, op.BackReferencedTriggerID)
+	return nil
+}
+
+func (i *immediateVisitor) RemoveTriggerBackReferenceInFunction(
+	ctx context.Context, op scop.RemoveTriggerBackReferenceInFunction,
+) error {
+	fnDesc, err := i.checkOutFunction(ctx, op.FunctionID)
+	if err != nil || fnDesc.Dropped() {
+		return err
+	}
+	fnDesc.RemoveTriggerReference(op.BackReferencedTableID, op.BackReferencedTriggerID)
+	return nil
+}
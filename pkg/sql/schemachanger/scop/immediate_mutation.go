@@ -864,3 +864,34 @@ type CreateDatabaseDescriptor struct {
 	immediateMutationOp
 	DatabaseID descpb.ID
 }
+
+// AddTrigger adds a trigger to a table.
+type AddTrigger struct {
+	immediateMutationOp
+	Trigger scpb.Trigger
+}
+
+// RemoveTrigger removes a trigger from a table.
+type RemoveTrigger struct {
+	immediateMutationOp
+	TableID   descpb.ID
+	TriggerID descpb.TriggerID
+}
+
+// AddTriggerBackReferenceInFunction adds a back-reference to a trigger in the
+// function executed by the trigger.
+type AddTriggerBackReferenceInFunction struct {
+	immediateMutationOp
+	FunctionID              descpb.ID
+	BackReferencedTableID   descpb.ID
+	BackReferencedTriggerID descpb.TriggerID
+}
+
+// RemoveTriggerBackReferenceInFunction removes a back-reference to a trigger
+// from the function executed by the trigger.
+type RemoveTriggerBackReferenceInFunction struct {
+	immediateMutationOp
+	FunctionID              descpb.ID
+	BackReferencedTableID   descpb.ID
+	BackReferencedTriggerID descpb.TriggerID
+}
@@ -131,6 +131,10 @@ type ImmediateMutationVisitor interface {
 	SetSequenceOptions(context.Context, SetSequenceOptions) error
 	InitSequence(context.Context, InitSequence) error
 	CreateDatabaseDescriptor(context.Context, CreateDatabaseDescriptor) error
+	AddTrigger(context.Context, AddTrigger) error
+	RemoveTrigger(context.Context, RemoveTrigger) error
+	AddTriggerBackReferenceInFunction(context.Context, AddTriggerBackReferenceInFunction) error
+	RemoveTriggerBackReferenceInFunction(context.Context, RemoveTriggerBackReferenceInFunction) error
 }
 
 // Visit is part of the ImmediateMutationOp interface.
@@ -677,3 +681,23 @@ func (op InitSequence) Visit(ctx context.Context, v ImmediateMutationVisitor) er
 func (op CreateDatabaseDescriptor) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
 	return v.CreateDatabaseDescriptor(ctx, op)
 }
+
+// Visit is part of the ImmediateMutationOp interface.
+func (op AddTrigger) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.AddTrigger(ctx, op)
+}
+
+// Visit is part of the ImmediateMutationOp interface.
+func (op RemoveTrigger) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.RemoveTrigger(ctx, op)
+}
+
+// Visit is part of the ImmediateMutationOp interface.
+func (op AddTriggerBackReferenceInFunction) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.AddTriggerBackReferenceInFunction(ctx, op)
+}
+
+// Visit is part of the ImmediateMutationOp interface.
+func (op RemoveTriggerBackReferenceInFunction) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.RemoveTriggerBackReferenceInFunction(ctx, op)
+}
@@ -2309,6 +2309,43 @@ func (c *ElementCollection[E]) FilterTemporaryIndex() *ElementCollection[*Tempor
 	return (*ElementCollection[*TemporaryIndex])(ret)
 }
 
+func (e Trigger) element() {}
+
+// Element implements ElementGetter.
+func (e * ElementProto_Trigger) Element() Element {
+	return e.Trigger
+}
+
+// ForEachTrigger iterates over elements of type Trigger.
+// Deprecated
+func ForEachTrigger(
+	c *ElementCollection[Element], fn func(current Status, target TargetStatus, e *Trigger),
+) {
+  c.FilterTrigger().ForEach(fn)
+}
+
+// FindTrigger finds the first element of type Trigger.
+// Deprecated
+func FindTrigger(
+	c *ElementCollection[Element],
+) (current Status, target TargetStatus, element *Trigger) {
+	if tc := c.FilterTrigger(); !tc.IsEmpty() {
+		var e Element
+		current, target, e = tc.Get(0)
+		element = e.(*Trigger)
+	}
+	return current, target, element
+}
+
+// TriggerElements filters elements of type Trigger.
+func (c *ElementCollection[E]) FilterTrigger() *ElementCollection[*Trigger] {
+	ret := c.genericFilter(func(_ Status, _ TargetStatus, e Element) bool {
+		_, ok := e.(*Trigger)
+		return ok
+	})
+	return (*ElementCollection[*Trigger])(ret)
+}
+
 func (e UniqueWithoutIndexConstraint) element() {}
 
 // Element implements ElementGetter.
@@ -2586,6 +2623,8 @@ func (e* ElementProto) SetElement(element Element) {
 			e.ElementOneOf = &ElementProto_TableZoneConfig{ TableZoneConfig: t}
 		case *TemporaryIndex:
 			e.ElementOneOf = &ElementProto_TemporaryIndex{ TemporaryIndex: t}
+		case *Trigger:
+			e.ElementOneOf = &ElementProto_Trigger{ Trigger: t}
 		case *UniqueWithoutIndexConstraint:
 			e.ElementOneOf = &ElementProto_UniqueWithoutIndexConstraint{ UniqueWithoutIndexConstraint: t}
 		case *UniqueWithoutIndexConstraintUnvalidated:
@@ -2662,6 +2701,7 @@ func GetElementOneOfProtos() []interface{} {
 	((*ElementProto_TableSchemaLocked)(nil)),
 	((*ElementProto_TableZoneConfig)(nil)),
 	((*ElementProto_TemporaryIndex)(nil)),
+	((*ElementProto_Trigger)(nil)),
 	((*ElementProto_UniqueWithoutIndexConstraint)(nil)),
 	((*ElementProto_UniqueWithoutIndexConstraintUnvalidated)(nil)),
 	((*ElementProto_UserPrivileges)(nil)),
@@ -2735,6 +2775,7 @@ func GetElementTypes() []interface{} {
 	((*TableSchemaLocked)(nil)),
 	((*TableZoneConfig)(nil)),
 	((*TemporaryIndex)(nil)),
+	((*Trigger)(nil)),
 	((*UniqueWithoutIndexConstraint)(nil)),
 	((*UniqueWithoutIndexConstraintUnvalidated)(nil)),
 	((*UserPrivileges)(nil)),
@@ -71,6 +71,8 @@ const (
 	// RecreateSourceIndexID is the index ID that we are replacing with
 	// this new index.
 	RecreateSourceIndexID
+	// TriggerID is the ID of a trigger.
+	TriggerID
 
 	// TargetStatus is the target status of an element.
 	TargetStatus
@@ -390,6 +392,13 @@ var elementSchemaOptions = []rel.SchemaOption{
 	rel.EntityMapping(t((*scpb.TableSchemaLocked)(nil)),
 		rel.EntityAttr(DescID, "TableID"),
 	),
+	rel.EntityMapping(t((*scpb.Trigger)(nil)),
+		rel.EntityAttr(DescID, "TableID"),
+		rel.EntityAttr(TriggerID, "TriggerID"),
+		rel.EntityAttr(Name, "Name"),
+		rel.EntityAttr(ReferencedDescID, "FuncID"),
+		rel.EntityAttr(ReferencedColumnIDs, "ReferencedColumnIDs"),
+	),
 	rel.EntityMapping(t((*scpb.Function)(nil)),
 		rel.EntityAttr(DescID, "FunctionID"),
 	),
@@ -296,14 +296,16 @@ var regularBuiltins = map[string]builtinDefinition{
 	// TODO(pmattis): What string functions should also support types.Bytes?
 
 	"lower": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
-		stringOverload1(
-			func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
-				return tree.NewDString(strings.ToLower(s)), nil
-			},
-			types.String,
-			"Converts all characters in `val` to their lower-case equivalents.",
-			volatility.Immutable,
-		),
+		append([]tree.Overload{
+			preferOverload(stringOverload1(
+				func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
+					return tree.NewDString(strings.ToLower(s)), nil
+				},
+				types.String,
+				"Converts all characters in `val` to their lower-case equivalents.",
+				volatility.Immutable,
+			)),
+		}, makeRangeAccessorOverloads("lower")...)...,
 	),
 
 	"unaccent": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
@@ -327,14 +329,16 @@ var regularBuiltins = map[string]builtinDefinition{
 	),
 
 	"upper": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
-		stringOverload1(
-			func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
-				return tree.NewDString(strings.ToUpper(s)), nil
-			},
-			types.String,
-			"Converts all characters in `val` to their to their upper-case equivalents.",
-			volatility.Immutable,
-		),
+		append([]tree.Overload{
+			preferOverload(stringOverload1(
+				func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
+					return tree.NewDString(strings.ToUpper(s)), nil
+				},
+				types.String,
+				"Converts all characters in `val` to their to their upper-case equivalents.",
+				volatility.Immutable,
+			)),
+		}, makeRangeAccessorOverloads("upper")...)...,
 	),
 
 	"prettify_statement": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
@@ -1400,7 +1400,7 @@ var builtinOidsArray = []string{
 	1424: `obj_description(object_oid: oid, catalog_name: string) -> string`,
 	1425: `oid(int: int) -> oid`,
 	1426: `shobj_description(object_oid: oid, catalog_name: string) -> string`,
-	1427: `pg_try_advisory_lock(int: int) -> bool`,
+	1427: `pg_try_advisory_lock(key: int) -> bool`,
 	1428: `pg_advisory_unlock(key: int) -> bool`,
 	1429: `pg_client_encoding() -> string`,
 	1430: `pg_function_is_visible(oid: oid) -> bool`,
@@ -2570,6 +2570,264 @@ var builtinOidsArray = []string{
 	2602: `crdb_internal.execute_internally(query: string, session_bound: bool, overrides: string) -> string`,
 	2603: `crdb_internal.execute_internally(query: string, overrides: string, use_session_txn: bool) -> string`,
 	2604: `crdb_internal.execute_internally(query: string, session_bound: bool, overrides: string, use_session_txn: bool) -> string`,
+	2605: `pg_try_advisory_lock(key1: int4, key2: int4) -> bool`,
+	2606: `pg_try_advisory_lock_shared(key: int) -> bool`,
+	2607: `pg_try_advisory_lock_shared(key1: int4, key2: int4) -> bool`,
+	2608: `pg_advisory_lock(key: int) -> void`,
+	2609: `pg_advisory_lock(key1: int4, key2: int4) -> void`,
+	2610: `pg_advisory_lock_shared(key: int) -> void`,
+	2611: `pg_advisory_lock_shared(key1: int4, key2: int4) -> void`,
+	2612: `pg_advisory_xact_lock(key: int) -> void`,
+	2613: `pg_advisory_xact_lock(key1: int4, key2: int4) -> void`,
+	2614: `pg_advisory_xact_lock_shared(key: int) -> void`,
+	2615: `pg_advisory_xact_lock_shared(key1: int4, key2: int4) -> void`,
+	2616: `pg_try_advisory_xact_lock(key: int) -> bool`,
+	2617: `pg_try_advisory_xact_lock(key1: int4, key2: int4) -> bool`,
+	2618: `pg_try_advisory_xact_lock_shared(key: int) -> bool`,
+	2619: `pg_try_advisory_xact_lock_shared(key1: int4, key2: int4) -> bool`,
+	2620: `triggersend(trigger: trigger) -> bytes`,
+	2621: `triggerrecv(input: anyelement) -> trigger`,
+	2622: `triggerout(trigger: trigger) -> bytes`,
+	2623: `triggerin(input: anyelement) -> trigger`,
+	2624: `pg_notify(channel: string, payload: string) -> void`,
+	2625: `int4rangesend(int4range: int4range) -> bytes`,
+	2626: `int4rangerecv(input: anyelement) -> int4range`,
+	2627: `int4rangeout(int4range: int4range) -> bytes`,
+	2628: `int4rangein(input: anyelement) -> int4range`,
+	2629: `varchar(int4range: int4range) -> varchar`,
+	2630: `text(int4range: int4range) -> string`,
+	2631: `bpchar(int4range: int4range) -> char`,
+	2632: `name(int4range: int4range) -> name`,
+	2633: `char(int4range: int4range) -> "char"`,
+	2634: `int4multirangesend(int4multirange: int4multirange) -> bytes`,
+	2635: `int4multirangerecv(input: anyelement) -> int4multirange`,
+	2636: `int4multirangeout(int4multirange: int4multirange) -> bytes`,
+	2637: `int4multirangein(input: anyelement) -> int4multirange`,
+	2638: `varchar(int4multirange: int4multirange) -> varchar`,
+	2639: `text(int4multirange: int4multirange) -> string`,
+	2640: `bpchar(int4multirange: int4multirange) -> char`,
+	2641: `name(int4multirange: int4multirange) -> name`,
+	2642: `char(int4multirange: int4multirange) -> "char"`,
+	2643: `int8rangesend(int8range: int8range) -> bytes`,
+	2644: `int8rangerecv(input: anyelement) -> int8range`,
+	2645: `int8rangeout(int8range: int8range) -> bytes`,
+	2646: `int8rangein(input: anyelement) -> int8range`,
+	2647: `varchar(int8range: int8range) -> varchar`,
+	2648: `text(int8range: int8range) -> string`,
+	2649: `bpchar(int8range: int8range) -> char`,
+	2650: `name(int8range: int8range) -> name`,
+	2651: `char(int8range: int8range) -> "char"`,
+	2652: `int8multirangesend(int8multirange: int8multirange) -> bytes`,
+	2653: `int8multirangerecv(input: anyelement) -> int8multirange`,
+	2654: `int8multirangeout(int8multirange: int8multirange) -> bytes`,
+	2655: `int8multirangein(input: anyelement) -> int8multirange`,
+	2656: `varchar(int8multirange: int8multirange) -> varchar`,
+	2657: `text(int8multirange: int8multirange) -> string`,
+	2658: `bpchar(int8multirange: int8multirange) -> char`,
+	2659: `name(int8multirange: int8multirange) -> name`,
+	2660: `char(int8multirange: int8multirange) -> "char"`,
+	2661: `numrangesend(numrange: numrange) -> bytes`,
+	2662: `numrangerecv(input: anyelement) -> numrange`,
+	2663: `numrangeout(numrange: numrange) -> bytes`,
+	2664: `numrangein(input: anyelement) -> numrange`,
+	2665: `varchar(numrange: numrange) -> varchar`,
+	2666: `text(numrange: numrange) -> string`,
+	2667: `bpchar(numrange: numrange) -> char`,
+	2668: `name(numrange: numrange) -> name`,
+	2669: `char(numrange: numrange) -> "char"`,
+	2670: `nummultirangesend(nummultirange: nummultirange) -> bytes`,
+	2671: `nummultirangerecv(input: anyelement) -> nummultirange`,
+	2672: `nummultirangeout(nummultirange: nummultirange) -> bytes`,
+	2673: `nummultirangein(input: anyelement) -> nummultirange`,
+	2674: `varchar(nummultirange: nummultirange) -> varchar`,
+	2675: `text(nummultirange: nummultirange) -> string`,
+	2676: `bpchar(nummultirange: nummultirange) -> char`,
+	2677: `name(nummultirange: nummultirange) -> name`,
+	2678: `char(nummultirange: nummultirange) -> "char"`,
+	2679: `tsrangesend(tsrange: tsrange) -> bytes`,
+	2680: `tsrangerecv(input: anyelement) -> tsrange`,
+	2681: `tsrangeout(tsrange: tsrange) -> bytes`,
+	2682: `tsrangein(input: anyelement) -> tsrange`,
+	2683: `varchar(tsrange: tsrange) -> varchar`,
+	2684: `text(tsrange: tsrange) -> string`,
+	2685: `bpchar(tsrange: tsrange) -> char`,
+	2686: `name(tsrange: tsrange) -> name`,
+	2687: `char(tsrange: tsrange) -> "char"`,
+	2688: `tsmultirangesend(tsmultirange: tsmultirange) -> bytes`,
+	2689: `tsmultirangerecv(input: anyelement) -> tsmultirange`,
+	2690: `tsmultirangeout(tsmultirange: tsmultirange) -> bytes`,
+	2691: `tsmultirangein(input: anyelement) -> tsmultirange`,
+	2692: `varchar(tsmultirange: tsmultirange) -> varchar`,
+	2693: `text(tsmultirange: tsmultirange) -> string`,
+	2694: `bpchar(tsmultirange: tsmultirange) -> char`,
+	2695: `name(tsmultirange: tsmultirange) -> name`,
+	2696: `char(tsmultirange: tsmultirange) -> "char"`,
+	2697: `tstzrangesend(tstzrange: tstzrange) -> bytes`,
+	2698: `tstzrangerecv(input: anyelement) -> tstzrange`,
+	2699: `tstzrangeout(tstzrange: tstzrange) -> bytes`,
+	2700: `tstzrangein(input: anyelement) -> tstzrange`,
+	2701: `varchar(tstzrange: tstzrange) -> varchar`,
+	2702: `text(tstzrange: tstzrange) -> string`,
+	2703: `bpchar(tstzrange: tstzrange) -> char`,
+	2704: `name(tstzrange: tstzrange) -> name`,
+	2705: `char(tstzrange: tstzrange) -> "char"`,
+	2706: `tstzmultirangesend(tstzmultirange: tstzmultirange) -> bytes`,
+	2707: `tstzmultirangerecv(input: anyelement) -> tstzmultirange`,
+	2708: `tstzmultirangeout(tstzmultirange: tstzmultirange) -> bytes`,
+	2709: `tstzmultirangein(input: anyelement) -> tstzmultirange`,
+	2710: `varchar(tstzmultirange: tstzmultirange) -> varchar`,
+	2711: `text(tstzmultirange: tstzmultirange) -> string`,
+	2712: `bpchar(tstzmultirange: tstzmultirange) -> char`,
+	2713: `name(tstzmultirange: tstzmultirange) -> name`,
+	2714: `char(tstzmultirange: tstzmultirange) -> "char"`,
+	2715: `daterangesend(daterange: daterange) -> bytes`,
+	2716: `daterangerecv(input: anyelement) -> daterange`,
+	2717: `daterangeout(daterange: daterange) -> bytes`,
+	2718: `daterangein(input: anyelement) -> daterange`,
+	2719: `varchar(daterange: daterange) -> varchar`,
+	2720: `text(daterange: daterange) -> string`,
+	2721: `bpchar(daterange: daterange) -> char`,
+	2722: `name(daterange: daterange) -> name`,
+	2723: `char(daterange: daterange) -> "char"`,
+	2724: `datemultirangesend(datemultirange: datemultirange) -> bytes`,
+	2725: `datemultirangerecv(input: anyelement) -> datemultirange`,
+	2726: `datemultirangeout(datemultirange: datemultirange) -> bytes`,
+	2727: `datemultirangein(input: anyelement) -> datemultirange`,
+	2728: `varchar(datemultirange: datemultirange) -> varchar`,
+	2729: `text(datemultirange: datemultirange) -> string`,
+	2730: `bpchar(datemultirange: datemultirange) -> char`,
+	2731: `name(datemultirange: datemultirange) -> name`,
+	2732: `char(datemultirange: datemultirange) -> "char"`,
+	2733: `lower(range: int4range) -> int4`,
+	2734: `lower(multirange: int4multirange) -> int4`,
+	2735: `lower(range: int8range) -> int`,
+	2736: `lower(multirange: int8multirange) -> int`,
+	2737: `lower(range: numrange) -> decimal`,
+	2738: `lower(multirange: nummultirange) -> decimal`,
+	2739: `lower(range: tsrange) -> timestamp`,
+	2740: `lower(multirange: tsmultirange) -> timestamp`,
+	2741: `lower(range: tstzrange) -> timestamptz`,
+	2742: `lower(multirange: tstzmultirange) -> timestamptz`,
+	2743: `lower(range: daterange) -> date`,
+	2744: `lower(multirange: datemultirange) -> date`,
+	2745: `upper(range: int4range) -> int4`,
+	2746: `upper(multirange: int4multirange) -> int4`,
+	2747: `upper(range: int8range) -> int`,
+	2748: `upper(multirange: int8multirange) -> int`,
+	2749: `upper(range: numrange) -> decimal`,
+	2750: `upper(multirange: nummultirange) -> decimal`,
+	2751: `upper(range: tsrange) -> timestamp`,
+	2752: `upper(multirange: tsmultirange) -> timestamp`,
+	2753: `upper(range: tstzrange) -> timestamptz`,
+	2754: `upper(multirange: tstzmultirange) -> timestamptz`,
+	2755: `upper(range: daterange) -> date`,
+	2756: `upper(multirange: datemultirange) -> date`,
+	2757: `isempty(range: int4range) -> bool`,
+	2758: `isempty(multirange: int4multirange) -> bool`,
+	2759: `isempty(range: int8range) -> bool`,
+	2760: `isempty(multirange: int8multirange) -> bool`,
+	2761: `isempty(range: numrange) -> bool`,
+	2762: `isempty(multirange: nummultirange) -> bool`,
+	2763: `isempty(range: tsrange) -> bool`,
+	2764: `isempty(multirange: tsmultirange) -> bool`,
+	2765: `isempty(range: tstzrange) -> bool`,
+	2766: `isempty(multirange: tstzmultirange) -> bool`,
+	2767: `isempty(range: daterange) -> bool`,
+	2768: `isempty(multirange: datemultirange) -> bool`,
+	2769: `lower_inc(range: int4range) -> bool`,
+	2770: `lower_inc(multirange: int4multirange) -> bool`,
+	2771: `lower_inc(range: int8range) -> bool`,
+	2772: `lower_inc(multirange: int8multirange) -> bool`,
+	2773: `lower_inc(range: numrange) -> bool`,
+	2774: `lower_inc(multirange: nummultirange) -> bool`,
+	2775: `lower_inc(range: tsrange) -> bool`,
+	2776: `lower_inc(multirange: tsmultirange) -> bool`,
+	2777: `lower_inc(range: tstzrange) -> bool`,
+	2778: `lower_inc(multirange: tstzmultirange) -> bool`,
+	2779: `lower_inc(range: daterange) -> bool`,
+	2780: `lower_inc(multirange: datemultirange) -> bool`,
+	2781: `upper_inc(range: int4range) -> bool`,
+	2782: `upper_inc(multirange: int4multirange) -> bool`,
+	2783: `upper_inc(range: int8range) -> bool`,
+	2784: `upper_inc(multirange: int8multirange) -> bool`,
+	2785: `upper_inc(range: numrange) -> bool`,
+	2786: `upper_inc(multirange: nummultirange) -> bool`,
+	2787: `upper_inc(range: tsrange) -> bool`,
+	2788: `upper_inc(multirange: tsmultirange) -> bool`,
+	2789: `upper_inc(range: tstzrange) -> bool`,
+	2790: `upper_inc(multirange: tstzmultirange) -> bool`,
+	2791: `upper_inc(range: daterange) -> bool`,
+	2792: `upper_inc(multirange: datemultirange) -> bool`,
+	2793: `lower_inf(range: int4range) -> bool`,
+	2794: `lower_inf(multirange: int4multirange) -> bool`,
+	2795: `lower_inf(range: int8range) -> bool`,
+	2796: `lower_inf(multirange: int8multirange) -> bool`,
+	2797: `lower_inf(range: numrange) -> bool`,
+	2798: `lower_inf(multirange: nummultirange) -> bool`,
+	2799: `lower_inf(range: tsrange) -> bool`,
+	2800: `lower_inf(multirange: tsmultirange) -> bool`,
+	2801: `lower_inf(range: tstzrange) -> bool`,
+	2802: `lower_inf(multirange: tstzmultirange) -> bool`,
+	2803: `lower_inf(range: daterange) -> bool`,
+	2804: `lower_inf(multirange: datemultirange) -> bool`,
+	2805: `upper_inf(range: int4range) -> bool`,
+	2806: `upper_inf(multirange: int4multirange) -> bool`,
+	2807: `upper_inf(range: int8range) -> bool`,
+	2808: `upper_inf(multirange: int8multirange) -> bool`,
+	2809: `upper_inf(range: numrange) -> bool`,
+	2810: `upper_inf(multirange: nummultirange) -> bool`,
+	2811: `upper_inf(range: tsrange) -> bool`,
+	2812: `upper_inf(multirange: tsmultirange) -> bool`,
+	2813: `upper_inf(range: tstzrange) -> bool`,
+	2814: `upper_inf(multirange: tstzmultirange) -> bool`,
+	2815: `upper_inf(range: daterange) -> bool`,
+	2816: `upper_inf(multirange: datemultirange) -> bool`,
+	2817: `int4range(lower: int4, upper: int4) -> int4range`,
+	2818: `int4range(lower: int4, upper: int4, bounds: string) -> int4range`,
+	2819: `int4multirange(int4range...) -> int4multirange`,
+	2820: `int8range(lower: int, upper: int) -> int8range`,
+	2821: `int8range(lower: int, upper: int, bounds: string) -> int8range`,
+	2822: `int8multirange(int8range...) -> int8multirange`,
+	2823: `numrange(lower: decimal, upper: decimal) -> numrange`,
+	2824: `numrange(lower: decimal, upper: decimal, bounds: string) -> numrange`,
+	2825: `nummultirange(numrange...) -> nummultirange`,
+	2826: `tsrange(lower: timestamp, upper: timestamp) -> tsrange`,
+	2827: `tsrange(lower: timestamp, upper: timestamp, bounds: string) -> tsrange`,
+	2828: `tsmultirange(tsrange...) -> tsmultirange`,
+	2829: `tstzrange(lower: timestamptz, upper: timestamptz) -> tstzrange`,
+	2830: `tstzrange(lower: timestamptz, upper: timestamptz, bounds: string) -> tstzrange`,
+	2831: `tstzmultirange(tstzrange...) -> tstzmultirange`,
+	2832: `daterange(lower: date, upper: date) -> daterange`,
+	2833: `daterange(lower: date, upper: date, bounds: string) -> daterange`,
+	2834: `datemultirange(daterange...) -> datemultirange`,
+	2835: `jsonpathsend(jsonpath: jsonpath) -> bytes`,
+	2836: `jsonpathrecv(input: anyelement) -> jsonpath`,
+	2837: `jsonpathout(jsonpath: jsonpath) -> bytes`,
+	2838: `jsonpathin(input: anyelement) -> jsonpath`,
+	2839: `varchar(jsonpath: jsonpath) -> varchar`,
+	2840: `text(jsonpath: jsonpath) -> string`,
+	2841: `bpchar(jsonpath: jsonpath) -> char`,
+	2842: `name(jsonpath: jsonpath) -> name`,
+	2843: `char(jsonpath: jsonpath) -> "char"`,
+	2844: `jsonpath(string: string) -> jsonpath`,
+	2845: `jsonpath(jsonpath: jsonpath) -> jsonpath`,
+	2846: `jsonb_path_exists(target: jsonb, path: jsonpath) -> bool`,
+	2847: `jsonb_path_exists(target: jsonb, path: jsonpath, vars: jsonb) -> bool`,
+	2848: `jsonb_path_exists(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> bool`,
+	2849: `jsonb_path_match(target: jsonb, path: jsonpath) -> bool`,
+	2850: `jsonb_path_match(target: jsonb, path: jsonpath, vars: jsonb) -> bool`,
+	2851: `jsonb_path_match(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> bool`,
+	2852: `jsonb_path_query(target: jsonb, path: jsonpath) -> jsonb`,
+	2853: `jsonb_path_query(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2854: `jsonb_path_query(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2855: `jsonb_path_query_array(target: jsonb, path: jsonpath) -> jsonb`,
+	2856: `jsonb_path_query_array(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2857: `jsonb_path_query_array(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2858: `jsonb_path_query_first(target: jsonb, path: jsonpath) -> jsonb`,
+	2859: `jsonb_path_query_first(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2860: `jsonb_path_query_first(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2861: `crdb_internal.table_changes(table: regclass, start_time: timestamptz) -> tuple{decimal AS mvcc_timestamp, string AS operation, jsonb AS key, jsonb AS before, jsonb AS after}`,
+	2862: `crdb_internal.table_changes(table: regclass, start_time: timestamptz, end_time: timestamptz) -> tuple{decimal AS mvcc_timestamp, string AS operation, jsonb AS key, jsonb AS before, jsonb AS after}`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
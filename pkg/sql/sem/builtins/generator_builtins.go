@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/randident"
@@ -671,6 +672,37 @@ The last argument is a JSONB object containing the following optional fields:
 			volatility.Stable,
 		),
 	),
+	"crdb_internal.table_changes": makeBuiltin(genProps(),
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "table", Typ: types.RegClass},
+				{Name: "start_time", Typ: types.TimestampTZ},
+			},
+			tableChangesGeneratorType,
+			makeTableChangesGenerator,
+			"Returns the changes made to the rows of the table after start_time, up to "+
+				"the statement time. Each change has the MVCC timestamp at which it was "+
+				"written, the operation (insert, update or delete), the primary key of the "+
+				"row and the row before and after the change. start_time must be within "+
+				"the GC window of the table.",
+			volatility.Stable,
+		),
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "table", Typ: types.RegClass},
+				{Name: "start_time", Typ: types.TimestampTZ},
+				{Name: "end_time", Typ: types.TimestampTZ},
+			},
+			tableChangesGeneratorType,
+			makeTableChangesGenerator,
+			"Returns the changes made to the rows of the table after start_time, up to "+
+				"and including end_time. Each change has the MVCC timestamp at which it was "+
+				"written, the operation (insert, update or delete), the primary key of the "+
+				"row and the row before and after the change. start_time must be within "+
+				"the GC window of the table.",
+			volatility.Stable,
+		),
+	),
 	"crdb_internal.sstable_metrics": makeBuiltin(
 		tree.FunctionProperties{
 			Category: builtinconstants.CategorySystemInfo,
@@ -3222,6 +3254,77 @@ func (tssi *tableSpanStatsIterator) ResolvedType() *types.T {
 	return tableSpanStatsGeneratorType
 }
 
+var tableChangesGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.Decimal, types.String, types.Jsonb, types.Jsonb, types.Jsonb},
+	[]string{"mvcc_timestamp", "operation", "key", "before", "after"},
+)
+
+// tableChangesGenerator is a value generator that iterates over the changes
+// made to the rows of a table in a time interval.
+type tableChangesGenerator struct {
+	tableID   int64
+	startTime hlc.Timestamp
+	endTime   hlc.Timestamp
+	planner   eval.Planner
+	it        eval.InternalRows
+}
+
+func makeTableChangesGenerator(
+	ctx context.Context, evalCtx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	g := &tableChangesGenerator{
+		tableID: int64(tree.MustBeDOid(args[0]).Oid),
+		startTime: hlc.Timestamp{
+			WallTime: tree.MustBeDTimestampTZ(args[1]).UnixNano(),
+		},
+		planner: evalCtx.Planner,
+	}
+	if len(args) > 2 {
+		g.endTime = hlc.Timestamp{WallTime: tree.MustBeDTimestampTZ(args[2]).UnixNano()}
+	}
+	return g, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *tableChangesGenerator) ResolvedType() *types.T {
+	return tableChangesGeneratorType
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *tableChangesGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	it, err := g.planner.TableChanges(ctx, g.tableID, g.startTime, g.endTime)
+	if err != nil {
+		return err
+	}
+	g.it = it
+	return nil
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *tableChangesGenerator) Next(ctx context.Context) (bool, error) {
+	if g.it == nil {
+		return false, errors.AssertionFailedf("Start must be called before Next")
+	}
+	return g.it.Next(ctx)
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *tableChangesGenerator) Values() (tree.Datums, error) {
+	if g.it == nil {
+		return nil, errors.AssertionFailedf("Start must be called before Values")
+	}
+	return g.it.Cur(), nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *tableChangesGenerator) Close(_ context.Context) {
+	if g.it != nil {
+		// Closing the iterator only releases its memory and cannot fail.
+		_ = g.it.Close()
+		g.it = nil
+	}
+}
+
 var tableMetricsGeneratorType = types.MakeLabeledTuple(
 	[]*types.T{types.Int, types.Int, types.Int, types.Int, types.Int, types.Json},
 	[]string{"node_id", "store_id", "level", "file_num", "approximate_span_bytes", "metrics"},
@@ -0,0 +1,233 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
+)
+
+func init() {
+	for k, v := range jsonpathBuiltins {
+		// Most builtins in this file are of the Normal class, but
+		// jsonb_path_query is of the Generator class.
+		const enforceClass = false
+		registerBuiltin(k, v, tree.NormalClass, enforceClass)
+	}
+}
+
+var jsonpathBuiltins = map[string]builtinDefinition{
+	"jsonb_path_exists": makeBuiltin(jsonProps(),
+		makeJSONPathOverloads(
+			types.Bool,
+			"Returns whether the JSON path returns any item for the specified JSON value.",
+			func(a jsonPathArgs) (tree.Datum, error) {
+				return eval.JSONPathExists(a.target, a.path, a.vars, a.silent)
+			},
+		)...,
+	),
+
+	"jsonb_path_match": makeBuiltin(jsonProps(),
+		makeJSONPathOverloads(
+			types.Bool,
+			"Returns the result of a JSON path predicate check for the specified JSON value. "+
+				"The path must return a single boolean, or a JSON null in which case NULL is returned.",
+			func(a jsonPathArgs) (tree.Datum, error) {
+				return eval.JSONPathMatch(a.target, a.path, a.vars, a.silent)
+			},
+		)...,
+	),
+
+	"jsonb_path_query": makeBuiltin(jsonProps(),
+		makeJSONPathGeneratorOverloads(
+			"Returns all JSON items returned by the JSON path for the specified JSON value.",
+		)...,
+	),
+
+	"jsonb_path_query_array": makeBuiltin(jsonProps(),
+		makeJSONPathOverloads(
+			types.Jsonb,
+			"Returns all JSON items returned by the JSON path for the specified JSON value, "+
+				"as a JSON array.",
+			func(a jsonPathArgs) (tree.Datum, error) {
+				items, err := a.query()
+				if err != nil {
+					return nil, err
+				}
+				b := json.NewArrayBuilder(len(items))
+				for _, j := range items {
+					b.Add(j)
+				}
+				return tree.NewDJSON(b.Build()), nil
+			},
+		)...,
+	),
+
+	"jsonb_path_query_first": makeBuiltin(jsonProps(),
+		makeJSONPathOverloads(
+			types.Jsonb,
+			"Returns the first JSON item returned by the JSON path for the specified JSON value, "+
+				"or NULL if there are no results.",
+			func(a jsonPathArgs) (tree.Datum, error) {
+				items, err := a.query()
+				if err != nil {
+					return nil, err
+				}
+				if len(items) == 0 {
+					return tree.DNull, nil
+				}
+				return tree.NewDJSON(items[0]), nil
+			},
+		)...,
+	),
+}
+
+// jsonPathArgsInfo describes the optional arguments of the JSON path
+// functions.
+const jsonPathArgsInfo = "\n\nIf vars is specified, it must be a JSON object whose fields provide " +
+	"the values of the named variables of the path. If silent is true, the " +
+	"errors caused by the structure of the JSON value are suppressed, as done " +
+	"by the @? and @@ operators."
+
+// jsonPathArgs are the arguments of the JSON path functions, which take the
+// target JSON value, the path, and optionally the variables of the path and
+// the silent flag.
+type jsonPathArgs struct {
+	target *tree.DJSON
+	path   *tree.DJsonpath
+	vars   json.JSON
+	silent bool
+}
+
+func makeJSONPathArgs(args tree.Datums) jsonPathArgs {
+	a := jsonPathArgs{
+		target: tree.MustBeDJSON(args[0]),
+		path:   tree.MustBeDJsonpath(args[1]),
+	}
+	if len(args) > 2 {
+		a.vars = tree.MustBeDJSON(args[2]).JSON
+	}
+	if len(args) > 3 {
+		a.silent = bool(tree.MustBeDBool(args[3]))
+	}
+	return a
+}
+
+// query returns the items returned by the path. If silent is true, no items
+// are returned instead of the errors caused by the structure of the target.
+func (a jsonPathArgs) query() ([]json.JSON, error) {
+	items, err := a.path.Path.Eval(a.target.JSON, a.vars)
+	if err != nil {
+		if a.silent && jsonpath.IsSuppressible(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return items, nil
+}
+
+// jsonPathParamTypes returns the parameters of the overloads of the JSON path
+// functions, which take between 2 and 4 arguments.
+func jsonPathParamTypes() []tree.ParamTypes {
+	params := tree.ParamTypes{
+		{Name: "target", Typ: types.Jsonb},
+		{Name: "path", Typ: types.Jsonpath},
+		{Name: "vars", Typ: types.Jsonb},
+		{Name: "silent", Typ: types.Bool},
+	}
+	return []tree.ParamTypes{params[:2], params[:3], params}
+}
+
+func makeJSONPathOverloads(
+	ret *types.T, info string, fn func(jsonPathArgs) (tree.Datum, error),
+) []tree.Overload {
+	var overloads []tree.Overload
+	for _, params := range jsonPathParamTypes() {
+		overloadInfo := info
+		if len(params) > 2 {
+			overloadInfo += jsonPathArgsInfo
+		}
+		overloads = append(overloads, tree.Overload{
+			Types:      params,
+			ReturnType: tree.FixedReturnType(ret),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(makeJSONPathArgs(args))
+			},
+			Info:       overloadInfo,
+			Volatility: volatility.Immutable,
+		})
+	}
+	return overloads
+}
+
+func makeJSONPathGeneratorOverloads(info string) []tree.Overload {
+	var overloads []tree.Overload
+	for _, params := range jsonPathParamTypes() {
+		overloadInfo := info
+		if len(params) > 2 {
+			overloadInfo += jsonPathArgsInfo
+		}
+		overloads = append(overloads, makeGeneratorOverload(
+			params,
+			types.Jsonb,
+			func(_ context.Context, _ *eval.Context, args tree.Datums) (eval.ValueGenerator, error) {
+				return &jsonPathQueryGenerator{args: makeJSONPathArgs(args)}, nil
+			},
+			overloadInfo,
+			volatility.Immutable,
+		))
+	}
+	return overloads
+}
+
+// jsonPathQueryGenerator supports jsonb_path_query.
+type jsonPathQueryGenerator struct {
+	args  jsonPathArgs
+	items []json.JSON
+	buf   [1]tree.Datum
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) ResolvedType() *types.T {
+	return types.Jsonb
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Start(_ context.Context, _ *kv.Txn) error {
+	var err error
+	g.items, err = g.args.query()
+	return err
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Next(_ context.Context) (bool, error) {
+	if len(g.items) == 0 {
+		return false, nil
+	}
+	g.buf[0] = tree.NewDJSON(g.items[0])
+	g.items = g.items[1:]
+	return true, nil
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Values() (tree.Datums, error) {
+	return g.buf[:], nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Close(_ context.Context) {}
@@ -89,6 +89,96 @@ var typeBuiltinsHaveUnderscore = map[oid.Oid]struct{}{
 	types.AnyTuple.Oid():    {},
 }
 
+var errAdvisoryLocksUnavailable = pgerror.New(
+	pgcode.FeatureNotSupported, "advisory locks are not available in this context",
+)
+
+// advisoryLockProps returns the properties of the advisory lock builtins. They
+// depend on the session that issued them and so cannot be distributed.
+func advisoryLockProps() tree.FunctionProperties {
+	return tree.FunctionProperties{
+		Category:         builtinconstants.CategorySystemInfo,
+		DistsqlBlocklist: true,
+	}
+}
+
+// makeAdvisoryLockBuiltins creates the two overloads of an advisory lock
+// builtin: one keyed by a single int8, and one keyed by a pair of int4s. The
+// given function is called with the key built from the arguments.
+func makeAdvisoryLockBuiltins(
+	retType *types.T,
+	info string,
+	fn func(ctx context.Context, locker eval.AdvisoryLocker, key eval.AdvisoryLockKey) (tree.Datum, error),
+) builtinDefinition {
+	call := func(ctx context.Context, evalCtx *eval.Context, key eval.AdvisoryLockKey) (tree.Datum, error) {
+		if evalCtx.AdvisoryLocker == nil {
+			return nil, errAdvisoryLocksUnavailable
+		}
+		return fn(ctx, evalCtx.AdvisoryLocker, key)
+	}
+	return makeBuiltin(advisoryLockProps(),
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
+			ReturnType: tree.FixedReturnType(retType),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				key := int64(tree.MustBeDInt(args[0]))
+				return call(ctx, evalCtx, eval.MakeAdvisoryLockKeyFromInt8(key))
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
+			ReturnType: tree.FixedReturnType(retType),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				key1 := int32(tree.MustBeDInt(args[0]))
+				key2 := int32(tree.MustBeDInt(args[1]))
+				return call(ctx, evalCtx, eval.MakeAdvisoryLockKeyFromInt4s(key1, key2))
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+	)
+}
+
+// makeAdvisoryLockBuiltin creates a builtin that acquires an advisory lock in
+// the given mode. If try is true, the builtin returns whether the lock was
+// acquired rather than waiting for conflicting holders to release it.
+func makeAdvisoryLockBuiltin(
+	mode eval.AdvisoryLockMode, txnScoped bool, try bool, info string,
+) builtinDefinition {
+	retType := types.Void
+	if try {
+		retType = types.Bool
+	}
+	return makeAdvisoryLockBuiltins(retType, info,
+		func(ctx context.Context, locker eval.AdvisoryLocker, key eval.AdvisoryLockKey) (tree.Datum, error) {
+			acquired, err := locker.AcquireAdvisoryLock(ctx, key, mode, txnScoped, !try /* wait */)
+			if err != nil {
+				return nil, err
+			}
+			if !try {
+				return tree.DVoidDatum, nil
+			}
+			return tree.MakeDBool(tree.DBool(acquired)), nil
+		},
+	)
+}
+
+// makeAdvisoryUnlockBuiltin creates a builtin that releases a session-level
+// advisory lock held in the given mode.
+func makeAdvisoryUnlockBuiltin(mode eval.AdvisoryLockMode, info string) builtinDefinition {
+	return makeAdvisoryLockBuiltins(types.Bool, info,
+		func(ctx context.Context, locker eval.AdvisoryLocker, key eval.AdvisoryLockKey) (tree.Datum, error) {
+			released, err := locker.ReleaseAdvisoryLock(ctx, key, mode)
+			if err != nil {
+				return nil, err
+			}
+			return tree.MakeDBool(tree.DBool(released)), nil
+		},
+	)
+}
+
 // PGIOBuiltinPrefix returns the string prefix to a type's IO functions. This
 // is either the type's postgres display name or the type's postgres display
 // name plus an underscore, depending on the type.
@@ -149,6 +239,11 @@ func init() {
 		if !ok {
 			return
 		}
+		// Range and multirange types have constructor functions with the same
+		// name as the type, which are defined in range_builtins.go.
+		if f := toType.Family(); f == types.RangeFamily || f == types.MultirangeFamily {
+			return
+		}
 		distSQLBlockList := toType.Family() == types.OidFamily
 		if _, ok := castBuiltins[toOID]; !ok {
 			castBuiltins[toOID] = &builtinDefinition{
@@ -881,6 +976,40 @@ var pgBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	// https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-INFO-SESSION
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategorySystemInfo,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "channel", Typ: types.String}, {Name: "payload", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.Void),
+			// pg_notify is not strict: a NULL payload is sent as an empty string,
+			// while a NULL channel results in an error.
+			CalledOnNullInput: true,
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if evalCtx.Notifier == nil {
+					return nil, pgerror.New(pgcode.FeatureNotSupported,
+						"notifications are not available in this context")
+				}
+				if args[0] == tree.DNull {
+					return nil, pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+				}
+				var payload string
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := evalCtx.Notifier.SendNotification(ctx, string(tree.MustBeDString(args[0])), payload); err != nil {
+					return nil, err
+				}
+				return tree.DVoidDatum, nil
+			},
+			Info:       "Sends a notification with the given payload to the sessions listening on the given channel.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	// pg_my_temp_schema returns the OID of session's temporary schema, or 0 if
 	// none.
 	// https://www.postgresql.org/docs/11/functions-info.html
@@ -1159,68 +1288,88 @@ var pgBuiltins = map[string]builtinDefinition{
 		},
 	),
 
-	"pg_try_advisory_lock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "int", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-	),
-
-	"pg_advisory_unlock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-	),
-
-	"pg_advisory_unlock_shared": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-	),
-
-	"pg_advisory_unlock_all": makeBuiltin(defProps(),
+	// Advisory lock functions.
+	// https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADVISORY-LOCKS
+
+	"pg_advisory_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockExclusive,
+		false, /* txnScoped */
+		false, /* try */
+		"Obtains an exclusive session-level advisory lock, waiting if necessary. Deadlocks involving session-level advisory locks are not detected; use lock_timeout to bound the wait.",
+	),
+	"pg_advisory_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockShared,
+		false, /* txnScoped */
+		false, /* try */
+		"Obtains a shared session-level advisory lock, waiting if necessary. Deadlocks involving session-level advisory locks are not detected; use lock_timeout to bound the wait.",
+	),
+	"pg_try_advisory_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockExclusive,
+		false, /* txnScoped */
+		true,  /* try */
+		"Obtains an exclusive session-level advisory lock if available. "+
+			"Returns true if the lock was obtained and false otherwise.",
+	),
+	"pg_try_advisory_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockShared,
+		false, /* txnScoped */
+		true,  /* try */
+		"Obtains a shared session-level advisory lock if available. "+
+			"Returns true if the lock was obtained and false otherwise.",
+	),
+	"pg_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockExclusive,
+		true,  /* txnScoped */
+		false, /* try */
+		"Obtains an exclusive transaction-level advisory lock, waiting if necessary. "+
+			"The lock is released when the current transaction ends.",
+	),
+	"pg_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockShared,
+		true,  /* txnScoped */
+		false, /* try */
+		"Obtains a shared transaction-level advisory lock, waiting if necessary. "+
+			"The lock is released when the current transaction ends.",
+	),
+	"pg_try_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockExclusive,
+		true, /* txnScoped */
+		true, /* try */
+		"Obtains an exclusive transaction-level advisory lock if available. "+
+			"Returns true if the lock was obtained and false otherwise.",
+	),
+	"pg_try_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockShared,
+		true, /* txnScoped */
+		true, /* try */
+		"Obtains a shared transaction-level advisory lock if available. "+
+			"Returns true if the lock was obtained and false otherwise.",
+	),
+	"pg_advisory_unlock": makeAdvisoryUnlockBuiltin(
+		eval.AdvisoryLockExclusive,
+		"Releases a previously-acquired exclusive session-level advisory lock. "+
+			"Returns true if the lock was successfully released and false if it was not held.",
+	),
+	"pg_advisory_unlock_shared": makeAdvisoryUnlockBuiltin(
+		eval.AdvisoryLockShared,
+		"Releases a previously-acquired shared session-level advisory lock. "+
+			"Returns true if the lock was successfully released and false if it was not held.",
+	),
+
+	"pg_advisory_unlock_all": makeBuiltin(advisoryLockProps(),
 		tree.Overload{
 			Types:      tree.ParamTypes{},
 			ReturnType: tree.FixedReturnType(types.Void),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx context.Context, evalCtx *eval.Context, _ tree.Datums) (tree.Datum, error) {
+				if evalCtx.AdvisoryLocker == nil {
+					return nil, errAdvisoryLocksUnavailable
+				}
+				if err := evalCtx.AdvisoryLocker.ReleaseAllAdvisoryLocks(ctx); err != nil {
+					return nil, err
+				}
 				return tree.DVoidDatum, nil
 			},
-			Info:       notUsableInfo,
+			Info:       "Releases all session-level advisory locks held by the current session.",
 			Volatility: volatility.Volatile,
 		},
 	),
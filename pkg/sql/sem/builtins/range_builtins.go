@@ -0,0 +1,226 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+func init() {
+	for k, v := range rangeBuiltins {
+		v.props.Category = builtinconstants.CategoryRange
+		const enforceClass = true
+		registerBuiltin(k, v, tree.NormalClass, enforceClass)
+	}
+}
+
+var rangeBuiltins = makeRangeBuiltins()
+
+// rangeAccessor describes a builtin which extracts a property of a range or a
+// multirange. A multirange is treated as the smallest range containing all of
+// its ranges.
+type rangeAccessor struct {
+	info string
+	// retType returns the type of the result, given the subtype of the range.
+	retType func(subtype *types.T) *types.T
+	fn      func(r *tree.DRange) tree.Datum
+}
+
+var rangeAccessors = map[string]rangeAccessor{
+	"lower": {
+		info:    "Returns the lower bound of the range, or NULL if the range is empty or its lower bound is infinite.",
+		retType: func(subtype *types.T) *types.T { return subtype },
+		fn:      func(r *tree.DRange) tree.Datum { return r.Lower },
+	},
+	"upper": {
+		info:    "Returns the upper bound of the range, or NULL if the range is empty or its upper bound is infinite.",
+		retType: func(subtype *types.T) *types.T { return subtype },
+		fn:      func(r *tree.DRange) tree.Datum { return r.Upper },
+	},
+	"isempty": {
+		info:    "Returns whether the range is empty.",
+		retType: func(*types.T) *types.T { return types.Bool },
+		fn:      func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.Empty)) },
+	},
+	"lower_inc": {
+		info:    "Returns whether the lower bound of the range is inclusive.",
+		retType: func(*types.T) *types.T { return types.Bool },
+		fn:      func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.LowerInc)) },
+	},
+	"upper_inc": {
+		info:    "Returns whether the upper bound of the range is inclusive.",
+		retType: func(*types.T) *types.T { return types.Bool },
+		fn:      func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.UpperInc)) },
+	},
+	"lower_inf": {
+		info:    "Returns whether the lower bound of the range is infinite.",
+		retType: func(*types.T) *types.T { return types.Bool },
+		fn: func(r *tree.DRange) tree.Datum {
+			return tree.MakeDBool(tree.DBool(!r.Empty && r.Lower == tree.DNull))
+		},
+	},
+	"upper_inf": {
+		info:    "Returns whether the upper bound of the range is infinite.",
+		retType: func(*types.T) *types.T { return types.Bool },
+		fn: func(r *tree.DRange) tree.Datum {
+			return tree.MakeDBool(tree.DBool(!r.Empty && r.Upper == tree.DNull))
+		},
+	},
+}
+
+// makeRangeAccessorOverloads returns the overloads of the named range
+// accessor builtin for all range and multirange types.
+func makeRangeAccessorOverloads(name string) []tree.Overload {
+	acc := rangeAccessors[name]
+	var overloads []tree.Overload
+	for i, r := range types.RangeTypes {
+		m := types.MultirangeTypes[i]
+		overloads = append(overloads,
+			tree.Overload{
+				Types:      tree.ParamTypes{{Name: "range", Typ: r}},
+				ReturnType: tree.FixedReturnType(acc.retType(r.RangeContents())),
+				Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+					return acc.fn(tree.MustBeDRange(args[0])), nil
+				},
+				Info:       acc.info,
+				Volatility: volatility.Immutable,
+			},
+			tree.Overload{
+				Types:      tree.ParamTypes{{Name: "multirange", Typ: m}},
+				ReturnType: tree.FixedReturnType(acc.retType(r.RangeContents())),
+				Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+					span, err := multirangeSpan(tree.MustBeDMultirange(args[0]))
+					if err != nil {
+						return nil, err
+					}
+					return acc.fn(span), nil
+				},
+				Info:       acc.info,
+				Volatility: volatility.Immutable,
+			},
+		)
+	}
+	return overloads
+}
+
+func makeRangeBuiltins() map[string]builtinDefinition {
+	res := make(map[string]builtinDefinition)
+	for name := range rangeAccessors {
+		// The lower and upper builtins also have string overloads, and are
+		// defined in builtins.go.
+		if name == "lower" || name == "upper" {
+			continue
+		}
+		res[name] = makeBuiltin(defProps(), makeRangeAccessorOverloads(name)...)
+	}
+
+	for i, r := range types.RangeTypes {
+		r, m := r, types.MultirangeTypes[i]
+		subtype := r.RangeContents()
+		res[r.Name()] = makeBuiltin(defProps(),
+			tree.Overload{
+				Types:      tree.ParamTypes{{Name: "lower", Typ: subtype}, {Name: "upper", Typ: subtype}},
+				ReturnType: tree.FixedReturnType(r),
+				Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+					return tree.NewDRange(r, args[0], args[1], true /* lowerInc */, false /* upperInc */)
+				},
+				Info: fmt.Sprintf("Constructs a %s with the given bounds. The lower bound is "+
+					"inclusive and the upper bound is exclusive. A NULL bound is infinite.", r.Name()),
+				Volatility:        volatility.Immutable,
+				CalledOnNullInput: true,
+			},
+			tree.Overload{
+				Types: tree.ParamTypes{
+					{Name: "lower", Typ: subtype},
+					{Name: "upper", Typ: subtype},
+					{Name: "bounds", Typ: types.String},
+				},
+				ReturnType: tree.FixedReturnType(r),
+				Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+					if args[2] == tree.DNull {
+						return nil, pgerror.New(pgcode.DataException, "range constructor flags argument must not be null")
+					}
+					lowerInc, upperInc, err := parseRangeBoundFlags(string(tree.MustBeDString(args[2])))
+					if err != nil {
+						return nil, err
+					}
+					return tree.NewDRange(r, args[0], args[1], lowerInc, upperInc)
+				},
+				Info: fmt.Sprintf("Constructs a %s with the given bounds. The inclusivity of the bounds "+
+					"is specified by `bounds`, which is one of `[]`, `[)`, `(]` or `()`. A NULL bound "+
+					"is infinite.", r.Name()),
+				Volatility:        volatility.Immutable,
+				CalledOnNullInput: true,
+			},
+		)
+		res[m.Name()] = makeBuiltin(defProps(),
+			tree.Overload{
+				Types:      tree.VariadicType{VarType: r},
+				ReturnType: tree.FixedReturnType(m),
+				Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+					ranges := make([]*tree.DRange, len(args))
+					for i := range args {
+						if args[i] == tree.DNull {
+							return nil, pgerror.New(pgcode.NullValueNotAllowed,
+								"multirange values cannot contain null members")
+						}
+						ranges[i] = tree.MustBeDRange(args[i])
+					}
+					return tree.NewDMultirange(m, ranges), nil
+				},
+				Info:              fmt.Sprintf("Constructs a %s containing the given ranges.", m.Name()),
+				Volatility:        volatility.Immutable,
+				CalledOnNullInput: true,
+			},
+		)
+	}
+	return res
+}
+
+// preferOverload marks the given overload as preferred. It is used for the
+// string overloads of lower and upper, so that untyped NULLs and placeholders
+// resolve to them as in Postgres.
+func preferOverload(o tree.Overload) tree.Overload {
+	o.PreferredOverload = true
+	return o
+}
+
+// multirangeSpan returns the smallest range containing all ranges of the
+// multirange, which is empty if the multirange is empty.
+func multirangeSpan(d *tree.DMultirange) (*tree.DRange, error) {
+	rangeTyp := d.ResolvedType().RangeContents()
+	if len(d.Ranges) == 0 {
+		return tree.NewDEmptyRange(rangeTyp), nil
+	}
+	first, last := d.Ranges[0], d.Ranges[len(d.Ranges)-1]
+	return tree.NewDRange(rangeTyp, first.Lower, last.Upper, first.LowerInc, last.UpperInc)
+}
+
+// parseRangeBoundFlags parses the bounds argument of a range constructor.
+func parseRangeBoundFlags(s string) (lowerInc, upperInc bool, _ error) {
+	if len(s) != 2 || (s[0] != '[' && s[0] != '(') || (s[1] != ']' && s[1] != ')') {
+		return false, false, errors.WithHint(
+			pgerror.New(pgcode.Syntax, "invalid range bound flags"),
+			`Valid values are "[]", "[)", "(]", and "()".`,
+		)
+	}
+	return s[0] == '[', s[1] == ']', nil
+}
@@ -213,6 +213,18 @@ func LookupCast(src, tgt *types.T) (Cast, bool) {
 		}, true
 	}
 
+	// Domains have dynamic OIDs, so they can't be populated in castMap. The
+	// casts from and to a domain are the casts from and to its base type.
+	if src.IsDomain() || tgt.IsDomain() {
+		if src.Oid() == tgt.Oid() {
+			return Cast{
+				MaxContext: ContextImplicit,
+				Volatility: volatility.Immutable,
+			}, true
+		}
+		return LookupCast(src.DomainBaseType(), tgt.DomainBaseType())
+	}
+
 	// Enums have dynamic OIDs, so they can't be populated in castMap. Instead,
 	// we dynamically create cast structs for valid enum casts.
 	if srcFamily == types.EnumFamily && tgtFamily == types.StringFamily {
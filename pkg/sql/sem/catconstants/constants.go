@@ -101,6 +101,8 @@ const (
 	MVCCStatistics                         SystemTableName = "mvcc_statistics"
 	StmtExecInsightsTableName              SystemTableName = "statement_execution_insights"
 	TxnExecInsightsTableName               SystemTableName = "transaction_execution_insights"
+	ReplicationSlotsTableName              SystemTableName = "replication_slots"
+	NotificationsTableName                 SystemTableName = "notifications"
 )
 
 // Oid for virtual database and table.
@@ -142,6 +142,12 @@ func (e *evaluator) EvalCompareBox2DOp(
 	return tree.MakeDBool(tree.DBool(op.Op(left, right))), nil
 }
 
+func (e *evaluator) EvalCompareRangeOp(
+	ctx context.Context, op *tree.CompareRangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(op.Op(left, right))), nil
+}
+
 func (e *evaluator) EvalCompareScalarOp(
 	ctx context.Context, op *tree.CompareScalarOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -696,6 +702,20 @@ func (e *evaluator) EvalJSONSomeExistsOp(
 	return tree.JSONExistsAny(tree.MustBeDJSON(a), tree.MustBeDArray(b))
 }
 
+func (e *evaluator) EvalJSONPathExistsOp(
+	ctx context.Context, _ *tree.JSONPathExistsOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	// Like jsonb_path_exists with silent set to true.
+	return JSONPathExists(tree.MustBeDJSON(a), tree.MustBeDJsonpath(b), nil /* vars */, true /* silent */)
+}
+
+func (e *evaluator) EvalJSONPathMatchOp(
+	ctx context.Context, _ *tree.JSONPathMatchOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	// Like jsonb_path_match with silent set to true.
+	return JSONPathMatch(tree.MustBeDJSON(a), tree.MustBeDJsonpath(b), nil /* vars */, true /* silent */)
+}
+
 func (e *evaluator) EvalLShiftINetOp(
 	ctx context.Context, _ *tree.LShiftINetOp, left, right tree.Datum,
 ) (tree.Datum, error) {
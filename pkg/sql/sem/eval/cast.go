@@ -101,11 +101,21 @@ func PerformAssignmentCast(
 func performCast(
 	ctx context.Context, evalCtx *Context, d tree.Datum, t *types.T, truncateWidth bool,
 ) (tree.Datum, error) {
-	d, err := performCastWithoutPrecisionTruncation(ctx, evalCtx, d, t, truncateWidth)
+	// Values of a domain type are values of its base type which satisfy the
+	// constraints of the domain.
+	base := t.DomainBaseType()
+	d, err := performCastWithoutPrecisionTruncation(ctx, evalCtx, d, base, truncateWidth)
 	if err != nil {
 		return nil, err
 	}
-	return tree.AdjustValueToType(t, d)
+	d, err = tree.AdjustValueToType(base, d)
+	if err != nil || !t.IsDomain() {
+		return d, err
+	}
+	if err := checkDomainConstraints(ctx, evalCtx, d, t); err != nil {
+		return nil, err
+	}
+	return d, nil
 }
 
 var (
@@ -460,7 +470,7 @@ func performCastWithoutPrecisionTruncation(
 				tree.FmtDataConversionConfig(evalCtx.SessionData().DataConversionConfig),
 				tree.FmtLocation(evalCtx.GetLocation()),
 			)
-		case *tree.DArray:
+		case *tree.DArray, *tree.DRange, *tree.DMultirange:
 			s = tree.AsStringWithFlags(
 				d,
 				tree.FmtPgwireText,
@@ -498,6 +508,8 @@ func performCastWithoutPrecisionTruncation(
 			s = t.String()
 		case *tree.DJSON:
 			s = t.JSON.String()
+		case *tree.DJsonpath:
+			s = t.Path.String()
 		case *tree.DTSQuery:
 			s = t.TSQuery.String()
 		case *tree.DTSVector:
@@ -619,6 +631,34 @@ func performCastWithoutPrecisionTruncation(
 			return tree.NewDRefCursor(d.Contents), nil
 		}
 
+	case types.RangeFamily:
+		switch d := d.(type) {
+		case *tree.DString:
+			res, _, err := tree.ParseDRangeFromString(evalCtx, string(*d), t)
+			return res, err
+		case *tree.DCollatedString:
+			res, _, err := tree.ParseDRangeFromString(evalCtx, d.Contents, t)
+			return res, err
+		case *tree.DRange:
+			if d.ResolvedType().Oid() == t.Oid() {
+				return d, nil
+			}
+		}
+
+	case types.MultirangeFamily:
+		switch d := d.(type) {
+		case *tree.DString:
+			res, _, err := tree.ParseDMultirangeFromString(evalCtx, string(*d), t)
+			return res, err
+		case *tree.DCollatedString:
+			res, _, err := tree.ParseDMultirangeFromString(evalCtx, d.Contents, t)
+			return res, err
+		case *tree.DMultirange:
+			if d.ResolvedType().Oid() == t.Oid() {
+				return d, nil
+			}
+		}
+
 	case types.GeographyFamily:
 		switch d := d.(type) {
 		case *tree.DString:
@@ -892,6 +932,15 @@ func performCastWithoutPrecisionTruncation(
 			}
 			return tree.ParseDJSON(string(j))
 		}
+	case types.JsonpathFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDJsonpath(string(*v))
+		case *tree.DCollatedString:
+			return tree.ParseDJsonpath(v.Contents)
+		case *tree.DJsonpath:
+			return v, nil
+		}
 	case types.TSQueryFamily:
 		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V23_1) {
 			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
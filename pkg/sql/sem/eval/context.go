@@ -169,6 +169,10 @@ type Context struct {
 
 	Sequence SequenceOperators
 
+	AdvisoryLocker AdvisoryLocker
+
+	Notifier Notifier
+
 	Tenant TenantOperator
 
 	// Regions stores information about regions.
@@ -409,6 +409,13 @@ type Planner interface {
 
 	GetDetailsForSpanStats(ctx context.Context, dbId int, tableId int) (InternalRows, error)
 
+	// TableChanges returns the changes made to the rows of the given table
+	// between startTime (exclusive) and endTime (inclusive). If endTime is
+	// empty, the read timestamp of the transaction is used. Each row contains
+	// the MVCC timestamp of the change, the operation, the primary key and the
+	// row before and after the change.
+	TableChanges(ctx context.Context, tableID int64, startTime, endTime hlc.Timestamp) (InternalRows, error)
+
 	// MaybeReallocateAnnotations makes a new annotations slice of size
 	// numAnnotations if one is maintained by this Planner and the current one has
 	// less than numAnnotations entries. If updated, the annotations in the eval
@@ -637,6 +644,88 @@ type SequenceOperators interface {
 	GetLastSequenceValueByID(ctx context.Context, seqID uint32) (value int64, wasCalled bool, err error)
 }
 
+// AdvisoryLockKey identifies a Postgres advisory lock. As in Postgres, a lock
+// requested with a single int8 key is split into its high (ClassID) and low
+// (ObjID) 32 bits and uses an ObjSubID of 1, while a lock requested with two
+// int4 keys uses them as ClassID and ObjID and an ObjSubID of 2. The two key
+// spaces therefore never overlap.
+type AdvisoryLockKey struct {
+	ClassID  uint32
+	ObjID    uint32
+	ObjSubID uint16
+}
+
+// MakeAdvisoryLockKeyFromInt8 returns the AdvisoryLockKey for a lock requested
+// with a single int8 key.
+func MakeAdvisoryLockKeyFromInt8(key int64) AdvisoryLockKey {
+	return AdvisoryLockKey{
+		ClassID:  uint32(uint64(key) >> 32),
+		ObjID:    uint32(key),
+		ObjSubID: 1,
+	}
+}
+
+// MakeAdvisoryLockKeyFromInt4s returns the AdvisoryLockKey for a lock
+// requested with two int4 keys.
+func MakeAdvisoryLockKeyFromInt4s(key1, key2 int32) AdvisoryLockKey {
+	return AdvisoryLockKey{
+		ClassID:  uint32(key1),
+		ObjID:    uint32(key2),
+		ObjSubID: 2,
+	}
+}
+
+// AdvisoryLockMode is the mode in which an advisory lock is held.
+type AdvisoryLockMode int
+
+const (
+	// AdvisoryLockExclusive conflicts with all other holders of the lock.
+	AdvisoryLockExclusive AdvisoryLockMode = iota
+	// AdvisoryLockShared conflicts only with exclusive holders of the lock.
+	AdvisoryLockShared
+)
+
+// String implements the fmt.Stringer interface. The names match the lock modes
+// reported in Postgres' pg_locks.
+func (m AdvisoryLockMode) String() string {
+	if m == AdvisoryLockShared {
+		return "ShareLock"
+	}
+	return "ExclusiveLock"
+}
+
+// AdvisoryLocker is used to acquire and release Postgres advisory locks on
+// behalf of the current session.
+type AdvisoryLocker interface {
+	// AcquireAdvisoryLock acquires the advisory lock identified by key in the
+	// given mode. Transaction-scoped locks are released when the current
+	// transaction finishes, while session-scoped locks are held until they are
+	// explicitly released or the session ends. If wait is false and the lock is
+	// held in a conflicting mode by another session, false is returned instead
+	// of blocking.
+	AcquireAdvisoryLock(
+		ctx context.Context, key AdvisoryLockKey, mode AdvisoryLockMode, txnScoped bool, wait bool,
+	) (bool, error)
+
+	// ReleaseAdvisoryLock releases one hold of the session-scoped advisory lock
+	// identified by key in the given mode. It returns false if the session does
+	// not hold the lock in that mode.
+	ReleaseAdvisoryLock(ctx context.Context, key AdvisoryLockKey, mode AdvisoryLockMode) (bool, error)
+
+	// ReleaseAllAdvisoryLocks releases all session-scoped advisory locks held by
+	// the session.
+	ReleaseAllAdvisoryLocks(ctx context.Context) error
+}
+
+// Notifier is used to send notifications with pg_notify on behalf of the
+// current session.
+type Notifier interface {
+	// SendNotification sends a notification with the given payload on the given channel
+	// of the current database. The notification is delivered to the sessions
+	// listening on the channel when the current transaction commits.
+	SendNotification(ctx context.Context, channel, payload string) error
+}
+
 // ChangefeedState is used to track progress and checkpointing for sinkless/core changefeeds.
 // Because a CREATE CHANGEFEED statement for a sinkless changefeed will hang and return data
 // over the SQL connection, this state belongs in the EvalCtx.
@@ -0,0 +1,82 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package eval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// DomainValueName is the name by which the CHECK constraints of a domain
+// reference the value being checked.
+const DomainValueName = "value"
+
+// SubstituteDomainValue returns a copy of the CHECK expression of a domain in
+// which the references to VALUE are replaced with the given expression.
+func SubstituteDomainValue(expr tree.Expr, value tree.Expr) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(e tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if n, ok := e.(*tree.UnresolvedName); ok && n.NumParts == 1 && n.Parts[0] == DomainValueName {
+			return false, value, nil
+		}
+		return true, e, nil
+	})
+}
+
+// checkDomainConstraints returns an error if the datum, which must already have
+// the base type of the domain type t, violates the constraints of the domain.
+//
+// The CHECK constraints are satisfied by NULL values.
+func checkDomainConstraints(
+	ctx context.Context, evalCtx *Context, d tree.Datum, t *types.T,
+) error {
+	dd := t.TypeMeta.DomainData
+	if dd == nil {
+		return errors.AssertionFailedf("domain type %s is not hydrated", t.SQLStringForError())
+	}
+	if d == tree.DNull {
+		if dd.NotNull {
+			return pgerror.Newf(pgcode.NotNullViolation,
+				"domain %s does not allow null values", t.Name())
+		}
+		return nil
+	}
+	semaCtx := tree.MakeSemaContext()
+	for i := range dd.CheckExprs {
+		// The expressions are parsed for every value, since type checking them
+		// modifies the parsed expression.
+		expr, err := parser.ParseExpr(dd.CheckExprs[i])
+		if err != nil {
+			return err
+		}
+		if expr, err = SubstituteDomainValue(expr, d); err != nil {
+			return err
+		}
+		typedExpr, err := tree.TypeCheckAndRequire(ctx, expr, &semaCtx, types.Bool, "domain check")
+		if err != nil {
+			return err
+		}
+		res, err := Expr(ctx, evalCtx, typedExpr)
+		if err != nil {
+			return err
+		}
+		if res == tree.DBoolFalse {
+			return pgerror.Newf(pgcode.CheckViolation,
+				"value for domain %s violates check constraint %q", t.Name(), dd.CheckNames[i])
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/errors"
 )
 
@@ -111,3 +112,41 @@ func PopulateRecordWithJSON(
 	}
 	return nil
 }
+
+// JSONPathExists returns whether the path returns any item for the given JSON
+// document, as done by jsonb_path_exists and the @? operator. If silent is
+// true, NULL is returned instead of the errors caused by the structure of the
+// document.
+func JSONPathExists(
+	j *tree.DJSON, p *tree.DJsonpath, vars json.JSON, silent bool,
+) (tree.Datum, error) {
+	res, err := p.Exists(j.JSON, vars)
+	if err != nil {
+		if silent && jsonpath.IsSuppressible(err) {
+			return tree.DNull, nil
+		}
+		return nil, err
+	}
+	return tree.MakeDBool(tree.DBool(res)), nil
+}
+
+// JSONPathMatch returns the result of a path which returns a single boolean
+// for the given JSON document, as done by jsonb_path_match and the @@
+// operator. NULL is returned if the result is unknown. If silent is true, NULL
+// is also returned instead of the errors caused by the structure of the
+// document.
+func JSONPathMatch(
+	j *tree.DJSON, p *tree.DJsonpath, vars json.JSON, silent bool,
+) (tree.Datum, error) {
+	res, ok, err := p.Match(j.JSON, vars)
+	if err != nil {
+		if silent && jsonpath.IsSuppressible(err) {
+			return tree.DNull, nil
+		}
+		return nil, err
+	}
+	if !ok {
+		return tree.DNull, nil
+	}
+	return tree.MakeDBool(tree.DBool(res)), nil
+}
@@ -46,5 +46,19 @@ func (tc *unsupportedTypeChecker) CheckType(ctx context.Context, typ *types.T) e
 			"%s not supported until version 23.2", errorTypeString,
 		)
 	}
+	switch typ.Family() {
+	case types.RangeFamily, types.MultirangeFamily:
+		if !tc.version.IsActive(ctx, clusterversion.V24_1_RangeTypes) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s not supported until version 24.1", typ.SQLStringForError(),
+			)
+		}
+	case types.JsonpathFamily:
+		if !tc.version.IsActive(ctx, clusterversion.V24_1_Jsonpath) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s not supported until version 24.1", typ.SQLStringForError(),
+			)
+		}
+	}
 	return nil
 }
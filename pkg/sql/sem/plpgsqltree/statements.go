@@ -189,15 +189,63 @@ func (s *Declaration) WalkStmt(visitor StatementVisitor) Statement {
 	return newStmt
 }
 
+// decl_alias
+type AliasDeclaration struct {
+	StatementImpl
+	Name Variable
+	// Target is the name of the variable or parameter that is aliased. It is
+	// empty if the alias refers to a parameter by position.
+	Target Variable
+	// TargetIdx is the one-based position of the aliased parameter when it is
+	// referenced with the $n syntax, or zero otherwise.
+	TargetIdx int
+}
+
+func (s *AliasDeclaration) CopyNode() *AliasDeclaration {
+	copyNode := *s
+	return &copyNode
+}
+
+func (s *AliasDeclaration) Format(ctx *tree.FmtCtx) {
+	ctx.FormatNode(&s.Name)
+	ctx.WriteString(" ALIAS FOR ")
+	if s.TargetIdx > 0 {
+		ctx.WriteString("$")
+		ctx.WriteString(strconv.Itoa(s.TargetIdx))
+	} else {
+		ctx.FormatNode(&s.Target)
+	}
+	ctx.WriteString(";\n")
+}
+
+func (s *AliasDeclaration) PlpgSQLStatementTag() string {
+	return "decl_alias"
+}
+
+func (s *AliasDeclaration) WalkStmt(visitor StatementVisitor) Statement {
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
+}
+
 type CursorDeclaration struct {
 	StatementImpl
 	Name   Variable
 	Scroll tree.CursorScrollOption
-	Query  tree.Statement
+	// Args are the arguments of the cursor, which are bound when the cursor is
+	// opened. They can be referenced by name within the cursor query.
+	Args  []CursorArg
+	Query tree.Statement
+}
+
+// CursorArg is a named argument of a bound cursor.
+type CursorArg struct {
+	Name Variable
+	Typ  tree.ResolvableTypeReference
 }
 
 func (s *CursorDeclaration) CopyNode() *CursorDeclaration {
 	copyNode := *s
+	copyNode.Args = append([]CursorArg(nil), copyNode.Args...)
 	return &copyNode
 }
 
@@ -209,7 +257,20 @@ func (s *CursorDeclaration) Format(ctx *tree.FmtCtx) {
 	case tree.NoScroll:
 		ctx.WriteString(" NO SCROLL")
 	}
-	ctx.WriteString(" CURSOR FOR ")
+	ctx.WriteString(" CURSOR")
+	if len(s.Args) > 0 {
+		ctx.WriteString(" (")
+		for i := range s.Args {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&s.Args[i].Name)
+			ctx.WriteString(" ")
+			ctx.FormatTypeReference(s.Args[i].Typ)
+		}
+		ctx.WriteString(")")
+	}
+	ctx.WriteString(" FOR ")
 	ctx.FormatNode(s.Query)
 	ctx.WriteString(";\n")
 }
@@ -228,6 +289,10 @@ type Assignment struct {
 	StatementImpl
 	Var   Variable
 	Value Expr
+
+	// Indirection is set when the assignment targets a single element of a
+	// composite-typed variable, e.g. NEW.x := 1.
+	Indirection tree.Name
 }
 
 func (s *Assignment) CopyNode() *Assignment {
@@ -241,6 +306,10 @@ func (s *Assignment) PlpgSQLStatementTag() string {
 
 func (s *Assignment) Format(ctx *tree.FmtCtx) {
 	ctx.FormatNode(&s.Var)
+	if s.Indirection != "" {
+		ctx.WriteByte('.')
+		ctx.FormatNode(&s.Indirection)
+	}
 	ctx.WriteString(" := ")
 	ctx.FormatNode(s.Value)
 	ctx.WriteString(";\n")
@@ -613,11 +682,33 @@ type ForInt struct {
 	Lower   Expr
 	Upper   Expr
 	Step    Expr
-	Reverse int
+	Reverse bool
 	Body    []Statement
 }
 
+func (s *ForInt) CopyNode() *ForInt {
+	copyNode := *s
+	copyNode.Body = append([]Statement(nil), copyNode.Body...)
+	return &copyNode
+}
+
 func (s *ForInt) Format(ctx *tree.FmtCtx) {
+	formatLoopLabel(ctx, s.Label)
+	ctx.WriteString("FOR ")
+	ctx.FormatNode(&s.Var)
+	ctx.WriteString(" IN ")
+	if s.Reverse {
+		ctx.WriteString("REVERSE ")
+	}
+	ctx.FormatNode(s.Lower)
+	ctx.WriteString("..")
+	ctx.FormatNode(s.Upper)
+	if s.Step != nil {
+		ctx.WriteString(" BY ")
+		ctx.FormatNode(s.Step)
+	}
+	ctx.WriteString(" LOOP\n")
+	formatLoopBody(ctx, s.Label, s.Body)
 }
 
 func (s *ForInt) PlpgSQLStatementTag() string {
@@ -625,14 +716,41 @@ func (s *ForInt) PlpgSQLStatementTag() string {
 }
 
 func (s *ForInt) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, recurse := visitor.Visit(s)
+
+	if recurse {
+		for i, bodyStmt := range s.Body {
+			newBodyStmt := bodyStmt.WalkStmt(visitor)
+			if newBodyStmt != bodyStmt {
+				if newStmt == s {
+					newStmt = s.CopyNode()
+				}
+				newStmt.(*ForInt).Body[i] = newBodyStmt
+			}
+		}
+	}
+	return newStmt
 }
 
+// ForQuery contains the fields common to the FOR loops that iterate over the
+// rows of a query.
 type ForQuery struct {
 	StatementImpl
-	Label string
-	Var   Variable
-	Body  []Statement
+	Label  string
+	Target []Variable
+	Body   []Statement
+}
+
+func (s *ForQuery) formatTarget(ctx *tree.FmtCtx) {
+	formatLoopLabel(ctx, s.Label)
+	ctx.WriteString("FOR ")
+	for i := range s.Target {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&s.Target[i])
+	}
+	ctx.WriteString(" IN ")
 }
 
 func (s *ForQuery) Format(ctx *tree.FmtCtx) {
@@ -648,10 +766,21 @@ func (s *ForQuery) WalkStmt(visitor StatementVisitor) Statement {
 
 type ForSelect struct {
 	ForQuery
-	Query Expr
+	Query tree.Statement
+}
+
+func (s *ForSelect) CopyNode() *ForSelect {
+	copyNode := *s
+	copyNode.Target = append([]Variable(nil), copyNode.Target...)
+	copyNode.Body = append([]Statement(nil), copyNode.Body...)
+	return &copyNode
 }
 
 func (s *ForSelect) Format(ctx *tree.FmtCtx) {
+	s.formatTarget(ctx)
+	ctx.FormatNode(s.Query)
+	ctx.WriteString(" LOOP\n")
+	formatLoopBody(ctx, s.Label, s.Body)
 }
 
 func (s *ForSelect) PlpgSQLStatementTag() string {
@@ -659,7 +788,20 @@ func (s *ForSelect) PlpgSQLStatementTag() string {
 }
 
 func (s *ForSelect) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, recurse := visitor.Visit(s)
+
+	if recurse {
+		for i, bodyStmt := range s.Body {
+			newBodyStmt := bodyStmt.WalkStmt(visitor)
+			if newBodyStmt != bodyStmt {
+				if newStmt == s {
+					newStmt = s.CopyNode()
+				}
+				newStmt.(*ForSelect).Body[i] = newBodyStmt
+			}
+		}
+	}
+	return newStmt
 }
 
 type ForCursor struct {
@@ -815,13 +957,24 @@ func (s *Return) WalkStmt(visitor StatementVisitor) Statement {
 	return newStmt
 }
 
+// stmt_return_next
 type ReturnNext struct {
 	StatementImpl
-	Expr   Expr
-	RetVar Variable
+	Expr Expr
+}
+
+func (s *ReturnNext) CopyNode() *ReturnNext {
+	copyNode := *s
+	return &copyNode
 }
 
 func (s *ReturnNext) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("RETURN NEXT")
+	if s.Expr != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(s.Expr)
+	}
+	ctx.WriteString(";\n")
 }
 
 func (s *ReturnNext) PlpgSQLStatementTag() string {
@@ -829,17 +982,25 @@ func (s *ReturnNext) PlpgSQLStatementTag() string {
 }
 
 func (s *ReturnNext) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
 }
 
+// stmt_return_query
 type ReturnQuery struct {
 	StatementImpl
-	Query        Expr
-	DynamicQuery Expr
-	Params       []Expr
+	Query tree.Statement
+}
+
+func (s *ReturnQuery) CopyNode() *ReturnQuery {
+	copyNode := *s
+	return &copyNode
 }
 
 func (s *ReturnQuery) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("RETURN QUERY ")
+	ctx.FormatNode(s.Query)
+	ctx.WriteString(";\n")
 }
 
 func (s *ReturnQuery) PlpgSQLStatementTag() string {
@@ -847,7 +1008,8 @@ func (s *ReturnQuery) PlpgSQLStatementTag() string {
 }
 
 func (s *ReturnQuery) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
 }
 
 // stmt_raise
@@ -1030,10 +1192,26 @@ func (s *DynamicExecute) WalkStmt(visitor StatementVisitor) Statement {
 // stmt_perform
 type Perform struct {
 	StatementImpl
-	Expr Expr
+	// Query is the query to execute with its results discarded. It is parsed
+	// from the PERFORM statement by replacing the PERFORM keyword with SELECT.
+	Query tree.Statement
+}
+
+func (s *Perform) CopyNode() *Perform {
+	copyNode := *s
+	return &copyNode
 }
 
 func (s *Perform) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("PERFORM ")
+	// Format the query without its leading SELECT keyword, which was not part
+	// of the original statement.
+	start := ctx.Len()
+	ctx.FormatNode(s.Query)
+	query := string(ctx.Bytes()[start:])
+	ctx.Truncate(start)
+	ctx.WriteString(strings.TrimPrefix(query, "SELECT "))
+	ctx.WriteString(";\n")
 }
 
 func (s *Perform) PlpgSQLStatementTag() string {
@@ -1041,7 +1219,8 @@ func (s *Perform) PlpgSQLStatementTag() string {
 }
 
 func (s *Perform) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
 }
 
 // stmt_call
@@ -1125,16 +1304,29 @@ type Open struct {
 	CurVar Variable
 	Scroll tree.CursorScrollOption
 	Query  tree.Statement
+	// Args are the values supplied for the arguments of a bound cursor.
+	Args []Expr
 }
 
 func (s *Open) CopyNode() *Open {
 	copyNode := *s
+	copyNode.Args = append([]Expr(nil), copyNode.Args...)
 	return &copyNode
 }
 
 func (s *Open) Format(ctx *tree.FmtCtx) {
 	ctx.WriteString("OPEN ")
 	ctx.FormatNode(&s.CurVar)
+	if len(s.Args) > 0 {
+		ctx.WriteString("(")
+		for i := range s.Args {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(s.Args[i])
+		}
+		ctx.WriteString(")")
+	}
 	switch s.Scroll {
 	case tree.Scroll:
 		ctx.WriteString(" SCROLL")
@@ -1293,3 +1485,26 @@ func formatStringQuotes(ctx *tree.FmtCtx, str string) {
 	formatString(ctx, str)
 	ctx.WriteString("'")
 }
+
+// formatLoopLabel prints the label of a loop statement, if any.
+func formatLoopLabel(ctx *tree.FmtCtx, label string) {
+	if label != "" {
+		ctx.WriteString("<<")
+		ctx.FormatNameP(&label)
+		ctx.WriteString(">>\n")
+	}
+}
+
+// formatLoopBody prints the body of a loop statement, followed by END LOOP and
+// the label of the loop, if any.
+func formatLoopBody(ctx *tree.FmtCtx, label string, body []Statement) {
+	for _, stmt := range body {
+		ctx.FormatNode(stmt)
+	}
+	ctx.WriteString("END LOOP")
+	if label != "" {
+		ctx.WriteString(" ")
+		ctx.FormatNameP(&label)
+	}
+	ctx.WriteString(";\n")
+}
@@ -73,6 +73,8 @@ func (*AlterTableSetOnUpdate) alterTableCmd()        {}
 func (*AlterTableSetVisible) alterTableCmd()         {}
 func (*AlterTableValidateConstraint) alterTableCmd() {}
 func (*AlterTablePartitionByTable) alterTableCmd()   {}
+func (*AlterTableAttachPartition) alterTableCmd()    {}
+func (*AlterTableDetachPartition) alterTableCmd()    {}
 func (*AlterTableInjectStats) alterTableCmd()        {}
 func (*AlterTableSetStorageParams) alterTableCmd()   {}
 func (*AlterTableResetStorageParams) alterTableCmd() {}
@@ -96,6 +98,8 @@ var _ AlterTableCmd = &AlterTableSetOnUpdate{}
 var _ AlterTableCmd = &AlterTableSetVisible{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
 var _ AlterTableCmd = &AlterTablePartitionByTable{}
+var _ AlterTableCmd = &AlterTableAttachPartition{}
+var _ AlterTableCmd = &AlterTableDetachPartition{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
 var _ AlterTableCmd = &AlterTableSetStorageParams{}
 var _ AlterTableCmd = &AlterTableResetStorageParams{}
@@ -559,6 +563,43 @@ func (node *AlterTablePartitionByTable) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.PartitionByTable)
 }
 
+// AlterTableAttachPartition represents an ALTER TABLE ATTACH PARTITION
+// command.
+type AlterTableAttachPartition struct {
+	Name  Name
+	Bound PartitionBoundSpec
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableAttachPartition) TelemetryName() string {
+	return "attach_partition"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableAttachPartition) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ATTACH PARTITION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Bound)
+}
+
+// AlterTableDetachPartition represents an ALTER TABLE DETACH PARTITION
+// command.
+type AlterTableDetachPartition struct {
+	Name Name
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableDetachPartition) TelemetryName() string {
+	return "detach_partition"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableDetachPartition) Format(ctx *FmtCtx) {
+	ctx.WriteString(" DETACH PARTITION ")
+	ctx.FormatNode(&node.Name)
+}
+
 // AuditMode represents a table audit mode
 type AuditMode int
 
@@ -707,6 +748,26 @@ func (node *AlterTableSetSchema) TelemetryName() string {
 	return "set_schema"
 }
 
+// AlterMaterializedViewStorageParams represents an ALTER MATERIALIZED VIEW
+// SET (...) or RESET (...) command.
+type AlterMaterializedViewStorageParams struct {
+	Name     *UnresolvedObjectName
+	IfExists bool
+	// Cmd is either an *AlterTableSetStorageParams or an
+	// *AlterTableResetStorageParams.
+	Cmd AlterTableCmd
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterMaterializedViewStorageParams) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER MATERIALIZED VIEW ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(node.Name)
+	ctx.FormatNode(node.Cmd)
+}
+
 // AlterTableOwner represents an ALTER TABLE OWNER TO command.
 type AlterTableOwner struct {
 	Name           *UnresolvedObjectName
@@ -542,6 +542,7 @@ var (
 		types.UUIDArray,
 		types.INet,
 		types.Jsonb,
+		types.Jsonpath,
 		types.PGLSN,
 		types.PGLSNArray,
 		types.RefCursor,
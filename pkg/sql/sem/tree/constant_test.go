@@ -378,6 +378,13 @@ func mustParseDTSQuery(t *testing.T, s string) tree.Datum {
 	}
 	return d
 }
+func mustParseDJsonpath(t *testing.T, s string) tree.Datum {
+	d, err := tree.ParseDJsonpath(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
 func mustParseDArrayOfType(typ *types.T) func(t *testing.T, s string) tree.Datum {
 	return func(t *testing.T, s string) tree.Datum {
 		evalContext := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
@@ -403,6 +410,7 @@ var parseFuncs = map[*types.T]func(*testing.T, string) tree.Datum{
 	types.TimestampTZ:      mustParseDTimestampTZ,
 	types.Interval:         mustParseDInterval,
 	types.Jsonb:            mustParseDJSON,
+	types.Jsonpath:         mustParseDJsonpath,
 	types.Uuid:             mustParseDUuid,
 	types.Box2D:            mustParseDBox2D,
 	types.Geography:        mustParseDGeography,
@@ -458,8 +466,8 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 		},
 		{
 			c: tree.NewStrVal("true"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Bool, types.Jsonb, types.TSVector,
-				types.TSQuery, types.RefCursor),
+			parseOptions: typeSet(types.String, types.Bytes, types.Bool, types.Jsonb, types.Jsonpath,
+				types.TSVector, types.TSQuery, types.RefCursor),
 		},
 		{
 			c: tree.NewStrVal("2010-09-28"),
@@ -527,6 +535,7 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 				types.Decimal,
 				types.Interval,
 				types.Jsonb,
+				types.Jsonpath,
 				types.TSVector,
 				types.TSQuery,
 				types.RefCursor,
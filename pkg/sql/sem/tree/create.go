@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/collatedstring"
 	"github.com/cockroachdb/cockroach/pkg/util/pretty"
@@ -374,6 +375,10 @@ type CreateType struct {
 	// CompositeTypeList is set when this repesnets a CREATE TYPE ... AS ( )
 	// statement.
 	CompositeTypeList []CompositeTypeElem
+	// DomainBaseType and DomainConstraints are set when this represents a
+	// CREATE DOMAIN statement.
+	DomainBaseType    ResolvableTypeReference
+	DomainConstraints []DomainConstraint
 	// IfNotExists is true if IF NOT EXISTS was requested.
 	IfNotExists bool
 }
@@ -382,6 +387,17 @@ var _ Statement = &CreateType{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateType) Format(ctx *FmtCtx) {
+	if node.Variety == Domain {
+		ctx.WriteString("CREATE DOMAIN ")
+		ctx.FormatNode(node.TypeName)
+		ctx.WriteString(" AS ")
+		ctx.FormatTypeReference(node.DomainBaseType)
+		for i := range node.DomainConstraints {
+			ctx.WriteByte(' ')
+			ctx.FormatNode(&node.DomainConstraints[i])
+		}
+		return
+	}
 	ctx.WriteString("CREATE TYPE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
@@ -412,6 +428,37 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// DomainConstraint represents a constraint of a DOMAIN type.
+type DomainConstraint struct {
+	// Name is the name of the constraint, if specified.
+	Name Name
+	// Check is the expression of a CHECK constraint. If nil, this is a NOT NULL
+	// constraint, or a NULL constraint if Nullable is set.
+	Check Expr
+	// Nullable is set for the NULL constraint, which is the default and is only
+	// accepted for compatibility.
+	Nullable bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DomainConstraint) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	switch {
+	case node.Check != nil:
+		ctx.WriteString("CHECK (")
+		ctx.FormatNode(node.Check)
+		ctx.WriteByte(')')
+	case node.Nullable:
+		ctx.WriteString("NULL")
+	default:
+		ctx.WriteString("NOT NULL")
+	}
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -426,6 +473,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExcludeConstraintTableDef) tableDef()    {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -502,6 +550,7 @@ type ColumnTableDef struct {
 		IsUnique       bool
 		WithoutIndex   bool
 		ConstraintName Name
+		Deferrability  ConstraintDeferrability
 	}
 	DefaultExpr struct {
 		Expr           Expr
@@ -518,6 +567,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -686,6 +736,7 @@ func NewColumnTableDef(
 			d.Unique.IsUnique = true
 			d.Unique.WithoutIndex = t.WithoutIndex
 			d.Unique.ConstraintName = c.Name
+			d.Unique.Deferrability = t.Deferrability
 		case *ColumnCheckConstraint:
 			d.CheckExprs = append(d.CheckExprs, ColumnTableDefCheckExpr{
 				Expr:           t.Expr,
@@ -701,6 +752,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			if d.GeneratedIdentity.IsGeneratedAsIdentity {
 				return nil, pgerror.Newf(pgcode.Syntax,
@@ -812,6 +864,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			if node.Unique.WithoutIndex {
 				ctx.WriteString(" WITHOUT INDEX")
 			}
+			ctx.FormatNode(&node.Unique.Deferrability)
 		}
 	}
 	if node.HasDefaultExpr() {
@@ -871,6 +924,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(&node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -999,7 +1053,8 @@ type ShardedPrimaryKeyConstraint struct {
 
 // UniqueConstraint represents UNIQUE on a column.
 type UniqueConstraint struct {
-	WithoutIndex bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnCheckConstraint represents either a check on a column.
@@ -1009,10 +1064,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -1102,14 +1158,16 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExcludeConstraintTableDef) constraintTableDef()    {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
-	IfNotExists  bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	IfNotExists   bool
+	Deferrability ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -1154,6 +1212,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(&node.Deferrability)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -1171,15 +1230,90 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 }
 
+// ExcludeConstraintTableDef represents an EXCLUDE constraint within a CREATE
+// TABLE statement.
+type ExcludeConstraintTableDef struct {
+	Name Name
+	// Using is the access method named in the USING clause, if any.
+	Using       string
+	Elems       ExcludeElemList
+	Predicate   Expr
+	IfNotExists bool
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExcludeConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// SetIfNotExists implements the ConstraintTableDef interface.
+func (node *ExcludeConstraintTableDef) SetIfNotExists() {
+	node.IfNotExists = true
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExcludeConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		if node.IfNotExists {
+			ctx.WriteString("IF NOT EXISTS ")
+		}
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.Using != "" {
+		ctx.WriteString("USING ")
+		ctx.WriteString(node.Using)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE (")
+		ctx.FormatNode(node.Predicate)
+		ctx.WriteByte(')')
+	}
+}
+
+// ExcludeElem is an element of an EXCLUDE constraint: an indexed column or
+// expression and the operator used to compare it across two rows.
+type ExcludeElem struct {
+	IndexElem
+	Operator treecmp.ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExcludeElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.IndexElem)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExcludeElemList is a list of ExcludeElem.
+type ExcludeElemList []ExcludeElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExcludeElemList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name        Name
-	Table       TableName
-	FromCols    NameList
-	ToCols      NameList
-	Actions     ReferenceActions
-	Match       CompositeKeyMatchMethod
-	IfNotExists bool
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	IfNotExists   bool
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -1210,6 +1344,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(&node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1439,6 +1574,38 @@ func (node *RangePartition) Format(ctx *FmtCtx) {
 	}
 }
 
+// PartitionBoundSpec represents the bound of a partition in CREATE TABLE ...
+// PARTITION OF and ALTER TABLE ... ATTACH PARTITION. Exactly one of Default,
+// In, or From and To is set.
+type PartitionBoundSpec struct {
+	// Default is true for a DEFAULT partition, which contains the rows that do
+	// not belong to any other list partition.
+	Default bool
+	// In contains the values of a list partition.
+	In Exprs
+	// From and To contain the bounds of a range partition.
+	From Exprs
+	To   Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PartitionBoundSpec) Format(ctx *FmtCtx) {
+	switch {
+	case node.Default:
+		ctx.WriteString(`DEFAULT`)
+	case node.In != nil:
+		ctx.WriteString(`FOR VALUES IN (`)
+		ctx.FormatNode(&node.In)
+		ctx.WriteByte(')')
+	default:
+		ctx.WriteString(`FOR VALUES FROM (`)
+		ctx.FormatNode(&node.From)
+		ctx.WriteString(`) TO (`)
+		ctx.FormatNode(&node.To)
+		ctx.WriteByte(')')
+	}
+}
+
 // StorageParam is a key-value parameter for table storage.
 type StorageParam struct {
 	Key   Name
@@ -1502,6 +1669,10 @@ type CreateTable struct {
 	Defs     TableDefs
 	AsSource *Select
 	Locality *Locality
+	// PartitionOf is set in CREATE TABLE ... PARTITION OF queries to the
+	// partitioned table, and PartitionBound to the bound of the new partition.
+	PartitionOf    *TableName
+	PartitionBound *PartitionBoundSpec
 }
 
 // As returns true if this table represents a CREATE TABLE ... AS statement,
@@ -1545,7 +1716,12 @@ func (node *CreateTable) Format(ctx *FmtCtx) {
 // FormatBody formats the "body" of the create table definition - everything
 // but the CREATE TABLE tableName part.
 func (node *CreateTable) FormatBody(ctx *FmtCtx) {
-	if node.As() {
+	if node.PartitionOf != nil {
+		ctx.WriteString(" PARTITION OF ")
+		ctx.FormatNode(node.PartitionOf)
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.PartitionBound)
+	} else if node.As() {
 		if len(node.Defs) > 0 {
 			ctx.WriteString(" (")
 			ctx.FormatNode(&node.Defs)
@@ -1622,12 +1798,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -1952,6 +2129,8 @@ type CreateView struct {
 	Replace      bool
 	Materialized bool
 	WithData     bool
+	// StorageParams are the storage parameters of a materialized view.
+	StorageParams StorageParams
 }
 
 // Format implements the NodeFormatter interface.
@@ -1984,6 +2163,12 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 		ctx.WriteByte(')')
 	}
 
+	if node.StorageParams != nil {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.StorageParams)
+		ctx.WriteString(")")
+	}
+
 	ctx.WriteString(" AS ")
 	ctx.FormatNode(node.AsSource)
 	if node.Materialized && node.WithData {
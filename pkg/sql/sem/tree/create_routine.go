@@ -205,6 +205,9 @@ func (RoutineVolatility) routineOption()        {}
 func (RoutineLeakproof) routineOption()         {}
 func (RoutineBodyStr) routineOption()           {}
 func (RoutineLanguage) routineOption()          {}
+func (RoutineSecurity) routineOption()          {}
+func (RoutineSetConfig) routineOption()         {}
+func (RoutineResetConfig) routineOption()       {}
 
 // RoutineNullInputBehavior represent the UDF property on null parameters.
 type RoutineNullInputBehavior int
@@ -277,6 +280,70 @@ func (node RoutineLeakproof) Format(ctx *FmtCtx) {
 	ctx.WriteString("LEAKPROOF")
 }
 
+// RoutineSecurity indicates the privileges with which a routine is executed.
+type RoutineSecurity int
+
+const (
+	// RoutineInvoker indicates that the routine is executed with the
+	// privileges of the user that calls it. This is the default if no security
+	// option is provided.
+	RoutineInvoker RoutineSecurity = iota
+	// RoutineDefiner indicates that the routine is executed with the
+	// privileges of the user that owns it.
+	RoutineDefiner
+)
+
+// Format implements the NodeFormatter interface.
+func (node RoutineSecurity) Format(ctx *FmtCtx) {
+	switch node {
+	case RoutineInvoker:
+		ctx.WriteString("SECURITY INVOKER")
+	case RoutineDefiner:
+		ctx.WriteString("SECURITY DEFINER")
+	default:
+		panic(pgerror.New(pgcode.InvalidParameterValue, "unknown routine option"))
+	}
+}
+
+// RoutineSetConfig represents a SET clause of a routine, which sets a session
+// variable for the duration of the routine's execution. A single DefaultVal
+// represents SET name TO DEFAULT, which removes the setting from the routine.
+type RoutineSetConfig struct {
+	Name   string
+	Values Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node RoutineSetConfig) Format(ctx *FmtCtx) {
+	ctx.WriteString("SET ")
+	ctx.WithFlags(ctx.flags & ^FmtAnonymize & ^FmtMarkRedactionNode, func() {
+		// Session var names never contain PII and should be distinguished
+		// for feature tracking purposes.
+		ctx.FormatNameP(&node.Name)
+	})
+	ctx.WriteString(" = ")
+	ctx.FormatNode(&node.Values)
+}
+
+// RoutineResetConfig represents a RESET clause of ALTER FUNCTION, which
+// removes a session variable setting (or all settings) from a routine.
+type RoutineResetConfig struct {
+	Name string
+	All  bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node RoutineResetConfig) Format(ctx *FmtCtx) {
+	if node.All {
+		ctx.WriteString("RESET ALL")
+		return
+	}
+	ctx.WriteString("RESET ")
+	ctx.WithFlags(ctx.flags & ^FmtAnonymize & ^FmtMarkRedactionNode, func() {
+		ctx.FormatNameP(&node.Name)
+	})
+}
+
 // RoutineLanguage indicates the language of the statements in the routine body.
 type RoutineLanguage string
 
@@ -397,10 +464,10 @@ const (
 )
 
 // IsInParamClass returns true if the given parameter class specifies an input
-// parameter (i.e. either IN or INOUT).
+// parameter (i.e. either IN, INOUT, or VARIADIC).
 func IsInParamClass(class RoutineParamClass) bool {
 	switch class {
-	case RoutineParamIn, RoutineParamInOut:
+	case RoutineParamIn, RoutineParamInOut, RoutineParamVariadic:
 		return true
 	default:
 		return false
@@ -427,8 +494,8 @@ func IsOutParamClass(class RoutineParamClass) bool {
 	}
 }
 
-// IsInParam returns true if the parameter is an input parameter (i.e. either IN
-// or INOUT).
+// IsInParam returns true if the parameter is an input parameter (i.e. either
+// IN, INOUT, or VARIADIC).
 func (node *RoutineParam) IsInParam() bool {
 	return IsInParamClass(node.Class)
 }
@@ -660,6 +727,7 @@ const (
 	TTLExpirationExpr               SchemaExprContext = "TTL EXPIRATION EXPRESSION"
 	TTLDefaultExpr                  SchemaExprContext = "TTL DEFAULT"
 	TTLUpdateExpr                   SchemaExprContext = "TTL UPDATE"
+	TriggerWhenExpr                 SchemaExprContext = "trigger WHEN condition"
 )
 
 func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
@@ -672,7 +740,7 @@ func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
 // ValidateRoutineOptions checks whether there are conflicting or redundant
 // routine options in the given slice.
 func ValidateRoutineOptions(options RoutineOptions, isProc bool) error {
-	var hasLang, hasBody, hasLeakProof, hasVolatility, hasNullInputBehavior bool
+	var hasLang, hasBody, hasLeakProof, hasVolatility, hasNullInputBehavior, hasSecurity bool
 	conflictingErr := func(opt RoutineOption) error {
 		return errors.Wrapf(ErrConflictingRoutineOption, "%s", AsString(opt))
 	}
@@ -712,6 +780,13 @@ func ValidateRoutineOptions(options RoutineOptions, isProc bool) error {
 				return conflictingErr(option)
 			}
 			hasNullInputBehavior = true
+		case RoutineSecurity:
+			if hasSecurity {
+				return conflictingErr(option)
+			}
+			hasSecurity = true
+		case RoutineSetConfig, RoutineResetConfig:
+			// Multiple settings are allowed; later ones take precedence.
 		default:
 			return pgerror.Newf(pgcode.InvalidParameterValue, "unknown function option: ", AsString(option))
 		}
@@ -0,0 +1,170 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     []*TriggerEvent
+	TableName  TableName
+	ForEach    TriggerForEach
+	When       Expr
+	FuncName   RoutineName
+	FuncArgs   []string
+}
+
+var _ Statement = &CreateTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	for i := range node.Events {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.FormatNode(node.Events[i])
+	}
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.TableName)
+	ctx.WriteString(" FOR EACH ")
+	ctx.WriteString(node.ForEach.String())
+	if node.When != nil {
+		ctx.WriteString(" WHEN (")
+		ctx.FormatNode(node.When)
+		ctx.WriteByte(')')
+	}
+	ctx.WriteString(" EXECUTE FUNCTION ")
+	ctx.FormatNode(&node.FuncName)
+	ctx.WriteByte('(')
+	for i := range node.FuncArgs {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(NewStrVal(node.FuncArgs[i]))
+	}
+	ctx.WriteByte(')')
+}
+
+// TriggerActionTime represents the activation time of a trigger: before the
+// triggering event, after it, or in place of it.
+type TriggerActionTime uint8
+
+const (
+	// TriggerActionTimeUnknown is the zero value of TriggerActionTime.
+	TriggerActionTimeUnknown TriggerActionTime = iota
+	// TriggerActionTimeBefore indicates a BEFORE trigger.
+	TriggerActionTimeBefore
+	// TriggerActionTimeAfter indicates an AFTER trigger.
+	TriggerActionTimeAfter
+	// TriggerActionTimeInsteadOf indicates an INSTEAD OF trigger.
+	TriggerActionTimeInsteadOf
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerActionTimeUnknown:   "UNKNOWN",
+	TriggerActionTimeBefore:    "BEFORE",
+	TriggerActionTimeAfter:     "AFTER",
+	TriggerActionTimeInsteadOf: "INSTEAD OF",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEventType represents the kind of statement that fires a trigger.
+type TriggerEventType uint8
+
+const (
+	// TriggerEventInsert indicates an INSERT event.
+	TriggerEventInsert TriggerEventType = iota
+	// TriggerEventUpdate indicates an UPDATE event.
+	TriggerEventUpdate
+	// TriggerEventDelete indicates a DELETE event.
+	TriggerEventDelete
+	// TriggerEventTruncate indicates a TRUNCATE event.
+	TriggerEventTruncate
+)
+
+var triggerEventTypeName = [...]string{
+	TriggerEventInsert:   "INSERT",
+	TriggerEventUpdate:   "UPDATE",
+	TriggerEventDelete:   "DELETE",
+	TriggerEventTruncate: "TRUNCATE",
+}
+
+func (t TriggerEventType) String() string {
+	return triggerEventTypeName[t]
+}
+
+// TriggerEvent represents one of the events that fires a trigger. Columns is
+// only set for UPDATE OF events.
+type TriggerEvent struct {
+	EventType TriggerEventType
+	Columns   NameList
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvent) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.EventType.String())
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Columns)
+	}
+}
+
+// TriggerForEach indicates whether a trigger fires once for each modified row
+// or once for each statement.
+type TriggerForEach uint8
+
+const (
+	// TriggerForEachStatement indicates a statement-level trigger. This is the
+	// default.
+	TriggerForEachStatement TriggerForEach = iota
+	// TriggerForEachRow indicates a row-level trigger.
+	TriggerForEachRow
+)
+
+func (t TriggerForEach) String() string {
+	if t == TriggerForEachRow {
+		return "ROW"
+	}
+	return "STATEMENT"
+}
+
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	IfExists     bool
+	Trigger      Name
+	Table        TableName
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Trigger)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
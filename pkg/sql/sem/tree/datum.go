@@ -39,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/stringencoding"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
@@ -3755,6 +3756,286 @@ func (d *DPGLSN) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DRange is the Datum for range types. A range is either empty, or it contains
+// the values between its lower and upper bounds. Ranges are always
+// canonicalized when they are created; see NewDRange.
+type DRange struct {
+	typ *types.T
+	// Lower and Upper are the bounds of the range. A bound is DNull if the
+	// range is unbounded on that side, or if the range is empty.
+	Lower, Upper Datum
+	// LowerInc and UpperInc are true if the respective bound is inclusive. They
+	// are always false for unbounded sides.
+	LowerInc, UpperInc bool
+	// Empty is true if the range contains no values.
+	Empty bool
+}
+
+// AsDRange attempts to retrieve a *DRange from an Expr, returning a *DRange
+// and a flag signifying whether the assertion was successful. The function
+// should be used instead of direct type assertions wherever a *DRange wrapped
+// by a *DOidWrapper is possible.
+func AsDRange(e Expr) (*DRange, bool) {
+	switch t := e.(type) {
+	case *DRange:
+		return t, true
+	case *DOidWrapper:
+		return AsDRange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDRange attempts to retrieve a *DRange from an Expr, panicking if the
+// assertion fails.
+func MustBeDRange(e Expr) *DRange {
+	r, ok := AsDRange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DRange, found %T", e))
+	}
+	return r
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DRange) ResolvedType() *types.T {
+	return d.typ
+}
+
+// Compare implements the Datum interface.
+func (d *DRange) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DRange) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DRange)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return compareRanges(d, v), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DRange) Prev(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DRange) Next(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DRange) IsMax(_ CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DRange) IsMin(_ CompareContext) bool {
+	return d.Empty
+}
+
+// Max implements the Datum interface.
+func (d *DRange) Max(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DRange) Min(_ CompareContext) (Datum, bool) {
+	return NewDEmptyRange(d.typ), true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DRange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DRange) Format(ctx *FmtCtx) {
+	if ctx.HasFlags(fmtPgwireFormat) {
+		d.pgwireFormat(ctx)
+		return
+	}
+	bareStrings := ctx.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	str := AsStringWithFlags(d, FmtPgwireText, FmtDataConversionConfig(ctx.dataConversionConfig), FmtLocation(ctx.location))
+	if !bareStrings {
+		str = strings.ReplaceAll(str, `'`, `''`)
+	}
+	ctx.WriteString(str)
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DRange) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.Lower.Size() + d.Upper.Size()
+}
+
+// IsComposite implements the CompositeDatum interface.
+func (d *DRange) IsComposite() bool {
+	for _, bound := range []Datum{d.Lower, d.Upper} {
+		if cdatum, ok := bound.(CompositeDatum); ok && cdatum.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
+// DMultirange is the Datum for multirange types. A multirange is an ordered
+// list of non-empty ranges which neither overlap nor are adjacent to each
+// other; see NewDMultirange.
+type DMultirange struct {
+	typ    *types.T
+	Ranges []*DRange
+}
+
+// AsDMultirange attempts to retrieve a *DMultirange from an Expr, returning a
+// *DMultirange and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DMultirange wrapped by a *DOidWrapper is possible.
+func AsDMultirange(e Expr) (*DMultirange, bool) {
+	switch t := e.(type) {
+	case *DMultirange:
+		return t, true
+	case *DOidWrapper:
+		return AsDMultirange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDMultirange attempts to retrieve a *DMultirange from an Expr,
+// panicking if the assertion fails.
+func MustBeDMultirange(e Expr) *DMultirange {
+	m, ok := AsDMultirange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DMultirange, found %T", e))
+	}
+	return m
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DMultirange) ResolvedType() *types.T {
+	return d.typ
+}
+
+// Compare implements the Datum interface.
+func (d *DMultirange) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DMultirange) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DMultirange)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	n := len(d.Ranges)
+	if len(v.Ranges) < n {
+		n = len(v.Ranges)
+	}
+	for i := 0; i < n; i++ {
+		if cmp := compareRanges(d.Ranges[i], v.Ranges[i]); cmp != 0 {
+			return cmp, nil
+		}
+	}
+	switch {
+	case len(d.Ranges) < len(v.Ranges):
+		return -1, nil
+	case len(d.Ranges) > len(v.Ranges):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Prev implements the Datum interface.
+func (d *DMultirange) Prev(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DMultirange) Next(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DMultirange) IsMax(_ CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DMultirange) IsMin(_ CompareContext) bool {
+	return len(d.Ranges) == 0
+}
+
+// Max implements the Datum interface.
+func (d *DMultirange) Max(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DMultirange) Min(_ CompareContext) (Datum, bool) {
+	return &DMultirange{typ: d.typ}, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DMultirange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DMultirange) Format(ctx *FmtCtx) {
+	if ctx.HasFlags(fmtPgwireFormat) {
+		d.pgwireFormat(ctx)
+		return
+	}
+	bareStrings := ctx.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	str := AsStringWithFlags(d, FmtPgwireText, FmtDataConversionConfig(ctx.dataConversionConfig), FmtLocation(ctx.location))
+	if !bareStrings {
+		str = strings.ReplaceAll(str, `'`, `''`)
+	}
+	ctx.WriteString(str)
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DMultirange) Size() uintptr {
+	sz := unsafe.Sizeof(*d)
+	for _, r := range d.Ranges {
+		sz += r.Size()
+	}
+	return sz
+}
+
+// IsComposite implements the CompositeDatum interface.
+func (d *DMultirange) IsComposite() bool {
+	for _, r := range d.Ranges {
+		if r.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
 // DBox2D is the Datum representation of the Box2D type.
 type DBox2D struct {
 	geo.CartesianBoundingBox
@@ -4038,7 +4319,7 @@ func AsJSON(
 		// This is RFC3339Nano, but without the TZ fields.
 		return json.FromString(formatTime(t.UTC(), "2006-01-02T15:04:05.999999999")), nil
 	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DBox2D,
-		*DTSVector, *DTSQuery, *DPGLSN:
+		*DTSVector, *DTSQuery, *DJsonpath, *DPGLSN:
 		return json.FromString(
 			AsStringWithFlags(t, FmtBareStrings, FmtDataConversionConfig(dcc), FmtLocation(loc)),
 		), nil
@@ -4159,6 +4440,131 @@ func (d *DJSON) Size() uintptr {
 	return unsafe.Sizeof(*d) + d.JSON.Size()
 }
 
+// DJsonpath is the jsonpath Datum.
+type DJsonpath struct {
+	jsonpath.Path
+}
+
+// Format implements the NodeFormatter interface.
+func (d *DJsonpath) Format(ctx *FmtCtx) {
+	bareStrings := ctx.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	str := d.Path.String()
+	if !bareStrings {
+		str = strings.ReplaceAll(str, `'`, `''`)
+	}
+	ctx.WriteString(str)
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DJsonpath) ResolvedType() *types.T {
+	return types.Jsonpath
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (d *DJsonpath) AmbiguousFormat() bool { return true }
+
+// Compare implements the Datum interface.
+func (d *DJsonpath) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DJsonpath) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DJsonpath)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return strings.Compare(d.String(), v.String()), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DJsonpath) Prev(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DJsonpath) Next(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMin implements the Datum interface.
+func (d *DJsonpath) IsMin(_ CompareContext) bool {
+	return false
+}
+
+// IsMax implements the Datum interface.
+func (d *DJsonpath) IsMax(_ CompareContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (d *DJsonpath) Max(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DJsonpath) Min(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Size implements the Datum interface.
+func (d *DJsonpath) Size() uintptr {
+	return uintptr(len(d.Path.String()))
+}
+
+// AsDJsonpath attempts to retrieve a DJsonpath from an Expr, returning a
+// DJsonpath and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DJsonpath wrapped by a *DOidWrapper is possible.
+func AsDJsonpath(e Expr) (*DJsonpath, bool) {
+	switch t := e.(type) {
+	case *DJsonpath:
+		return t, true
+	case *DOidWrapper:
+		return AsDJsonpath(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDJsonpath attempts to retrieve a DJsonpath from an Expr, panicking if
+// the assertion fails.
+func MustBeDJsonpath(e Expr) *DJsonpath {
+	v, ok := AsDJsonpath(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DJsonpath, found %T", e))
+	}
+	return v
+}
+
+// NewDJsonpath is a helper routine to create a DJsonpath initialized from its
+// argument.
+func NewDJsonpath(p jsonpath.Path) *DJsonpath {
+	return &DJsonpath{Path: p}
+}
+
+// ParseDJsonpath takes a string of jsonpath and returns a DJsonpath value.
+func ParseDJsonpath(s string) (Datum, error) {
+	p, err := jsonpath.Parse(s)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.Syntax, "could not parse jsonpath")
+	}
+	return NewDJsonpath(*p), nil
+}
+
 // DTSQuery is the tsquery Datum.
 type DTSQuery struct {
 	tsearch.TSQuery
@@ -6161,14 +6567,19 @@ var baseDatumTypeSizes = map[types.Family]struct {
 	types.TimestampTZFamily:    {unsafe.Sizeof(DTimestampTZ{}), fixedSize},
 	types.TSQueryFamily:        {unsafe.Sizeof(DTSQuery{}), variableSize},
 	types.TSVectorFamily:       {unsafe.Sizeof(DTSVector{}), variableSize},
+	types.JsonpathFamily:       {unsafe.Sizeof(DJsonpath{}), variableSize},
 	types.IntervalFamily:       {unsafe.Sizeof(DInterval{}), fixedSize},
 	types.JsonFamily:           {unsafe.Sizeof(DJSON{}), variableSize},
 	types.UuidFamily:           {unsafe.Sizeof(DUuid{}), fixedSize},
 	types.INetFamily:           {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	types.OidFamily:            {unsafe.Sizeof(DOid{}.Oid), fixedSize},
 	types.EnumFamily:           {unsafe.Sizeof(DEnum{}), variableSize},
+	types.RangeFamily:          {unsafe.Sizeof(DRange{}), variableSize},
+	types.MultirangeFamily:     {unsafe.Sizeof(DMultirange{}), variableSize},
 
 	types.VoidFamily: {sz: unsafe.Sizeof(DVoid{}), variable: fixedSize},
+	// There are no values of the trigger pseudo-type.
+	types.TriggerFamily: {sz: 0, variable: fixedSize},
 	// TODO(jordan,justin): This seems suspicious.
 	types.ArrayFamily: {unsafe.Sizeof(DString("")), variableSize},
 
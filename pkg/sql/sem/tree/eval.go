@@ -1454,6 +1454,49 @@ func cmpOpFixups(
 		})
 	}
 
+	// Range and multirange comparisons.
+	appendCmpOp := func(sym treecmp.ComparisonOperatorSymbol, cmpOp *CmpOp) {
+		s, ok := cmpOps[sym]
+		if !ok {
+			s = new(CmpOpOverloads)
+			cmpOps[sym] = s
+		}
+		s.overloads = append(s.overloads, cmpOp)
+	}
+	appendRangeOp := func(sym treecmp.ComparisonOperatorSymbol, l, r *types.T, op func(left, right Datum) bool) {
+		appendCmpOp(sym, &CmpOp{
+			LeftType:   l,
+			RightType:  r,
+			EvalOp:     &CompareRangeOp{Op: op},
+			Volatility: volatility.Immutable,
+		})
+	}
+	for i, r := range types.RangeTypes {
+		m := types.MultirangeTypes[i]
+		e := r.RangeContents()
+		for _, t := range []*types.T{r, m} {
+			appendCmpOp(treecmp.EQ, makeEqFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.LT, makeLtFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.LE, makeLeFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.IsNotDistinctFrom, makeIsFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.In, makeEvalTupleIn(t, volatility.Immutable))
+			appendRangeOp(treecmp.Contains, t, e, RangesContainElem)
+			appendRangeOp(treecmp.ContainedBy, e, t, func(left, right Datum) bool {
+				return RangesContainElem(right, left)
+			})
+		}
+		for _, lt := range []*types.T{r, m} {
+			for _, rt := range []*types.T{r, m} {
+				appendRangeOp(treecmp.Overlaps, lt, rt, RangesOverlap)
+				appendRangeOp(treecmp.Adjacent, lt, rt, RangesAdjacent)
+				appendRangeOp(treecmp.Contains, lt, rt, RangesContain)
+				appendRangeOp(treecmp.ContainedBy, lt, rt, func(left, right Datum) bool {
+					return RangesContain(right, left)
+				})
+			}
+		}
+	}
+
 	for _, overloads := range cmpOps {
 		_ = overloads.ForEachCmpOp(func(op *CmpOp) error {
 			op.types = ParamTypes{{"left", op.LeftType}, {"right", op.RightType}}
@@ -1962,6 +2005,20 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			EvalOp:     &TSMatchesVectorQueryOp{},
 			Volatility: volatility.Immutable,
 		},
+		{
+			LeftType:   types.Jsonb,
+			RightType:  types.Jsonpath,
+			EvalOp:     &JSONPathMatchOp{},
+			Volatility: volatility.Immutable,
+		},
+	}},
+	treecmp.JSONPathExists: {overloads: []*CmpOp{
+		{
+			LeftType:   types.Jsonb,
+			RightType:  types.Jsonpath,
+			EvalOp:     &JSONPathExistsOp{},
+			Volatility: volatility.Immutable,
+		},
 	}},
 })
 
@@ -32,6 +32,11 @@ type CompareBox2DOp struct {
 	Op func(left, right Datum) bool
 }
 
+// CompareRangeOp is a BinaryEvalOp.
+type CompareRangeOp struct {
+	Op func(left, right Datum) bool
+}
+
 // InTupleOp is a BinaryEvalOp.
 type InTupleOp struct{}
 
@@ -81,6 +86,12 @@ type TSMatchesVectorQueryOp struct{}
 // TSMatchesQueryVectorOp is a BinaryEvalOp.
 type TSMatchesQueryVectorOp struct{}
 
+// JSONPathExistsOp is a BinaryEvalOp.
+type JSONPathExistsOp struct{}
+
+// JSONPathMatchOp is a BinaryEvalOp.
+type JSONPathMatchOp struct{}
+
 // AppendToMaybeNullArrayOp is a BinaryEvalOp.
 type AppendToMaybeNullArrayOp struct {
 	Typ *types.T
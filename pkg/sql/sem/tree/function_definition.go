@@ -263,7 +263,9 @@ func (fd *ResolvedFunctionDefinition) MatchOverload(
 ) (QualifiedOverload, error) {
 	matched := func(ol QualifiedOverload, schema string) bool {
 		if ol.Type == UDFRoutine || ol.Type == ProcedureRoutine {
-			return schema == ol.Schema && (paramTypes == nil || ol.params().MatchIdentical(paramTypes))
+			// Routines are matched against their declared signature, so a VARIADIC
+			// parameter is referenced by its array type.
+			return schema == ol.Schema && (paramTypes == nil || ol.Types.MatchIdentical(paramTypes))
 		}
 		return schema == ol.Schema && (paramTypes == nil || ol.params().Match(paramTypes))
 	}
@@ -18,8 +18,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
@@ -272,10 +274,27 @@ type Overload struct {
 	// the signature of the function), RoutineParams contains all parameters as
 	// well as their class.
 	RoutineParams RoutineParams
+	// Variadic is true if the last parameter in Types is a VARIADIC parameter
+	// of an array type, in which case the routine accepts one or more arguments
+	// of the element type of the array in its place. Only used for UDFs.
+	Variadic bool
+	// Security is the security mode of the routine. Only used for UDFs.
+	Security RoutineSecurity
+	// Owner is the owner of the routine. It is only set for UDFs, and is used
+	// to determine the effective user for SECURITY DEFINER routines.
+	Owner username.SQLUsername
+	// Config contains the session variable settings of the routine, as
+	// "name=value" strings. Only used for UDFs.
+	Config []string
 }
 
 // params implements the overloadImpl interface.
-func (b Overload) params() TypeList { return b.Types }
+func (b Overload) params() TypeList {
+	if b.Variadic {
+		return makeVariadicRoutineParams(b.Types)
+	}
+	return b.Types
+}
 
 // returnType implements the overloadImpl interface.
 func (b Overload) returnType() ReturnTyper { return b.ReturnType }
@@ -566,8 +585,8 @@ func (v VariadicType) MatchAt(typ *types.T, i int) bool {
 	return typ.Family() == types.UnknownFamily || v.VarType.Equivalent(typ)
 }
 
-// MatchAtIdentical is part of the TypeList interface.
-// TODO(mgartner): This will be incorrect once we add support for variadic UDFs
+// MatchAtIdentical is part of the TypeList interface. Note that variadic UDFs
+// use variadicRoutineParams, which matches identical types.
 func (VariadicType) MatchAtIdentical(typ *types.T, i int) bool {
 	return true
 }
@@ -613,6 +632,59 @@ func (v VariadicType) String() string {
 	return s.String()
 }
 
+// variadicRoutineParams is the TypeList of a user-defined routine with a
+// VARIADIC parameter, which is used to match the arguments of an invocation of
+// the routine. Unlike VariadicType, at least one argument must be supplied in
+// place of the VARIADIC parameter.
+type variadicRoutineParams struct {
+	VariadicType
+}
+
+var _ TypeList = variadicRoutineParams{}
+
+// makeVariadicRoutineParams returns the variadicRoutineParams for the given
+// signature of a routine, the last type of which is the array type of the
+// VARIADIC parameter.
+func makeVariadicRoutineParams(signature TypeList) variadicRoutineParams {
+	typs := signature.Types()
+	n := len(typs) - 1
+	return variadicRoutineParams{VariadicType{
+		FixedTypes: typs[:n],
+		VarType:    typs[n].ArrayContents(),
+	}}
+}
+
+// Match is part of the TypeList interface.
+func (v variadicRoutineParams) Match(types []*types.T) bool {
+	return v.MatchLen(len(types)) && v.VariadicType.Match(types)
+}
+
+// MatchIdentical is part of the TypeList interface.
+func (v variadicRoutineParams) MatchIdentical(types []*types.T) bool {
+	if !v.MatchLen(len(types)) {
+		return false
+	}
+	for i := range types {
+		if !v.MatchAtIdentical(types[i], i) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchAtIdentical is part of the TypeList interface.
+func (v variadicRoutineParams) MatchAtIdentical(typ *types.T, i int) bool {
+	if i < len(v.FixedTypes) {
+		return typ.Family() == types.UnknownFamily || v.FixedTypes[i].Identical(typ)
+	}
+	return typ.Family() == types.UnknownFamily || v.VarType.Identical(typ)
+}
+
+// MatchLen is part of the TypeList interface.
+func (v variadicRoutineParams) MatchLen(l int) bool {
+	return l > len(v.FixedTypes)
+}
+
 // UnknownReturnType is returned from ReturnTypers when the arguments provided are
 // not sufficient to determine a return type. This is necessary for cases like overload
 // resolution, where the argument types are not resolved yet so the type-level function
@@ -684,6 +756,303 @@ func returnTypeToFixedType(s ReturnTyper, inputTyps []TypedExpr) *types.T {
 	return types.Any
 }
 
+// polymorphicKind identifies the polymorphic pseudo-types that can be used in
+// the signature of a user-defined routine.
+type polymorphicKind int
+
+const (
+	notPolymorphic polymorphicKind = iota
+	polymorphicAnyElement
+	polymorphicAnyArray
+	polymorphicAnyCompatible
+	polymorphicAnyCompatibleArray
+)
+
+func getPolymorphicKind(typ *types.T) polymorphicKind {
+	switch typ.Family() {
+	case types.AnyFamily:
+		if typ.Oid() == types.AnyCompatible.Oid() {
+			return polymorphicAnyCompatible
+		}
+		return polymorphicAnyElement
+	case types.ArrayFamily:
+		if typ.ArrayContents().Family() != types.AnyFamily {
+			return notPolymorphic
+		}
+		if typ.Oid() == types.AnyCompatibleArray.Oid() {
+			return polymorphicAnyCompatibleArray
+		}
+		return polymorphicAnyArray
+	}
+	return notPolymorphic
+}
+
+// IsPolymorphicType returns true if the given type is one of the polymorphic
+// pseudo-types anyelement, anyarray, anycompatible, or anycompatiblearray.
+func IsPolymorphicType(typ *types.T) bool {
+	return getPolymorphicKind(typ) != notPolymorphic
+}
+
+// ContainsPolymorphicType returns true if the given type is a polymorphic
+// pseudo-type, or a tuple containing a polymorphic pseudo-type (e.g., the
+// return type of a routine with multiple OUT parameters).
+func ContainsPolymorphicType(typ *types.T) bool {
+	if typ.Family() == types.TupleFamily {
+		for _, t := range typ.TupleContents() {
+			if IsPolymorphicType(t) {
+				return true
+			}
+		}
+		return false
+	}
+	return IsPolymorphicType(typ)
+}
+
+// PolymorphicTypes holds the concrete types that the polymorphic parameters of
+// a routine resolve to for a specific invocation. A nil type indicates that no
+// argument determines the type.
+type PolymorphicTypes struct {
+	// AnyElement is the type of anyelement parameters. anyarray parameters are
+	// arrays of AnyElement.
+	AnyElement *types.T
+	// AnyCompatible is the common type of anycompatible parameters.
+	// anycompatiblearray parameters are arrays of AnyCompatible.
+	AnyCompatible *types.T
+}
+
+// ResolvePolymorphicTypes determines the concrete types of the polymorphic
+// parameters in params from the types of the given arguments, the same way as
+// Postgres:
+//
+//   - All arguments of anyelement parameters must have the same type, and all
+//     arguments of anyarray parameters must be arrays of that type.
+//   - Arguments of anycompatible parameters and the elements of arguments of
+//     anycompatiblearray parameters are coerced to their common type.
+//
+// Arguments of unknown type (i.e., NULL) do not determine a type. An error is
+// returned if the types of the arguments are not consistent.
+func ResolvePolymorphicTypes(params TypeList, argTypes []*types.T) (PolymorphicTypes, error) {
+	var res PolymorphicTypes
+	var compatTypes []*types.T
+	for i, argType := range argTypes {
+		paramType := params.GetAt(i)
+		if paramType == nil || argType.Family() == types.UnknownFamily {
+			continue
+		}
+		kind := getPolymorphicKind(paramType)
+		elemType := argType
+		switch kind {
+		case notPolymorphic:
+			continue
+		case polymorphicAnyArray, polymorphicAnyCompatibleArray:
+			if argType.Family() != types.ArrayFamily {
+				return PolymorphicTypes{}, pgerror.Newf(pgcode.DatatypeMismatch,
+					"argument declared %s is not an array but type %s",
+					paramType.Name(), argType.Name(),
+				)
+			}
+			elemType = argType.ArrayContents()
+		}
+		switch kind {
+		case polymorphicAnyElement, polymorphicAnyArray:
+			if res.AnyElement == nil {
+				res.AnyElement = elemType
+			} else if !res.AnyElement.Equivalent(elemType) {
+				return PolymorphicTypes{}, pgerror.Newf(pgcode.DatatypeMismatch,
+					"arguments declared anyelement are not all alike: %s versus %s",
+					res.AnyElement.Name(), elemType.Name(),
+				)
+			}
+		case polymorphicAnyCompatible, polymorphicAnyCompatibleArray:
+			compatTypes = append(compatTypes, elemType)
+		}
+	}
+	if len(compatTypes) > 0 {
+		// Choose the common type in the same way as for UNION and CASE: if the
+		// candidate type can be implicitly cast to another type, but not
+		// vice-versa, the other type becomes the new candidate.
+		candidate := compatTypes[0]
+		for _, typ := range compatTypes[1:] {
+			if cast.ValidCast(candidate, typ, cast.ContextImplicit) &&
+				!cast.ValidCast(typ, candidate, cast.ContextImplicit) {
+				candidate = typ
+			}
+		}
+		for _, typ := range compatTypes {
+			if !typ.Equivalent(candidate) && !cast.ValidCast(typ, candidate, cast.ContextImplicit) {
+				return PolymorphicTypes{}, pgerror.Newf(pgcode.DatatypeMismatch,
+					"arguments declared anycompatible cannot be cast to a common type: %s versus %s",
+					candidate.Name(), typ.Name(),
+				)
+			}
+		}
+		res.AnyCompatible = candidate
+	}
+	return res, nil
+}
+
+// Resolve returns the given type with polymorphic types replaced by their
+// concrete types. It returns nil if a polymorphic type could not be resolved.
+func (p PolymorphicTypes) Resolve(typ *types.T) *types.T {
+	switch getPolymorphicKind(typ) {
+	case polymorphicAnyElement:
+		return p.AnyElement
+	case polymorphicAnyArray:
+		if p.AnyElement == nil {
+			return nil
+		}
+		return types.MakeArray(p.AnyElement)
+	case polymorphicAnyCompatible:
+		return p.AnyCompatible
+	case polymorphicAnyCompatibleArray:
+		if p.AnyCompatible == nil {
+			return nil
+		}
+		return types.MakeArray(p.AnyCompatible)
+	}
+	if typ.Family() == types.TupleFamily && ContainsPolymorphicType(typ) {
+		contents := make([]*types.T, len(typ.TupleContents()))
+		for i, t := range typ.TupleContents() {
+			if contents[i] = p.Resolve(t); contents[i] == nil {
+				return nil
+			}
+		}
+		return types.MakeLabeledTuple(contents, typ.TupleLabels())
+	}
+	return typ
+}
+
+// PolymorphicReturnType returns a ReturnTyper for a user-defined routine with
+// the given signature and a return type that contains polymorphic types. The
+// concrete return type is determined from the types of the arguments.
+func PolymorphicReturnType(signature TypeList, variadic bool, typ *types.T) ReturnTyper {
+	params := signature
+	if variadic {
+		params = makeVariadicRoutineParams(signature)
+	}
+	return func(args []TypedExpr) *types.T {
+		if len(args) == 0 {
+			return UnknownReturnType
+		}
+		argTypes := make([]*types.T, len(args))
+		for i := range args {
+			argTypes[i] = args[i].ResolvedType()
+		}
+		p, err := ResolvePolymorphicTypes(params, argTypes)
+		if err != nil {
+			return UnknownReturnType
+		}
+		if resolved := p.Resolve(typ); resolved != nil {
+			return resolved
+		}
+		return UnknownReturnType
+	}
+}
+
+// CheckPolymorphicReturnType returns an error if the given return type of a
+// user-defined routine contains polymorphic types that cannot be determined
+// from the types of its input parameters.
+func CheckPolymorphicReturnType(paramTypes []*types.T, retType *types.T) error {
+	if !ContainsPolymorphicType(retType) {
+		return nil
+	}
+	// Use placeholder types to determine which polymorphic types are resolved
+	// by the parameters.
+	var p PolymorphicTypes
+	for _, typ := range paramTypes {
+		switch getPolymorphicKind(typ) {
+		case polymorphicAnyElement, polymorphicAnyArray:
+			p.AnyElement = types.Any
+		case polymorphicAnyCompatible, polymorphicAnyCompatibleArray:
+			p.AnyCompatible = types.AnyCompatible
+		}
+	}
+	if p.Resolve(retType) == nil {
+		return errors.WithDetailf(
+			pgerror.New(pgcode.InvalidFunctionDefinition, "cannot determine result data type"),
+			"A result of type %s requires at least one input of a polymorphic type of the same family.",
+			retType.Name(),
+		)
+	}
+	return nil
+}
+
+// ResolvePolymorphicTypes returns the concrete types of the polymorphic
+// parameters of the routine for the given type-checked arguments.
+func (b *Overload) ResolvePolymorphicTypes(args []TypedExpr) (PolymorphicTypes, error) {
+	argTypes := make([]*types.T, len(args))
+	for i := range args {
+		argTypes[i] = args[i].ResolvedType()
+	}
+	return ResolvePolymorphicTypes(b.params(), argTypes)
+}
+
+// typeCheckPolymorphicArgs checks that the arguments of an invocation of a
+// user-defined routine with polymorphic parameters determine the concrete
+// types of those parameters, and casts the arguments of anycompatible and
+// anycompatiblearray parameters to their common type.
+func typeCheckPolymorphicArgs(ol *Overload, typedExprs []TypedExpr) error {
+	params := ol.params()
+	hasPolymorphicParams := false
+	for _, typ := range params.Types() {
+		if IsPolymorphicType(typ) {
+			hasPolymorphicParams = true
+			break
+		}
+	}
+	if !hasPolymorphicParams {
+		return nil
+	}
+	p, err := ol.ResolvePolymorphicTypes(typedExprs)
+	if err != nil {
+		return err
+	}
+	for i, expr := range typedExprs {
+		paramType := params.GetAt(i)
+		kind := getPolymorphicKind(paramType)
+		if kind == notPolymorphic {
+			continue
+		}
+		typ := p.Resolve(paramType)
+		if typ == nil {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"could not determine polymorphic type because input has type unknown",
+			)
+		}
+		argType := expr.ResolvedType()
+		if argType.Family() == types.UnknownFamily || argType.Identical(typ) {
+			continue
+		}
+		if kind == polymorphicAnyCompatible || kind == polymorphicAnyCompatibleArray {
+			typedExprs[i] = NewTypedCastExpr(expr, typ)
+		}
+	}
+	return nil
+}
+
+// filterPolymorphicRoutineOverloads removes the overloads of user-defined
+// routines from the candidate overloads if the types of their polymorphic
+// parameters cannot be resolved consistently for the type-checked arguments.
+func (s *overloadTypeChecker) filterPolymorphicRoutineOverloads(overloads []QualifiedOverload) {
+	var argTypes []*types.T
+	truncated := s.overloadIdxs[:0]
+	for _, idx := range s.overloadIdxs {
+		if overloads[idx].Type != BuiltinRoutine {
+			if argTypes == nil {
+				argTypes = make([]*types.T, len(s.typedExprs))
+				for i := range s.typedExprs {
+					argTypes[i] = s.typedExprs[i].ResolvedType()
+				}
+			}
+			if _, err := ResolvePolymorphicTypes(s.params[idx], argTypes); err != nil {
+				continue
+			}
+		}
+		truncated = append(truncated, idx)
+	}
+	s.overloadIdxs = truncated
+}
+
 type overloadTypeChecker struct {
 	overloads       []overloadImpl
 	params          []TypeList
@@ -0,0 +1,215 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+const emptyRangeLiteral = "empty"
+
+func malformedRangeError(s, detail string) error {
+	return errors.WithDetail(
+		pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed range literal: %q", s),
+		detail,
+	)
+}
+
+func malformedMultirangeError(s, detail string) error {
+	return errors.WithDetail(
+		pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed multirange literal: %q", s),
+		detail,
+	)
+}
+
+type rangeParseState struct {
+	// s is the remaining input.
+	s                string
+	ctx              ParseContext
+	dependsOnContext bool
+}
+
+func (p *rangeParseState) eatWhitespace() {
+	p.s = strings.TrimLeftFunc(p.s, unicode.IsSpace)
+}
+
+// parseBound parses a bound of a range, up to but not including the
+// following comma or closing bracket. It returns false if the bound is
+// empty, which indicates an unbounded side of the range. Bounds can be
+// quoted with double quotes, and any character can be escaped with a
+// backslash.
+func (p *rangeParseState) parseBound() (_ string, ok bool, _ error) {
+	var b strings.Builder
+	inQuote := false
+	i := 0
+	for ; i < len(p.s); i++ {
+		ch := p.s[i]
+		if !inQuote && (ch == ',' || ch == ')' || ch == ']') {
+			break
+		}
+		switch ch {
+		case '\\':
+			i++
+			if i >= len(p.s) {
+				return "", false, errors.New("Unexpected end of input.")
+			}
+			b.WriteByte(p.s[i])
+		case '"':
+			if inQuote && i+1 < len(p.s) && p.s[i+1] == '"' {
+				// A doubled double quote within a quoted bound is a literal
+				// double quote.
+				b.WriteByte('"')
+				i++
+			} else {
+				inQuote = !inQuote
+			}
+			ok = true
+		default:
+			b.WriteByte(ch)
+			ok = true
+		}
+	}
+	if inQuote {
+		return "", false, errors.New("Unexpected end of input.")
+	}
+	p.s = p.s[i:]
+	return b.String(), ok, nil
+}
+
+// parseRange parses a range of the given type from the beginning of the
+// remaining input. If the range is part of a multirange, it stops after the
+// closing bracket; otherwise, it requires that only whitespace follows the
+// range.
+func (p *rangeParseState) parseRange(orig string, t *types.T) (*DRange, error) {
+	p.eatWhitespace()
+	if len(p.s) >= len(emptyRangeLiteral) &&
+		strings.EqualFold(p.s[:len(emptyRangeLiteral)], emptyRangeLiteral) {
+		p.s = p.s[len(emptyRangeLiteral):]
+		return NewDEmptyRange(t), nil
+	}
+	if len(p.s) == 0 || (p.s[0] != '[' && p.s[0] != '(') {
+		return nil, malformedRangeError(orig, "Missing left parenthesis or bracket.")
+	}
+	lowerInc := p.s[0] == '['
+	p.s = p.s[1:]
+
+	lower, err := p.parseBoundDatum(orig, t)
+	if err != nil {
+		return nil, err
+	}
+	if p.s[0] != ',' {
+		return nil, malformedRangeError(orig, "Missing comma after lower bound.")
+	}
+	p.s = p.s[1:]
+	upper, err := p.parseBoundDatum(orig, t)
+	if err != nil {
+		return nil, err
+	}
+	if p.s[0] == ',' {
+		return nil, malformedRangeError(orig, "Too many commas.")
+	}
+	upperInc := p.s[0] == ']'
+	p.s = p.s[1:]
+	return NewDRange(t, lower, upper, lowerInc, upperInc)
+}
+
+// parseBoundDatum parses a bound of a range of the given type, returning
+// DNull for an unbounded side. On success, the remaining input is not empty.
+func (p *rangeParseState) parseBoundDatum(orig string, t *types.T) (Datum, error) {
+	str, ok, err := p.parseBound()
+	if err != nil {
+		return nil, malformedRangeError(orig, err.Error())
+	}
+	if len(p.s) == 0 {
+		return nil, malformedRangeError(orig, "Unexpected end of input.")
+	}
+	if !ok {
+		return DNull, nil
+	}
+	d, dependsOnContext, err := ParseAndRequireString(t.RangeContents(), str, p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.dependsOnContext = p.dependsOnContext || dependsOnContext
+	return d, nil
+}
+
+// ParseDRangeFromString parses the string-form of a range of the given type,
+// such as '[1,10)'.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseContext (either for the time or the local timezone).
+func ParseDRangeFromString(
+	ctx ParseContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	p := rangeParseState{s: s, ctx: ctx}
+	r, err := p.parseRange(s, t)
+	if err != nil {
+		return nil, false, err
+	}
+	p.eatWhitespace()
+	if len(p.s) > 0 {
+		return nil, false, malformedRangeError(s, "Junk after right parenthesis or bracket.")
+	}
+	return r, p.dependsOnContext, nil
+}
+
+// ParseDMultirangeFromString parses the string-form of a multirange of the
+// given type, such as '{[1,3), [5,7)}'.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseContext (either for the time or the local timezone).
+func ParseDMultirangeFromString(
+	ctx ParseContext, s string, t *types.T,
+) (_ *DMultirange, dependsOnContext bool, _ error) {
+	p := rangeParseState{s: s, ctx: ctx}
+	p.eatWhitespace()
+	if len(p.s) == 0 || p.s[0] != '{' {
+		return nil, false, malformedMultirangeError(s, "Missing left brace.")
+	}
+	p.s = p.s[1:]
+	p.eatWhitespace()
+	var ranges []*DRange
+	if len(p.s) > 0 && p.s[0] == '}' {
+		p.s = p.s[1:]
+	} else {
+		for {
+			r, err := p.parseRange(s, t.RangeContents())
+			if err != nil {
+				return nil, false, err
+			}
+			ranges = append(ranges, r)
+			p.eatWhitespace()
+			if len(p.s) == 0 {
+				return nil, false, malformedMultirangeError(s, "Unexpected end of input.")
+			}
+			if p.s[0] == '}' {
+				p.s = p.s[1:]
+				break
+			}
+			if p.s[0] != ',' {
+				return nil, false, malformedMultirangeError(s, "Expected comma or end of multirange.")
+			}
+			p.s = p.s[1:]
+		}
+	}
+	p.eatWhitespace()
+	if len(p.s) > 0 {
+		return nil, false, malformedMultirangeError(s, "Junk after closing right brace.")
+	}
+	return NewDMultirange(t, ranges), p.dependsOnContext, nil
+}
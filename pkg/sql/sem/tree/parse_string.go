@@ -69,6 +69,10 @@ func ParseAndRequireString(
 		d, err = ParseDIntervalWithTypeMetadata(intervalStyle(ctx), s, itm)
 	case types.PGLSNFamily:
 		d, err = ParseDPGLSN(s)
+	case types.RangeFamily:
+		d, dependsOnContext, err = ParseDRangeFromString(ctx, s, t)
+	case types.MultirangeFamily:
+		d, dependsOnContext, err = ParseDMultirangeFromString(ctx, s, t)
 	case types.RefCursorFamily:
 		d = NewDRefCursor(s)
 	case types.Box2DFamily:
@@ -106,6 +110,8 @@ func ParseAndRequireString(
 		if err == nil {
 			d = NewDEnum(e)
 		}
+	case types.JsonpathFamily:
+		d, err = ParseDJsonpath(s)
 	case types.TSQueryFamily:
 		d, err = ParseDTSQuery(s)
 	case types.TSVectorFamily:
@@ -177,7 +177,62 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 	}
 }
 
-var tupleQuoteSet, arrayQuoteSet asciiSet
+func (d *DRange) pgwireFormat(ctx *FmtCtx) {
+	// Like for tuples, the bounds of a range are printed in "postgres mode",
+	// and then quoted if they contain special characters, with double quotes
+	// and backslashes doubled.
+	if d.Empty {
+		ctx.WriteString("empty")
+		return
+	}
+	if d.LowerInc {
+		ctx.WriteByte('[')
+	} else {
+		ctx.WriteByte('(')
+	}
+	d.pgwireFormatBound(ctx, d.Lower)
+	ctx.WriteByte(',')
+	d.pgwireFormatBound(ctx, d.Upper)
+	if d.UpperInc {
+		ctx.WriteByte(']')
+	} else {
+		ctx.WriteByte(')')
+	}
+}
+
+func (d *DRange) pgwireFormatBound(ctx *FmtCtx, bound Datum) {
+	if bound == DNull {
+		// Unbounded sides are represented by an empty bound.
+		return
+	}
+	s := AsStringWithFlags(bound, ctx.flags, FmtDataConversionConfig(ctx.dataConversionConfig), FmtLocation(ctx.location))
+	quote := s == "" || rangeQuoteSet.in(s)
+	if quote {
+		ctx.WriteByte('"')
+	}
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			ctx.WriteByte(byte(r))
+		}
+		ctx.WriteRune(r)
+	}
+	if quote {
+		ctx.WriteByte('"')
+	}
+}
+
+func (d *DMultirange) pgwireFormat(ctx *FmtCtx) {
+	ctx.WriteByte('{')
+	for i, r := range d.Ranges {
+		if i > 0 {
+			ctx.WriteByte(',')
+		}
+		r.pgwireFormat(ctx)
+	}
+	ctx.WriteByte('}')
+}
+
+var tupleQuoteSet, arrayQuoteSet, rangeQuoteSet asciiSet
 
 func init() {
 	var ok bool
@@ -185,6 +240,10 @@ func init() {
 	if !ok {
 		panic("tuple asciiset")
 	}
+	rangeQuoteSet, ok = makeASCIISet(" \t\v\f\r\n()[],\"\\")
+	if !ok {
+		panic("range asciiset")
+	}
 	arrayQuoteSet, ok = makeASCIISet(" \t\v\f\r\n{},\"\\")
 	if !ok {
 		panic("array asciiset")
@@ -0,0 +1,100 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreatePublication represents a CREATE PUBLICATION statement.
+type CreatePublication struct {
+	Name Name
+	// AllTables is true for FOR ALL TABLES publications, which publish the
+	// changes of all the tables of the database, including the tables which are
+	// created after the publication.
+	AllTables bool
+	Tables    TableNames
+}
+
+var _ Statement = &CreatePublication{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE PUBLICATION ")
+	ctx.FormatNode(&node.Name)
+	if node.AllTables {
+		ctx.WriteString(" FOR ALL TABLES")
+	} else if len(node.Tables) > 0 {
+		ctx.WriteString(" FOR TABLE ")
+		ctx.FormatNode(&node.Tables)
+	}
+}
+
+// DropPublication represents a DROP PUBLICATION statement.
+type DropPublication struct {
+	Names        NameList
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropPublication{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP PUBLICATION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// CreateSubscription represents a CREATE SUBSCRIPTION statement.
+type CreateSubscription struct {
+	Name Name
+	// ConnectionURI is the connection string of the cluster on which the
+	// publications are defined.
+	ConnectionURI Expr
+	Publications  NameList
+	Options       KVOptions
+}
+
+var _ Statement = &CreateSubscription{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SUBSCRIPTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" CONNECTION ")
+	ctx.FormatNode(node.ConnectionURI)
+	ctx.WriteString(" PUBLICATION ")
+	ctx.FormatNode(&node.Publications)
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
+// DropSubscription represents a DROP SUBSCRIPTION statement.
+type DropSubscription struct {
+	Name     Name
+	IfExists bool
+}
+
+var _ Statement = &DropSubscription{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP SUBSCRIPTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+}
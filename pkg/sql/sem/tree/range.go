@@ -0,0 +1,432 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// rangeCompareContext is the CompareContext used to compare the bounds of
+// ranges with each other and with elements of the range's subtype. Both sides
+// of these comparisons always have the same type, so their result does not
+// depend on the session.
+type rangeCompareContext struct{}
+
+var _ CompareContext = rangeCompareContext{}
+
+// UnwrapDatum is part of the CompareContext interface.
+func (rangeCompareContext) UnwrapDatum(d Datum) Datum {
+	return UnwrapDOidWrapper(d)
+}
+
+// GetLocation is part of the CompareContext interface.
+func (rangeCompareContext) GetLocation() *time.Location {
+	return time.UTC
+}
+
+// GetRelativeParseTime is part of the CompareContext interface.
+func (rangeCompareContext) GetRelativeParseTime() time.Time {
+	return time.Time{}
+}
+
+// MustGetPlaceholderValue is part of the CompareContext interface.
+func (rangeCompareContext) MustGetPlaceholderValue(p *Placeholder) Datum {
+	panic(errors.AssertionFailedf("unexpected placeholder %s in range", p))
+}
+
+var rangeCmpCtx CompareContext = rangeCompareContext{}
+
+// rangeBound is one of the bounds of a non-empty range.
+type rangeBound struct {
+	// val is the value of the bound, or DNull if the bound is infinite.
+	val       Datum
+	inclusive bool
+	lower     bool
+}
+
+func (b rangeBound) infinite() bool {
+	return b.val == DNull
+}
+
+func (d *DRange) lowerBound() rangeBound {
+	return rangeBound{val: d.Lower, inclusive: d.LowerInc, lower: true}
+}
+
+func (d *DRange) upperBound() rangeBound {
+	return rangeBound{val: d.Upper, inclusive: d.UpperInc, lower: false}
+}
+
+// compareRangeBounds compares two bounds of (possibly different) ranges. It
+// returns -1 if b1 comes before b2, taking into account whether the bounds
+// are lower or upper bounds and whether they are inclusive.
+func compareRangeBounds(b1, b2 rangeBound) int {
+	if b1.infinite() && b2.infinite() {
+		if b1.lower == b2.lower {
+			return 0
+		}
+		if b1.lower {
+			return -1
+		}
+		return 1
+	}
+	if b1.infinite() {
+		if b1.lower {
+			return -1
+		}
+		return 1
+	}
+	if b2.infinite() {
+		if b2.lower {
+			return 1
+		}
+		return -1
+	}
+	res := b1.val.Compare(rangeCmpCtx, b2.val)
+	if res != 0 {
+		return res
+	}
+	switch {
+	case !b1.inclusive && !b2.inclusive:
+		// An exclusive lower bound is just after the value, and an exclusive
+		// upper bound is just before it.
+		if b1.lower == b2.lower {
+			return 0
+		}
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case !b1.inclusive:
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case !b2.inclusive:
+		if b2.lower {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// compareRangeBoundValues compares the values of two bounds, ignoring whether
+// they are inclusive.
+func compareRangeBoundValues(b1, b2 rangeBound) int {
+	b1.inclusive, b2.inclusive = true, true
+	return compareRangeBounds(b1, b2)
+}
+
+// rangeBoundsAdjacent returns true if the given upper bound of a range is
+// adjacent to the given lower bound of another range, that is, if there are
+// no values between them and the ranges do not overlap.
+func rangeBoundsAdjacent(upper, lower rangeBound) bool {
+	if upper.infinite() || lower.infinite() {
+		return false
+	}
+	// The bounds of discrete ranges are canonicalized, so there is no need to
+	// special case them here.
+	return compareRangeBoundValues(upper, lower) == 0 && upper.inclusive != lower.inclusive
+}
+
+// isDiscreteRangeSubtype returns true if the given subtype of a range type is
+// discrete, in which case the bounds of ranges of that type are canonicalized
+// to the [) form.
+func isDiscreteRangeSubtype(t *types.T) bool {
+	switch t.Family() {
+	case types.IntFamily, types.DateFamily:
+		return true
+	}
+	return false
+}
+
+// nextDiscreteRangeValue returns the value following the given bound value of
+// a range with a discrete subtype.
+func nextDiscreteRangeValue(t *types.T, d Datum) (Datum, error) {
+	switch v := d.(type) {
+	case *DInt:
+		maxVal := int64(math.MaxInt64)
+		if t.Width() == 32 {
+			maxVal = math.MaxInt32
+		}
+		if int64(*v) >= maxVal {
+			return nil, pgerror.New(pgcode.NumericValueOutOfRange, "integer out of range")
+		}
+		return NewDInt(*v + 1), nil
+	case *DDate:
+		if !v.IsFinite() {
+			// Infinite dates are their own successors.
+			return v, nil
+		}
+		next, err := v.AddDays(1)
+		if err != nil {
+			return nil, err
+		}
+		return NewDDate(next), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected discrete range bound %T", d)
+}
+
+// NewDRange returns a new range of the given range type with the given bounds.
+// A DNull bound makes the range unbounded on that side. The range is
+// canonicalized: unbounded sides are exclusive, the bounds of discrete ranges
+// are converted to the [) form, and ranges which contain no values are
+// empty.
+func NewDRange(typ *types.T, lower, upper Datum, lowerInc, upperInc bool) (*DRange, error) {
+	subtype := typ.RangeContents()
+	if subtype == nil {
+		return nil, errors.AssertionFailedf("%s is not a range type", typ.SQLStringForError())
+	}
+	lower, upper = UnwrapDOidWrapper(lower), UnwrapDOidWrapper(upper)
+	if lower == DNull {
+		lowerInc = false
+	}
+	if upper == DNull {
+		upperInc = false
+	}
+	if lower != DNull && upper != DNull {
+		cmp := lower.Compare(rangeCmpCtx, upper)
+		if cmp > 0 {
+			return nil, pgerror.New(pgcode.DataException,
+				"range lower bound must be less than or equal to range upper bound",
+			)
+		}
+		if cmp == 0 && !(lowerInc && upperInc) {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	if isDiscreteRangeSubtype(subtype) {
+		var err error
+		if lower != DNull && !lowerInc {
+			if lower, err = nextDiscreteRangeValue(subtype, lower); err != nil {
+				return nil, err
+			}
+			lowerInc = true
+		}
+		if upper != DNull && upperInc {
+			if upper, err = nextDiscreteRangeValue(subtype, upper); err != nil {
+				return nil, err
+			}
+			upperInc = false
+		}
+		if lower != DNull && upper != DNull {
+			if cmp := lower.Compare(rangeCmpCtx, upper); cmp > 0 || (cmp == 0 && !upperInc) {
+				return NewDEmptyRange(typ), nil
+			}
+		}
+	}
+	return &DRange{
+		typ:      typ,
+		Lower:    lower,
+		Upper:    upper,
+		LowerInc: lowerInc,
+		UpperInc: upperInc,
+	}, nil
+}
+
+// NewDEmptyRange returns a new empty range of the given range type.
+func NewDEmptyRange(typ *types.T) *DRange {
+	return &DRange{typ: typ, Lower: DNull, Upper: DNull, Empty: true}
+}
+
+// compareRanges implements the ordering of ranges: empty ranges sort first,
+// and other ranges are ordered by their lower bound and then their upper
+// bound.
+func compareRanges(r1, r2 *DRange) int {
+	switch {
+	case r1.Empty && r2.Empty:
+		return 0
+	case r1.Empty:
+		return -1
+	case r2.Empty:
+		return 1
+	}
+	if cmp := compareRangeBounds(r1.lowerBound(), r2.lowerBound()); cmp != 0 {
+		return cmp
+	}
+	return compareRangeBounds(r1.upperBound(), r2.upperBound())
+}
+
+// Overlaps returns true if the two ranges have a value in common.
+func (d *DRange) Overlaps(other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	if compareRangeBounds(d.lowerBound(), other.lowerBound()) >= 0 &&
+		compareRangeBounds(d.lowerBound(), other.upperBound()) <= 0 {
+		return true
+	}
+	return compareRangeBounds(other.lowerBound(), d.lowerBound()) >= 0 &&
+		compareRangeBounds(other.lowerBound(), d.upperBound()) <= 0
+}
+
+// Contains returns true if every value of the other range is in the range.
+func (d *DRange) Contains(other *DRange) bool {
+	if other.Empty {
+		return true
+	}
+	if d.Empty {
+		return false
+	}
+	return compareRangeBounds(d.lowerBound(), other.lowerBound()) <= 0 &&
+		compareRangeBounds(d.upperBound(), other.upperBound()) >= 0
+}
+
+// ContainsElem returns true if the given value of the range's subtype is in
+// the range.
+func (d *DRange) ContainsElem(elem Datum) bool {
+	if d.Empty {
+		return false
+	}
+	if d.Lower != DNull {
+		cmp := d.Lower.Compare(rangeCmpCtx, elem)
+		if cmp > 0 || (cmp == 0 && !d.LowerInc) {
+			return false
+		}
+	}
+	if d.Upper != DNull {
+		cmp := d.Upper.Compare(rangeCmpCtx, elem)
+		if cmp < 0 || (cmp == 0 && !d.UpperInc) {
+			return false
+		}
+	}
+	return true
+}
+
+// Adjacent returns true if the two ranges do not overlap, and there are no
+// values between them.
+func (d *DRange) Adjacent(other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return rangeBoundsAdjacent(d.upperBound(), other.lowerBound()) ||
+		rangeBoundsAdjacent(other.upperBound(), d.lowerBound())
+}
+
+// mergeRanges returns the smallest range containing both of the given
+// non-empty ranges.
+func mergeRanges(r1, r2 *DRange) *DRange {
+	res := *r1
+	if compareRangeBounds(r2.lowerBound(), r1.lowerBound()) < 0 {
+		res.Lower, res.LowerInc = r2.Lower, r2.LowerInc
+	}
+	if compareRangeBounds(r2.upperBound(), r1.upperBound()) > 0 {
+		res.Upper, res.UpperInc = r2.Upper, r2.UpperInc
+	}
+	return &res
+}
+
+// NewDMultirange returns a new multirange of the given multirange type
+// containing the values of the given ranges. The ranges are sorted, and
+// empty ranges are removed while overlapping or adjacent ranges are merged.
+func NewDMultirange(typ *types.T, ranges []*DRange) *DMultirange {
+	sorted := make([]*DRange, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.Empty {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return compareRanges(sorted[i], sorted[j]) < 0
+	})
+	res := &DMultirange{typ: typ, Ranges: sorted[:0]}
+	for _, r := range sorted {
+		if n := len(res.Ranges); n > 0 {
+			last := res.Ranges[n-1]
+			if last.Overlaps(r) || last.Adjacent(r) {
+				res.Ranges[n-1] = mergeRanges(last, r)
+				continue
+			}
+		}
+		res.Ranges = append(res.Ranges, r)
+	}
+	return res
+}
+
+// rangesOf returns the non-empty ranges of the given range or multirange.
+func rangesOf(d Datum) []*DRange {
+	switch t := UnwrapDOidWrapper(d).(type) {
+	case *DRange:
+		if t.Empty {
+			return nil
+		}
+		return []*DRange{t}
+	case *DMultirange:
+		return t.Ranges
+	}
+	panic(errors.AssertionFailedf("expected range or multirange, found %T", d))
+}
+
+// RangesOverlap returns true if the given ranges or multiranges have a value
+// in common. It implements the && operator.
+func RangesOverlap(left, right Datum) bool {
+	for _, l := range rangesOf(left) {
+		for _, r := range rangesOf(right) {
+			if l.Overlaps(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RangesContain returns true if every value of the right range or multirange
+// is contained in the left range or multirange. It implements the @>
+// operator.
+func RangesContain(left, right Datum) bool {
+	// The ranges of a multirange are neither overlapping nor adjacent, so each
+	// range of the right side must be contained in a single range of the left
+	// side.
+	leftRanges := rangesOf(left)
+	for _, r := range rangesOf(right) {
+		found := false
+		for _, l := range leftRanges {
+			if l.Contains(r) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// RangesContainElem returns true if the given value of the subtype is
+// contained in the given range or multirange.
+func RangesContainElem(d Datum, elem Datum) bool {
+	for _, r := range rangesOf(d) {
+		if r.ContainsElem(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+// RangesAdjacent returns true if the given ranges or multiranges are adjacent
+// to each other. It implements the -|- operator.
+func RangesAdjacent(left, right Datum) bool {
+	l, r := rangesOf(left), rangesOf(right)
+	if len(l) == 0 || len(r) == 0 {
+		return false
+	}
+	// Multiranges are adjacent if the ranges spanning them are adjacent.
+	return rangeBoundsAdjacent(l[len(l)-1].upperBound(), r[0].lowerBound()) ||
+		rangeBoundsAdjacent(r[len(r)-1].upperBound(), l[0].lowerBound())
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// ReindexKind describes the target of a REINDEX statement.
+type ReindexKind int
+
+const (
+	// ReindexTable describes the REINDEX TABLE statement.
+	ReindexTable ReindexKind = iota
+	// ReindexIndex describes the REINDEX INDEX statement.
+	ReindexIndex
+	// ReindexDatabase describes the REINDEX DATABASE statement.
+	ReindexDatabase
+)
+
+// Reindex represents a REINDEX statement.
+type Reindex struct {
+	Kind         ReindexKind
+	Concurrently bool
+	// Table is only set during REINDEX TABLE statements.
+	Table *UnresolvedObjectName
+	// Index is only set during REINDEX INDEX statements.
+	Index *TableIndexName
+	// Database is only set during REINDEX DATABASE statements.
+	Database Name
+}
+
+var _ Statement = &Reindex{}
+
+// Format implements the NodeFormatter interface.
+func (node *Reindex) Format(ctx *FmtCtx) {
+	ctx.WriteString("REINDEX ")
+	switch node.Kind {
+	case ReindexTable:
+		ctx.WriteString("TABLE ")
+	case ReindexIndex:
+		ctx.WriteString("INDEX ")
+	case ReindexDatabase:
+		ctx.WriteString("DATABASE ")
+	default:
+		panic("unhandled ReindexKind")
+	}
+	if node.Concurrently {
+		ctx.WriteString("CONCURRENTLY ")
+	}
+	switch node.Kind {
+	case ReindexTable:
+		ctx.FormatNode(node.Table)
+	case ReindexIndex:
+		ctx.FormatNode(node.Index)
+	case ReindexDatabase:
+		ctx.FormatNode(&node.Database)
+	}
+}
@@ -13,6 +13,7 @@ package tree
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
@@ -135,6 +136,19 @@ type RoutineExpr struct {
 	// CursorDeclaration contains the information needed to open a SQL cursor with
 	// the result of the *first* body statement. It may be unset.
 	CursorDeclaration *RoutineOpenCursor
+
+	// ResultBuffer is set for the routines that make up a set-returning PLpgSQL
+	// routine. For the root routine (Generator is true), it indicates that the
+	// output of the routine is the set of rows added to the buffer. For all
+	// other routines, it indicates that the result of the *first* body statement
+	// should be added to the buffer. It may be unset.
+	ResultBuffer *RoutineResultBuffer
+
+	// SessionSettings is set for routines that are defined with SECURITY
+	// DEFINER or with SET clauses. It contains the session data changes that
+	// must be applied for the duration of the routine's execution. It may be
+	// unset.
+	SessionSettings *RoutineSessionSettings
 }
 
 // NewTypedRoutineExpr returns a new RoutineExpr that is well-typed.
@@ -151,6 +165,8 @@ func NewTypedRoutineExpr(
 	procedure bool,
 	blockState *BlockState,
 	cursorDeclaration *RoutineOpenCursor,
+	resultBuffer *RoutineResultBuffer,
+	sessionSettings *RoutineSessionSettings,
 ) *RoutineExpr {
 	return &RoutineExpr{
 		Args:              args,
@@ -165,6 +181,8 @@ func NewTypedRoutineExpr(
 		Procedure:         procedure,
 		BlockState:        blockState,
 		CursorDeclaration: cursorDeclaration,
+		ResultBuffer:      resultBuffer,
+		SessionSettings:   sessionSettings,
 	}
 }
 
@@ -220,6 +238,30 @@ type RoutineOpenCursor struct {
 	CursorSQL string
 }
 
+// RoutineResultBuffer is shared between all routines that make up a
+// set-returning PLpgSQL routine. The RETURN NEXT and RETURN QUERY statements
+// add rows to the buffer, which become the output of the routine once it
+// finishes executing.
+type RoutineResultBuffer struct {
+	// Container holds the rows that have been added to the buffer. It is
+	// initialized by the execution engine when the root routine starts, and is
+	// only valid for the duration of its execution.
+	Container interface{}
+}
+
+// RoutineSessionSettings describes the changes to the session data that are
+// made while a routine is executing. The changes are undone once the routine
+// finishes.
+type RoutineSessionSettings struct {
+	// Definer, if set, is the user whose privileges the routine executes with,
+	// i.e., the owner of a SECURITY DEFINER routine. It is empty for SECURITY
+	// INVOKER routines.
+	Definer username.SQLUsername
+	// Config contains the session variables set by the routine, as
+	// "name=value" strings.
+	Config []string
+}
+
 // BlockState is shared state between all routines that make up a PLpgSQL block.
 // It allows for coordination between the routines for exception handling.
 type BlockState struct {
@@ -21,6 +21,7 @@ package tree
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -594,6 +595,7 @@ type AliasedTableExpr struct {
 	Ordinality bool
 	Lateral    bool
 	As         AliasClause
+	Sample     *TableSample
 }
 
 // Format implements the NodeFormatter interface.
@@ -612,6 +614,70 @@ func (node *AliasedTableExpr) Format(ctx *FmtCtx) {
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(&node.As)
 	}
+	if node.Sample != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.Sample)
+	}
+}
+
+// TableSampleMethod represents the sampling method of a TABLESAMPLE clause.
+type TableSampleMethod int
+
+// TableSampleMethod values.
+const (
+	// TableSampleNone is the default, and means that the table is not sampled.
+	TableSampleNone TableSampleMethod = iota
+	// TableSampleBernoulli selects each row of the table independently with the
+	// given probability.
+	TableSampleBernoulli
+	// TableSampleSystem selects whole ranges of the table with the given
+	// probability.
+	TableSampleSystem
+)
+
+var tableSampleMethodName = [...]string{
+	TableSampleNone:      "",
+	TableSampleBernoulli: "BERNOULLI",
+	TableSampleSystem:    "SYSTEM",
+}
+
+func (m TableSampleMethod) String() string {
+	return tableSampleMethodName[m]
+}
+
+// TableSampleMethodFromString returns the TableSampleMethod with the given
+// name.
+func TableSampleMethodFromString(name string) (TableSampleMethod, error) {
+	switch strings.ToUpper(name) {
+	case "BERNOULLI":
+		return TableSampleBernoulli, nil
+	case "SYSTEM":
+		return TableSampleSystem, nil
+	}
+	return 0, pgerror.Newf(pgcode.UndefinedObject, "tablesample method %s does not exist", name)
+}
+
+// TableSample represents a TABLESAMPLE clause.
+type TableSample struct {
+	Method TableSampleMethod
+	// Percent is the percentage of the table to sample.
+	Percent Expr
+	// Repeatable is the seed of the REPEATABLE clause, if any.
+	Repeatable Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TableSample) Format(ctx *FmtCtx) {
+	ctx.WriteString("TABLESAMPLE ")
+	ctx.WriteString(node.Method.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(node.Percent)
+	ctx.WriteByte(')')
+	if node.Repeatable != nil {
+		ctx.WriteString(" REPEATABLE (")
+		ctx.FormatNode(node.Repeatable)
+		ctx.WriteByte(')')
+	}
 }
 
 // ParenTableExpr represents a parenthesized TableExpr.
@@ -780,6 +846,47 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetKind identifies the syntactic form of a GroupingSet.
+type GroupingSetKind uint8
+
+const (
+	// GroupingSets represents GROUPING SETS (...).
+	GroupingSets GroupingSetKind = iota
+	// Rollup represents ROLLUP (...).
+	Rollup
+	// Cube represents CUBE (...).
+	Cube
+)
+
+var groupingSetKindName = [...]string{
+	GroupingSets: "GROUPING SETS",
+	Rollup:       "ROLLUP",
+	Cube:         "CUBE",
+}
+
+// String implements the fmt.Stringer interface.
+func (k GroupingSetKind) String() string {
+	return groupingSetKindName[k]
+}
+
+// GroupingSet represents a GROUPING SETS, ROLLUP or CUBE element of a GROUP BY
+// clause. For ROLLUP and CUBE, each element of Exprs is either an expression
+// or a Tuple of expressions that is treated as a single unit. For GROUPING
+// SETS, each element is an expression, a Tuple of expressions (an empty Tuple
+// denotes the empty grouping set), or a nested GroupingSet.
+type GroupingSet struct {
+	Kind  GroupingSetKind
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Kind.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
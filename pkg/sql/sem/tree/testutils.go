@@ -83,6 +83,12 @@ func SampleDatum(t *types.T) Datum {
 		return NewDOid(1009)
 	case types.PGLSNFamily:
 		return NewDPGLSN(0x1000000100)
+	case types.RangeFamily:
+		r, _ := NewDRange(t, DNull, DNull, false /* lowerInc */, false /* upperInc */)
+		return r
+	case types.MultirangeFamily:
+		r, _ := NewDRange(t.RangeContents(), DNull, DNull, false /* lowerInc */, false /* upperInc */)
+		return NewDMultirange(t, []*DRange{r})
 	case types.RefCursorFamily:
 		return NewDRefCursor("Wheezer")
 	case types.Box2DFamily:
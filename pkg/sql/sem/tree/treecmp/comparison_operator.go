@@ -70,6 +70,8 @@ const (
 	JSONAllExists
 	Overlaps
 	TSMatches
+	Adjacent
+	JSONPathExists
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -122,6 +124,8 @@ var comparisonOpName = [...]string{
 	JSONAllExists:     "?&",
 	Overlaps:          "&&",
 	TSMatches:         "@@",
+	Adjacent:          "-|-",
+	JSONPathExists:    "@?",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -989,6 +989,7 @@ var (
 	errInvalidMaxUsage     = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
 	errInvalidMinUsage     = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
 	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
+	errInvalidGroupingSet  = pgerror.New(pgcode.Syntax, "GROUPING SETS, ROLLUP and CUBE can only appear in a GROUP BY clause")
 )
 
 // NewAggInAggError creates an error for the case when an aggregate function is
@@ -1192,6 +1193,10 @@ func (expr *FuncExpr) TypeCheck(
 		return nil, err
 	}
 
+	// Remove user-defined routines with polymorphic parameters that cannot be
+	// resolved consistently for the arguments.
+	s.filterPolymorphicRoutineOverloads(def.Overloads)
+
 	var hasUDFOverload bool
 	var calledOnNullInputFns, notCalledOnNullInputFns intsets.Fast
 	for _, idx := range s.overloadIdxs {
@@ -1384,6 +1389,12 @@ func (expr *FuncExpr) TypeCheck(
 		}
 	}
 
+	if overloadImpl.Type != BuiltinRoutine {
+		if err := typeCheckPolymorphicArgs(overloadImpl, s.typedExprs); err != nil {
+			return nil, err
+		}
+	}
+
 	for i, subExpr := range s.typedExprs {
 		expr.Exprs[i] = subExpr
 	}
@@ -1792,6 +1803,32 @@ func (expr PartitionMaxVal) TypeCheck(
 	return nil, errInvalidMaxUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSet
+}
+
+// TypeCheck implements the Expr interface. GROUPING operations are replaced
+// by the optimizer before type checking when they appear in a valid context,
+// so reaching this method always results in an error.
+func (expr *GroupingExpr) TypeCheck(
+	_ context.Context, semaCtx *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	if semaCtx != nil && semaCtx.Properties.IsSet(RejectAggregates) {
+		return nil, NewInvalidGroupingUsageError(semaCtx.Properties.required.context)
+	}
+	return nil, pgerror.New(pgcode.Grouping,
+		"arguments to GROUPING must be grouping expressions of the associated query level")
+}
+
+// NewInvalidGroupingUsageError creates an error for a GROUPING operation
+// used in a context where aggregation is not allowed.
+func NewInvalidGroupingUsageError(context string) error {
+	return pgerror.Newf(pgcode.Grouping, "grouping operations are not allowed in %s", context)
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *NumVal) TypeCheck(
 	ctx context.Context, semaCtx *SemaContext, desired *types.T,
@@ -2078,6 +2115,20 @@ func (d *DPGLSN) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (Typed
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DRange) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DMultirange) TypeCheck(
+	_ context.Context, _ *SemaContext, _ *types.T,
+) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DGeography) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
@@ -2096,6 +2147,12 @@ func (d *DJSON) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedE
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DJsonpath) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTSQuery) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
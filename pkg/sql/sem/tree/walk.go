@@ -534,13 +534,40 @@ func (expr *Subquery) WalkTableExpr(v Visitor) TableExpr {
 
 // WalkTableExpr implements the TableExpr interface.
 func (expr *AliasedTableExpr) WalkTableExpr(v Visitor) TableExpr {
+	ret := expr
 	newExpr, changed := walkTableExpr(v, expr.Expr)
 	if changed {
 		exprCopy := *expr
 		exprCopy.Expr = newExpr
-		return &exprCopy
+		ret = &exprCopy
 	}
-	return expr
+	if expr.Sample != nil {
+		sample, changed := walkTableSample(v, expr.Sample)
+		if changed {
+			if ret == expr {
+				exprCopy := *expr
+				ret = &exprCopy
+			}
+			ret.Sample = sample
+		}
+	}
+	return ret
+}
+
+func walkTableSample(v Visitor, sample *TableSample) (*TableSample, bool) {
+	percent, changedP := WalkExpr(v, sample.Percent)
+	var repeatable Expr
+	var changedR bool
+	if sample.Repeatable != nil {
+		repeatable, changedR = WalkExpr(v, sample.Repeatable)
+	}
+	if changedP || changedR {
+		sampleCopy := *sample
+		sampleCopy.Percent = percent
+		sampleCopy.Repeatable = repeatable
+		return &sampleCopy, true
+	}
+	return sample, false
 }
 
 // WalkTableExpr implements the TableExpr interface.
@@ -711,6 +738,26 @@ func (expr PartitionMaxVal) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr PartitionMinVal) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *GroupingExpr) Walk(v Visitor) Expr {
+	if exprs, changed := walkExprSlice(v, expr.Exprs); changed {
+		exprCopy := *expr
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return expr
+}
+
+// Walk implements the Expr interface.
+func (expr *GroupingSet) Walk(v Visitor) Expr {
+	if exprs, changed := walkExprSlice(v, expr.Exprs); changed {
+		exprCopy := *expr
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return expr
+}
+
 // Walk implements the Expr interface.
 func (expr *NumVal) Walk(_ Visitor) Expr { return expr }
 
@@ -762,6 +809,12 @@ func (expr *DBox2D) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DPGLSN) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DRange) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DMultirange) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DGeography) Walk(_ Visitor) Expr { return expr }
 
@@ -771,6 +824,9 @@ func (expr *DGeometry) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DJSON) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DJsonpath) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DTSQuery) Walk(_ Visitor) Expr { return expr }
 
@@ -1463,6 +1519,64 @@ func (stmt *Insert) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *Merge) copyNode() *Merge {
+	stmtCopy := *stmt
+	whens := make([]MergeWhen, len(stmt.Whens))
+	stmtCopy.Whens = make(MergeWhens, len(stmt.Whens))
+	for i, w := range stmt.Whens {
+		whens[i] = *w
+		whens[i].Exprs = make(UpdateExprs, len(w.Exprs))
+		for j, e := range w.Exprs {
+			eCopy := *e
+			whens[i].Exprs[j] = &eCopy
+		}
+		whens[i].Values = append(Exprs(nil), w.Values...)
+		stmtCopy.Whens[i] = &whens[i]
+	}
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *Merge) walkStmt(v Visitor) Statement {
+	ret := stmt
+	copyOnWrite := func() {
+		if ret == stmt {
+			ret = stmt.copyNode()
+		}
+	}
+	if e, changed := WalkExpr(v, stmt.On); changed {
+		copyOnWrite()
+		ret.On = e
+	}
+	for i, when := range stmt.Whens {
+		if when.Cond != nil {
+			if e, changed := WalkExpr(v, when.Cond); changed {
+				copyOnWrite()
+				ret.Whens[i].Cond = e
+			}
+		}
+		for j, expr := range when.Exprs {
+			if e, changed := WalkExpr(v, expr.Expr); changed {
+				copyOnWrite()
+				ret.Whens[i].Exprs[j].Expr = e
+			}
+		}
+		for j, expr := range when.Values {
+			if e, changed := WalkExpr(v, expr); changed {
+				copyOnWrite()
+				ret.Whens[i].Values[j] = e
+			}
+		}
+	}
+	returning, changed := walkReturningClause(v, stmt.Returning)
+	if changed {
+		copyOnWrite()
+		ret.Returning = returning
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *CreateTable) copyNode() *CreateTable {
 	stmtCopy := *stmt
@@ -2079,6 +2193,7 @@ var _ walkableStmt = &DropTenant{}
 var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Import{}
 var _ walkableStmt = &Insert{}
+var _ walkableStmt = &Merge{}
 var _ walkableStmt = &ParenSelect{}
 var _ walkableStmt = &Restore{}
 var _ walkableStmt = &SelectClause{}
@@ -51,6 +51,10 @@ type InternalExecutorOverride struct {
 	// cardinality estimation in the optimizer.
 	// TODO(#102954): this should be removed when #102954 is fixed.
 	OptimizerUseHistograms bool
+	// AllowMaterializedViewMutations, if set, allows the query to modify the rows
+	// of materialized views. It is used to refresh materialized views
+	// concurrently and incrementally.
+	AllowMaterializedViewMutations bool
 	// MultiOverride, if set, is a comma-separated list of variable_name=value
 	// overrides. For example, 'Database=foo,OptimizerUseHistograms=true'. These
 	// overrides are performed on the best-effort basis - see SessionData.Update
@@ -174,6 +174,11 @@ type LocalUnmigratableSessionData struct {
 	// IsSSL indicates whether the session is using SSL/TLS.
 	IsSSL bool
 
+	// AllowMaterializedViewMutations allows the statements of the session to
+	// modify the rows of materialized views. It is only set by the internal
+	// executor for refreshing materialized views.
+	AllowMaterializedViewMutations bool
+
 	// ////////////////////////////////////////////////////////////////////////
 	// WARNING: consider whether a session parameter you're adding needs to  //
 	// be propagated to the remote nodes or needs to persist amongst session //
@@ -139,7 +139,11 @@ func ShowCreateView(
 			f.WriteRune(',')
 		}
 	}
-	f.WriteString(") AS ")
+	f.WriteString(")")
+	if desc.GetIncrementalRefreshJobID() != catpb.InvalidJobID {
+		f.WriteString(" WITH (incremental_refresh = true)")
+	}
+	f.WriteString(" AS ")
 
 	cfg := tree.DefaultPrettyCfg()
 	cfg.UseTabs = true
@@ -586,6 +590,10 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(tree.ForeignKeyReferenceActionType[fk.OnUpdate].String())
 	}
+	if d := tree.ConstraintDeferrabilityType[fk.Deferrability]; d.IsDeferrable() {
+		buf.WriteByte(' ')
+		buf.WriteString(d.String())
+	}
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -847,13 +855,21 @@ func showConstraintClause(
 			formatQuoteNames(&f.Buffer, c.GetName())
 			f.WriteString(" ")
 		}
-		f.WriteString("UNIQUE WITHOUT INDEX (")
-		colNames, err := catalog.ColumnNamesForIDs(desc, c.CollectKeyColumnIDs().Ordered())
-		if err != nil {
-			return err
+		if c.IsExclusion() {
+			if err := formatExclusionConstraint(f, desc, c.UniqueWithoutIndexDesc()); err != nil {
+				return err
+			}
+		} else {
+			f.WriteString("UNIQUE WITHOUT INDEX (")
+			colNames, err := catalog.ColumnNamesForIDs(desc, c.CollectKeyColumnIDs().Ordered())
+			if err != nil {
+				return err
+			}
+			f.WriteString(strings.Join(colNames, ", "))
+			f.WriteString(")")
 		}
-		f.WriteString(strings.Join(colNames, ", "))
-		f.WriteString(")")
+		deferrability := tree.ConstraintDeferrabilityType[c.Deferrability()]
+		f.FormatNode(&deferrability)
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(
@@ -862,6 +878,10 @@ func showConstraintClause(
 			if err != nil {
 				return err
 			}
+			if c.IsExclusion() {
+				// The predicate of an exclusion constraint must be parenthesized.
+				pred = "(" + pred + ")"
+			}
 			f.WriteString(pred)
 		}
 		if !c.IsConstraintValidated() {
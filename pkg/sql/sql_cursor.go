@@ -13,18 +13,25 @@ package sql
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
@@ -32,20 +39,12 @@ import (
 // DeclareCursor implements the DECLARE statement.
 // See https://www.postgresql.org/docs/current/sql-declare.html for details.
 func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (planNode, error) {
-	if s.Binary {
-		return nil, unimplemented.NewWithIssue(77099, "DECLARE BINARY CURSOR")
-	}
-	if s.Scroll == tree.Scroll {
-		return nil, unimplemented.NewWithIssue(77102, "DECLARE SCROLL CURSOR")
-	}
-
 	return &delayedNode{
 		name: s.String(),
 		constructor: func(ctx context.Context, p *planner) (_ planNode, _ error) {
-			if p.extendedEvalCtx.TxnImplicit {
-				if s.Hold {
-					return nil, unimplemented.NewWithIssue(77101, "DECLARE CURSOR WITH HOLD can only be used in transaction blocks")
-				}
+			// Cursors declared WITH HOLD outlive the transaction, so they can
+			// also be declared in an implicit transaction.
+			if p.extendedEvalCtx.TxnImplicit && !s.Hold {
 				return nil, pgerror.Newf(pgcode.NoActiveSQLTransaction, "DECLARE CURSOR can only be used in transaction blocks")
 			}
 
@@ -117,6 +116,25 @@ func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (pla
 				statement:  statement,
 				created:    timeutil.Now(),
 				withHold:   s.Hold,
+				scroll:     s.Scroll == tree.Scroll,
+			}
+			planCols := pt.main.planColumns()
+			if s.Binary {
+				cursor.formatCodes = make([]pgwirebase.FormatCode, len(planCols))
+				for i := range cursor.formatCodes {
+					cursor.formatCodes[i] = pgwirebase.FormatBinary
+				}
+			}
+			if cursor.scroll || cursor.withHold {
+				// SCROLL cursors must be able to move backward, and WITH HOLD
+				// cursors must be usable after the transaction commits, so the
+				// result of the query is buffered up front.
+				buffered, err := p.bufferCursorRows(itCtx, rows, planCols, cursor.withHold)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to DECLARE CURSOR")
+				}
+				cursor.Rows = buffered
+				cursor.eagerExecution = true
 			}
 			if err := p.sqlCursors.addCursor(s.Name, cursor); err != nil {
 				// This case shouldn't happen because cursor names are scoped to a session,
@@ -159,7 +177,7 @@ func (p *planner) newFetchNode(s *tree.CursorStmt) (*fetchNode, error) {
 			pgcode.InvalidCursorName, "cursor %q does not exist", s.Name,
 		)
 	}
-	if s.Count < 0 || s.FetchType == tree.FetchBackwardAll {
+	if !cursor.scroll && (s.Count < 0 || s.FetchType == tree.FetchBackwardAll) {
 		return nil, errBackwardScan
 	}
 	node := &fetchNode{
@@ -182,6 +200,9 @@ type fetchNode struct {
 	// mode.
 	offset    int64
 	fetchType tree.FetchType
+	// step is the direction in which a SCROLL cursor moves for each row
+	// returned: 1 for forward and -1 for backward.
+	step int64
 
 	seeked bool
 
@@ -205,6 +226,9 @@ func (f *fetchNode) startInternal() error {
 }
 
 func (f *fetchNode) nextInternal(ctx context.Context) (bool, error) {
+	if f.cursor.scroll {
+		return f.nextScroll(ctx)
+	}
 	if f.fetchType == tree.FetchAll {
 		return f.cursor.Next(ctx)
 	}
@@ -253,6 +277,56 @@ func (f *fetchNode) nextInternal(ctx context.Context) (bool, error) {
 	return f.cursor.Next(ctx)
 }
 
+// nextScroll implements nextInternal for SCROLL cursors, which can move to
+// any row of the buffered result. The position of the cursor is tracked by
+// curRow, which is updated on every move.
+func (f *fetchNode) nextScroll(ctx context.Context) (bool, error) {
+	rows := f.cursor.Rows.(*bufferedCursorRows)
+	if !f.seeked {
+		f.seeked = true
+		switch f.fetchType {
+		case tree.FetchNormal:
+			if f.n == 0 {
+				// FETCH 0 returns the current row again.
+				return f.seek(ctx, rows, f.cursor.curRow)
+			}
+			f.step = 1
+			if f.n < 0 {
+				f.step, f.n = -1, -f.n
+			}
+		case tree.FetchAll:
+			f.step, f.n = 1, math.MaxInt64
+		case tree.FetchBackwardAll:
+			f.step, f.n = -1, math.MaxInt64
+		case tree.FetchFirst:
+			return f.seek(ctx, rows, 1)
+		case tree.FetchLast:
+			return f.seek(ctx, rows, rows.len())
+		case tree.FetchAbsolute:
+			if f.offset < 0 {
+				// Negative positions count from the end of the result.
+				return f.seek(ctx, rows, rows.len()+1+f.offset)
+			}
+			return f.seek(ctx, rows, f.offset)
+		case tree.FetchRelative:
+			return f.seek(ctx, rows, f.cursor.curRow+f.offset)
+		}
+	}
+	if f.n <= 0 {
+		return false, nil
+	}
+	f.n--
+	return f.seek(ctx, rows, f.cursor.curRow+f.step)
+}
+
+// seek moves a SCROLL cursor to the given position of its buffered rows and
+// updates curRow to the resulting position.
+func (f *fetchNode) seek(ctx context.Context, rows *bufferedCursorRows, pos int64) (bool, error) {
+	more, err := rows.seek(ctx, pos)
+	f.cursor.curRow = rows.pos
+	return more, err
+}
+
 func (f *fetchNode) startExec(params runParams) error {
 	return f.startInternal()
 }
@@ -342,6 +416,12 @@ type sqlCursor struct {
 	created    time.Time
 	curRow     int64
 	withHold   bool
+	// scroll is set for cursors declared with SCROLL, which can move backward.
+	// The rows of such cursors are buffered in a bufferedCursorRows.
+	scroll bool
+	// formatCodes is set for cursors declared with BINARY; rows fetched from
+	// such cursors are returned in the binary format.
+	formatCodes []pgwirebase.FormatCode
 	// eagerExecution indicates that the cursor's query was executed eagerly and
 	// stored in a row container. If true, there is no need to set the transaction
 	// sequence number, since the query is no longer active. In addition, the
@@ -354,6 +434,145 @@ type sqlCursor struct {
 	committed bool
 }
 
+// bufferedCursorRows is an isql.Rows which buffers the entire result of a
+// cursor's query in a disk-backed row container, so that the rows can be read
+// in any order and after the transaction that executed the query is finished.
+// It is used for SCROLL and WITH HOLD cursors.
+type bufferedCursorRows struct {
+	rows        *rowcontainer.DiskBackedIndexedRowContainer
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+	resultCols  colinfo.ResultColumns
+	// pos is the position of the cursor. It is 0 before the first row, i for
+	// the i-th row (starting from 1), and len()+1 after the last row.
+	pos     int64
+	lastRow tree.Datums
+}
+
+var _ isql.Rows = &bufferedCursorRows{}
+
+// bufferCursorRows reads all rows from the given iterator into a new
+// bufferedCursorRows, and closes the iterator. If hold is true, the rows are
+// accounted for in the session's memory monitor, since the cursor can outlive
+// the transaction.
+func (p *planner) bufferCursorRows(
+	ctx context.Context, it isql.Rows, resultCols colinfo.ResultColumns, hold bool,
+) (_ *bufferedCursorRows, retErr error) {
+	defer func() {
+		if err := it.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	parent := p.Mon()
+	if hold {
+		parent = p.sessionMonitor
+		if parent == nil {
+			return nil, errors.AssertionFailedf("cannot declare cursor WITH HOLD without an active session")
+		}
+	}
+	evalCtx := p.ExtendedEvalContextCopy()
+	distSQLCfg := &evalCtx.DistSQLPlanner.distSQLSrv.ServerConfig
+	b := &bufferedCursorRows{resultCols: make(colinfo.ResultColumns, len(resultCols))}
+	copy(b.resultCols, resultCols)
+	b.memMonitor = execinfra.NewLimitedMonitorNoFlowCtx(
+		ctx, parent, distSQLCfg, evalCtx.SessionData(), "sql-cursor-limited",
+	)
+	b.diskMonitor = execinfra.NewMonitor(ctx, distSQLCfg.ParentDiskMonitor, "sql-cursor-disk")
+	typs := getTypesFromResultColumns(resultCols)
+	b.rows = rowcontainer.NewDiskBackedIndexedRowContainer(
+		colinfo.NoOrdering, typs, &evalCtx.Context,
+		distSQLCfg.TempStorage, b.memMonitor, b.diskMonitor,
+	)
+	scratch := make(rowenc.EncDatumRow, len(typs))
+	for {
+		ok, err := it.Next(ctx)
+		if err != nil {
+			b.close(ctx)
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		for i, d := range it.Cur() {
+			scratch[i] = rowenc.EncDatum{Datum: d}
+		}
+		if err := b.rows.AddRow(ctx, scratch); err != nil {
+			b.close(ctx)
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// len returns the number of buffered rows.
+func (b *bufferedCursorRows) len() int64 {
+	return int64(b.rows.Len())
+}
+
+// seek moves the cursor to the given position, which is clamped to the range
+// [0, len()+1]. It returns whether there is a row at the new position, in
+// which case it becomes the current row.
+func (b *bufferedCursorRows) seek(ctx context.Context, pos int64) (bool, error) {
+	if pos < 0 {
+		pos = 0
+	} else if n := b.len(); pos > n {
+		pos = n + 1
+	}
+	b.pos = pos
+	b.lastRow = nil
+	if pos == 0 || pos > b.len() {
+		return false, nil
+	}
+	row, err := b.rows.GetRow(ctx, int(pos-1))
+	if err != nil {
+		return false, err
+	}
+	b.lastRow, err = row.GetDatums(0, len(b.resultCols))
+	return err == nil, err
+}
+
+// Next implements the isql.Rows interface.
+func (b *bufferedCursorRows) Next(ctx context.Context) (bool, error) {
+	return b.seek(ctx, b.pos+1)
+}
+
+// Cur implements the isql.Rows interface.
+func (b *bufferedCursorRows) Cur() tree.Datums {
+	return b.lastRow
+}
+
+// RowsAffected implements the isql.Rows interface.
+func (b *bufferedCursorRows) RowsAffected() int {
+	return b.rows.Len()
+}
+
+// Close implements the isql.Rows interface.
+func (b *bufferedCursorRows) Close() error {
+	// Use context.Background(), since the cursor can outlive the context in
+	// which it was created.
+	b.close(context.Background())
+	return nil
+}
+
+func (b *bufferedCursorRows) close(ctx context.Context) {
+	if b.rows != nil {
+		b.rows.Close(ctx)
+		b.memMonitor.Stop(ctx)
+		b.diskMonitor.Stop(ctx)
+		b.rows = nil
+	}
+}
+
+// Types implements the isql.Rows interface.
+func (b *bufferedCursorRows) Types() colinfo.ResultColumns {
+	return b.resultCols
+}
+
+// HasResults implements the isql.Rows interface.
+func (b *bufferedCursorRows) HasResults() bool {
+	return b.len() > 0
+}
+
 // Next implements the Rows interface.
 func (s *sqlCursor) Next(ctx context.Context) (bool, error) {
 	more, err := s.Rows.Next(ctx)
@@ -371,10 +590,6 @@ type sqlCursors interface {
 	//     current transaction are closed.
 	//   * If the reason for closing is an explicit CLOSE ALL or the session
 	//     closing, all cursors are closed unconditionally.
-	//
-	// NOTE: a SQL cursor declared WITH HOLD will currently result in an
-	// "unimplemented" error if the reason for closing is txn commit, since
-	// WITH HOLD is not yet implemented for SQL cursors.
 	closeAll(reason cursorCloseReason) error
 	// closeCursor closes the named cursor, returning an error if that cursor
 	// didn't exist in the set.
@@ -443,13 +658,13 @@ func (c *cursorMap) closeAll(reason cursorCloseReason) error {
 		switch reason {
 		case cursorCloseForTxnCommit:
 			if curs.withHold {
-				if curs.eagerExecution {
-					// Cursors declared using WITH HOLD are not closed at transaction
-					// commit, and become the responsibility of the session.
-					curs.committed = true
-					continue
+				if !curs.eagerExecution {
+					return errors.AssertionFailedf("cursor %s WITH HOLD was not buffered", n)
 				}
-				return unimplemented.NewWithIssuef(77101, "cursor %s WITH HOLD must be closed before committing", n)
+				// Cursors declared using WITH HOLD are not closed at transaction
+				// commit, and become the responsibility of the session.
+				curs.committed = true
+				continue
 			}
 		case cursorCloseForTxnRollback:
 			if curs.committed {
@@ -550,7 +765,12 @@ func (p *planner) checkNoConflictingCursors(stmt tree.Statement) error {
 	// We could improve this by matching the memo metadata's list of dependent
 	// schema objects in each open cursor with the objects being changed in the
 	// schema change.
-	if len(p.sqlCursors.list()) > 0 {
+	for _, c := range p.sqlCursors.list() {
+		// Cursors held over from a previous transaction have buffered their
+		// results already, so they cannot conflict with the schema change.
+		if c.committed {
+			continue
+		}
 		return unimplemented.NewWithIssue(74608, "cannot run schema change "+
 			"in a transaction with open DECLARE cursors")
 	}
@@ -200,6 +200,17 @@ func NewUnsupportedUnvalidatedConstraintError(constraintType catconstants.Constr
 		"%v constraints cannot be marked NOT VALID", constraintType)
 }
 
+// NewDeferrableUniqueIndexError creates an error for a DEFERRABLE unique
+// constraint backed by an index, which enforces uniqueness as rows are
+// written.
+func NewDeferrableUniqueIndexError() error {
+	return errors.WithHint(
+		pgerror.New(pgcode.FeatureNotSupported,
+			"unique constraints with an index cannot be marked DEFERRABLE"),
+		"use UNIQUE WITHOUT INDEX to create a deferrable unique constraint",
+	)
+}
+
 // MakeObjectAlreadyExistsError creates an error for a namespace collision
 // with an arbitrary descriptor type.
 func MakeObjectAlreadyExistsError(collidingObject *descpb.Descriptor, name string) error {
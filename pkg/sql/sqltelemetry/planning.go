@@ -49,6 +49,10 @@ var ForeignKeyCascadesUseCounter = telemetry.GetCounterOnce("sql.plan.fk.cascade
 // LATERAL keyword.
 var LateralJoinUseCounter = telemetry.GetCounterOnce("sql.plan.lateral-join")
 
+// TableSampleUseCounter is to be incremented whenever a query uses the
+// TABLESAMPLE clause.
+var TableSampleUseCounter = telemetry.GetCounterOnce("sql.plan.table-sample")
+
 // HashJoinHintUseCounter is to be incremented whenever a query specifies a
 // hash join via a query hint.
 var HashJoinHintUseCounter = telemetry.GetCounterOnce("sql.plan.hints.hash-join")
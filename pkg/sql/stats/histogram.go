@@ -98,8 +98,9 @@ const upperBoundsKeyEncodedVersion = HistogramVersion(2)
 
 // EncodeUpperBound encodes the upper-bound datum of a histogram bucket.
 func EncodeUpperBound(version HistogramVersion, upperBound tree.Datum) ([]byte, error) {
-	if version >= upperBoundsValueEncodedVersion || upperBound.ResolvedType().Family() == types.TSQueryFamily {
-		// TSQuery doesn't have key-encoding, so we must use value-encoding.
+	if version >= upperBoundsValueEncodedVersion || mustValueEncodeUpperBound(upperBound.ResolvedType()) {
+		// TSQuery and Jsonpath don't have key-encoding, so we must use
+		// value-encoding.
 		return valueside.Encode(nil /* appendTo */, valueside.NoColumnID, upperBound, nil /* scratch */)
 	}
 	return keyside.Encode(nil /* b */, upperBound, encoding.Ascending)
@@ -111,8 +112,8 @@ func DecodeUpperBound(
 ) (tree.Datum, error) {
 	var datum tree.Datum
 	var err error
-	if version >= upperBoundsValueEncodedVersion || typ.Family() == types.TSQueryFamily {
-		// TSQuery doesn't have key-encoding, so we must have used
+	if version >= upperBoundsValueEncodedVersion || mustValueEncodeUpperBound(typ) {
+		// TSQuery and Jsonpath don't have key-encoding, so we must have used
 		// value-encoding, regardless of the histogram version.
 		datum, _, err = valueside.Decode(a, typ, upperBound)
 	} else {
@@ -121,6 +122,12 @@ func DecodeUpperBound(
 	return datum, err
 }
 
+// mustValueEncodeUpperBound returns true if upper bounds of the given type
+// can only be value-encoded.
+func mustValueEncodeUpperBound(typ *types.T) bool {
+	return typ.Family() == types.TSQueryFamily || typ.Family() == types.JsonpathFamily
+}
+
 // GetDefaultHistogramBuckets gets the default number of histogram buckets to
 // create for the given table.
 func GetDefaultHistogramBuckets(sv *settings.Values, desc catalog.TableDescriptor) uint32 {
@@ -0,0 +1,81 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package viewstorageparam implements storageparam.Setter for materialized
+// views.
+package viewstorageparam
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
+)
+
+// IncrementalRefresh is the storage parameter which enables the incremental
+// refresh of a materialized view.
+const IncrementalRefresh = `incremental_refresh`
+
+// Setter observes storage parameters for materialized views. The parameters
+// are not stored in the view descriptor directly; the caller applies them
+// once they have all been set.
+type Setter struct {
+	// IncrementalRefresh is whether the view is refreshed incrementally.
+	IncrementalRefresh bool
+}
+
+var _ storageparam.Setter = (*Setter)(nil)
+
+// Set implements the Setter interface.
+func (po *Setter) Set(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	key string,
+	datum tree.Datum,
+) error {
+	switch key {
+	case IncrementalRefresh:
+		var b bool
+		if s, err := paramparse.DatumAsString(ctx, evalCtx, key, datum); err == nil {
+			if b, err = paramparse.ParseBoolVar(key, s); err != nil {
+				return err
+			}
+		} else {
+			d, err := paramparse.GetSingleBool(key, datum)
+			if err != nil {
+				return err
+			}
+			b = bool(*d)
+		}
+		po.IncrementalRefresh = b
+		return nil
+	}
+	return pgerror.Newf(pgcode.InvalidParameterValue, "invalid storage parameter %q", key)
+}
+
+// Reset implements the Setter interface.
+func (po *Setter) Reset(ctx context.Context, evalCtx *eval.Context, key string) error {
+	switch key {
+	case IncrementalRefresh:
+		po.IncrementalRefresh = false
+		return nil
+	}
+	return pgerror.Newf(pgcode.InvalidParameterValue, "invalid storage parameter %q", key)
+}
+
+// RunPostChecks implements the Setter interface.
+func (po *Setter) RunPostChecks() error {
+	return nil
+}
@@ -0,0 +1,546 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// tableChangesScanBatchSize is the maximum number of rows whose state at the
+// start of the interval is read, and whose changes are decoded, in a single
+// batch.
+const tableChangesScanBatchSize = 1000
+
+// tableChangesExportFileSize is the target size of the SST returned by each
+// ExportRequest, which bounds the number of revisions buffered at once.
+const tableChangesExportFileSize = 4 << 20 // 4 MiB
+
+// TableChanges is part of the eval.Planner interface.
+//
+// It returns, for every row of the table's primary index which was written in
+// the interval (startTime, endTime], one result row per MVCC timestamp at which
+// the row was written. Each result contains the timestamp, the operation
+// (insert, update or delete), the primary key of the row and the row's values
+// before and after the write, as JSON objects keyed by column name. Results
+// are ordered by primary index key and then by timestamp.
+//
+// The revisions are read with ExportRequests over all MVCC revisions, so the
+// start of the interval must be within the GC window of the table. The state
+// of each changed row at the start of the interval is read with a historical
+// scan. Rows are decoded using the current schema of the table, so the table's
+// descriptor must not have been modified during the interval.
+//
+// The changes are computed lazily as the returned rows are iterated, one
+// exported SST at a time, and the memory used by the revisions and changes
+// buffered in between is accounted for by the planner's monitor.
+func (p *planner) TableChanges(
+	ctx context.Context, tableID int64, startTime, endTime hlc.Timestamp,
+) (eval.InternalRows, error) {
+	ctx, sp := tracing.ChildSpan(ctx, "sql.TableChanges")
+	defer sp.Finish()
+
+	desc, err := p.Descriptors().ByIDWithLeased(p.txn).WithoutNonPublic().Get().Table(ctx, descpb.ID(tableID))
+	if err != nil {
+		return nil, err
+	}
+	if !desc.IsTable() || desc.IsVirtualTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a table", desc.GetName())
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+
+	readTS := p.txn.ReadTimestamp()
+	if endTime.IsEmpty() {
+		endTime = readTS
+	}
+	if readTS.Less(endTime) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"end time %s must not be after the statement time %s", endTime, readTS)
+	}
+	if !startTime.Less(endTime) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"start time %s must be before end time %s", startTime, endTime)
+	}
+	if modTime := desc.GetModificationTime(); startTime.Less(modTime) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"table %q was modified at %s, after the start time %s; "+
+				"changes can only be decoded with the current schema of the table",
+			desc.GetName(), modTime, startTime)
+	}
+
+	decoder, err := makeTableChangesDecoder(p, desc)
+	if err != nil {
+		return nil, err
+	}
+	return &tableChangesRows{
+		p:         p,
+		desc:      desc,
+		decoder:   decoder,
+		startTime: startTime,
+		endTime:   endTime,
+		span:      desc.PrimaryIndexSpan(p.ExecCfg().Codec),
+		acc:       p.Mon().MakeBoundAccount(),
+	}, nil
+}
+
+// tableChangesRevision is a single MVCC revision of a column family of a row.
+type tableChangesRevision struct {
+	key roachpb.Key
+	ts  hlc.Timestamp
+	// value is the encoded roachpb.Value of the revision, or nil if the
+	// revision is a deletion tombstone.
+	value []byte
+}
+
+// tableChangesRow contains the revisions written to a row in the interval and
+// the state of the row at the start of the interval.
+type tableChangesRow struct {
+	// prefix is the key prefix shared by all column families of the row.
+	prefix    roachpb.Key
+	revisions []tableChangesRevision
+	// initial maps the key of each column family of the row to its encoded
+	// value at the start of the interval.
+	initial map[string][]byte
+	// size is the memory accounted for the row.
+	size int64
+}
+
+const (
+	tableChangesRowOverhead      = int64(unsafe.Sizeof(tableChangesRow{}))
+	tableChangesRevisionOverhead = int64(unsafe.Sizeof(tableChangesRevision{}))
+)
+
+// exportBatch exports the revisions in the interval of the next part of the
+// remaining span and adds them to the pending rows.
+func (r *tableChangesRows) exportBatch(ctx context.Context) error {
+	header := kvpb.Header{
+		Timestamp:                   r.endTime,
+		ReturnElasticCPUResumeSpans: true,
+		// Ask for a single SST per request, so that the rows are exported in
+		// batches of roughly tableChangesExportFileSize.
+		TargetBytes: 1,
+	}
+	req := &kvpb.ExportRequest{
+		RequestHeader:  kvpb.RequestHeader{Key: r.span.Key, EndKey: r.span.EndKey},
+		StartTime:      r.startTime,
+		MVCCFilter:     kvpb.MVCCFilter_All,
+		TargetFileSize: tableChangesExportFileSize,
+	}
+	res, pErr := kv.SendWrappedWith(ctx, r.p.ExecCfg().DB.NonTransactionalSender(), header, req)
+	if pErr != nil {
+		return errors.Wrapf(pErr.GoError(), "exporting revisions of table %q between %s and %s",
+			r.desc.GetName(), r.startTime, r.endTime)
+	}
+	resp := res.(*kvpb.ExportResponse)
+	for _, file := range resp.Files {
+		if err := func() error {
+			it, err := storage.NewMemSSTIterator(file.SST, false, /* verify */
+				storage.IterOptions{
+					KeyTypes:   storage.IterKeyTypePointsAndRanges,
+					LowerBound: keys.MinKey,
+					UpperBound: keys.MaxKey,
+				})
+			if err != nil {
+				return err
+			}
+			defer it.Close()
+			for it.SeekGE(storage.NilKey); ; it.Next() {
+				if ok, err := it.Valid(); err != nil {
+					return err
+				} else if !ok {
+					return nil
+				}
+				hasPoint, hasRange := it.HasPointAndRange()
+				if hasRange {
+					// MVCC range tombstones are written when data is cleared in bulk,
+					// e.g. by a rolled back IMPORT, and do not map to row changes.
+					return unimplemented.New("table_changes range keys",
+						"cannot report changes of rows cleared by MVCC range tombstones")
+				}
+				if !hasPoint {
+					continue
+				}
+				if err := r.addRevision(ctx, it); err != nil {
+					return err
+				}
+			}
+		}(); err != nil {
+			return err
+		}
+	}
+	r.span = roachpb.Span{}
+	if resp.ResumeSpan != nil {
+		if !resp.ResumeSpan.Valid() {
+			return errors.AssertionFailedf("invalid resume span: %s", resp.ResumeSpan)
+		}
+		r.span = *resp.ResumeSpan
+	}
+	return nil
+}
+
+// addRevision adds the revision the iterator is positioned at to the pending
+// row it belongs to. Revisions are exported in key order, so the row is either
+// the last pending row or a new one.
+func (r *tableChangesRows) addRevision(ctx context.Context, it storage.MVCCIterator) error {
+	k := it.UnsafeKey()
+	prefix, err := keys.EnsureSafeSplitKey(k.Key)
+	if err != nil {
+		return err
+	}
+	raw, err := it.UnsafeValue()
+	if err != nil {
+		return err
+	}
+	v, err := storage.DecodeMVCCValue(raw)
+	if err != nil {
+		return err
+	}
+	rev := tableChangesRevision{key: k.Key.Clone(), ts: k.Timestamp}
+	if !v.IsTombstone() {
+		rev.value = append([]byte(nil), v.Value.RawBytes...)
+	}
+	size := tableChangesRevisionOverhead + int64(len(rev.key)+len(rev.value))
+	n := len(r.pending)
+	if n == 0 || !bytes.Equal(r.pending[n-1].prefix, prefix) {
+		r.pending = append(r.pending, &tableChangesRow{prefix: rev.key[:len(prefix)]})
+		size += tableChangesRowOverhead
+		n++
+	}
+	if err := r.acc.Grow(ctx, size); err != nil {
+		return err
+	}
+	row := r.pending[n-1]
+	row.revisions = append(row.revisions, rev)
+	row.size += size
+	return nil
+}
+
+// scanRowsAt populates the state of the given rows at the timestamp provided.
+func (p *planner) scanRowsAt(
+	ctx context.Context, changed []*tableChangesRow, ts hlc.Timestamp,
+) error {
+	return p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if err := txn.SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		b := txn.NewBatch()
+		for _, r := range changed {
+			b.Scan(r.prefix, r.prefix.PrefixEnd())
+		}
+		if err := txn.Run(ctx, b); err != nil {
+			return err
+		}
+		for i, r := range changed {
+			r.initial = make(map[string][]byte, len(b.Results[i].Rows))
+			for _, res := range b.Results[i].Rows {
+				r.initial[string(res.Key)] = res.Value.RawBytes
+			}
+		}
+		return nil
+	})
+}
+
+// tableChangesDecoder decodes the states of a row into JSON objects.
+type tableChangesDecoder struct {
+	fetcher row.Fetcher
+	spec    fetchpb.IndexFetchSpec
+	alloc   tree.DatumAlloc
+	// keyOrds are the ordinals of the primary key columns in the fetched
+	// columns.
+	keyOrds []int
+	evalCtx *eval.Context
+}
+
+func makeTableChangesDecoder(
+	p *planner, desc catalog.TableDescriptor,
+) (*tableChangesDecoder, error) {
+	d := &tableChangesDecoder{evalCtx: p.EvalContext()}
+	var colIDs []descpb.ColumnID
+	for _, col := range desc.PublicColumns() {
+		if !col.IsVirtual() {
+			colIDs = append(colIDs, col.GetID())
+		}
+	}
+	if err := rowenc.InitIndexFetchSpec(
+		&d.spec, p.ExecCfg().Codec, desc, desc.GetPrimaryIndex(), colIDs,
+	); err != nil {
+		return nil, err
+	}
+	keyCols := desc.GetPrimaryIndex().CollectKeyColumnIDs()
+	for i := range d.spec.FetchedColumns {
+		if keyCols.Contains(d.spec.FetchedColumns[i].ColumnID) {
+			d.keyOrds = append(d.keyOrds, i)
+		}
+	}
+	if err := d.fetcher.Init(context.TODO(), row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &d.alloc,
+		Spec:              &d.spec,
+	}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// decodeChanges returns one result per timestamp at which the row was
+// written.
+func (d *tableChangesDecoder) decodeChanges(
+	ctx context.Context, r *tableChangesRow,
+) ([]tree.Datums, error) {
+	// Revisions are exported in descending timestamp order per column family,
+	// replay them in ascending timestamp order.
+	sort.SliceStable(r.revisions, func(i, j int) bool {
+		return r.revisions[i].ts.Less(r.revisions[j].ts)
+	})
+	state := r.initial
+	before, key, err := d.decodeRow(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	var results []tree.Datums
+	for i := 0; i < len(r.revisions); {
+		ts := r.revisions[i].ts
+		for ; i < len(r.revisions) && r.revisions[i].ts == ts; i++ {
+			if r.revisions[i].value == nil {
+				delete(state, string(r.revisions[i].key))
+			} else {
+				state[string(r.revisions[i].key)] = r.revisions[i].value
+			}
+		}
+		after, afterKey, err := d.decodeRow(ctx, state)
+		if err != nil {
+			return nil, err
+		}
+		var op string
+		switch {
+		case before == nil && after == nil:
+			// A deletion of a row which did not exist.
+			continue
+		case before == nil:
+			op = "insert"
+			key = afterKey
+		case after == nil:
+			op = "delete"
+		default:
+			op = "update"
+		}
+		results = append(results, tree.Datums{
+			eval.TimestampToDecimalDatum(ts),
+			tree.NewDString(op),
+			tree.NewDJSON(key),
+			jsonOrNull(before),
+			jsonOrNull(after),
+		})
+		before = after
+	}
+	return results, nil
+}
+
+// decodeRow decodes the row made up of the given column family values into a
+// JSON object of all columns and a JSON object of the primary key columns. It
+// returns nil objects if the row does not exist.
+func (d *tableChangesDecoder) decodeRow(
+	ctx context.Context, state map[string][]byte,
+) (rowJSON json.JSON, keyJSON json.JSON, _ error) {
+	if len(state) == 0 {
+		return nil, nil, nil
+	}
+	kvs := make([]roachpb.KeyValue, 0, len(state))
+	for k, v := range state {
+		kvs = append(kvs, roachpb.KeyValue{Key: roachpb.Key(k), Value: roachpb.Value{RawBytes: v}})
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key.Compare(kvs[j].Key) < 0
+	})
+	if err := d.fetcher.ConsumeKVProvider(ctx, &row.KVProvider{KVs: kvs}); err != nil {
+		return nil, nil, err
+	}
+	datums, err := d.fetcher.NextRowDecoded(ctx)
+	if err != nil || datums == nil {
+		return nil, nil, err
+	}
+	sd := d.evalCtx.SessionData()
+	rowBuilder := json.NewObjectBuilder(len(datums))
+	keyBuilder := json.NewObjectBuilder(len(d.keyOrds))
+	for i, datum := range datums {
+		j, err := tree.AsJSON(datum, sd.DataConversionConfig, sd.Location)
+		if err != nil {
+			return nil, nil, err
+		}
+		rowBuilder.Add(d.spec.FetchedColumns[i].Name, j)
+	}
+	for _, ord := range d.keyOrds {
+		j, err := tree.AsJSON(datums[ord], sd.DataConversionConfig, sd.Location)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyBuilder.Add(d.spec.FetchedColumns[ord].Name, j)
+	}
+	return rowBuilder.Build(), keyBuilder.Build(), nil
+}
+
+func jsonOrNull(j json.JSON) tree.Datum {
+	if j == nil {
+		return tree.DNull
+	}
+	return tree.NewDJSON(j)
+}
+
+// tableChangesRows implements the eval.InternalRows interface over the
+// changes of a table, which it computes in batches as it is iterated.
+type tableChangesRows struct {
+	p         *planner
+	desc      catalog.TableDescriptor
+	decoder   *tableChangesDecoder
+	startTime hlc.Timestamp
+	endTime   hlc.Timestamp
+	// span is the part of the primary index span which remains to be
+	// exported. It is empty once all the revisions have been exported.
+	span roachpb.Span
+	// pending are the rows whose revisions have been exported but not decoded
+	// yet. Unless span is empty, the last pending row may have more revisions
+	// in span, so it is only decoded once span has been exported further.
+	pending []*tableChangesRow
+	// changes are the decoded changes which have not been returned yet, and
+	// changesSize the memory accounted for them.
+	changes     []tree.Datums
+	changesSize int64
+	// acc accounts for the pending rows and the decoded changes.
+	acc  mon.BoundAccount
+	cur  tree.Datums
+	done bool
+	err  error
+}
+
+var _ eval.InternalRows = &tableChangesRows{}
+
+// Next is part of the eval.InternalRows interface.
+func (r *tableChangesRows) Next(ctx context.Context) (bool, error) {
+	if r.done {
+		return false, r.err
+	}
+	var err error
+	for len(r.changes) == 0 {
+		r.acc.Shrink(ctx, r.changesSize)
+		r.changesSize = 0
+		complete := len(r.pending)
+		if len(r.span.Key) != 0 {
+			complete--
+		}
+		if complete > tableChangesScanBatchSize {
+			complete = tableChangesScanBatchSize
+		}
+		if complete > 0 {
+			err = r.decodeBatch(ctx, complete)
+		} else if len(r.span.Key) != 0 {
+			err = r.exportBatch(ctx)
+		} else {
+			break
+		}
+		if err != nil {
+			break
+		}
+	}
+	if len(r.changes) == 0 || err != nil {
+		r.done, r.err = true, err
+		r.cur = nil
+		r.changes, r.pending = nil, nil
+		r.acc.Clear(ctx)
+		return false, err
+	}
+	r.cur, r.changes[0] = r.changes[0], nil
+	r.changes = r.changes[1:]
+	return true, nil
+}
+
+// decodeBatch reads the initial state of the first n pending rows, which must
+// have all their revisions exported, and decodes their changes.
+func (r *tableChangesRows) decodeBatch(ctx context.Context, n int) error {
+	batch := r.pending[:n]
+	if err := r.p.scanRowsAt(ctx, batch, r.startTime); err != nil {
+		return err
+	}
+	var released int64
+	for _, row := range batch {
+		var initialSize int64
+		for k, v := range row.initial {
+			initialSize += int64(len(k) + len(v))
+		}
+		if err := r.acc.Grow(ctx, initialSize); err != nil {
+			return err
+		}
+		released += row.size + initialSize
+		changes, err := r.decoder.decodeChanges(ctx, row)
+		if err != nil {
+			return err
+		}
+		for _, c := range changes {
+			var size int64
+			for _, d := range c {
+				size += int64(d.Size())
+			}
+			if err := r.acc.Grow(ctx, size); err != nil {
+				return err
+			}
+			r.changesSize += size
+		}
+		r.changes = append(r.changes, changes...)
+	}
+	m := copy(r.pending, r.pending[n:])
+	for i := m; i < len(r.pending); i++ {
+		r.pending[i] = nil
+	}
+	r.pending = r.pending[:m]
+	r.acc.Shrink(ctx, released)
+	return nil
+}
+
+// Cur is part of the eval.InternalRows interface.
+func (r *tableChangesRows) Cur() tree.Datums {
+	return r.cur
+}
+
+// Close is part of the eval.InternalRows interface.
+func (r *tableChangesRows) Close() error {
+	if !r.done {
+		r.done = true
+		r.changes, r.pending = nil, nil
+		r.acc.Clear(context.TODO())
+	}
+	return nil
+}
@@ -38,6 +38,8 @@ import (
 	plpgsql "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/regions"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree/utils"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -110,6 +112,9 @@ func (p *planner) writeTypeSchemaChange(
 	// Check if there is a cached specification for this type, otherwise create one.
 	record, recordExists := p.extendedEvalCtx.jobs.uniqueToCreate[typeDesc.ID]
 	transitioningMembers, beingDropped := findTransitioningMembers(typeDesc)
+	// The job must also be cancelable when it validates the constraints added
+	// to a domain, so that the constraints are removed if the validation fails.
+	cancelable := beingDropped || domainHasValidatingConstraints(typeDesc)
 	if recordExists {
 		// Update it.
 		newDetails := jobspb.TypeSchemaChangeDetails{
@@ -127,8 +132,8 @@ func (p *planner) writeTypeSchemaChange(
 					return nonCancelable
 				}
 				// Type change jobs are non-cancelable unless an enum member is being
-				// dropped.
-				return !beingDropped
+				// dropped or a domain constraint is being validated.
+				return !cancelable
 			})
 		log.Infof(ctx, "job %d: updated with type change for type %d", record.JobID, typeDesc.ID)
 	} else {
@@ -144,8 +149,9 @@ func (p *planner) writeTypeSchemaChange(
 			},
 			Progress: jobspb.TypeSchemaChangeProgress{},
 			// Type change jobs in general are not cancelable, unless they include
-			// a transition that drops an enum member.
-			NonCancelable: !beingDropped,
+			// a transition that drops an enum member or validates a domain
+			// constraint.
+			NonCancelable: !cancelable,
 		}
 		p.extendedEvalCtx.jobs.uniqueToCreate[typeDesc.ID] = &newRecord
 		log.Infof(ctx, "queued new type change job %d for type %d", newRecord.JobID, typeDesc.ID)
@@ -494,6 +500,49 @@ func (t *typeSchemaChanger) exec(ctx context.Context) error {
 		}
 	}
 
+	// Validate the constraints being added to a domain against the values
+	// stored in the columns of the domain type, and make them public.
+	if !typeDesc.Dropped() && domainHasValidatingConstraints(typeDesc) {
+		if err := t.execCfg.InternalDB.DescsTxn(ctx, t.validateDomainConstraints); err != nil {
+			return err
+		}
+		run := func(ctx context.Context, txn descs.Txn) error {
+			typeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, t.typeID)
+			if err != nil {
+				return err
+			}
+			for i := range typeDesc.Domain.Constraints {
+				c := &typeDesc.Domain.Constraints[i]
+				if c.Validity == descpb.ConstraintValidity_Validating {
+					c.Validity = descpb.ConstraintValidity_Validated
+				}
+			}
+			b := txn.KV().NewBatch()
+			if err := txn.Descriptors().WriteDescToBatch(
+				ctx, kvTrace, typeDesc, b,
+			); err != nil {
+				return err
+			}
+			// As for enums, bump the version of the array type as well.
+			arrayTypeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, typeDesc.ArrayTypeID)
+			if err != nil {
+				return err
+			}
+			if err := txn.Descriptors().WriteDescToBatch(
+				ctx, kvTrace, arrayTypeDesc, b,
+			); err != nil {
+				return err
+			}
+			return txn.KV().Run(ctx, b)
+		}
+		if err := t.execCfg.InternalDB.DescsTxn(ctx, run); err != nil {
+			return err
+		}
+		if err := refreshTypeDescriptorLeases(ctx, leaseMgr, t.execCfg.DB, typeDesc); err != nil {
+			return err
+		}
+	}
+
 	// If the type is being dropped, remove the descriptor here only
 	// if the declarative schema changer is not in use.
 	if typeDesc.Dropped() && typeDesc.GetDeclarativeSchemaChangerState() == nil {
@@ -604,6 +653,116 @@ func (t *typeSchemaChanger) cleanupEnumValues(ctx context.Context) error {
 	return t.execCfg.InternalDB.DescsTxn(ctx, cleanup)
 }
 
+// domainHasValidatingConstraints returns whether the type is a domain with
+// constraints which are being added.
+func domainHasValidatingConstraints(typeDesc catalog.TypeDescriptor) bool {
+	domain := typeDesc.AsDomainTypeDescriptor()
+	if domain == nil {
+		return false
+	}
+	for i := 0; i < domain.NumDomainConstraints(); i++ {
+		if domain.GetDomainConstraint(i).Validity == descpb.ConstraintValidity_Validating {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDomainConstraints returns an error if a value stored in a table
+// column of the domain type violates one of the constraints being added to the
+// domain. Arrays of the domain type are not validated.
+func (t *typeSchemaChanger) validateDomainConstraints(ctx context.Context, txn descs.Txn) error {
+	typeDesc, err := txn.Descriptors().ByID(txn.KV()).Get().Type(ctx, t.typeID)
+	if err != nil {
+		return err
+	}
+	domain := typeDesc.AsDomainTypeDescriptor()
+	if domain == nil {
+		return errors.AssertionFailedf("type %q is not a domain", typeDesc.GetName())
+	}
+	dbDesc, err := txn.Descriptors().ByID(txn.KV()).WithoutNonPublic().Get().Database(ctx, typeDesc.GetParentID())
+	if err != nil {
+		return err
+	}
+	override := sessiondata.InternalExecutorOverride{
+		User:     username.NodeUserName(),
+		Database: dbDesc.GetName(),
+	}
+	domainOID := catid.TypeIDToOID(typeDesc.GetID())
+	for i := 0; i < typeDesc.NumReferencingDescriptors(); i++ {
+		desc, err := txn.Descriptors().ByID(txn.KV()).Get().Desc(ctx, typeDesc.GetReferencingDescriptorID(i))
+		if err != nil {
+			return err
+		}
+		tbl, ok := desc.(catalog.TableDescriptor)
+		if !ok || tbl.Dropped() || !tbl.IsPhysicalTable() {
+			continue
+		}
+		for _, col := range tbl.PublicColumns() {
+			if col.GetType().Oid() != domainOID {
+				continue
+			}
+			for j := 0; j < domain.NumDomainConstraints(); j++ {
+				c := domain.GetDomainConstraint(j)
+				if c.Validity != descpb.ConstraintValidity_Validating {
+					continue
+				}
+				expr, err := parser.ParseExpr(c.Expr)
+				if err != nil {
+					return err
+				}
+				// The column is cast to the base type of the domain, so that the
+				// expression is evaluated as when a value is cast to the domain.
+				expr, err = eval.SubstituteDomainValue(expr, &tree.CastExpr{
+					Expr:       &tree.ColumnItem{ColumnName: col.ColName()},
+					Type:       domain.BaseType(),
+					SyntaxMode: tree.CastShort,
+				})
+				if err != nil {
+					return err
+				}
+				query := fmt.Sprintf(
+					"SELECT 1 FROM [%d AS t] WHERE NOT (%s) LIMIT 1", tbl.GetID(), tree.Serialize(expr),
+				)
+				row, err := txn.QueryRowEx(ctx, "validate-domain-constraint", txn.KV(), override, query)
+				if err != nil {
+					return err
+				}
+				if row != nil {
+					return pgerror.Newf(pgcode.CheckViolation,
+						"column %q of table %q contains values that violate the new constraint",
+						col.GetName(), tbl.GetName())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// cleanupDomainConstraints removes the constraints which were being added to a
+// domain when the type schema change fails.
+func (t *typeSchemaChanger) cleanupDomainConstraints(ctx context.Context) error {
+	cleanup := func(ctx context.Context, txn descs.Txn) error {
+		typeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, t.typeID)
+		if err != nil {
+			return err
+		}
+		// No cleanup required.
+		if !domainHasValidatingConstraints(typeDesc) {
+			return nil
+		}
+		kept := typeDesc.Domain.Constraints[:0]
+		for _, c := range typeDesc.Domain.Constraints {
+			if c.Validity != descpb.ConstraintValidity_Validating {
+				kept = append(kept, c)
+			}
+		}
+		typeDesc.Domain.Constraints = kept
+		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, typeDesc, txn.KV())
+	}
+	return t.execCfg.InternalDB.DescsTxn(ctx, cleanup)
+}
+
 // convertToSQLStringRepresentation takes an array of bytes (the physical
 // representation of an enum) and converts it into a string that can be used
 // in a SQL predicate.
@@ -1370,6 +1529,9 @@ func (t *typeChangeResumer) OnFailOrCancel(
 		if err := tc.cleanupEnumValues(ctx); err != nil {
 			return err
 		}
+		if err := tc.cleanupDomainConstraints(ctx); err != nil {
+			return err
+		}
 
 		if fn := tc.execCfg.TypeSchemaChangerTestingKnobs.RunAfterOnFailOrCancel; fn != nil {
 			return fn()
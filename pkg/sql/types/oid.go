@@ -66,12 +66,15 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_bytea:      Bytes,
 	oid.T_char:       QChar,
 	oid.T_date:       Date,
+	oid.T_daterange:  DateRange,
 	oid.T_float4:     Float4,
 	oid.T_float8:     Float,
 	oid.T_int2:       Int2,
 	oid.T_int2vector: Int2Vector,
 	oid.T_int4:       Int4,
+	oid.T_int4range:  Int4Range,
 	oid.T_int8:       Int,
+	oid.T_int8range:  Int8Range,
 	oid.T_inet:       INet,
 	oid.T_interval:   Interval,
 	// NOTE(sql-exp): Uncomment the line below if we support the JSON type.
@@ -81,6 +84,7 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_jsonb:        Jsonb,
 	oid.T_name:         Name,
 	oid.T_numeric:      Decimal,
+	oid.T_numrange:     NumRange,
 	oid.T_oid:          Oid,
 	oid.T_oidvector:    OidVector,
 	oid.T_pg_lsn:       PGLSN,
@@ -97,6 +101,9 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_timetz:       TimeTZ,
 	oid.T_timestamp:    Timestamp,
 	oid.T_timestamptz:  TimestampTZ,
+	oid.T_trigger:      Trigger,
+	oid.T_tsrange:      TSRange,
+	oid.T_tstzrange:    TSTZRange,
 	oid.T_tsquery:      TSQuery,
 	oid.T_tsvector:     TSVector,
 	oid.T_unknown:      Unknown,
@@ -108,6 +115,15 @@ var OidToType = map[oid.Oid]*T{
 	oidext.T_geometry:  Geometry,
 	oidext.T_geography: Geography,
 	oidext.T_box2d:     Box2D,
+
+	oidext.T_int4multirange: Int4Multirange,
+	oidext.T_int8multirange: Int8Multirange,
+	oidext.T_nummultirange:  NumMultirange,
+	oidext.T_tsmultirange:   TSMultirange,
+	oidext.T_tstzmultirange: TSTZMultirange,
+	oidext.T_datemultirange: DateMultirange,
+
+	oidext.T_jsonpath: Jsonpath,
 }
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
@@ -119,17 +135,21 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_bytea:        oid.T__bytea,
 	oid.T_char:         oid.T__char,
 	oid.T_date:         oid.T__date,
+	oid.T_daterange:    oid.T__daterange,
 	oid.T_float4:       oid.T__float4,
 	oid.T_float8:       oid.T__float8,
 	oid.T_inet:         oid.T__inet,
 	oid.T_int2:         oid.T__int2,
 	oid.T_int2vector:   oid.T__int2vector,
 	oid.T_int4:         oid.T__int4,
+	oid.T_int4range:    oid.T__int4range,
 	oid.T_int8:         oid.T__int8,
+	oid.T_int8range:    oid.T__int8range,
 	oid.T_interval:     oid.T__interval,
 	oid.T_jsonb:        oid.T__jsonb,
 	oid.T_name:         oid.T__name,
 	oid.T_numeric:      oid.T__numeric,
+	oid.T_numrange:     oid.T__numrange,
 	oid.T_oid:          oid.T__oid,
 	oid.T_oidvector:    oid.T__oidvector,
 	oid.T_pg_lsn:       oid.T__pg_lsn,
@@ -147,6 +167,8 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_timestamp:    oid.T__timestamp,
 	oid.T_timestamptz:  oid.T__timestamptz,
 	oid.T_tsquery:      oid.T__tsquery,
+	oid.T_tsrange:      oid.T__tsrange,
+	oid.T_tstzrange:    oid.T__tstzrange,
 	oid.T_tsvector:     oid.T__tsvector,
 	oid.T_uuid:         oid.T__uuid,
 	oid.T_varbit:       oid.T__varbit,
@@ -155,6 +177,15 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oidext.T_geometry:  oidext.T__geometry,
 	oidext.T_geography: oidext.T__geography,
 	oidext.T_box2d:     oidext.T__box2d,
+
+	oidext.T_int4multirange: oidext.T__int4multirange,
+	oidext.T_int8multirange: oidext.T__int8multirange,
+	oidext.T_nummultirange:  oidext.T__nummultirange,
+	oidext.T_tsmultirange:   oidext.T__tsmultirange,
+	oidext.T_tstzmultirange: oidext.T__tstzmultirange,
+	oidext.T_datemultirange: oidext.T__datemultirange,
+
+	oidext.T_jsonpath: oidext.T__jsonpath,
 }
 
 // familyToOid maps each type family to a default OID value that is used when
@@ -183,8 +214,11 @@ var familyToOid = map[Family]oid.Oid{
 	TimeTZFamily:         oid.T_timetz,
 	JsonFamily:           oid.T_jsonb,
 	TSQueryFamily:        oid.T_tsquery,
+	JsonpathFamily:       oidext.T_jsonpath,
 	TSVectorFamily:       oid.T_tsvector,
 	TupleFamily:          oid.T_record,
+	RangeFamily:          oid.T_anyrange,
+	MultirangeFamily:     oidext.T_anymultirange,
 	BitFamily:            oid.T_bit,
 	AnyFamily:            oid.T_anyelement,
 
@@ -207,6 +241,9 @@ func init() {
 // type.
 func CalcArrayOid(elemTyp *T) oid.Oid {
 	o := elemTyp.Oid()
+	if elemTyp.IsDomain() {
+		return elemTyp.UserDefinedArrayOID()
+	}
 	switch elemTyp.Family() {
 	case ArrayFamily:
 		// Postgres nested arrays return the OID of the nested array (i.e. the
@@ -219,6 +256,11 @@ func CalcArrayOid(elemTyp *T) oid.Oid {
 			return o
 		}
 
+	case AnyFamily:
+		if o == oidext.T_anycompatible {
+			return oidext.T_anycompatiblearray
+		}
+
 	case UnknownFamily:
 		// Postgres doesn't have an OID for an array of unknown values, since
 		// it's not possible to create that in Postgres. But CRDB does allow that,
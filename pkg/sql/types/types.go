@@ -215,6 +215,9 @@ type UserDefinedTypeMetadata struct {
 	// EnumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
 
+	// DomainData is non-nil iff the metadata is for a DOMAIN type.
+	DomainData *DomainMetadata
+
 	// Version is the descriptor version of the descriptor used to construct
 	// this version of the type metadata.
 	Version uint32
@@ -225,6 +228,17 @@ type UserDefinedTypeMetadata struct {
 	ImplicitRecordType bool
 }
 
+// DomainMetadata is metadata about a DOMAIN needed for evaluation.
+type DomainMetadata struct {
+	// NotNull is true if the domain does not allow NULL values.
+	NotNull bool
+	// CheckNames are the names of the CHECK constraints of the domain.
+	CheckNames []string
+	// CheckExprs are the serialized expressions of the CHECK constraints of the
+	// domain, which reference the checked value as VALUE.
+	CheckExprs []string
+}
+
 // EnumMetadata is metadata about an ENUM needed for evaluation.
 type EnumMetadata struct {
 	// PhysicalRepresentations is a slice of the byte array
@@ -517,6 +531,16 @@ var (
 		},
 	}
 
+	// Jsonpath is the jsonpath type, which represents a SQL/JSON path
+	// expression.
+	Jsonpath = &T{
+		InternalType: InternalType{
+			Family: JsonpathFamily,
+			Oid:    oidext.T_jsonpath,
+			Locale: &emptyLocale,
+		},
+	}
+
 	// TSVector is the tsvector type which represents a document compressed in
 	// a form that a tsquery query can operate on.
 	TSVector = &T{
@@ -537,6 +561,86 @@ var (
 		},
 	}
 
+	// Trigger is the pseudo-type used as the return type of trigger functions.
+	// Trigger functions can only be invoked by triggers, so there are no values
+	// of this type.
+	Trigger = &T{
+		InternalType: InternalType{
+			Family: TriggerFamily,
+			Oid:    oid.T_trigger,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int4Range is the type of a range of Int4 values.
+	Int4Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int4range, Locale: &emptyLocale}}
+
+	// Int8Range is the type of a range of Int values.
+	Int8Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int8range, Locale: &emptyLocale}}
+
+	// NumRange is the type of a range of Decimal values.
+	NumRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_numrange, Locale: &emptyLocale}}
+
+	// TSRange is the type of a range of Timestamp values.
+	TSRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tsrange, Locale: &emptyLocale}}
+
+	// TSTZRange is the type of a range of TimestampTZ values.
+	TSTZRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tstzrange, Locale: &emptyLocale}}
+
+	// DateRange is the type of a range of Date values.
+	DateRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_daterange, Locale: &emptyLocale}}
+
+	// Int4Multirange is the type of a multirange of Int4Range values.
+	Int4Multirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_int4multirange, Locale: &emptyLocale}}
+
+	// Int8Multirange is the type of a multirange of Int8Range values.
+	Int8Multirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_int8multirange, Locale: &emptyLocale}}
+
+	// NumMultirange is the type of a multirange of NumRange values.
+	NumMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_nummultirange, Locale: &emptyLocale}}
+
+	// TSMultirange is the type of a multirange of TSRange values.
+	TSMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_tsmultirange, Locale: &emptyLocale}}
+
+	// TSTZMultirange is the type of a multirange of TSTZRange values.
+	TSTZMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_tstzmultirange, Locale: &emptyLocale}}
+
+	// DateMultirange is the type of a multirange of DateRange values.
+	DateMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_datemultirange, Locale: &emptyLocale}}
+
+	// RangeTypes contains all of the predefined range types.
+	RangeTypes = []*T{
+		Int4Range,
+		Int8Range,
+		NumRange,
+		TSRange,
+		TSTZRange,
+		DateRange,
+	}
+
+	// MultirangeTypes contains all of the predefined multirange types, in the
+	// same order as the range types in RangeTypes.
+	MultirangeTypes = []*T{
+		Int4Multirange,
+		Int8Multirange,
+		NumMultirange,
+		TSMultirange,
+		TSTZMultirange,
+		DateMultirange,
+	}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -588,6 +692,14 @@ var (
 	AnyEnum = &T{InternalType: InternalType{
 		Family: EnumFamily, Locale: &emptyLocale, Oid: oid.T_anyenum}}
 
+	// AnyCompatible is a special type used only during static analysis as a
+	// wildcard type in the signature of routines. Like Any, it matches any
+	// other type, but all arguments matching AnyCompatible are coerced to a
+	// common type rather than being required to have the same type.
+	// Execution-time values should never have this type.
+	AnyCompatible = &T{InternalType: InternalType{
+		Family: AnyFamily, Oid: oidext.T_anycompatible, Locale: &emptyLocale}}
+
 	// AnyTuple is a special type used only during static analysis as a wildcard
 	// type that matches a tuple with any number of fields of any type (including
 	// tuple types). Execution-time values should never have this type.
@@ -683,6 +795,12 @@ var (
 	AnyEnumArray = &T{InternalType: InternalType{
 		Family: ArrayFamily, ArrayContents: AnyEnum, Oid: oid.T_anyarray, Locale: &emptyLocale}}
 
+	// AnyCompatibleArray is the type of an array value having
+	// AnyCompatible-typed elements.
+	AnyCompatibleArray = &T{InternalType: InternalType{
+		Family: ArrayFamily, ArrayContents: AnyCompatible, Oid: oidext.T_anycompatiblearray,
+		Locale: &emptyLocale}}
+
 	// JSONBArray is the type of an array value having JSONB-typed elements.
 	JSONBArray = &T{InternalType: InternalType{
 		Family: ArrayFamily, ArrayContents: Jsonb, Oid: oid.T__jsonb, Locale: &emptyLocale}}
@@ -1174,6 +1292,20 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeDomain constructs a new instance of a domain type with the given stable
+// type ID and base type. The domain has the same family and attributes as its
+// base type. Note that it does not hydrate cached fields on the type.
+func MakeDomain(typeOID, arrayTypeOID oid.Oid, base *T) *T {
+	t := *base
+	t.InternalType.Oid = typeOID
+	t.InternalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID:  arrayTypeOID,
+		DomainBaseOID: base.Oid(),
+	}
+	t.TypeMeta = UserDefinedTypeMetadata{}
+	return &t
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1372,6 +1504,10 @@ func (t *T) TypeModifier() int32 {
 // precision. If the given type already has no type modifiers, it is returned
 // unchanged and the function does not allocate a new type.
 func (t *T) WithoutTypeModifiers() *T {
+	if t.IsDomain() {
+		// Domains have no type modifiers.
+		return t
+	}
 	switch t.Family() {
 	case ArrayFamily:
 		// Remove type modifiers of the array content type.
@@ -1444,6 +1580,50 @@ func (t *T) TupleContents() []*T {
 	return t.InternalType.TupleContents
 }
 
+// RangeContents returns the subtype of a range type, or the range type of a
+// multirange type. This is nil for types that are not in the RangeFamily or
+// MultirangeFamily.
+func (t *T) RangeContents() *T {
+	switch t.Oid() {
+	case oid.T_int4range:
+		return Int4
+	case oid.T_int8range:
+		return Int
+	case oid.T_numrange:
+		return Decimal
+	case oid.T_tsrange:
+		return Timestamp
+	case oid.T_tstzrange:
+		return TimestampTZ
+	case oid.T_daterange:
+		return Date
+	case oidext.T_int4multirange:
+		return Int4Range
+	case oidext.T_int8multirange:
+		return Int8Range
+	case oidext.T_nummultirange:
+		return NumRange
+	case oidext.T_tsmultirange:
+		return TSRange
+	case oidext.T_tstzmultirange:
+		return TSTZRange
+	case oidext.T_datemultirange:
+		return DateRange
+	}
+	return nil
+}
+
+// MakeMultirange returns the multirange type corresponding to the given range
+// type.
+func MakeMultirange(rangeTyp *T) *T {
+	for i, t := range RangeTypes {
+		if t.Oid() == rangeTyp.Oid() {
+			return MultirangeTypes[i]
+		}
+	}
+	panic(errors.AssertionFailedf("unexpected range type %s", rangeTyp.SQLStringForError()))
+}
+
 // TupleLabels returns a slice containing the labels of each tuple field. This
 // is nil for types not in the TupleFamily, or if the tuple type does not
 // specify labels.
@@ -1481,6 +1661,25 @@ func (t *T) UserDefined() bool {
 	return IsOIDUserDefinedType(t.Oid())
 }
 
+// IsDomain returns whether or not t is a domain type.
+func (t *T) IsDomain() bool {
+	return t.Family() != ArrayFamily && t.InternalType.UDTMetadata != nil &&
+		t.InternalType.UDTMetadata.DomainBaseOID != 0
+}
+
+// DomainBaseType returns the base type of t if it is a domain type, and t
+// otherwise.
+func (t *T) DomainBaseType() *T {
+	if !t.IsDomain() {
+		return t
+	}
+	base := *t
+	base.InternalType.Oid = t.InternalType.UDTMetadata.DomainBaseOID
+	base.InternalType.UDTMetadata = nil
+	base.TypeMeta = UserDefinedTypeMetadata{}
+	return &base
+}
+
 // IsOIDUserDefinedType returns whether or not o corresponds to a user
 // defined type.
 func IsOIDUserDefinedType(o oid.Oid) bool {
@@ -1505,8 +1704,11 @@ var familyNames = map[Family]redact.SafeString{
 	IntFamily:            "int",
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
+	JsonpathFamily:       "jsonpath",
 	OidFamily:            "oid",
+	MultirangeFamily:     "multirange",
 	PGLSNFamily:          "pg_lsn",
+	RangeFamily:          "range",
 	RefCursorFamily:      "refcursor",
 	StringFamily:         "string",
 	TimeFamily:           "time",
@@ -1515,6 +1717,7 @@ var familyNames = map[Family]redact.SafeString{
 	TimeTZFamily:         "timetz",
 	TSQueryFamily:        "tsquery",
 	TSVectorFamily:       "tsvector",
+	TriggerFamily:        "trigger",
 	TupleFamily:          "tuple",
 	UnknownFamily:        "unknown",
 	UuidFamily:           "uuid",
@@ -1541,8 +1744,14 @@ func (f Family) Name() redact.SafeString {
 //
 // TODO(andyk): Should these be changed to be the same as SQLStandardName?
 func (t *T) Name() string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		return t.TypeMeta.Name.Basename()
+	}
 	switch fam := t.Family(); fam {
 	case AnyFamily:
+		if t.Oid() == oidext.T_anycompatible {
+			return "anycompatible"
+		}
 		return "anyelement"
 
 	case ArrayFamily:
@@ -1601,6 +1810,9 @@ func (t *T) Name() string {
 		}
 		panic(errors.AssertionFailedf("unexpected OID: %d", t.Oid()))
 
+	case RangeFamily, MultirangeFamily:
+		return t.PGName()
+
 	case TupleFamily:
 		return t.SQLStandardName()
 
@@ -1678,9 +1890,15 @@ func (t *T) TelemetryName() string {
 // This function is full of special cases. See backend/utils/adt/format_type.c
 // in Postgres.
 func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		return t.TypeMeta.Name.Basename()
+	}
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
+		if t.Oid() == oidext.T_anycompatible {
+			return "anycompatible"
+		}
 		return "anyelement"
 	case ArrayFamily:
 		switch t.Oid() {
@@ -1782,6 +2000,8 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		default:
 			panic(errors.AssertionFailedf("unexpected Oid: %v", errors.Safe(t.Oid())))
 		}
+	case MultirangeFamily, RangeFamily:
+		return t.PGName()
 	case PGLSNFamily:
 		return "pg_lsn"
 	case RefCursorFamily:
@@ -1843,6 +2063,8 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "timestamp with time zone"
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
+	case JsonpathFamily:
+		return "jsonpath"
 	case TSQueryFamily:
 		return "tsquery"
 	case TSVectorFamily:
@@ -1859,6 +2081,8 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		return "uuid"
 	case VoidFamily:
 		return "void"
+	case TriggerFamily:
+		return "trigger"
 	case EnumFamily:
 		return t.TypeMeta.Name.Basename()
 	default:
@@ -1876,6 +2100,10 @@ func (t *T) InformationSchemaName() string {
 	if t.Family() == ArrayFamily {
 		return "ARRAY"
 	}
+	// Columns of a domain type report the data type of the domain.
+	if t.IsDomain() {
+		return t.DomainBaseType().InformationSchemaName()
+	}
 	// TypeMeta attributes are populated only when it is user defined type.
 	if t.TypeMeta.Name != nil {
 		return "USER-DEFINED"
@@ -1887,6 +2115,13 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.IsDomain() {
+		// See the comment for enums below.
+		if t.TypeMeta.Name == nil {
+			return fmt.Sprintf("@%d", t.Oid())
+		}
+		return t.TypeMeta.Name.FQName()
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -2022,7 +2257,8 @@ func (t *T) SQLStringForError() redact.RedactableString {
 		IntervalFamily, StringFamily, BytesFamily, TimestampTZFamily, CollatedStringFamily, OidFamily,
 		UnknownFamily, UuidFamily, INetFamily, TimeFamily, JsonFamily, TimeTZFamily, BitFamily,
 		GeometryFamily, GeographyFamily, Box2DFamily, VoidFamily, EncodedKeyFamily, TSQueryFamily,
-		TSVectorFamily, AnyFamily, PGLSNFamily, RefCursorFamily:
+		TSVectorFamily, AnyFamily, PGLSNFamily, RefCursorFamily, TriggerFamily, RangeFamily,
+		MultirangeFamily, JsonpathFamily:
 		// These types do not contain other types, and do not require redaction.
 		return redact.Sprint(redact.SafeString(t.SQLString()))
 	}
@@ -2097,6 +2333,12 @@ func (t *T) Equivalent(other *T) bool {
 		if t.Oid() != other.Oid() {
 			return false
 		}
+
+	case RangeFamily, MultirangeFamily:
+		// Range types with different subtypes are not equivalent.
+		if t.Oid() != other.Oid() {
+			return false
+		}
 	}
 
 	return true
@@ -2161,6 +2403,7 @@ func (t *T) Equal(other *T) bool {
 func (t *T) IsWildcardType() bool {
 	for _, wildcard := range []*T{
 		Any, AnyArray, AnyCollatedString, AnyEnum, AnyEnumArray, AnyTuple, AnyTupleArray,
+		AnyCompatible, AnyCompatibleArray,
 	} {
 		// Note that pointer comparison is insufficient since we might have
 		// deserialized t from disk.
@@ -2261,6 +2504,9 @@ func (t *InternalType) Identical(other *InternalType) bool {
 		if t.UDTMetadata.ArrayTypeOID != other.UDTMetadata.ArrayTypeOID {
 			return false
 		}
+		if t.UDTMetadata.DomainBaseOID != other.UDTMetadata.DomainBaseOID {
+			return false
+		}
 	} else if t.UDTMetadata != nil {
 		return false
 	} else if other.UDTMetadata != nil {
@@ -2293,6 +2539,12 @@ func (t *T) Unmarshal(data []byte) error {
 // setting required values. This is necessary to preserve backwards-
 // compatibility with older formats (e.g. restoring database from old backup).
 func (t *T) upgradeType() error {
+	// Domain types are upgraded like their base type, but keep their own OID.
+	if t.IsDomain() {
+		domainOID := t.InternalType.Oid
+		t.InternalType.Oid = t.InternalType.UDTMetadata.DomainBaseOID
+		defer func() { t.InternalType.Oid = domainOID }()
+	}
 	switch t.Family() {
 	case IntFamily:
 		// Check VisibleType field that was populated in previous versions.
@@ -2530,6 +2782,12 @@ func (t *T) MarshalTo(data []byte) (int, error) {
 // CRDB. This is necessary to preserve backwards-compatibility in mixed-version
 // scenarios, such as during upgrade.
 func (t *T) downgradeType() error {
+	// Domain types are downgraded like their base type, but keep their own OID.
+	if t.IsDomain() {
+		domainOID := t.InternalType.Oid
+		t.InternalType.Oid = t.InternalType.UDTMetadata.DomainBaseOID
+		defer func() { t.InternalType.Oid = domainOID }()
+	}
 	// Set Family and VisibleType for 19.1 backwards-compatibility.
 	switch t.Family() {
 	case BitFamily:
@@ -2780,6 +3038,10 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 		return false, 90886
 	case TSVectorFamily:
 		return false, 90886
+	case JsonpathFamily:
+		return false, 22513
+	case RangeFamily, MultirangeFamily:
+		return false, 27791
 	default:
 		return true, 0
 	}
@@ -2989,6 +3251,10 @@ var unreservedTypeTokens = map[string]*T{
 
 	"string": String,
 	"uuid":   Uuid,
+
+	// Polymorphic pseudo-types that are not present in OidToType.
+	"anycompatible":      AnyCompatible,
+	"anycompatiblearray": AnyCompatibleArray,
 }
 
 // The following map must include all types predefined in PostgreSQL
@@ -2999,7 +3265,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"box":           21286,
 	"cidr":          18846,
 	"circle":        21286,
-	"jsonpath":      22513,
 	"line":          21286,
 	"lseg":          21286,
 	"macaddr":       45813,
@@ -889,7 +889,9 @@ CREATE TABLE information_schema.role_column_grants (
 	is_grantable STRING
 )`
 
-// InformationSchemaDomainConstraints is an empty table in the information_schema that is not implemented yet
+// InformationSchemaDomainConstraints describes the schema of the
+// information_schema.domain_constraints table.
+// Postgres: https://www.postgresql.org/docs/current/infoschema-domain-constraints.html
 const InformationSchemaDomainConstraints = `
 CREATE TABLE information_schema.domain_constraints (
 	constraint_catalog STRING,
@@ -974,7 +976,9 @@ CREATE TABLE information_schema.usage_privileges (
 	is_grantable STRING
 )`
 
-// InformationSchemaDomains is an empty table in the information_schema that is not implemented yet
+// InformationSchemaDomains describes the schema of the
+// information_schema.domains table.
+// Postgres: https://www.postgresql.org/docs/current/infoschema-domains.html
 const InformationSchemaDomains = `
 CREATE TABLE information_schema.domains (
 	domain_catalog STRING,
@@ -145,6 +145,22 @@ var upgrades = []upgradebase.Upgrade{
 		upgrade.RestoreActionNotRequired("cluster restore does not preserve the multiregion configuration of the system database"),
 	),
 
+	upgrade.NewTenantUpgrade(
+		"create system.replication_slots table",
+		clusterversion.V24_1_ReplicationSlotsTable.Version(),
+		upgrade.NoPrecondition,
+		createReplicationSlotsTable,
+		upgrade.RestoreActionNotRequired("replication slots are specific to the cluster on which they were created"),
+	),
+
+	upgrade.NewTenantUpgrade(
+		"create system.notifications table",
+		clusterversion.V24_1_NotificationsTable.Version(),
+		upgrade.NoPrecondition,
+		createNotificationsTable,
+		upgrade.RestoreActionNotRequired("notifications are only retained until they are delivered"),
+	),
+
 	// Note: when starting a new release version, the first upgrade (for
 	// Vxy_zStart) must be a newFirstUpgrade. Keep this comment at the bottom.
 }
@@ -0,0 +1,70 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package upgrades_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/upgrade/upgrades"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestV24_1SystemTableMigrations checks that each of the system tables added
+// in 24.1 is only created by the upgrade for its version. The upgrades are
+// run in version order on the same cluster.
+func TestV24_1SystemTableMigrations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	clusterArgs := base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{
+			Knobs: base.TestingKnobs{
+				Server: &server.TestingKnobs{
+					DisableAutomaticVersionUpgrade: make(chan struct{}),
+					BinaryVersionOverride:          clusterversion.MinSupported.Version(),
+				},
+			},
+		},
+	}
+
+	var (
+		ctx   = context.Background()
+		tc    = testcluster.StartTestCluster(t, 1, clusterArgs)
+		sqlDB = tc.ServerConn(0)
+	)
+	defer tc.Stopper().Stop(ctx)
+
+	for _, test := range []struct {
+		table   string
+		version clusterversion.Key
+	}{
+		{table: "replication_slots", version: clusterversion.V24_1_ReplicationSlotsTable},
+		{table: "notifications", version: clusterversion.V24_1_NotificationsTable},
+	} {
+		t.Run(test.table, func(t *testing.T) {
+			query := "SELECT * FROM system.public." + test.table
+			_, err := sqlDB.Exec(query)
+			require.Error(t, err, "system.public.%s should not exist yet", test.table)
+
+			upgrades.Upgrade(t, sqlDB, test.version, nil /* done */, false /* expectError */)
+
+			_, err = sqlDB.Exec(query)
+			require.NoError(t, err, "system.public.%s should exist", test.table)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import "github.com/cockroachdb/cockroach/pkg/util/json"
+
+// maxContainmentKeys is the maximum number of keys of the paths supported by
+// Containments. In lax mode, the number of containments doubles with each key.
+const maxContainmentKeys = 3
+
+// Containments returns a set of JSON documents such that any document for
+// which the path returns at least one item contains (@>) at least one of
+// them. This is used to constrain inverted indexes on JSON columns for the @?
+// operator. ok is false if the path is not of a supported form.
+//
+// The only supported form is a chain of keys followed by a filter which
+// compares the current item with a literal, such as `$.a.b ? (@ == 1)`.
+func (p *Path) Containments() (_ []json.JSON, ok bool) {
+	chain, ok := p.Expr.(*Chain)
+	if !ok || chain.Head != (Root{}) || len(chain.Accessors) == 0 ||
+		len(chain.Accessors) > maxContainmentKeys+1 {
+		return nil, false
+	}
+	n := len(chain.Accessors) - 1
+	keys := make([]string, n)
+	for i, a := range chain.Accessors[:n] {
+		k, ok := a.(*Key)
+		if !ok {
+			return nil, false
+		}
+		keys[i] = k.Name
+	}
+	filter, ok := chain.Accessors[n].(*Filter)
+	if !ok {
+		return nil, false
+	}
+	val, ok := filterEqualityLiteral(filter.Pred)
+	if !ok {
+		return nil, false
+	}
+
+	if p.Strict {
+		// In strict mode, arrays are never unwrapped, so the value must be
+		// nested in objects exactly as described by the keys.
+		for i := n - 1; i >= 0; i-- {
+			val = buildContainmentObject(keys[i], val)
+		}
+		return []json.JSON{val}, true
+	}
+
+	// In lax mode, the filter unwraps an array, and so does the comparison
+	// with the current item, so the value may be nested in up to two arrays.
+	// Each object of the path may also be the element of an array, since the
+	// key accessors unwrap arrays.
+	res := []json.JSON{val, buildContainmentArray(val), buildContainmentArray(buildContainmentArray(val))}
+	for i := n - 1; i >= 0; i-- {
+		next := make([]json.JSON, 0, len(res)*2)
+		for _, v := range res {
+			obj := buildContainmentObject(keys[i], v)
+			next = append(next, obj, buildContainmentArray(obj))
+		}
+		res = next
+	}
+	return res, true
+}
+
+// filterEqualityLiteral returns the literal of a filter predicate of the form
+// `@ == literal` or `literal == @`.
+func filterEqualityLiteral(pred Node) (json.JSON, bool) {
+	b, ok := pred.(*Binary)
+	if !ok || b.Op != OpEq {
+		return nil, false
+	}
+	left, right := b.Left, b.Right
+	if left != (Current{}) {
+		left, right = right, left
+	}
+	lit, ok := right.(*Literal)
+	if left != (Current{}) || !ok {
+		return nil, false
+	}
+	return lit.Value, true
+}
+
+func buildContainmentObject(key string, val json.JSON) json.JSON {
+	b := json.NewObjectBuilder(1)
+	b.Add(key, val)
+	return b.Build()
+}
+
+func buildContainmentArray(val json.JSON) json.JSON {
+	b := json.NewArrayBuilder(1)
+	b.Add(val)
+	return b.Build()
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainments(t *testing.T) {
+	for _, tc := range []struct {
+		path     string
+		expected string
+	}{
+		{`$ ? (@ == 1)`, `1 [1] [[1]]`},
+		{`strict $ ? (@ == 1)`, `1`},
+		{`$.a ? (@ == "x")`,
+			`{"a": "x"} [{"a": "x"}] {"a": ["x"]} [{"a": ["x"]}] {"a": [["x"]]} [{"a": [["x"]]}]`},
+		{`$.a ? (null == @)`,
+			`{"a": null} [{"a": null}] {"a": [null]} [{"a": [null]}] {"a": [[null]]} [{"a": [[null]]}]`},
+		{`strict $.a.b ? (@ == true)`, `{"a": {"b": true}}`},
+		{`strict $.a.b.c ? (@ == 1)`, `{"a": {"b": {"c": 1}}}`},
+
+		// Unsupported paths.
+		{`$`, ``},
+		{`$.a`, ``},
+		{`$.a == 1`, ``},
+		{`$.a[*] ? (@ == 1)`, ``},
+		{`$.a ? (@ > 1)`, ``},
+		{`$.a ? (@ == 1 && @ == 2)`, ``},
+		{`$.a ? (@.b == 1)`, ``},
+		{`$.a ? (@ == $x)`, ``},
+		{`$.a ? (@ == 1).b`, ``},
+		{`$.a.b.c.d ? (@ == 1)`, ``},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := Parse(tc.path)
+			require.NoError(t, err)
+			res, ok := p.Containments()
+			require.Equal(t, tc.expected != "", ok)
+			strs := make([]string, len(res))
+			for i, j := range res {
+				strs[i] = j.String()
+			}
+			require.Equal(t, tc.expected, strings.Join(strs, " "))
+		})
+	}
+
+	// There are 2^n * 3 containments for a lax path with n keys.
+	p, err := Parse(`$.a.b.c ? (@ == 1)`)
+	require.NoError(t, err)
+	res, ok := p.Containments()
+	require.True(t, ok)
+	require.Len(t, res, 24)
+}
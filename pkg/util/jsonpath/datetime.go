@@ -0,0 +1,319 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// datetimeKind is the SQL type of a datetime value.
+type datetimeKind int
+
+const (
+	kindDate datetimeKind = iota
+	kindTime
+	kindTimeTZ
+	kindTimestamp
+	kindTimestampTZ
+)
+
+func (k datetimeKind) String() string {
+	switch k {
+	case kindDate:
+		return "date"
+	case kindTime:
+		return "time without time zone"
+	case kindTimeTZ:
+		return "time with time zone"
+	case kindTimestamp:
+		return "timestamp without time zone"
+	default:
+		return "timestamp with time zone"
+	}
+}
+
+func (k datetimeKind) hasTZ() bool {
+	return k == kindTimeTZ || k == kindTimestampTZ
+}
+
+// datetime is a value produced by the datetime item method.
+type datetime struct {
+	kind datetimeKind
+	// t is the value of the datetime. Values without a time zone are in UTC,
+	// and time values are on 2000-01-01.
+	t time.Time
+}
+
+// String formats the datetime in the ISO 8601 format used for datetime values
+// in JSON.
+func (d *datetime) String() string {
+	const frac = ".999999"
+	switch d.kind {
+	case kindDate:
+		return d.t.Format("2006-01-02")
+	case kindTime:
+		return d.t.Format("15:04:05" + frac)
+	case kindTimeTZ:
+		return d.t.Format("15:04:05" + frac + "-07:00")
+	case kindTimestamp:
+		return d.t.Format("2006-01-02T15:04:05" + frac)
+	default:
+		return d.t.Format("2006-01-02T15:04:05" + frac + "-07:00")
+	}
+}
+
+// compare compares two datetimes. Dates can be compared with timestamps, and
+// ok is false if the datetimes are otherwise of different kinds. An error is
+// returned if one datetime has a time zone and the other does not, since
+// comparing them requires the session time zone.
+func (d *datetime) compare(o *datetime) (cmp int, ok bool, _ error) {
+	isDateOrTimestamp := func(k datetimeKind) bool {
+		return k == kindDate || k == kindTimestamp || k == kindTimestampTZ
+	}
+	if d.kind != o.kind {
+		bothDates := isDateOrTimestamp(d.kind) && isDateOrTimestamp(o.kind)
+		bothTimes := !isDateOrTimestamp(d.kind) && !isDateOrTimestamp(o.kind)
+		if !bothDates && !bothTimes {
+			return 0, false, nil
+		}
+		if d.kind.hasTZ() != o.kind.hasTZ() {
+			return 0, false, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot convert value from %s to %s without time zone usage", d.kind, o.kind)
+		}
+	}
+	return d.t.Compare(o.t), true, nil
+}
+
+// isoTemplates are the templates which are tried, in order, when the datetime
+// method is called without a template.
+var isoTemplates = func() []string {
+	var res []string
+	for _, prefix := range []string{`yyyy-mm-dd HH24:MI:SS`, `yyyy-mm-dd"T"HH24:MI:SS`, `HH24:MI:SS`} {
+		for _, frac := range []string{".US", ""} {
+			for _, tz := range []string{"TZH:TZM", "TZH", ""} {
+				res = append(res, prefix+frac+tz)
+			}
+		}
+	}
+	return append(res, "yyyy-mm-dd")
+}()
+
+// parseDatetime parses a string with the given template, or with one of the
+// ISO 8601 templates if the template is nil.
+func parseDatetime(s string, template *string) (*datetime, error) {
+	if template != nil {
+		return parseDatetimeTemplate(s, *template, false /* std */)
+	}
+	for _, t := range isoTemplates {
+		if d, err := parseDatetimeTemplate(s, t, true /* std */); err == nil {
+			return d, nil
+		}
+	}
+	return nil, newEvalError(pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+		"datetime format is not recognized: %q", s)
+}
+
+// templateFields are the fields supported in datetime templates, with the
+// longest fields first. The template fields are matched case-insensitively.
+var templateFields = []string{
+	"HH24", "HH12", "YYYY", "A.M.", "P.M.", "FF1", "FF2", "FF3", "FF4", "FF5", "FF6",
+	"TZH", "TZM", "HH", "MM", "DD", "MI", "SS", "MS", "US", "AM", "PM",
+}
+
+// parseDatetimeTemplate parses a string with a template in the format of the
+// to_timestamp function of Postgres. Only numeric fields are supported. In
+// std mode, the separators in the template must match the input exactly;
+// otherwise any separator matches any non-alphanumeric character.
+func parseDatetimeTemplate(s, template string, std bool) (*datetime, error) {
+	input := s
+	year, month, day := 2000, 1, 1
+	var hour, minute, sec, nsec, tzh, tzm int
+	tzSign := 1
+	pm, hour12 := -1, false
+	var hasDate, hasTime, hasTZ bool
+
+	invalid := func(format string, args ...interface{}) error {
+		return newEvalError(pgcode.InvalidDatetimeFormat, format, args...)
+	}
+	// readInt reads an unsigned integer of at most maxDigits digits.
+	readInt := func(field string, maxDigits int) (int, int, error) {
+		n, digits := 0, 0
+		for digits < maxDigits && digits < len(s) && s[digits] >= '0' && s[digits] <= '9' {
+			n = n*10 + int(s[digits]-'0')
+			digits++
+		}
+		if digits == 0 {
+			return 0, 0, invalid("invalid value %q for %q", s, field)
+		}
+		s = s[digits:]
+		return n, digits, nil
+	}
+
+	for len(template) > 0 {
+		if template[0] == '"' {
+			end := strings.IndexByte(template[1:], '"')
+			if end < 0 {
+				return nil, invalid("unterminated quoted string in datetime template")
+			}
+			lit := template[1 : end+1]
+			if !strings.HasPrefix(s, lit) {
+				return nil, invalid("unmatched format character %q", lit)
+			}
+			s, template = s[len(lit):], template[end+2:]
+			continue
+		}
+		field := ""
+		for _, f := range templateFields {
+			if len(template) >= len(f) && strings.EqualFold(template[:len(f)], f) {
+				field = f
+				break
+			}
+		}
+		if field == "" {
+			c := template[0]
+			template = template[1:]
+			switch {
+			case len(s) > 0 && s[0] == c:
+				s = s[1:]
+			case !std && !isAlnum(c) && len(s) > 0 && !isAlnum(s[0]):
+				s = s[1:]
+			case !std && c == ' ':
+				// A space in the template matches nothing in the input.
+			default:
+				return nil, invalid("unmatched format character %q", c)
+			}
+			continue
+		}
+		template = template[len(field):]
+
+		var err error
+		switch field {
+		case "YYYY":
+			year, _, err = readInt(field, 4)
+			hasDate = true
+		case "MM":
+			month, _, err = readInt(field, 2)
+			hasDate = true
+		case "DD":
+			day, _, err = readInt(field, 2)
+			hasDate = true
+		case "HH24", "HH12", "HH":
+			hour, _, err = readInt(field, 2)
+			hour12 = hour12 || field != "HH24"
+			hasTime = true
+		case "MI":
+			minute, _, err = readInt(field, 2)
+			hasTime = true
+		case "SS":
+			sec, _, err = readInt(field, 2)
+			hasTime = true
+		case "MS", "US", "FF1", "FF2", "FF3", "FF4", "FF5", "FF6":
+			maxDigits := map[string]int{"MS": 3, "US": 6}[field]
+			if maxDigits == 0 {
+				maxDigits = int(field[2] - '0')
+			}
+			var n, digits int
+			n, digits, err = readInt(field, maxDigits)
+			for ; digits < 9; digits++ {
+				n *= 10
+			}
+			nsec = n
+			hasTime = true
+		case "TZH":
+			if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+				if s[0] == '-' {
+					tzSign = -1
+				}
+				s = s[1:]
+			} else if std {
+				return nil, invalid("invalid value %q for %q", s, field)
+			}
+			tzh, _, err = readInt(field, 2)
+			hasTZ = true
+		case "TZM":
+			tzm, _, err = readInt(field, 2)
+			hasTZ = true
+		case "AM", "PM", "A.M.", "P.M.":
+			switch {
+			case len(s) >= 2 && strings.EqualFold(s[:2], "am"):
+				pm = 0
+				s = s[2:]
+			case len(s) >= 2 && strings.EqualFold(s[:2], "pm"):
+				pm = 1
+				s = s[2:]
+			case len(s) >= 4 && strings.EqualFold(s[:4], "a.m."):
+				pm = 0
+				s = s[4:]
+			case len(s) >= 4 && strings.EqualFold(s[:4], "p.m."):
+				pm = 1
+				s = s[4:]
+			default:
+				err = invalid("invalid value %q for %q", s, field)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(s) > 0 {
+		return nil, invalid("trailing characters remain in input string after datetime format")
+	}
+
+	if hour12 {
+		if hour < 1 || hour > 12 {
+			return nil, invalid(`hour "%d" is invalid for the 12-hour clock`, hour)
+		}
+		if hour == 12 {
+			hour = 0
+		}
+		if pm == 1 {
+			hour += 12
+		}
+	}
+	if month < 1 || month > 12 || day < 1 ||
+		day > time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day() ||
+		hour > 23 || minute > 59 || sec > 59 || tzh > 15 || tzm > 59 {
+		return nil, errors.Mark(pgerror.Newf(pgcode.DatetimeFieldOverflow,
+			"date/time field value out of range: %q", input), errSuppressible)
+	}
+
+	loc := time.UTC
+	if hasTZ {
+		offset := tzSign * (tzh*3600 + tzm*60)
+		loc = time.FixedZone(fmt.Sprintf("%+03d:%02d", tzSign*tzh, tzm), offset)
+	}
+	d := &datetime{t: time.Date(year, time.Month(month), day, hour, minute, sec, nsec, loc)}
+	switch {
+	case hasDate && hasTime && hasTZ:
+		d.kind = kindTimestampTZ
+	case hasDate && hasTime:
+		d.kind = kindTimestamp
+	case hasDate:
+		d.kind = kindDate
+	case hasTZ:
+		d.kind = kindTimeTZ
+	case hasTime:
+		d.kind = kindTime
+	default:
+		return nil, invalid("datetime format is not recognized")
+	}
+	return d, nil
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,970 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// errSuppressible marks the errors which are suppressed when a path is
+// evaluated in silent mode. These are the errors caused by the structure or
+// the values of the queried document, as opposed to errors such as a missing
+// variable.
+var errSuppressible = errors.New("suppressible jsonpath error")
+
+// IsSuppressible returns true if the given error, returned by Path.Eval, is
+// suppressed by the silent argument of the path functions and by the @? and
+// @@ operators.
+func IsSuppressible(err error) bool {
+	return errors.Is(err, errSuppressible)
+}
+
+func newEvalError(code pgcode.Code, format string, args ...interface{}) error {
+	return errors.Mark(pgerror.Newf(code, format, args...), errSuppressible)
+}
+
+// decimalCtx is the context used for the arithmetic operations and numeric
+// methods. It matches the precision of decimals in SQL.
+var decimalCtx = &apd.Context{
+	Precision:   20,
+	Rounding:    apd.RoundHalfUp,
+	MaxExponent: 2000,
+	MinExponent: -2000,
+	Traps:       apd.DefaultTraps,
+}
+
+// exactCtx is used for the arithmetic operations whose result is exact.
+var exactCtx = decimalCtx.WithPrecision(0)
+
+// item is a value of the sequences produced while evaluating a path. It is
+// either a JSON value, or a datetime value produced by the datetime method.
+type item struct {
+	json json.JSON
+	dt   *datetime
+}
+
+func jsonItem(j json.JSON) item { return item{json: j} }
+
+// toJSON converts the item to JSON. Datetime values are converted to strings.
+func (it item) toJSON() json.JSON {
+	if it.dt != nil {
+		return json.FromString(it.dt.String())
+	}
+	return it.json
+}
+
+func (it item) isArray() bool {
+	return it.dt == nil && it.json.Type() == json.ArrayJSONType
+}
+
+func (it item) isObject() bool {
+	return it.dt == nil && it.json.Type() == json.ObjectJSONType
+}
+
+// elements returns the elements of an array item.
+func (it item) elements() ([]item, error) {
+	n := it.json.Len()
+	res := make([]item, n)
+	for i := range res {
+		elem, err := it.json.FetchValIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = jsonItem(elem)
+	}
+	return res, nil
+}
+
+// number returns the value of a numeric item.
+func (it item) number() (*apd.Decimal, bool) {
+	if it.dt != nil {
+		return nil, false
+	}
+	return it.json.AsDecimal()
+}
+
+// typeName returns the name of the type of the item, as returned by the type
+// method.
+func (it item) typeName() string {
+	if it.dt != nil {
+		return it.dt.kind.String()
+	}
+	switch it.json.Type() {
+	case json.NullJSONType:
+		return "null"
+	case json.TrueJSONType, json.FalseJSONType:
+		return "boolean"
+	case json.NumberJSONType:
+		return "number"
+	case json.StringJSONType:
+		return "string"
+	case json.ArrayJSONType:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// tribool is the result of a predicate.
+type tribool int
+
+const (
+	triFalse tribool = iota
+	triTrue
+	triUnknown
+)
+
+func makeTribool(b bool) tribool {
+	if b {
+		return triTrue
+	}
+	return triFalse
+}
+
+// Eval evaluates the path against the given JSON document, and returns the
+// resulting sequence of JSON values. If the path is a predicate, the result is
+// a single boolean, or a JSON null if the predicate is unknown. The named
+// variables of the path are taken from vars, which must be an object if it is
+// not nil.
+//
+// Errors for which IsSuppressible returns true should be ignored by callers
+// that evaluate paths in silent mode.
+func (p *Path) Eval(root json.JSON, vars json.JSON) ([]json.JSON, error) {
+	if vars != nil && vars.Type() != json.ObjectJSONType {
+		return nil, errors.WithDetail(
+			pgerror.New(pgcode.InvalidParameterValue, `"vars" argument is not an object`),
+			`Jsonpath parameters should be encoded as key-value pairs of "vars" object.`,
+		)
+	}
+	e := evaluator{root: root, vars: vars, strict: p.Strict}
+	items, err := e.eval(p.Expr, jsonItem(root))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]json.JSON, len(items))
+	for i, it := range items {
+		res[i] = it.toJSON()
+	}
+	return res, nil
+}
+
+// Exists returns true if the path returns at least one item for the given
+// JSON document.
+func (p *Path) Exists(root json.JSON, vars json.JSON) (bool, error) {
+	res, err := p.Eval(root, vars)
+	if err != nil {
+		return false, err
+	}
+	return len(res) > 0, nil
+}
+
+// Match returns the result of a path which returns a single boolean, such as
+// a predicate. ok is false if the result is unknown, i.e. a JSON null.
+func (p *Path) Match(root json.JSON, vars json.JSON) (_ bool, ok bool, _ error) {
+	res, err := p.Eval(root, vars)
+	if err != nil {
+		return false, false, err
+	}
+	if len(res) == 1 {
+		switch res[0].Type() {
+		case json.TrueJSONType:
+			return true, true, nil
+		case json.FalseJSONType:
+			return false, true, nil
+		case json.NullJSONType:
+			return false, false, nil
+		}
+	}
+	return false, false, newEvalError(pgcode.SingletonSQLJSONItemRequired,
+		"single boolean result is expected")
+}
+
+type evaluator struct {
+	root   json.JSON
+	vars   json.JSON
+	strict bool
+	// last is the index of the last element of the innermost array being
+	// subscripted, which is the value of `last`.
+	last int
+	// keyValueID is the id of the next object processed by the keyvalue
+	// method.
+	keyValueID int
+	regexps    map[*LikeRegex]*regexp.Regexp
+}
+
+// eval evaluates a node, where cur is the value of `@`.
+func (e *evaluator) eval(n Node, cur item) ([]item, error) {
+	if isPredicate(n) {
+		res, err := e.evalPred(n, cur)
+		if err != nil {
+			return nil, err
+		}
+		switch res {
+		case triTrue:
+			return []item{jsonItem(json.TrueJSONValue)}, nil
+		case triFalse:
+			return []item{jsonItem(json.FalseJSONValue)}, nil
+		default:
+			return []item{jsonItem(json.NullJSONValue)}, nil
+		}
+	}
+	switch t := n.(type) {
+	case Root:
+		return []item{jsonItem(e.root)}, nil
+	case Current:
+		return []item{cur}, nil
+	case Last:
+		return []item{jsonItem(json.FromInt(e.last))}, nil
+	case *Variable:
+		var v json.JSON
+		if e.vars != nil {
+			var err error
+			if v, err = e.vars.FetchValKey(t.Name); err != nil {
+				return nil, err
+			}
+		}
+		if v == nil {
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"could not find jsonpath variable %q", t.Name)
+		}
+		return []item{jsonItem(v)}, nil
+	case *Literal:
+		return []item{jsonItem(t.Value)}, nil
+	case *Chain:
+		items, err := e.eval(t.Head, cur)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range t.Accessors {
+			var next []item
+			for _, it := range items {
+				if next, err = e.access(a, it, cur, next); err != nil {
+					return nil, err
+				}
+			}
+			items = next
+		}
+		return items, nil
+	case *Binary:
+		return e.evalArithmetic(t, cur)
+	case *Unary:
+		return e.evalUnary(t, cur)
+	}
+	return nil, errors.AssertionFailedf("unexpected jsonpath node %T", n)
+}
+
+// evalUnwrapped evaluates a node and, in lax mode, replaces each array in the
+// result by its elements.
+func (e *evaluator) evalUnwrapped(n Node, cur item) ([]item, error) {
+	items, err := e.eval(n, cur)
+	if err != nil || e.strict {
+		return items, err
+	}
+	var res []item
+	for _, it := range items {
+		if err := e.unwrap(it, func(elem item) error {
+			res = append(res, elem)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// unwrap calls fn on each element of the item if it is an array and the path
+// is evaluated in lax mode, and on the item itself otherwise.
+func (e *evaluator) unwrap(it item, fn func(item) error) error {
+	if e.strict || !it.isArray() {
+		return fn(it)
+	}
+	elems, err := it.elements()
+	if err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if err := fn(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// access applies an accessor to an item, and appends the results to res.
+func (e *evaluator) access(a Accessor, it item, cur item, res []item) ([]item, error) {
+	switch t := a.(type) {
+	case *Key:
+		err := e.unwrap(it, func(it item) error {
+			if !it.isObject() {
+				if e.strict {
+					return newEvalError(pgcode.SQLJSONObjectNotFound,
+						"jsonpath member accessor can only be applied to an object")
+				}
+				return nil
+			}
+			v, err := it.json.FetchValKey(t.Name)
+			if err != nil {
+				return err
+			}
+			if v == nil {
+				if e.strict {
+					return newEvalError(pgcode.SQLJSONMemberNotFound,
+						"JSON object does not contain key %q", t.Name)
+				}
+				return nil
+			}
+			res = append(res, jsonItem(v))
+			return nil
+		})
+		return res, err
+
+	case AnyKey:
+		err := e.unwrap(it, func(it item) error {
+			if !it.isObject() {
+				if e.strict {
+					return newEvalError(pgcode.SQLJSONObjectNotFound,
+						"jsonpath wildcard member accessor can only be applied to an object")
+				}
+				return nil
+			}
+			iter, err := it.json.ObjectIter()
+			if err != nil {
+				return err
+			}
+			for iter.Next() {
+				res = append(res, jsonItem(iter.Value()))
+			}
+			return nil
+		})
+		return res, err
+
+	case AnyArray:
+		if !it.isArray() {
+			if e.strict {
+				return nil, newEvalError(pgcode.SQLJSONArrayNotFound,
+					"jsonpath wildcard array accessor can only be applied to an array")
+			}
+			return append(res, it), nil
+		}
+		elems, err := it.elements()
+		if err != nil {
+			return nil, err
+		}
+		return append(res, elems...), nil
+
+	case *IndexArray:
+		return e.accessIndexes(t, it, cur, res)
+
+	case *AnyPath:
+		return e.accessAnyPath(t, it, 0 /* level */, res)
+
+	case *Filter:
+		err := e.unwrap(it, func(it item) error {
+			ok, err := e.evalPred(t.Pred, it)
+			if err != nil {
+				return err
+			}
+			if ok == triTrue {
+				res = append(res, it)
+			}
+			return nil
+		})
+		return res, err
+
+	case *Method:
+		return e.evalMethod(t, it, res)
+	}
+	return nil, errors.AssertionFailedf("unexpected jsonpath accessor %T", a)
+}
+
+// accessIndexes applies array subscripts to an item. In lax mode, a non-array
+// item is treated as an array containing only the item, and out of bounds
+// subscripts are ignored.
+func (e *evaluator) accessIndexes(a *IndexArray, it item, cur item, res []item) ([]item, error) {
+	var elems []item
+	if it.isArray() {
+		var err error
+		if elems, err = it.elements(); err != nil {
+			return nil, err
+		}
+	} else if e.strict {
+		return nil, newEvalError(pgcode.SQLJSONArrayNotFound,
+			"jsonpath array accessor can only be applied to an array")
+	} else {
+		elems = []item{it}
+	}
+
+	prevLast := e.last
+	e.last = len(elems) - 1
+	defer func() { e.last = prevLast }()
+	for _, s := range a.Subscripts {
+		from, err := e.evalIndex(s.From, cur)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if s.To != nil {
+			if to, err = e.evalIndex(s.To, cur); err != nil {
+				return nil, err
+			}
+		}
+		if from < 0 || from > to || to >= len(elems) {
+			if e.strict {
+				return nil, newEvalError(pgcode.InvalidSQLJSONSubscript,
+					"jsonpath array subscript is out of bounds")
+			}
+			if from < 0 {
+				from = 0
+			}
+			if to >= len(elems) {
+				to = len(elems) - 1
+			}
+		}
+		for i := from; i <= to; i++ {
+			res = append(res, elems[i])
+		}
+	}
+	return res, nil
+}
+
+// evalIndex evaluates an array subscript, which must be a single number. The
+// number is truncated to an integer.
+func (e *evaluator) evalIndex(n Node, cur item) (int, error) {
+	items, err := e.evalUnwrapped(n, cur)
+	if err != nil {
+		return 0, err
+	}
+	var d *apd.Decimal
+	ok := len(items) == 1
+	if ok {
+		d, ok = items[0].number()
+	}
+	if !ok {
+		return 0, newEvalError(pgcode.InvalidSQLJSONSubscript,
+			"jsonpath array subscript is not a single numeric value")
+	}
+	var i, frac apd.Decimal
+	d.Modf(&i, &frac)
+	idx, err := i.Int64()
+	if err != nil || idx > math.MaxInt32 || idx < math.MinInt32 {
+		return 0, newEvalError(pgcode.InvalidSQLJSONSubscript,
+			"jsonpath array subscript is out of integer range")
+	}
+	return int(idx), nil
+}
+
+// accessAnyPath appends the item and its descendants within the levels of
+// the accessor to res. The item itself is at the given level.
+func (e *evaluator) accessAnyPath(a *AnyPath, it item, level int, res []item) ([]item, error) {
+	if level >= a.First && (a.Last == anyLevelLast || level <= a.Last) {
+		res = append(res, it)
+	}
+	if a.Last != anyLevelLast && level >= a.Last {
+		return res, nil
+	}
+	var children []item
+	switch {
+	case it.isArray():
+		var err error
+		if children, err = it.elements(); err != nil {
+			return nil, err
+		}
+	case it.isObject():
+		iter, err := it.json.ObjectIter()
+		if err != nil {
+			return nil, err
+		}
+		for iter.Next() {
+			children = append(children, jsonItem(iter.Value()))
+		}
+	}
+	for _, c := range children {
+		var err error
+		if res, err = e.accessAnyPath(a, c, level+1, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// evalMethod applies an item method to an item, and appends the results to
+// res.
+func (e *evaluator) evalMethod(m *Method, it item, res []item) ([]item, error) {
+	switch m.Name {
+	case "type":
+		return append(res, jsonItem(json.FromString(it.typeName()))), nil
+
+	case "size":
+		if it.isArray() {
+			return append(res, jsonItem(json.FromInt(it.json.Len()))), nil
+		}
+		if e.strict {
+			return nil, newEvalError(pgcode.SQLJSONArrayNotFound,
+				"jsonpath item method .size() can only be applied to an array")
+		}
+		return append(res, jsonItem(json.FromInt(1))), nil
+	}
+
+	err := e.unwrap(it, func(it item) error {
+		var r item
+		var err error
+		switch m.Name {
+		case "double":
+			r, err = evalDouble(it)
+		case "ceiling", "floor", "abs":
+			r, err = evalNumericMethod(m.Name, it)
+		case "keyvalue":
+			return e.evalKeyValue(it, &res)
+		case "datetime":
+			if it.dt != nil || it.json.Type() != json.StringJSONType {
+				return newEvalError(pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+					"jsonpath item method .datetime() can only be applied to a string")
+			}
+			s, err := it.json.AsText()
+			if err != nil {
+				return err
+			}
+			dt, err := parseDatetime(*s, m.Arg)
+			if err != nil {
+				return err
+			}
+			r = item{dt: dt}
+		default:
+			return errors.AssertionFailedf("unknown jsonpath method %s", m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		res = append(res, r)
+		return nil
+	})
+	return res, err
+}
+
+func evalDouble(it item) (item, error) {
+	var f float64
+	if d, ok := it.number(); ok {
+		var err error
+		if f, err = d.Float64(); err != nil {
+			return item{}, err
+		}
+	} else if it.dt == nil && it.json.Type() == json.StringJSONType {
+		s, err := it.json.AsText()
+		if err != nil {
+			return item{}, err
+		}
+		if f, err = strconv.ParseFloat(strings.TrimSpace(*s), 64); err != nil {
+			return item{}, newEvalError(pgcode.NonNumericSQLJSONItem,
+				"argument %q of jsonpath item method .double() is invalid for type double precision", *s)
+		}
+	} else {
+		return item{}, newEvalError(pgcode.NonNumericSQLJSONItem,
+			"jsonpath item method .double() can only be applied to a string or numeric value")
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return item{}, newEvalError(pgcode.NonNumericSQLJSONItem,
+			"NaN or Infinity is not allowed for jsonpath item method .double()")
+	}
+	j, err := json.FromFloat64(f)
+	return jsonItem(j), err
+}
+
+func evalNumericMethod(name string, it item) (item, error) {
+	d, ok := it.number()
+	if !ok {
+		return item{}, newEvalError(pgcode.NonNumericSQLJSONItem,
+			"jsonpath item method .%s() can only be applied to a numeric value", name)
+	}
+	var r apd.Decimal
+	var err error
+	switch name {
+	case "ceiling":
+		_, err = exactCtx.Ceil(&r, d)
+	case "floor":
+		_, err = exactCtx.Floor(&r, d)
+	case "abs":
+		r.Abs(d)
+	}
+	if err != nil {
+		return item{}, err
+	}
+	return jsonItem(json.FromDecimal(r)), nil
+}
+
+// evalKeyValue appends an object of the form {"key": k, "value": v, "id": n}
+// to res for each key-value pair of an object item. The id identifies the
+// object the pair belongs to. Unlike Postgres, which derives it from the
+// location of the object in the binary representation of the document, ids
+// are assigned sequentially in the order in which objects are processed.
+func (e *evaluator) evalKeyValue(it item, res *[]item) error {
+	if !it.isObject() {
+		return newEvalError(pgcode.SQLJSONObjectNotFound,
+			"jsonpath item method .keyvalue() can only be applied to an object")
+	}
+	id := json.FromInt(e.keyValueID)
+	e.keyValueID++
+	iter, err := it.json.ObjectIter()
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		b := json.NewObjectBuilder(3)
+		b.Add("id", id)
+		b.Add("key", json.FromString(iter.Key()))
+		b.Add("value", iter.Value())
+		*res = append(*res, jsonItem(b.Build()))
+	}
+	return nil
+}
+
+// evalArithmetic evaluates a binary arithmetic operation, whose operands must
+// each be a single number.
+func (e *evaluator) evalArithmetic(b *Binary, cur item) ([]item, error) {
+	operand := func(n Node, side string) (*apd.Decimal, error) {
+		items, err := e.evalUnwrapped(n, cur)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 1 {
+			if d, ok := items[0].number(); ok {
+				return d, nil
+			}
+		}
+		return nil, newEvalError(pgcode.SingletonSQLJSONItemRequired,
+			"%s operand of jsonpath operator %s is not a single numeric value", side, b.Op)
+	}
+	l, err := operand(b.Left, "left")
+	if err != nil {
+		return nil, err
+	}
+	r, err := operand(b.Right, "right")
+	if err != nil {
+		return nil, err
+	}
+	var res apd.Decimal
+	switch b.Op {
+	case OpAdd:
+		_, err = exactCtx.Add(&res, l, r)
+	case OpSub:
+		_, err = exactCtx.Sub(&res, l, r)
+	case OpMul:
+		_, err = exactCtx.Mul(&res, l, r)
+	case OpDiv, OpMod:
+		if r.IsZero() {
+			return nil, newEvalError(pgcode.DivisionByZero, "division by zero")
+		}
+		if b.Op == OpDiv {
+			_, err = decimalCtx.Quo(&res, l, r)
+		} else {
+			_, err = decimalCtx.Rem(&res, l, r)
+		}
+	default:
+		return nil, errors.AssertionFailedf("unexpected jsonpath operator %s", b.Op)
+	}
+	if err != nil {
+		return nil, errors.Mark(
+			pgerror.WithCandidateCode(err, pgcode.NumericValueOutOfRange), errSuppressible,
+		)
+	}
+	return []item{jsonItem(json.FromDecimal(res))}, nil
+}
+
+// evalUnary evaluates a unary plus or minus operation, which is applied to
+// each item of its operand.
+func (e *evaluator) evalUnary(u *Unary, cur item) ([]item, error) {
+	items, err := e.evalUnwrapped(u.Arg, cur)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]item, len(items))
+	for i, it := range items {
+		d, ok := it.number()
+		if !ok {
+			return nil, newEvalError(pgcode.NonNumericSQLJSONItem,
+				"operand of unary jsonpath operator %s is not a numeric value", u.Op)
+		}
+		if u.Op == OpMinus {
+			var neg apd.Decimal
+			neg.Neg(d)
+			it = jsonItem(json.FromDecimal(neg))
+		}
+		res[i] = it
+	}
+	return res, nil
+}
+
+// evalPred evaluates a predicate. Suppressible errors that occur while
+// evaluating the operands of the predicate make it unknown.
+func (e *evaluator) evalPred(n Node, cur item) (tribool, error) {
+	switch t := n.(type) {
+	case *Binary:
+		switch t.Op {
+		case OpAnd:
+			l, err := e.evalPred(t.Left, cur)
+			if err != nil || l == triFalse {
+				return triFalse, err
+			}
+			r, err := e.evalPred(t.Right, cur)
+			if err != nil || r == triTrue {
+				return l, err
+			}
+			return r, nil
+		case OpOr:
+			l, err := e.evalPred(t.Left, cur)
+			if err != nil || l == triTrue {
+				return triTrue, err
+			}
+			r, err := e.evalPred(t.Right, cur)
+			if err != nil || r == triFalse {
+				return l, err
+			}
+			return r, nil
+		}
+		return e.evalComparison(t, cur)
+	case *Not:
+		res, err := e.evalPred(t.Arg, cur)
+		switch res {
+		case triTrue:
+			return triFalse, err
+		case triFalse:
+			return triTrue, err
+		}
+		return triUnknown, err
+	case *IsUnknown:
+		res, err := e.evalPred(t.Arg, cur)
+		return makeTribool(res == triUnknown), err
+	case *Exists:
+		items, err := e.eval(t.Arg, cur)
+		if err != nil {
+			if IsSuppressible(err) {
+				return triUnknown, nil
+			}
+			return triUnknown, err
+		}
+		return makeTribool(len(items) > 0), nil
+	case *LikeRegex:
+		re, err := e.regexp(t)
+		if err != nil {
+			return triUnknown, err
+		}
+		return e.evalItemPred(t.Arg, cur, func(it item) tribool {
+			s, ok := it.text()
+			if !ok {
+				return triUnknown
+			}
+			return makeTribool(re.MatchString(s))
+		})
+	}
+	return triUnknown, errors.AssertionFailedf("unexpected jsonpath predicate %T", n)
+}
+
+// evalComparison evaluates a comparison or a starts with predicate.
+func (e *evaluator) evalComparison(b *Binary, cur item) (tribool, error) {
+	right, err := e.evalUnwrapped(b.Right, cur)
+	if err != nil {
+		if IsSuppressible(err) {
+			return triUnknown, nil
+		}
+		return triUnknown, err
+	}
+	if b.Op == OpStartsWith {
+		var prefix string
+		ok := len(right) == 1
+		if ok {
+			prefix, ok = right[0].text()
+		}
+		if !ok {
+			return triUnknown, nil
+		}
+		return e.evalItemPred(b.Left, cur, func(it item) tribool {
+			s, ok := it.text()
+			if !ok {
+				return triUnknown
+			}
+			return makeTribool(strings.HasPrefix(s, prefix))
+		})
+	}
+	var hardErr error
+	res, err := e.evalItemPred(b.Left, cur, func(l item) tribool {
+		var res tribool
+		for _, r := range right {
+			var cmp tribool
+			cmp, hardErr = compareItems(b.Op, l, r)
+			if hardErr != nil {
+				return triUnknown
+			}
+			switch {
+			case cmp == triUnknown && e.strict, cmp == triTrue && !e.strict:
+				return cmp
+			case cmp == triUnknown || res == triFalse:
+				res = cmp
+			}
+		}
+		return res
+	})
+	if hardErr != nil {
+		return triUnknown, hardErr
+	}
+	return res, err
+}
+
+// evalItemPred evaluates the node, and tests each item of the result with
+// the given function. In lax mode, the predicate is true if it is true for
+// any item, and is otherwise unknown if it is unknown for any item. In strict
+// mode, the predicate is unknown if it is unknown for any item, and is
+// otherwise true if it is true for any item.
+func (e *evaluator) evalItemPred(n Node, cur item, fn func(item) tribool) (tribool, error) {
+	items, err := e.evalUnwrapped(n, cur)
+	if err != nil {
+		if IsSuppressible(err) {
+			return triUnknown, nil
+		}
+		return triUnknown, err
+	}
+	res := triFalse
+	for _, it := range items {
+		switch fn(it) {
+		case triTrue:
+			if !e.strict {
+				return triTrue, nil
+			}
+			if res == triFalse {
+				res = triTrue
+			}
+		case triUnknown:
+			if e.strict {
+				return triUnknown, nil
+			}
+			res = triUnknown
+		}
+	}
+	return res, nil
+}
+
+// text returns the value of a string item.
+func (it item) text() (string, bool) {
+	if it.dt != nil || it.json.Type() != json.StringJSONType {
+		return "", false
+	}
+	s, err := it.json.AsText()
+	if err != nil {
+		return "", false
+	}
+	return *s, true
+}
+
+// compareItems applies a comparison operator to two items. The result is
+// unknown if the items are not comparable. An error is only returned for
+// datetime values which cannot be compared without a time zone.
+func compareItems(op Operator, l, r item) (tribool, error) {
+	var cmp int
+	if l.dt != nil || r.dt != nil {
+		if l.dt == nil || r.dt == nil {
+			return triUnknown, nil
+		}
+		var ok bool
+		var err error
+		if cmp, ok, err = l.dt.compare(r.dt); err != nil || !ok {
+			return triUnknown, err
+		}
+	} else {
+		lt, rt := l.json.Type(), r.json.Type()
+		isBool := func(t json.Type) bool { return t == json.TrueJSONType || t == json.FalseJSONType }
+		if lt != rt && !(isBool(lt) && isBool(rt)) {
+			if lt == json.NullJSONType || rt == json.NullJSONType {
+				// Nulls are not equal to any other value, and are not ordered
+				// with respect to them.
+				return makeTribool(op == OpNe), nil
+			}
+			return triUnknown, nil
+		}
+		switch lt {
+		case json.ArrayJSONType, json.ObjectJSONType:
+			return triUnknown, nil
+		case json.StringJSONType:
+			ls, _ := l.text()
+			rs, _ := r.text()
+			cmp = strings.Compare(ls, rs)
+		default:
+			var err error
+			if cmp, err = l.json.Compare(r.json); err != nil {
+				return triUnknown, err
+			}
+		}
+	}
+	switch op {
+	case OpEq:
+		return makeTribool(cmp == 0), nil
+	case OpNe:
+		return makeTribool(cmp != 0), nil
+	case OpLt:
+		return makeTribool(cmp < 0), nil
+	case OpLe:
+		return makeTribool(cmp <= 0), nil
+	case OpGt:
+		return makeTribool(cmp > 0), nil
+	case OpGe:
+		return makeTribool(cmp >= 0), nil
+	}
+	return triUnknown, errors.AssertionFailedf("unexpected comparison operator %s", op)
+}
+
+// regexp returns the compiled regular expression of a like_regex predicate.
+func (e *evaluator) regexp(n *LikeRegex) (*regexp.Regexp, error) {
+	if re, ok := e.regexps[n]; ok {
+		return re, nil
+	}
+	re, err := compileRegex(n.Pattern, n.Flags)
+	if err != nil {
+		return nil, err
+	}
+	if e.regexps == nil {
+		e.regexps = make(map[*LikeRegex]*regexp.Regexp)
+	}
+	e.regexps[n] = re
+	return re, nil
+}
+
+// compileRegex compiles the pattern of a like_regex predicate with the given
+// flags. The supported flags are i (case-insensitive), s (. matches newlines),
+// m (^ and $ match at line boundaries) and q (the pattern is matched
+// literally).
+func compileRegex(pattern, flags string) (*regexp.Regexp, error) {
+	if err := validateRegexFlags(flags); err != nil {
+		return nil, err
+	}
+	var prefix string
+	for _, f := range "ism" {
+		if strings.ContainsRune(flags, f) {
+			prefix += string(f)
+		}
+	}
+	if strings.ContainsRune(flags, 'q') {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if prefix != "" {
+		pattern = "(?" + prefix + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidRegularExpression, "invalid regular expression")
+	}
+	return re, nil
+}
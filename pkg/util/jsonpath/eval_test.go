@@ -0,0 +1,255 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	for _, tc := range []struct {
+		doc      string
+		path     string
+		vars     string
+		expected string
+	}{
+		{`{"a": 1}`, `$`, ``, `{"a": 1}`},
+		{`{"a": 1}`, `$.a`, ``, `1`},
+		{`{"a": 1}`, `$.b`, ``, ``},
+		{`{"a": {"b": [1, 2, 3]}}`, `$.a.b[*]`, ``, `1 2 3`},
+		{`{"a": {"b": [1, 2, 3]}}`, `$.a.b[1 to last]`, ``, `2 3`},
+		{`{"a": {"b": [1, 2, 3]}}`, `$.a.b[last - 1, 0]`, ``, `2 1`},
+		{`{"a": {"b": [1, 2, 3]}}`, `$.a.b[0.9]`, ``, `1`},
+		{`{"a": {"b": [1, 2, 3]}}`, `$.a.b[5]`, ``, ``},
+		{`{"a": {"b": [1, 2, 3]}}`, `$.a.b[1 to 5]`, ``, `2 3`},
+		{`{"a": {"b": [1, 2, 3]}}`, `$.a.b[$.a.b[0]]`, ``, `2`},
+		{`{"a": 1, "b": 2}`, `$.*`, ``, `1 2`},
+		{`[1, [2, [3]]]`, `$.**`, ``, `[1, [2, [3]]] 1 [2, [3]] 2 [3] 3`},
+		{`[1, [2, [3]]]`, `$.**{2}`, ``, `2 [3]`},
+		{`[1, [2, [3]]]`, `$.**{2 to last}`, ``, `2 [3] 3`},
+
+		// Lax mode unwraps arrays and wraps non-arrays.
+		{`{"a": [{"b": 1}, {"b": 2}, 3]}`, `$.a.b`, ``, `1 2`},
+		{`{"a": 1}`, `$.a[0]`, ``, `1`},
+		{`{"a": 1}`, `$.a[*]`, ``, `1`},
+		{`{"a": 1}`, `$.a.size()`, ``, `1`},
+
+		// Filters.
+		{`{"a": [1, 2, 3, 4, 5]}`, `$.a[*] ? (@ > 2)`, ``, `3 4 5`},
+		{`{"a": [1, 2, 3, 4, 5]}`, `$.a ? (@ > 2)`, ``, `3 4 5`},
+		{`{"a": [1, 2, 3, 4, 5]}`, `$.a ? (@ > $min && @ < $max)`, `{"min": 1, "max": 4}`, `2 3`},
+		{`[{"a": 1}, {"a": "x"}, {"b": 2}]`, `$[*] ? (@.a == 1)`, ``, `{"a": 1}`},
+		{`[{"a": 1}, {"a": "x"}, {"b": 2}]`, `$[*] ? (!(@.a == 1))`, ``, `{"b": 2}`},
+		{`[{"a": 1}, {"a": "x"}, {"b": 2}]`, `$[*] ? ((@.a == 1) is unknown)`, ``, `{"a": "x"}`},
+		{`[{"a": 1}, {"a": "x"}, {"b": 2}]`, `$[*] ? (exists (@.b))`, ``, `{"b": 2}`},
+		{`[{"a": [1, 2]}, {"a": [3]}]`, `$[*] ? (@.a[*] == 2).a`, ``, `[1, 2]`},
+		{`["abc", "abd", "xyz", 1]`, `$[*] ? (@ starts with "ab")`, ``, `"abc" "abd"`},
+		{`["abc", "ABD", "xyz", 1]`, `$[*] ? (@ like_regex "^ab")`, ``, `"abc"`},
+		{`["abc", "ABD", "xyz", 1]`, `$[*] ? (@ like_regex "^ab" flag "i")`, ``, `"abc" "ABD"`},
+		{`["a.c", "abc"]`, `$[*] ? (@ like_regex "a.c" flag "q")`, ``, `"a.c"`},
+		{`[null, 1, "a"]`, `$[*] ? (@ != null)`, ``, `1 "a"`},
+		{`[null, 1, "a"]`, `$[*] ? (@ == null)`, ``, `null`},
+		{`[true, false]`, `$[*] ? (@ == true)`, ``, `true`},
+
+		// Predicates.
+		{`{"a": 1}`, `$.a == 1`, ``, `true`},
+		{`{"a": 1}`, `$.a == 2`, ``, `false`},
+		{`{"a": 1}`, `$.a == "1"`, ``, `null`},
+		{`{"a": [1, "x"]}`, `$.a[*] == 1`, ``, `true`},
+		{`{"a": [1, "x"]}`, `strict $.a[*] == 1`, ``, `null`},
+		{`{"a": 1}`, `$.b == 1`, ``, `false`},
+		{`{"a": 1}`, `strict $.b == 1`, ``, `null`},
+		{`{"a": 1}`, `$.a == 1 && $.b == 1`, ``, `false`},
+		{`{"a": 1}`, `$.a == 1 || $.a == "x"`, ``, `true`},
+		{`{"a": 1}`, `$.a == "x" || $.a == 2`, ``, `null`},
+		{`{"a": "abc"}`, `$.a starts with "a"`, ``, `true`},
+		{`{"a": [1, 2]}`, `exists($.a ? (@ > 1))`, ``, `true`},
+		{`{"a": [1, 2]}`, `exists($.a ? (@ > 2))`, ``, `false`},
+		{`{"a": 1}`, `strict exists($.b)`, ``, `null`},
+
+		// Arithmetic.
+		{`{"a": 2}`, `$.a + 3`, ``, `5`},
+		{`{"a": 2}`, `$.a * 1.5 - 1`, ``, `2.0`},
+		{`{"a": 7}`, `$.a % 4`, ``, `3`},
+		{`{"a": 1}`, `$.a / 3`, ``, `0.33333333333333333333`},
+		{`{"a": [2]}`, `$.a + 1`, ``, `3`},
+		{`[1, 2, 3]`, `-$[*]`, ``, `-1 -2 -3`},
+		{`[1, 2, 3]`, `$[*] ? (@ * 2 > 3)`, ``, `2 3`},
+
+		// Methods.
+		{`[1, "a", null, true, [], {}]`, `$[*].type()`, ``, `"number" "string" "null" "boolean" "array" "object"`},
+		{`[1, "a", null, true, [], {}]`, `$.type()`, ``, `"array"`},
+		{`[[1, 2], 3]`, `$[*].size()`, ``, `2 1`},
+		{`[1.5, -1.5]`, `$[*].ceiling()`, ``, `2 -1`},
+		{`[1.5, -1.5]`, `$.floor()`, ``, `1 -2`},
+		{`[1.5, -1.5]`, `$.abs()`, ``, `1.5 1.5`},
+		{`["1.5", 2]`, `$.double()`, ``, `1.5 2`},
+		{`{"a": 1, "b": [2]}`, `$.keyvalue()`, ``,
+			`{"id": 0, "key": "a", "value": 1} {"id": 0, "key": "b", "value": [2]}`},
+		{`[{"a": 1}, {"b": 2}]`, `$.keyvalue().key`, ``, `"a" "b"`},
+		{`[{"a": 1}, {"b": 2}]`, `$[*].keyvalue().id`, ``, `0 1`},
+
+		// Datetimes.
+		{`"2024-01-02"`, `$.datetime()`, ``, `"2024-01-02"`},
+		{`"2024-01-02"`, `$.datetime().type()`, ``, `"date"`},
+		{`"2024-01-02 03:04:05"`, `$.datetime()`, ``, `"2024-01-02T03:04:05"`},
+		{`"2024-01-02T03:04:05.5+01"`, `$.datetime()`, ``, `"2024-01-02T03:04:05.5+01:00"`},
+		{`"2024-01-02T03:04:05+01:30"`, `$.datetime().type()`, ``, `"timestamp with time zone"`},
+		{`"12:34:56"`, `$.datetime().type()`, ``, `"time without time zone"`},
+		{`"12:34:56-05"`, `$.datetime().type()`, ``, `"time with time zone"`},
+		{`"02/01/2024"`, `$.datetime("DD/MM/YYYY")`, ``, `"2024-01-02"`},
+		{`"2024-01-02 10:30 PM"`, `$.datetime("YYYY-MM-DD HH:MI AM")`, ``, `"2024-01-02T22:30:00"`},
+		{`"12:00:00.12"`, `$.datetime("HH24:MI:SS.MS")`, ``, `"12:00:00.12"`},
+		{`["2024-01-02", "2024-01-03 00:00:00"]`,
+			`$[*] ? (@.datetime() > "2024-01-02".datetime())`, ``, `"2024-01-03 00:00:00"`},
+		{`["2024-01-02", "2024-01-03"]`,
+			`$[*] ? (@.datetime() == "12:00:00".datetime())`, ``, ``},
+	} {
+		t.Run(tc.doc+" "+tc.path, func(t *testing.T) {
+			p, err := Parse(tc.path)
+			require.NoError(t, err)
+			doc, err := json.ParseJSON(tc.doc)
+			require.NoError(t, err)
+			var vars json.JSON
+			if tc.vars != "" {
+				vars, err = json.ParseJSON(tc.vars)
+				require.NoError(t, err)
+			}
+			res, err := p.Eval(doc, vars)
+			require.NoError(t, err)
+			strs := make([]string, len(res))
+			for i, j := range res {
+				strs[i] = j.String()
+			}
+			require.Equal(t, tc.expected, strings.Join(strs, " "))
+		})
+	}
+}
+
+func TestEvalError(t *testing.T) {
+	for _, tc := range []struct {
+		doc          string
+		path         string
+		vars         string
+		code         pgcode.Code
+		err          string
+		suppressible bool
+	}{
+		{`{"a": 1}`, `strict $.b`, ``, pgcode.SQLJSONMemberNotFound,
+			`JSON object does not contain key "b"`, true},
+		{`[1]`, `strict $.a`, ``, pgcode.SQLJSONObjectNotFound,
+			`jsonpath member accessor can only be applied to an object`, true},
+		{`{"a": 1}`, `strict $[0]`, ``, pgcode.SQLJSONArrayNotFound,
+			`jsonpath array accessor can only be applied to an array`, true},
+		{`[1]`, `strict $[1]`, ``, pgcode.InvalidSQLJSONSubscript,
+			`jsonpath array subscript is out of bounds`, true},
+		{`[1]`, `$["a"]`, ``, pgcode.InvalidSQLJSONSubscript,
+			`jsonpath array subscript is not a single numeric value`, true},
+		{`{"a": 1}`, `strict $.a.size()`, ``, pgcode.SQLJSONArrayNotFound,
+			`jsonpath item method .size() can only be applied to an array`, true},
+		{`{"a": "x"}`, `$.a + 1`, ``, pgcode.SingletonSQLJSONItemRequired,
+			`left operand of jsonpath operator + is not a single numeric value`, true},
+		{`{"a": [1, 2]}`, `1 + $.a`, ``, pgcode.SingletonSQLJSONItemRequired,
+			`right operand of jsonpath operator + is not a single numeric value`, true},
+		{`{"a": 1}`, `$.a / 0`, ``, pgcode.DivisionByZero, `division by zero`, true},
+		{`"a"`, `-$`, ``, pgcode.NonNumericSQLJSONItem,
+			`operand of unary jsonpath operator - is not a numeric value`, true},
+		{`"a"`, `$.abs()`, ``, pgcode.NonNumericSQLJSONItem,
+			`jsonpath item method .abs() can only be applied to a numeric value`, true},
+		{`"a"`, `$.double()`, ``, pgcode.NonNumericSQLJSONItem,
+			`argument "a" of jsonpath item method .double() is invalid for type double precision`, true},
+		{`1`, `$.keyvalue()`, ``, pgcode.SQLJSONObjectNotFound,
+			`jsonpath item method .keyvalue() can only be applied to an object`, true},
+		{`1`, `$.datetime()`, ``, pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+			`jsonpath item method .datetime() can only be applied to a string`, true},
+		{`"abc"`, `$.datetime()`, ``, pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+			`datetime format is not recognized: "abc"`, true},
+		{`"2024-13-01"`, `$.datetime("YYYY-MM-DD")`, ``, pgcode.DatetimeFieldOverflow,
+			`date/time field value out of range: "2024-13-01"`, true},
+		{`"2024-01-01x"`, `$.datetime("YYYY-MM-DD")`, ``, pgcode.InvalidDatetimeFormat,
+			`trailing characters remain in input string after datetime format`, true},
+		{`["2024-01-02", "2024-01-02 00:00:00+00"]`,
+			`$[*] ? (@.datetime() < "2024-01-03 00:00:00+00".datetime())`, ``, pgcode.FeatureNotSupported,
+			`cannot convert value from date to timestamp with time zone without time zone usage`, false},
+		{`{"a": 1}`, `$.a == $x`, ``, pgcode.UndefinedObject,
+			`could not find jsonpath variable "x"`, false},
+		{`{"a": 1}`, `$.a == $x`, `{"y": 1}`, pgcode.UndefinedObject,
+			`could not find jsonpath variable "x"`, false},
+		{`{"a": 1}`, `$`, `[1]`, pgcode.InvalidParameterValue,
+			`"vars" argument is not an object`, false},
+	} {
+		t.Run(tc.doc+" "+tc.path, func(t *testing.T) {
+			p, err := Parse(tc.path)
+			require.NoError(t, err)
+			doc, err := json.ParseJSON(tc.doc)
+			require.NoError(t, err)
+			var vars json.JSON
+			if tc.vars != "" {
+				vars, err = json.ParseJSON(tc.vars)
+				require.NoError(t, err)
+			}
+			_, err = p.Eval(doc, vars)
+			require.Error(t, err)
+			require.Equal(t, tc.err, err.Error())
+			require.Equal(t, tc.code, pgerror.GetPGCode(err))
+			require.Equal(t, tc.suppressible, IsSuppressible(err))
+		})
+	}
+}
+
+func TestExistsAndMatch(t *testing.T) {
+	for _, tc := range []struct {
+		doc    string
+		path   string
+		exists bool
+		// match is "true", "false", "null" or "error".
+		match string
+	}{
+		{`{"a": 1}`, `$.a`, true, `error`},
+		{`{"a": 1}`, `$.b`, false, `error`},
+		{`{"a": 1}`, `$.a == 1`, true, `true`},
+		{`{"a": 1}`, `$.a == 2`, true, `false`},
+		{`{"a": "x"}`, `$.a == 1`, true, `null`},
+		{`{"a": true}`, `$.a`, true, `true`},
+		{`{"a": [true, false]}`, `$.a[*]`, true, `error`},
+		{`{"a": [1, 2]}`, `$.a ? (@ > 1)`, true, `error`},
+		{`{"a": [1, 2]}`, `$.a ? (@ > 2)`, false, `error`},
+	} {
+		t.Run(tc.doc+" "+tc.path, func(t *testing.T) {
+			p, err := Parse(tc.path)
+			require.NoError(t, err)
+			doc, err := json.ParseJSON(tc.doc)
+			require.NoError(t, err)
+			exists, err := p.Exists(doc, nil /* vars */)
+			require.NoError(t, err)
+			require.Equal(t, tc.exists, exists)
+
+			res, ok, err := p.Match(doc, nil /* vars */)
+			switch {
+			case err != nil:
+				require.Equal(t, `error`, tc.match)
+				require.Equal(t, pgcode.SingletonSQLJSONItemRequired, pgerror.GetPGCode(err))
+				require.True(t, IsSuppressible(err))
+			case !ok:
+				require.Equal(t, `null`, tc.match)
+			default:
+				require.Equal(t, tc.match, strconv.FormatBool(res))
+			}
+		})
+	}
+}
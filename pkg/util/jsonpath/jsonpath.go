@@ -0,0 +1,444 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package jsonpath implements the SQL/JSON path language, which is used to
+// query JSON documents. A path is parsed by Parse into a Path, which can be
+// evaluated against a document with Path.Eval.
+//
+// The syntax and semantics follow Postgres:
+// https://www.postgresql.org/docs/current/functions-json.html#FUNCTIONS-SQLJSON-PATH
+package jsonpath
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// Path is a parsed SQL/JSON path expression.
+type Path struct {
+	// Strict is true if the path is evaluated in strict mode, and false if it
+	// is evaluated in lax mode. In lax mode, arrays are automatically unwrapped
+	// and structural errors are suppressed.
+	Strict bool
+	// Expr is the expression of the path. It is either an expression which
+	// returns a sequence of JSON items, or a predicate.
+	Expr Node
+}
+
+// String returns the canonical string representation of the path, which
+// matches the output of Postgres.
+func (p *Path) String() string {
+	var sb strings.Builder
+	if p.Strict {
+		sb.WriteString("strict ")
+	}
+	p.Expr.format(&sb, true /* brackets */)
+	return sb.String()
+}
+
+// IsPredicate returns true if the path is a predicate check expression, such
+// as `$.a > 1`, rather than an expression which returns JSON items.
+func (p *Path) IsPredicate() bool {
+	return isPredicate(p.Expr)
+}
+
+// Node is a node of a path expression.
+type Node interface {
+	// format writes the node to sb. If brackets is true, binary and unary
+	// operations are enclosed in parentheses.
+	format(sb *strings.Builder, brackets bool)
+}
+
+// Accessor is an accessor of a Chain, which is applied to each item of the
+// sequence produced by the preceding part of the chain.
+type Accessor interface {
+	// formatAccessor writes the accessor to sb.
+	formatAccessor(sb *strings.Builder)
+}
+
+// Root is the `$` variable, which refers to the JSON document being queried.
+type Root struct{}
+
+// Current is the `@` variable, which refers to the item being tested by a
+// filter expression.
+type Current struct{}
+
+// Last is the `last` keyword, which refers to the last index of the array
+// being subscripted.
+type Last struct{}
+
+// Variable is a named variable such as `$x`, whose value is taken from the
+// vars argument of the path functions.
+type Variable struct {
+	Name string
+}
+
+// Literal is a scalar JSON literal: a string, number, boolean or null.
+type Literal struct {
+	Value json.JSON
+}
+
+// Chain is an expression followed by a sequence of accessors, such as
+// `$.a[0]`.
+type Chain struct {
+	Head      Node
+	Accessors []Accessor
+}
+
+// Operator is a unary or binary operator of a path expression.
+type Operator int
+
+// The operators of path expressions.
+const (
+	OpAnd Operator = iota
+	OpOr
+	OpEq
+	OpNe
+	OpLt
+	OpLe
+	OpGt
+	OpGe
+	OpStartsWith
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+	OpPlus
+	OpMinus
+)
+
+var operatorNames = [...]string{
+	OpAnd:        "&&",
+	OpOr:         "||",
+	OpEq:         "==",
+	OpNe:         "!=",
+	OpLt:         "<",
+	OpLe:         "<=",
+	OpGt:         ">",
+	OpGe:         ">=",
+	OpStartsWith: "starts with",
+	OpAdd:        "+",
+	OpSub:        "-",
+	OpMul:        "*",
+	OpDiv:        "/",
+	OpMod:        "%",
+	OpPlus:       "+",
+	OpMinus:      "-",
+}
+
+func (o Operator) String() string {
+	return operatorNames[o]
+}
+
+// priority returns the binding priority of the operator, which is used to
+// decide where parentheses are needed when formatting a path.
+func (o Operator) priority() int {
+	switch o {
+	case OpOr:
+		return 0
+	case OpAnd:
+		return 1
+	case OpEq, OpNe, OpLt, OpLe, OpGt, OpGe, OpStartsWith:
+		return 2
+	case OpAdd, OpSub:
+		return 3
+	case OpMul, OpDiv, OpMod:
+		return 4
+	case OpPlus, OpMinus:
+		return 5
+	}
+	panic(errors.AssertionFailedf("unknown operator %d", o))
+}
+
+// Binary is a binary operation. The logical and comparison operators are
+// predicates, and the arithmetic operators are expressions.
+type Binary struct {
+	Op          Operator
+	Left, Right Node
+}
+
+// Unary is a unary plus or minus operation.
+type Unary struct {
+	Op  Operator
+	Arg Node
+}
+
+// Not is the `!` predicate.
+type Not struct {
+	Arg Node
+}
+
+// IsUnknown is the `is unknown` predicate, which is true if its argument
+// predicate is unknown.
+type IsUnknown struct {
+	Arg Node
+}
+
+// Exists is the `exists` predicate, which is true if its argument returns at
+// least one item.
+type Exists struct {
+	Arg Node
+}
+
+// LikeRegex is the `like_regex` predicate, which matches strings against a
+// regular expression.
+type LikeRegex struct {
+	Arg     Node
+	Pattern string
+	Flags   string
+}
+
+// Key is the `.key` accessor, which returns the value of a key of an object.
+type Key struct {
+	Name string
+}
+
+// AnyKey is the `.*` accessor, which returns the values of an object.
+type AnyKey struct{}
+
+// AnyArray is the `[*]` accessor, which returns the elements of an array.
+type AnyArray struct{}
+
+// Subscript is an array subscript, which is either a single index or, if To
+// is not nil, a range of indexes.
+type Subscript struct {
+	From, To Node
+}
+
+// IndexArray is the `[subscript, ...]` accessor, which returns the elements
+// of an array at the given indexes.
+type IndexArray struct {
+	Subscripts []Subscript
+}
+
+// anyLevelLast is used for the levels of an AnyPath to represent `last`.
+const anyLevelLast = -1
+
+// AnyPath is the `.**` accessor, which returns the item and all of its
+// descendants within the given nesting levels. A level of -1 means `last`,
+// the deepest level.
+type AnyPath struct {
+	First, Last int
+}
+
+// Filter is the `?(predicate)` accessor, which returns the items for which
+// the predicate is true.
+type Filter struct {
+	Pred Node
+}
+
+// Method is an item method such as `.size()`.
+type Method struct {
+	Name string
+	// Arg is the optional template argument of the datetime method.
+	Arg *string
+}
+
+// The item methods supported by Method.
+var methodNames = map[string]bool{
+	"type":     true,
+	"size":     true,
+	"double":   true,
+	"ceiling":  true,
+	"floor":    true,
+	"abs":      true,
+	"keyvalue": true,
+	"datetime": true,
+}
+
+func (Root) format(sb *strings.Builder, _ bool)    { sb.WriteByte('$') }
+func (Current) format(sb *strings.Builder, _ bool) { sb.WriteByte('@') }
+func (Last) format(sb *strings.Builder, _ bool)    { sb.WriteString("last") }
+
+func (v *Variable) format(sb *strings.Builder, _ bool) {
+	sb.WriteByte('$')
+	writeQuoted(sb, v.Name)
+}
+
+func (l *Literal) format(sb *strings.Builder, _ bool) {
+	if d, ok := l.Value.AsDecimal(); ok {
+		sb.WriteString(d.Text('f'))
+		return
+	}
+	sb.WriteString(l.Value.String())
+}
+
+func (c *Chain) format(sb *strings.Builder, brackets bool) {
+	if priority(c.Head) < maxPriority {
+		sb.WriteByte('(')
+		c.Head.format(sb, false /* brackets */)
+		sb.WriteByte(')')
+	} else {
+		c.Head.format(sb, brackets)
+	}
+	for _, a := range c.Accessors {
+		a.formatAccessor(sb)
+	}
+}
+
+func (b *Binary) format(sb *strings.Builder, brackets bool) {
+	if brackets {
+		sb.WriteByte('(')
+	}
+	p := b.Op.priority()
+	b.Left.format(sb, priority(b.Left) <= p)
+	sb.WriteByte(' ')
+	sb.WriteString(b.Op.String())
+	sb.WriteByte(' ')
+	b.Right.format(sb, priority(b.Right) <= p)
+	if brackets {
+		sb.WriteByte(')')
+	}
+}
+
+func (u *Unary) format(sb *strings.Builder, brackets bool) {
+	if brackets {
+		sb.WriteByte('(')
+	}
+	sb.WriteString(u.Op.String())
+	u.Arg.format(sb, priority(u.Arg) <= u.Op.priority())
+	if brackets {
+		sb.WriteByte(')')
+	}
+}
+
+func (n *Not) format(sb *strings.Builder, _ bool) {
+	sb.WriteString("!(")
+	n.Arg.format(sb, false /* brackets */)
+	sb.WriteByte(')')
+}
+
+func (n *IsUnknown) format(sb *strings.Builder, _ bool) {
+	sb.WriteByte('(')
+	n.Arg.format(sb, false /* brackets */)
+	sb.WriteString(") is unknown")
+}
+
+func (n *Exists) format(sb *strings.Builder, _ bool) {
+	sb.WriteString("exists (")
+	n.Arg.format(sb, false /* brackets */)
+	sb.WriteByte(')')
+}
+
+func (n *LikeRegex) format(sb *strings.Builder, brackets bool) {
+	if brackets {
+		sb.WriteByte('(')
+	}
+	n.Arg.format(sb, priority(n.Arg) <= OpEq.priority())
+	sb.WriteString(" like_regex ")
+	writeQuoted(sb, n.Pattern)
+	if n.Flags != "" {
+		sb.WriteString(" flag ")
+		writeQuoted(sb, n.Flags)
+	}
+	if brackets {
+		sb.WriteByte(')')
+	}
+}
+
+func (k *Key) formatAccessor(sb *strings.Builder) {
+	sb.WriteByte('.')
+	writeQuoted(sb, k.Name)
+}
+
+func (AnyKey) formatAccessor(sb *strings.Builder)   { sb.WriteString(".*") }
+func (AnyArray) formatAccessor(sb *strings.Builder) { sb.WriteString("[*]") }
+
+func (a *IndexArray) formatAccessor(sb *strings.Builder) {
+	sb.WriteByte('[')
+	for i, s := range a.Subscripts {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		s.From.format(sb, false /* brackets */)
+		if s.To != nil {
+			sb.WriteString(" to ")
+			s.To.format(sb, false /* brackets */)
+		}
+	}
+	sb.WriteByte(']')
+}
+
+func (a *AnyPath) formatAccessor(sb *strings.Builder) {
+	level := func(l int) string {
+		if l == anyLevelLast {
+			return "last"
+		}
+		return fmt.Sprint(l)
+	}
+	sb.WriteString(".**")
+	switch {
+	case a.First == 0 && a.Last == anyLevelLast:
+	case a.First == a.Last:
+		fmt.Fprintf(sb, "{%s}", level(a.First))
+	default:
+		fmt.Fprintf(sb, "{%s to %s}", level(a.First), level(a.Last))
+	}
+}
+
+func (f *Filter) formatAccessor(sb *strings.Builder) {
+	sb.WriteString("?(")
+	f.Pred.format(sb, false /* brackets */)
+	sb.WriteByte(')')
+}
+
+func (m *Method) formatAccessor(sb *strings.Builder) {
+	sb.WriteByte('.')
+	sb.WriteString(m.Name)
+	sb.WriteByte('(')
+	if m.Arg != nil {
+		writeQuoted(sb, *m.Arg)
+	}
+	sb.WriteByte(')')
+}
+
+// writeQuoted writes s to sb as a double-quoted JSON string.
+func writeQuoted(sb *strings.Builder, s string) {
+	sb.WriteString(json.FromString(s).String())
+}
+
+// maxPriority is the priority of nodes which are not operations, and never
+// need to be enclosed in parentheses.
+const maxPriority = 6
+
+// priority returns the binding priority of a node.
+func priority(n Node) int {
+	switch t := n.(type) {
+	case *Binary:
+		return t.Op.priority()
+	case *Unary:
+		return t.Op.priority()
+	case *LikeRegex:
+		return OpEq.priority()
+	}
+	return maxPriority
+}
+
+// isPredicate returns true if the node is a predicate, which evaluates to
+// true, false or unknown rather than to a sequence of items.
+func isPredicate(n Node) bool {
+	switch t := n.(type) {
+	case *Binary:
+		return t.Op.priority() <= OpStartsWith.priority()
+	case *Not, *IsUnknown, *Exists, *LikeRegex:
+		return true
+	}
+	return false
+}
+
+// makeNumber returns a numeric literal with the given value.
+func makeNumber(d *apd.Decimal) *Literal {
+	return &Literal{Value: json.FromDecimal(*d)}
+}
@@ -0,0 +1,876 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// tokIdent is an unquoted word. Keywords such as `strict` and `exists`
+	// are lexed as identifiers, and are recognized by the parser depending on
+	// the context, so that they can also be used as keys.
+	tokIdent
+	tokString
+	tokNumber
+	// tokVariable is a `$name` or `$"name"` variable. The bare `$` is lexed as
+	// tokPunct.
+	tokVariable
+	// tokPunct is an operator or punctuation.
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	// str is the text of identifiers, numbers and punctuation, and the
+	// unescaped value of strings and variables.
+	str string
+	// pos is the byte offset of the token in the input.
+	pos int
+}
+
+// punctuation lists the multi-character operators before their prefixes, so
+// that the longest operator is lexed.
+var punctuation = []string{
+	"**", "==", "!=", "<>", "<=", ">=", "&&", "||",
+	"$", "@", ".", ",", "[", "]", "(", ")", "{", "}", "?", "*", "+", "-", "/", "%",
+	"!", "<", ">",
+}
+
+// lexer splits a path expression into tokens.
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) {
+		r, n := utf8.DecodeRuneInString(l.input[l.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += n
+	}
+	start := l.pos
+	if l.pos == len(l.input) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	c := l.input[l.pos]
+	switch {
+	case c == '"':
+		s, err := l.lexString()
+		return token{kind: tokString, str: s, pos: start}, err
+	case c == '$' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '"':
+		l.pos++
+		s, err := l.lexString()
+		return token{kind: tokVariable, str: s, pos: start}, err
+	case c == '$' && l.pos+1 < len(l.input) && isIdentChar(l.input[l.pos+1]):
+		l.pos++
+		return token{kind: tokVariable, str: l.lexIdent(), pos: start}, nil
+	case c >= '0' && c <= '9':
+		return l.lexNumber()
+	case isIdentStart(c):
+		return token{kind: tokIdent, str: l.lexIdent(), pos: start}, nil
+	}
+	for _, p := range punctuation {
+		if strings.HasPrefix(l.input[l.pos:], p) {
+			l.pos += len(p)
+			return token{kind: tokPunct, str: p, pos: start}, nil
+		}
+	}
+	return token{}, l.syntaxError(start)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= utf8.RuneSelf
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func (l *lexer) lexIdent() string {
+	start := l.pos
+	for l.pos < len(l.input) && isIdentChar(l.input[l.pos]) {
+		l.pos++
+	}
+	return l.input[start:l.pos]
+}
+
+// lexNumber lexes a numeric literal, which is an integer or decimal number
+// with an optional exponent.
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	digits := func() {
+		for l.pos < len(l.input) && l.input[l.pos] >= '0' && l.input[l.pos] <= '9' {
+			l.pos++
+		}
+	}
+	digits()
+	if l.pos-start > 1 && l.input[start] == '0' {
+		// Like Postgres, integers with leading zeros are not allowed.
+		return token{}, pgerror.Newf(pgcode.Syntax,
+			"trailing junk after numeric literal at or near %q of jsonpath input",
+			l.input[start:start+2])
+	}
+	if l.pos+1 < len(l.input) && l.input[l.pos] == '.' &&
+		l.input[l.pos+1] >= '0' && l.input[l.pos+1] <= '9' {
+		l.pos++
+		digits()
+	}
+	if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
+		l.pos++
+		if l.pos < len(l.input) && (l.input[l.pos] == '+' || l.input[l.pos] == '-') {
+			l.pos++
+		}
+		expStart := l.pos
+		digits()
+		if l.pos == expStart {
+			return token{}, l.syntaxError(start)
+		}
+	}
+	if l.pos < len(l.input) && isIdentStart(l.input[l.pos]) {
+		return token{}, pgerror.Newf(pgcode.Syntax,
+			"trailing junk after numeric literal at or near %q of jsonpath input",
+			l.input[start:l.pos+1])
+	}
+	return token{kind: tokNumber, str: l.input[start:l.pos], pos: start}, nil
+}
+
+// lexString lexes a double-quoted string, which may contain the escape
+// sequences of JSON strings as well as \xNN.
+func (l *lexer) lexString() (string, error) {
+	start := l.pos
+	l.pos++
+	var sb strings.Builder
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return sb.String(), nil
+		case '\\':
+			if l.pos+1 >= len(l.input) {
+				return "", l.unterminatedString()
+			}
+			l.pos += 2
+			switch e := l.input[l.pos-1]; e {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'v':
+				sb.WriteByte('\v')
+			case 'x', 'u':
+				n := 2
+				if e == 'u' {
+					n = 4
+				}
+				if l.pos+n > len(l.input) {
+					return "", l.invalidEscape(start)
+				}
+				v, err := strconv.ParseUint(l.input[l.pos:l.pos+n], 16, 32)
+				if err != nil {
+					return "", l.invalidEscape(start)
+				}
+				l.pos += n
+				sb.WriteRune(rune(v))
+			default:
+				// Any other escaped character, including quotes and backslashes,
+				// stands for itself.
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+			l.pos++
+		}
+	}
+	return "", l.unterminatedString()
+}
+
+func (l *lexer) syntaxError(pos int) error {
+	if pos >= len(l.input) {
+		return pgerror.New(pgcode.Syntax, "syntax error at end of jsonpath input")
+	}
+	_, n := utf8.DecodeRuneInString(l.input[pos:])
+	return pgerror.Newf(pgcode.Syntax,
+		"syntax error at or near %q of jsonpath input", l.input[pos:pos+n])
+}
+
+func (l *lexer) unterminatedString() error {
+	return pgerror.New(pgcode.Syntax, "unterminated quoted string in jsonpath input")
+}
+
+func (l *lexer) invalidEscape(pos int) error {
+	return pgerror.Newf(pgcode.Syntax,
+		"invalid escape sequence at or near %q of jsonpath input", l.input[pos:])
+}
+
+// parser is a recursive descent parser for path expressions. The grammar and
+// the operator precedence follow Postgres. From the lowest to the highest
+// precedence, the operators are:
+//
+//	||
+//	&&
+//	!
+//	== != <> < <= > >= starts with, like_regex
+//	+ - (binary)
+//	* / %
+//	+ - (unary)
+//	accessors
+type parser struct {
+	lexer
+	tok token
+	// inFilter is the nesting depth of filter expressions, within which `@`
+	// can be used.
+	inFilter int
+	// inSubscript is the nesting depth of array subscripts, within which
+	// `last` can be used.
+	inSubscript int
+}
+
+// Parse parses a SQL/JSON path expression.
+func Parse(s string) (*Path, error) {
+	p := parser{lexer: lexer{input: s}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	path := &Path{}
+	if p.tok.kind == tokIdent {
+		switch strings.ToLower(p.tok.str) {
+		case "strict":
+			path.Strict = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case "lax":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.syntaxError(p.tok.pos)
+	}
+	path.Expr = expr
+	return path, nil
+}
+
+func (p *parser) advance() error {
+	var err error
+	p.tok, err = p.next()
+	return err
+}
+
+// isPunct returns true if the current token is the given punctuation.
+func (p *parser) isPunct(s string) bool {
+	return p.tok.kind == tokPunct && p.tok.str == s
+}
+
+// isKeyword returns true if the current token is the given keyword.
+func (p *parser) isKeyword(s string) bool {
+	return p.tok.kind == tokIdent && strings.EqualFold(p.tok.str, s)
+}
+
+// expectPunct consumes the given punctuation, or returns a syntax error.
+func (p *parser) expectPunct(s string) error {
+	if !p.isPunct(s) {
+		return p.syntaxError(p.tok.pos)
+	}
+	return p.advance()
+}
+
+// expectKeyword consumes the given keyword, or returns a syntax error.
+func (p *parser) expectKeyword(s string) error {
+	if !p.isKeyword(s) {
+		return p.syntaxError(p.tok.pos)
+	}
+	return p.advance()
+}
+
+// expectString consumes a string literal and returns its value, or returns a
+// syntax error.
+func (p *parser) expectString() (string, error) {
+	if p.tok.kind != tokString {
+		return "", p.syntaxError(p.tok.pos)
+	}
+	s := p.tok.str
+	return s, p.advance()
+}
+
+// checkPredicate returns a syntax error at pos if the predicate-ness of n
+// differs from the given one.
+func (p *parser) checkPredicate(n Node, pred bool, pos int) error {
+	if isPredicate(n) != pred {
+		return p.syntaxError(pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	pos := p.tok.pos
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("||") {
+		rightPos := p.tok.pos
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkPredicate(left, true, pos); err != nil {
+			return nil, err
+		}
+		if err := p.checkPredicate(right, true, rightPos); err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: OpOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	pos := p.tok.pos
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("&&") {
+		rightPos := p.tok.pos
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkPredicate(left, true, pos); err != nil {
+			return nil, err
+		}
+		if err := p.checkPredicate(right, true, rightPos); err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: OpAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseNot parses the `!` predicate, whose argument must be a parenthesized
+// predicate or an exists predicate.
+func (p *parser) parseNot() (Node, error) {
+	if !p.isPunct("!") {
+		return p.parseComparison()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	pos := p.tok.pos
+	if !p.isPunct("(") && !p.isKeyword("exists") {
+		return nil, p.syntaxError(pos)
+	}
+	arg, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPredicate(arg, true, pos); err != nil {
+		return nil, err
+	}
+	return &Not{Arg: arg}, nil
+}
+
+var comparisonOps = map[string]Operator{
+	"==": OpEq,
+	"!=": OpNe,
+	"<>": OpNe,
+	"<":  OpLt,
+	"<=": OpLe,
+	">":  OpGt,
+	">=": OpGe,
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	pos := p.tok.pos
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, isComparison := comparisonOps[p.tok.str]
+	switch {
+	case p.tok.kind == tokPunct && isComparison:
+		if err := p.checkPredicate(left, false, pos); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		rightPos := p.tok.pos
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkPredicate(right, false, rightPos); err != nil {
+			return nil, err
+		}
+		return &Binary{Op: op, Left: left, Right: right}, nil
+
+	case p.isKeyword("starts"):
+		if err := p.checkPredicate(left, false, pos); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("with"); err != nil {
+			return nil, err
+		}
+		var right Node
+		switch p.tok.kind {
+		case tokString:
+			right = &Literal{Value: json.FromString(p.tok.str)}
+		case tokVariable:
+			right = &Variable{Name: p.tok.str}
+		default:
+			return nil, p.syntaxError(p.tok.pos)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &Binary{Op: OpStartsWith, Left: left, Right: right}, nil
+
+	case p.isKeyword("like_regex"):
+		if err := p.checkPredicate(left, false, pos); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n := &LikeRegex{Arg: left}
+		if n.Pattern, err = p.expectString(); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("flag") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if n.Flags, err = p.expectString(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := compileRegex(n.Pattern, n.Flags); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (Node, error) {
+	pos := p.tok.pos
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := OpAdd
+		if p.tok.str == "-" {
+			op = OpSub
+		}
+		if left, err = p.parseArithmeticRight(op, left, pos, p.parseMultiplicative); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Node, error) {
+	pos := p.tok.pos
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") || p.isPunct("%") {
+		op := map[string]Operator{"*": OpMul, "/": OpDiv, "%": OpMod}[p.tok.str]
+		if left, err = p.parseArithmeticRight(op, left, pos, p.parseUnary); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseArithmeticRight parses the right operand of a binary arithmetic
+// operator with the given parse function, and returns the operation.
+func (p *parser) parseArithmeticRight(
+	op Operator, left Node, leftPos int, parseFn func() (Node, error),
+) (Node, error) {
+	if err := p.checkPredicate(left, false, leftPos); err != nil {
+		return nil, err
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	rightPos := p.tok.pos
+	right, err := parseFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPredicate(right, false, rightPos); err != nil {
+		return nil, err
+	}
+	return &Binary{Op: op, Left: left, Right: right}, nil
+}
+
+// parseUnary parses the unary plus and minus operators. They are folded into
+// numeric literals.
+func (p *parser) parseUnary() (Node, error) {
+	if !p.isPunct("+") && !p.isPunct("-") {
+		return p.parseAccessorExpr()
+	}
+	op := OpPlus
+	if p.tok.str == "-" {
+		op = OpMinus
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	pos := p.tok.pos
+	arg, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPredicate(arg, false, pos); err != nil {
+		return nil, err
+	}
+	if l, ok := arg.(*Literal); ok {
+		if d, ok := l.Value.AsDecimal(); ok {
+			if op == OpMinus {
+				var neg apd.Decimal
+				neg.Neg(d)
+				return makeNumber(&neg), nil
+			}
+			return l, nil
+		}
+	}
+	return &Unary{Op: op, Arg: arg}, nil
+}
+
+// parseAccessorExpr parses a primary expression followed by accessors.
+func (p *parser) parseAccessorExpr() (Node, error) {
+	head, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	var accessors []Accessor
+	for {
+		var a Accessor
+		switch {
+		case p.isPunct("."):
+			a, err = p.parseDotAccessor()
+		case p.isPunct("["):
+			a, err = p.parseArrayAccessor()
+		case p.isPunct("?"):
+			a, err = p.parseFilter()
+		default:
+			if len(accessors) == 0 {
+				return head, nil
+			}
+			return &Chain{Head: head, Accessors: accessors}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		accessors = append(accessors, a)
+	}
+}
+
+// parsePrimary parses a variable, a literal, an exists predicate, or a
+// parenthesized expression or predicate.
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.tok
+	var n Node
+	switch tok.kind {
+	case tokString:
+		n = &Literal{Value: json.FromString(tok.str)}
+	case tokNumber:
+		var d apd.Decimal
+		if _, _, err := d.SetString(tok.str); err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric literal %q", tok.str)
+		}
+		n = makeNumber(&d)
+	case tokVariable:
+		n = &Variable{Name: tok.str}
+	case tokIdent:
+		switch strings.ToLower(tok.str) {
+		case "true":
+			n = &Literal{Value: json.TrueJSONValue}
+		case "false":
+			n = &Literal{Value: json.FalseJSONValue}
+		case "null":
+			n = &Literal{Value: json.NullJSONValue}
+		case "last":
+			if p.inSubscript == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "LAST is allowed only in array subscripts")
+			}
+			n = Last{}
+		case "exists":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("("); err != nil {
+				return nil, err
+			}
+			pos := p.tok.pos
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.checkPredicate(arg, false, pos); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			return &Exists{Arg: arg}, nil
+		default:
+			return nil, p.syntaxError(tok.pos)
+		}
+	case tokPunct:
+		switch tok.str {
+		case "$":
+			n = Root{}
+		case "@":
+			if p.inFilter == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "@ is not allowed in root expressions")
+			}
+			n = Current{}
+		case "(":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			if isPredicate(inner) && p.isKeyword("is") {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				if err := p.expectKeyword("unknown"); err != nil {
+					return nil, err
+				}
+				return &IsUnknown{Arg: inner}, nil
+			}
+			return inner, nil
+		default:
+			return nil, p.syntaxError(tok.pos)
+		}
+	default:
+		return nil, p.syntaxError(tok.pos)
+	}
+	return n, p.advance()
+}
+
+// parseDotAccessor parses an accessor starting with `.`: a key, a wildcard,
+// a recursive wildcard or an item method.
+func (p *parser) parseDotAccessor() (Accessor, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	tok := p.tok
+	switch {
+	case p.isPunct("*"):
+		return AnyKey{}, p.advance()
+	case p.isPunct("**"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return p.parseAnyPathLevels()
+	case tok.kind == tokString:
+		return &Key{Name: tok.str}, p.advance()
+	case tok.kind == tokIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !p.isPunct("(") {
+			return &Key{Name: tok.str}, nil
+		}
+		if !methodNames[tok.str] {
+			return nil, p.syntaxError(tok.pos)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		m := &Method{Name: tok.str}
+		if tok.str == "datetime" && p.tok.kind == tokString {
+			arg := p.tok.str
+			m.Arg = &arg
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		return m, p.expectPunct(")")
+	}
+	return nil, p.syntaxError(tok.pos)
+}
+
+// parseAnyPathLevels parses the optional levels of a `.**` accessor, which
+// are either `{level}` or `{level to level}`.
+func (p *parser) parseAnyPathLevels() (Accessor, error) {
+	a := &AnyPath{First: 0, Last: anyLevelLast}
+	if !p.isPunct("{") {
+		return a, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	parseLevel := func() (int, error) {
+		if p.isKeyword("last") {
+			return anyLevelLast, p.advance()
+		}
+		if p.tok.kind != tokNumber {
+			return 0, p.syntaxError(p.tok.pos)
+		}
+		l, err := strconv.ParseUint(p.tok.str, 10, 31)
+		if err != nil {
+			return 0, p.syntaxError(p.tok.pos)
+		}
+		return int(l), p.advance()
+	}
+	var err error
+	if a.First, err = parseLevel(); err != nil {
+		return nil, err
+	}
+	a.Last = a.First
+	if p.isKeyword("to") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if a.Last, err = parseLevel(); err != nil {
+			return nil, err
+		}
+	}
+	return a, p.expectPunct("}")
+}
+
+// parseArrayAccessor parses a `[*]` accessor or a list of array subscripts.
+func (p *parser) parseArrayAccessor() (Accessor, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.isPunct("*") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return AnyArray{}, p.expectPunct("]")
+	}
+	p.inSubscript++
+	defer func() { p.inSubscript-- }()
+	a := &IndexArray{}
+	for {
+		var s Subscript
+		var err error
+		if s.From, err = p.parseSubscriptExpr(); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("to") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if s.To, err = p.parseSubscriptExpr(); err != nil {
+				return nil, err
+			}
+		}
+		a.Subscripts = append(a.Subscripts, s)
+		if !p.isPunct(",") {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return a, p.expectPunct("]")
+}
+
+func (p *parser) parseSubscriptExpr() (Node, error) {
+	pos := p.tok.pos
+	n, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return n, p.checkPredicate(n, false, pos)
+}
+
+// parseFilter parses a `?(predicate)` filter.
+func (p *parser) parseFilter() (Accessor, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	p.inFilter++
+	defer func() { p.inFilter-- }()
+	pos := p.tok.pos
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPredicate(pred, true, pos); err != nil {
+		return nil, err
+	}
+	return &Filter{Pred: pred}, p.expectPunct(")")
+}
+
+// validateRegexFlags checks the flags of a like_regex predicate.
+func validateRegexFlags(flags string) error {
+	for _, f := range flags {
+		switch f {
+		case 'i', 's', 'm', 'q':
+		case 'x':
+			return pgerror.New(pgcode.FeatureNotSupported,
+				`XQuery "x" flag (expanded regular expressions) is not implemented`)
+		default:
+			return errors.WithDetailf(
+				pgerror.New(pgcode.Syntax, "invalid input syntax for type jsonpath"),
+				"Unrecognized flag character %q in LIKE_REGEX predicate.", f,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`$`, `$`},
+		{`  $  `, `$`},
+		{`lax $`, `$`},
+		{`strict $`, `strict $`},
+		{`STRICT $`, `strict $`},
+		{`$.a`, `$."a"`},
+		{`$."a b"`, `$."a b"`},
+		{`$.a.b.c`, `$."a"."b"."c"`},
+		{`$.strict`, `$."strict"`},
+		{`$."a\"b"`, `$."a\"b"`},
+		{`$."A\x42"`, `$."AB"`},
+		{`$.*`, `$.*`},
+		{`$[*]`, `$[*]`},
+		{`$.a[0]`, `$."a"[0]`},
+		{`$.a[1, 2 to 3]`, `$."a"[1,2 to 3]`},
+		{`$[last]`, `$[last]`},
+		{`$[last - 1 to last]`, `$[last - 1 to last]`},
+		{`$[$.a + 1]`, `$[$."a" + 1]`},
+		{`$.**`, `$.**`},
+		{`$.**{2}`, `$.**{2}`},
+		{`$.**{1 to last}`, `$.**{1 to last}`},
+		{`$.**{last}.a`, `$.**{last}."a"`},
+		{`$x`, `$"x"`},
+		{`$"x y"`, `$"x y"`},
+		{`1`, `1`},
+		{`-1`, `-1`},
+		{`1.50`, `1.50`},
+		{`0.5`, `0.5`},
+		{`1e3`, `1000`},
+		{`"abc"`, `"abc"`},
+		{`true`, `true`},
+		{`null`, `null`},
+		{`$.a + 1`, `($."a" + 1)`},
+		{`$.a + 1 * 2`, `($."a" + 1 * 2)`},
+		{`($.a + 1) * 2`, `(($."a" + 1) * 2)`},
+		{`1 - (2 - 3)`, `(1 - (2 - 3))`},
+		{`1 - 2 - 3`, `((1 - 2) - 3)`},
+		{`-$.a`, `(-$."a")`},
+		{`-(-$.a)`, `(-(-$."a"))`},
+		{`+$.a`, `(+$."a")`},
+		{`$.a % 2 / 3`, `(($."a" % 2) / 3)`},
+		{`($.a).b`, `$."a"."b"`},
+		{`($.a + 1).type()`, `($."a" + 1).type()`},
+		{`$.a == 1`, `($."a" == 1)`},
+		{`$.a <> 1`, `($."a" != 1)`},
+		{`$.a == 1 && $.b != 2`, `($."a" == 1 && $."b" != 2)`},
+		{`$.a == 1 || $.b == 2 && $.c == 3`, `($."a" == 1 || $."b" == 2 && $."c" == 3)`},
+		{`($.a == 1 || $.b == 2) && $.c == 3`, `(($."a" == 1 || $."b" == 2) && $."c" == 3)`},
+		{`!($.a == 1)`, `!($."a" == 1)`},
+		{`exists($.a)`, `exists ($."a")`},
+		{`($.a == 1) is unknown`, `($."a" == 1) is unknown`},
+		{`$.a starts with "b"`, `($."a" starts with "b")`},
+		{`$.a starts with $x`, `($."a" starts with $"x")`},
+		{`$.a like_regex "^a.*b$"`, `($."a" like_regex "^a.*b$")`},
+		{`$.a like_regex "^ab" flag "i"`, `($."a" like_regex "^ab" flag "i")`},
+		{`$.a ? (@ > 1)`, `$."a"?(@ > 1)`},
+		{`$.a ? (@.b == "x").c`, `$."a"?(@."b" == "x")."c"`},
+		{`$ ? (@.a ? (@ > 1) == 2)`, `$?(@."a"?(@ > 1) == 2)`},
+		{`$ ? (exists (@.a) && !(@.b < 1))`, `$?(exists (@."a") && !(@."b" < 1))`},
+		{`$.a.type()`, `$."a".type()`},
+		{`$.a.size().double()`, `$."a".size().double()`},
+		{`$.a.ceiling().floor().abs()`, `$."a".ceiling().floor().abs()`},
+		{`$.keyvalue()`, `$.keyvalue()`},
+		{`$.datetime()`, `$.datetime()`},
+		{`$.datetime("HH24:MI")`, `$.datetime("HH24:MI")`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			p, err := Parse(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, p.String())
+
+			// The formatted path must parse to the same path.
+			p2, err := Parse(p.String())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, p2.String())
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{``, `syntax error at end of jsonpath input`},
+		{`$.`, `syntax error at end of jsonpath input`},
+		{`$.a[`, `syntax error at end of jsonpath input`},
+		{`$ $`, `syntax error at or near "$" of jsonpath input`},
+		{`$.a +`, `syntax error at end of jsonpath input`},
+		{`$."a`, `unterminated quoted string in jsonpath input`},
+		{`1a`, `trailing junk after numeric literal`},
+		{`01`, `trailing junk after numeric literal`},
+		{`@`, `@ is not allowed in root expressions`},
+		{`$.a + @`, `@ is not allowed in root expressions`},
+		{`last`, `LAST is allowed only in array subscripts`},
+		{`$ ? (@ == last)`, `LAST is allowed only in array subscripts`},
+		{`$ ? (@.a)`, `syntax error`},
+		{`$.a == 1 + 1 == 2`, `syntax error`},
+		{`!$.a`, `syntax error`},
+		{`$.a starts with 1`, `syntax error`},
+		{`$.a like_regex $x`, `syntax error`},
+		{`$.a like_regex "a" flag "z"`, `invalid input syntax for type jsonpath`},
+		{`$.a like_regex "a" flag "x"`, `XQuery "x" flag (expanded regular expressions) is not implemented`},
+		{`$.a like_regex "(" `, `invalid regular expression`},
+		{`$.foo()`, `syntax error`},
+		{`$.size(1)`, `syntax error`},
+		{`$.**{last to 1}x`, `syntax error`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}